@@ -27,6 +27,17 @@ ALLOWED_DOMAINS = [
 PASSTHROUGH_DOMAINS = []
 
 
+def _env_domains(name):
+    """Parse a comma-separated domain list from the environment."""
+    return [d.strip() for d in os.environ.get(name, "").split(",") if d.strip()]
+
+
+# Private registries configured in devagent (network.registries) are expanded
+# to host + CDN by devagent and passed in via the proxy container environment.
+ALLOWED_DOMAINS.extend(_env_domains("DEVAGENT_EXTRA_ALLOWED_DOMAINS"))
+PASSTHROUGH_DOMAINS.extend(_env_domains("DEVAGENT_EXTRA_PASSTHROUGH_DOMAINS"))
+
+
 def _parse_domain_entry(entry):
     """Normalize domain entry to dict format with log attribute.
 
@@ -47,6 +47,10 @@
           then builtins.replaceStrings ["\n"] [""] (builtins.readFile versionFile)
           else "0.1.0-dev";
 
+        # Git revision for `devagent version`. No build date is injected: nix
+        # builds are reproducible, so the binary reports it as unknown.
+        commit = self.rev or self.dirtyRev or "unknown";
+
         frontend = pkgs.buildNpmPackage {
           pname = "devagent-frontend";
           inherit version;
@@ -98,6 +102,7 @@
                   "-s"
                   "-w"
                   "-X main.version=${version}"
+                  "-X main.commit=${commit}"
                 ];
               };
               tsnsrvPkg = inputs.tsnsrv.packages.${pkgs.system}.tsnsrv;
@@ -122,6 +127,7 @@
                 "-s"
                 "-w"
                 "-X main.version=${version}"
+                "-X main.commit=${commit}"
               ];
               postInstall = ''
                 mv $out/bin/devagent $out/bin/devagent.exe
@@ -0,0 +1,135 @@
+// pattern: Imperative Shell
+
+// Package audit records lifecycle actions (who, what, when, and the result)
+// in an append-only JSON Lines file, separate from the debug log.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FileName is the audit log file, kept in the data dir.
+const FileName = "audit.jsonl"
+
+// Sources of an action.
+const (
+	SourceTUI = "tui"
+	SourceWeb = "web"
+	SourceCLI = "cli"
+)
+
+// SourceHeader is the HTTP request header the CLI sets (to SourceCLI) so the
+// web API can attribute its requests; requests without it count as SourceWeb.
+const SourceHeader = "X-Devagent-Source"
+
+// Results of an action.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// DefaultLimit is how many entries Recent returns for a limit of zero or less.
+const DefaultLimit = 100
+
+// Entry is one recorded action.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // e.g. "container.destroy", "session.create"
+	Target string    `json:"target"` // container ID or name, "<container>/<session>", "<project>/<worktree>"
+	Source string    `json:"source"` // SourceTUI, SourceWeb or SourceCLI
+	Result string    `json:"result"` // ResultOK or ResultError
+	Error  string    `json:"error,omitempty"`
+}
+
+// Log appends entries to the audit file. Safe for concurrent use; a nil *Log
+// records nothing.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open returns a Log writing to path. The file (and its directory) is
+// created on the first Record.
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Record appends an entry for action on target from source; err (nil for
+// success) sets the result. The audit log must never fail the action it
+// records, so write errors are dropped.
+func (l *Log) Record(action, target, source string, err error) {
+	if l == nil {
+		return
+	}
+	entry := Entry{
+		Time:   time.Now().UTC(),
+		Action: action,
+		Target: target,
+		Source: source,
+		Result: ResultOK,
+	}
+	if err != nil {
+		entry.Result = ResultError
+		entry.Error = err.Error()
+	}
+	line, mErr := json.Marshal(entry)
+	if mErr != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return
+	}
+	f, oErr := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if oErr != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.Write(append(line, '\n'))
+}
+
+// Recent returns up to limit of the newest entries, newest first
+// (DefaultLimit when limit is zero or less). A missing file has no entries;
+// malformed lines are skipped.
+func (l *Log) Recent(limit int) ([]Entry, error) {
+	if l == nil {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	l.mu.Lock()
+	data, err := os.ReadFile(l.path)
+	l.mu.Unlock()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	slices.Reverse(entries)
+	return entries, nil
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", FileName)
+	l := Open(path)
+
+	l.Record("container.stop", "abc123", SourceTUI, nil)
+	l.Record("container.destroy", "abc123", SourceWeb, errors.New("compose down failed"))
+	l.Record("session.create", "abc123/dev", SourceCLI, nil)
+
+	entries, err := l.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Recent(2) returned %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Action != "session.create" || e.Source != SourceCLI || e.Result != ResultOK || e.Error != "" {
+		t.Errorf("newest entry = %+v, want ok session.create from cli", e)
+	}
+	if e := entries[1]; e.Action != "container.destroy" || e.Result != ResultError || e.Error != "compose down failed" {
+		t.Errorf("second entry = %+v, want failed container.destroy", e)
+	}
+	if entries[0].Time.IsZero() {
+		t.Error("entry time not set")
+	}
+
+	// Appending never rewrites earlier entries; a malformed line is skipped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile error = %v", err)
+	}
+	_, _ = f.WriteString("not json\n")
+	_ = f.Close()
+	l.Record("container.start", "abc123", SourceTUI, nil)
+
+	all, err := l.Recent(0)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(all) != 4 || all[3].Action != "container.stop" || all[0].Action != "container.start" {
+		t.Errorf("Recent(0) = %+v, want 4 entries newest first", all)
+	}
+}
+
+func TestNilAndMissing(t *testing.T) {
+	var l *Log
+	l.Record("container.stop", "abc123", SourceTUI, nil)
+	if entries, err := l.Recent(10); entries != nil || err != nil {
+		t.Errorf("nil Log Recent() = %v, %v", entries, err)
+	}
+
+	entries, err := Open(filepath.Join(t.TempDir(), FileName)).Recent(10)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("missing file Recent() = %v, %v, want no entries", entries, err)
+	}
+}
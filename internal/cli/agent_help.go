@@ -123,7 +123,7 @@ func (a *App) printCommandReference(w io.Writer) {
 
 	// Ungrouped commands in defined order
 	fmt.Fprintln(w, "Top-level commands:")
-	for _, name := range []string{"list", "cleanup", "version"} {
+	for _, name := range []string{"list", "prune", "cleanup", "doctor", "version"} {
 		if cmd, ok := a.commands[name]; ok {
 			fmt.Fprintf(w, "  %-12s %s\n", cmd.Name, cmd.Summary)
 			fmt.Fprintf(w, "               %s\n", cmd.Usage)
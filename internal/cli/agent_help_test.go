@@ -8,7 +8,7 @@ import (
 )
 
 func TestApp_PrintAgentHelp_NonEmpty(t *testing.T) {
-	app := BuildApp("test", "")
+	app := BuildApp(BuildInfo{Version: "test"}, "", "")
 	buf := &bytes.Buffer{}
 	app.PrintAgentHelp(buf)
 
@@ -18,7 +18,7 @@ func TestApp_PrintAgentHelp_NonEmpty(t *testing.T) {
 }
 
 func TestApp_PrintAgentHelp_ContainsSectionHeaders(t *testing.T) {
-	app := BuildApp("test", "")
+	app := BuildApp(BuildInfo{Version: "test"}, "", "")
 	buf := &bytes.Buffer{}
 	app.PrintAgentHelp(buf)
 	output := buf.String()
@@ -42,14 +42,16 @@ func TestApp_PrintAgentHelp_ContainsSectionHeaders(t *testing.T) {
 }
 
 func TestApp_PrintAgentHelp_ContainsRealCommandNames(t *testing.T) {
-	app := BuildApp("test", "")
+	app := BuildApp(BuildInfo{Version: "test"}, "", "")
 	buf := &bytes.Buffer{}
 	app.PrintAgentHelp(buf)
 	output := buf.String()
 
 	commands := []string{
 		"list",
+		"prune",
 		"cleanup",
+		"doctor",
 		"version",
 		"worktree create",
 		"container start",
@@ -70,7 +72,7 @@ func TestApp_PrintAgentHelp_ContainsRealCommandNames(t *testing.T) {
 }
 
 func TestApp_PrintAgentHelp_ContainsRealUsageStrings(t *testing.T) {
-	app := BuildApp("test", "")
+	app := BuildApp(BuildInfo{Version: "test"}, "", "")
 	buf := &bytes.Buffer{}
 	app.PrintAgentHelp(buf)
 	output := buf.String()
@@ -92,7 +94,7 @@ func TestApp_PrintAgentHelp_ContainsRealUsageStrings(t *testing.T) {
 }
 
 func TestApp_PrintAgentHelp_ContainsExitCodes(t *testing.T) {
-	app := BuildApp("test", "")
+	app := BuildApp(BuildInfo{Version: "test"}, "", "")
 	buf := &bytes.Buffer{}
 	app.PrintAgentHelp(buf)
 	output := buf.String()
@@ -120,7 +120,7 @@ func (a *App) PrintHelp(w io.Writer) {
 	fmt.Fprintf(w, "Commands:\n")
 
 	// Print ungrouped commands
-	for _, name := range []string{"list", "cleanup", "version"} {
+	for _, name := range []string{"list", "prune", "cleanup", "doctor", "version"} {
 		if cmd, ok := a.commands[name]; ok {
 			fmt.Fprintf(w, "  %-10s %s\n", cmd.Name, cmd.Summary)
 		}
@@ -0,0 +1,106 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"syscall"
+
+	flag "github.com/spf13/pflag"
+
+	"devagent/internal/container"
+	"devagent/internal/instance"
+)
+
+// parseAttachArgs parses `attach <id-or-name> [session] [--create]`. Flags
+// may come before, between or after the positional arguments.
+func parseAttachArgs(args []string) (id, session string, create bool, err error) {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	fs.SetInterspersed(true)
+	createFlag := fs.Bool("create", false, "create the session if it does not exist")
+	if err := fs.Parse(args); err != nil || fs.NArg() < 1 || fs.NArg() > 2 {
+		return "", "", false, fmt.Errorf("usage: devagent attach <id-or-name> [session] [--create]")
+	}
+	return fs.Arg(0), fs.Arg(1), *createFlag, nil
+}
+
+// runAttachCommand replaces this process with a tmux attach inside a running
+// container. session picks the session; when empty the first attached
+// session is used, else the first one. A missing session is an error unless
+// create is set, in which case it is created first (session, or
+// defaultOpenSession when empty).
+func runAttachCommand(configDir, configFile, id, session string, create bool) error {
+	cfg, err := LoadConfig(configDir, configFile)
+	if err != nil {
+		return err
+	}
+
+	delegate := Delegate{ConfigDir: configDir}
+	delegate.Run(func(client *instance.Client) error {
+		data, err := client.GetContainer(id)
+		if err != nil {
+			return err
+		}
+		var c openContainer
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("invalid container response: %w", err)
+		}
+		if c.State != string(container.StateRunning) {
+			return fmt.Errorf("container %s is %s; start it with `devagent container start %s`", c.Name, c.State, c.Name)
+		}
+
+		name, ok := pickAttachSession(c.Sessions, session)
+		if !ok {
+			if !create {
+				if session != "" {
+					return fmt.Errorf("no session %q in %s; rerun with --create to create it", session, c.Name)
+				}
+				return fmt.Errorf("%s has no sessions; rerun with --create to create one", c.Name)
+			}
+			if name = session; name == "" {
+				name = defaultOpenSession
+			}
+			if _, err := client.CreateSession(c.ID, name); err != nil {
+				return err
+			}
+		}
+
+		user := c.RemoteUser
+		if user == "" {
+			user = container.DefaultRemoteUser
+		}
+		runtimePath := cfg.DetectedRuntimePath()
+		return syscall.Exec(runtimePath, buildAttachArgs(runtimePath, user, c.Name, name), append(os.Environ(), cfg.RuntimeHostEnv()...))
+	})
+	return nil
+}
+
+// pickAttachSession returns the session to attach to: requested if the
+// container has it, otherwise (requested empty) the first attached session,
+// else the first session. ok is false when there is no such session.
+func pickAttachSession(sessions []openSession, requested string) (string, bool) {
+	if requested != "" {
+		for _, s := range sessions {
+			if s.Name == requested {
+				return requested, true
+			}
+		}
+		return "", false
+	}
+	for _, s := range sessions {
+		if s.Attached {
+			return s.Name, true
+		}
+	}
+	if len(sessions) > 0 {
+		return sessions[0].Name, true
+	}
+	return "", false
+}
+
+// buildAttachArgs returns the argv (argv[0] included) that attaches to a tmux
+// session in a container, matching config.DefaultAttachCommandTemplate.
+func buildAttachArgs(runtimePath, user, containerName, session string) []string {
+	return []string{runtimePath, "exec", "-it", "-u", user, containerName, "tmux", "attach", "-t", session}
+}
@@ -0,0 +1,71 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildAttachArgs(t *testing.T) {
+	got := buildAttachArgs("/usr/bin/docker", "vscode", "myproj", "main")
+	want := []string{"/usr/bin/docker", "exec", "-it", "-u", "vscode", "myproj", "tmux", "attach", "-t", "main"}
+	if !slices.Equal(got, want) {
+		t.Errorf("buildAttachArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestPickAttachSession(t *testing.T) {
+	sessions := []openSession{{Name: "main"}, {Name: "build", Attached: true}}
+
+	tests := []struct {
+		name      string
+		sessions  []openSession
+		requested string
+		want      string
+		wantOK    bool
+	}{
+		{"requested exists", sessions, "main", "main", true},
+		{"requested missing", sessions, "other", "", false},
+		{"prefers attached", sessions, "", "build", true},
+		{"falls back to first", []openSession{{Name: "a"}, {Name: "b"}}, "", "a", true},
+		{"no sessions", nil, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := pickAttachSession(tt.sessions, tt.requested)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("pickAttachSession() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseAttachArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantID      string
+		wantSession string
+		wantCreate  bool
+		wantErr     bool
+	}{
+		{name: "flag after name", args: []string{"mybox", "--create"}, wantID: "mybox", wantCreate: true},
+		{name: "flag after session", args: []string{"mybox", "main", "--create"}, wantID: "mybox", wantSession: "main", wantCreate: true},
+		{name: "flag first", args: []string{"--create", "mybox", "main"}, wantID: "mybox", wantSession: "main", wantCreate: true},
+		{name: "no flag", args: []string{"mybox"}, wantID: "mybox"},
+		{name: "missing name", args: []string{"--create"}, wantErr: true},
+		{name: "too many arguments", args: []string{"mybox", "main", "extra"}, wantErr: true},
+		{name: "unknown flag", args: []string{"mybox", "--force"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, session, create, err := parseAttachArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAttachArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || session != tt.wantSession || create != tt.wantCreate {
+				t.Errorf("parseAttachArgs() = (%q, %q, %v), want (%q, %q, %v)", id, session, create, tt.wantID, tt.wantSession, tt.wantCreate)
+			}
+		})
+	}
+}
@@ -2,10 +2,18 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	flag "github.com/spf13/pflag"
+
+	"devagent/internal/config"
 	"devagent/internal/instance"
 )
 
@@ -22,39 +30,152 @@ func ResolveDataDir(configDir string) string {
 	return filepath.Join(home, ".config", "devagent")
 }
 
+// LoadConfig loads the config from configFile when given (it takes
+// precedence over configDir's config.yaml), else from configDir or the default
+// profile. Templates are always read from configDir's templates directory when
+// configDir is set. main and the commands that read the config (doctor, open,
+// attach) share it, so --config means the same file everywhere.
+func LoadConfig(configDir, configFile string) (config.Config, error) {
+	if configFile != "" {
+		if configDir != "" {
+			config.SetTemplatesPath(filepath.Join(configDir, "templates"))
+		}
+		return config.LoadFromFile(configFile)
+	}
+	if configDir != "" {
+		return config.LoadFromDir(configDir)
+	}
+	return config.Load()
+}
+
 // BuildApp creates and configures the CLI application with all commands and groups.
-func BuildApp(version string, configDir string) *App {
-	app := NewApp(version)
+// configFile is the --config file (empty for configDir's config.yaml); see LoadConfig.
+func BuildApp(info BuildInfo, configDir, configFile string) *App {
+	app := NewApp(info.Version)
 
 	// Register ungrouped commands
 	app.AddCommand(&Command{
 		Name:    "list",
 		Summary: "Output JSON data about all managed containers",
-		Usage:   "Usage: devagent list",
+		Usage:   "Usage: devagent list [--all]",
 		Run: func(args []string) error {
-			return runListCommand(configDir)
+			fs := flag.NewFlagSet("list", flag.ContinueOnError)
+			all := fs.Bool("all", false, "include containers not managed by devagent (marked \"unmanaged\")")
+			if err := fs.Parse(args); err != nil {
+				fmt.Fprintf(os.Stderr, "Usage: devagent list [--all]\n")
+				os.Exit(1)
+			}
+			return runListCommand(configDir, *all)
 		},
 	})
 
 	app.AddCommand(&Command{
 		Name:    "cleanup",
 		Summary: "Remove stale lock/port files from a crashed instance",
-		Usage:   "Usage: devagent cleanup",
+		Usage:   "Usage: devagent cleanup [--dry-run]",
 		Run: func(args []string) error {
-			return runCleanupCommand(configDir)
+			fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+			dryRun := fs.Bool("dry-run", false, "report files that would be removed without removing them")
+			if err := fs.Parse(args); err != nil {
+				fmt.Fprintf(os.Stderr, "Usage: devagent cleanup [--dry-run]\n")
+				os.Exit(1)
+			}
+			return runCleanupCommand(configDir, *dryRun)
 		},
 	})
 
 	app.AddCommand(&Command{
-		Name:    "version",
-		Summary: "Print version and exit",
-		Usage:   "Usage: devagent version",
+		Name:    "prune",
+		Summary: "Destroy all stopped managed containers and orphaned sidecars",
+		Usage:   "Usage: devagent prune [--confirm]",
 		Run: func(args []string) error {
-			fmt.Println(version)
+			fs := flag.NewFlagSet("prune", flag.ContinueOnError)
+			confirm := fs.Bool("confirm", false, "allow destroying more containers than confirm.destroy_threshold")
+			if err := fs.Parse(args); err != nil {
+				fmt.Fprintf(os.Stderr, "Usage: devagent prune [--confirm]\n")
+				os.Exit(1)
+			}
+			delegate := Delegate{ConfigDir: configDir, ClientTimeout: 2 * time.Minute}
+			delegate.Run(func(client *instance.Client) error {
+				data, err := client.Prune(*confirm)
+				if err != nil {
+					if strings.Contains(err.Error(), "status 412") {
+						return fmt.Errorf("%w (rerun with --confirm)", err)
+					}
+					return err
+				}
+				return printPruneResult(data, os.Stdout)
+			})
 			return nil
 		},
 	})
 
+	app.AddCommand(&Command{
+		Name:    "open",
+		Summary: "Open a running container in VS Code or attach to a session",
+		Usage:   "Usage: devagent open <id-or-name>",
+		Run: func(args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: devagent open <id-or-name>")
+			}
+			return runOpenCommand(configDir, configFile, args[0])
+		},
+	})
+
+	app.AddCommand(&Command{
+		Name:    "attach",
+		Summary: "Attach this terminal to a tmux session in a running container",
+		Usage:   "Usage: devagent attach <id-or-name> [session] [--create]",
+		Run: func(args []string) error {
+			id, session, create, err := parseAttachArgs(args)
+			if err != nil {
+				return err
+			}
+			return runAttachCommand(configDir, configFile, id, session, create)
+		},
+	})
+
+	app.AddCommand(&Command{
+		Name:    "restart",
+		Summary: "Restart the running instance to pick up config changes",
+		Usage:   "Usage: devagent restart",
+		Run: func(args []string) error {
+			delegate := Delegate{ConfigDir: configDir}
+			delegate.Run(func(client *instance.Client) error {
+				if _, err := client.Restart(); err != nil {
+					return err
+				}
+				fmt.Println("Restarting devagent.")
+				return nil
+			})
+			return nil
+		},
+	})
+
+	app.AddCommand(&Command{
+		Name:    "doctor",
+		Summary: "Check runtime, compose, and config prerequisites",
+		Usage:   "Usage: devagent doctor",
+		Run: func(args []string) error {
+			return runDoctorCommand(configDir, configFile)
+		},
+	})
+
+	app.AddCommand(&Command{
+		Name:    "version",
+		Summary: "Print version and build metadata and exit",
+		Usage:   "Usage: devagent version [--json]",
+		Run: func(args []string) error {
+			fs := flag.NewFlagSet("version", flag.ContinueOnError)
+			asJSON := fs.Bool("json", false, "print {version, commit, date, go_version, runtime} as JSON")
+			if err := fs.Parse(args); err != nil {
+				fmt.Fprintf(os.Stderr, "Usage: devagent version [--json]\n")
+				os.Exit(1)
+			}
+			return writeVersion(os.Stdout, info, *asJSON)
+		},
+	})
+
 	// Register command groups
 	worktreeGroup := app.AddGroup("worktree", "Manage git worktrees")
 	RegisterWorktreeCommands(worktreeGroup, configDir)
@@ -70,14 +191,14 @@ func BuildApp(version string, configDir string) *App {
 
 // runListCommand delegates to the running devagent instance via HTTP.
 // Requires a running TUI instance — outputs the same project hierarchy
-// available at GET /api/projects.
-func runListCommand(configDir string) error {
-	return runListCommandWithDiscovery(configDir, instance.Discover)
+// available at GET /api/projects. With all, unmanaged containers are included.
+func runListCommand(configDir string, all bool) error {
+	return runListCommandWithDiscovery(configDir, all, instance.Discover)
 }
 
 // runListCommandWithDiscovery is the internal implementation that accepts
 // a discoverer function for testing purposes.
-func runListCommandWithDiscovery(configDir string, discoverer func(string) (string, error)) error {
+func runListCommandWithDiscovery(configDir string, all bool, discoverer func(string) (string, error)) error {
 	dataDir := ResolveDataDir(configDir)
 	baseURL, err := discoverer(dataDir)
 	if err != nil {
@@ -86,7 +207,11 @@ func runListCommandWithDiscovery(configDir string, discoverer func(string) (stri
 	}
 
 	client := instance.NewClient(baseURL)
-	data, err := client.List()
+	list := client.List
+	if all {
+		list = client.ListAll
+	}
+	data, err := list()
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -96,18 +221,64 @@ func runListCommandWithDiscovery(configDir string, discoverer func(string) (stri
 	return nil
 }
 
+// printPruneResult prints the IDs removed by a prune (POST /api/prune response).
+func printPruneResult(data []byte, w io.Writer) error {
+	var resp struct {
+		Removed []string `json:"removed"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("invalid prune response: %w", err)
+	}
+	if len(resp.Removed) == 0 {
+		fmt.Fprintln(w, "No stopped containers to prune.")
+		return nil
+	}
+	for _, id := range resp.Removed {
+		fmt.Fprintf(w, "Removed: %s\n", id)
+	}
+	fmt.Fprintf(w, "Pruned %d container(s).\n", len(resp.Removed))
+	return nil
+}
+
 // runCleanupCommand removes stale lock and port files from a crashed instance.
-func runCleanupCommand(configDir string) error {
-	dataDir := ResolveDataDir(configDir)
+// With dryRun, it only reports what would be removed.
+func runCleanupCommand(configDir string, dryRun bool) error {
+	if err := runCleanup(ResolveDataDir(configDir), dryRun, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return nil
+}
 
+// runCleanup is the testable implementation of the cleanup command.
+// Both modes verify that no instance is running by acquiring the lock.
+func runCleanup(dataDir string, dryRun bool, w io.Writer) error {
 	// Try to acquire the lock to verify no instance is actually running
 	fl, err := instance.Lock(dataDir)
+	if errors.Is(err, instance.ErrStaleLock) {
+		return fmt.Errorf("the devagent lock is held by an instance that is not responding; stop that process or start devagent with --force")
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: a devagent instance appears to be running. Stop it first.\n")
-		os.Exit(1)
+		return fmt.Errorf("a devagent instance appears to be running. Stop it first")
+	}
+
+	if dryRun {
+		// We got the lock — no instance is running. Report, then release
+		// without touching any files.
+		defer instance.Release(fl)
+		files := instance.StaleFiles(dataDir)
+		if len(files) == 0 {
+			fmt.Fprintln(w, "No stale files to remove.")
+			return nil
+		}
+		for _, path := range files {
+			fmt.Fprintf(w, "Would remove: %s\n", path)
+		}
+		return nil
 	}
+
 	// We got the lock — no instance is running. Clean up and release.
 	instance.Cleanup(dataDir, fl)
-	fmt.Println("Cleaned up stale lock and port files.")
+	fmt.Fprintln(w, "Cleaned up stale lock and port files.")
 	return nil
 }
@@ -6,11 +6,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"devagent/internal/instance"
 )
 
 func TestBuildApp_VersionCommand_PrintsVersion(t *testing.T) {
-	app := BuildApp("1.2.3", "")
+	app := BuildApp(BuildInfo{Version: "1.2.3", Commit: "abc1234", Date: "2026-01-02T03:04:05Z", GoVersion: "go1.24.0", Runtime: "linux/amd64"}, "", "")
 
 	// Find the version command
 	versionCmd, ok := app.commands["version"]
@@ -37,13 +41,14 @@ func TestBuildApp_VersionCommand_PrintsVersion(t *testing.T) {
 	}
 
 	output := buf.String()
-	if output != "1.2.3\n" {
-		t.Errorf("version command output = %q, want \"1.2.3\\n\"", output)
+	want := "1.2.3 (commit abc1234, built 2026-01-02T03:04:05Z, go1.24.0 linux/amd64)\n"
+	if output != want {
+		t.Errorf("version command output = %q, want %q", output, want)
 	}
 }
 
 func TestBuildApp_NoArgs_ReturnsTrueForTUI(t *testing.T) {
-	app := BuildApp("1.0.0", "")
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, "", "")
 	result := app.Execute(nil)
 	if !result {
 		t.Errorf("Execute(nil) returned %v, want true", result)
@@ -52,7 +57,7 @@ func TestBuildApp_NoArgs_ReturnsTrueForTUI(t *testing.T) {
 
 func TestBuildApp_ContainerStart_RequiresArg(t *testing.T) {
 	// Build app
-	app := BuildApp("1.0.0", "")
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, "", "")
 
 	// The container group and start command should be registered
 	// Just verify the basic structure to ensure no panics
@@ -92,7 +97,7 @@ func TestBuildApp_ListCommand_DelegatesToInstance(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runListCommandWithDiscovery("", mockDiscoverer)
+	err := runListCommandWithDiscovery("", false, mockDiscoverer)
 
 	w.Close()
 	buf := &bytes.Buffer{}
@@ -109,10 +114,47 @@ func TestBuildApp_ListCommand_DelegatesToInstance(t *testing.T) {
 	}
 }
 
+func TestListCommand_AllIncludesUnmanaged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("all") == "true" {
+			w.Write([]byte(`{"projects":[],"unmatched":[{"id":"other1","unmanaged":true}]}`))
+			return
+		}
+		w.Write([]byte(`{"projects":[],"unmatched":[]}`))
+	}))
+	defer server.Close()
+
+	discoverer := func(string) (string, error) { return server.URL, nil }
+
+	for _, tt := range []struct {
+		all  bool
+		want bool
+	}{{false, false}, {true, true}} {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := runListCommandWithDiscovery("", tt.all, discoverer)
+
+		w.Close()
+		buf := &bytes.Buffer{}
+		buf.ReadFrom(r)
+		os.Stdout = oldStdout
+
+		if err != nil {
+			t.Fatalf("list (all=%v) returned error: %v", tt.all, err)
+		}
+		if got := strings.Contains(buf.String(), `"unmanaged":true`); got != tt.want {
+			t.Errorf("list (all=%v) output = %s, want unmanaged container: %v", tt.all, buf.String(), tt.want)
+		}
+	}
+}
+
 func TestBuildApp_CleanupCommand_Registered(t *testing.T) {
 	// Create a temporary directory for the config
 	tmpDir := t.TempDir()
-	app := BuildApp("1.0.0", tmpDir)
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, tmpDir, "")
 
 	// Find the cleanup command
 	cleanupCmd, ok := app.commands["cleanup"]
@@ -163,3 +205,105 @@ func TestBuildApp_CleanupCommand_Registered(t *testing.T) {
 		t.Errorf("expected cleanup message in output, got: %s", output)
 	}
 }
+
+func TestRunCleanup_DryRunReportsFilesWithoutRemoving(t *testing.T) {
+	tmpDir := t.TempDir()
+	portPath := filepath.Join(tmpDir, "devagent.port")
+	if err := os.WriteFile(portPath, []byte("127.0.0.1:1234"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := runCleanup(tmpDir, true, buf); err != nil {
+		t.Fatalf("runCleanup(dry-run) returned error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Would remove: "+portPath) {
+		t.Errorf("expected dry-run to report %s, got: %s", portPath, buf.String())
+	}
+	if _, err := os.Stat(portPath); err != nil {
+		t.Errorf("dry-run must leave port file in place: %v", err)
+	}
+
+	// The lock must have been released so a real cleanup can run afterwards
+	buf.Reset()
+	if err := runCleanup(tmpDir, false, buf); err != nil {
+		t.Fatalf("runCleanup after dry-run returned error: %v", err)
+	}
+	if _, err := os.Stat(portPath); !os.IsNotExist(err) {
+		t.Error("real cleanup should remove the port file")
+	}
+}
+
+func TestRunCleanup_DryRunNothingToRemove(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := runCleanup(t.TempDir(), true, buf); err != nil {
+		t.Fatalf("runCleanup(dry-run) returned error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No stale files") {
+		t.Errorf("expected 'No stale files' message, got: %s", buf.String())
+	}
+}
+
+func TestRunCleanup_DryRunFailsWhenInstanceRunning(t *testing.T) {
+	tmpDir := t.TempDir()
+	fl, err := instance.Lock(tmpDir)
+	if err != nil {
+		t.Fatalf("Lock() failed: %v", err)
+	}
+	defer instance.Cleanup(tmpDir, fl)
+
+	if err := runCleanup(tmpDir, true, &bytes.Buffer{}); err == nil {
+		t.Error("dry-run should fail while an instance holds the lock")
+	}
+}
+
+func TestPrintPruneResult(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printPruneResult([]byte(`{"removed":["abc","def"]}`), &buf); err != nil {
+		t.Fatalf("printPruneResult() error = %v", err)
+	}
+	want := "Removed: abc\nRemoved: def\nPruned 2 container(s).\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := printPruneResult([]byte(`{"removed":[]}`), &buf); err != nil {
+		t.Fatalf("printPruneResult() error = %v", err)
+	}
+	if buf.String() != "No stopped containers to prune.\n" {
+		t.Errorf("output = %q, want nothing-to-prune message", buf.String())
+	}
+}
+
+func TestLoadConfig_ConfigFileWins(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("theme: latte\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profile := filepath.Join(t.TempDir(), "staging.yaml")
+	if err := os.WriteFile(profile, []byte("theme: frappe\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(dir, profile)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Theme != "frappe" {
+		t.Errorf("Theme = %q, want frappe from --config", cfg.Theme)
+	}
+
+	cfg, err = LoadConfig(dir, "")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Theme != "latte" {
+		t.Errorf("Theme = %q, want latte from the config dir", cfg.Theme)
+	}
+
+	if _, err := LoadConfig(dir, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("LoadConfig() should fail for a missing --config file")
+	}
+}
@@ -251,7 +251,7 @@ func TestContainerStart_MissingArg_ReturnsError(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create a command via BuildApp and get the container start command
-	app := BuildApp("1.0.0", tmpDir)
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, tmpDir, "")
 	containerGroup := app.groups["container"]
 	if containerGroup == nil {
 		t.Fatal("container group not found")
@@ -284,7 +284,7 @@ func TestContainerStop_MissingArg_ReturnsError(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create a command via BuildApp and get the container stop command
-	app := BuildApp("1.0.0", tmpDir)
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, tmpDir, "")
 	containerGroup := app.groups["container"]
 	if containerGroup == nil {
 		t.Fatal("container group not found")
@@ -317,7 +317,7 @@ func TestContainerDestroy_MissingArg_ReturnsError(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create a command via BuildApp and get the container destroy command
-	app := BuildApp("1.0.0", tmpDir)
+	app := BuildApp(BuildInfo{Version: "1.0.0"}, tmpDir, "")
 	containerGroup := app.groups["container"]
 	if containerGroup == nil {
 		t.Fatal("container group not found")
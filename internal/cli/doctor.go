@@ -0,0 +1,209 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"devagent/internal/config"
+	"devagent/internal/container"
+)
+
+// CheckResult is the outcome of a single doctor check.
+type CheckResult struct {
+	Name     string
+	OK       bool
+	Critical bool   // a failed critical check makes doctor exit non-zero
+	Detail   string // what was found (or what went wrong)
+	Hint     string // remediation shown when the check fails
+}
+
+// commandRunner runs an external command and returns its stdout.
+type commandRunner func(name string, args ...string) ([]byte, error)
+
+func execRunner(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// runDoctorCommand runs all checks, prints the checklist, and exits 1 if any
+// critical check failed.
+func runDoctorCommand(configDir, configFile string) error {
+	if !runDoctor(configDir, configFile, os.Stdout) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runDoctor is the testable implementation of the doctor command.
+// Returns false if any critical check failed.
+func runDoctor(configDir, configFile string, w io.Writer) bool {
+	var results []CheckResult
+
+	cfg, err := LoadConfig(configDir, configFile)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:     "Config",
+			Critical: true,
+			Detail:   err.Error(),
+			Hint:     "fix the YAML in config.yaml (remaining checks use defaults)",
+		})
+	} else {
+		results = append(results, CheckResult{Name: "Config", OK: true, Detail: "loaded"})
+	}
+
+	runtime := checkRuntime(&cfg, exec.LookPath)
+	results = append(results, runtime)
+	if runtime.OK {
+		results = append(results, checkCompose(&cfg, execRunner))
+	}
+	results = append(results,
+		checkTailscale(&cfg),
+		checkScanPaths(&cfg),
+		checkDataDir(ResolveDataDir(configDir)),
+	)
+
+	return printChecklist(w, results)
+}
+
+// printChecklist writes one ✓/✗ line per check, with the hint indented under
+// failures. Returns false if any critical check failed.
+func printChecklist(w io.Writer, results []CheckResult) bool {
+	ok := true
+	for _, r := range results {
+		mark := "✓"
+		if !r.OK {
+			mark = "✗"
+			if r.Critical {
+				ok = false
+			}
+		}
+		line := fmt.Sprintf("%s %s", mark, r.Name)
+		if r.Detail != "" {
+			line += ": " + r.Detail
+		}
+		if !r.OK && !r.Critical {
+			line += " (warning)"
+		}
+		fmt.Fprintln(w, line)
+		if !r.OK && r.Hint != "" {
+			fmt.Fprintf(w, "    → %s\n", r.Hint)
+		}
+	}
+	return ok
+}
+
+// checkRuntime verifies the configured (or auto-detected) container runtime
+// resolves to an executable. On success, Detail is the resolved path.
+func checkRuntime(cfg *config.Config, lookPath config.LookPathFunc) CheckResult {
+	res := CheckResult{Name: "Container runtime", Critical: true}
+	if err := cfg.ValidateRuntimeWith(lookPath); err != nil {
+		res.Detail = err.Error()
+		res.Hint = "set runtime to docker or podman in config.yaml and make sure it is on PATH"
+		return res
+	}
+	path := cfg.DetectedRuntimePathWith(lookPath)
+	if _, err := lookPath(path); err != nil {
+		res.Detail = "neither docker nor podman found in PATH"
+		res.Hint = "install Docker or Podman"
+		return res
+	}
+	res.OK = true
+	res.Detail = path
+	return res
+}
+
+// checkCompose verifies the compose invocation devagent will use works, since
+// all containers are created through compose. The invocation is resolved as
+// the container manager does (container.DetectComposeCommand: the
+// compose_command override, else the first candidate whose `version` runs).
+// Detail is the detected command and its reported version.
+func checkCompose(cfg *config.Config, run commandRunner) CheckResult {
+	res := CheckResult{Name: "Compose", Critical: true}
+	version := func(argv []string) ([]byte, error) {
+		return run(argv[0], append(slices.Clone(argv[1:]), "version")...)
+	}
+	argv := container.DetectComposeCommand(cfg.DetectedRuntime(), cfg.ComposeCommand, func(argv []string) bool {
+		_, err := version(argv)
+		return err == nil
+	})
+	command := strings.Join(argv, " ")
+	out, err := version(argv)
+	if err != nil {
+		res.Detail = fmt.Sprintf("%s version failed: %v", command, err)
+		res.Hint = "install the Docker Compose plugin (docker) or podman-compose (podman), or set compose_command"
+		return res
+	}
+	res.OK = true
+	res.Detail = command + ": " + strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	return res
+}
+
+// checkTailscale validates the tailscale section when it is enabled.
+func checkTailscale(cfg *config.Config) CheckResult {
+	res := CheckResult{Name: "Tailscale", Critical: true}
+	if !cfg.Tailscale.Enabled {
+		res.OK = true
+		res.Detail = "disabled"
+		return res
+	}
+	if err := cfg.Tailscale.Validate(cfg.ResolveTokenPath); err != nil {
+		res.Detail = err.Error()
+		res.Hint = "fix the tailscale section in config.yaml or set tailscale.enabled: false"
+		return res
+	}
+	res.OK = true
+	res.Detail = "config valid"
+	return res
+}
+
+// checkScanPaths reports scan paths that do not exist. Non-critical: discovery
+// skips missing directories.
+func checkScanPaths(cfg *config.Config) CheckResult {
+	res := CheckResult{Name: "Scan paths"}
+	paths := cfg.ResolveScanPaths()
+	if len(paths) == 0 {
+		res.OK = true
+		res.Detail = "none configured"
+		return res
+	}
+	var missing []string
+	for _, p := range paths {
+		if info, err := os.Stat(p); err != nil || !info.IsDir() {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		res.Detail = "missing " + strings.Join(missing, ", ")
+		res.Hint = "create the directories or remove them from scan_paths"
+		return res
+	}
+	res.OK = true
+	res.Detail = fmt.Sprintf("%d found", len(paths))
+	return res
+}
+
+// checkDataDir verifies the data dir (lock, port, and state files) is writable
+// by creating and removing a temp file in it.
+func checkDataDir(dataDir string) CheckResult {
+	res := CheckResult{Name: "Data directory", Critical: true}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		res.Detail = err.Error()
+		res.Hint = "check permissions on " + dataDir
+		return res
+	}
+	f, err := os.CreateTemp(dataDir, ".doctor-*")
+	if err != nil {
+		res.Detail = fmt.Sprintf("%s is not writable: %v", dataDir, err)
+		res.Hint = "check permissions on " + dataDir
+		return res
+	}
+	f.Close()
+	os.Remove(f.Name())
+	res.OK = true
+	res.Detail = dataDir
+	return res
+}
@@ -0,0 +1,188 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"devagent/internal/config"
+)
+
+func fakeLookPath(found ...string) config.LookPathFunc {
+	return func(name string) (string, error) {
+		for _, f := range found {
+			if name == f || name == "/usr/bin/"+f {
+				return "/usr/bin/" + f, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestCheckRuntime(t *testing.T) {
+	tests := []struct {
+		name    string
+		runtime string
+		found   []string
+		wantOK  bool
+		detail  string
+	}{
+		{"auto-detect docker", "", []string{"docker"}, true, "/usr/bin/docker"},
+		{"auto-detect podman", "", []string{"podman"}, true, "/usr/bin/podman"},
+		{"none installed", "", nil, false, "neither docker nor podman found in PATH"},
+		{"configured missing", "podman", []string{"docker"}, false, "runtime 'podman' not found in PATH"},
+		{"configured unknown", "lxc", []string{"lxc"}, false, "runtime must be 'docker' or 'podman', got: lxc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Runtime: tt.runtime}
+			res := checkRuntime(&cfg, fakeLookPath(tt.found...))
+			if res.OK != tt.wantOK || res.Detail != tt.detail {
+				t.Errorf("checkRuntime() = {OK:%v Detail:%q}, want {OK:%v Detail:%q}", res.OK, res.Detail, tt.wantOK, tt.detail)
+			}
+			if !res.Critical {
+				t.Error("runtime check should be critical")
+			}
+		})
+	}
+}
+
+func TestCheckCompose(t *testing.T) {
+	var gotArgs []string
+	ok := checkCompose(&config.Config{Runtime: "docker"}, func(name string, args ...string) ([]byte, error) {
+		gotArgs = append([]string{name}, args...)
+		return []byte("Docker Compose version v2.29.1\n"), nil
+	})
+	if !ok.OK || ok.Detail != "docker compose: Docker Compose version v2.29.1" {
+		t.Errorf("checkCompose() = %+v, want OK with command and version", ok)
+	}
+	if strings.Join(gotArgs, " ") != "docker compose version" {
+		t.Errorf("ran %v, want docker compose version", gotArgs)
+	}
+
+	failed := checkCompose(&config.Config{Runtime: "docker"}, func(string, ...string) ([]byte, error) {
+		return nil, errors.New("exit status 125")
+	})
+	if failed.OK || failed.Hint == "" {
+		t.Errorf("checkCompose() on failure = %+v, want not OK with hint", failed)
+	}
+}
+
+func TestCheckCompose_UsesDetectedCommand(t *testing.T) {
+	// Only podman-compose works: detection falls back to it, as the manager does
+	var ran []string
+	podman := checkCompose(&config.Config{Runtime: "podman"}, func(name string, args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(append([]string{name}, args...), " "))
+		if name != "podman-compose" {
+			return nil, errors.New("not found")
+		}
+		return []byte("podman-compose version 1.2.0\n"), nil
+	})
+	if !podman.OK || podman.Detail != "podman-compose: podman-compose version 1.2.0" {
+		t.Errorf("checkCompose(podman) = %+v, want OK via podman-compose", podman)
+	}
+
+	// The compose_command override is used as is, without probing
+	ran = nil
+	override := checkCompose(&config.Config{Runtime: "docker", ComposeCommand: "docker-compose"}, func(name string, args ...string) ([]byte, error) {
+		ran = append(ran, strings.Join(append([]string{name}, args...), " "))
+		return []byte("Docker Compose version 1.29.2\n"), nil
+	})
+	if !override.OK || !slices.Equal(ran, []string{"docker-compose version"}) {
+		t.Errorf("checkCompose(override) = %+v after running %v, want only docker-compose version", override, ran)
+	}
+}
+
+func TestCheckTailscale(t *testing.T) {
+	cfg := config.Config{}
+	if res := checkTailscale(&cfg); !res.OK || res.Detail != "disabled" {
+		t.Errorf("checkTailscale(disabled) = %+v, want OK", res)
+	}
+
+	cfg.Tailscale = config.TailscaleConfig{Enabled: true, Name: "devagent", AuthKeyPath: filepath.Join(t.TempDir(), "missing")}
+	res := checkTailscale(&cfg)
+	if res.OK || !res.Critical || !strings.Contains(res.Detail, "auth key file not found") {
+		t.Errorf("checkTailscale(missing key) = %+v, want critical failure", res)
+	}
+}
+
+func TestCheckScanPaths(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "nope")
+
+	cfg := config.Config{ScanPaths: []string{dir, missing}}
+	res := checkScanPaths(&cfg)
+	if res.OK || res.Critical || res.Detail != "missing "+missing {
+		t.Errorf("checkScanPaths() = %+v, want non-critical failure naming %s", res, missing)
+	}
+
+	cfg.ScanPaths = []string{dir}
+	if res := checkScanPaths(&cfg); !res.OK {
+		t.Errorf("checkScanPaths(existing) = %+v, want OK", res)
+	}
+}
+
+func TestCheckDataDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	if res := checkDataDir(dir); !res.OK {
+		t.Fatalf("checkDataDir() = %+v, want OK", res)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("checkDataDir() left files behind: %v", entries)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	ro := t.TempDir()
+	if err := os.Chmod(ro, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(ro, 0755) })
+	if res := checkDataDir(ro); res.OK || !res.Critical {
+		t.Errorf("checkDataDir(read-only) = %+v, want critical failure", res)
+	}
+}
+
+func TestRunDoctor_ReadsConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("theme: latte\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	custom := filepath.Join(dir, "custom.yaml")
+	if err := os.WriteFile(custom, []byte("startup_view: split\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	runDoctor(dir, custom, &buf)
+	if !strings.Contains(buf.String(), "✗ Config: startup_view") {
+		t.Errorf("doctor should check --config custom.yaml, not config.yaml, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintChecklist(t *testing.T) {
+	var buf bytes.Buffer
+	ok := printChecklist(&buf, []CheckResult{
+		{Name: "Container runtime", OK: true, Critical: true, Detail: "/usr/bin/docker"},
+		{Name: "Scan paths", Detail: "missing /x", Hint: "create it"},
+	})
+	if !ok {
+		t.Error("printChecklist() = false, want true when only non-critical checks fail")
+	}
+	want := "✓ Container runtime: /usr/bin/docker\n✗ Scan paths: missing /x (warning)\n    → create it\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if printChecklist(&buf, []CheckResult{{Name: "Compose", Critical: true, Detail: "failed"}}) {
+		t.Error("printChecklist() = true, want false when a critical check fails")
+	}
+}
@@ -0,0 +1,98 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"devagent/internal/config"
+	"devagent/internal/container"
+	"devagent/internal/instance"
+)
+
+// defaultOpenSession is the session `devagent open` creates in terminal mode
+// when the container has none.
+const defaultOpenSession = "main"
+
+// openContainer is the part of GET /api/containers/{id} that open needs.
+type openContainer struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	State       string        `json:"state"`
+	ProjectPath string        `json:"project_path"`
+	RemoteUser  string        `json:"remote_user"`
+	Sessions    []openSession `json:"sessions"`
+}
+
+// openSession is a session entry of openContainer.
+type openSession struct {
+	Name     string `json:"name"`
+	Attached bool   `json:"attached"`
+}
+
+// runOpenCommand opens a running container the way open_mode asks: VS Code
+// attached to the container, or a tmux session attached in this terminal
+// (creating the default session if the container has none).
+func runOpenCommand(configDir, configFile, id string) error {
+	cfg, err := LoadConfig(configDir, configFile)
+	if err != nil {
+		return err
+	}
+
+	delegate := Delegate{ConfigDir: configDir}
+	delegate.Run(func(client *instance.Client) error {
+		data, err := client.GetContainer(id)
+		if err != nil {
+			return err
+		}
+		var c openContainer
+		if err := json.Unmarshal(data, &c); err != nil {
+			return fmt.Errorf("invalid container response: %w", err)
+		}
+		if c.State != string(container.StateRunning) {
+			return fmt.Errorf("container %s is %s; start it with `devagent container start %s`", c.Name, c.State, c.Name)
+		}
+
+		if cfg.ResolvedOpenMode(exec.LookPath) == config.OpenModeVSCode {
+			uri := container.VSCodeURI(c.ID, container.ReadWorkspaceFolder(c.ProjectPath))
+			return runInTerminal(exec.Command("code", "--folder-uri", uri))
+		}
+
+		session := defaultOpenSession
+		if len(c.Sessions) > 0 {
+			session = c.Sessions[0].Name
+		} else if _, err := client.CreateSession(c.ID, session); err != nil {
+			return err
+		}
+		attach, err := openAttachCommand(&cfg, c, session)
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command("sh", "-c", attach)
+		cmd.Env = append(os.Environ(), cfg.RuntimeHostEnv()...)
+		return runInTerminal(cmd)
+	})
+	return nil
+}
+
+// openAttachCommand renders the configured attach command for a session.
+func openAttachCommand(cfg *config.Config, c openContainer, session string) (string, error) {
+	user := c.RemoteUser
+	if user == "" {
+		user = container.DefaultRemoteUser
+	}
+	data := config.AttachCommandData{Runtime: cfg.DetectedRuntimePath(), User: user, Name: c.Name, Session: session}
+	cmd, err := config.RenderAttachCommand(cfg.AttachCommandTemplate, data)
+	if err != nil {
+		return "", fmt.Errorf("attach_command_template: %w", err)
+	}
+	return cmd, nil
+}
+
+// runInTerminal runs cmd attached to this process's stdio.
+func runInTerminal(cmd *exec.Cmd) error {
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
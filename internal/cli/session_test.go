@@ -83,7 +83,7 @@ func TestSessionCreate_MissingArgs(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Build the app with the temp config dir
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	sessionGroup := app.groups["session"]
 	if sessionGroup == nil {
 		t.Fatal("session group not found")
@@ -437,7 +437,7 @@ func TestSessionReadlines_CustomCount(t *testing.T) {
 func TestSessionReadlines_MissingArgs(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	sessionGroup := app.groups["session"]
 	if sessionGroup == nil {
 		t.Fatal("session group not found")
@@ -460,7 +460,7 @@ func TestSessionReadlines_MissingArgs(t *testing.T) {
 func TestSessionReadlines_InvalidCount(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	readlinesCmd := app.groups["session"].Commands["readlines"]
 
 	err := readlinesCmd.Run([]string{"container-id", "session-name", "abc"})
@@ -533,7 +533,7 @@ func TestSessionSend_Success(t *testing.T) {
 func TestSessionSend_MissingArgs(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	sessionGroup := app.groups["session"]
 	if sessionGroup == nil {
 		t.Fatal("session group not found")
@@ -724,7 +724,7 @@ func TestSessionTail_NoColorFlag(t *testing.T) {
 func TestSessionTail_MissingArgs(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	sessionGroup := app.groups["session"]
 	if sessionGroup == nil {
 		t.Fatal("session group not found")
@@ -752,7 +752,7 @@ func TestSessionTail_MissingArgs(t *testing.T) {
 func TestSessionTail_InvalidInterval(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	app := BuildApp("test", tmpDir)
+	app := BuildApp(BuildInfo{Version: "test"}, tmpDir, "")
 	sessionGroup := app.groups["session"]
 	if sessionGroup == nil {
 		t.Fatal("session group not found")
@@ -0,0 +1,69 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+)
+
+// BuildInfo describes the running binary, for `devagent version` and bug
+// reports. Version, Commit and Date are injected via ldflags
+// (-X main.version=... -X main.commit=... -X main.date=...).
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Runtime   string `json:"runtime"` // GOOS/GOARCH
+}
+
+// NewBuildInfo returns the BuildInfo of the running binary. A commit or date
+// not injected via ldflags falls back to the VCS stamp `go build` embeds, then
+// to "unknown".
+func NewBuildInfo(version, commit, date string) BuildInfo {
+	if commit == "" || date == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range bi.Settings {
+				switch {
+				case s.Key == "vcs.revision" && commit == "":
+					commit = s.Value
+				case s.Key == "vcs.time" && date == "":
+					date = s.Value
+				}
+			}
+		}
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+	return BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Runtime:   runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// String formats the build info on one line, e.g.
+// "1.2.3 (commit abc1234, built 2026-01-02T03:04:05Z, go1.24.0 linux/amd64)".
+func (b BuildInfo) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s %s)", b.Version, b.Commit, b.Date, b.GoVersion, b.Runtime)
+}
+
+// writeVersion prints the build info as one line, or as indented JSON.
+func writeVersion(w io.Writer, info BuildInfo, asJSON bool) error {
+	if !asJSON {
+		_, err := fmt.Fprintln(w, info)
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(info)
+}
@@ -0,0 +1,50 @@
+// pattern: Imperative Shell
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestNewBuildInfo(t *testing.T) {
+	info := NewBuildInfo("1.2.3", "abc1234", "2026-01-02T03:04:05Z")
+	if info.Version != "1.2.3" || info.Commit != "abc1234" || info.Date != "2026-01-02T03:04:05Z" {
+		t.Errorf("ldflag values not kept: %+v", info)
+	}
+	if info.GoVersion != runtime.Version() || info.Runtime != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Errorf("go_version/runtime = %q/%q", info.GoVersion, info.Runtime)
+	}
+
+	// Test binaries carry no VCS stamp, so unset values read "unknown"
+	if info := NewBuildInfo("dev", "", ""); info.Commit != "unknown" || info.Date != "unknown" {
+		t.Errorf("commit/date = %q/%q, want unknown", info.Commit, info.Date)
+	}
+}
+
+func TestWriteVersion_JSONShape(t *testing.T) {
+	info := NewBuildInfo("1.2.3", "abc1234", "2026-01-02T03:04:05Z")
+
+	var buf bytes.Buffer
+	if err := writeVersion(&buf, info, true); err != nil {
+		t.Fatalf("writeVersion() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON object of strings: %v\n%s", err, buf.String())
+	}
+	var keys []string
+	for k := range got {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if want := []string{"commit", "date", "go_version", "runtime", "version"}; !slices.Equal(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if got["version"] != "1.2.3" || got["commit"] != "abc1234" || got["date"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("values = %v", got)
+	}
+}
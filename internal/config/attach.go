@@ -0,0 +1,38 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// DefaultAttachCommandTemplate is the attach command used when
+// attach_command_template is not set.
+const DefaultAttachCommandTemplate = "{{.Runtime}} exec -it -u {{.User}} {{.Name}} tmux attach -t {{.Session}}"
+
+// AttachCommandData is the context an attach command template is rendered with.
+type AttachCommandData struct {
+	Runtime string // container runtime binary path
+	User    string // user to exec as
+	Name    string // container name
+	Session string // tmux session name
+}
+
+// RenderAttachCommand renders an attach command template (text/template, see
+// AttachCommandData). An empty tmpl uses DefaultAttachCommandTemplate.
+func RenderAttachCommand(tmpl string, data AttachCommandData) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultAttachCommandTemplate
+	}
+	t, err := template.New("attach").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
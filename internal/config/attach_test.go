@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestRenderAttachCommand(t *testing.T) {
+	data := AttachCommandData{Runtime: "/usr/bin/docker", User: "vscode", Name: "myproj", Session: "dev"}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "default when unset",
+			want: "/usr/bin/docker exec -it -u vscode myproj tmux attach -t dev",
+		},
+		{
+			name: "custom template",
+			tmpl: "{{.Runtime}} exec -it -u {{.User}} -e TERM=xterm-256color {{.Name}} zsh -c 'tmux attach -t {{.Session}}'",
+			want: "/usr/bin/docker exec -it -u vscode -e TERM=xterm-256color myproj zsh -c 'tmux attach -t dev'",
+		},
+		{name: "unknown field", tmpl: "{{.Shell}}", wantErr: true},
+		{name: "parse error", tmpl: "{{.Name", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderAttachCommand(tt.tmpl, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderAttachCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderAttachCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,20 +8,36 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Theme           string          `yaml:"theme"`
-	Runtime         string          `yaml:"runtime"`
-	LogLevel        string          `yaml:"log_level"`
-	Web             WebConfig       `yaml:"web"`
-	Tailscale       TailscaleConfig `yaml:"tailscale"`
-	ClaudeTokenPath string          `yaml:"claude_token_path"`
-	GitHubTokenPath string          `yaml:"github_token_path"`
-	ScanPaths       []string        `yaml:"scan_paths"`
+	Theme                 string          `yaml:"theme"`
+	UnknownTheme          string          `yaml:"-"` // theme from the file that is not in Themes; Theme fell back to DefaultTheme
+	Runtime               string          `yaml:"runtime"`
+	ComposeCommand        string          `yaml:"compose_command"` // compose invocation override, e.g. "docker-compose"; detected when empty
+	RuntimeHost           string          `yaml:"runtime_host"`    // daemon address (unix, tcp or ssh URL); DOCKER_HOST/CONTAINER_HOST when empty
+	LogLevel              string          `yaml:"log_level"`
+	LogFormat             string          `yaml:"log_format"` // log file format: json (default) or text
+	Logging               LoggingConfig   `yaml:"logging"`
+	Web                   WebConfig       `yaml:"web"`
+	Tailscale             TailscaleConfig `yaml:"tailscale"`
+	ClaudeTokenPath       string          `yaml:"claude_token_path"`
+	GitHubTokenPath       string          `yaml:"github_token_path"`
+	ScanPaths             []string        `yaml:"scan_paths"`
+	ScanMaxDepth          int             `yaml:"scan_max_depth"`          // levels below each scan path to search; 0 = one
+	CloneRoot             string          `yaml:"clone_root"`              // where git URLs are cloned; defaults to the first scan path
+	StartupView           string          `yaml:"startup_view"`            // panels open at TUI start: tree (default), logs or detail
+	AttachCommandTemplate string          `yaml:"attach_command_template"` // text/template for TUI attach commands (see RenderAttachCommand)
+	OpenMode              string          `yaml:"open_mode"`               // devagent open: auto (default), vscode or terminal
+	RefreshInterval       time.Duration   `yaml:"refresh_interval"`        // TUI periodic refresh cadence, e.g. "10s"
+	Network               NetworkConfig   `yaml:"network"`
+	Confirm               ConfirmConfig   `yaml:"confirm"`
+	StopSidecarsOnExit    bool            `yaml:"stop_sidecars_on_exit"` // on quit, stop the sidecars of containers created in this run
 }
 
 type TailscaleConfig struct {
@@ -37,20 +53,68 @@ type TailscaleConfig struct {
 }
 
 type WebConfig struct {
-	Bind string `yaml:"bind"`
-	Port int    `yaml:"port"`
+	Bind         string `yaml:"bind"`
+	Port         int    `yaml:"port"`
+	Compression  bool   `yaml:"compression"`   // gzip API responses
+	FallbackPort bool   `yaml:"fallback_port"` // use an ephemeral port if port is in use
+	// AllowedOrigins are the origins allowed to call the API cross-origin
+	// ("*" for any); empty keeps the API same-origin only.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	// Socket, when set, serves the web UI on this Unix socket (mode 0600)
+	// instead of TCP; bind, port and tailscale are then unused. May start with ~/.
+	Socket string `yaml:"socket"`
+	// MaxConcurrentBuilds caps how many container builds (create, clone,
+	// worktree start) the web API runs at once; more get 429.
+	MaxConcurrentBuilds int `yaml:"max_concurrent_builds"`
+	// ReadOnly makes the web API view-only for everyone but the local host
+	// (e.g. tailnet visitors): mutations and terminal attaches get 403. The
+	// TUI and CLI are unaffected.
+	ReadOnly bool `yaml:"read_only"`
 }
 
+// Startup views for Config.StartupView.
+const (
+	StartupViewTree   = "tree"   // tree only
+	StartupViewLogs   = "logs"   // log panel open
+	StartupViewDetail = "detail" // detail panel open on the first running container
+)
+
+// DefaultMaxConcurrentBuilds is web.max_concurrent_builds when not set.
+const DefaultMaxConcurrentBuilds = 2
+
+// DefaultTheme is the Catppuccin flavor used when theme is unset or unknown.
+const DefaultTheme = "mocha"
+
+// Themes lists the supported Catppuccin flavors, lightest first. The TUI
+// cycles through them in this order.
+var Themes = []string{"latte", "frappe", "macchiato", "mocha"}
+
+// IsTheme reports whether name is one of Themes.
+func IsTheme(name string) bool {
+	return slices.Contains(Themes, name)
+}
+
+// DefaultRefreshInterval is the TUI's periodic refresh cadence when
+// refresh_interval is not set.
+const DefaultRefreshInterval = 10 * time.Second
+
 // LookPathFunc is the function signature for looking up executables.
 type LookPathFunc func(name string) (string, error)
 
 func DefaultConfig() Config {
 	return Config{
-		Theme:    "mocha",
-		LogLevel: "info",
+		Theme:           DefaultTheme,
+		LogLevel:        "info",
+		RefreshInterval: DefaultRefreshInterval,
+		Logging: LoggingConfig{
+			MaxSizeMB:  10,
+			MaxBackups: 3,
+			MaxAgeDays: 7,
+		},
 		Web: WebConfig{
-			Bind: "127.0.0.1",
-			Port: 0, // disabled by default
+			Bind:                "127.0.0.1",
+			Port:                0, // disabled by default
+			MaxConcurrentBuilds: DefaultMaxConcurrentBuilds,
 		},
 		Tailscale: TailscaleConfig{
 			Name:        "devagent",
@@ -76,6 +140,16 @@ func LoadFromDir(configDir string) (Config, error) {
 	return LoadFrom(configPath)
 }
 
+// LoadFromFile loads config from an explicit YAML file (devagent --config).
+// Unlike LoadFrom, a missing file is an error rather than the defaults. The
+// templates path is left to the caller (see SetTemplatesPath).
+func LoadFromFile(path string) (Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		return DefaultConfig(), err
+	}
+	return LoadFrom(path)
+}
+
 func LoadFrom(configPath string) (Config, error) {
 	cfg := DefaultConfig()
 
@@ -92,7 +166,61 @@ func LoadFrom(configPath string) (Config, error) {
 	}
 
 	if cfg.Theme == "" {
-		cfg.Theme = "mocha"
+		cfg.Theme = DefaultTheme
+	} else if !IsTheme(cfg.Theme) {
+		// Not fatal: fall back and let the caller warn
+		cfg.UnknownTheme = cfg.Theme
+		cfg.Theme = DefaultTheme
+	}
+
+	if err := cfg.Network.Validate(); err != nil {
+		return DefaultConfig(), err
+	}
+
+	switch cfg.LogFormat {
+	case "", "json", "text":
+	default:
+		return DefaultConfig(), fmt.Errorf("log_format %q: must be json or text", cfg.LogFormat)
+	}
+
+	if err := cfg.Logging.Validate(); err != nil {
+		return DefaultConfig(), err
+	}
+
+	if cfg.ScanMaxDepth < 0 {
+		return DefaultConfig(), fmt.Errorf("scan_max_depth %d: must not be negative", cfg.ScanMaxDepth)
+	}
+
+	if cfg.Confirm.DestroyThreshold < 0 {
+		return DefaultConfig(), fmt.Errorf("confirm.destroy_threshold %d: must not be negative", cfg.Confirm.DestroyThreshold)
+	}
+
+	if cfg.Web.MaxConcurrentBuilds < 1 {
+		return DefaultConfig(), fmt.Errorf("web.max_concurrent_builds %d: must be at least 1", cfg.Web.MaxConcurrentBuilds)
+	}
+
+	if cfg.RefreshInterval < time.Second {
+		return DefaultConfig(), fmt.Errorf("refresh_interval %s: must be at least 1s", cfg.RefreshInterval)
+	}
+
+	switch cfg.StartupView {
+	case "", StartupViewTree, StartupViewLogs, StartupViewDetail:
+	default:
+		return DefaultConfig(), fmt.Errorf("startup_view %q: must be tree, logs or detail", cfg.StartupView)
+	}
+
+	if err := validateOpenMode(cfg.OpenMode); err != nil {
+		return DefaultConfig(), err
+	}
+
+	if cfg.RuntimeHost != "" {
+		if err := ValidateRuntimeHost(cfg.RuntimeHost); err != nil {
+			return DefaultConfig(), fmt.Errorf("runtime_host %q: %w", cfg.RuntimeHost, err)
+		}
+	}
+
+	if _, err := RenderAttachCommand(cfg.AttachCommandTemplate, AttachCommandData{}); err != nil {
+		return DefaultConfig(), fmt.Errorf("attach_command_template: %w", err)
 	}
 
 	return cfg, nil
@@ -153,7 +281,9 @@ func (c *Config) DetectedRuntimePathWith(lookPath LookPathFunc) string {
 }
 
 // ValidateRuntime validates the configured runtime.
-// If Runtime is empty (auto-detect mode), validation is skipped.
+// The detected runtime host (runtime_host, DOCKER_HOST or CONTAINER_HOST), if
+// any, must be a valid address; a remote one still needs the local binary.
+// If Runtime is empty (auto-detect mode), the runtime itself is not checked.
 // Otherwise, validates the runtime is "docker" or "podman" and the binary exists.
 func (c *Config) ValidateRuntime() error {
 	return c.ValidateRuntimeWith(exec.LookPath)
@@ -161,6 +291,12 @@ func (c *Config) ValidateRuntime() error {
 
 // ValidateRuntimeWith validates the configured runtime using the provided lookup function.
 func (c *Config) ValidateRuntimeWith(lookPath LookPathFunc) error {
+	if host := c.DetectedRuntimeHost(); host != "" {
+		if err := ValidateRuntimeHost(host); err != nil {
+			return fmt.Errorf("runtime host %q: %w", host, err)
+		}
+	}
+
 	if c.Runtime == "" {
 		// Auto-detect mode - skip validation
 		return nil
@@ -195,6 +331,49 @@ func (c *Config) ResolveTokenPath(path string) string {
 	return path
 }
 
+// ResolveLogPath returns the configured log file path with ~ expanded, or
+// orchestrator.log in dataDir when none is configured.
+func (c *Config) ResolveLogPath(dataDir string) string {
+	if c.Logging.Path == "" {
+		return filepath.Join(dataDir, "orchestrator.log")
+	}
+	return c.ResolveTokenPath(c.Logging.Path)
+}
+
+// ReadAllowlist returns the network allowlist: the inline network.allowlist
+// merged with the domains in network.allowlist_file (read now, so edits to the
+// file take effect on the next reload). A configured file that is missing or
+// invalid is an error.
+func (c *Config) ReadAllowlist() ([]string, error) {
+	var fileDomains []string
+	if path := c.ResolveAllowlistFile(); path != "" {
+		var err error
+		if fileDomains, err = ReadAllowlistFile(path); err != nil {
+			return nil, fmt.Errorf("network.allowlist_file: %w", err)
+		}
+	}
+	return c.Network.AllowedDomains(fileDomains), nil
+}
+
+// ResolveAllowlistFile returns network.allowlist_file with ~ expanded, or ""
+// when none is configured.
+func (c *Config) ResolveAllowlistFile() string {
+	return c.ResolveTokenPath(c.Network.AllowlistFile)
+}
+
+// ReadAllowlistFile reads and parses an allowlist file (see ParseAllowlistFile).
+func ReadAllowlistFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := ParseAllowlistFile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return domains, nil
+}
+
 // ResolveScanPaths returns scan paths with ~ expanded to the user's home directory.
 func (c *Config) ResolveScanPaths() []string {
 	var resolved []string
@@ -204,6 +383,18 @@ func (c *Config) ResolveScanPaths() []string {
 	return resolved
 }
 
+// ResolveCloneRoot returns the directory git URLs are cloned into (clone_root,
+// ~ expanded), defaulting to the first scan path. Returns "" if neither is set.
+func (c *Config) ResolveCloneRoot() string {
+	if c.CloneRoot != "" {
+		return c.ResolveTokenPath(c.CloneRoot)
+	}
+	if paths := c.ResolveScanPaths(); len(paths) > 0 {
+		return paths[0]
+	}
+	return ""
+}
+
 // ResolvePathFunc is the function signature for resolving paths with ~ expansion.
 type ResolvePathFunc func(string) string
 
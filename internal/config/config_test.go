@@ -3,11 +3,37 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+func TestLoadFromFile(t *testing.T) {
+	// Any file name, outside the default config dir layout
+	path := filepath.Join(t.TempDir(), "profiles", "staging.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("theme: frappe\nscan_max_depth: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if cfg.Theme != "frappe" || cfg.ScanMaxDepth != 2 {
+		t.Errorf("Theme/ScanMaxDepth = %q/%d, want frappe/2", cfg.Theme, cfg.ScanMaxDepth)
+	}
+
+	// Unlike LoadFrom, a missing explicit file is an error
+	if _, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.yaml")); !os.IsNotExist(err) {
+		t.Errorf("LoadFromFile(missing) error = %v, want not-exist", err)
+	}
+}
+
 func TestLoadFullConfig(t *testing.T) {
 	// Create temp config file with all fields
 	tempDir := t.TempDir()
@@ -254,6 +280,259 @@ func TestLoadFrom_LogLevel_EmptyUsesDefault(t *testing.T) {
 	}
 }
 
+func TestLoadFrom_LogFormat(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: ""},
+		{content: "log_format: text\n", want: "text"},
+		{content: "log_format: json\n", want: "json"},
+		{content: "log_format: xml\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.LogFormat != tt.want {
+			t.Errorf("LoadFrom(%q).LogFormat = %q, want %q", tt.content, cfg.LogFormat, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_ScanMaxDepth(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: 0},
+		{content: "scan_max_depth: 3\n", want: 3},
+		{content: "scan_max_depth: -1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.ScanMaxDepth != tt.want {
+			t.Errorf("LoadFrom(%q).ScanMaxDepth = %d, want %d", tt.content, cfg.ScanMaxDepth, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_MaxConcurrentBuilds(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: DefaultMaxConcurrentBuilds},
+		{content: "web:\n  port: 8080\n", want: DefaultMaxConcurrentBuilds},
+		{content: "web:\n  max_concurrent_builds: 4\n", want: 4},
+		{content: "web:\n  max_concurrent_builds: 0\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.Web.MaxConcurrentBuilds != tt.want {
+			t.Errorf("LoadFrom(%q).Web.MaxConcurrentBuilds = %d, want %d", tt.content, cfg.Web.MaxConcurrentBuilds, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_UnknownThemeFallsBack(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("theme: solarized\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v, want fallback without error", err)
+	}
+	if cfg.Theme != DefaultTheme || cfg.UnknownTheme != "solarized" {
+		t.Errorf("Theme/UnknownTheme = %q/%q, want %q/solarized", cfg.Theme, cfg.UnknownTheme, DefaultTheme)
+	}
+}
+
+func TestLoadFrom_StartupView(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: ""},
+		{content: "startup_view: tree\n", want: StartupViewTree},
+		{content: "startup_view: logs\n", want: StartupViewLogs},
+		{content: "startup_view: detail\n", want: StartupViewDetail},
+		{content: "startup_view: split\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.StartupView != tt.want {
+			t.Errorf("LoadFrom(%q).StartupView = %q, want %q", tt.content, cfg.StartupView, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_RefreshInterval(t *testing.T) {
+	tests := []struct {
+		content string
+		want    time.Duration
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: DefaultRefreshInterval},
+		{content: "refresh_interval: 30s\n", want: 30 * time.Second},
+		{content: "refresh_interval: 500ms\n", wantErr: true},
+		{content: "refresh_interval: soon\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.RefreshInterval != tt.want {
+			t.Errorf("LoadFrom(%q).RefreshInterval = %s, want %s", tt.content, cfg.RefreshInterval, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_AttachCommandTemplate(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: ""},
+		{content: "attach_command_template: \"{{.Runtime}} exec -it {{.Name}} tmux attach -t {{.Session}}\"\n", want: "{{.Runtime}} exec -it {{.Name}} tmux attach -t {{.Session}}"},
+		{content: "attach_command_template: \"{{.Shell}}\"\n", wantErr: true},
+		{content: "attach_command_template: \"{{.Name\"\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadFrom(configPath)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+		if !tt.wantErr && cfg.AttachCommandTemplate != tt.want {
+			t.Errorf("LoadFrom(%q).AttachCommandTemplate = %q, want %q", tt.content, cfg.AttachCommandTemplate, tt.want)
+		}
+	}
+}
+
+func TestLoadFrom_Logging(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    LoggingConfig
+		wantErr string
+	}{
+		{
+			name:    "omitted uses defaults",
+			content: "theme: latte\n",
+			want:    LoggingConfig{MaxSizeMB: 10, MaxBackups: 3, MaxAgeDays: 7},
+		},
+		{
+			name:    "partial keeps other defaults",
+			content: "logging:\n  path: ~/logs/devagent.log\n  max_size_mb: 50\n",
+			want:    LoggingConfig{Path: "~/logs/devagent.log", MaxSizeMB: 50, MaxBackups: 3, MaxAgeDays: 7},
+		},
+		{
+			name:    "zero size rejected",
+			content: "logging:\n  max_size_mb: 0\n",
+			wantErr: "max_size_mb",
+		},
+		{
+			name:    "negative backups rejected",
+			content: "logging:\n  max_backups: -1\n",
+			wantErr: "max_backups",
+		},
+		{
+			name:    "relative path rejected",
+			content: "logging:\n  path: logs/devagent.log\n",
+			wantErr: "logging.path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			cfg, err := LoadFrom(configPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadFrom() error = %v, want error mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFrom() error = %v", err)
+			}
+			if cfg.Logging != tt.want {
+				t.Errorf("Logging = %+v, want %+v", cfg.Logging, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveLogPath(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := DefaultConfig()
+	if got := cfg.ResolveLogPath("/data"); got != "/data/orchestrator.log" {
+		t.Errorf("ResolveLogPath() default = %q, want /data/orchestrator.log", got)
+	}
+	cfg.Logging.Path = "~/logs/devagent.log"
+	if got, want := cfg.ResolveLogPath("/data"), filepath.Join(home, "logs/devagent.log"); got != want {
+		t.Errorf("ResolveLogPath() = %q, want %q", got, want)
+	}
+}
+
 func TestDefaultConfig_WebConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -276,6 +555,7 @@ func TestWebConfig_UnmarshalYAML(t *testing.T) {
 web:
   port: 8080
   bind: "0.0.0.0"
+  compression: true
 `)
 		var cfg Config
 		if err := yaml.Unmarshal(input, &cfg); err != nil {
@@ -287,6 +567,9 @@ web:
 		if cfg.Web.Bind != "0.0.0.0" {
 			t.Errorf("Web.Bind = %q, want %q", cfg.Web.Bind, "0.0.0.0")
 		}
+		if !cfg.Web.Compression {
+			t.Error("Web.Compression = false, want true")
+		}
 	})
 
 	t.Run("missing web section leaves zero values", func(t *testing.T) {
@@ -521,3 +804,26 @@ func TestConfig_ResolveScanPaths_Empty(t *testing.T) {
 		t.Errorf("expected nil for empty scan paths, got %v", resolved)
 	}
 }
+
+func TestConfig_ResolveCloneRoot(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"unset", Config{}, ""},
+		{"first scan path", Config{ScanPaths: []string{"/opt/projects", "/srv"}}, "/opt/projects"},
+		{"clone_root wins", Config{CloneRoot: "~/clones", ScanPaths: []string{"/opt/projects"}}, filepath.Join(home, "clones")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.ResolveCloneRoot(); got != tt.want {
+				t.Errorf("ResolveCloneRoot() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// pattern: Functional Core
+
+package config
+
+// ConfirmConfig controls which destructive TUI actions open a confirmation
+// dialog. Each field defaults to true (confirm) when omitted; set it to false
+// to run the action immediately.
+type ConfirmConfig struct {
+	DestroyContainer *bool `yaml:"destroy_container"`
+	DeleteWorktree   *bool `yaml:"delete_worktree"`
+	KillSession      *bool `yaml:"kill_session"`
+	Bulk             *bool `yaml:"bulk"` // multi-container operations such as prune
+
+	// DestroyThreshold is how many containers one operation may destroy before
+	// it needs an explicit confirmation: typing "destroy" in the TUI, or
+	// ?confirm=true on the API. 0 means DefaultDestroyConfirmThreshold.
+	DestroyThreshold int `yaml:"destroy_threshold"`
+}
+
+// DefaultDestroyConfirmThreshold is confirm.destroy_threshold when not set.
+const DefaultDestroyConfirmThreshold = 3
+
+// Confirm action names, matching the TUI's confirm dialog actions.
+const (
+	ConfirmDestroyContainer = "destroy_container"
+	ConfirmDeleteWorktree   = "destroy_worktree"
+	ConfirmKillSession      = "kill_session"
+	ConfirmPrune            = "prune"
+)
+
+// Requires reports whether the given action needs confirmation. Unknown
+// actions always require it.
+func (c ConfirmConfig) Requires(action string) bool {
+	var setting *bool
+	switch action {
+	case ConfirmDestroyContainer:
+		setting = c.DestroyContainer
+	case ConfirmDeleteWorktree:
+		setting = c.DeleteWorktree
+	case ConfirmKillSession:
+		setting = c.KillSession
+	case ConfirmPrune:
+		setting = c.Bulk
+	}
+	return setting == nil || *setting
+}
+
+// DestroyConfirmThreshold returns the effective destroy_threshold: operations
+// destroying more containers than this need an explicit confirmation, even
+// when Requires would skip the dialog.
+func (c ConfirmConfig) DestroyConfirmThreshold() int {
+	if c.DestroyThreshold <= 0 {
+		return DefaultDestroyConfirmThreshold
+	}
+	return c.DestroyThreshold
+}
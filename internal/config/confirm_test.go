@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfirmConfig_Requires(t *testing.T) {
+	off := false
+	c := ConfirmConfig{DestroyContainer: &off}
+
+	if c.Requires(ConfirmDestroyContainer) {
+		t.Error("destroy_container disabled, should not require confirmation")
+	}
+	for _, action := range []string{ConfirmDeleteWorktree, ConfirmKillSession, ConfirmPrune, "unknown"} {
+		if !c.Requires(action) {
+			t.Errorf("%s should default to requiring confirmation", action)
+		}
+	}
+}
+
+func TestLoadFrom_ConfirmPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "confirm:\n  kill_session: false\n  bulk: true\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if cfg.Confirm.Requires(ConfirmKillSession) {
+		t.Error("kill_session: false should disable confirmation")
+	}
+	if !cfg.Confirm.Requires(ConfirmPrune) || !cfg.Confirm.Requires(ConfirmDestroyContainer) {
+		t.Error("bulk and omitted actions should require confirmation")
+	}
+}
+
+func TestLoadFrom_DestroyThreshold(t *testing.T) {
+	tests := []struct {
+		content string
+		want    int
+		wantErr bool
+	}{
+		{content: "theme: latte\n", want: DefaultDestroyConfirmThreshold},
+		{content: "confirm:\n  destroy_threshold: 10\n", want: 10},
+		{content: "confirm:\n  destroy_threshold: -1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFrom(path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("LoadFrom(%q) error = nil, want an error", tt.content)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("LoadFrom(%q) error = %v", tt.content, err)
+		}
+		if got := cfg.Confirm.DestroyConfirmThreshold(); got != tt.want {
+			t.Errorf("LoadFrom(%q) threshold = %d, want %d", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// LoggingConfig controls where devagent writes its log file and how it is
+// rotated. Omitted fields keep DefaultConfig's values.
+type LoggingConfig struct {
+	Path       string `yaml:"path"`         // log file; empty means orchestrator.log in the data dir
+	MaxSizeMB  int    `yaml:"max_size_mb"`  // rotate after this many megabytes
+	MaxBackups int    `yaml:"max_backups"`  // rotated files to keep
+	MaxAgeDays int    `yaml:"max_age_days"` // days to keep rotated files
+}
+
+// Validate checks that rotation settings are at least 1 and that the path,
+// if set, is absolute or ~/-relative.
+func (l LoggingConfig) Validate() error {
+	if l.Path != "" && !filepath.IsAbs(l.Path) && !strings.HasPrefix(l.Path, "~/") {
+		return fmt.Errorf("logging.path %q: must be absolute or start with ~/", l.Path)
+	}
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"max_size_mb", l.MaxSizeMB},
+		{"max_backups", l.MaxBackups},
+		{"max_age_days", l.MaxAgeDays},
+	} {
+		if f.value < 1 {
+			return fmt.Errorf("logging.%s: must be at least 1, got %d", f.name, f.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// NameTemplateData is the context a template's NameTemplate is rendered with.
+type NameTemplateData struct {
+	ProjectBase string // last element of the project path
+	ProjectPath string // project path as entered
+	Template    string // template name
+}
+
+// containerNameRe matches names usable as both a container name and a compose
+// project name (compose requires lowercase).
+var containerNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateContainerName checks a container name against the naming rules:
+// lowercase letters, digits, '-' and '_', starting with a letter or digit.
+func ValidateContainerName(name string) error {
+	if !containerNameRe.MatchString(name) {
+		return fmt.Errorf("invalid container name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// parseNameTemplate parses a NameTemplate, failing on unknown fields at
+// render time.
+func parseNameTemplate(text string) (*template.Template, error) {
+	return template.New("name").Option("missingkey=error").Parse(text)
+}
+
+// RenderContainerName renders nameTemplate (e.g. "{{.ProjectBase}}-{{.Template}}")
+// for a project path and template, and validates the result.
+func RenderContainerName(nameTemplate, projectPath, templateName string) (string, error) {
+	tmpl, err := parseNameTemplate(nameTemplate)
+	if err != nil {
+		return "", err
+	}
+	data := NameTemplateData{
+		ProjectBase: filepath.Base(filepath.Clean(projectPath)),
+		ProjectPath: projectPath,
+		Template:    templateName,
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(buf.String())
+	if err := ValidateContainerName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestRenderContainerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		project  string
+		want     string
+		wantErr  bool
+	}{
+		{name: "base and template", template: "{{.ProjectBase}}-{{.Template}}", project: "/home/u/code/api/", want: "api-go-project"},
+		{name: "literal", template: "sandbox", project: "/x", want: "sandbox"},
+		{name: "uppercase is invalid", template: "{{.ProjectBase}}", project: "/home/u/MyApp", wantErr: true},
+		{name: "empty result", template: "{{if false}}x{{end}}", project: "/x", wantErr: true},
+		{name: "unknown field", template: "{{.Nope}}", project: "/x", wantErr: true},
+		{name: "parse error", template: "{{.ProjectBase", project: "/x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderContainerName(tt.template, tt.project, "go-project")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderContainerName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderContainerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
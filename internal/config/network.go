@@ -0,0 +1,180 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// NetworkConfig holds network isolation settings applied to every container's
+// proxy sidecar, on top of the template's own allowlist (filter.py).
+type NetworkConfig struct {
+	// Registries lists private container/package registry hosts (e.g.
+	// "registry.example.com" or "registry.example.com:5000"). Each entry is
+	// expanded by RegistryDomains into the registry host plus its CDN, and the
+	// result is added to both the proxy allowlist and the TLS passthrough list
+	// so clients that pin certificates can still pull.
+	Registries []string `yaml:"registries"`
+
+	// AutoRestartProxy restarts a container's stopped proxy sidecar when a
+	// refresh finds it down while the container is running. Off by default:
+	// the degraded state is only logged and shown in the TUI.
+	AutoRestartProxy bool `yaml:"auto_restart_proxy"`
+
+	// Allowlist lists extra domains every container's proxy allows, on top of
+	// the template's own allowlist. Entries are hostnames or "*.<hostname>"
+	// wildcards (see ValidateAllowlistDomain).
+	Allowlist []string `yaml:"allowlist"`
+
+	// AllowlistFile is an optional file of extra allowed domains, one per line
+	// ('#' starts a comment; see ParseAllowlistFile). It is merged with
+	// Allowlist and re-read on every allowlist reload; may start with ~/.
+	AllowlistFile string `yaml:"allowlist_file"`
+}
+
+// registryHostRe matches a bare DNS hostname (no scheme, path, or wildcard).
+var registryHostRe = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// registryCDNs maps well-known registries to the extra hosts their blobs are
+// served from. Registries not listed here expand to "*.<host>".
+var registryCDNs = map[string][]string{
+	"docker.io": {"registry-1.docker.io", "auth.docker.io", "production.cloudflare.docker.com"},
+	"ghcr.io":   {"pkg-containers.githubusercontent.com"},
+	"quay.io":   {"*.quay.io"},
+	"gcr.io":    {"storage.googleapis.com"},
+}
+
+// allowlistDomainPattern matches a hostname, optionally with a leading "*."
+// wildcard label, as understood by the proxy's filter.py.
+var allowlistDomainPattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidateAllowlistDomain checks that domain is a lowercase hostname or a
+// "*.<hostname>" wildcard.
+func ValidateAllowlistDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("domain is empty")
+	}
+	if len(domain) > 253 || !allowlistDomainPattern.MatchString(domain) {
+		return fmt.Errorf("invalid domain %q: must be a hostname such as example.com or *.example.com", domain)
+	}
+	return nil
+}
+
+// Validate checks that every registry entry is a bare host with an optional
+// port and every allowlist entry is a valid domain.
+func (n NetworkConfig) Validate() error {
+	for _, entry := range n.Registries {
+		if _, err := registryHost(entry); err != nil {
+			return err
+		}
+	}
+	for _, domain := range n.Allowlist {
+		if err := ValidateAllowlistDomain(domain); err != nil {
+			return fmt.Errorf("network.allowlist: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseAllowlistFile parses the content of an allowlist file: one domain per
+// line, blank lines ignored, '#' starting a comment (whole-line or trailing).
+// Every domain must pass ValidateAllowlistDomain; the error names the line.
+func ParseAllowlistFile(content string) ([]string, error) {
+	var domains []string
+	for i, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if err := ValidateAllowlistDomain(line); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		domains = append(domains, line)
+	}
+	return domains, nil
+}
+
+// AllowedDomains merges the inline Allowlist with the domains read from
+// AllowlistFile: inline entries first, then file entries, deduplicated in that
+// order.
+func (n NetworkConfig) AllowedDomains(fileDomains []string) []string {
+	return MergeAllowlists(n.Allowlist, fileDomains)
+}
+
+// MergeAllowlists concatenates domain lists, dropping repeats so each domain
+// keeps its first position.
+func MergeAllowlists(lists ...[]string) []string {
+	var domains []string
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, d := range list {
+			if !seen[d] {
+				seen[d] = true
+				domains = append(domains, d)
+			}
+		}
+	}
+	return domains
+}
+
+// RegistryDomains expands the configured registries into the domain patterns
+// to add to the proxy allowlist and passthrough list. Each registry contributes
+// its host plus its CDN hosts. Ports are stripped (the proxy matches on host),
+// invalid entries are skipped, and the result is deduplicated in input order.
+func (n NetworkConfig) RegistryDomains() []string {
+	var domains []string
+	seen := make(map[string]bool)
+	add := func(d string) {
+		if !seen[d] {
+			seen[d] = true
+			domains = append(domains, d)
+		}
+	}
+
+	for _, entry := range n.Registries {
+		host, err := registryHost(entry)
+		if err != nil {
+			continue
+		}
+		add(host)
+		if cdns, ok := registryCDNs[host]; ok {
+			for _, cdn := range cdns {
+				add(cdn)
+			}
+		} else {
+			add("*." + host)
+		}
+	}
+	return domains
+}
+
+// registryHost normalizes a registry entry to its lowercase hostname,
+// stripping an optional port.
+func registryHost(entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", fmt.Errorf("network.registries: empty entry")
+	}
+	if strings.Contains(entry, "://") || strings.Contains(entry, "/") {
+		return "", fmt.Errorf("network.registries: %q must be a host (no scheme or path)", entry)
+	}
+
+	host := entry
+	if h, port, err := net.SplitHostPort(entry); err == nil {
+		if port == "" {
+			return "", fmt.Errorf("network.registries: %q has an empty port", entry)
+		}
+		host = h
+	}
+
+	if !registryHostRe.MatchString(host) {
+		return "", fmt.Errorf("network.registries: %q is not a valid hostname", entry)
+	}
+	return strings.ToLower(host), nil
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNetworkConfig_RegistryDomains_GenericRegistry(t *testing.T) {
+	n := NetworkConfig{Registries: []string{"Registry.Example.com:5000"}}
+
+	got := n.RegistryDomains()
+	want := []string{"registry.example.com", "*.registry.example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RegistryDomains() = %v, want %v", got, want)
+	}
+}
+
+func TestNetworkConfig_RegistryDomains_KnownCDN(t *testing.T) {
+	n := NetworkConfig{Registries: []string{"ghcr.io", "ghcr.io"}}
+
+	got := n.RegistryDomains()
+	want := []string{"ghcr.io", "pkg-containers.githubusercontent.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RegistryDomains() = %v, want %v (deduplicated)", got, want)
+	}
+}
+
+func TestNetworkConfig_Validate(t *testing.T) {
+	tests := []struct {
+		entry   string
+		wantErr bool
+	}{
+		{"registry.example.com", false},
+		{"registry.example.com:5000", false},
+		{"localhost", false},
+		{"", true},
+		{"https://registry.example.com", true},
+		{"registry.example.com/team", true},
+		{"*.example.com", true},
+		{"registry.example.com:", true},
+	}
+	for _, tt := range tests {
+		err := NetworkConfig{Registries: []string{tt.entry}}.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadFrom_NetworkRegistries(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	content := "network:\n  registries:\n    - registry.example.com\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if !slices.Equal(cfg.Network.Registries, []string{"registry.example.com"}) {
+		t.Errorf("Network.Registries = %v", cfg.Network.Registries)
+	}
+}
+
+func TestLoadFrom_NetworkRegistries_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	content := "theme: latte\nnetwork:\n  registries:\n    - https://registry.example.com\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFrom(configPath)
+	if err == nil {
+		t.Fatal("LoadFrom() should reject a registry with a scheme")
+	}
+	if len(cfg.Network.Registries) != 0 {
+		t.Errorf("invalid config should fall back to defaults, got registries %v", cfg.Network.Registries)
+	}
+}
+
+func TestNetworkConfig_Validate_Allowlist(t *testing.T) {
+	if err := (NetworkConfig{Allowlist: []string{"example.com", "*.example.org"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for _, bad := range []string{"https://example.com", "Example.com", "", "*.*.example.com"} {
+		if err := (NetworkConfig{Allowlist: []string{bad}}).Validate(); err == nil {
+			t.Errorf("Validate(allowlist %q) should fail", bad)
+		}
+	}
+}
+
+func TestParseAllowlistFile(t *testing.T) {
+	content := "# team domains\nexample.com\n\n  *.internal.example.org   # wildcard\nexample.com\n"
+	got, err := ParseAllowlistFile(content)
+	if err != nil {
+		t.Fatalf("ParseAllowlistFile() error = %v", err)
+	}
+	want := []string{"example.com", "*.internal.example.org", "example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAllowlistFile() = %v, want %v", got, want)
+	}
+
+	_, err = ParseAllowlistFile("example.com\nhttps://bad.example.com\n")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ParseAllowlistFile() error = %v, want it to name line 2", err)
+	}
+}
+
+func TestNetworkConfig_AllowedDomains(t *testing.T) {
+	n := NetworkConfig{Allowlist: []string{"b.example.com", "a.example.com"}}
+
+	got := n.AllowedDomains([]string{"a.example.com", "c.example.com", "c.example.com"})
+	want := []string{"b.example.com", "a.example.com", "c.example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("AllowedDomains() = %v, want %v (inline first, then file, deduplicated)", got, want)
+	}
+	if got := (NetworkConfig{}).AllowedDomains(nil); len(got) != 0 {
+		t.Errorf("AllowedDomains() with no sources = %v, want empty", got)
+	}
+}
+
+func TestConfig_ReadAllowlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowlist.txt")
+	if err := os.WriteFile(path, []byte("file.example.com\ninline.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{Network: NetworkConfig{Allowlist: []string{"inline.example.com"}, AllowlistFile: path}}
+
+	got, err := cfg.ReadAllowlist()
+	if err != nil {
+		t.Fatalf("ReadAllowlist() error = %v", err)
+	}
+	if want := []string{"inline.example.com", "file.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("ReadAllowlist() = %v, want %v", got, want)
+	}
+
+	cfg.Network.AllowlistFile = filepath.Join(t.TempDir(), "missing.txt")
+	if _, err := cfg.ReadAllowlist(); err == nil {
+		t.Error("ReadAllowlist() should fail for a missing allowlist_file")
+	}
+}
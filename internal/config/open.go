@@ -0,0 +1,38 @@
+// pattern: Functional Core
+
+package config
+
+import "fmt"
+
+// Open modes for Config.OpenMode (`devagent open`).
+const (
+	OpenModeAuto     = "auto"     // VS Code when the code CLI is installed, else terminal
+	OpenModeVSCode   = "vscode"   // open VS Code attached to the container
+	OpenModeTerminal = "terminal" // attach to a tmux session in this terminal
+)
+
+// vscodeBinary is the VS Code command-line launcher used by OpenModeVSCode.
+const vscodeBinary = "code"
+
+// validateOpenMode rejects open_mode values other than the OpenMode constants.
+func validateOpenMode(mode string) error {
+	switch mode {
+	case "", OpenModeAuto, OpenModeVSCode, OpenModeTerminal:
+		return nil
+	}
+	return fmt.Errorf("open_mode %q: must be auto, vscode or terminal", mode)
+}
+
+// ResolvedOpenMode returns how `devagent open` attaches: OpenModeVSCode or
+// OpenModeTerminal. An empty or auto OpenMode picks VS Code when the code CLI
+// is found by lookPath, and the terminal otherwise.
+func (c *Config) ResolvedOpenMode(lookPath LookPathFunc) string {
+	switch c.OpenMode {
+	case OpenModeVSCode, OpenModeTerminal:
+		return c.OpenMode
+	}
+	if _, err := lookPath(vscodeBinary); err == nil {
+		return OpenModeVSCode
+	}
+	return OpenModeTerminal
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvedOpenMode(t *testing.T) {
+	withCode := func(name string) (string, error) {
+		if name == "code" {
+			return "/usr/local/bin/code", nil
+		}
+		return "", errors.New("not found")
+	}
+	withoutCode := func(string) (string, error) { return "", errors.New("not found") }
+
+	tests := []struct {
+		name     string
+		mode     string
+		lookPath LookPathFunc
+		want     string
+	}{
+		{name: "default with code installed", lookPath: withCode, want: OpenModeVSCode},
+		{name: "default without code", lookPath: withoutCode, want: OpenModeTerminal},
+		{name: "auto with code installed", mode: OpenModeAuto, lookPath: withCode, want: OpenModeVSCode},
+		{name: "auto without code", mode: OpenModeAuto, lookPath: withoutCode, want: OpenModeTerminal},
+		{name: "terminal despite code", mode: OpenModeTerminal, lookPath: withCode, want: OpenModeTerminal},
+		{name: "vscode without code", mode: OpenModeVSCode, lookPath: withoutCode, want: OpenModeVSCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{OpenMode: tt.mode}
+			if got := cfg.ResolvedOpenMode(tt.lookPath); got != tt.want {
+				t.Errorf("ResolvedOpenMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFrom_OpenMode(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("open_mode: browser\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadFrom(configPath); err == nil {
+		t.Error("LoadFrom() accepted open_mode: browser")
+	}
+}
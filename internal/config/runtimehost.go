@@ -0,0 +1,73 @@
+// pattern: Imperative Shell
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsPodmanBinary reports whether binary (a name or path) is podman or one of
+// its tools such as podman-compose, which take CONTAINER_HOST and --url
+// rather than Docker's DOCKER_HOST and --host.
+func IsPodmanBinary(binary string) bool {
+	return strings.HasPrefix(filepath.Base(binary), "podman")
+}
+
+// RuntimeHostEnvVar returns the environment variable a runtime binary reads
+// its daemon address from: CONTAINER_HOST for podman (and podman-compose),
+// DOCKER_HOST for everything else (docker, docker-compose).
+func RuntimeHostEnvVar(binary string) string {
+	if IsPodmanBinary(binary) {
+		return "CONTAINER_HOST"
+	}
+	return "DOCKER_HOST"
+}
+
+// DetectedRuntimeHost returns the daemon the runtime should talk to:
+// runtime_host when set, otherwise the detected runtime's own variable
+// (DOCKER_HOST or CONTAINER_HOST) from the environment. Empty means the
+// runtime's default local socket.
+func (c *Config) DetectedRuntimeHost() string {
+	if c.RuntimeHost != "" {
+		return c.RuntimeHost
+	}
+	return os.Getenv(RuntimeHostEnvVar(c.DetectedRuntime()))
+}
+
+// RuntimeHostEnv returns the environment entry (e.g. DOCKER_HOST=ssh://...)
+// that points a runtime process started outside container.Runtime, such as an
+// attach, at the detected host. Nil when there is none.
+func (c *Config) RuntimeHostEnv() []string {
+	host := c.DetectedRuntimeHost()
+	if host == "" {
+		return nil
+	}
+	return []string{RuntimeHostEnvVar(c.DetectedRuntime()) + "=" + host}
+}
+
+// ValidateRuntimeHost checks a runtime host address: unix:///path/to/socket
+// for a local (e.g. rootless podman) socket, tcp://host:port, or
+// ssh://[user@]host[:port][/socket] for a remote daemon.
+func ValidateRuntimeHost(host string) error {
+	u, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return fmt.Errorf("unix host needs a socket path, e.g. unix:///run/user/1000/podman/podman.sock")
+		}
+	case "tcp", "ssh":
+		if u.Hostname() == "" {
+			return fmt.Errorf("%s host needs a host name, e.g. %s://example.com", u.Scheme, u.Scheme)
+		}
+	default:
+		return fmt.Errorf("scheme must be unix, tcp or ssh, got %q", u.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidateRuntimeHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{"unix:///run/user/1000/podman/podman.sock", false},
+		{"tcp://10.0.0.5:2375", false},
+		{"ssh://core@build-box", false},
+		{"ssh://core@build-box:2222/run/user/1000/podman/podman.sock", false},
+		{"unix://", true},
+		{"tcp://:2375", true},
+		{"ftp://build-box", true},
+		{"/var/run/docker.sock", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateRuntimeHost(tt.host); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRuntimeHost(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateRuntime_AcceptsRemoteHost(t *testing.T) {
+	lookPath := func(name string) (string, error) { return "/usr/bin/" + name, nil }
+
+	cfg := Config{Runtime: "podman", RuntimeHost: "ssh://core@build-box/run/user/1000/podman/podman.sock"}
+	if err := cfg.ValidateRuntimeWith(lookPath); err != nil {
+		t.Errorf("ValidateRuntime(remote podman) error = %v, want nil", err)
+	}
+
+	cfg = Config{Runtime: "docker", RuntimeHost: "ftp://build-box"}
+	if err := cfg.ValidateRuntimeWith(lookPath); err == nil {
+		t.Error("ValidateRuntime(ftp host) error = nil, want an error")
+	}
+
+	t.Setenv("DOCKER_HOST", "nonsense")
+	cfg = Config{Runtime: "docker"}
+	if err := cfg.ValidateRuntimeWith(lookPath); err == nil {
+		t.Error("ValidateRuntime(invalid DOCKER_HOST) error = nil, want an error")
+	}
+}
+
+func TestDetectedRuntimeHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://docker-box:2375")
+	t.Setenv("CONTAINER_HOST", "ssh://core@podman-box")
+
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{Runtime: "docker"}, "tcp://docker-box:2375"},
+		{Config{Runtime: "podman"}, "ssh://core@podman-box"},
+		{Config{Runtime: "podman", RuntimeHost: "unix:///tmp/podman.sock"}, "unix:///tmp/podman.sock"},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.DetectedRuntimeHost(); got != tt.want {
+			t.Errorf("%+v.DetectedRuntimeHost() = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+
+	cfg := Config{Runtime: "podman"}
+	if got := cfg.RuntimeHostEnv(); !slices.Equal(got, []string{"CONTAINER_HOST=ssh://core@podman-box"}) {
+		t.Errorf("RuntimeHostEnv() = %v", got)
+	}
+}
+
+func TestLoadFrom_RuntimeHost(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr bool
+	}{
+		{content: "runtime_host: ssh://core@build-box\n"},
+		{content: "runtime_host: unix:///run/user/1000/podman/podman.sock\n"},
+		{content: "runtime_host: build-box:2375\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		if _, err := LoadFrom(configPath); (err != nil) != tt.wantErr {
+			t.Errorf("LoadFrom(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+		}
+	}
+}
+
+func TestIsPodmanBinary(t *testing.T) {
+	for binary, want := range map[string]bool{
+		"podman":                true,
+		"/usr/bin/podman":       true,
+		"podman-compose":        true,
+		"docker":                false,
+		"/usr/local/bin/docker": false,
+		"docker-compose":        false,
+	} {
+		if got := IsPodmanBinary(binary); got != want {
+			t.Errorf("IsPodmanBinary(%q) = %v, want %v", binary, got, want)
+		}
+		if got := RuntimeHostEnvVar(binary) == "CONTAINER_HOST"; got != want {
+			t.Errorf("RuntimeHostEnvVar(%q) = %q, want podman's variable %v", binary, RuntimeHostEnvVar(binary), want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ScanPathWarnings reports scan paths that overlap dangerously: a scan path
+// that contains one of devagent's own directories (config or data dir), where
+// discovery can pick up devagent artifacts, and scan paths that repeat or are
+// nested inside another scan path, which causes duplicate discovery.
+// All paths must already be resolved (see ResolveScanPaths).
+func ScanPathWarnings(scanPaths []string, devagentDirs []string) []string {
+	var warnings []string
+	clean := make([]string, len(scanPaths))
+	for i, p := range scanPaths {
+		clean[i] = filepath.Clean(p)
+	}
+
+	for i, p := range clean {
+		for _, dir := range devagentDirs {
+			if dir != "" && pathWithin(filepath.Clean(dir), p) {
+				warnings = append(warnings, fmt.Sprintf("scan path %s contains devagent directory %s", p, filepath.Clean(dir)))
+			}
+		}
+		for j, other := range clean {
+			switch {
+			case i == j:
+			case p == other:
+				if j < i {
+					warnings = append(warnings, fmt.Sprintf("scan path %s is listed more than once", p))
+				}
+			case pathWithin(p, other):
+				warnings = append(warnings, fmt.Sprintf("scan path %s is inside scan path %s", p, other))
+			}
+		}
+	}
+	return warnings
+}
+
+// pathWithin reports whether path equals parent or lies beneath it.
+func pathWithin(path, parent string) bool {
+	rel, err := filepath.Rel(parent, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanPathWarnings(t *testing.T) {
+	devagentDirs := []string{"/home/u/.config/devagent"}
+
+	tests := []struct {
+		name      string
+		scanPaths []string
+		want      []string
+	}{
+		{
+			name:      "disjoint paths",
+			scanPaths: []string{"/home/u/code", "/home/u/work"},
+		},
+		{
+			name:      "sibling with shared prefix is not nested",
+			scanPaths: []string{"/home/u/code", "/home/u/code-old"},
+		},
+		{
+			name:      "home contains config dir",
+			scanPaths: []string{"/home/u"},
+			want:      []string{"scan path /home/u contains devagent directory /home/u/.config/devagent"},
+		},
+		{
+			name:      "config dir itself",
+			scanPaths: []string{"/home/u/.config/devagent/"},
+			want:      []string{"scan path /home/u/.config/devagent contains devagent directory /home/u/.config/devagent"},
+		},
+		{
+			name:      "nested scan paths",
+			scanPaths: []string{"/home/u/code", "/home/u/code/work"},
+			want:      []string{"scan path /home/u/code/work is inside scan path /home/u/code"},
+		},
+		{
+			name:      "duplicate scan paths",
+			scanPaths: []string{"/home/u/code", "/home/u/code/"},
+			want:      []string{"scan path /home/u/code is listed more than once"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScanPathWarnings(tt.scanPaths, devagentDirs)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ScanPathWarnings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
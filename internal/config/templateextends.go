@@ -0,0 +1,109 @@
+// pattern: Functional Core
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resolveTemplateExtends applies template inheritance: each template that
+// extends a base gets the base's settings where it sets none, the base's
+// sessions merged with its own, and the base directories in BasePaths. Bases
+// are resolved first, so chains work. A template whose chain names an unknown
+// template or loops back on itself is dropped with an error, as is every
+// template extending it.
+func resolveTemplateExtends(templates []Template) ([]Template, []error) {
+	byName := make(map[string]Template, len(templates))
+	for _, t := range templates {
+		byName[t.Name] = t
+	}
+
+	resolved := make(map[string]Template, len(templates))
+	failed := make(map[string]error)
+
+	var resolve func(name string, chain []string) (Template, error)
+	resolve = func(name string, chain []string) (Template, error) {
+		if t, ok := resolved[name]; ok {
+			return t, nil
+		}
+		if err, ok := failed[name]; ok {
+			return Template{}, err
+		}
+		for _, seen := range chain {
+			if seen == name {
+				return Template{}, fmt.Errorf("extends cycle: %s", strings.Join(append(chain, name), " -> "))
+			}
+		}
+		t, ok := byName[name]
+		if !ok {
+			return Template{}, fmt.Errorf("extends unknown template %q", name)
+		}
+		if t.Extends == "" {
+			resolved[name] = t
+			return t, nil
+		}
+
+		base, err := resolve(t.Extends, append(chain, name))
+		if err != nil {
+			failed[name] = err
+			return Template{}, err
+		}
+		t = inheritTemplate(t, base)
+		resolved[name] = t
+		return t, nil
+	}
+
+	var result []Template
+	var errs []error
+	for _, t := range templates {
+		r, err := resolve(t.Name, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template %s: %w", t.Name, err))
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, errs
+}
+
+// inheritTemplate returns child with base's values filled in: settings the
+// child leaves empty come from base, sessions are merged by name (base order,
+// a child session replaces the base one of the same name, new ones follow),
+// and base's directories go beneath the child's.
+func inheritTemplate(child, base Template) Template {
+	if child.NameTemplate == "" {
+		child.NameTemplate = base.NameTemplate
+	}
+	if child.DefaultScanRoot == "" {
+		child.DefaultScanRoot = base.DefaultScanRoot
+	}
+	if child.AllowlistFile == "" {
+		child.AllowlistFile = base.AllowlistFile
+	}
+	child.UseExistingDevcontainer = child.UseExistingDevcontainer || base.UseExistingDevcontainer
+	child.InitialSessions = mergeSessionSpecs(base.InitialSessions, child.InitialSessions)
+	child.BasePaths = append(append([]string{}, base.BasePaths...), base.Path)
+	return child
+}
+
+// mergeSessionSpecs merges two session lists by name; override wins.
+func mergeSessionSpecs(base, override []SessionSpec) []SessionSpec {
+	if len(base) == 0 {
+		return override
+	}
+	merged := append([]SessionSpec{}, base...)
+	index := make(map[string]int, len(merged))
+	for i, s := range merged {
+		index[s.Name] = i
+	}
+	for _, s := range override {
+		if i, ok := index[s.Name]; ok {
+			merged[i] = s
+			continue
+		}
+		index[s.Name] = len(merged)
+		merged = append(merged, s)
+	}
+	return merged
+}
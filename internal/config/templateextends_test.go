@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeChildTemplate writes a template directory holding only template.yaml
+// (no .devcontainer marker) plus any extra files.
+func writeChildTemplate(t *testing.T, dir, name, settings string, files map[string]string) string {
+	t.Helper()
+	templateDir := filepath.Join(dir, name)
+	files[settingsFileName] = settings
+	for path, content := range files {
+		full := filepath.Join(templateDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile %s: %v", path, err)
+		}
+	}
+	return templateDir
+}
+
+func templateByName(templates []Template, name string) *Template {
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i]
+		}
+	}
+	return nil
+}
+
+func TestLoadTemplates_ExtendsInheritsAndOverrides(t *testing.T) {
+	dir := t.TempDir()
+	baseDir := writeTemplateFiles(t, dir, "base", map[string]string{
+		"template.yaml": "name_template: \"{{.ProjectBase}}\"\ndefault_scan_root: ~/code/\n",
+		"sessions.yaml": "sessions:\n  - name: main\n  - name: logs\n    command: tail -f /tmp/log\n",
+	})
+	middleDir := writeChildTemplate(t, dir, "middle", "extends: base\ndefault_scan_root: ~/work/\n", map[string]string{
+		"sessions.yaml": "sessions:\n  - name: logs\n    command: journalctl -f\n  - name: tests\n",
+	})
+	childDir := writeChildTemplate(t, dir, "child", "extends: middle\nname_template: \"{{.ProjectBase}}-dev\"\n", map[string]string{})
+
+	templates, loadErrs, err := loadTemplatesFrom(dir)
+	if err != nil || len(loadErrs) != 0 {
+		t.Fatalf("loadTemplatesFrom() err = %v, loadErrs = %v", err, loadErrs)
+	}
+	if len(templates) != 3 {
+		t.Fatalf("got %d templates, want 3", len(templates))
+	}
+
+	middle := templateByName(templates, "middle")
+	if middle.NameTemplate != "{{.ProjectBase}}" {
+		t.Errorf("middle NameTemplate = %q, want inherited", middle.NameTemplate)
+	}
+	if middle.DefaultScanRoot != "~/work/" {
+		t.Errorf("middle DefaultScanRoot = %q, want override", middle.DefaultScanRoot)
+	}
+	wantSessions := []SessionSpec{{Name: "main"}, {Name: "logs", Command: "journalctl -f"}, {Name: "tests"}}
+	if !slices.Equal(middle.InitialSessions, wantSessions) {
+		t.Errorf("middle sessions = %+v, want %+v", middle.InitialSessions, wantSessions)
+	}
+
+	child := templateByName(templates, "child")
+	if child.NameTemplate != "{{.ProjectBase}}-dev" || child.DefaultScanRoot != "~/work/" {
+		t.Errorf("child settings = %q, %q", child.NameTemplate, child.DefaultScanRoot)
+	}
+	if !slices.Equal(child.BasePaths, []string{baseDir, middleDir}) {
+		t.Errorf("child BasePaths = %v", child.BasePaths)
+	}
+	wantDirs := []string{
+		filepath.Join(baseDir, ".devcontainer"),
+		filepath.Join(middleDir, ".devcontainer"),
+		filepath.Join(childDir, ".devcontainer"),
+	}
+	if !slices.Equal(child.DevcontainerDirs(), wantDirs) {
+		t.Errorf("DevcontainerDirs() = %v, want %v", child.DevcontainerDirs(), wantDirs)
+	}
+	if err := child.Validate(); err != nil {
+		t.Errorf("Validate() on a child without .devcontainer = %v", err)
+	}
+}
+
+func TestLoadTemplates_ExtendsErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, "ok", nil)
+	writeTemplateFiles(t, dir, "a", map[string]string{"template.yaml": "extends: b\n"})
+	writeTemplateFiles(t, dir, "b", map[string]string{"template.yaml": "extends: a\n"})
+	writeChildTemplate(t, dir, "orphan", "extends: missing\n", map[string]string{})
+	writeChildTemplate(t, dir, "standalone-settings", "default_scan_root: ~/code/\n", map[string]string{})
+
+	templates, loadErrs, err := loadTemplatesFrom(dir)
+	if err != nil {
+		t.Fatalf("loadTemplatesFrom() error = %v", err)
+	}
+	if len(templates) != 1 || templates[0].Name != "ok" {
+		t.Errorf("templates = %v, want only ok", templates)
+	}
+
+	joined := ""
+	for _, e := range loadErrs {
+		joined += e.Error() + "\n"
+	}
+	for _, want := range []string{
+		"template a: extends cycle: a -> b -> a",
+		"template b: extends cycle:",
+		`template orphan: extends unknown template "missing"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("load errors missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "standalone-settings") {
+		t.Errorf("a template.yaml without marker or extends should be ignored:\n%s", joined)
+	}
+}
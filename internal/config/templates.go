@@ -3,8 +3,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Template represents a loaded devcontainer template.
@@ -14,8 +18,79 @@ import (
 type Template struct {
 	Name string // Template name (from directory name)
 	Path string // Absolute path to template directory
+
+	// InitialSessions are tmux sessions created in every new container built
+	// from this template. Loaded from the optional sessions.yaml at the
+	// template root.
+	InitialSessions []SessionSpec
+
+	// NameTemplate and DefaultScanRoot pre-fill the TUI create form. Loaded
+	// from the optional template.yaml at the template root.
+	NameTemplate    string // text/template for the container name (see RenderContainerName)
+	DefaultScanRoot string // initial project path; may start with ~/
+
+	// AllowlistFile is an optional file of extra allowed domains (same format
+	// as network.allowlist_file) from template.yaml's allowlist_file. A
+	// relative path is resolved against the templates directory so several
+	// templates can share one file; ~/ is expanded. Empty when unset.
+	AllowlistFile string
+
+	// UseExistingDevcontainer keeps a project's own .devcontainer/
+	// devcontainer.json instead of overwriting it with the template's, from
+	// template.yaml's use_existing_devcontainer (see
+	// container.CreateOptions.UseExisting).
+	UseExistingDevcontainer bool
+
+	// Extends names the base template from template.yaml's extends; empty
+	// when the template stands alone. Inheritance is resolved at load (see
+	// resolveTemplateExtends), so the fields above already include the base's.
+	Extends string
+	// BasePaths are the directories of the templates this one extends,
+	// outermost base first. Their .devcontainer files sit beneath this
+	// template's own (see DevcontainerDirs).
+	BasePaths []string
 }
 
+// DevcontainerDirs returns the .devcontainer directories a container is built
+// from, outermost base first and this template's own last; a file in a later
+// directory replaces the same file in an earlier one.
+func (t Template) DevcontainerDirs() []string {
+	dirs := make([]string, 0, len(t.BasePaths)+1)
+	for _, p := range t.BasePaths {
+		dirs = append(dirs, filepath.Join(p, ".devcontainer"))
+	}
+	return append(dirs, filepath.Join(t.Path, ".devcontainer"))
+}
+
+// templateSettings is the content of a template's optional template.yaml.
+type templateSettings struct {
+	NameTemplate    string `yaml:"name_template"`
+	DefaultScanRoot string `yaml:"default_scan_root"`
+	AllowlistFile   string `yaml:"allowlist_file"`
+	Extends         string `yaml:"extends"` // base template name
+
+	UseExistingDevcontainer bool `yaml:"use_existing_devcontainer"`
+}
+
+// SessionSpec describes a tmux session to create after container creation.
+type SessionSpec struct {
+	Name    string `yaml:"name"`    // Session name (letters, digits, '-' and '_')
+	Cwd     string `yaml:"cwd"`     // Working directory inside the container; empty uses tmux's default
+	Command string `yaml:"command"` // Shell command to run; empty starts an interactive shell
+}
+
+// sessionsFileName is the optional per-template file listing initial sessions.
+// It lives at the template root (outside .devcontainer/) so it is never copied
+// into projects.
+const sessionsFileName = "sessions.yaml"
+
+// settingsFileName is the optional per-template file with form defaults. Like
+// sessions.yaml it lives at the template root.
+const settingsFileName = "template.yaml"
+
+// validSessionNameRe matches the session names accepted by the web API.
+var validSessionNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 // customTemplatesPath allows overriding the templates directory.
 var customTemplatesPath string
 
@@ -33,51 +108,156 @@ func LoadTemplates() ([]Template, error) {
 }
 
 // LoadTemplatesFrom loads all templates from the specified directory.
-// Each subdirectory containing a .devcontainer/docker-compose.yml.tmpl file is treated as a template.
-// The directory name is used as the template name.
+// Each subdirectory containing a .devcontainer/docker-compose.yml.tmpl file, or
+// a template.yaml that extends another template, is treated as a template.
+// The directory name is used as the template name. Templates that fail to load
+// (including an unknown or cyclic extends) are skipped; TemplateWarningsFrom
+// reports why.
 func LoadTemplatesFrom(dir string) ([]Template, error) {
+	templates, _, err := loadTemplatesFrom(dir)
+	return templates, err
+}
+
+// loadTemplatesFrom loads the templates in dir, returning the loaded templates
+// and the load error of each skipped template (prefixed with its name).
+func loadTemplatesFrom(dir string) ([]Template, []error, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return []Template{}, nil
+			return []Template{}, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	var templates []Template
+	var loadErrs []error
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 
 		templateDir := filepath.Join(dir, entry.Name())
-		markerPath := filepath.Join(templateDir, ".devcontainer", "docker-compose.yml.tmpl")
-		if _, err := os.Stat(markerPath); err != nil {
-			if os.IsNotExist(err) {
-				continue // Not a template directory
-			}
-			continue // Skip on stat errors
+		if !hasTemplateMarker(templateDir) && !fileExists(filepath.Join(templateDir, settingsFileName)) {
+			continue // Not a template directory
 		}
 
 		tmpl, err := loadTemplate(templateDir, entry.Name())
 		if err != nil {
+			loadErrs = append(loadErrs, fmt.Errorf("template %s: %w", entry.Name(), err))
+			continue
+		}
+		// Without the marker a directory is only a template if it extends one
+		if tmpl.Extends == "" && !hasTemplateMarker(templateDir) {
 			continue
 		}
 		templates = append(templates, tmpl)
 	}
 
-	return templates, nil
+	templates, extendErrs := resolveTemplateExtends(templates)
+	return templates, append(loadErrs, extendErrs...), nil
+}
+
+// hasTemplateMarker reports whether dir has .devcontainer/docker-compose.yml.tmpl.
+func hasTemplateMarker(dir string) bool {
+	return fileExists(filepath.Join(dir, ".devcontainer", "docker-compose.yml.tmpl"))
+}
+
+// fileExists reports whether path can be stat'ed.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
 }
 
 // loadTemplate loads a single template from a directory.
 // The dirName is used as the template name.
 func loadTemplate(templateDir string, dirName string) (Template, error) {
+	sessions, err := loadSessionSpecs(filepath.Join(templateDir, sessionsFileName))
+	if err != nil {
+		return Template{}, err
+	}
+	settings, err := loadTemplateSettings(filepath.Join(templateDir, settingsFileName))
+	if err != nil {
+		return Template{}, err
+	}
 	return Template{
-		Name: dirName,
-		Path: templateDir,
+		Name:            dirName,
+		Path:            templateDir,
+		InitialSessions: sessions,
+		NameTemplate:    settings.NameTemplate,
+		DefaultScanRoot: settings.DefaultScanRoot,
+		AllowlistFile:   resolveTemplateAllowlistFile(settings.AllowlistFile, filepath.Dir(templateDir)),
+		Extends:         settings.Extends,
+
+		UseExistingDevcontainer: settings.UseExistingDevcontainer,
 	}, nil
 }
 
+// resolveTemplateAllowlistFile resolves a template's allowlist_file: ~/ is
+// expanded and a relative path is taken relative to the templates directory.
+func resolveTemplateAllowlistFile(path, templatesDir string) string {
+	if path == "" {
+		return ""
+	}
+	path = (&Config{}).ResolveTokenPath(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(templatesDir, path)
+	}
+	return path
+}
+
+// loadTemplateSettings reads a template.yaml file. A missing file yields zero
+// settings. Returns an error for malformed YAML or an unparsable name_template.
+func loadTemplateSettings(path string) (templateSettings, error) {
+	var settings templateSettings
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, err
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if settings.NameTemplate != "" {
+		if _, err := parseNameTemplate(settings.NameTemplate); err != nil {
+			return settings, fmt.Errorf("%s: invalid name_template: %w", path, err)
+		}
+	}
+	return settings, nil
+}
+
+// loadSessionSpecs reads a sessions.yaml file. A missing file yields no sessions.
+// Returns an error for malformed YAML, invalid or duplicate session names.
+func loadSessionSpecs(path string) ([]SessionSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var file struct {
+		Sessions []SessionSpec `yaml:"sessions"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range file.Sessions {
+		if !validSessionNameRe.MatchString(s.Name) {
+			return nil, fmt.Errorf("%s: invalid session name %q", path, s.Name)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("%s: duplicate session name %q", path, s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return file.Sessions, nil
+}
+
 func getTemplatesPath() string {
 	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
 		return filepath.Join(xdgConfig, "devagent", "templates")
@@ -145,3 +145,120 @@ func TestLoadTemplates_UsesDirectoryName(t *testing.T) {
 		t.Errorf("Name should be directory name: got %q, want %q", templates[0].Name, "my-template")
 	}
 }
+
+func TestLoadTemplates_InitialSessions(t *testing.T) {
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "basic")
+	devcontainerDir := filepath.Join(templateDir, ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devcontainerDir, "docker-compose.yml.tmpl"), []byte("services:\n  app:\n"), 0644); err != nil {
+		t.Fatalf("Failed to write docker-compose.yml.tmpl: %v", err)
+	}
+
+	sessions := `sessions:
+  - name: dev
+    cwd: /workspace
+    command: make watch
+  - name: agent
+`
+	if err := os.WriteFile(filepath.Join(templateDir, "sessions.yaml"), []byte(sessions), 0644); err != nil {
+		t.Fatalf("Failed to write sessions.yaml: %v", err)
+	}
+
+	templates, err := LoadTemplatesFrom(tempDir)
+	if err != nil {
+		t.Fatalf("LoadTemplatesFrom failed: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("Expected 1 template, got %d", len(templates))
+	}
+
+	want := []SessionSpec{
+		{Name: "dev", Cwd: "/workspace", Command: "make watch"},
+		{Name: "agent"},
+	}
+	got := templates[0].InitialSessions
+	if len(got) != len(want) {
+		t.Fatalf("InitialSessions = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("InitialSessions[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadSessionSpecs_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad name":  "sessions:\n  - name: \"has space\"\n",
+		"empty":     "sessions:\n  - cwd: /tmp\n",
+		"duplicate": "sessions:\n  - name: dev\n  - name: dev\n",
+		"bad yaml":  "sessions: [\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sessions.yaml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write sessions.yaml: %v", err)
+			}
+			if _, err := loadSessionSpecs(path); err == nil {
+				t.Error("loadSessionSpecs() should have failed")
+			}
+		})
+	}
+
+	sessions, err := loadSessionSpecs(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || sessions != nil {
+		t.Errorf("missing file: got (%v, %v), want (nil, nil)", sessions, err)
+	}
+}
+
+func TestLoadTemplates_Settings(t *testing.T) {
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "basic")
+	devcontainerDir := filepath.Join(templateDir, ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(devcontainerDir, "docker-compose.yml.tmpl"), []byte("services:\n  app:\n"), 0644); err != nil {
+		t.Fatalf("Failed to write docker-compose.yml.tmpl: %v", err)
+	}
+	settings := "name_template: \"{{.ProjectBase}}-{{.Template}}\"\ndefault_scan_root: ~/code/\nuse_existing_devcontainer: true\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "template.yaml"), []byte(settings), 0644); err != nil {
+		t.Fatalf("Failed to write template.yaml: %v", err)
+	}
+
+	templates, err := LoadTemplatesFrom(tempDir)
+	if err != nil {
+		t.Fatalf("LoadTemplatesFrom failed: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("Expected 1 template, got %d", len(templates))
+	}
+	if templates[0].NameTemplate != "{{.ProjectBase}}-{{.Template}}" || templates[0].DefaultScanRoot != "~/code/" {
+		t.Errorf("settings = (%q, %q), want name template and scan root", templates[0].NameTemplate, templates[0].DefaultScanRoot)
+	}
+	if !templates[0].UseExistingDevcontainer {
+		t.Error("UseExistingDevcontainer = false, want true from use_existing_devcontainer")
+	}
+}
+
+func TestLoadTemplateSettings_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad template": "name_template: \"{{.ProjectBase\"\n",
+		"bad yaml":     "name_template: [\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.yaml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write template.yaml: %v", err)
+			}
+			if _, err := loadTemplateSettings(path); err == nil {
+				t.Error("loadTemplateSettings() should have failed")
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// pattern: Imperative Shell
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Validate checks a loaded template for problems that would otherwise only
+// surface when a container is created from it: .tmpl files under .devcontainer
+// that do not parse, invalid or duplicate initial session names, an
+// unparsable name template, and an allowlist file that is missing or invalid.
+// All problems are returned joined.
+func (t Template) Validate() error {
+	var errs []error
+
+	devcontainerDir := filepath.Join(t.Path, ".devcontainer")
+	walkErr := filepath.WalkDir(devcontainerDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".tmpl") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if _, err := template.New(filepath.Base(path)).Parse(string(content)); err != nil {
+			rel, _ := filepath.Rel(t.Path, path)
+			errs = append(errs, fmt.Errorf("%s: %w", rel, err))
+		}
+		return nil
+	})
+	// A template that extends another may take every file from its base
+	if walkErr != nil && !(len(t.BasePaths) > 0 && errors.Is(walkErr, fs.ErrNotExist)) {
+		errs = append(errs, fmt.Errorf("read .devcontainer: %w", walkErr))
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range t.InitialSessions {
+		if !validSessionNameRe.MatchString(s.Name) {
+			errs = append(errs, fmt.Errorf("invalid session name %q", s.Name))
+		} else if seen[s.Name] {
+			errs = append(errs, fmt.Errorf("duplicate session name %q", s.Name))
+		}
+		seen[s.Name] = true
+	}
+
+	if t.NameTemplate != "" {
+		if _, err := parseNameTemplate(t.NameTemplate); err != nil {
+			errs = append(errs, fmt.Errorf("invalid name_template: %w", err))
+		}
+	}
+
+	if t.AllowlistFile != "" {
+		if _, err := ReadAllowlistFile(t.AllowlistFile); err != nil {
+			errs = append(errs, fmt.Errorf("allowlist_file: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// TemplateWarnings validates the templates in the default templates directory
+// (see TemplateWarningsFrom).
+func TemplateWarnings() []string {
+	if customTemplatesPath != "" {
+		return TemplateWarningsFrom(customTemplatesPath)
+	}
+	return TemplateWarningsFrom(getTemplatesPath())
+}
+
+// TemplateWarningsFrom reports every problem across all templates in dir, one
+// warning per problem prefixed with the template name: templates that
+// LoadTemplatesFrom skips because sessions.yaml or template.yaml is malformed,
+// and each Validate error of the loaded ones. A bad template never blocks the
+// others, so these are warnings rather than load errors.
+func TemplateWarningsFrom(dir string) []string {
+	templates, loadErrs, err := loadTemplatesFrom(dir)
+	if err != nil {
+		return []string{fmt.Sprintf("read templates directory %s: %v", dir, err)}
+	}
+
+	var warnings []string
+	for _, err := range loadErrs {
+		warnings = append(warnings, err.Error())
+	}
+	for _, t := range templates {
+		err := t.Validate()
+		if err == nil {
+			continue
+		}
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("template %s: %v", t.Name, err))
+			continue
+		}
+		for _, e := range joined.Unwrap() {
+			warnings = append(warnings, fmt.Sprintf("template %s: %v", t.Name, e))
+		}
+	}
+	return warnings
+}
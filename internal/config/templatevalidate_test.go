@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTemplateFiles creates a template directory under dir with the given
+// files (paths relative to the template root) plus the compose marker.
+func writeTemplateFiles(t *testing.T, dir, name string, files map[string]string) string {
+	t.Helper()
+	templateDir := filepath.Join(dir, name)
+	all := map[string]string{".devcontainer/docker-compose.yml.tmpl": "services:\n  app:\n"}
+	for path, content := range files {
+		all[path] = content
+	}
+	for path, content := range all {
+		full := filepath.Join(templateDir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile %s: %v", path, err)
+		}
+	}
+	return templateDir
+}
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		tmpl    Template
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			files: map[string]string{".devcontainer/devcontainer.json.tmpl": `{"name": "{{.ContainerName}}"}`},
+			tmpl: Template{
+				InitialSessions: []SessionSpec{{Name: "dev"}, {Name: "logs"}},
+				NameTemplate:    "{{.ProjectBase}}",
+			},
+		},
+		{
+			name:    "unparsable tmpl file",
+			files:   map[string]string{".devcontainer/devcontainer.json.tmpl": `{"name": "{{.ContainerName"}`},
+			wantErr: ".devcontainer/devcontainer.json.tmpl:",
+		},
+		{
+			name:    "unparsable nested tmpl file",
+			files:   map[string]string{".devcontainer/containers/proxy/filter.py.tmpl": "{{if}}"},
+			wantErr: ".devcontainer/containers/proxy/filter.py.tmpl:",
+		},
+		{
+			name:    "invalid session name",
+			tmpl:    Template{InitialSessions: []SessionSpec{{Name: "has space"}}},
+			wantErr: `invalid session name "has space"`,
+		},
+		{
+			name:    "duplicate session name",
+			tmpl:    Template{InitialSessions: []SessionSpec{{Name: "dev"}, {Name: "dev"}}},
+			wantErr: `duplicate session name "dev"`,
+		},
+		{
+			name:    "missing allowlist file",
+			tmpl:    Template{AllowlistFile: "/nonexistent/allowlist.txt"},
+			wantErr: "allowlist_file:",
+		},
+		{
+			name:    "bad name template",
+			tmpl:    Template{NameTemplate: "{{.ProjectBase"},
+			wantErr: "invalid name_template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := tt.tmpl
+			tmpl.Name = "basic"
+			tmpl.Path = writeTemplateFiles(t, t.TempDir(), "basic", tt.files)
+
+			err := tmpl.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateValidate_AggregatesErrors(t *testing.T) {
+	tmpl := Template{
+		Name:            "basic",
+		Path:            writeTemplateFiles(t, t.TempDir(), "basic", map[string]string{".devcontainer/a.tmpl": "{{end}}"}),
+		InitialSessions: []SessionSpec{{Name: "bad name"}},
+		NameTemplate:    "{{",
+	}
+
+	err := tmpl.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	for _, want := range []string{".devcontainer/a.tmpl", "invalid session name", "invalid name_template"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, missing %q", err, want)
+		}
+	}
+}
+
+func TestTemplateWarningsFrom(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, "good", nil)
+	writeTemplateFiles(t, dir, "bad-sessions", map[string]string{"sessions.yaml": "sessions:\n  - name: dev\n  - name: dev\n"})
+	writeTemplateFiles(t, dir, "bad-settings", map[string]string{"template.yaml": "name_template: [\n"})
+	writeTemplateFiles(t, dir, "bad-compose", map[string]string{
+		".devcontainer/docker-compose.yml.tmpl": "{{.ContainerName",
+		".devcontainer/devcontainer.json.tmpl":  "{{end}}",
+	})
+
+	warnings := TemplateWarningsFrom(dir)
+	if len(warnings) != 4 {
+		t.Fatalf("TemplateWarningsFrom() = %q, want 4 warnings", warnings)
+	}
+	counts := map[string]int{}
+	for _, w := range warnings {
+		name, _, _ := strings.Cut(strings.TrimPrefix(w, "template "), ":")
+		counts[name]++
+	}
+	if counts["bad-sessions"] != 1 || counts["bad-settings"] != 1 || counts["bad-compose"] != 2 || counts["good"] != 0 {
+		t.Errorf("warnings per template = %v, want bad-sessions:1 bad-settings:1 bad-compose:2", counts)
+	}
+
+	// Invalid templates are reported, not fatal: the loadable ones still load.
+	templates, err := LoadTemplatesFrom(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplatesFrom() error = %v", err)
+	}
+	if len(templates) != 2 {
+		t.Errorf("loaded %d templates, want 2 (good, bad-compose)", len(templates))
+	}
+}
+
+func TestTemplateWarningsFrom_AllValid(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplateFiles(t, dir, "basic", map[string]string{"sessions.yaml": "sessions:\n  - name: dev\n"})
+
+	if warnings := TemplateWarningsFrom(dir); len(warnings) != 0 {
+		t.Errorf("TemplateWarningsFrom() = %q, want none", warnings)
+	}
+	if warnings := TemplateWarningsFrom(filepath.Join(dir, "missing")); len(warnings) != 0 {
+		t.Errorf("TemplateWarningsFrom(missing) = %q, want none", warnings)
+	}
+}
+
+func TestLoadTemplates_SharedAllowlistFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "team-allowlist.txt"), []byte("team.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go-project", "python-project"} {
+		writeTemplateFiles(t, dir, name, map[string]string{"template.yaml": "allowlist_file: team-allowlist.txt\n"})
+	}
+	writeTemplateFiles(t, dir, "absolute", map[string]string{"template.yaml": "allowlist_file: /etc/devagent/allowlist.txt\n"})
+
+	templates, err := LoadTemplatesFrom(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplatesFrom() error = %v", err)
+	}
+	want := map[string]string{
+		"go-project":     filepath.Join(dir, "team-allowlist.txt"),
+		"python-project": filepath.Join(dir, "team-allowlist.txt"),
+		"absolute":       "/etc/devagent/allowlist.txt",
+	}
+	for _, tmpl := range templates {
+		if tmpl.AllowlistFile != want[tmpl.Name] {
+			t.Errorf("%s: AllowlistFile = %q, want %q", tmpl.Name, tmpl.AllowlistFile, want[tmpl.Name])
+		}
+	}
+
+	warnings := TemplateWarningsFrom(dir)
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "template absolute: allowlist_file:") {
+		t.Errorf("TemplateWarningsFrom() = %q, want only the missing absolute file", warnings)
+	}
+}
@@ -0,0 +1,35 @@
+// pattern: Imperative Shell
+
+package container
+
+import "time"
+
+// TouchActivity records now as the container's last activity. The Manager
+// calls it for execs, session creation and keys sent to a session; callers
+// that attach to a session (the web terminal) call it themselves.
+func (m *Manager) TouchActivity(containerID string) {
+	m.activityMu.Lock()
+	m.activity[containerID] = time.Now()
+	m.activityMu.Unlock()
+}
+
+// LastActivity returns when the container was last active, and false if no
+// activity has been recorded since devagent started.
+func (m *Manager) LastActivity(containerID string) (time.Time, bool) {
+	m.activityMu.Lock()
+	defer m.activityMu.Unlock()
+	t, ok := m.activity[containerID]
+	return t, ok
+}
+
+// pruneActivity drops the activity of containers that no longer exist.
+// Caller must hold m.mu.
+func (m *Manager) pruneActivity() {
+	m.activityMu.Lock()
+	defer m.activityMu.Unlock()
+	for id := range m.activity {
+		if _, ok := m.containers[id]; !ok {
+			delete(m.activity, id)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"devagent/internal/config"
+)
+
+// UpdateAllowlist replaces the proxy allowlist of a container's project with
+// domains and, if the container is running, restarts its proxy sidecar so the
+// filter script is reloaded. Every domain must pass ValidateAllowlistDomain;
+// nothing is written otherwise.
+func (m *Manager) UpdateAllowlist(ctx context.Context, containerID string, domains []string) error {
+	c, ok := m.Get(containerID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	for _, d := range domains {
+		if err := ValidateAllowlistDomain(d); err != nil {
+			return err
+		}
+	}
+
+	scriptPath := filterScriptPath(c.ProjectPath)
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return fmt.Errorf("failed to read filter script: %w", err)
+	}
+	updated, err := replaceAllowlistInScript(string(content), domains)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(scriptPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write filter script: %w", err)
+	}
+
+	logger := m.containerLogger(c.Name)
+	logger.Info("proxy allowlist updated", "containerID", c.ID, "domains", len(domains))
+
+	if !c.IsRunning() {
+		return nil
+	}
+	if err := m.runtime.ComposeRestart(ctx, c.ProjectPath, composeProjectName(c), "proxy"); err != nil {
+		return fmt.Errorf("failed to restart proxy: %w", err)
+	}
+	return nil
+}
+
+// EffectiveAllowlist returns the extra domains written into the filter script
+// of a project built from templateName: the config allowlist
+// (network.allowlist, then network.allowlist_file) followed by the template's
+// allowlist_file, deduplicated. Files are read now; a missing or invalid file
+// is an error.
+func (m *Manager) EffectiveAllowlist(templateName string) ([]string, error) {
+	var configDomains, templateDomains []string
+	if m.cfg != nil {
+		var err error
+		if configDomains, err = m.cfg.ReadAllowlist(); err != nil {
+			return nil, err
+		}
+	}
+	if tmpl := m.template(templateName); tmpl != nil && tmpl.AllowlistFile != "" {
+		var err error
+		if templateDomains, err = config.ReadAllowlistFile(tmpl.AllowlistFile); err != nil {
+			return nil, fmt.Errorf("template %s allowlist_file: %w", tmpl.Name, err)
+		}
+	}
+	return config.MergeAllowlists(configDomains, templateDomains), nil
+}
+
+// AllowlistFiles returns the allowlist files feeding EffectiveAllowlist: the
+// config's network.allowlist_file and every template's allowlist_file.
+func (m *Manager) AllowlistFiles() []string {
+	var files []string
+	if m.cfg != nil {
+		if path := m.cfg.ResolveAllowlistFile(); path != "" {
+			files = append(files, path)
+		}
+	}
+	if m.composeGenerator != nil {
+		for _, tmpl := range m.composeGenerator.Templates() {
+			if tmpl.AllowlistFile != "" {
+				files = append(files, tmpl.AllowlistFile)
+			}
+		}
+	}
+	return config.MergeAllowlists(files)
+}
+
+// template returns the named template, or nil if unknown.
+func (m *Manager) template(name string) *config.Template {
+	if m.composeGenerator == nil || name == "" {
+		return nil
+	}
+	return m.composeGenerator.GetTemplate(name)
+}
+
+// ReloadAllowlist rewrites the config allowlist block of a project's filter
+// script from the project's EffectiveAllowlist, then restarts the proxy
+// sidecar of every running container of the project so the proxy picks up the
+// change without being recreated. Projects without a filter script have no
+// proxy allowlist and are rejected.
+func (m *Manager) ReloadAllowlist(ctx context.Context, projectPath string) error {
+	running, templateName := m.projectContainers(projectPath)
+	written, _, err := m.writeConfigAllowlist(projectPath, templateName)
+	if err != nil {
+		return err
+	}
+	if !written {
+		return fmt.Errorf("no proxy filter script for project %s", projectPath)
+	}
+	return m.restartProxies(ctx, running)
+}
+
+// ReloadChangedAllowlists rewrites the config allowlist block of every project
+// with a running container and restarts the proxies of the projects whose
+// block changed. Used when an allowlist file is edited; failures are joined
+// after attempting every project.
+func (m *Manager) ReloadChangedAllowlists(ctx context.Context) error {
+	m.mu.RLock()
+	projects := make(map[string]bool)
+	for _, c := range m.containers {
+		if c.IsRunning() && c.ProjectPath != "" {
+			projects[c.ProjectPath] = true
+		}
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for projectPath := range projects {
+		running, templateName := m.projectContainers(projectPath)
+		_, changed, err := m.writeConfigAllowlist(projectPath, templateName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", projectPath, err))
+			continue
+		}
+		if changed {
+			if err := m.restartProxies(ctx, running); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// projectContainers returns the running containers of a project and the
+// template the project was created from (from any of its containers).
+func (m *Manager) projectContainers(projectPath string) ([]*Container, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var running []*Container
+	templateName := ""
+	for _, c := range m.containers {
+		if c.ProjectPath != projectPath {
+			continue
+		}
+		if templateName == "" {
+			templateName = c.Template
+		}
+		if c.IsRunning() {
+			running = append(running, c)
+		}
+	}
+	return running, templateName
+}
+
+// restartProxies restarts the proxy sidecar of each container so a rewritten
+// filter script takes effect.
+func (m *Manager) restartProxies(ctx context.Context, containers []*Container) error {
+	var errs []error
+	for _, c := range containers {
+		if err := m.runtime.ComposeRestart(ctx, c.ProjectPath, composeProjectName(c), "proxy"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to restart proxy: %w", c.Name, err))
+			continue
+		}
+		m.containerLogger(c.Name).Info("proxy allowlist reloaded", "containerID", c.ID)
+	}
+	return errors.Join(errs...)
+}
+
+// writeConfigAllowlist writes the effective allowlist into the project's
+// filter script. Reports written=false, without error, when the project has no
+// filter script, and changed=true when the file content changed.
+func (m *Manager) writeConfigAllowlist(projectPath, templateName string) (written, changed bool, err error) {
+	domains, err := m.EffectiveAllowlist(templateName)
+	if err != nil {
+		return false, false, err
+	}
+
+	scriptPath := filterScriptPath(projectPath)
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to read filter script: %w", err)
+	}
+	updated, err := replaceConfigAllowlistInScript(string(content), domains)
+	if err != nil {
+		return false, false, err
+	}
+	if updated == string(content) {
+		return true, false, nil
+	}
+	if err := os.WriteFile(scriptPath, []byte(updated), 0644); err != nil {
+		return false, false, fmt.Errorf("failed to write filter script: %w", err)
+	}
+	return true, true, nil
+}
@@ -0,0 +1,242 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"devagent/internal/config"
+)
+
+// allowlistTestManager returns a manager with a running app container (and its
+// proxy) whose project has a filter script allowing github.com.
+func allowlistTestManager(t *testing.T) (*Manager, *mockRuntime, string) {
+	t.Helper()
+	projectPath := t.TempDir()
+	scriptPath := filterScriptPath(projectPath)
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(scriptPath, []byte("ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	containers := sidecarReconcileContainers(StateRunning)
+	containers[0].ProjectPath = projectPath
+	mock := &mockRuntime{containers: containers}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	return mgr, mock, projectPath
+}
+
+func TestUpdateAllowlist_WritesScriptAndRestartsProxy(t *testing.T) {
+	mgr, mock, projectPath := allowlistTestManager(t)
+
+	if err := mgr.UpdateAllowlist(context.Background(), "app-1", []string{"*.example.com"}); err != nil {
+		t.Fatalf("UpdateAllowlist() error = %v", err)
+	}
+
+	domains, err := ReadAllowlistFromFilterScript(projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(domains, []string{"*.example.com"}) {
+		t.Errorf("allowlist = %v, want [*.example.com]", domains)
+	}
+	if mock.composeRestartProject != "alpha" || !slices.Equal(mock.composeRestartServices, []string{"proxy"}) {
+		t.Errorf("ComposeRestart(%q, %v), want (alpha, [proxy])", mock.composeRestartProject, mock.composeRestartServices)
+	}
+}
+
+func TestUpdateAllowlist_RejectsInvalidDomain(t *testing.T) {
+	mgr, mock, projectPath := allowlistTestManager(t)
+
+	if err := mgr.UpdateAllowlist(context.Background(), "app-1", []string{"ok.example.com", "https://bad"}); err == nil {
+		t.Fatal("expected error for invalid domain")
+	}
+
+	domains, _ := ReadAllowlistFromFilterScript(projectPath)
+	if !slices.Equal(domains, []string{"github.com"}) {
+		t.Errorf("allowlist = %v, want it unchanged", domains)
+	}
+	if mock.composeRestartProject != "" {
+		t.Error("proxy should not be restarted")
+	}
+}
+
+func TestUpdateAllowlist_UnknownContainer(t *testing.T) {
+	mgr, _, _ := allowlistTestManager(t)
+	if err := mgr.UpdateAllowlist(context.Background(), "missing", nil); err == nil {
+		t.Error("expected error for unknown container")
+	}
+}
+
+func TestReloadAllowlist_MergesSourcesAndRestartsProxy(t *testing.T) {
+	mgr, mock, projectPath := allowlistTestManager(t)
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist.txt")
+	if err := os.WriteFile(allowlistFile, []byte("# shared\nfile.example.com\ninline.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mgr.cfg.Network = config.NetworkConfig{Allowlist: []string{"inline.example.com"}, AllowlistFile: allowlistFile}
+
+	if err := mgr.ReloadAllowlist(context.Background(), projectPath); err != nil {
+		t.Fatalf("ReloadAllowlist() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filterScriptPath(projectPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := "ALLOWED_DOMAINS.extend([\n    \"inline.example.com\",\n    \"file.example.com\",\n])"
+	if !strings.Contains(string(content), block) {
+		t.Errorf("filter script =\n%s\nwant config block with inline then file domains", content)
+	}
+	if domains, _ := ReadAllowlistFromFilterScript(projectPath); !slices.Equal(domains, []string{"github.com"}) {
+		t.Errorf("template allowlist = %v, want it unchanged", domains)
+	}
+	if mock.composeRestartProject != "alpha" || !slices.Equal(mock.composeRestartServices, []string{"proxy"}) {
+		t.Errorf("ComposeRestart(%q, %v), want (alpha, [proxy])", mock.composeRestartProject, mock.composeRestartServices)
+	}
+
+	// Editing the file and reloading again picks up the change.
+	if err := os.WriteFile(allowlistFile, []byte("other.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ReloadAllowlist(context.Background(), projectPath); err != nil {
+		t.Fatalf("ReloadAllowlist() error = %v", err)
+	}
+	content, _ = os.ReadFile(filterScriptPath(projectPath))
+	if strings.Contains(string(content), "file.example.com") || !strings.Contains(string(content), "other.example.com") {
+		t.Errorf("filter script after file edit =\n%s", content)
+	}
+}
+
+func TestReloadAllowlist_Errors(t *testing.T) {
+	mgr, mock, projectPath := allowlistTestManager(t)
+
+	mgr.cfg.Network = config.NetworkConfig{AllowlistFile: filepath.Join(t.TempDir(), "missing.txt")}
+	if err := mgr.ReloadAllowlist(context.Background(), projectPath); err == nil {
+		t.Error("expected error for a missing allowlist file")
+	}
+
+	mgr.cfg.Network = config.NetworkConfig{}
+	if err := mgr.ReloadAllowlist(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for a project without a filter script")
+	}
+	if mock.composeRestartProject != "" {
+		t.Error("proxy should not be restarted on failure")
+	}
+}
+
+// withSharedTemplateAllowlist gives the manager a "shared" template whose
+// allowlist_file holds content, and marks app-1 as built from it. Returns the
+// file path.
+func withSharedTemplateAllowlist(t *testing.T, mgr *Manager, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shared-allowlist.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mgr.composeGenerator = NewComposeGenerator(mgr.cfg, []config.Template{{Name: "shared", AllowlistFile: path}}, nil)
+	mgr.containers["app-1"].Template = "shared"
+	return path
+}
+
+func TestEffectiveAllowlist_IncludesTemplateFile(t *testing.T) {
+	mgr, _, _ := allowlistTestManager(t)
+	path := withSharedTemplateAllowlist(t, mgr, "team.example.com\ninline.example.com\n")
+	mgr.cfg.Network = config.NetworkConfig{Allowlist: []string{"inline.example.com"}}
+
+	got, err := mgr.EffectiveAllowlist("shared")
+	if err != nil {
+		t.Fatalf("EffectiveAllowlist() error = %v", err)
+	}
+	if want := []string{"inline.example.com", "team.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("EffectiveAllowlist() = %v, want %v (config first, then template file)", got, want)
+	}
+	if got, _ := mgr.EffectiveAllowlist("other"); !slices.Equal(got, []string{"inline.example.com"}) {
+		t.Errorf("EffectiveAllowlist(other) = %v, want only the config allowlist", got)
+	}
+	if files := mgr.AllowlistFiles(); !slices.Equal(files, []string{path}) {
+		t.Errorf("AllowlistFiles() = %v, want [%s]", files, path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.EffectiveAllowlist("shared"); err == nil {
+		t.Error("EffectiveAllowlist() should fail when the template's allowlist file is missing")
+	}
+}
+
+func TestReloadChangedAllowlists_RestartsOnlyChangedProjects(t *testing.T) {
+	mgr, mock, projectPath := allowlistTestManager(t)
+	path := withSharedTemplateAllowlist(t, mgr, "team.example.com\n")
+
+	if err := mgr.ReloadChangedAllowlists(context.Background()); err != nil {
+		t.Fatalf("ReloadChangedAllowlists() error = %v", err)
+	}
+	content, _ := os.ReadFile(filterScriptPath(projectPath))
+	if !strings.Contains(string(content), `"team.example.com"`) {
+		t.Errorf("filter script =\n%s\nwant the template file's domain", content)
+	}
+	if mock.composeRestartProject != "alpha" {
+		t.Errorf("ComposeRestart project = %q, want alpha", mock.composeRestartProject)
+	}
+
+	// Nothing changed: the proxy is left alone.
+	mock.composeRestartProject = ""
+	if err := mgr.ReloadChangedAllowlists(context.Background()); err != nil {
+		t.Fatalf("ReloadChangedAllowlists() error = %v", err)
+	}
+	if mock.composeRestartProject != "" {
+		t.Error("proxy restarted although the allowlist did not change")
+	}
+
+	if err := os.WriteFile(path, []byte("team.example.com\nmore.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.ReloadChangedAllowlists(context.Background()); err != nil {
+		t.Fatalf("ReloadChangedAllowlists() error = %v", err)
+	}
+	if mock.composeRestartProject != "alpha" {
+		t.Error("proxy should be restarted after the allowlist file changed")
+	}
+}
+
+func TestWatchAllowlistFiles_ReloadsOnEdit(t *testing.T) {
+	mgr, _, projectPath := allowlistTestManager(t)
+	path := withSharedTemplateAllowlist(t, mgr, "team.example.com\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- mgr.WatchAllowlistFiles(ctx, 10*time.Millisecond) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("WatchAllowlistFiles() error = %v", err)
+		}
+	}()
+
+	// Give the watcher time to register before editing.
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("edited.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		content, _ := os.ReadFile(filterScriptPath(projectPath))
+		if strings.Contains(string(content), `"edited.example.com"`) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Error("filter script was not updated after editing the allowlist file")
+}
@@ -0,0 +1,85 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultAllowlistWatchDebounce is how long WatchAllowlistFiles waits for
+// edits to an allowlist file to settle before reloading.
+const DefaultAllowlistWatchDebounce = 500 * time.Millisecond
+
+// WatchAllowlistFiles watches the files returned by AllowlistFiles and, once
+// edits have been quiet for debounce, calls ReloadChangedAllowlists so running
+// proxies pick up the change. Parent directories are watched, since editors
+// often replace a file rather than write it in place. The file set is
+// re-read after every reload, following config reloads that change templates.
+// Blocks until ctx is cancelled (returning nil) or watching fails.
+func (m *Manager) WatchAllowlistFiles(ctx context.Context, debounce time.Duration) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer func() { _ = fsw.Close() }()
+
+	files := make(map[string]bool)
+	dirs := make(map[string]bool)
+	watchFiles := func() {
+		files = make(map[string]bool)
+		for _, f := range m.AllowlistFiles() {
+			files[filepath.Clean(f)] = true
+			dir := filepath.Dir(f)
+			if dirs[dir] {
+				continue
+			}
+			if err := fsw.Add(dir); err != nil {
+				m.logger.Warn("cannot watch allowlist directory", "dir", dir, "error", err)
+				continue
+			}
+			dirs[dir] = true
+		}
+	}
+	watchFiles()
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			if files[filepath.Clean(event.Name)] {
+				timer.Reset(debounce)
+				fire = timer.C
+			}
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			m.logger.Warn("allowlist watcher error", "error", err)
+
+		case <-fire:
+			fire = nil
+			m.logger.Info("allowlist file changed, reloading proxy allowlists")
+			if err := m.ReloadChangedAllowlists(ctx); err != nil {
+				m.logger.Error("failed to reload proxy allowlists", "error", err)
+			}
+			watchFiles()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrCloneExists is returned by CloneAndCreate when the clone destination
+// already exists.
+var ErrCloneExists error = &kindError{msg: "clone destination already exists", kind: ErrAlreadyExists}
+
+// scpLikeGitURL matches git's scp-like syntax, e.g. git@github.com:org/repo.git.
+var scpLikeGitURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/].*$`)
+
+// cloneDirPattern restricts the directory name derived from a git URL.
+var cloneDirPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateGitURL checks that gitURL is an https, http, ssh or git URL, or
+// scp-like (user@host:path), and cannot be mistaken for a git option.
+func ValidateGitURL(gitURL string) error {
+	if gitURL == "" {
+		return errors.New("git URL is required")
+	}
+	if strings.HasPrefix(gitURL, "-") || strings.ContainsFunc(gitURL, func(r rune) bool { return r <= ' ' || r == 0x7f }) {
+		return fmt.Errorf("invalid git URL %q", gitURL)
+	}
+	for _, scheme := range []string{"https://", "http://", "ssh://", "git://"} {
+		if rest, ok := strings.CutPrefix(gitURL, scheme); ok {
+			if host, _, _ := strings.Cut(rest, "/"); host == "" || !strings.Contains(rest, "/") {
+				return fmt.Errorf("invalid git URL %q: missing host or repository path", gitURL)
+			}
+			return nil
+		}
+	}
+	if scpLikeGitURL.MatchString(gitURL) {
+		return nil
+	}
+	return fmt.Errorf("unsupported git URL %q (want https://, ssh://, git:// or user@host:path)", gitURL)
+}
+
+// CloneDirName derives the project directory name from a git URL: its last
+// path element without a ".git" suffix (".../org/repo.git" -> "repo"). The
+// name is a single path element, so it cannot escape the clone root.
+func CloneDirName(gitURL string) (string, error) {
+	if err := ValidateGitURL(gitURL); err != nil {
+		return "", err
+	}
+	p := gitURL
+	if i := strings.Index(p, "://"); i >= 0 {
+		p = p[i+3:]
+	} else if _, path, ok := strings.Cut(p, ":"); ok {
+		p = path // scp-like
+	}
+	p, _, _ = strings.Cut(p, "?")
+	p = strings.TrimRight(p, "/")
+	name := strings.TrimSuffix(p[strings.LastIndex(p, "/")+1:], ".git")
+	if !cloneDirPattern.MatchString(name) {
+		return "", fmt.Errorf("cannot derive a project directory from git URL %q", gitURL)
+	}
+	return name, nil
+}
+
+// CloneDestination returns where CloneAndCreate clones gitURL: the derived
+// directory under the configured clone root (see config.ResolveCloneRoot).
+func (m *Manager) CloneDestination(gitURL string) (string, error) {
+	name, err := CloneDirName(gitURL)
+	if err != nil {
+		return "", err
+	}
+	root := ""
+	if m.cfg != nil {
+		root = m.cfg.ResolveCloneRoot()
+	}
+	if root == "" {
+		return "", errors.New("no clone root: set clone_root or scan_paths in config.yaml")
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dest := filepath.Join(root, name)
+	if rel, err := filepath.Rel(root, dest); err != nil || rel != name {
+		return "", fmt.Errorf("clone destination %s escapes the clone root %s", dest, root)
+	}
+	return dest, nil
+}
+
+// cloneEnv keeps git clone from prompting for credentials or an unknown
+// host key on the terminal devagent runs in (it would hang the create): git
+// and ssh fail instead. A GIT_SSH_COMMAND from the environment is kept, with
+// BatchMode added.
+func cloneEnv() map[string]string {
+	sshCommand := os.Getenv("GIT_SSH_COMMAND")
+	if sshCommand == "" {
+		sshCommand = "ssh"
+	}
+	return map[string]string{
+		"GIT_TERMINAL_PROMPT": "0",
+		"GIT_SSH_COMMAND":     sshCommand + " -o BatchMode=yes",
+	}
+}
+
+// CloneAndCreate clones gitURL into CloneDestination, reporting git's output
+// as "clone" progress steps, then creates a container for the clone via
+// CreateWithCompose (opts.ProjectPath is replaced by the clone's path). An
+// existing destination is refused; a failed clone removes the directory this
+// call created. git never prompts for credentials (see cloneEnv). If the
+// create fails the clone is kept, so it can be retried as a normal create.
+func (m *Manager) CloneAndCreate(ctx context.Context, gitURL string, opts CreateOptions) (*Container, error) {
+	dest, err := m.CloneDestination(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseMounts(opts.ExtraMounts); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create clone root: %w", err)
+	}
+	// Creating dest claims it: a concurrent clone of the same URL gets
+	// ErrCloneExists here, so the cleanup below only removes our own clone.
+	if err := os.Mkdir(dest, 0755); errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("%w: %s", ErrCloneExists, dest)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to create clone destination: %w", err)
+	}
+
+	logName := opts.Name
+	if logName == "" {
+		logName = filepath.Base(dest)
+	}
+	logger := m.containerLogger(logName)
+	reportProgress := func(status, msg string) {
+		m.reportProgress(logger, opts.OnProgress, "clone", status, msg)
+	}
+
+	reportProgress("started", "Cloning "+gitURL)
+	err = m.gitExec(ctx, cloneEnv(), func(line string) {
+		// git redraws progress with carriage returns; keep the latest state
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			reportProgress("started", line)
+		}
+	}, "git", "clone", "--progress", "--", gitURL, dest)
+	if err != nil {
+		if rmErr := os.RemoveAll(dest); rmErr != nil {
+			logger.Warn("failed to remove partial clone", "path", dest, "error", rmErr)
+		}
+		reportProgress("failed", "Clone failed: "+err.Error())
+		return nil, fmt.Errorf("git clone: %w", err)
+	}
+	reportProgress("completed", "Cloned into "+dest)
+
+	opts.ProjectPath = dest
+	c, err := m.CreateWithCompose(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("cloned into %s but failed to create container: %w", dest, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,206 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCloneDirName(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://github.com/org/repo.git", want: "repo"},
+		{url: "https://github.com/org/repo", want: "repo"},
+		{url: "https://github.com/org/repo/", want: "repo"},
+		{url: "ssh://git@host:2222/org/my.repo.git", want: "my.repo"},
+		{url: "git@github.com:org/repo.git", want: "repo"},
+		{url: "git@github.com:repo.git", want: "repo"},
+		{url: "git://host/repo.git?ref=main", want: "repo"},
+		{url: "", wantErr: true},
+		{url: "--upload-pack=touch /tmp/x", wantErr: true},
+		{url: "https://github.com/org/repo name", wantErr: true},
+		{url: "file:///etc/passwd", wantErr: true},
+		{url: "/local/path/repo", wantErr: true},
+		{url: "https://github.com", wantErr: true},
+		{url: "https://github.com/org/..", wantErr: true},
+		{url: "https://github.com/org/.git", wantErr: true},
+		{url: "git@github.com:/abs/repo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := CloneDirName(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CloneDirName(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CloneDirName(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeGitClone returns a git executor that records its args, reports a few
+// progress lines and creates the destination (the last arg) before failing
+// with err, if any.
+func fakeGitClone(calls *[][]string, err error) StreamExecutor {
+	return func(ctx context.Context, env map[string]string, onLine func(string), name string, args ...string) error {
+		*calls = append(*calls, append([]string{name}, args...))
+		dest := args[len(args)-1]
+		if mkErr := os.MkdirAll(filepath.Join(dest, ".git"), 0755); mkErr != nil {
+			return mkErr
+		}
+		onLine("Cloning into '" + dest + "'...")
+		onLine("Receiving objects:  50% (1/2)\rReceiving objects: 100% (2/2), done.")
+		return err
+	}
+}
+
+func TestCloneAndCreate(t *testing.T) {
+	mgr, mock, _ := setupCreateWithComposeTest(t)
+	root := filepath.Join(t.TempDir(), "clones")
+	mgr.cfg.CloneRoot = root
+	var calls [][]string
+	mgr.gitExec = fakeGitClone(&calls, nil)
+
+	dest := filepath.Join(root, "repo")
+	mock.containers = append(mock.containers, Container{ID: "cloned-id", Name: "repo", ProjectPath: dest, State: StateRunning})
+
+	var steps []ProgressStep
+	c, err := mgr.CloneAndCreate(context.Background(), "https://github.com/org/repo.git", CreateOptions{
+		Template:   "default",
+		Name:       "repo",
+		OnProgress: func(s ProgressStep) { steps = append(steps, s) },
+	})
+	if err != nil {
+		t.Fatalf("CloneAndCreate() error = %v", err)
+	}
+	if c.ID != "cloned-id" {
+		t.Errorf("container = %q, want cloned-id", c.ID)
+	}
+
+	wantArgs := []string{"git", "clone", "--progress", "--", "https://github.com/org/repo.git", dest}
+	if len(calls) != 1 || !slices.Equal(calls[0], wantArgs) {
+		t.Errorf("git calls = %q, want [%q]", calls, wantArgs)
+	}
+	if mock.composeUpCalled != dest {
+		t.Errorf("compose up ran in %q, want the clone %q", mock.composeUpCalled, dest)
+	}
+
+	var clone []string
+	for _, s := range steps {
+		if s.Step == "clone" {
+			clone = append(clone, s.Status+": "+s.Message)
+		}
+	}
+	want := []string{
+		"started: Cloning https://github.com/org/repo.git",
+		"started: Cloning into '" + dest + "'...",
+		"started: Receiving objects: 100% (2/2), done.",
+		"completed: Cloned into " + dest,
+	}
+	if !slices.Equal(clone, want) {
+		t.Errorf("clone steps = %q, want %q", clone, want)
+	}
+}
+
+func TestCloneAndCreate_RemovesFailedClone(t *testing.T) {
+	mgr, mock, _ := setupCreateWithComposeTest(t)
+	root := t.TempDir()
+	mgr.cfg.CloneRoot = root
+	var calls [][]string
+	mgr.gitExec = fakeGitClone(&calls, errors.New("exit status 128: repository not found"))
+
+	_, err := mgr.CloneAndCreate(context.Background(), "git@github.com:org/missing.git", CreateOptions{Template: "default"})
+	if err == nil || !strings.Contains(err.Error(), "repository not found") {
+		t.Fatalf("CloneAndCreate() error = %v, want the git failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "missing")); !os.IsNotExist(err) {
+		t.Errorf("partial clone not removed: stat error = %v", err)
+	}
+	if mock.composeUpCalled != "" {
+		t.Errorf("compose up ran after a failed clone (in %q)", mock.composeUpCalled)
+	}
+}
+
+func TestCloneAndCreate_RefusesExistingOrUnconfigured(t *testing.T) {
+	mgr, _, _ := setupCreateWithComposeTest(t)
+	var calls [][]string
+	mgr.gitExec = fakeGitClone(&calls, nil)
+
+	if _, err := mgr.CloneAndCreate(context.Background(), "https://github.com/org/repo.git", CreateOptions{}); err == nil {
+		t.Error("CloneAndCreate() should fail without clone_root or scan_paths")
+	}
+
+	root := t.TempDir()
+	mgr.cfg.CloneRoot = root
+	existing := filepath.Join(root, "repo")
+	if err := os.Mkdir(existing, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(existing, "keep"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.CloneAndCreate(context.Background(), "https://github.com/org/repo.git", CreateOptions{}); err == nil {
+		t.Error("CloneAndCreate() should refuse an existing destination")
+	}
+	if _, err := os.Stat(filepath.Join(existing, "keep")); err != nil {
+		t.Errorf("existing destination was touched: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("git ran %d times, want 0", len(calls))
+	}
+}
+
+func TestCloneAndCreate_NeverPrompts(t *testing.T) {
+	mgr, _, _ := setupCreateWithComposeTest(t)
+	mgr.cfg.CloneRoot = t.TempDir()
+	t.Setenv("GIT_SSH_COMMAND", "ssh -i ~/.ssh/deploy")
+	var env map[string]string
+	mgr.gitExec = func(ctx context.Context, e map[string]string, onLine func(string), name string, args ...string) error {
+		env = e
+		return errors.New("exit status 128")
+	}
+
+	_, _ = mgr.CloneAndCreate(context.Background(), "git@github.com:org/private.git", CreateOptions{Template: "default"})
+	if env["GIT_TERMINAL_PROMPT"] != "0" {
+		t.Errorf("GIT_TERMINAL_PROMPT = %q, want 0", env["GIT_TERMINAL_PROMPT"])
+	}
+	if want := "ssh -i ~/.ssh/deploy -o BatchMode=yes"; env["GIT_SSH_COMMAND"] != want {
+		t.Errorf("GIT_SSH_COMMAND = %q, want %q", env["GIT_SSH_COMMAND"], want)
+	}
+}
+
+func TestCloneAndCreate_ConcurrentCloneKeepsDestination(t *testing.T) {
+	mgr, mock, _ := setupCreateWithComposeTest(t)
+	root := t.TempDir()
+	mgr.cfg.CloneRoot = root
+	dest := filepath.Join(root, "repo")
+	mock.containers = append(mock.containers, Container{ID: "cloned-id", Name: "repo", ProjectPath: dest, State: StateRunning})
+
+	// A second clone of the same URL that starts before the first one's git
+	// has written anything must be refused, not clone (and on failure remove)
+	// the same directory.
+	var secondErr error
+	mgr.gitExec = func(ctx context.Context, _ map[string]string, _ func(string), _ string, args ...string) error {
+		mgr.gitExec = fakeGitClone(new([][]string), errors.New("exit status 128"))
+		_, secondErr = mgr.CloneAndCreate(ctx, "https://github.com/org/repo.git", CreateOptions{Template: "default"})
+		return os.MkdirAll(filepath.Join(dest, ".git"), 0755)
+	}
+
+	if _, err := mgr.CloneAndCreate(context.Background(), "https://github.com/org/repo.git", CreateOptions{Template: "default", Name: "repo"}); err != nil {
+		t.Fatalf("first CloneAndCreate() error = %v", err)
+	}
+	if !errors.Is(secondErr, ErrCloneExists) {
+		t.Errorf("second CloneAndCreate() error = %v, want ErrCloneExists", secondErr)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err != nil {
+		t.Errorf("first clone's directory is gone: %v", err)
+	}
+}
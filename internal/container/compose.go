@@ -4,10 +4,13 @@ package container
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"text/template"
 
 	"devagent/internal/config"
@@ -32,11 +35,17 @@ type TemplateData struct {
 	ProxyImage      string // Docker image for mitmproxy sidecar (default: mitmproxy/mitmproxy:latest)
 	RemoteUser      string // User for devcontainer exec commands (default: vscode)
 	ProxyLogPath    string // Container path for proxy request logs (default: /opt/devagent-proxy/logs/requests.jsonl)
+
+	// Comma-separated domains from network.registries, passed to the proxy
+	// sidecar's environment and merged into filter.py's lists at load time.
+	ExtraAllowedDomains     string // Appended to ALLOWED_DOMAINS
+	ExtraPassthroughDomains string // Appended to PASSTHROUGH_DOMAINS (mitmproxy ignore_hosts)
 }
 
 // ComposeGenerator creates docker-compose.yml and related files for container orchestration.
 type ComposeGenerator struct {
 	cfg       *config.Config
+	mu        sync.RWMutex // protects templates (replaced on config reload)
 	templates []config.Template
 	logger    *logging.ScopedLogger
 }
@@ -53,6 +62,8 @@ func NewComposeGenerator(cfg *config.Config, templates []config.Template, logger
 // GetTemplate retrieves a template by name.
 // Returns nil if template not found.
 func (g *ComposeGenerator) GetTemplate(templateName string) *config.Template {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 	for i := range g.templates {
 		if g.templates[i].Name == templateName {
 			return &g.templates[i]
@@ -61,6 +72,21 @@ func (g *ComposeGenerator) GetTemplate(templateName string) *config.Template {
 	return nil
 }
 
+// Templates returns a copy of the generator's templates.
+func (g *ComposeGenerator) Templates() []config.Template {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return slices.Clone(g.templates)
+}
+
+// SetTemplates replaces the generator's templates. Used when the config is
+// reloaded at runtime; in-flight generations keep the template they resolved.
+func (g *ComposeGenerator) SetTemplates(templates []config.Template) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.templates = templates
+}
+
 // ComposeOptions holds options for generating compose files.
 // This is a subset of CreateOptions needed for compose generation.
 type ComposeOptions struct {
@@ -72,16 +98,26 @@ type ComposeOptions struct {
 // Generate creates docker-compose.yml content.
 // Returns ComposeResult with template data for file writing.
 func (g *ComposeGenerator) Generate(opts ComposeOptions) (*ComposeResult, error) {
+	return g.generate(opts, true)
+}
+
+// Preview is Generate without side effects: a missing Claude token is not
+// provisioned via `claude setup-token` (its path falls back to /dev/null).
+func (g *ComposeGenerator) Preview(opts ComposeOptions) (*ComposeResult, error) {
+	return g.generate(opts, false)
+}
+
+func (g *ComposeGenerator) generate(opts ComposeOptions, provisionToken bool) (*ComposeResult, error) {
 	// Find template
 	tmpl := g.GetTemplate(opts.Template)
 	if tmpl == nil {
-		return nil, fmt.Errorf("template not found: %s", opts.Template)
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, opts.Template)
 	}
 
 	// Build and validate template data
-	data := g.buildTemplateData(opts, tmpl)
+	data := g.buildTemplateData(opts, tmpl, provisionToken)
 	if err := validateTemplateData(data); err != nil {
-		return nil, fmt.Errorf("invalid template data: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplateData, err)
 	}
 	return &ComposeResult{
 		TemplateData: data,
@@ -89,12 +125,19 @@ func (g *ComposeGenerator) Generate(opts ComposeOptions) (*ComposeResult, error)
 }
 
 // buildTemplateData constructs TemplateData from options and template.
-func (g *ComposeGenerator) buildTemplateData(opts ComposeOptions, tmpl *config.Template) TemplateData {
+// provisionToken allows creating a missing Claude token.
+func (g *ComposeGenerator) buildTemplateData(opts ComposeOptions, tmpl *config.Template, provisionToken bool) TemplateData {
 	projectName := filepath.Base(opts.ProjectPath)
 
 	// Resolve and ensure Claude token (non-blocking on error).
 	// Falls back to /dev/null so Docker doesn't create an empty directory.
-	tokenPath, _ := ensureClaudeToken(g.cfg.ResolveTokenPath(g.cfg.ClaudeTokenPath))
+	claudeTokenPath := g.cfg.ResolveTokenPath(g.cfg.ClaudeTokenPath)
+	var tokenPath string
+	if provisionToken {
+		tokenPath, _ = ensureClaudeToken(claudeTokenPath)
+	} else if _, err := os.Stat(claudeTokenPath); claudeTokenPath != "" && err == nil {
+		tokenPath = claudeTokenPath
+	}
 	if tokenPath == "" {
 		tokenPath = "/dev/null"
 	}
@@ -109,6 +152,8 @@ func (g *ComposeGenerator) buildTemplateData(opts ComposeOptions, tmpl *config.T
 		ghTokenPath = "/dev/null"
 	}
 
+	registryDomains := strings.Join(g.cfg.Network.RegistryDomains(), ",")
+
 	return TemplateData{
 		ProjectPath:     opts.ProjectPath,
 		ProjectName:     projectName,
@@ -120,6 +165,9 @@ func (g *ComposeGenerator) buildTemplateData(opts ComposeOptions, tmpl *config.T
 		ProxyImage:      "mitmproxy/mitmproxy:latest",
 		RemoteUser:      DefaultRemoteUser,
 		ProxyLogPath:    "/opt/devagent-proxy/logs/requests.jsonl",
+
+		ExtraAllowedDomains:     registryDomains,
+		ExtraPassthroughDomains: registryDomains,
 	}
 }
 
@@ -149,20 +197,50 @@ func validateTemplateData(data TemplateData) error {
 
 // WriteToProject writes template files from the template's .devcontainer directory
 // to the project's .devcontainer directory, processing .tmpl files with the given data.
+// For a template that extends others, each base's files are written first and
+// the template's own replace them; devcontainer.json is merged across the layers.
 func (g *ComposeGenerator) WriteToProject(projectPath string, templateName string, data TemplateData) error {
+	return g.WriteToProjectExcept(projectPath, templateName, data)
+}
+
+// WriteToProjectExcept is WriteToProject, except that files named in keep
+// (relative to .devcontainer, e.g. "devcontainer.json") that the project
+// already has are left untouched.
+func (g *ComposeGenerator) WriteToProjectExcept(projectPath string, templateName string, data TemplateData, keep ...string) error {
 	tmpl := g.GetTemplate(templateName)
 	if tmpl == nil {
-		return fmt.Errorf("template not found: %s", templateName)
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, templateName)
 	}
 
-	src := filepath.Join(tmpl.Path, ".devcontainer")
 	dst := filepath.Join(projectPath, ".devcontainer")
 
 	if err := os.MkdirAll(dst, 0755); err != nil {
 		return err
 	}
 
-	return copyTemplateDir(src, dst, data)
+	dirs := tmpl.DevcontainerDirs()
+	for _, src := range dirs {
+		if err := copyTemplateDir(src, dst, data, keep...); err != nil {
+			if errors.Is(err, os.ErrNotExist) && src != dirs[0] {
+				continue // a child may take every file from its bases
+			}
+			return err
+		}
+	}
+	if len(dirs) == 1 {
+		return nil
+	}
+	if slices.Contains(keep, devcontainerJSON) {
+		if _, err := os.Stat(filepath.Join(dst, devcontainerJSON)); err == nil {
+			return nil
+		}
+	}
+
+	content, ok, err := mergeDevcontainerJSON(dirs, data)
+	if err != nil || !ok {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dst, devcontainerJSON), []byte(content), 0644)
 }
 
 // processTemplate reads a template file and processes it with the given data.
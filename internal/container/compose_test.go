@@ -245,6 +245,32 @@ func TestComposeGenerator_Generate_ProxyEnvironment(t *testing.T) {
 	}
 }
 
+func TestComposeGenerator_Generate_RegistryDomains(t *testing.T) {
+	templateDir := createTestTemplateDir(t, "basic")
+
+	templates := []config.Template{
+		{Name: "basic", Path: templateDir},
+	}
+
+	cfg := &config.Config{
+		Network: config.NetworkConfig{Registries: []string{"registry.example.com:5000"}},
+	}
+	gen := NewComposeGenerator(cfg, templates, logging.NopLogger())
+
+	result, err := gen.Generate(ComposeOptions{ProjectPath: "/test", Template: "basic"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	want := "registry.example.com,*.registry.example.com"
+	if result.TemplateData.ExtraAllowedDomains != want {
+		t.Errorf("ExtraAllowedDomains = %q, want %q", result.TemplateData.ExtraAllowedDomains, want)
+	}
+	if result.TemplateData.ExtraPassthroughDomains != want {
+		t.Errorf("ExtraPassthroughDomains = %q, want %q", result.TemplateData.ExtraPassthroughDomains, want)
+	}
+}
+
 func TestComposeGenerator_Generate_Labels(t *testing.T) {
 	templateDir := createTestTemplateDir(t, "go-project")
 
@@ -609,6 +635,56 @@ func TestComposeGenerator_WriteToProject_CopiesNonTemplateFiles(t *testing.T) {
 	}
 }
 
+// TestComposeGenerator_WriteToProjectExcept_KeepsExistingFile tests that
+// WriteToProjectExcept leaves a kept file that already exists untouched, and
+// still writes a kept file that is missing.
+func TestComposeGenerator_WriteToProjectExcept_KeepsExistingFile(t *testing.T) {
+	templateDir := filepath.Join(t.TempDir(), "template")
+	devcontainerDir := filepath.Join(templateDir, ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatalf("Failed to create template directory: %v", err)
+	}
+	for name, content := range map[string]string{
+		"devcontainer.json.tmpl":  `{"name": "{{.ProjectName}}"}`,
+		"docker-compose.yml.tmpl": "services: {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(devcontainerDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	gen := NewComposeGenerator(&config.Config{}, []config.Template{{Name: "basic", Path: templateDir}}, logging.NopLogger())
+
+	projectDir := t.TempDir()
+	devDir := filepath.Join(projectDir, ".devcontainer")
+	if err := os.MkdirAll(devDir, 0755); err != nil {
+		t.Fatalf("Failed to create project .devcontainer: %v", err)
+	}
+	custom := `{"name": "custom"}`
+	if err := os.WriteFile(filepath.Join(devDir, "devcontainer.json"), []byte(custom), 0644); err != nil {
+		t.Fatalf("Failed to write devcontainer.json: %v", err)
+	}
+
+	data := TemplateData{ProjectPath: projectDir, ProjectName: "myproject"}
+	if err := gen.WriteToProjectExcept(projectDir, "basic", data, "devcontainer.json"); err != nil {
+		t.Fatalf("WriteToProjectExcept failed: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(devDir, "devcontainer.json")); string(got) != custom {
+		t.Errorf("devcontainer.json = %q, want the existing %q", got, custom)
+	}
+	if _, err := os.Stat(filepath.Join(devDir, "docker-compose.yml")); err != nil {
+		t.Errorf("docker-compose.yml not written: %v", err)
+	}
+
+	// A kept file that does not exist yet is written as usual.
+	emptyProject := t.TempDir()
+	if err := gen.WriteToProjectExcept(emptyProject, "basic", data, "devcontainer.json"); err != nil {
+		t.Fatalf("WriteToProjectExcept failed: %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(emptyProject, ".devcontainer", "devcontainer.json")); !strings.Contains(string(got), "myproject") {
+		t.Errorf("devcontainer.json = %q, want it rendered from the template", got)
+	}
+}
+
 // TestComposeGenerator_WriteToProject_UnknownTemplate tests that WriteToProject
 // returns an error when the template is not found.
 func TestComposeGenerator_WriteToProject_UnknownTemplate(t *testing.T) {
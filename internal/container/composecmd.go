@@ -0,0 +1,53 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ComposeProbe reports whether a compose invocation (argv prefix, e.g.
+// ["docker", "compose"]) is usable on this host.
+type ComposeProbe func(argv []string) bool
+
+// composeProbeTimeout bounds each `<compose> version` run of the default probe.
+const composeProbeTimeout = 5 * time.Second
+
+// probeComposeVersion is the default ComposeProbe: it runs `<argv> version`.
+func probeComposeVersion(argv []string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), composeProbeTimeout)
+	defer cancel()
+	args := append(append([]string{}, argv[1:]...), "version")
+	return exec.CommandContext(ctx, argv[0], args...).Run() == nil
+}
+
+// composeCandidates lists the compose invocations that work with a runtime,
+// most preferred first. The first entry is the default when none probes OK.
+func composeCandidates(executable string) [][]string {
+	if executable == "podman" {
+		return [][]string{{"podman-compose"}, {"podman", "compose"}, {"docker-compose"}}
+	}
+	return [][]string{{executable, "compose"}, {"docker-compose"}}
+}
+
+// DetectComposeCommand resolves the compose invocation for a runtime. A
+// non-empty override (config compose_command, e.g. "docker-compose") is split
+// on whitespace and used as is; otherwise the first candidate that probe
+// accepts wins: docker compose, then docker-compose for docker; podman-compose,
+// podman compose, then docker-compose for podman. If none does, the preferred
+// candidate is returned so compose errors name the expected command.
+func DetectComposeCommand(executable, override string, probe ComposeProbe) []string {
+	if fields := strings.Fields(override); len(fields) > 0 {
+		return fields
+	}
+	candidates := composeCandidates(executable)
+	for _, argv := range candidates {
+		if probe(argv) {
+			return argv
+		}
+	}
+	return candidates[0]
+}
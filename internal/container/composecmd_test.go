@@ -0,0 +1,59 @@
+package container
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDetectComposeCommand(t *testing.T) {
+	// available builds a probe that accepts the listed invocations
+	available := func(invocations ...string) ComposeProbe {
+		return func(argv []string) bool {
+			return slices.Contains(invocations, strings.Join(argv, " "))
+		}
+	}
+
+	tests := []struct {
+		name       string
+		executable string
+		override   string
+		probe      ComposeProbe
+		want       []string
+	}{
+		{name: "docker compose plugin", executable: "docker", probe: available("docker compose", "docker-compose"), want: []string{"docker", "compose"}},
+		{name: "docker standalone compose", executable: "docker", probe: available("docker-compose"), want: []string{"docker-compose"}},
+		{name: "docker nothing found", executable: "docker", probe: available(), want: []string{"docker", "compose"}},
+		{name: "podman-compose", executable: "podman", probe: available("podman-compose", "podman compose"), want: []string{"podman-compose"}},
+		{name: "podman compose subcommand", executable: "podman", probe: available("podman compose"), want: []string{"podman", "compose"}},
+		{name: "podman with docker-compose", executable: "podman", probe: available("docker-compose"), want: []string{"docker-compose"}},
+		{name: "podman nothing found", executable: "podman", probe: available(), want: []string{"podman-compose"}},
+		{name: "override wins", executable: "docker", override: " docker-compose  --compatibility ", probe: available("docker compose"),
+			want: []string{"docker-compose", "--compatibility"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectComposeCommand(tt.executable, tt.override, tt.probe); !slices.Equal(got, tt.want) {
+				t.Errorf("DetectComposeCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComposeUp_UsesResolvedCommand(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	r := NewRuntimeWithExecutor("docker", func(_ context.Context, name string, args ...string) (string, error) {
+		gotName, gotArgs = name, args
+		return "", nil
+	})
+	r.compose = []string{"docker-compose"}
+
+	if err := r.ComposeUp(context.Background(), "/proj", "proj", nil); err != nil {
+		t.Fatalf("ComposeUp() error = %v", err)
+	}
+	if gotName != "docker-compose" || len(gotArgs) == 0 || gotArgs[0] != "-f" {
+		t.Errorf("ran %s %v, want docker-compose -f ...", gotName, gotArgs)
+	}
+}
@@ -0,0 +1,30 @@
+// pattern: Functional Core
+
+package container
+
+import (
+	"regexp"
+	"strings"
+)
+
+// buildKitLine matches BuildKit's plain-progress lines: "#<n> <rest>".
+var buildKitLine = regexp.MustCompile(`^#\d+ (.*)$`)
+
+// composeProgressLine reports whether a line of `compose up` output is worth
+// showing as a progress step, returning it trimmed. Container, network,
+// volume and image events ("Container foo  Started", "proxy Pulled") are kept,
+// as are BuildKit step headers ("#5 [app 2/4] RUN apt-get update"); the
+// output of build steps, timings, and blank lines are dropped.
+func composeProgressLine(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", false
+	}
+	if m := buildKitLine.FindStringSubmatch(line); m != nil {
+		if !strings.HasPrefix(m[1], "[") {
+			return "", false
+		}
+		return m[1], true
+	}
+	return line, true
+}
@@ -0,0 +1,91 @@
+package container
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// streamingMockRuntime is a mockRuntime whose compose up prints canned lines.
+type streamingMockRuntime struct {
+	*mockRuntime
+	lines []string
+}
+
+func (m *streamingMockRuntime) ComposeUpWithProgress(ctx context.Context, projectDir string, projectName string, env map[string]string, onLine func(string)) error {
+	for _, line := range m.lines {
+		onLine(line)
+	}
+	return m.ComposeUp(ctx, projectDir, projectName, env)
+}
+
+func TestComposeProgressLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   string
+		wantOK bool
+	}{
+		{line: " Container demo-proxy-1  Started", want: "Container demo-proxy-1  Started", wantOK: true},
+		{line: " proxy Pulling ", want: "proxy Pulling", wantOK: true},
+		{line: "#5 [app 2/4] RUN apt-get update", want: "[app 2/4] RUN apt-get update", wantOK: true},
+		{line: "#5 0.412 Get:1 http://archive.ubuntu.com jammy InRelease", wantOK: false},
+		{line: "#5 DONE 3.2s", wantOK: false},
+		{line: "   ", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := composeProgressLine(tt.line)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("composeProgressLine(%q) = (%q, %v), want (%q, %v)", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCreateWithCompose_ForwardsComposeOutputAsProgress(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	mgr.runtime = &streamingMockRuntime{mockRuntime: mock, lines: []string{
+		" proxy Pulling",
+		"#4 0.5 noise",
+		" Container test-container-app-1  Started",
+	}}
+
+	var steps []string
+	_, err := mgr.CreateWithCompose(context.Background(), CreateOptions{
+		ProjectPath: projectDir,
+		Template:    "default",
+		Name:        "test-container",
+		OnProgress: func(step ProgressStep) {
+			if step.Step == "container" && step.Status == "started" {
+				steps = append(steps, step.Message)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateWithCompose() error = %v", err)
+	}
+
+	want := []string{"Starting devcontainer", "proxy Pulling", "Container test-container-app-1  Started"}
+	if !slices.Equal(steps, want) {
+		t.Errorf("container progress = %q, want %q", steps, want)
+	}
+	if mock.composeUpProject != "test-container" {
+		t.Errorf("compose up project = %q, want test-container", mock.composeUpProject)
+	}
+}
+
+func TestComposeUpWithProgress_ReplaysExecutorOutput(t *testing.T) {
+	r := NewRuntimeWithExecutor("docker", func(_ context.Context, _ string, _ ...string) (string, error) {
+		return "Network demo_isolated  Created\nContainer demo-app-1  Started\n", nil
+	})
+
+	var lines []string
+	if err := r.ComposeUpWithProgress(context.Background(), "/proj", "demo", nil, func(line string) {
+		lines = append(lines, line)
+	}); err != nil {
+		t.Fatalf("ComposeUpWithProgress() error = %v", err)
+	}
+	want := []string{"Network demo_isolated  Created", "Container demo-app-1  Started"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
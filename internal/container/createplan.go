@@ -0,0 +1,289 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"devagent/internal/config"
+)
+
+// CreatePlan previews what CreateWithCompose would set up for a project:
+// the generated files, the app container's isolation and mounts, and whether
+// a proxy sidecar is created with which allowlist.
+type CreatePlan struct {
+	Name        string `json:"name"`
+	Template    string `json:"template"`
+	ProjectPath string `json:"project_path"`
+	// ExistingConfig is true when the project already has a
+	// .devcontainer/docker-compose.yml, which create keeps instead of writing
+	// the template's files; Files then shows the project's own files.
+	ExistingConfig bool              `json:"existing_config"`
+	Files          map[string]string `json:"files"` // .devcontainer-relative path -> contents
+	Isolation      PlanIsolation     `json:"isolation"`
+	Mounts         []string          `json:"mounts"`          // app service volumes
+	Proxy          *ProxyPlan        `json:"proxy,omitempty"` // nil when no proxy sidecar is created
+}
+
+// PlanIsolation is the app service's isolation as declared in the compose file.
+type PlanIsolation struct {
+	DroppedCaps []string `json:"dropped_caps,omitempty"`
+	AddedCaps   []string `json:"added_caps,omitempty"`
+	MemoryLimit string   `json:"memory_limit,omitempty"`
+	CPULimit    string   `json:"cpu_limit,omitempty"`
+	PidsLimit   int      `json:"pids_limit,omitempty"`
+	Networks    []string `json:"networks,omitempty"`
+}
+
+// ProxyPlan describes the proxy sidecar a create would start.
+type ProxyPlan struct {
+	Image string `json:"image"`
+	// AllowedDomains merges the template's filter.py list, the effective
+	// config/template allowlist, and the network.registries domains.
+	AllowedDomains []string `json:"allowed_domains"`
+}
+
+// planComposeFile is the subset of docker-compose.yml a plan reports on.
+type planComposeFile struct {
+	Services map[string]planService `yaml:"services"`
+}
+
+type planService struct {
+	Image     string      `yaml:"image"`
+	CapDrop   []string    `yaml:"cap_drop"`
+	CapAdd    []string    `yaml:"cap_add"`
+	MemLimit  string      `yaml:"mem_limit"`
+	Cpus      string      `yaml:"cpus"`
+	PidsLimit int         `yaml:"pids_limit"`
+	Networks  yaml.Node   `yaml:"networks"` // list or map of network names
+	Volumes   []yaml.Node `yaml:"volumes"`  // short "src:dst:mode" or long form
+}
+
+// PlanCreate returns the plan for creating a container with opts, without
+// side effects: no files are written, no token is provisioned, and the
+// runtime is not called. A missing or invalid allowlist file is an error, as
+// it would be for ReloadAllowlist.
+func (m *Manager) PlanCreate(ctx context.Context, opts CreateOptions) (*CreatePlan, error) {
+	if m.composeGenerator == nil {
+		return nil, errors.New("no templates loaded")
+	}
+	if opts.ProjectPath != "" {
+		absPath, err := filepath.Abs(opts.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve project path: %w", err)
+		}
+		opts.ProjectPath = absPath
+	}
+
+	result, err := m.composeGenerator.Preview(ComposeOptions{
+		ProjectPath: opts.ProjectPath,
+		Template:    opts.Template,
+		Name:        opts.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate compose config: %w", err)
+	}
+
+	plan := &CreatePlan{Name: opts.Name, Template: opts.Template, ProjectPath: opts.ProjectPath}
+	if _, err := os.Stat(filepath.Join(opts.ProjectPath, ".devcontainer", "docker-compose.yml")); err == nil {
+		plan.ExistingConfig = true
+		plan.Files, err = readSnapshotFiles(opts.ProjectPath)
+	} else {
+		tmpl := m.composeGenerator.GetTemplate(opts.Template)
+		plan.Files, err = renderPlanFiles(tmpl.DevcontainerDirs(), result.TemplateData)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !plan.ExistingConfig && m.keepsDevcontainerJSON(opts) {
+		kept, err := os.ReadFile(filepath.Join(opts.ProjectPath, ".devcontainer", devcontainerJSON))
+		if err != nil {
+			return nil, err
+		}
+		plan.Files[devcontainerJSON] = string(kept)
+	}
+	if !plan.ExistingConfig && len(opts.ExtraMounts) > 0 {
+		mounts, err := ParseMounts(opts.ExtraMounts)
+		if err != nil {
+			return nil, err
+		}
+		if plan.Files["docker-compose.yml"], err = addComposeMounts(plan.Files["docker-compose.yml"], mounts); err != nil {
+			return nil, err
+		}
+	}
+
+	var compose planComposeFile
+	if err := yaml.Unmarshal([]byte(plan.Files["docker-compose.yml"]), &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+
+	app := compose.Services["app"]
+	plan.Isolation = PlanIsolation{
+		DroppedCaps: app.CapDrop,
+		AddedCaps:   app.CapAdd,
+		MemoryLimit: app.MemLimit,
+		CPULimit:    app.Cpus,
+		PidsLimit:   app.PidsLimit,
+		Networks:    planNetworks(app.Networks),
+	}
+	plan.Mounts = planMounts(app.Volumes)
+
+	if proxy, ok := compose.Services["proxy"]; ok {
+		effective, err := m.EffectiveAllowlist(opts.Template)
+		if err != nil {
+			return nil, err
+		}
+		var registries []string
+		if extra := result.TemplateData.ExtraAllowedDomains; extra != "" {
+			registries = strings.Split(extra, ",")
+		}
+		plan.Proxy = &ProxyPlan{
+			Image: proxy.Image,
+			AllowedDomains: config.MergeAllowlists(
+				parseAllowlistFromScript(plan.Files[filepath.ToSlash(filterScriptRelPath)]),
+				effective,
+				registries,
+			),
+		}
+	}
+	return plan, nil
+}
+
+// GenerateResult previews what a create would generate, for review before a
+// long build: the project's devcontainer.json as create would leave it, and
+// the app container's isolation and mounts as the equivalent docker run
+// flags. The container is started by compose, so the runArgs the
+// devcontainer.json declares are not applied; IgnoredRunArgs lists them.
+// Plan has the full details.
+type GenerateResult struct {
+	DevcontainerJSON string      `json:"devcontainer_json"`
+	RunArgs          []string    `json:"run_args"`
+	IgnoredRunArgs   []string    `json:"ignored_run_args,omitempty"`
+	Plan             *CreatePlan `json:"plan"`
+}
+
+// PreviewCreate returns what creating a container with opts would generate.
+// Like PlanCreate it has no side effects: nothing is written into the project
+// and the runtime is not called.
+func (m *Manager) PreviewCreate(ctx context.Context, opts CreateOptions) (*GenerateResult, error) {
+	plan, err := m.PlanCreate(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &GenerateResult{
+		DevcontainerJSON: plan.Files[devcontainerJSON],
+		RunArgs:          planRunArgs(plan),
+		IgnoredRunArgs:   declaredRunArgs(plan.Files[devcontainerJSON]),
+		Plan:             plan,
+	}, nil
+}
+
+// planRunArgs renders a plan's app isolation and mounts as docker run flags.
+func planRunArgs(plan *CreatePlan) []string {
+	iso := plan.Isolation
+	args := []string{}
+	for _, c := range iso.DroppedCaps {
+		args = append(args, "--cap-drop="+c)
+	}
+	for _, c := range iso.AddedCaps {
+		args = append(args, "--cap-add="+c)
+	}
+	if iso.MemoryLimit != "" {
+		args = append(args, "--memory="+iso.MemoryLimit)
+	}
+	if iso.CPULimit != "" {
+		args = append(args, "--cpus="+iso.CPULimit)
+	}
+	if iso.PidsLimit > 0 {
+		args = append(args, "--pids-limit="+strconv.Itoa(iso.PidsLimit))
+	}
+	for _, n := range iso.Networks {
+		args = append(args, "--network="+n)
+	}
+	for _, v := range plan.Mounts {
+		args = append(args, "--volume="+v)
+	}
+	return args
+}
+
+// declaredRunArgs returns the runArgs a devcontainer.json declares, which
+// have no effect on a container compose starts.
+func declaredRunArgs(devcontainer string) []string {
+	// Only runArgs matters here; other fields vary in shape across templates
+	var declared struct {
+		RunArgs []string `json:"runArgs"`
+	}
+	if json.Unmarshal([]byte(devcontainer), &declared) != nil {
+		return nil
+	}
+	return declared.RunArgs
+}
+
+// renderPlanFiles renders the snapshotFiles from a template's .devcontainer
+// directories (outermost base first) in memory, as WriteToProject would
+// write them; a file no layer has is skipped.
+func renderPlanFiles(dirs []string, data TemplateData) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, rel := range snapshotFiles {
+		content, ok, err := renderLayeredFile(dirs, rel, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files[filepath.ToSlash(rel)] = content
+		}
+	}
+	return files, nil
+}
+
+// planNetworks returns the network names of a service's networks entry,
+// which compose accepts as a list or as a map keyed by name.
+func planNetworks(node yaml.Node) []string {
+	var names []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			names = append(names, n.Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			names = append(names, node.Content[i].Value)
+		}
+	}
+	return names
+}
+
+// planMounts renders a service's volumes as "source:target[:ro]" strings.
+// Short-form entries are kept as written.
+func planMounts(volumes []yaml.Node) []string {
+	mounts := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		if v.Kind == yaml.ScalarNode {
+			mounts = append(mounts, v.Value)
+			continue
+		}
+		var long struct {
+			Source   string `yaml:"source"`
+			Target   string `yaml:"target"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		if err := v.Decode(&long); err != nil {
+			continue
+		}
+		mount := long.Source + ":" + long.Target
+		if long.ReadOnly {
+			mount += ":ro"
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts
+}
@@ -0,0 +1,138 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"devagent/internal/config"
+)
+
+// planTestManager returns a manager with one template whose .devcontainer
+// holds the given files.
+func planTestManager(t *testing.T, files map[string]string) *Manager {
+	t.Helper()
+	templateDir := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(templateDir, ".devcontainer", rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewManager(ManagerOptions{
+		Config:    &config.Config{},
+		Templates: []config.Template{{Name: "default", Path: templateDir}},
+		Runtime:   &mockRuntime{},
+	})
+}
+
+func TestPlanCreate_IncludesProxyForAllowlistTemplate(t *testing.T) {
+	mgr := planTestManager(t, map[string]string{
+		"docker-compose.yml.tmpl": `services:
+  app:
+    cap_drop: [ALL]
+    mem_limit: 4g
+    pids_limit: 512
+    networks: [isolated]
+    volumes:
+      - {{.ProjectPath}}:/workspace:cached
+  proxy:
+    image: {{.ProxyImage}}
+`,
+		filterScriptRelPath: "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n",
+	})
+	projectPath := t.TempDir()
+
+	plan, err := mgr.PlanCreate(context.Background(), CreateOptions{ProjectPath: projectPath, Template: "default", Name: "demo"})
+	if err != nil {
+		t.Fatalf("PlanCreate() error = %v", err)
+	}
+
+	if plan.Proxy == nil {
+		t.Fatal("plan.Proxy = nil, want a proxy plan")
+	}
+	if plan.Proxy.Image != "mitmproxy/mitmproxy:latest" {
+		t.Errorf("proxy image = %q", plan.Proxy.Image)
+	}
+	if !slices.Contains(plan.Proxy.AllowedDomains, "github.com") {
+		t.Errorf("allowed domains = %v, want github.com", plan.Proxy.AllowedDomains)
+	}
+	if !slices.Equal(plan.Isolation.DroppedCaps, []string{"ALL"}) || plan.Isolation.MemoryLimit != "4g" || plan.Isolation.PidsLimit != 512 {
+		t.Errorf("isolation = %+v", plan.Isolation)
+	}
+	if !slices.Equal(plan.Mounts, []string{projectPath + ":/workspace:cached"}) {
+		t.Errorf("mounts = %v", plan.Mounts)
+	}
+	if plan.ExistingConfig {
+		t.Error("ExistingConfig = true for a project without .devcontainer")
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".devcontainer")); !os.IsNotExist(err) {
+		t.Error("PlanCreate should not write files into the project")
+	}
+}
+
+func TestPlanCreate_OmitsProxyWithoutProxyService(t *testing.T) {
+	mgr := planTestManager(t, map[string]string{
+		"docker-compose.yml": "services:\n  app:\n    image: ubuntu\n",
+	})
+
+	plan, err := mgr.PlanCreate(context.Background(), CreateOptions{ProjectPath: t.TempDir(), Template: "default", Name: "demo"})
+	if err != nil {
+		t.Fatalf("PlanCreate() error = %v", err)
+	}
+	if plan.Proxy != nil {
+		t.Errorf("plan.Proxy = %+v, want nil", plan.Proxy)
+	}
+}
+
+func TestPreviewCreate_IsolatedTemplateHasRunArgsAndNoSideEffects(t *testing.T) {
+	mgr := planTestManager(t, map[string]string{
+		"devcontainer.json.tmpl": `{"name": "{{.ContainerName}}", "runArgs": ["--init"]}`,
+		"docker-compose.yml.tmpl": `services:
+  app:
+    cap_drop: [NET_RAW, SYS_ADMIN]
+    mem_limit: 4g
+    cpus: "2"
+    pids_limit: 512
+    networks: [isolated]
+    volumes:
+      - {{.ProjectPath}}:/workspace:cached
+`,
+	})
+	mock := mgr.runtime.(*mockRuntime)
+	projectPath := t.TempDir()
+
+	result, err := mgr.PreviewCreate(context.Background(), CreateOptions{ProjectPath: projectPath, Template: "default", Name: "demo"})
+	if err != nil {
+		t.Fatalf("PreviewCreate() error = %v", err)
+	}
+
+	want := []string{
+		"--cap-drop=NET_RAW", "--cap-drop=SYS_ADMIN", "--memory=4g", "--cpus=2", "--pids-limit=512",
+		"--network=isolated", "--volume=" + projectPath + ":/workspace:cached",
+	}
+	if !slices.Equal(result.RunArgs, want) {
+		t.Errorf("RunArgs = %q, want %q", result.RunArgs, want)
+	}
+	// compose starts the container, so the devcontainer.json's own runArgs
+	// are reported apart as ignored
+	if !slices.Equal(result.IgnoredRunArgs, []string{"--init"}) {
+		t.Errorf("IgnoredRunArgs = %q, want [--init]", result.IgnoredRunArgs)
+	}
+	if !strings.Contains(result.DevcontainerJSON, `"name": "demo"`) {
+		t.Errorf("DevcontainerJSON = %q, want it rendered for demo", result.DevcontainerJSON)
+	}
+
+	if _, err := os.Stat(filepath.Join(projectPath, ".devcontainer")); !os.IsNotExist(err) {
+		t.Error("PreviewCreate should not write files into the project")
+	}
+	if mock.composeUpCalled != "" || len(mgr.List()) != 0 {
+		t.Errorf("PreviewCreate created a container (compose up in %q, %d containers)", mock.composeUpCalled, len(mgr.List()))
+	}
+}
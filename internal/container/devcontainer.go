@@ -3,12 +3,14 @@
 package container
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -98,6 +100,14 @@ func ensureGitHubToken(tokenPath string) (string, string) {
 	return tokenPath, strings.TrimSpace(string(data))
 }
 
+// VSCodeURI builds the vscode-remote URI that opens VS Code attached to a
+// running container. containerID is the full container ID; workspacePath is
+// the folder inside the container (see ReadWorkspaceFolder).
+func VSCodeURI(containerID, workspacePath string) string {
+	payload, _ := json.Marshal(map[string]string{"containerName": containerID})
+	return fmt.Sprintf("vscode-remote://attached-container+%s%s", hex.EncodeToString(payload), workspacePath)
+}
+
 // ReadWorkspaceFolder reads the workspaceFolder from a project's devcontainer.json.
 // Returns the workspace folder path, or a default of "/workspaces" if not specified or on error.
 func ReadWorkspaceFolder(projectPath string) string {
@@ -126,7 +136,8 @@ func ReadWorkspaceFolder(projectPath string) string {
 // copyTemplateDir copies a directory tree from src to dst, processing .tmpl files with templateData.
 // Non-.tmpl files are copied as-is. Directories are created as needed.
 // The .gitkeep files are copied to preserve empty directories.
-func copyTemplateDir(src, dst string, data TemplateData) error {
+// Output files named in keep (relative to dst) that already exist are left as they are.
+func copyTemplateDir(src, dst string, data TemplateData, keep ...string) error {
 	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -144,6 +155,12 @@ func copyTemplateDir(src, dst string, data TemplateData) error {
 			return os.MkdirAll(destPath, 0755)
 		}
 
+		if outRel := strings.TrimSuffix(relPath, ".tmpl"); slices.Contains(keep, outRel) {
+			if _, err := os.Stat(filepath.Join(dst, outRel)); err == nil {
+				return nil
+			}
+		}
+
 		// Process .tmpl files
 		if strings.HasSuffix(relPath, ".tmpl") {
 			content, err := processTemplate(path, data)
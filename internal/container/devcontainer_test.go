@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestMain sets up the test environment, including mocking claude setup-token
@@ -14,6 +15,8 @@ func TestMain(m *testing.M) {
 	claudeSetupTokenFunc = func() (string, error) {
 		return "", errors.New("claude CLI not available in tests")
 	}
+	// Keep Refresh retries from slowing down tests that simulate listing failures
+	refreshRetryBackoff = time.Millisecond
 	os.Exit(m.Run())
 }
 
@@ -0,0 +1,62 @@
+// pattern: Functional Core
+
+package container
+
+import "errors"
+
+// Error kinds returned (wrapped) by Manager, so callers classify a failure
+// with errors.Is instead of matching its message. The specific errors below
+// (ErrContainerNotFound, ErrSessionExists, ...) are each one of these kinds.
+var (
+	// ErrNotFound: the container, or what was asked of it, does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrNotRunning: the operation needs a running container.
+	ErrNotRunning = errors.New("container is not running")
+	// ErrAlreadyRunning: the container to start is running.
+	ErrAlreadyRunning = errors.New("container is already running")
+	// ErrAlreadyExists: what would be created exists already.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrInvalid: the options asked for are not valid, e.g. an unknown
+	// template.
+	ErrInvalid = errors.New("invalid options")
+	// ErrProtected: the container is protected from what was asked, e.g.
+	// destroying it while its note says do-not-delete.
+	ErrProtected = errors.New("container is protected")
+)
+
+// ErrContainerNotFound is returned (wrapped) when no known container matches
+// an ID or reference. It is an ErrNotFound.
+var ErrContainerNotFound error = &kindError{msg: "container not found", kind: ErrNotFound}
+
+// ErrSessionExists is returned (wrapped) by CreateSession when the container
+// already has a tmux session of that name. It is an ErrAlreadyExists.
+var ErrSessionExists error = &kindError{msg: "session already exists", kind: ErrAlreadyExists}
+
+// ErrTemplateNotFound is returned (wrapped) when CreateOptions name a
+// template that is not loaded. It is an ErrInvalid.
+var ErrTemplateNotFound error = &kindError{msg: "template not found", kind: ErrInvalid}
+
+// ErrInvalidTemplateData is returned (wrapped, with the cause) when the
+// values a template would be rendered with, such as the container name, are
+// not valid. It is an ErrInvalid.
+var ErrInvalidTemplateData error = &kindError{msg: "invalid template data", kind: ErrInvalid}
+
+// ErrInvalidNote is returned (wrapped, with the reason) by SetNote and
+// ValidateNote for a note that is too long or not a single line. It is an
+// ErrInvalid.
+var ErrInvalidNote error = &kindError{msg: "invalid note", kind: ErrInvalid}
+
+// ErrContainerProtected is returned (wrapped) by DestroyWithCompose and
+// DestroyWithOptions for a container whose note contains ProtectedNote,
+// unless DestroyOptions.Force is set. It is an ErrProtected.
+var ErrContainerProtected error = &kindError{msg: "container is protected by a " + ProtectedNote + " note", kind: ErrProtected}
+
+// kindError is a specific error with its own message that is also one of the
+// error kinds above (errors.Is matches both).
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string { return e.msg }
+func (e *kindError) Unwrap() error { return e.kind }
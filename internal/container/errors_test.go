@@ -0,0 +1,56 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestManager_ReturnsTypedErrors(t *testing.T) {
+	mock := &mockRuntime{
+		execAsOutput: func(id string, cmd []string) string {
+			if slices.Contains(cmd, "list-sessions") {
+				return "dev: 1 windows (created Mon Jan  1 00:00:00 2024)\n"
+			}
+			return ""
+		},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	mgr.containers["up"] = &Container{ID: "up", Name: "up", ProjectPath: t.TempDir(), State: StateRunning}
+	mgr.containers["down"] = &Container{ID: "down", Name: "down", ProjectPath: t.TempDir(), State: StateStopped}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		err  error
+		want []error
+	}{
+		{"start unknown", mgr.StartWithCompose(ctx, "nope"), []error{ErrNotFound, ErrContainerNotFound}},
+		{"stop unknown", mgr.StopWithCompose(ctx, "nope"), []error{ErrNotFound, ErrContainerNotFound}},
+		{"destroy unknown", mgr.DestroyWithCompose(ctx, "nope"), []error{ErrNotFound, ErrContainerNotFound}},
+		{"start running", mgr.StartWithCompose(ctx, "up"), []error{ErrAlreadyRunning}},
+		{"stop stopped", mgr.StopWithCompose(ctx, "down"), []error{ErrNotRunning}},
+		{"session on stopped", mgr.CreateSession(ctx, "down", "dev"), []error{ErrNotRunning}},
+		{"kill on stopped", mgr.KillSession(ctx, "down", "dev"), []error{ErrNotRunning}},
+		{"keys on unknown", mgr.SendKeys(ctx, "nope", "dev", "ls", true), []error{ErrNotFound}},
+		{"duplicate session", mgr.CreateSession(ctx, "up", "dev"), []error{ErrAlreadyExists, ErrSessionExists}},
+	}
+	for _, tt := range tests {
+		for _, want := range tt.want {
+			if !errors.Is(tt.err, want) {
+				t.Errorf("%s: error = %v, want errors.Is %v", tt.name, tt.err, want)
+			}
+		}
+	}
+
+	if err := mgr.CreateSession(ctx, "up", "other"); err != nil {
+		t.Errorf("CreateSession(new name) error = %v", err)
+	}
+	if _, err := mgr.GetCreationSnapshot("up"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetCreationSnapshot() error = %v, want an ErrNotFound", err)
+	}
+	if !errors.Is(ErrContainerExists, ErrAlreadyExists) || !errors.Is(ErrCloneExists, ErrAlreadyExists) {
+		t.Error("ErrContainerExists and ErrCloneExists should be ErrAlreadyExists")
+	}
+}
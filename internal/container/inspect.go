@@ -0,0 +1,49 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// InspectCacheTTL is how long Manager.InspectRaw reuses a container's inspect
+// output before running the runtime again.
+const InspectCacheTTL = 3 * time.Second
+
+// inspectEntry is a cached InspectRaw result.
+type inspectEntry struct {
+	data json.RawMessage
+	at   time.Time
+}
+
+// InspectRaw returns the runtime's inspect JSON for the container verbatim.
+// Results are cached per container for InspectCacheTTL, so a client polling
+// the web API does not run the runtime on every request.
+func (m *Manager) InspectRaw(ctx context.Context, containerID string) (json.RawMessage, error) {
+	m.inspectMu.Lock()
+	e, ok := m.inspectCache[containerID]
+	m.inspectMu.Unlock()
+	if ok && time.Since(e.at) < InspectCacheTTL {
+		return e.data, nil
+	}
+
+	data, err := m.runtime.InspectRaw(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	m.inspectMu.Lock()
+	defer m.inspectMu.Unlock()
+	// Drop expired entries so containers that are no longer inspected do not
+	// accumulate
+	for id, old := range m.inspectCache {
+		if now.Sub(old.at) >= InspectCacheTTL {
+			delete(m.inspectCache, id)
+		}
+	}
+	m.inspectCache[containerID] = inspectEntry{data: data, at: now}
+	return data, nil
+}
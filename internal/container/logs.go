@@ -0,0 +1,100 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Log tail bounds for Logs and ExportLogs: a tail of 0 or less means
+// DefaultLogTail, and larger requests are capped at MaxLogTail.
+const (
+	DefaultLogTail = 500
+	MaxLogTail     = 10000
+)
+
+// clampLogTail applies DefaultLogTail and MaxLogTail to a requested tail.
+func clampLogTail(tail int) int {
+	if tail <= 0 {
+		return DefaultLogTail
+	}
+	return min(tail, MaxLogTail)
+}
+
+// Logs returns the last tail lines of a container's output (stdout and
+// stderr interleaved, with timestamps).
+func (m *Manager) Logs(ctx context.Context, containerID string, tail int) (string, error) {
+	if _, ok := m.Get(containerID); !ok {
+		return "", fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	return m.runtime.Logs(ctx, containerID, clampLogTail(tail))
+}
+
+// ExportLogs writes the last tail lines of a container's output to a file
+// under the data dir's container-logs directory and returns its path.
+func (m *Manager) ExportLogs(ctx context.Context, containerID string, tail int) (string, error) {
+	logs, err := m.Logs(ctx, containerID, tail)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(getDataDir(), "container-logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log export dir: %w", err)
+	}
+	path := filepath.Join(dir, LogExportFilename(m.getContainerName(containerID), time.Now()))
+	if err := os.WriteFile(path, []byte(logs), 0644); err != nil {
+		return "", fmt.Errorf("failed to write logs: %w", err)
+	}
+
+	m.containerLogger(m.getContainerName(containerID)).Info("exported container logs", "containerID", containerID, "path", path)
+	return path, nil
+}
+
+// StreamLogsTail is how many existing lines StreamLogs replays before
+// following new output.
+const StreamLogsTail = 100
+
+// logFollower is implemented by runtimes that can follow a container's
+// output (the real Runtime; see FollowLogs).
+type logFollower interface {
+	FollowLogs(ctx context.Context, id string, tail int, onLine func(string)) error
+}
+
+// StreamLogs follows a container's output, delivering its last
+// StreamLogsTail lines and then new ones as they are written. The channel is
+// closed once ctx is canceled or the stream ends (e.g. the container
+// stopped); cancel ctx to stop following.
+func (m *Manager) StreamLogs(ctx context.Context, containerID string) (<-chan string, error) {
+	if _, ok := m.Get(containerID); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	follower, ok := m.runtime.(logFollower)
+	if !ok {
+		return nil, fmt.Errorf("runtime cannot stream logs")
+	}
+
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		err := follower.FollowLogs(ctx, containerID, StreamLogsTail, func(line string) {
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			m.containerLogger(m.getContainerName(containerID)).Warn("log stream ended", "containerID", containerID, "error", err)
+		}
+	}()
+	return lines, nil
+}
+
+// LogExportFilename names an exported log file: <name>-<UTC timestamp>.log.
+func LogExportFilename(name string, now time.Time) string {
+	return fmt.Sprintf("%s-%s.log", name, now.UTC().Format("20060102-150405"))
+}
@@ -0,0 +1,124 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogs_ClampsTail(t *testing.T) {
+	mock := &mockRuntime{containers: []Container{{ID: "abc", Name: "app", State: StateStopped}}}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	for _, tail := range []int{0, 50, MaxLogTail + 1} {
+		if _, err := mgr.Logs(context.Background(), "abc", tail); err != nil {
+			t.Fatalf("Logs(%d) error = %v", tail, err)
+		}
+	}
+	if want := []int{DefaultLogTail, 50, MaxLogTail}; !slices.Equal(mock.logsTails, want) {
+		t.Errorf("runtime tails = %v, want %v", mock.logsTails, want)
+	}
+
+	if _, err := mgr.Logs(context.Background(), "missing", 10); err == nil {
+		t.Error("Logs() for unknown container should fail")
+	}
+}
+
+func TestExportLogs_WritesToDataDir(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	mock := &mockRuntime{
+		containers: []Container{{ID: "abc", Name: "app", State: StateRunning}},
+		logsOutput: "line 1\nline 2\n",
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	path, err := mgr.ExportLogs(context.Background(), "abc", 0)
+	if err != nil {
+		t.Fatalf("ExportLogs() error = %v", err)
+	}
+	if dir := filepath.Join(dataHome, "devagent", "container-logs"); filepath.Dir(path) != dir {
+		t.Errorf("path = %q, want a file in %q", path, dir)
+	}
+	if name := filepath.Base(path); !strings.HasPrefix(name, "app-") || !strings.HasSuffix(name, ".log") {
+		t.Errorf("file name = %q, want app-<timestamp>.log", name)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "line 1\nline 2\n" {
+		t.Errorf("exported logs = %q", data)
+	}
+}
+
+func TestLogExportFilename(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got := LogExportFilename("myapp-app-1", now); got != "myapp-app-1-20260304-050607.log" {
+		t.Errorf("LogExportFilename() = %q", got)
+	}
+}
+
+// followingMockRuntime is a mockRuntime whose FollowLogs emits canned lines
+// and then follows until its context is canceled.
+type followingMockRuntime struct {
+	*mockRuntime
+	lines []string
+	tail  int
+}
+
+func (m *followingMockRuntime) FollowLogs(ctx context.Context, id string, tail int, onLine func(string)) error {
+	m.tail = tail
+	for _, line := range m.lines {
+		onLine(line)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestStreamLogs_DeliversLinesUntilCanceled(t *testing.T) {
+	mock := &mockRuntime{containers: []Container{{ID: "abc", Name: "app", State: StateRunning}}}
+	runtime := &followingMockRuntime{mockRuntime: mock, lines: []string{"one", "two"}}
+	mgr := NewManager(ManagerOptions{Runtime: runtime})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := mgr.StreamLogs(ctx, "abc")
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+	got := []string{<-lines, <-lines}
+	if !slices.Equal(got, runtime.lines) {
+		t.Errorf("lines = %q, want %q", got, runtime.lines)
+	}
+	if runtime.tail != StreamLogsTail {
+		t.Errorf("tail = %d, want %d", runtime.tail, StreamLogsTail)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Error("expected the channel to close after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+
+	if _, err := mgr.StreamLogs(context.Background(), "missing"); err == nil {
+		t.Error("StreamLogs() for unknown container should fail")
+	}
+}
@@ -4,11 +4,17 @@ package container
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"devagent/internal/config"
 	"devagent/internal/logging"
@@ -18,16 +24,22 @@ import (
 // RuntimeInterface abstracts container runtime operations for testing.
 type RuntimeInterface interface {
 	ListContainers(ctx context.Context) ([]Container, error)
+	ListAllContainers(ctx context.Context) ([]Container, error)
 	Exec(ctx context.Context, id string, cmd []string) (string, error)
 	ExecAs(ctx context.Context, id string, user string, cmd []string) (string, error)
+	Logs(ctx context.Context, id string, tail int) (string, error)
 	InspectContainer(ctx context.Context, id string) (ContainerState, error)
+	InspectRaw(ctx context.Context, id string) (json.RawMessage, error)
 	GetIsolationInfo(ctx context.Context, id string) (*IsolationInfo, error)
+	GetMounts(ctx context.Context, id string) ([]MountInfo, error)
+	GetPorts(ctx context.Context, id string) ([]PortMapping, error)
 
 	// Compose lifecycle operations
 	ComposeUp(ctx context.Context, projectDir string, projectName string, env map[string]string) error
 	ComposeStart(ctx context.Context, projectDir string, projectName string) error
-	ComposeStop(ctx context.Context, projectDir string, projectName string) error
+	ComposeStop(ctx context.Context, projectDir string, projectName string, services ...string) error
 	ComposeDown(ctx context.Context, projectDir string, projectName string) error
+	ComposeRestart(ctx context.Context, projectDir string, projectName string, services ...string) error
 }
 
 // Manager orchestrates container lifecycle operations.
@@ -37,6 +49,7 @@ type Manager struct {
 	runtime          RuntimeInterface
 	runtimeName      string            // "docker" or "podman" - used for attach commands
 	runtimePath      string            // full path to binary - bypasses shell aliases
+	runtimeHost      string            // daemon address (runtime_host / DOCKER_HOST / CONTAINER_HOST); empty for the default
 	composeGenerator *ComposeGenerator // for compose-based orchestration
 	tmuxClient       *tmux.Client
 	containers       map[string]*Container
@@ -45,6 +58,16 @@ type Manager struct {
 	logManager       logging.LoggerProvider        // for per-container loggers
 	proxyLogCancels  map[string]context.CancelFunc // proxyLogPath -> cancel func
 	onChange         func()                        // called after state changes (e.g. to notify SSE clients)
+	degraded         map[string]bool               // container IDs with a stopped sidecar, to warn once per transition
+	ops              *Operations                   // in-flight lifecycle operations
+	refreshed        atomic.Bool                   // set by the first successful Refresh
+	activityMu       sync.Mutex                    // protects activity
+	activity         map[string]time.Time          // container ID -> last exec/session activity
+	notes            *NoteStore                    // user notes by project path
+	gitExec          StreamExecutor                // runs git clone for CloneAndCreate
+	sessionProjects  map[string]string             // compose project name -> project dir, for projects CreateWithCompose brought up in this process
+	inspectMu        sync.Mutex                    // protects inspectCache
+	inspectCache     map[string]inspectEntry       // container ID -> recent InspectRaw result
 }
 
 // SetOnChange registers a callback invoked after container/session state changes.
@@ -60,6 +83,20 @@ func (m *Manager) notifyChange() {
 	}
 }
 
+// Operations returns the tracker of in-flight create/start/stop/destroy
+// operations, shared with the TUI and web API.
+func (m *Manager) Operations() *Operations {
+	return m.ops
+}
+
+// SetTemplates replaces the templates used for new container creation.
+// Existing containers are unaffected.
+func (m *Manager) SetTemplates(templates []config.Template) {
+	if m.composeGenerator != nil {
+		m.composeGenerator.SetTemplates(templates)
+	}
+}
+
 // ManagerOptions holds all configuration options for creating a Manager.
 type ManagerOptions struct {
 	Config      *config.Config
@@ -67,8 +104,10 @@ type ManagerOptions struct {
 	Runtime     RuntimeInterface
 	ComposeGen  *ComposeGenerator
 	LogManager  logging.LoggerProvider
-	RuntimeName string // "docker" or "podman" - used for attach commands
-	RuntimePath string // full path to binary - bypasses shell aliases
+	RuntimeName string         // "docker" or "podman" - used for attach commands
+	RuntimePath string         // full path to binary - bypasses shell aliases
+	RuntimeHost string         // daemon address for the runtime and attach commands; empty for the default
+	GitExecutor StreamExecutor // runs git for CloneAndCreate; nil uses os/exec
 }
 
 // nopLoggerProvider is a no-op LoggerProvider that returns NopLogger for all scopes.
@@ -89,10 +128,13 @@ func NewManager(opts ManagerOptions) *Manager {
 	if opts.RuntimePath == "" && opts.Config != nil {
 		opts.RuntimePath = opts.Config.DetectedRuntimePath()
 	}
+	if opts.RuntimeHost == "" && opts.Config != nil {
+		opts.RuntimeHost = opts.Config.DetectedRuntimeHost()
+	}
 
 	// Auto-create runtime from config if not provided
 	if opts.Runtime == nil && opts.Config != nil {
-		opts.Runtime = NewRuntime(opts.RuntimeName)
+		opts.Runtime = NewRuntime(opts.RuntimeName, opts.Config.ComposeCommand, opts.RuntimeHost)
 	}
 
 	// Default logger to NopLogger
@@ -117,12 +159,22 @@ func NewManager(opts ManagerOptions) *Manager {
 		runtime:          opts.Runtime,
 		runtimeName:      opts.RuntimeName,
 		runtimePath:      opts.RuntimePath,
+		runtimeHost:      opts.RuntimeHost,
 		composeGenerator: opts.ComposeGen,
 		containers:       make(map[string]*Container),
 		sidecars:         make(map[string]*Sidecar),
+		sessionProjects:  make(map[string]string),
+		inspectCache:     make(map[string]inspectEntry),
 		logger:           logger,
 		logManager:       logManager,
 		proxyLogCancels:  make(map[string]context.CancelFunc),
+		ops:              NewOperations(),
+		activity:         make(map[string]time.Time),
+		notes:            LoadNoteStore(""),
+		gitExec:          opts.GitExecutor,
+	}
+	if m.gitExec == nil {
+		m.gitExec = streamingExecutor
 	}
 
 	// Create tmux.Client with executor that wraps runtime.ExecAs with user lookup
@@ -138,7 +190,7 @@ func NewManager(opts ManagerOptions) *Manager {
 func (m *Manager) Refresh(ctx context.Context) error {
 	m.logger.Debug("refreshing container list")
 
-	containers, err := m.runtime.ListContainers(ctx)
+	containers, err := m.listContainersWithRetry(ctx)
 	if err != nil {
 		m.logger.Error("failed to list containers", "error", err)
 		return err
@@ -158,17 +210,104 @@ func (m *Manager) Refresh(ctx context.Context) error {
 
 	// Rebuild sidecars map
 	m.refreshSidecars(containers)
+	m.pruneActivity()
 
 	m.logger.Debug("container list refreshed", "count", len(m.containers), "sidecars", len(m.sidecars))
 
+	// Flag running containers whose sidecars have died
+	degraded := m.reconcileSidecars()
+
 	// Start proxy log readers for containers that don't have one yet
 	m.startMissingProxyLogReaders()
 
 	m.mu.Unlock()
+
+	if m.cfg != nil && m.cfg.Network.AutoRestartProxy {
+		m.restartSidecars(ctx, degraded)
+	}
+
+	m.refreshed.Store(true)
 	m.notifyChange()
 	return nil
 }
 
+// Refreshed reports whether Refresh has succeeded at least once, i.e. whether
+// the container list reflects the runtime.
+func (m *Manager) Refreshed() bool {
+	return m.refreshed.Load()
+}
+
+// refreshRetries is how many times Refresh retries a failed container listing,
+// waiting refreshRetryBackoff (doubled each attempt) in between. Transient
+// runtime hiccups are common enough that one failed `ps` should not surface.
+var (
+	refreshRetries      = 2
+	refreshRetryBackoff = 250 * time.Millisecond
+)
+
+// listContainersWithRetry lists containers, retrying transient failures.
+// Stops early if ctx is done and returns the last error.
+func (m *Manager) listContainersWithRetry(ctx context.Context) ([]Container, error) {
+	backoff := refreshRetryBackoff
+	for attempt := 0; ; attempt++ {
+		containers, err := m.runtime.ListContainers(ctx)
+		if err == nil || attempt >= refreshRetries {
+			return containers, err
+		}
+		m.logger.Debug("container listing failed, retrying", "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// reconcileSidecars sets SidecarWarning on running containers whose sidecars are
+// not running and returns them. Logs a warning when a container becomes degraded
+// and an info message when it recovers, not on every refresh.
+// Must be called with m.mu held.
+func (m *Manager) reconcileSidecars() []*Container {
+	warnings := sidecarWarnings(m.containers, m.sidecars)
+
+	var degraded []*Container
+	next := make(map[string]bool, len(warnings))
+	for id, warning := range warnings {
+		c := m.containers[id]
+		c.SidecarWarning = warning
+		degraded = append(degraded, c)
+		next[id] = true
+		if !m.degraded[id] {
+			m.containerLogger(c.Name).Warn("sidecar not running", "containerID", id, "warning", warning)
+		}
+	}
+	for id := range m.degraded {
+		if c, ok := m.containers[id]; ok && !next[id] {
+			m.containerLogger(c.Name).Info("sidecars running again", "containerID", id)
+		}
+	}
+	m.degraded = next
+
+	SortContainers(degraded, SortByName, false)
+	return degraded
+}
+
+// restartSidecars starts the stopped services of each degraded container's
+// compose project. Failures are logged; the next Refresh reports the outcome.
+func (m *Manager) restartSidecars(ctx context.Context, degraded []*Container) {
+	for _, c := range degraded {
+		if c.ProjectPath == "" {
+			continue
+		}
+		logger := m.containerLogger(c.Name)
+		logger.Info("restarting stopped sidecars", "containerID", c.ID)
+		if err := m.runtime.ComposeStart(ctx, c.ProjectPath, composeProjectName(c)); err != nil {
+			logger.Error("failed to restart sidecars", "containerID", c.ID, "error", err)
+		}
+	}
+}
+
 // List returns all known containers sorted by name for stable display order.
 func (m *Manager) List() []*Container {
 	m.mu.RLock()
@@ -178,12 +317,39 @@ func (m *Manager) List() []*Container {
 	for _, c := range m.containers {
 		result = append(result, c)
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
-	})
+	SortContainers(result, SortByName, false)
 	return result
 }
 
+// ListAll returns the managed containers plus every container on the host
+// without the devagent.managed=true label, sorted by name. Unmanaged
+// containers are queried from the runtime on each call and have Unmanaged
+// set; devagent sidecars are excluded.
+func (m *Manager) ListAll(ctx context.Context) ([]*Container, error) {
+	all, err := m.runtime.ListAllContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	result := make([]*Container, 0, len(all))
+	for _, c := range m.containers {
+		result = append(result, c)
+	}
+	for i := range all {
+		c := all[i]
+		if _, known := m.containers[c.ID]; known || c.Labels[LabelManagedBy] == "true" {
+			continue
+		}
+		c.Unmanaged = true
+		result = append(result, &c)
+	}
+	m.mu.RUnlock()
+
+	SortContainers(result, SortByName, false)
+	return result, nil
+}
+
 // containerLogger returns a logger scoped to a specific container.
 // Falls back to base "container" scope if name is empty or logManager is nil.
 func (m *Manager) containerLogger(name string) *logging.ScopedLogger {
@@ -193,6 +359,38 @@ func (m *Manager) containerLogger(name string) *logging.ScopedLogger {
 	return m.logManager.For("container." + name)
 }
 
+// canceledCreateCleanupTimeout bounds the compose down run after a canceled
+// create; the create's own context is already done by then.
+const canceledCreateCleanupTimeout = 30 * time.Second
+
+// cleanupCanceledCreate tears down the compose project of a create whose
+// compose up was canceled. Failures are logged, not returned.
+func (m *Manager) cleanupCanceledCreate(logger *logging.ScopedLogger, projectDir, projectName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), canceledCreateCleanupTimeout)
+	defer cancel()
+	if err := m.runtime.ComposeDown(ctx, projectDir, projectName); err != nil {
+		logger.Warn("failed to clean up canceled create", "composeProject", projectName, "error", err)
+		return
+	}
+	logger.Info("cleaned up canceled create", "composeProject", projectName)
+}
+
+// composeUp runs compose up, forwarding the meaningful lines of its output
+// (see composeProgressLine) to progress when the runtime can stream them.
+func (m *Manager) composeUp(ctx context.Context, projectDir, projectName string, env map[string]string, progress func(string)) error {
+	streamer, ok := m.runtime.(interface {
+		ComposeUpWithProgress(ctx context.Context, projectDir string, projectName string, env map[string]string, onLine func(string)) error
+	})
+	if !ok {
+		return m.runtime.ComposeUp(ctx, projectDir, projectName, env)
+	}
+	return streamer.ComposeUpWithProgress(ctx, projectDir, projectName, env, func(line string) {
+		if msg, ok := composeProgressLine(line); ok {
+			progress(msg)
+		}
+	})
+}
+
 // reportProgress logs a progress message and notifies the OnProgress callback if set.
 func (m *Manager) reportProgress(logger *logging.ScopedLogger, callback ProgressCallback, step, status, msg string) {
 	logger.Info(msg, "step", step, "status", status)
@@ -219,11 +417,12 @@ func (m *Manager) refreshSidecars(allContainers []Container) {
 		composeProject := c.Labels[LabelComposeProject]
 
 		sidecar := &Sidecar{
-			ID:        c.ID,
-			Name:      c.Name,
-			Type:      sidecarType,
-			ParentRef: composeProject, // Compose project name for grouping
-			State:     c.State,
+			ID:         c.ID,
+			Name:       c.Name,
+			Type:       sidecarType,
+			ParentRef:  composeProject, // Compose project name for grouping
+			ProjectDir: composeProjectDir(c.Labels[LabelComposeWorkingDir]),
+			State:      c.State,
 		}
 		m.sidecars[c.ID] = sidecar
 	}
@@ -258,6 +457,18 @@ func (m *Manager) GetSidecarsForProject(projectPath string) []*Sidecar {
 	return result
 }
 
+// GetMounts returns the bind and volume mounts of a container (one runtime
+// inspect per call).
+func (m *Manager) GetMounts(ctx context.Context, containerID string) ([]MountInfo, error) {
+	return m.runtime.GetMounts(ctx, containerID)
+}
+
+// GetPorts returns the container's ports published on the host (one runtime
+// inspect per call).
+func (m *Manager) GetPorts(ctx context.Context, containerID string) ([]PortMapping, error) {
+	return m.runtime.GetPorts(ctx, containerID)
+}
+
 // GetContainerIsolationInfo returns isolation details for a container.
 // Combines data from Docker inspect, sidecar lookup, and proxy configuration.
 func (m *Manager) GetContainerIsolationInfo(ctx context.Context, c *Container) (*IsolationInfo, error) {
@@ -280,9 +491,10 @@ func (m *Manager) GetContainerIsolationInfo(ctx context.Context, c *Container) (
 		}
 	}
 
-	// Read allowlist from filter script if network is isolated
+	// Read allowlist from filter script if network is isolated, including
+	// the config domains the generated block extends it with
 	if info.NetworkIsolated {
-		allowlist, err := ReadAllowlistFromFilterScript(c.ProjectPath)
+		allowlist, err := readEnforcedAllowlist(c.ProjectPath)
 		if err == nil && allowlist != nil {
 			info.AllowedDomains = allowlist
 		}
@@ -308,6 +520,16 @@ func (m *Manager) RuntimePath() string {
 	return m.runtimePath
 }
 
+// RuntimeEnv returns the environment entries (e.g. DOCKER_HOST=ssh://...)
+// that point a runtime command started outside the Runtime, such as a
+// terminal attach, at the configured host. Nil when none is set.
+func (m *Manager) RuntimeEnv() []string {
+	if m.runtimeHost == "" {
+		return nil
+	}
+	return []string{config.RuntimeHostEnvVar(m.RuntimeName()) + "=" + m.runtimeHost}
+}
+
 // Get returns a container by ID.
 func (m *Manager) Get(id string) (*Container, bool) {
 	m.mu.RLock()
@@ -321,22 +543,86 @@ func (m *Manager) Get(id string) (*Container, bool) {
 // by Docker container name. Returns (nil, false) if no match is found.
 // pattern: Functional Core
 func (m *Manager) GetByNameOrID(ref string) (*Container, bool) {
+	if c, ok := m.Get(ref); ok {
+		return c, true
+	}
+	return m.GetByName(ref)
+}
+
+// GetByName returns the container with the exact Docker name.
+func (m *Manager) GetByName(name string) (*Container, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Fast path: exact ID match
-	if c, ok := m.containers[ref]; ok {
-		return c, true
+	for _, c := range m.containers {
+		if c.Name == name {
+			return c, true
+		}
 	}
+	return nil, false
+}
+
+// ErrAmbiguousRef is returned (wrapped) by Resolve when a prefix matches
+// more than one container.
+var ErrAmbiguousRef = errors.New("ambiguous container reference")
 
-	// Slow path: match by container name
+// Resolve looks up a container the way a human would name it: exact ID,
+// then exact name, then a prefix of exactly one container's ID or name.
+// A prefix matching several containers is an error wrapping ErrAmbiguousRef
+// that lists their names.
+func (m *Manager) Resolve(ref string) (*Container, error) {
+	if c, ok := m.GetByNameOrID(ref); ok {
+		return c, nil
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("%w: empty reference", ErrContainerNotFound)
+	}
+
+	m.mu.RLock()
+	var matches []*Container
 	for _, c := range m.containers {
-		if c.Name == ref {
-			return c, true
+		if strings.HasPrefix(c.ID, ref) || strings.HasPrefix(c.Name, ref) {
+			matches = append(matches, c)
 		}
 	}
+	m.mu.RUnlock()
 
-	return nil, false
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, ref)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, 0, len(matches))
+	for _, c := range matches {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousRef, ref, strings.Join(names, ", "))
+}
+
+// ErrInexactRef is returned by ResolveExact for a reference that only
+// abbreviates a container's name or ID.
+var ErrInexactRef = errors.New("container reference must be the full name or ID")
+
+// ShortIDLen is the length of the short container IDs `docker ps` shows, the
+// shortest ID prefix ResolveExact accepts.
+const ShortIDLen = 12
+
+// ResolveExact is Resolve for actions that stop, destroy or run something in
+// a container, where a short prefix such as "d" must not pick one by
+// accident: it accepts an exact ID or name, or a prefix of at least
+// ShortIDLen characters of exactly one container's ID. A shorter or name
+// prefix that Resolve would accept is an error wrapping ErrInexactRef.
+func (m *Manager) ResolveExact(ref string) (*Container, error) {
+	c, err := m.Resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+	if c.ID == ref || c.Name == ref || (len(ref) >= ShortIDLen && strings.HasPrefix(c.ID, ref)) {
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: %q abbreviates %s", ErrInexactRef, ref, c.Name)
 }
 
 // GetByComposeProject returns the container with the given compose project name, or nil.
@@ -351,7 +637,98 @@ func (m *Manager) GetByComposeProject(composeName string) *Container {
 	return nil
 }
 
+// Shutdown stops the running sidecars (e.g. the proxy) of compose projects
+// that CreateWithCompose brought up in this process, when
+// cfg.StopSidecarsOnExit is set, so repeated runs do not accumulate them.
+// Sidecars of projects discovered at startup are left alone, and nothing is
+// removed: the app containers keep running, and with network.auto_restart_proxy
+// the next run starts their proxy again. Errors are joined after trying every
+// project.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m.cfg == nil || !m.cfg.StopSidecarsOnExit {
+		return nil
+	}
+
+	m.mu.RLock()
+	services := make(map[string][]string) // compose project -> sidecar services
+	for _, s := range m.sidecars {
+		if _, ok := m.sessionProjects[s.ParentRef]; ok && s.State == StateRunning {
+			services[s.ParentRef] = append(services[s.ParentRef], s.Type)
+		}
+	}
+	dirs := make(map[string]string, len(services))
+	for name := range services {
+		dirs[name] = m.sessionProjects[name]
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		svcs := services[name]
+		slices.Sort(svcs)
+		svcs = slices.Compact(svcs)
+		m.logger.Info("stopping sidecars started this session", "project", name, "services", svcs)
+		if err := m.runtime.ComposeStop(ctx, dirs[name], name, svcs...); err != nil {
+			m.logger.Error("failed to stop sidecars", "project", name, "error", err)
+			errs = append(errs, fmt.Errorf("stop sidecars of %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ErrContainerExists is returned by CreateWithCompose when the project already
+// has a container under the same compose project. Set CreateOptions.Force to
+// create one anyway. It is an ErrAlreadyExists.
+var ErrContainerExists error = &kindError{msg: "project already has a container", kind: ErrAlreadyExists}
+
+// createComposeName is the compose project name a create uses: opts.Name if
+// provided (e.g. worktree-specific name), otherwise derived from the project
+// directory base name.
+func createComposeName(opts CreateOptions) string {
+	if opts.Name != "" {
+		return opts.Name
+	}
+	return SanitizeComposeName(filepath.Base(opts.ProjectPath))
+}
+
+// ExistingContainer returns the known container that CreateWithCompose would
+// refuse opts for with ErrContainerExists, or nil. It ignores opts.Force, so
+// callers (e.g. the web API answering 409 before queueing a build) apply the
+// same rule as the create itself.
+func (m *Manager) ExistingContainer(opts CreateOptions) *Container {
+	if opts.ProjectPath != "" {
+		if abs, err := filepath.Abs(opts.ProjectPath); err == nil {
+			opts.ProjectPath = abs
+		}
+	}
+	return m.existingContainerFor(opts.ProjectPath, createComposeName(opts))
+}
+
+// existingContainerFor returns a known container for projectPath that would
+// collide with a new compose project named composeName, or nil. Worktree
+// containers share the project root but use their own compose project, so
+// they do not collide with each other or with the main container.
+func (m *Manager) existingContainerFor(projectPath, composeName string) *Container {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, c := range m.containers {
+		if c.ProjectPath != projectPath {
+			continue
+		}
+		name := c.ComposeProject
+		if name == "" {
+			name = composeProjectName(c)
+		}
+		if name == composeName {
+			return c
+		}
+	}
+	return nil
+}
+
 // CreateWithCompose creates a new devcontainer using docker-compose orchestration.
+// It returns ErrContainerExists when the project already has a container,
+// unless opts.Force is set.
 func (m *Manager) CreateWithCompose(ctx context.Context, opts CreateOptions) (*Container, error) {
 	// Ensure ProjectPath is absolute (relative paths break Docker Compose volume mounts —
 	// Compose interprets "foo:/path" as named volume "foo" instead of bind mount "./foo")
@@ -363,6 +740,21 @@ func (m *Manager) CreateWithCompose(ctx context.Context, opts CreateOptions) (*C
 		opts.ProjectPath = absPath
 	}
 
+	composeName := createComposeName(opts)
+
+	if !opts.Force {
+		if existing := m.existingContainerFor(opts.ProjectPath, composeName); existing != nil {
+			return nil, fmt.Errorf("%w: %s (%s)", ErrContainerExists, existing.Name, opts.ProjectPath)
+		}
+	}
+	extraMounts, err := ParseMounts(opts.ExtraMounts)
+	if err != nil {
+		return nil, err
+	}
+
+	m.ops.Begin(opts.Name, OpCreate)
+	defer m.ops.End(opts.Name)
+
 	// Create scoped logger for this operation.
 	logger := m.containerLogger(opts.Name)
 
@@ -399,11 +791,28 @@ func (m *Manager) CreateWithCompose(ctx context.Context, opts CreateOptions) (*C
 	if _, err := os.Stat(composeFilePath); os.IsNotExist(err) {
 		reportProgress("files", "started", "Writing configuration files")
 
-		if err := m.composeGenerator.WriteToProject(opts.ProjectPath, opts.Template, composeResult.TemplateData); err != nil {
+		if m.keepsDevcontainerJSON(opts) {
+			logger.Info("keeping the project's devcontainer.json")
+			reportProgress("files", "started", "Keeping existing devcontainer.json")
+			err = m.composeGenerator.WriteToProjectExcept(opts.ProjectPath, opts.Template, composeResult.TemplateData, devcontainerJSON)
+		} else {
+			err = m.composeGenerator.WriteToProject(opts.ProjectPath, opts.Template, composeResult.TemplateData)
+		}
+		if err != nil {
 			return nil, fmt.Errorf("failed to write template files: %w", err)
 		}
+		if len(extraMounts) > 0 {
+			if err := writeComposeMounts(composeFilePath, extraMounts); err != nil {
+				return nil, fmt.Errorf("failed to add extra mounts: %w", err)
+			}
+		}
 
 		reportProgress("files", "completed", "Configuration files written")
+	} else if len(extraMounts) > 0 {
+		logger.Warn("extra mounts ignored: project has its own docker-compose.yml", "mounts", opts.ExtraMounts)
+	}
+	if _, _, err := m.writeConfigAllowlist(opts.ProjectPath, opts.Template); err != nil {
+		logger.Warn("failed to apply config allowlist", "error", err)
 	}
 	if _, err := os.Stat(composeFilePath); err != nil {
 		// Format error message to include filename for clarity
@@ -427,22 +836,27 @@ func (m *Manager) CreateWithCompose(ctx context.Context, opts CreateOptions) (*C
 		return nil, fmt.Errorf("failed to allocate ports: %w", err)
 	}
 
-	// Determine compose project name: use opts.Name if provided (e.g. worktree-specific name),
-	// otherwise derive from the project directory base name
-	composeName := opts.Name
-	if composeName == "" {
-		composeName = SanitizeComposeName(filepath.Base(opts.ProjectPath))
-	}
-
 	reportProgress("container", "started", "Starting devcontainer")
 
 	// Start devcontainer using direct compose up
-	if err := m.runtime.ComposeUp(ctx, opts.ProjectPath, composeName, allocatedPorts); err != nil {
+	if err := m.composeUp(ctx, opts.ProjectPath, composeName, allocatedPorts, func(msg string) {
+		reportProgress("container", "started", msg)
+	}); err != nil {
+		if ctx.Err() != nil {
+			// Canceled or timed out: compose up was killed part way, so remove
+			// whatever it created (proxy sidecar, network) rather than orphan it.
+			reportProgress("container", "failed", "Creation canceled")
+			m.cleanupCanceledCreate(logger, opts.ProjectPath, composeName)
+			return nil, fmt.Errorf("compose up canceled: %w", ctx.Err())
+		}
 		reportProgress("container", "failed", fmt.Sprintf("Failed to start: %v", err))
 		return nil, fmt.Errorf("compose up failed: %w", err)
 	}
 
 	logger.Info("devcontainer started via compose", "projectName", composeName)
+	m.mu.Lock()
+	m.sessionProjects[composeName] = opts.ProjectPath
+	m.mu.Unlock()
 	reportProgress("container", "completed", "Devcontainer started successfully")
 
 	// Refresh container list
@@ -469,9 +883,99 @@ func (m *Manager) CreateWithCompose(ctx context.Context, opts CreateOptions) (*C
 	container.ComposeProject = composeName
 	container.Ports = allocatedPorts
 
+	m.saveCreationSnapshot(ctx, logger, container, opts.Template, composeResult.TemplateData)
+
+	if tmpl := m.composeGenerator.GetTemplate(opts.Template); tmpl != nil && len(tmpl.InitialSessions) > 0 {
+		m.createInitialSessions(ctx, logger, opts.OnProgress, container.ID, tmpl.InitialSessions)
+	}
+
 	return container, nil
 }
 
+// keepsDevcontainerJSON reports whether a create leaves the project's existing
+// .devcontainer/devcontainer.json in place: requested by opts.UseExisting or
+// the template's use_existing_devcontainer, and the file is present.
+func (m *Manager) keepsDevcontainerJSON(opts CreateOptions) bool {
+	useExisting := opts.UseExisting
+	if tmpl := m.composeGenerator.GetTemplate(opts.Template); tmpl != nil && tmpl.UseExistingDevcontainer {
+		useExisting = true
+	}
+	if !useExisting {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(opts.ProjectPath, ".devcontainer", devcontainerJSON))
+	return err == nil
+}
+
+// saveCreationSnapshot records the generated files and isolation settings the
+// container was created with. Failures are logged; they do not fail the create.
+func (m *Manager) saveCreationSnapshot(ctx context.Context, logger *logging.ScopedLogger, c *Container, template string, data TemplateData) {
+	files, err := readSnapshotFiles(c.ProjectPath)
+	if err != nil {
+		logger.Warn("failed to read files for creation snapshot", "error", err)
+		return
+	}
+	snapshot := &CreationSnapshot{
+		ContainerID:    c.ID,
+		Name:           c.Name,
+		Template:       template,
+		ProjectPath:    c.ProjectPath,
+		ComposeProject: c.ComposeProject,
+		CreatedAt:      time.Now(),
+		Ports:          c.Ports,
+		Isolation: IsolationSnapshot{
+			ExtraAllowedDomains:     data.ExtraAllowedDomains,
+			ExtraPassthroughDomains: data.ExtraPassthroughDomains,
+		},
+		Files: files,
+	}
+	if info, err := m.GetContainerIsolationInfo(ctx, c); err != nil {
+		logger.Warn("failed to inspect isolation for creation snapshot", "error", err)
+	} else {
+		iso := &snapshot.Isolation
+		iso.DroppedCaps = info.DroppedCaps
+		iso.AddedCaps = info.AddedCaps
+		iso.MemoryLimit = info.MemoryLimit
+		iso.CPULimit = info.CPULimit
+		iso.PidsLimit = info.PidsLimit
+		iso.NetworkIsolated = info.NetworkIsolated
+		iso.NetworkName = info.NetworkName
+		iso.AllowedDomains = info.AllowedDomains
+	}
+	if err := writeSnapshot(snapshot); err != nil {
+		logger.Warn("failed to write creation snapshot", "error", err)
+	}
+}
+
+// GetCreationSnapshot returns the configuration snapshot recorded when the
+// container (by name or ID) was created. Returns ErrSnapshotNotFound if none exists.
+func (m *Manager) GetCreationSnapshot(ref string) (*CreationSnapshot, error) {
+	c, ok := m.GetByNameOrID(ref)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, ref)
+	}
+	return readSnapshot(c.ID)
+}
+
+// createInitialSessions creates the template's initial tmux sessions in a newly
+// created container, reporting each as a "session" progress step. A failed
+// session is reported and logged but does not fail container creation.
+func (m *Manager) createInitialSessions(ctx context.Context, logger *logging.ScopedLogger, onProgress ProgressCallback, containerID string, specs []config.SessionSpec) {
+	created := 0
+	for _, spec := range specs {
+		m.reportProgress(logger, onProgress, "session", "started", fmt.Sprintf("Creating session %s", spec.Name))
+		if err := m.tmuxClient.CreateSessionIn(ctx, containerID, spec.Name, spec.Cwd, spec.Command); err != nil {
+			m.reportProgress(logger, onProgress, "session", "failed", fmt.Sprintf("Failed to create session %s: %v", spec.Name, err))
+			continue
+		}
+		created++
+		m.reportProgress(logger, onProgress, "session", "completed", fmt.Sprintf("Session %s created", spec.Name))
+	}
+	if created > 0 {
+		m.notifyChange()
+	}
+}
+
 // composeProjectName returns the compose project name for a container.
 // Reads from Docker's com.docker.compose.project label (set by devcontainer CLI).
 // Falls back to the container name if label is missing (shouldn't happen for compose containers).
@@ -530,11 +1034,18 @@ func (m *Manager) startMissingProxyLogReaders() {
 // StartWithCompose starts a compose-based devcontainer using docker-compose start.
 // This is for containers created with CreateWithCompose().
 func (m *Manager) StartWithCompose(ctx context.Context, containerID string) error {
+	m.ops.Begin(containerID, OpStart)
+	defer m.ops.End(containerID)
+
 	m.mu.Lock()
 	c, ok := m.containers[containerID]
 	if !ok {
 		m.mu.Unlock()
-		return fmt.Errorf("container not found: %s", containerID)
+		return fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	if c.State == StateRunning {
+		m.mu.Unlock()
+		return ErrAlreadyRunning
 	}
 
 	if c.ProjectPath == "" {
@@ -555,6 +1066,7 @@ func (m *Manager) StartWithCompose(ctx context.Context, containerID string) erro
 
 	m.mu.Lock()
 	c.State = StateRunning
+	c.ExitCode = 0
 	m.mu.Unlock()
 
 	logger.Info("compose container started")
@@ -564,11 +1076,18 @@ func (m *Manager) StartWithCompose(ctx context.Context, containerID string) erro
 
 // StopWithCompose stops a compose-based devcontainer using docker-compose stop.
 func (m *Manager) StopWithCompose(ctx context.Context, containerID string) error {
+	m.ops.Begin(containerID, OpStop)
+	defer m.ops.End(containerID)
+
 	m.mu.Lock()
 	c, ok := m.containers[containerID]
 	if !ok {
 		m.mu.Unlock()
-		return fmt.Errorf("container not found: %s", containerID)
+		return fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	if c.State != StateRunning {
+		m.mu.Unlock()
+		return ErrNotRunning
 	}
 
 	if c.ProjectPath == "" {
@@ -589,6 +1108,7 @@ func (m *Manager) StopWithCompose(ctx context.Context, containerID string) error
 
 	m.mu.Lock()
 	c.State = StateStopped
+	c.ExitCode = 0
 	// Stop proxy log reader — container is no longer running
 	proxyLogPath := filepath.Join(c.ProjectPath, ".devcontainer", "containers", "proxy", "opt", "devagent-proxy", "logs", "requests.jsonl")
 	if cancel, ok := m.proxyLogCancels[proxyLogPath]; ok {
@@ -603,19 +1123,37 @@ func (m *Manager) StopWithCompose(ctx context.Context, containerID string) error
 }
 
 // DestroyWithCompose destroys a compose-based devcontainer using docker-compose down.
-// This removes both app and proxy containers, networks, and volumes.
+// This removes both app and proxy containers, networks, and volumes, and
+// purges the project's proxy configs (DestroyOptions.Purge). A protected
+// container is refused (ErrContainerProtected).
 func (m *Manager) DestroyWithCompose(ctx context.Context, containerID string) error {
+	return m.DestroyWithOptions(ctx, containerID, DestroyOptions{Purge: true})
+}
+
+// DestroyWithOptions destroys a compose-based devcontainer like
+// DestroyWithCompose, purging proxy configs only when opts.Purge is set. A
+// container protected by a do-not-delete note (IsProtected) is refused with
+// ErrContainerProtected unless opts.Force is set. It never touches the
+// project directory or its git worktree.
+func (m *Manager) DestroyWithOptions(ctx context.Context, containerID string, opts DestroyOptions) error {
+	m.ops.Begin(containerID, OpDestroy)
+	defer m.ops.End(containerID)
+
 	m.mu.Lock()
 	c, ok := m.containers[containerID]
 	if !ok {
 		m.mu.Unlock()
-		return fmt.Errorf("container not found: %s", containerID)
+		return fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
 	}
 
 	if c.ProjectPath == "" {
 		m.mu.Unlock()
 		return fmt.Errorf("container has no project path: %s", containerID)
 	}
+	if !opts.Force && m.IsProtected(c) {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrContainerProtected, c.Name)
+	}
 
 	// Stop proxy log reader if running (mutex already held)
 	proxyLogPath := filepath.Join(c.ProjectPath, ".devcontainer", "containers", "proxy", "opt", "devagent-proxy", "logs", "requests.jsonl")
@@ -637,9 +1175,15 @@ func (m *Manager) DestroyWithCompose(ctx context.Context, containerID string) er
 	}
 
 	// Clean up proxy config directories (same as legacy destroy)
-	if err := CleanupProxyConfigs(c.ProjectPath); err != nil {
-		logger.Warn("failed to cleanup proxy configs", "error", err)
-		// Continue - this is non-fatal
+	if opts.Purge {
+		if err := CleanupProxyConfigs(c.ProjectPath); err != nil {
+			logger.Warn("failed to cleanup proxy configs", "error", err)
+			// Continue - this is non-fatal
+		}
+	}
+
+	if err := removeSnapshot(containerID); err != nil {
+		logger.Warn("failed to remove creation snapshot", "error", err)
 	}
 
 	// Remove from containers map
@@ -674,12 +1218,41 @@ func (m *Manager) getContainerName(containerID string) string {
 	return ""
 }
 
-// CreateSession creates a tmux session inside a container.
+// runningContainer returns the known container containerID, or an error
+// wrapping ErrContainerNotFound, or ErrNotRunning.
+func (m *Manager) runningContainer(containerID string) (*Container, error) {
+	c, ok := m.Get(containerID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerID)
+	}
+	if !c.IsRunning() {
+		return nil, ErrNotRunning
+	}
+	return c, nil
+}
+
+// CreateSession creates a tmux session inside a running container. Returns an
+// error wrapping ErrContainerNotFound, ErrNotRunning or, when the container
+// already has a session of that name, ErrSessionExists.
 func (m *Manager) CreateSession(ctx context.Context, containerID, sessionName string) error {
-	containerName := m.getContainerName(containerID)
-	scopedLogger := m.containerLogger(containerName).With("containerID", containerID, "session", sessionName)
+	c, err := m.runningContainer(containerID)
+	if err != nil {
+		return err
+	}
+	scopedLogger := m.containerLogger(c.Name).With("containerID", containerID, "session", sessionName)
 	scopedLogger.Info("creating tmux session")
 
+	sessions, err := m.tmuxClient.ListSessions(ctx, containerID)
+	if err != nil {
+		scopedLogger.Error("failed to list sessions", "error", err)
+		return err
+	}
+	for _, s := range sessions {
+		if s.Name == sessionName {
+			return fmt.Errorf("%w: %s", ErrSessionExists, sessionName)
+		}
+	}
+
 	// Delegate to tmux.Client
 	if err := m.tmuxClient.CreateSession(ctx, containerID, sessionName); err != nil {
 		scopedLogger.Error("failed to create session", "error", err)
@@ -687,14 +1260,19 @@ func (m *Manager) CreateSession(ctx context.Context, containerID, sessionName st
 	}
 
 	scopedLogger.Info("session created")
+	m.TouchActivity(containerID)
 	m.notifyChange()
 	return nil
 }
 
-// KillSession destroys a tmux session inside a container.
+// KillSession destroys a tmux session inside a running container. Returns an
+// error wrapping ErrContainerNotFound or ErrNotRunning for such containers.
 func (m *Manager) KillSession(ctx context.Context, containerID, sessionName string) error {
-	containerName := m.getContainerName(containerID)
-	scopedLogger := m.containerLogger(containerName).With("containerID", containerID, "session", sessionName)
+	c, err := m.runningContainer(containerID)
+	if err != nil {
+		return err
+	}
+	scopedLogger := m.containerLogger(c.Name).With("containerID", containerID, "session", sessionName)
 	scopedLogger.Info("killing tmux session")
 
 	// Delegate to tmux.Client
@@ -725,6 +1303,22 @@ func (m *Manager) ListSessions(ctx context.Context, containerID string) ([]tmux.
 	return sessions, nil
 }
 
+// ListWindows lists the windows of a tmux session inside a container.
+func (m *Manager) ListWindows(ctx context.Context, containerID, sessionName string) ([]tmux.Window, error) {
+	containerName := m.getContainerName(containerID)
+	scopedLogger := m.containerLogger(containerName).With("containerID", containerID, "session", sessionName)
+	scopedLogger.Debug("listing tmux windows")
+
+	windows, err := m.tmuxClient.ListWindows(ctx, containerID, sessionName)
+	if err != nil {
+		scopedLogger.Error("failed to list windows", "error", err)
+		return nil, err
+	}
+
+	scopedLogger.Debug("windows listed", "count", len(windows))
+	return windows, nil
+}
+
 // CaptureSession captures pane content from a tmux session in a container.
 func (m *Manager) CaptureSession(ctx context.Context, containerID, sessionName string, opts tmux.CaptureOpts) (string, error) {
 	containerName := m.getContainerName(containerID)
@@ -770,16 +1364,99 @@ func (m *Manager) CursorPosition(ctx context.Context, containerID, sessionName s
 	return pos, nil
 }
 
-// SendToSession sends keystrokes to a tmux session in a container.
+// SendToSession sends keystrokes to a tmux session in a container, followed by Enter.
 func (m *Manager) SendToSession(ctx context.Context, containerID, sessionName, text string) error {
-	containerName := m.getContainerName(containerID)
-	scopedLogger := m.containerLogger(containerName).With("containerID", containerID, "session", sessionName)
-	scopedLogger.Info("sending keys to tmux session")
+	return m.SendKeys(ctx, containerID, sessionName, text, true)
+}
+
+// SendKeys sends keys to a tmux session in a container with tmux send-keys,
+// followed by Enter when enter is set. keys is passed to tmux as a single
+// literal argument, so neither shell metacharacters nor tmux key names in it
+// are interpreted. Returns an
+// error wrapping ErrContainerNotFound or ErrNotRunning for such containers.
+func (m *Manager) SendKeys(ctx context.Context, containerID, sessionName, keys string, enter bool) error {
+	c, err := m.runningContainer(containerID)
+	if err != nil {
+		return err
+	}
+	scopedLogger := m.containerLogger(c.Name).With("containerID", containerID, "session", sessionName)
+	scopedLogger.Info("sending keys to tmux session", "enter", enter)
 
-	if err := m.tmuxClient.SendKeys(ctx, containerID, sessionName, text); err != nil {
+	send := m.tmuxClient.TypeKeys
+	if enter {
+		send = m.tmuxClient.SendKeys
+	}
+	if err := send(ctx, containerID, sessionName, keys); err != nil {
 		scopedLogger.Error("failed to send keys", "error", err)
 		return err
 	}
 
+	m.TouchActivity(containerID)
 	return nil
 }
+
+// MaxExecOutput caps the stdout Exec keeps; the rest is discarded while the
+// command runs and the result is marked Truncated.
+const MaxExecOutput = 1 << 20
+
+// ExecResult is the outcome of a one-shot command run with Exec.
+type ExecResult struct {
+	Stdout    string
+	ExitCode  int
+	Truncated bool // stdout exceeded MaxExecOutput
+}
+
+// cappedExecer is implemented by runtimes that can bound a command's stdout
+// while it runs (the real Runtime; see ExecCapped).
+type cappedExecer interface {
+	ExecCapped(ctx context.Context, id, user string, cmd []string, limit int) (string, bool, error)
+}
+
+// Exec runs a one-shot, non-interactive command in a container, as user when
+// set and otherwise as the container's default user (the image's USER, not
+// necessarily root). At most MaxExecOutput bytes of stdout are kept. A
+// command that runs and exits non-zero is not an error: its exit code is
+// reported in the result. Errors are returned only when the command could not
+// be run (wrapping ErrContainerNotFound or ErrNotRunning for such containers)
+// or ctx ended first.
+func (m *Manager) Exec(ctx context.Context, containerID, user string, cmd []string) (ExecResult, error) {
+	if len(cmd) == 0 {
+		return ExecResult{}, fmt.Errorf("command is required")
+	}
+	c, err := m.runningContainer(containerID)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	scopedLogger := m.containerLogger(c.Name).With("containerID", containerID, "user", user)
+	scopedLogger.Info("executing command", "command", cmd[0])
+	m.TouchActivity(containerID)
+
+	var (
+		out       string
+		truncated bool
+	)
+	if capped, ok := m.runtime.(cappedExecer); ok {
+		out, truncated, err = capped.ExecCapped(ctx, containerID, user, cmd, MaxExecOutput)
+	} else {
+		if user == "" {
+			out, err = m.runtime.Exec(ctx, containerID, cmd)
+		} else {
+			out, err = m.runtime.ExecAs(ctx, containerID, user, cmd)
+		}
+		if len(out) > MaxExecOutput {
+			out, truncated = out[:MaxExecOutput], true
+		}
+	}
+	if err == nil {
+		return ExecResult{Stdout: out, Truncated: truncated}, nil
+	}
+
+	// *exec.ExitError (wrapped by the runtime executor) carries the exit code.
+	var exitErr interface{ ExitCode() int }
+	if ctx.Err() == nil && errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		scopedLogger.Debug("command exited non-zero", "exitCode", exitErr.ExitCode())
+		return ExecResult{Stdout: out, ExitCode: exitErr.ExitCode(), Truncated: truncated}, nil
+	}
+	scopedLogger.Error("failed to execute command", "error", err)
+	return ExecResult{}, err
+}
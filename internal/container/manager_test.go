@@ -2,10 +2,12 @@ package container
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -16,6 +18,9 @@ import (
 type mockRuntime struct {
 	containers []Container
 	listErr    error
+	listFails  int         // number of initial ListContainers calls that fail with listErr
+	unmanaged  []Container // extra containers returned only by ListAllContainers
+	listCalls  int
 
 	// Compose operations
 	composeUpCalled     string            // projectDir
@@ -27,24 +32,73 @@ type mockRuntime struct {
 	composeStartErr     error
 	composeStopCalled   string
 	composeStopProject  string
+	composeStopServices []string
+	inspectRaw          json.RawMessage
+	inspectRawCalls     int
 	composeStopErr      error
 	composeDownCalled   string
 	composeDownProject  string
 	composeDownErr      error
+	composeDownProjects []string // every project passed to ComposeDown, in order
+
+	// ExecAs calls (container ID, user, cmd) in order; execAsErr, if set,
+	// decides each call's error and execAsOutput each call's output
+	execAsIDs    []string
+	execAsCalls  [][]string
+	execAsUsers  []string
+	execAsErr    func(cmd []string) error
+	execAsOutput func(id string, cmd []string) string
+
+	// Exec calls (container ID, cmd) in order, and the result every call returns
+	execIDs    []string
+	execCalls  [][]string
+	execOutput string
+	execErr    error
+
+	composeRestartProject  string
+	composeRestartServices []string
+	composeRestartErr      error
+
+	// Logs calls (container ID, tail) and the output every call returns
+	logsIDs    []string
+	logsTails  []int
+	logsOutput string
+}
+
+func (m *mockRuntime) Logs(ctx context.Context, id string, tail int) (string, error) {
+	m.logsIDs = append(m.logsIDs, id)
+	m.logsTails = append(m.logsTails, tail)
+	return m.logsOutput, nil
 }
 
 func (m *mockRuntime) ListContainers(ctx context.Context) ([]Container, error) {
-	if m.listErr != nil {
+	m.listCalls++
+	if m.listErr != nil && (m.listFails == 0 || m.listCalls <= m.listFails) {
 		return nil, m.listErr
 	}
 	return m.containers, nil
 }
 
+func (m *mockRuntime) ListAllContainers(ctx context.Context) ([]Container, error) {
+	return slices.Concat(m.containers, m.unmanaged), nil
+}
+
 func (m *mockRuntime) Exec(ctx context.Context, id string, cmd []string) (string, error) {
-	return "", nil
+	m.execIDs = append(m.execIDs, id)
+	m.execCalls = append(m.execCalls, cmd)
+	return m.execOutput, m.execErr
 }
 
 func (m *mockRuntime) ExecAs(ctx context.Context, id string, user string, cmd []string) (string, error) {
+	m.execAsIDs = append(m.execAsIDs, id)
+	m.execAsCalls = append(m.execAsCalls, cmd)
+	m.execAsUsers = append(m.execAsUsers, user)
+	if m.execAsErr != nil {
+		return "", m.execAsErr(cmd)
+	}
+	if m.execAsOutput != nil {
+		return m.execAsOutput(id, cmd), nil
+	}
 	return "", nil
 }
 
@@ -52,6 +106,19 @@ func (m *mockRuntime) InspectContainer(ctx context.Context, id string) (Containe
 	return StateRunning, nil
 }
 
+func (m *mockRuntime) InspectRaw(ctx context.Context, id string) (json.RawMessage, error) {
+	m.inspectRawCalls++
+	return m.inspectRaw, nil
+}
+
+func (m *mockRuntime) GetMounts(ctx context.Context, id string) ([]MountInfo, error) {
+	return nil, nil
+}
+
+func (m *mockRuntime) GetPorts(ctx context.Context, id string) ([]PortMapping, error) {
+	return nil, nil
+}
+
 func (m *mockRuntime) GetIsolationInfo(ctx context.Context, id string) (*IsolationInfo, error) {
 	return &IsolationInfo{}, nil
 }
@@ -69,18 +136,26 @@ func (m *mockRuntime) ComposeStart(ctx context.Context, projectDir string, proje
 	return m.composeStartErr
 }
 
-func (m *mockRuntime) ComposeStop(ctx context.Context, projectDir string, projectName string) error {
+func (m *mockRuntime) ComposeStop(ctx context.Context, projectDir string, projectName string, services ...string) error {
 	m.composeStopCalled = projectDir
 	m.composeStopProject = projectName
+	m.composeStopServices = services
 	return m.composeStopErr
 }
 
 func (m *mockRuntime) ComposeDown(ctx context.Context, projectDir string, projectName string) error {
 	m.composeDownCalled = projectDir
 	m.composeDownProject = projectName
+	m.composeDownProjects = append(m.composeDownProjects, projectName)
 	return m.composeDownErr
 }
 
+func (m *mockRuntime) ComposeRestart(ctx context.Context, projectDir string, projectName string, services ...string) error {
+	m.composeRestartProject = projectName
+	m.composeRestartServices = services
+	return m.composeRestartErr
+}
+
 func TestList_Empty(t *testing.T) {
 	mock := &mockRuntime{containers: []Container{}}
 	mgr := NewManager(ManagerOptions{Runtime: mock})
@@ -145,6 +220,36 @@ func TestList_ReturnsDeterministicOrder(t *testing.T) {
 	}
 }
 
+func TestListAll_IncludesUnmanaged(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{
+			{ID: "aaa", Name: "alpha", State: StateRunning, Labels: map[string]string{LabelManagedBy: "true"}},
+		},
+		unmanaged: []Container{
+			{ID: "zzz", Name: "postgres", State: StateRunning, Labels: map[string]string{}},
+		},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if got := mgr.List(); len(got) != 1 {
+		t.Fatalf("List() returned %d containers, want 1 (managed only)", len(got))
+	}
+
+	all, err := mgr.ListAll(context.Background())
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "alpha" || all[1].Name != "postgres" {
+		t.Fatalf("ListAll() = %v, want [alpha postgres]", all)
+	}
+	if all[0].Unmanaged || !all[1].Unmanaged {
+		t.Errorf("Unmanaged = [%v %v], want [false true]", all[0].Unmanaged, all[1].Unmanaged)
+	}
+}
+
 func TestGet_Found(t *testing.T) {
 	mock := &mockRuntime{
 		containers: []Container{
@@ -189,6 +294,47 @@ func TestRefresh_Error(t *testing.T) {
 	}
 }
 
+func TestRefresh_RetriesTransientListFailure(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{{ID: "abc123", Name: "test"}},
+		listErr:    errors.New("docker ps: connection reset"),
+		listFails:  1,
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v, want success after retry", err)
+	}
+	if mock.listCalls != 2 {
+		t.Errorf("ListContainers calls = %d, want 2", mock.listCalls)
+	}
+	if _, ok := mgr.Get("abc123"); !ok {
+		t.Error("container should be listed after a successful retry")
+	}
+}
+
+func TestRefresh_GivesUpAfterRetries(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{{ID: "abc123", Name: "test"}},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("initial Refresh() error = %v", err)
+	}
+
+	mock.listErr = errors.New("docker not running")
+	mock.listCalls = 0
+	if err := mgr.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() error = nil, want error after retries are exhausted")
+	}
+	if mock.listCalls != refreshRetries+1 {
+		t.Errorf("ListContainers calls = %d, want %d", mock.listCalls, refreshRetries+1)
+	}
+	if _, ok := mgr.Get("abc123"); !ok {
+		t.Error("a failed Refresh should keep the last known containers")
+	}
+}
+
 func TestManager_GetSidecarsForProject(t *testing.T) {
 	mock := &mockRuntime{}
 	mgr := NewManager(ManagerOptions{Runtime: mock})
@@ -299,6 +445,72 @@ func TestManager_RefreshSidecars(t *testing.T) {
 	}
 }
 
+// sidecarReconcileContainers returns a running app and a stopped app, each with
+// a proxy sidecar in the given state.
+func sidecarReconcileContainers(proxyState ContainerState) []Container {
+	return []Container{
+		{ID: "app-1", Name: "alpha-app-1", ProjectPath: "/src/alpha", State: StateRunning,
+			Labels: map[string]string{LabelComposeProject: "alpha"}},
+		{ID: "proxy-1", Name: "alpha-proxy-1", State: proxyState,
+			Labels: map[string]string{LabelSidecarType: "proxy", LabelComposeProject: "alpha"}},
+		{ID: "app-2", Name: "bravo-app-1", ProjectPath: "/src/bravo", State: StateStopped,
+			Labels: map[string]string{LabelComposeProject: "bravo"}},
+		{ID: "proxy-2", Name: "bravo-proxy-1", State: StateStopped,
+			Labels: map[string]string{LabelSidecarType: "proxy", LabelComposeProject: "bravo"}},
+	}
+}
+
+// TestRefresh_FlagsStoppedProxySidecar verifies that Refresh flags a running
+// container whose proxy sidecar is stopped, but not a stopped container.
+func TestRefresh_FlagsStoppedProxySidecar(t *testing.T) {
+	mock := &mockRuntime{containers: sidecarReconcileContainers(StateStopped)}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	running, _ := mgr.Get("app-1")
+	if running.SidecarWarning == "" {
+		t.Error("running container with stopped proxy should be flagged")
+	}
+	if !strings.Contains(running.SidecarWarning, "proxy") {
+		t.Errorf("SidecarWarning = %q, want it to name the proxy sidecar", running.SidecarWarning)
+	}
+	stopped, _ := mgr.Get("app-2")
+	if stopped.SidecarWarning != "" {
+		t.Errorf("stopped container flagged: %q", stopped.SidecarWarning)
+	}
+	if mock.composeStartCalled != "" {
+		t.Error("sidecar should not be restarted without network.auto_restart_proxy")
+	}
+
+	// Proxy comes back: the warning clears
+	mock.containers = sidecarReconcileContainers(StateRunning)
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if c, _ := mgr.Get("app-1"); c.SidecarWarning != "" {
+		t.Errorf("SidecarWarning = %q after proxy recovered, want empty", c.SidecarWarning)
+	}
+}
+
+// TestRefresh_AutoRestartsStoppedProxySidecar verifies the compose project of a
+// degraded container is started when network.auto_restart_proxy is set.
+func TestRefresh_AutoRestartsStoppedProxySidecar(t *testing.T) {
+	mock := &mockRuntime{containers: sidecarReconcileContainers(StateStopped)}
+	cfg := &config.Config{Network: config.NetworkConfig{AutoRestartProxy: true}}
+	mgr := NewManager(ManagerOptions{Config: cfg, Runtime: mock})
+
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if mock.composeStartCalled != "/src/alpha" || mock.composeStartProject != "alpha" {
+		t.Errorf("ComposeStart(%q, %q), want (/src/alpha, alpha)", mock.composeStartCalled, mock.composeStartProject)
+	}
+}
+
 func TestComposeGenerator_GeneratesAndWritesFiles(t *testing.T) {
 	projectDir := t.TempDir()
 
@@ -494,6 +706,32 @@ func TestDestroyWithCompose_CallsRuntimeCompose(t *testing.T) {
 	}
 }
 
+// TestDestroyWithOptions_PurgeOnly verifies that proxy configs are removed
+// only when Purge is set, and that the project directory is left alone.
+func TestDestroyWithOptions_PurgeOnly(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	projectDir := t.TempDir()
+	certDir, err := GetProxyCertDir(projectDir)
+	if err != nil {
+		t.Fatalf("GetProxyCertDir error = %v", err)
+	}
+
+	mgr := NewManager(ManagerOptions{Runtime: &mockRuntime{}})
+	for _, purge := range []bool{false, true} {
+		mgr.containers["test-id"] = &Container{ID: "test-id", Name: "test-container", ProjectPath: projectDir, State: StateStopped}
+		if err := mgr.DestroyWithOptions(context.Background(), "test-id", DestroyOptions{Purge: purge}); err != nil {
+			t.Fatalf("DestroyWithOptions(purge=%v) error = %v", purge, err)
+		}
+		_, statErr := os.Stat(certDir)
+		if gone := os.IsNotExist(statErr); gone != purge {
+			t.Errorf("purge=%v: proxy cert dir removed = %v", purge, gone)
+		}
+		if _, err := os.Stat(projectDir); err != nil {
+			t.Errorf("purge=%v: project dir stat error = %v", purge, err)
+		}
+	}
+}
+
 func TestStartWithCompose_NotFound(t *testing.T) {
 	mock := &mockRuntime{}
 	mgr := NewManager(ManagerOptions{Runtime: mock})
@@ -564,6 +802,83 @@ func TestManager_ConcurrentRefreshAndList(t *testing.T) {
 	<-done
 }
 
+func TestResolve(t *testing.T) {
+	mgr := NewManager(ManagerOptions{Runtime: &mockRuntime{}})
+	mgr.containers["abc123"] = &Container{ID: "abc123", Name: "myproject-app-1"}
+	mgr.containers["abd456"] = &Container{ID: "abd456", Name: "myproject-worker-1"}
+	mgr.containers["f00"] = &Container{ID: "f00", Name: "abc"} // name equals another ID's prefix
+
+	tests := []struct {
+		ref     string
+		wantID  string
+		wantErr error
+	}{
+		{ref: "abc123", wantID: "abc123"},             // exact ID
+		{ref: "myproject-worker-1", wantID: "abd456"}, // exact name
+		{ref: "abc", wantID: "f00"},                   // exact name beats ID prefix
+		{ref: "abd", wantID: "abd456"},                // unique ID prefix
+		{ref: "myproject-w", wantID: "abd456"},        // unique name prefix
+		{ref: "myproject", wantErr: ErrAmbiguousRef},
+		{ref: "ab", wantErr: ErrAmbiguousRef},
+		{ref: "zzz", wantErr: ErrContainerNotFound},
+		{ref: "", wantErr: ErrContainerNotFound},
+	}
+	for _, tt := range tests {
+		c, err := mgr.Resolve(tt.ref)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || c.ID != tt.wantID {
+			t.Errorf("Resolve(%q) = %v, %v; want %s", tt.ref, c, err, tt.wantID)
+		}
+	}
+
+	if _, err := mgr.Resolve("myproject"); err == nil || !strings.Contains(err.Error(), "myproject-app-1, myproject-worker-1") {
+		t.Errorf("ambiguous error = %v, want both names listed", err)
+	}
+	if c, ok := mgr.GetByName("myproject-app-1"); !ok || c.ID != "abc123" {
+		t.Errorf("GetByName() = %v, %v; want abc123", c, ok)
+	}
+	if _, ok := mgr.GetByName("myproject"); ok {
+		t.Error("GetByName() should not match a prefix")
+	}
+}
+
+func TestResolveExact(t *testing.T) {
+	mgr := NewManager(ManagerOptions{Runtime: &mockRuntime{}})
+	mgr.containers["d1e2f3a4b5c6d7e8"] = &Container{ID: "d1e2f3a4b5c6d7e8", Name: "devbox"}
+	mgr.containers["0a1b2c3d4e5f6a7b"] = &Container{ID: "0a1b2c3d4e5f6a7b", Name: "api"}
+
+	tests := []struct {
+		ref     string
+		wantID  string
+		wantErr error
+	}{
+		{ref: "d1e2f3a4b5c6d7e8", wantID: "d1e2f3a4b5c6d7e8"}, // exact ID
+		{ref: "api", wantID: "0a1b2c3d4e5f6a7b"},              // exact name
+		{ref: "d1e2f3a4b5c6", wantID: "d1e2f3a4b5c6d7e8"},     // short ID
+		{ref: "d", wantErr: ErrInexactRef},
+		{ref: "d1e2f3a4b5c", wantErr: ErrInexactRef}, // one short of ShortIDLen
+		{ref: "dev", wantErr: ErrInexactRef},
+		{ref: "zzz", wantErr: ErrContainerNotFound},
+	}
+	for _, tt := range tests {
+		c, err := mgr.ResolveExact(tt.ref)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResolveExact(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || c.ID != tt.wantID {
+			t.Errorf("ResolveExact(%q) = %v, %v; want %s", tt.ref, c, err, tt.wantID)
+		}
+	}
+}
+
 func TestGetByNameOrID_MatchesByID(t *testing.T) {
 	mock := &mockRuntime{}
 	mgr := NewManager(ManagerOptions{Runtime: mock})
@@ -765,7 +1080,283 @@ func TestCreateWithCompose_CallsComposeUpWithCorrectArgs(t *testing.T) {
 	}
 }
 
+// TestCreateWithCompose_RefusesExistingContainer verifies that creating a
+// container for a project that already has one returns ErrContainerExists
+// without running compose up, and that Force creates it anyway.
+func TestCreateWithCompose_RefusesExistingContainer(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	ctx := context.Background()
+	if err := mgr.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	opts := CreateOptions{
+		ProjectPath: projectDir,
+		Template:    "default",
+		Name:        "test-container",
+	}
+
+	_, err := mgr.CreateWithCompose(ctx, opts)
+	if !errors.Is(err, ErrContainerExists) {
+		t.Fatalf("CreateWithCompose error = %v, want ErrContainerExists", err)
+	}
+	if mock.composeUpCalled != "" {
+		t.Errorf("compose up ran for %q, want it skipped", mock.composeUpCalled)
+	}
+
+	// A worktree-style compose project for the same path does not collide.
+	worktreeOpts := opts
+	worktreeOpts.Name = "test-container-feature"
+	if _, err := mgr.CreateWithCompose(ctx, worktreeOpts); err != nil {
+		t.Fatalf("CreateWithCompose for worktree name failed: %v", err)
+	}
+
+	opts.Force = true
+	if _, err := mgr.CreateWithCompose(ctx, opts); err != nil {
+		t.Fatalf("CreateWithCompose with Force failed: %v", err)
+	}
+	if mock.composeUpProject != opts.Name {
+		t.Errorf("compose up project = %q, want %q", mock.composeUpProject, opts.Name)
+	}
+}
+
+// TestShutdown_StopsOnlySessionSidecars verifies that Shutdown stops the
+// sidecars of projects created in this process and leaves those of projects
+// that were already running at startup alone.
+func TestShutdown_StopsOnlySessionSidecars(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	sidecar := func(id, project string) Container {
+		return Container{ID: id, Name: project + "-proxy-1", State: StateRunning, Labels: map[string]string{
+			LabelSidecarType:    "proxy",
+			LabelComposeProject: project,
+		}}
+	}
+	mock.containers = append(mock.containers, sidecar("old-proxy", "preexisting"), sidecar("new-proxy", "fresh"))
+	ctx := context.Background()
+	if err := mgr.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if _, err := mgr.CreateWithCompose(ctx, CreateOptions{ProjectPath: projectDir, Template: "default", Name: "fresh"}); err != nil {
+		t.Fatalf("CreateWithCompose failed: %v", err)
+	}
+
+	// Disabled by default
+	if err := mgr.Shutdown(ctx); err != nil || mock.composeStopProject != "" {
+		t.Fatalf("Shutdown without stop_sidecars_on_exit = %v, stopped %q; want nothing stopped", err, mock.composeStopProject)
+	}
+
+	mgr.cfg.StopSidecarsOnExit = true
+	if err := mgr.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if mock.composeStopProject != "fresh" || mock.composeStopCalled != projectDir {
+		t.Errorf("stopped project %q in %q, want fresh in %q", mock.composeStopProject, mock.composeStopCalled, projectDir)
+	}
+	if !slices.Equal(mock.composeStopServices, []string{"proxy"}) {
+		t.Errorf("stopped services = %v, want only [proxy]", mock.composeStopServices)
+	}
+}
+
+// blockingUpRuntime is a mockRuntime whose compose up runs until its context
+// is canceled, signalling started once it is running.
+type blockingUpRuntime struct {
+	*mockRuntime
+	started chan struct{}
+}
+
+func (m *blockingUpRuntime) ComposeUp(ctx context.Context, projectDir string, projectName string, env map[string]string) error {
+	close(m.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// TestCreateWithCompose_CancelTearsDownProject verifies that canceling the
+// create's context stops compose up and runs compose down for the project, so
+// a partially created proxy sidecar and network are not orphaned.
+func TestCreateWithCompose_CancelTearsDownProject(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	runtime := &blockingUpRuntime{mockRuntime: mock, started: make(chan struct{})}
+	mgr.runtime = runtime
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := mgr.CreateWithCompose(ctx, CreateOptions{ProjectPath: projectDir, Template: "default", Name: "test-container"})
+		done <- err
+	}()
+
+	<-runtime.started
+	cancel()
+	err := <-done
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateWithCompose() error = %v, want context.Canceled", err)
+	}
+	if !slices.Equal(mock.composeDownProjects, []string{"test-container"}) {
+		t.Errorf("ComposeDown projects = %v, want [test-container]", mock.composeDownProjects)
+	}
+	if mgr.Operations().Action("test-container") != "" {
+		t.Error("create operation should end after cancel")
+	}
+}
+
+// TestCreateWithCompose_CreatesInitialSessions verifies that the template's initial
+// sessions are created via ExecAs after compose up, each reported as a progress step,
+// and that a failing session does not fail the create.
+func TestCreateWithCompose_CreatesInitialSessions(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	mgr.SetTemplates([]config.Template{{
+		Name: "default",
+		Path: mgr.composeGenerator.GetTemplate("default").Path,
+		InitialSessions: []config.SessionSpec{
+			{Name: "dev", Cwd: "/workspace", Command: "make watch"},
+			{Name: "broken"},
+			{Name: "agent"},
+		},
+	}})
+	mock.execAsErr = func(cmd []string) error {
+		if slices.Contains(cmd, "broken") {
+			return errors.New("tmux failed")
+		}
+		return nil
+	}
+
+	var steps []ProgressStep
+	_, err := mgr.CreateWithCompose(context.Background(), CreateOptions{
+		ProjectPath: projectDir,
+		Template:    "default",
+		Name:        "test-container",
+		OnProgress:  func(s ProgressStep) { steps = append(steps, s) },
+	})
+	if err != nil {
+		t.Fatalf("CreateWithCompose failed: %v", err)
+	}
+
+	wantCmds := [][]string{
+		{"tmux", "-u", "new-session", "-d", "-s", "dev", "-c", "/workspace", "make watch"},
+		{"tmux", "-u", "new-session", "-d", "-s", "broken"},
+		{"tmux", "-u", "new-session", "-d", "-s", "agent"},
+	}
+	if len(mock.execAsCalls) != len(wantCmds) {
+		t.Fatalf("ExecAs calls = %v, want %v", mock.execAsCalls, wantCmds)
+	}
+	for i, want := range wantCmds {
+		if !slices.Equal(mock.execAsCalls[i], want) {
+			t.Errorf("ExecAs call %d = %v, want %v", i, mock.execAsCalls[i], want)
+		}
+	}
+
+	var sessionStatuses []string
+	for _, s := range steps {
+		if s.Step == "session" {
+			sessionStatuses = append(sessionStatuses, s.Status)
+		}
+	}
+	wantStatuses := []string{"started", "completed", "started", "failed", "started", "completed"}
+	if !slices.Equal(sessionStatuses, wantStatuses) {
+		t.Errorf("session step statuses = %v, want %v", sessionStatuses, wantStatuses)
+	}
+}
+
 // TestCreateWithCompose_SanitizesProjectName verifies that the compose project name
+// TestCreateWithCompose_WritesCreationSnapshot verifies that creation records the
+// generated files, and that the snapshot is retrievable until the container is destroyed.
+func TestCreateWithCompose_WritesCreationSnapshot(t *testing.T) {
+	mgr, _, projectDir := setupCreateWithComposeTest(t)
+
+	c, err := mgr.CreateWithCompose(context.Background(), CreateOptions{
+		ProjectPath: projectDir,
+		Template:    "default",
+		Name:        "test-container",
+	})
+	if err != nil {
+		t.Fatalf("CreateWithCompose failed: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(projectDir, ".devcontainer", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("reading compose file: %v", err)
+	}
+
+	// Later edits to the project's files must not affect the snapshot.
+	if err := os.WriteFile(filepath.Join(projectDir, ".devcontainer", "docker-compose.yml"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("rewriting compose file: %v", err)
+	}
+
+	snap, err := mgr.GetCreationSnapshot("test-container")
+	if err != nil {
+		t.Fatalf("GetCreationSnapshot() error = %v", err)
+	}
+	if snap.ContainerID != c.ID || snap.Template != "default" || snap.ComposeProject != "test-container" {
+		t.Errorf("snapshot = {ID:%q Template:%q ComposeProject:%q}, want {%q default test-container}",
+			snap.ContainerID, snap.Template, snap.ComposeProject, c.ID)
+	}
+	if snap.Files["docker-compose.yml"] != string(compose) {
+		t.Errorf("snapshot docker-compose.yml = %q, want %q", snap.Files["docker-compose.yml"], compose)
+	}
+
+	if err := mgr.DestroyWithCompose(context.Background(), c.ID); err != nil {
+		t.Fatalf("DestroyWithCompose failed: %v", err)
+	}
+	if _, err := readSnapshot(c.ID); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("readSnapshot() after destroy error = %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+// TestCreateWithCompose_UseExistingKeepsDevcontainerJSON verifies that
+// UseExisting leaves a project's own devcontainer.json untouched while the
+// template's docker-compose.yml is still written, and that without it the
+// file is overwritten.
+func TestCreateWithCompose_UseExistingKeepsDevcontainerJSON(t *testing.T) {
+	for _, useExisting := range []bool{true, false} {
+		mgr, _, projectDir := setupCreateWithComposeTest(t)
+		devDir := filepath.Join(projectDir, ".devcontainer")
+		if err := os.Remove(filepath.Join(devDir, "docker-compose.yml")); err != nil {
+			t.Fatalf("removing compose file: %v", err)
+		}
+		custom := `{"name": "custom"}`
+		if err := os.WriteFile(filepath.Join(devDir, "devcontainer.json"), []byte(custom), 0644); err != nil {
+			t.Fatalf("writing devcontainer.json: %v", err)
+		}
+
+		_, err := mgr.CreateWithCompose(context.Background(), CreateOptions{
+			ProjectPath: projectDir,
+			Template:    "default",
+			Name:        "test-container",
+			UseExisting: useExisting,
+		})
+		if err != nil {
+			t.Fatalf("CreateWithCompose(UseExisting=%v) failed: %v", useExisting, err)
+		}
+
+		got, err := os.ReadFile(filepath.Join(devDir, "devcontainer.json"))
+		if err != nil {
+			t.Fatalf("reading devcontainer.json: %v", err)
+		}
+		if kept := string(got) == custom; kept != useExisting {
+			t.Errorf("UseExisting=%v: devcontainer.json = %q, kept = %v", useExisting, got, kept)
+		}
+		if _, err := os.Stat(filepath.Join(devDir, "docker-compose.yml")); err != nil {
+			t.Errorf("UseExisting=%v: docker-compose.yml not written: %v", useExisting, err)
+		}
+	}
+}
+
+func TestGetCreationSnapshot_NotFound(t *testing.T) {
+	mgr, _, _ := setupCreateWithComposeTest(t)
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if _, err := mgr.GetCreationSnapshot("test-container-id"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("GetCreationSnapshot() error = %v, want ErrSnapshotNotFound", err)
+	}
+	if _, err := mgr.GetCreationSnapshot("../../etc/passwd"); err == nil || errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("GetCreationSnapshot(unknown) error = %v, want container not found", err)
+	}
+}
+
 // is correctly sanitized from the project path.
 func TestCreateWithCompose_SanitizesProjectName(t *testing.T) {
 	mgr, mock, projectDir := setupCreateWithComposeTest(t)
@@ -978,3 +1569,112 @@ func TestCreateWithCompose_WorktreeComposeProjNaming(t *testing.T) {
 		t.Errorf("Expected ComposeUp with projectName %q, got %q", opts.Name, mock.composeUpProject)
 	}
 }
+
+func TestSendKeys_PassesKeysAsSingleArgument(t *testing.T) {
+	keys := `echo "$HOME"; rm -rf /tmp/x`
+	tests := []struct {
+		name  string
+		enter bool
+		want  [][]string
+	}{
+		{
+			name:  "with enter",
+			enter: true,
+			want: [][]string{
+				{"tmux", "send-keys", "-l", "-t", "dev", "--", keys},
+				{"tmux", "send-keys", "-t", "dev", "Enter"},
+			},
+		},
+		{
+			name: "without enter",
+			want: [][]string{{"tmux", "send-keys", "-l", "-t", "dev", "--", keys}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockRuntime{}
+			mgr := NewManager(ManagerOptions{Runtime: mock})
+			mgr.containers["c1"] = &Container{ID: "c1", Name: "app", State: StateRunning}
+
+			if err := mgr.SendKeys(context.Background(), "c1", "dev", keys, tt.enter); err != nil {
+				t.Fatalf("SendKeys() error = %v", err)
+			}
+			if len(mock.execAsCalls) != len(tt.want) {
+				t.Fatalf("ExecAs calls = %q, want %q", mock.execAsCalls, tt.want)
+			}
+			for i, want := range tt.want {
+				if !slices.Equal(mock.execAsCalls[i], want) {
+					t.Errorf("ExecAs call %d = %q, want %q", i, mock.execAsCalls[i], want)
+				}
+			}
+		})
+	}
+}
+
+// exitCodeError mimics *exec.ExitError as wrapped by the runtime executor.
+type exitCodeError struct{ code int }
+
+func (e exitCodeError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e exitCodeError) ExitCode() int { return e.code }
+
+func TestExec_ReportsExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		execErr  error
+		wantCode int
+		wantErr  bool
+	}{
+		{name: "success"},
+		{name: "non-zero exit", execErr: fmt.Errorf("%w: not a git repository", exitCodeError{128}), wantCode: 128},
+		{name: "runtime failure", execErr: errors.New("no such container"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockRuntime{execOutput: "out", execErr: tt.execErr}
+			mgr := NewManager(ManagerOptions{Runtime: mock})
+			mgr.containers["c1"] = &Container{ID: "c1", Name: "app", State: StateRunning}
+
+			result, err := mgr.Exec(context.Background(), "c1", "", []string{"git", "status"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (result.Stdout != "out" || result.ExitCode != tt.wantCode) {
+				t.Errorf("Exec() = %+v, want stdout %q exit code %d", result, "out", tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExec_RunsAsUser(t *testing.T) {
+	mock := &mockRuntime{}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	mgr.containers["c1"] = &Container{ID: "c1", Name: "app", State: StateRunning}
+
+	if _, err := mgr.Exec(context.Background(), "c1", "vscode", []string{"id"}); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if !slices.Equal(mock.execAsUsers, []string{"vscode"}) {
+		t.Errorf("ExecAs users = %v, want [vscode]", mock.execAsUsers)
+	}
+}
+
+func TestInspectRaw_CachesWithinTTL(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{{ID: "abc123", Name: "app", State: StateRunning}},
+		inspectRaw: json.RawMessage(`[{"Id":"abc123"}]`),
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+
+	for range 2 {
+		data, err := mgr.InspectRaw(context.Background(), "abc123")
+		if err != nil {
+			t.Fatalf("InspectRaw() error = %v", err)
+		}
+		if string(data) != `[{"Id":"abc123"}]` {
+			t.Errorf("InspectRaw() = %s, want runtime output verbatim", data)
+		}
+	}
+	if mock.inspectRawCalls != 1 {
+		t.Errorf("runtime inspect calls = %d, want 1 (second call served from cache)", mock.inspectRawCalls)
+	}
+}
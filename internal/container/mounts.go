@@ -0,0 +1,248 @@
+// pattern: Functional Core
+
+package container
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mount is an extra mount requested at create time, parsed from a docker
+// --mount string such as "type=bind,source=/host/cache,target=/cache,readonly".
+type Mount struct {
+	Type     string // "bind" or "volume"
+	Source   string // absolute host path for bind, volume name for volume
+	Target   string // absolute container path
+	ReadOnly bool
+}
+
+// ParseMount parses and validates a docker --mount string. type, source
+// (or src) and target (or dst/destination) are required; readonly (or ro)
+// is the only other option accepted. A bind of the host root or of a runtime
+// daemon socket (see exposesRuntimeSocket) is refused.
+func ParseMount(spec string) (Mount, error) {
+	var m Mount
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return m, fmt.Errorf("empty mount")
+	}
+	for _, field := range strings.Split(spec, ",") {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "type":
+			m.Type = value
+		case "source", "src":
+			m.Source = value
+		case "target", "dst", "destination":
+			m.Target = value
+		case "readonly", "ro":
+			switch {
+			case !hasValue, value == "true", value == "1":
+				m.ReadOnly = true
+			case value == "false", value == "0":
+				m.ReadOnly = false
+			default:
+				return m, fmt.Errorf("mount %q: invalid %s value %q", spec, key, value)
+			}
+		default:
+			return m, fmt.Errorf("mount %q: unsupported option %q", spec, key)
+		}
+	}
+
+	switch {
+	case m.Type == "":
+		return m, fmt.Errorf("mount %q: type is required", spec)
+	case m.Type != "bind" && m.Type != "volume":
+		return m, fmt.Errorf("mount %q: type must be bind or volume", spec)
+	case m.Source == "":
+		return m, fmt.Errorf("mount %q: source is required", spec)
+	case m.Target == "":
+		return m, fmt.Errorf("mount %q: target is required", spec)
+	case !strings.HasPrefix(m.Target, "/"):
+		return m, fmt.Errorf("mount %q: target must be an absolute path", spec)
+	case m.Type == "bind" && !filepath.IsAbs(m.Source):
+		return m, fmt.Errorf("mount %q: bind source must be an absolute path", spec)
+	case m.Type == "volume" && strings.ContainsAny(m.Source, `/\`):
+		return m, fmt.Errorf("mount %q: volume source must be a volume name", spec)
+	case m.Type == "bind" && filepath.Clean(m.Source) == "/":
+		return m, fmt.Errorf("mount %q: bind source must not be the host root", spec)
+	case m.Type == "bind" && exposesRuntimeSocket(filepath.Clean(m.Source)):
+		return m, fmt.Errorf("mount %q: bind source must not expose the container runtime socket", spec)
+	}
+	return m, nil
+}
+
+// runtimeSockets are the usual rootful Docker and Podman daemon sockets.
+var runtimeSockets = []string{
+	"/var/run/docker.sock",
+	"/run/docker.sock",
+	"/var/run/podman/podman.sock",
+	"/run/podman/podman.sock",
+}
+
+// exposesRuntimeSocket reports whether bind-mounting source would hand the
+// container a runtime daemon socket, and with it the host: a socket named
+// docker.sock or podman.sock (rootless ones live under /run/user/<uid>), or a
+// directory holding one of runtimeSockets.
+func exposesRuntimeSocket(source string) bool {
+	switch filepath.Base(source) {
+	case "docker.sock", "podman.sock":
+		return true
+	}
+	for _, sock := range runtimeSockets {
+		if strings.HasPrefix(sock, source+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMounts parses every spec with ParseMount, stopping at the first
+// invalid one.
+func ParseMounts(specs []string) ([]Mount, error) {
+	mounts := make([]Mount, 0, len(specs))
+	for _, spec := range specs {
+		m, err := ParseMount(spec)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
+
+// SplitMounts splits free text holding several --mount strings, separated by
+// newlines or commas, into one string per mount. Since a mount's own options
+// are comma separated, each mount must start with its type= option.
+func SplitMounts(text string) []string {
+	var specs []string
+	for _, line := range strings.Split(text, "\n") {
+		for _, field := range strings.Split(line, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if strings.HasPrefix(field, "type=") || len(specs) == 0 {
+				specs = append(specs, field)
+				continue
+			}
+			specs[len(specs)-1] += "," + field
+		}
+	}
+	return specs
+}
+
+// addComposeMounts appends mounts to the app service's volumes in compose
+// file content (long syntax), declaring any named volumes at the top level.
+func addComposeMounts(content string, mounts []Mount) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("docker-compose.yml is not a mapping")
+	}
+	root := doc.Content[0]
+	app := mappingValue(mappingValue(root, "services"), "app")
+	if app == nil || app.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("docker-compose.yml has no app service")
+	}
+	volumes := ensureMappingKey(app, "volumes", yaml.SequenceNode)
+	if volumes == nil {
+		return "", fmt.Errorf("app service volumes is not a list")
+	}
+
+	for _, m := range mounts {
+		entry := &yaml.Node{Kind: yaml.MappingNode}
+		appendPair(entry, "type", m.Type)
+		appendPair(entry, "source", m.Source)
+		appendPair(entry, "target", m.Target)
+		if m.ReadOnly {
+			entry.Content = append(entry.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: "read_only"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+		volumes.Content = append(volumes.Content, entry)
+
+		if m.Type == "volume" {
+			named := ensureMappingKey(root, "volumes", yaml.MappingNode)
+			if named == nil {
+				return "", fmt.Errorf("top-level volumes is not a mapping")
+			}
+			if mappingValue(named, m.Source) == nil {
+				named.Content = append(named.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Value: m.Source},
+					&yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeComposeMounts adds mounts to the compose file at path in place.
+func writeComposeMounts(path string, mounts []Mount) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	updated, err := addComposeMounts(string(content), mounts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ensureMappingKey returns the value node for key in a mapping node, adding
+// an empty node of the given kind when the key is missing or null. Returns
+// nil when the key holds a node of another kind.
+func ensureMappingKey(node *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	if v := mappingValue(node, key); v != nil {
+		switch {
+		case v.Kind == kind:
+			return v
+		case v.Tag == "!!null":
+			*v = yaml.Node{Kind: kind}
+			return v
+		default:
+			return nil
+		}
+	}
+	v := &yaml.Node{Kind: kind}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+// appendPair appends a string key/value pair to a mapping node.
+func appendPair(node *yaml.Node, key, value string) {
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
@@ -0,0 +1,136 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMount(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Mount
+		wantErr string
+	}{
+		{spec: "type=bind,source=/home/u/.cache,target=/home/vscode/.cache", want: Mount{Type: "bind", Source: "/home/u/.cache", Target: "/home/vscode/.cache"}},
+		{spec: "type=volume,src=gocache,dst=/go/pkg,readonly", want: Mount{Type: "volume", Source: "gocache", Target: "/go/pkg", ReadOnly: true}},
+		{spec: "type=bind,source=/a,destination=/b,ro=false", want: Mount{Type: "bind", Source: "/a", Target: "/b"}},
+		{spec: "source=/a,target=/b", wantErr: "type is required"},
+		{spec: "type=bind,target=/b", wantErr: "source is required"},
+		{spec: "type=bind,source=/a", wantErr: "target is required"},
+		{spec: "type=tmpfs,source=x,target=/b", wantErr: "type must be bind or volume"},
+		{spec: "type=bind,source=relative,target=/b", wantErr: "absolute"},
+		{spec: "type=bind,source=/a,target=b", wantErr: "absolute"},
+		{spec: "type=bind,source=/a,target=/b,consistency=cached", wantErr: "unsupported option"},
+		{spec: "  ", wantErr: "empty mount"},
+		{spec: "type=bind,source=/,target=/host", wantErr: "host root"},
+		{spec: "type=bind,source=/etc/..,target=/host", wantErr: "host root"},
+		{spec: "type=bind,source=/var/run/docker.sock,target=/var/run/docker.sock", wantErr: "runtime socket"},
+		{spec: "type=bind,source=/run/user/1000/podman/podman.sock,target=/run/podman.sock", wantErr: "runtime socket"},
+		{spec: "type=bind,source=/var/run,target=/host-run", wantErr: "runtime socket"},
+		{spec: "type=bind,source=/run/,target=/host-run", wantErr: "runtime socket"},
+		{spec: "type=bind,source=/var/run/other,target=/other", want: Mount{Type: "bind", Source: "/var/run/other", Target: "/other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseMount(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseMount() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMount() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMount() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMounts(t *testing.T) {
+	got := SplitMounts("type=bind,source=/a,target=/b, type=volume,source=v,target=/v,readonly\ntype=bind,source=/c,target=/d\n")
+	want := []string{
+		"type=bind,source=/a,target=/b",
+		"type=volume,source=v,target=/v,readonly",
+		"type=bind,source=/c,target=/d",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("SplitMounts() = %q, want %q", got, want)
+	}
+}
+
+// TestCreateWithCompose_ExtraMounts verifies that extra mounts are added to
+// the generated app service and that an invalid one fails the create before
+// any file is written.
+func TestCreateWithCompose_ExtraMounts(t *testing.T) {
+	mgr, mock, projectDir := setupCreateWithComposeTest(t)
+	composeFile := filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")
+	if err := os.Remove(composeFile); err != nil {
+		t.Fatalf("Remove error = %v", err)
+	}
+	opts := CreateOptions{
+		ProjectPath: projectDir,
+		Template:    "default",
+		Name:        "test-container",
+		ExtraMounts: []string{"type=bind,target=/cache"},
+	}
+
+	if _, err := mgr.CreateWithCompose(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "source is required") {
+		t.Fatalf("CreateWithCompose error = %v, want missing source", err)
+	}
+	if _, err := os.Stat(composeFile); !os.IsNotExist(err) {
+		t.Errorf("compose file written for an invalid mount (stat error = %v)", err)
+	}
+	if mock.composeUpCalled != "" {
+		t.Errorf("compose up ran for %q, want it skipped", mock.composeUpCalled)
+	}
+
+	opts.ExtraMounts = []string{"type=bind,source=/host/cache,target=/cache,readonly", "type=volume,source=gocache,target=/go/pkg"}
+	if _, err := mgr.CreateWithCompose(context.Background(), opts); err != nil {
+		t.Fatalf("CreateWithCompose error = %v", err)
+	}
+
+	content, err := os.ReadFile(composeFile)
+	if err != nil {
+		t.Fatalf("ReadFile error = %v", err)
+	}
+	var compose struct {
+		Services map[string]struct {
+			Image   string `yaml:"image"`
+			Volumes []struct {
+				Type     string `yaml:"type"`
+				Source   string `yaml:"source"`
+				Target   string `yaml:"target"`
+				ReadOnly bool   `yaml:"read_only"`
+			} `yaml:"volumes"`
+		} `yaml:"services"`
+		Volumes map[string]any `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		t.Fatalf("generated compose file does not parse: %v\n%s", err, content)
+	}
+	app := compose.Services["app"]
+	if app.Image != "ubuntu:22.04" {
+		t.Errorf("app image = %q, want the template's service kept", app.Image)
+	}
+	if len(app.Volumes) != 2 {
+		t.Fatalf("app volumes = %+v, want the 2 extra mounts", app.Volumes)
+	}
+	if v := app.Volumes[0]; v.Type != "bind" || v.Source != "/host/cache" || v.Target != "/cache" || !v.ReadOnly {
+		t.Errorf("bind mount = %+v", v)
+	}
+	if v := app.Volumes[1]; v.Type != "volume" || v.Source != "gocache" || v.Target != "/go/pkg" || v.ReadOnly {
+		t.Errorf("volume mount = %+v", v)
+	}
+	if _, ok := compose.Volumes["gocache"]; !ok {
+		t.Errorf("top-level volumes = %v, want gocache declared", compose.Volumes)
+	}
+}
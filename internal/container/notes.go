@@ -0,0 +1,138 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LabelNote is the user label namespace key for a container's free-form note
+// (e.g. "staging"). Docker labels are immutable after create, so notes live in
+// a NoteStore keyed by project path rather than on the container itself.
+const LabelNote = "devagent.note"
+
+// ProtectedNote marks a container that must not be destroyed casually: the
+// TUI destroy confirmation requires typing its name, Prune skips it, and
+// DestroyWithCompose refuses it unless forced (DestroyOptions.Force).
+const ProtectedNote = "do-not-delete"
+
+// MaxNoteLen bounds a note's length in bytes.
+const MaxNoteLen = 200
+
+// NotesFileName is the note store file, kept in the data dir.
+const NotesFileName = "container-notes.json"
+
+// NoteStore holds container notes keyed by project path, so a note survives
+// container rebuilds. Safe for concurrent use.
+type NoteStore struct {
+	path string // empty keeps notes in memory only
+
+	mu    sync.Mutex
+	notes map[string]string
+}
+
+// LoadNoteStore reads the note store at path. A missing or corrupt file
+// yields an empty store; path "" never touches disk.
+func LoadNoteStore(path string) *NoteStore {
+	s := &NoteStore{path: path, notes: make(map[string]string)}
+	if path == "" {
+		return s
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s.notes); err != nil || s.notes == nil {
+		s.notes = make(map[string]string)
+	}
+	return s
+}
+
+// Get returns the note for projectPath, or "" if there is none.
+func (s *NoteStore) Get(projectPath string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notes[projectPath]
+}
+
+// Set stores note for projectPath (surrounding whitespace trimmed; empty
+// removes it) and writes the file atomically (temp file + rename).
+func (s *NoteStore) Set(projectPath, note string) error {
+	note = strings.TrimSpace(note)
+	if err := ValidateNote(note); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if note == "" {
+		delete(s.notes, projectPath)
+	} else {
+		s.notes[projectPath] = note
+	}
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// ValidateNote checks that note is a single line of at most MaxNoteLen bytes;
+// an error wraps ErrInvalidNote.
+func ValidateNote(note string) error {
+	if len(note) > MaxNoteLen {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidNote, MaxNoteLen)
+	}
+	if strings.ContainsAny(note, "\r\n") {
+		return fmt.Errorf("%w: must be a single line", ErrInvalidNote)
+	}
+	return nil
+}
+
+// IsProtectedNote reports whether note contains the ProtectedNote tag
+// (case-insensitive), e.g. "do-not-delete" or "staging, DO-NOT-DELETE".
+func IsProtectedNote(note string) bool {
+	return strings.Contains(strings.ToLower(note), ProtectedNote)
+}
+
+// LoadNotes replaces the in-memory note store with the one persisted at path
+// (see NotesFileName). Call before the manager is shared (main does so at
+// startup); until then notes are kept in memory only.
+func (m *Manager) LoadNotes(path string) {
+	m.notes = LoadNoteStore(path)
+}
+
+// Note returns the note of the container for projectPath.
+func (m *Manager) Note(projectPath string) string {
+	return m.notes.Get(projectPath)
+}
+
+// SetNote sets (or with "" clears) the note of the container for projectPath
+// and notifies change listeners.
+func (m *Manager) SetNote(projectPath, note string) error {
+	if err := m.notes.Set(projectPath, note); err != nil {
+		return err
+	}
+	m.notifyChange()
+	return nil
+}
+
+// IsProtected reports whether c's note marks it do-not-delete.
+func (m *Manager) IsProtected(c *Container) bool {
+	return c != nil && IsProtectedNote(m.Note(c.ProjectPath))
+}
@@ -0,0 +1,127 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNoteStore_PersistsAcrossLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", NotesFileName)
+
+	s := LoadNoteStore(path)
+	if err := s.Set("/src/app", "  staging  "); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Set("/src/other", "scratch"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := s.Set("/src/other", ""); err != nil {
+		t.Fatalf("Set() clearing error = %v", err)
+	}
+
+	reloaded := LoadNoteStore(path)
+	if got := reloaded.Get("/src/app"); got != "staging" {
+		t.Errorf("Get() after reload = %q, want %q", got, "staging")
+	}
+	if got := reloaded.Get("/src/other"); got != "" {
+		t.Errorf("cleared note = %q, want empty", got)
+	}
+}
+
+func TestNoteStore_RejectsInvalidNotes(t *testing.T) {
+	s := LoadNoteStore("")
+	for _, note := range []string{"two\nlines", strings.Repeat("x", MaxNoteLen+1)} {
+		if err := s.Set("/src/app", note); !errors.Is(err, ErrInvalidNote) {
+			t.Errorf("Set(%q) error = %v, want ErrInvalidNote", note, err)
+		}
+	}
+	if got := s.Get("/src/app"); got != "" {
+		t.Errorf("invalid note was stored: %q", got)
+	}
+}
+
+func TestNoteStore_CorruptFileStartsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), NotesFileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := LoadNoteStore(path)
+	if got := s.Get("/src/app"); got != "" {
+		t.Errorf("Get() = %q, want empty", got)
+	}
+	if err := s.Set("/src/app", "ok"); err != nil {
+		t.Errorf("Set() on a corrupt store error = %v", err)
+	}
+}
+
+func TestIsProtectedNote(t *testing.T) {
+	for note, want := range map[string]bool{
+		"do-not-delete":          true,
+		"staging, DO-NOT-DELETE": true,
+		"staging":                false,
+		"":                       false,
+	} {
+		if got := IsProtectedNote(note); got != want {
+			t.Errorf("IsProtectedNote(%q) = %v, want %v", note, got, want)
+		}
+	}
+}
+
+func TestDestroy_RefusesProtectedUnlessForced(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mock := &mockRuntime{
+		containers: []Container{{ID: "kept", Name: "kept", ProjectPath: "/src/kept", State: StateStopped, Labels: managedLabels("kept")}},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if err := mgr.SetNote("/src/kept", "do-not-delete"); err != nil {
+		t.Fatalf("SetNote() error = %v", err)
+	}
+
+	err := mgr.DestroyWithCompose(context.Background(), "kept")
+	if !errors.Is(err, ErrContainerProtected) || !errors.Is(err, ErrProtected) {
+		t.Fatalf("DestroyWithCompose() error = %v, want ErrContainerProtected", err)
+	}
+	if mock.composeDownCalled != "" {
+		t.Error("a protected container should not be taken down")
+	}
+
+	if err := mgr.DestroyWithOptions(context.Background(), "kept", DestroyOptions{Force: true}); err != nil {
+		t.Fatalf("DestroyWithOptions(Force) error = %v", err)
+	}
+	if mock.composeDownCalled != "/src/kept" {
+		t.Errorf("ComposeDown dir = %q, want /src/kept", mock.composeDownCalled)
+	}
+}
+
+func TestPrune_SkipsProtectedContainers(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mock := &mockRuntime{
+		containers: []Container{
+			{ID: "stopped-plain", Name: "old", ProjectPath: "/src/old", State: StateStopped, Labels: managedLabels("old")},
+			{ID: "stopped-kept", Name: "kept", ProjectPath: "/src/kept", State: StateStopped, Labels: managedLabels("kept")},
+		},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if err := mgr.SetNote("/src/kept", "do-not-delete"); err != nil {
+		t.Fatalf("SetNote() error = %v", err)
+	}
+
+	removed, err := mgr.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if !slices.Equal(removed, []string{"stopped-plain"}) {
+		t.Errorf("Prune() removed = %v, want [stopped-plain]", removed)
+	}
+}
@@ -0,0 +1,92 @@
+// pattern: Functional Core
+
+package container
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Operation actions tracked by Operations.
+const (
+	OpCreate  = "create"
+	OpStart   = "start"
+	OpStop    = "stop"
+	OpDestroy = "destroy"
+)
+
+// Operation is an in-flight container lifecycle operation.
+type Operation struct {
+	ID        string // container ID, or the container name for a create
+	Action    string // OpCreate, OpStart, OpStop or OpDestroy
+	StartedAt time.Time
+}
+
+// Operations tracks in-flight container operations by ID. The Manager records
+// its own lifecycle calls; the TUI marks operations as soon as they are
+// requested so spinners show before the call starts. Safe for concurrent use.
+type Operations struct {
+	mu  sync.RWMutex
+	ops map[string]Operation
+	now func() time.Time
+}
+
+// NewOperations returns an empty operation tracker.
+func NewOperations() *Operations {
+	return &Operations{ops: make(map[string]Operation), now: time.Now}
+}
+
+// Begin marks an operation on id as in flight. Re-marking the same action
+// keeps the original start time.
+func (o *Operations) Begin(id, action string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if op, ok := o.ops[id]; ok && op.Action == action {
+		return
+	}
+	o.ops[id] = Operation{ID: id, Action: action, StartedAt: o.now()}
+}
+
+// End clears the operation on id, if any.
+func (o *Operations) End(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.ops, id)
+}
+
+// Action returns the in-flight action on id, or "" if there is none.
+func (o *Operations) Action(id string) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.ops[id].Action
+}
+
+// Actions returns a snapshot of the in-flight actions keyed by ID.
+func (o *Operations) Actions() map[string]string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	actions := make(map[string]string, len(o.ops))
+	for id, op := range o.ops {
+		actions[id] = op.Action
+	}
+	return actions
+}
+
+// List returns the in-flight operations, oldest first.
+func (o *Operations) List() []Operation {
+	o.mu.RLock()
+	list := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		list = append(list, op)
+	}
+	o.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartedAt.Equal(list[j].StartedAt) {
+			return list[i].StartedAt.Before(list[j].StartedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
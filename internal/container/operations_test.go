@@ -0,0 +1,41 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOperations_BeginEnd(t *testing.T) {
+	ops := NewOperations()
+	clock := time.Unix(1000, 0)
+	ops.now = func() time.Time { return clock }
+
+	ops.Begin("b", OpStop)
+	clock = clock.Add(time.Second)
+	ops.Begin("a", OpStart)
+	clock = clock.Add(time.Second)
+	ops.Begin("b", OpStop) // same action keeps its start time
+
+	list := ops.List()
+	if len(list) != 2 || list[0].ID != "b" || list[1].ID != "a" {
+		t.Fatalf("List() = %+v, want b then a", list)
+	}
+	if !list[0].StartedAt.Equal(time.Unix(1000, 0)) {
+		t.Errorf("re-marked operation StartedAt = %v, want the original start", list[0].StartedAt)
+	}
+	if got := ops.Action("a"); got != OpStart {
+		t.Errorf("Action(a) = %q, want %q", got, OpStart)
+	}
+	if got := ops.Actions(); len(got) != 2 || got["b"] != OpStop {
+		t.Errorf("Actions() = %v", got)
+	}
+
+	ops.End("b")
+	ops.End("missing")
+	if got := ops.Action("b"); got != "" {
+		t.Errorf("Action(b) after End = %q, want none", got)
+	}
+	if len(ops.List()) != 1 {
+		t.Errorf("List() after End = %+v, want one operation", ops.List())
+	}
+}
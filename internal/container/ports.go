@@ -3,10 +3,13 @@
 package container
 
 import (
+	"cmp"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -77,3 +80,54 @@ func SanitizeComposeName(name string) string {
 	name = strings.Trim(name, "-")
 	return name
 }
+
+// portBinding is one host binding in `inspect` NetworkSettings.Ports.
+type portBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// parsePortBindings parses the JSON of a container's NetworkSettings.Ports,
+// keyed by "<port>/<protocol>" (Docker and Podman use the same shape). Exposed
+// but unpublished ports (null bindings) are skipped, and a port bound on both
+// IPv4 and IPv6 is listed once. Mappings are sorted by container port.
+func parsePortBindings(output string) ([]PortMapping, error) {
+	output = strings.TrimSpace(output)
+	if output == "" || output == "null" {
+		return nil, nil
+	}
+	var raw map[string][]portBinding
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, err
+	}
+
+	var mappings []PortMapping
+	for key, bindings := range raw {
+		portStr, proto, _ := strings.Cut(key, "/")
+		containerPort, err := strconv.Atoi(portStr)
+		if err != nil {
+			continue
+		}
+		if proto == "" {
+			proto = "tcp"
+		}
+		for _, b := range bindings {
+			hostPort, err := strconv.Atoi(b.HostPort)
+			if err != nil {
+				continue
+			}
+			m := PortMapping{ContainerPort: containerPort, HostPort: hostPort, Protocol: proto}
+			if !slices.Contains(mappings, m) {
+				mappings = append(mappings, m)
+			}
+		}
+	}
+	slices.SortFunc(mappings, func(a, b PortMapping) int {
+		return cmp.Or(
+			cmp.Compare(a.ContainerPort, b.ContainerPort),
+			cmp.Compare(a.Protocol, b.Protocol),
+			cmp.Compare(a.HostPort, b.HostPort),
+		)
+	})
+	return mappings, nil
+}
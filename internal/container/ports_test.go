@@ -5,6 +5,7 @@ package container
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"testing"
 )
@@ -227,3 +228,46 @@ func TestSanitizeComposeName(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePortBindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []PortMapping
+	}{
+		{
+			name:   "docker with IPv4 and IPv6 bindings and an unpublished port",
+			output: `{"22/tcp":null,"3000/tcp":[{"HostIp":"0.0.0.0","HostPort":"3000"},{"HostIp":"::","HostPort":"3000"}],"53/udp":[{"HostIp":"0.0.0.0","HostPort":"5353"}]}`,
+			want: []PortMapping{
+				{ContainerPort: 53, HostPort: 5353, Protocol: "udp"},
+				{ContainerPort: 3000, HostPort: 3000, Protocol: "tcp"},
+			},
+		},
+		{
+			name:   "podman with empty host IP",
+			output: `{"8080/tcp":[{"HostIp":"","HostPort":"18080"}],"5432/tcp":[{"HostIp":"127.0.0.1","HostPort":"15432"}]}` + "\n",
+			want: []PortMapping{
+				{ContainerPort: 5432, HostPort: 15432, Protocol: "tcp"},
+				{ContainerPort: 8080, HostPort: 18080, Protocol: "tcp"},
+			},
+		},
+		{name: "no ports", output: "{}\n"},
+		{name: "null", output: "null\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePortBindings(tt.output)
+			if err != nil {
+				t.Fatalf("parsePortBindings() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parsePortBindings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parsePortBindings("not json"); err == nil {
+		t.Error("expected error for invalid output")
+	}
+}
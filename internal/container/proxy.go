@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"devagent/internal/config"
 )
 
 // projectHash returns a truncated SHA256 hash of the project path.
@@ -57,11 +59,19 @@ func ProxyCertExists(projectPath string) (bool, error) {
 	return true, nil
 }
 
+// filterScriptRelPath is the proxy filter script (the allowlist) relative to
+// the project's .devcontainer directory.
+var filterScriptRelPath = filepath.Join("containers", "proxy", "opt", "devagent-proxy", "filter.py")
+
+// filterScriptPath returns the path of a project's proxy filter script.
+func filterScriptPath(projectPath string) string {
+	return filepath.Join(projectPath, ".devcontainer", filterScriptRelPath)
+}
+
 // ReadAllowlistFromFilterScript reads the allowlist domains from an existing filter script.
 // Returns nil if the file doesn't exist or can't be parsed.
 func ReadAllowlistFromFilterScript(projectPath string) ([]string, error) {
-	scriptPath := filepath.Join(projectPath, ".devcontainer", "proxy", "filter.py")
-	content, err := os.ReadFile(scriptPath)
+	content, err := os.ReadFile(filterScriptPath(projectPath))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No filter script exists
@@ -72,6 +82,20 @@ func ReadAllowlistFromFilterScript(projectPath string) ([]string, error) {
 	return parseAllowlistFromScript(string(content)), nil
 }
 
+// readEnforcedAllowlist returns every domain a project's filter script
+// allows: the ALLOWED_DOMAINS array followed by the generated config block
+// that extends it. Returns nil if the file doesn't exist.
+func readEnforcedAllowlist(projectPath string) ([]string, error) {
+	content, err := os.ReadFile(filterScriptPath(projectPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return config.MergeAllowlists(parseAllowlistFromScript(string(content)), parseConfigAllowlistFromScript(string(content))), nil
+}
+
 // parseAllowlistFromScript extracts domain strings from the ALLOWED_DOMAINS array.
 // The format is:    "domain.com", (one per line, with quotes and comma)
 func parseAllowlistFromScript(content string) []string {
@@ -91,10 +115,29 @@ func parseAllowlistFromScript(content string) []string {
 	}
 
 	// Extract the content between [ and ]
-	arrayContent := content[startIdx+len(startMarker) : startIdx+endIdx]
+	return quotedEntries(content[startIdx+len(startMarker) : startIdx+endIdx])
+}
 
-	// Parse each line looking for quoted strings
-	for _, line := range strings.Split(arrayContent, "\n") {
+// parseConfigAllowlistFromScript extracts the domains of the generated
+// ALLOWED_DOMAINS.extend block (see replaceConfigAllowlistInScript), or nil
+// when the script has none.
+func parseConfigAllowlistFromScript(content string) []string {
+	begin := strings.Index(content, configAllowlistBegin)
+	if begin == -1 {
+		return nil
+	}
+	end := strings.Index(content[begin:], configAllowlistEnd)
+	if end == -1 {
+		return nil
+	}
+	return quotedEntries(content[begin+len(configAllowlistBegin) : begin+end])
+}
+
+// quotedEntries returns the domain of each line of a Python list body that
+// starts with a quoted string, like    "domain.com",
+func quotedEntries(listContent string) []string {
+	var domains []string
+	for _, line := range strings.Split(listContent, "\n") {
 		line = strings.TrimSpace(line)
 		// Look for quoted strings like "domain.com",
 		if strings.HasPrefix(line, "\"") {
@@ -110,6 +153,129 @@ func parseAllowlistFromScript(content string) []string {
 	return domains
 }
 
+// ValidateAllowlistDomain checks that domain is a lowercase hostname or a
+// "*.<hostname>" wildcard (see config.ValidateAllowlistDomain).
+func ValidateAllowlistDomain(domain string) error {
+	return config.ValidateAllowlistDomain(domain)
+}
+
+// replaceAllowlistInScript rewrites the plain string entries of the
+// ALLOWED_DOMAINS array to match domains. Entries still in domains keep their
+// line; new ones are appended before the closing bracket. Comments and
+// dict-style entries are left untouched.
+func replaceAllowlistInScript(content string, domains []string) (string, error) {
+	startMarker := "ALLOWED_DOMAINS = ["
+	startIdx := strings.Index(content, startMarker)
+	if startIdx == -1 {
+		return "", fmt.Errorf("ALLOWED_DOMAINS not found in filter script")
+	}
+	arrayStart := startIdx + len(startMarker)
+	endIdx := strings.Index(content[arrayStart:], "]")
+	if endIdx == -1 {
+		return "", fmt.Errorf("ALLOWED_DOMAINS is not closed in filter script")
+	}
+	arrayEnd := arrayStart + endIdx
+
+	wanted := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		wanted[d] = true
+	}
+
+	// The text between "[" and the closing bracket's line is one entry per line.
+	body := content[arrayStart:arrayEnd]
+	closingIndent := ""
+	if i := strings.LastIndex(body, "\n"); i >= 0 {
+		closingIndent = body[i+1:]
+		body = body[:i+1]
+	}
+
+	kept := make(map[string]bool, len(domains))
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "\"") {
+			endQuote := strings.Index(trimmed[1:], "\"")
+			if endQuote > 0 {
+				domain := trimmed[1 : endQuote+1]
+				if !wanted[domain] || kept[domain] {
+					continue
+				}
+				kept[domain] = true
+			}
+		}
+		b.WriteString(line)
+	}
+	if b.Len() == 0 {
+		b.WriteString("\n")
+	}
+	for _, d := range domains {
+		if !kept[d] {
+			kept[d] = true
+			fmt.Fprintf(&b, "    %q,\n", d)
+		}
+	}
+
+	return content[:arrayStart] + b.String() + closingIndent + content[arrayEnd:], nil
+}
+
+// Markers delimiting the block of config allowlist domains (network.allowlist
+// and network.allowlist_file) that devagent maintains in filter.py.
+const (
+	configAllowlistBegin = "# BEGIN devagent network.allowlist (generated from config; edits are overwritten)"
+	configAllowlistEnd   = "# END devagent network.allowlist"
+)
+
+// replaceConfigAllowlistInScript rewrites the generated block that extends
+// ALLOWED_DOMAINS with the config allowlist. An existing block is replaced in
+// place (or removed when domains is empty); otherwise a new block is inserted
+// after the ALLOWED_DOMAINS array. The template's own entries are untouched.
+func replaceConfigAllowlistInScript(content string, domains []string) (string, error) {
+	var block string
+	if len(domains) > 0 {
+		var b strings.Builder
+		b.WriteString(configAllowlistBegin + "\n")
+		b.WriteString("ALLOWED_DOMAINS.extend([\n")
+		for _, d := range domains {
+			fmt.Fprintf(&b, "    %q,\n", d)
+		}
+		b.WriteString("])\n")
+		b.WriteString(configAllowlistEnd + "\n")
+		block = b.String()
+	}
+
+	if begin := strings.Index(content, configAllowlistBegin); begin >= 0 {
+		end := strings.Index(content[begin:], configAllowlistEnd)
+		if end == -1 {
+			return "", fmt.Errorf("config allowlist block is not closed in filter script")
+		}
+		end += begin + len(configAllowlistEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		return content[:begin] + block + content[end:], nil
+	}
+	if block == "" {
+		return content, nil
+	}
+
+	startMarker := "ALLOWED_DOMAINS = ["
+	startIdx := strings.Index(content, startMarker)
+	if startIdx == -1 {
+		return "", fmt.Errorf("ALLOWED_DOMAINS not found in filter script")
+	}
+	closeIdx := strings.Index(content[startIdx:], "]")
+	if closeIdx == -1 {
+		return "", fmt.Errorf("ALLOWED_DOMAINS is not closed in filter script")
+	}
+	insertAt := len(content)
+	if nl := strings.Index(content[startIdx+closeIdx:], "\n"); nl >= 0 {
+		insertAt = startIdx + closeIdx + nl + 1
+	} else {
+		block = "\n" + block
+	}
+	return content[:insertAt] + "\n" + block + content[insertAt:], nil
+}
+
 // CleanupProxyConfigs removes the proxy configuration directories for a project.
 // Called when a container is destroyed to clean up associated resources.
 func CleanupProxyConfigs(projectPath string) error {
@@ -3,6 +3,7 @@ package container
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -221,8 +222,8 @@ func TestReadAllowlistFromFilterScript(t *testing.T) {
 	})
 
 	t.Run("reads allowlist from existing file", func(t *testing.T) {
-		// Write a filter script to the new location
-		proxyDir := filepath.Join(projectPath, ".devcontainer", "proxy")
+		// Write a filter script where templates place it
+		proxyDir := filepath.Join(projectPath, ".devcontainer", "containers", "proxy", "opt", "devagent-proxy")
 		if err := os.MkdirAll(proxyDir, 0755); err != nil {
 			t.Fatalf("Failed to create proxy dir: %v", err)
 		}
@@ -251,3 +252,149 @@ func TestReadAllowlistFromFilterScript(t *testing.T) {
 		}
 	})
 }
+
+func TestReadEnforcedAllowlist(t *testing.T) {
+	projectPath := t.TempDir()
+	if domains, err := readEnforcedAllowlist(projectPath); err != nil || domains != nil {
+		t.Errorf("readEnforcedAllowlist() = %v, %v, want nil, nil without a filter script", domains, err)
+	}
+
+	script, err := replaceConfigAllowlistInScript("ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n", []string{"github.com", "a.example.com"})
+	if err != nil {
+		t.Fatalf("replaceConfigAllowlistInScript() error = %v", err)
+	}
+	scriptPath := filterScriptPath(projectPath)
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config block is enforced too, so it is reported with the template list
+	domains, err := readEnforcedAllowlist(projectPath)
+	if err != nil {
+		t.Fatalf("readEnforcedAllowlist() error = %v", err)
+	}
+	if want := []string{"github.com", "a.example.com"}; !slices.Equal(domains, want) {
+		t.Errorf("readEnforcedAllowlist() = %v, want %v", domains, want)
+	}
+	// The editable list is still the template's alone
+	if domains, _ := ReadAllowlistFromFilterScript(projectPath); !slices.Equal(domains, []string{"github.com"}) {
+		t.Errorf("ReadAllowlistFromFilterScript() = %v, want [github.com]", domains)
+	}
+}
+
+func TestValidateAllowlistDomain(t *testing.T) {
+	valid := []string{"github.com", "*.github.com", "api.anthropic.com", "localhost", "registry-1.docker.io"}
+	for _, d := range valid {
+		if err := ValidateAllowlistDomain(d); err != nil {
+			t.Errorf("ValidateAllowlistDomain(%q) error = %v", d, err)
+		}
+	}
+
+	invalid := []string{"", "https://github.com", "github.com/org", "*github.com", "a.*.com", "GitHub.com", "-bad.com", "has space.com", `quote".com`}
+	for _, d := range invalid {
+		if err := ValidateAllowlistDomain(d); err == nil {
+			t.Errorf("ValidateAllowlistDomain(%q) should fail", d)
+		}
+	}
+}
+
+func TestReplaceAllowlistInScript(t *testing.T) {
+	script := `import os
+
+ALLOWED_DOMAINS = [
+    "api.anthropic.com",
+    "github.com",
+
+    # Quiet
+    {"domain": "telemetry.example.com", "log": False},
+    "old.example.com",
+]
+
+PASSTHROUGH_DOMAINS = []
+`
+	got, err := replaceAllowlistInScript(script, []string{"github.com", "api.anthropic.com", "new.example.com"})
+	if err != nil {
+		t.Fatalf("replaceAllowlistInScript() error = %v", err)
+	}
+	want := `import os
+
+ALLOWED_DOMAINS = [
+    "api.anthropic.com",
+    "github.com",
+
+    # Quiet
+    {"domain": "telemetry.example.com", "log": False},
+    "new.example.com",
+]
+
+PASSTHROUGH_DOMAINS = []
+`
+	if got != want {
+		t.Errorf("replaceAllowlistInScript() =\n%s\nwant\n%s", got, want)
+	}
+	if domains := parseAllowlistFromScript(got); !slices.Equal(domains, []string{"api.anthropic.com", "github.com", "new.example.com"}) {
+		t.Errorf("parsed allowlist = %v", domains)
+	}
+
+	empty, err := replaceAllowlistInScript("ALLOWED_DOMAINS = []\n", []string{"github.com"})
+	if err != nil {
+		t.Fatalf("replaceAllowlistInScript() error = %v", err)
+	}
+	if empty != "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n" {
+		t.Errorf("replaceAllowlistInScript() on empty list = %q", empty)
+	}
+
+	if _, err := replaceAllowlistInScript("print('no list')\n", nil); err == nil {
+		t.Error("expected error when ALLOWED_DOMAINS is missing")
+	}
+}
+
+func TestReplaceConfigAllowlistInScript(t *testing.T) {
+	script := "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n\nPASSTHROUGH_DOMAINS = []\n"
+
+	got, err := replaceConfigAllowlistInScript(script, []string{"a.example.com", "*.b.example.com"})
+	if err != nil {
+		t.Fatalf("replaceConfigAllowlistInScript() error = %v", err)
+	}
+	want := "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n\n" +
+		configAllowlistBegin + "\nALLOWED_DOMAINS.extend([\n    \"a.example.com\",\n    \"*.b.example.com\",\n])\n" + configAllowlistEnd + "\n" +
+		"\nPASSTHROUGH_DOMAINS = []\n"
+	if got != want {
+		t.Errorf("insert =\n%s\nwant\n%s", got, want)
+	}
+	if domains := parseAllowlistFromScript(got); !slices.Equal(domains, []string{"github.com"}) {
+		t.Errorf("template allowlist = %v, want it untouched", domains)
+	}
+
+	// Replacing keeps the block in place; the template list stays editable.
+	replaced, err := replaceConfigAllowlistInScript(got, []string{"c.example.com"})
+	if err != nil {
+		t.Fatalf("replaceConfigAllowlistInScript() error = %v", err)
+	}
+	if strings.Contains(replaced, "a.example.com") || strings.Count(replaced, configAllowlistBegin) != 1 || !strings.Contains(replaced, `"c.example.com"`) {
+		t.Errorf("replace =\n%s", replaced)
+	}
+	edited, err := replaceAllowlistInScript(replaced, []string{"github.com", "new.example.com"})
+	if err != nil || !strings.Contains(edited, `"c.example.com"`) {
+		t.Errorf("editing the template allowlist dropped the config block: %v\n%s", err, edited)
+	}
+
+	// An empty config allowlist removes the block entirely.
+	removed, err := replaceConfigAllowlistInScript(got, nil)
+	if err != nil {
+		t.Fatalf("replaceConfigAllowlistInScript() error = %v", err)
+	}
+	if removed != "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n\n\nPASSTHROUGH_DOMAINS = []\n" {
+		t.Errorf("remove = %q", removed)
+	}
+	if unchanged, _ := replaceConfigAllowlistInScript(script, nil); unchanged != script {
+		t.Errorf("no block and no domains should leave the script unchanged, got %q", unchanged)
+	}
+
+	if _, err := replaceConfigAllowlistInScript("print('no list')\n", []string{"a.example.com"}); err == nil {
+		t.Error("expected error when ALLOWED_DOMAINS is missing")
+	}
+}
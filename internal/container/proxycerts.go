@@ -0,0 +1,123 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// proxyCertDir is where mitmproxy keeps its CA inside the proxy sidecar (the
+// proxy-certs volume, mounted read-only into the app container).
+const proxyCertDir = "/home/mitmproxy/.mitmproxy"
+
+// proxyCertInstallScript re-runs the CA install that entrypoint.sh performs at
+// container start, so the app container trusts a regenerated CA.
+const proxyCertInstallScript = `cp /tmp/mitmproxy-certs/mitmproxy-ca-cert.pem /usr/local/share/ca-certificates/mitmproxy-ca-cert.crt && update-ca-certificates && git config --system http.sslCAInfo /etc/ssl/certs/ca-certificates.crt`
+
+// proxyCertWait bounds how long RegenerateProxyCerts waits for the restarted
+// proxy to write a new CA, polling every proxyCertPoll.
+var (
+	proxyCertWait = 30 * time.Second
+	proxyCertPoll = time.Second
+)
+
+// RegenerateProxyCerts replaces the mitmproxy CA for every running container
+// of a project: it clears the project's host cert dir and the proxy's CA
+// files, restarts the proxy sidecar so mitmproxy generates a new CA, and
+// re-installs the new CA into the app container's trust store.
+func (m *Manager) RegenerateProxyCerts(ctx context.Context, projectPath string) error {
+	type target struct {
+		container *Container
+		proxyID   string
+	}
+
+	m.mu.RLock()
+	var targets []target
+	for _, c := range m.containers {
+		if c.ProjectPath != projectPath || !c.IsRunning() {
+			continue
+		}
+		t := target{container: c}
+		for _, s := range m.sidecars {
+			if s.Type == "proxy" && s.ParentRef == composeProjectName(c) {
+				t.proxyID = s.ID
+			}
+		}
+		targets = append(targets, t)
+	}
+	m.mu.RUnlock()
+
+	if len(targets) == 0 {
+		return fmt.Errorf("no running container for project %s", projectPath)
+	}
+
+	if err := CleanupProxyConfigs(projectPath); err != nil {
+		return err
+	}
+	if _, err := GetProxyCertDir(projectPath); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, t := range targets {
+		if t.proxyID == "" {
+			errs = append(errs, fmt.Errorf("%s: no proxy sidecar", t.container.Name))
+			continue
+		}
+		if err := m.regenerateContainerProxyCert(ctx, t.container, t.proxyID); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.container.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// regenerateContainerProxyCert regenerates the CA of one container's proxy and
+// installs it into the container.
+func (m *Manager) regenerateContainerProxyCert(ctx context.Context, c *Container, proxyID string) error {
+	logger := m.containerLogger(c.Name)
+	logger.Info("regenerating proxy certificates", "containerID", c.ID)
+
+	clearCmd := []string{"sh", "-c", "rm -f " + proxyCertDir + "/mitmproxy-*"}
+	if _, err := m.runtime.Exec(ctx, proxyID, clearCmd); err != nil {
+		return fmt.Errorf("failed to clear proxy certificates: %w", err)
+	}
+
+	if err := m.runtime.ComposeRestart(ctx, c.ProjectPath, composeProjectName(c), "proxy"); err != nil {
+		return fmt.Errorf("failed to restart proxy: %w", err)
+	}
+
+	if err := m.waitForProxyCert(ctx, proxyID); err != nil {
+		return err
+	}
+
+	// The app container's default user (e.g. vscode) cannot write the system
+	// trust store, so install as root.
+	if _, err := m.runtime.ExecAs(ctx, c.ID, "root", []string{"sh", "-c", proxyCertInstallScript}); err != nil {
+		return fmt.Errorf("failed to install proxy certificate: %w", err)
+	}
+
+	logger.Info("proxy certificates regenerated", "containerID", c.ID)
+	return nil
+}
+
+// waitForProxyCert polls the proxy until mitmproxy has written its CA cert.
+func (m *Manager) waitForProxyCert(ctx context.Context, proxyID string) error {
+	check := []string{"test", "-f", proxyCertDir + "/mitmproxy-ca-cert.pem"}
+	deadline := time.Now().Add(proxyCertWait)
+	for {
+		if _, err := m.runtime.Exec(ctx, proxyID, check); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("proxy did not generate a new certificate within %s", proxyCertWait)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(proxyCertPoll):
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package container
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"devagent/internal/config"
+)
+
+func TestRegenerateProxyCerts_ClearsCertDirAndRestartsProxy(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mock := &mockRuntime{containers: sidecarReconcileContainers(StateRunning)}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	certPath, err := GetProxyCACertPath("/src/alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certPath, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mgr.RegenerateProxyCerts(context.Background(), "/src/alpha"); err != nil {
+		t.Fatalf("RegenerateProxyCerts() error = %v", err)
+	}
+
+	if _, err := os.Stat(certPath); !os.IsNotExist(err) {
+		t.Errorf("stale cert should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(certPath)); err != nil {
+		t.Errorf("cert dir should be recreated: %v", err)
+	}
+	if mock.composeRestartProject != "alpha" || !slices.Equal(mock.composeRestartServices, []string{"proxy"}) {
+		t.Errorf("ComposeRestart(%q, %v), want (alpha, [proxy])", mock.composeRestartProject, mock.composeRestartServices)
+	}
+	// Clear + readiness check run in the proxy; the install runs in the app,
+	// as root since it writes the system trust store.
+	if len(mock.execIDs) < 2 || mock.execIDs[0] != "proxy-1" || slices.Contains(mock.execIDs, "app-1") {
+		t.Fatalf("Exec targets = %v, want only proxy-1", mock.execIDs)
+	}
+	if !slices.Equal(mock.execAsIDs, []string{"app-1"}) || !slices.Equal(mock.execAsUsers, []string{"root"}) {
+		t.Fatalf("ExecAs targets = %v as %v, want app-1 as root", mock.execAsIDs, mock.execAsUsers)
+	}
+	install := mock.execAsCalls[0]
+	if !strings.Contains(strings.Join(install, " "), "update-ca-certificates") {
+		t.Errorf("install command = %v, want it to update the trust store", install)
+	}
+}
+
+func TestRegenerateProxyCerts_NoRunningContainer(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mock := &mockRuntime{containers: sidecarReconcileContainers(StateRunning)}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	// bravo's app container is stopped
+	if err := mgr.RegenerateProxyCerts(context.Background(), "/src/bravo"); err == nil {
+		t.Error("expected error for project without a running container")
+	}
+	if mock.composeRestartProject != "" {
+		t.Error("proxy should not be restarted")
+	}
+}
@@ -0,0 +1,99 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// composeProjectDir returns the project root for a compose working dir label.
+// devagent compose files live in <project>/.devcontainer, which compose records
+// as the working dir.
+func composeProjectDir(workingDir string) string {
+	if workingDir == "" {
+		return ""
+	}
+	if filepath.Base(workingDir) == ".devcontainer" {
+		return filepath.Dir(workingDir)
+	}
+	return workingDir
+}
+
+// Prune destroys every stopped devagent-managed container and tears down
+// orphaned sidecars (compose projects whose app container no longer exists).
+// Running containers, containers without the devagent.managed=true label and
+// containers whose note marks them do-not-delete are left untouched. Returns the IDs of destroyed containers; failures are
+// collected and returned together after attempting every target.
+func (m *Manager) Prune(ctx context.Context) ([]string, error) {
+	stopped, orphans := m.pruneTargets()
+
+	var removed []string
+	var errs []error
+	for _, id := range stopped {
+		if err := m.DestroyWithCompose(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("destroy %s: %w", id, err))
+			continue
+		}
+		removed = append(removed, id)
+	}
+
+	for project, dir := range orphans {
+		m.logger.Info("removing orphaned sidecars", "composeProject", project)
+		if err := m.runtime.ComposeDown(ctx, dir, project); err != nil {
+			errs = append(errs, fmt.Errorf("remove orphaned sidecars for %s: %w", project, err))
+			continue
+		}
+		m.mu.Lock()
+		for id, s := range m.sidecars {
+			if s.ParentRef == project {
+				delete(m.sidecars, id)
+			}
+		}
+		m.mu.Unlock()
+	}
+	if len(orphans) > 0 {
+		m.notifyChange()
+	}
+
+	m.logger.Info("prune completed", "removed", len(removed), "orphanedProjects", len(orphans), "errors", len(errs))
+	return removed, errors.Join(errs...)
+}
+
+// PruneCandidates returns the IDs of the containers Prune would destroy now,
+// without destroying anything, so callers can gate large prunes.
+func (m *Manager) PruneCandidates() []string {
+	stopped, _ := m.pruneTargets()
+	return stopped
+}
+
+// IsPruneCandidate reports whether Prune would destroy c: it is stopped,
+// labelled devagent.managed=true and not protected by a do-not-delete note.
+func (m *Manager) IsPruneCandidate(c *Container) bool {
+	return c.IsStopped() && c.Labels[LabelManagedBy] == "true" && !m.IsProtected(c)
+}
+
+// pruneTargets returns the stopped managed containers Prune destroys and the
+// orphaned sidecar projects it tears down (compose project -> project dir).
+func (m *Manager) pruneTargets() ([]string, map[string]string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var stopped []string
+	liveProjects := make(map[string]bool)
+	for _, c := range m.containers {
+		liveProjects[composeProjectName(c)] = true
+		if m.IsPruneCandidate(c) {
+			stopped = append(stopped, c.ID)
+		}
+	}
+	orphans := make(map[string]string)
+	for _, s := range m.sidecars {
+		if s.ParentRef != "" && !liveProjects[s.ParentRef] && s.ProjectDir != "" {
+			orphans[s.ParentRef] = s.ProjectDir
+		}
+	}
+	return stopped, orphans
+}
@@ -0,0 +1,92 @@
+package container
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func managedLabels(project string) map[string]string {
+	return map[string]string{LabelManagedBy: "true", LabelComposeProject: project}
+}
+
+func TestPrune_RemovesOnlyStoppedManagedContainers(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	mock := &mockRuntime{
+		containers: []Container{
+			{ID: "stopped-managed", Name: "old", ProjectPath: "/src/old", State: StateStopped, Labels: managedLabels("old")},
+			{ID: "running-managed", Name: "live", ProjectPath: "/src/live", State: StateRunning, Labels: managedLabels("live")},
+			{ID: "created-managed", Name: "new", ProjectPath: "/src/new", State: StateCreated, Labels: managedLabels("new")},
+			{ID: "stopped-unmanaged", Name: "other", ProjectPath: "/src/other", State: StateStopped, Labels: map[string]string{LabelComposeProject: "other"}},
+		},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	removed, err := mgr.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if !slices.Equal(removed, []string{"stopped-managed"}) {
+		t.Errorf("Prune() removed = %v, want [stopped-managed]", removed)
+	}
+	if !slices.Equal(mock.composeDownProjects, []string{"old"}) {
+		t.Errorf("ComposeDown projects = %v, want [old]", mock.composeDownProjects)
+	}
+	for _, id := range []string{"running-managed", "created-managed", "stopped-unmanaged"} {
+		if _, ok := mgr.Get(id); !ok {
+			t.Errorf("container %s should not be pruned", id)
+		}
+	}
+}
+
+func TestPrune_RemovesOrphanedSidecars(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	sidecarLabels := func(project, workingDir string) map[string]string {
+		return map[string]string{
+			LabelSidecarType:       "proxy",
+			LabelComposeProject:    project,
+			LabelComposeWorkingDir: workingDir,
+		}
+	}
+	mock := &mockRuntime{
+		containers: []Container{
+			{ID: "app", Name: "live", ProjectPath: "/src/live", State: StateRunning, Labels: managedLabels("live")},
+			{ID: "live-proxy", Name: "live-proxy-1", State: StateRunning, Labels: sidecarLabels("live", "/src/live/.devcontainer")},
+			{ID: "orphan-proxy", Name: "gone-proxy-1", State: StateRunning, Labels: sidecarLabels("gone", "/src/gone/.devcontainer")},
+		},
+	}
+	mgr := NewManager(ManagerOptions{Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	removed, err := mgr.Prune(context.Background())
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("Prune() removed = %v, want none", removed)
+	}
+	if mock.composeDownProject != "gone" || mock.composeDownCalled != "/src/gone" {
+		t.Errorf("ComposeDown(%q, %q), want (/src/gone, gone)", mock.composeDownCalled, mock.composeDownProject)
+	}
+	if len(mgr.GetSidecarsForProject("/src/live")) != 1 {
+		t.Error("sidecar of a live project should not be removed")
+	}
+}
+
+func TestComposeProjectDir(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"/src/app/.devcontainer":  "/src/app",
+		"/src/app/custom-compose": "/src/app/custom-compose",
+	}
+	for in, want := range tests {
+		if got := composeProjectDir(in); got != want {
+			t.Errorf("composeProjectDir(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// pattern: Functional Core
+
+package container
+
+import "fmt"
+
+// sidecarWarnings returns a warning per running container whose sidecars are
+// not all running, keyed by container ID. Containers with no sidecars (no
+// network isolation) and containers that are not running are never flagged,
+// since their sidecars are expected to be down with them.
+func sidecarWarnings(containers map[string]*Container, sidecars map[string]*Sidecar) map[string]string {
+	byProject := make(map[string][]*Sidecar)
+	for _, s := range sidecars {
+		byProject[s.ParentRef] = append(byProject[s.ParentRef], s)
+	}
+
+	warnings := make(map[string]string)
+	for id, c := range containers {
+		if c.State != StateRunning {
+			continue
+		}
+		for _, s := range byProject[composeProjectName(c)] {
+			if s.State != StateRunning {
+				warnings[id] = fmt.Sprintf("%s sidecar is %s", s.Type, s.State)
+				break
+			}
+		}
+	}
+	return warnings
+}
@@ -8,35 +8,66 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"devagent/internal/config"
 )
 
 // CommandExecutor is a function that executes a command and returns its output.
 type CommandExecutor func(ctx context.Context, name string, args ...string) (string, error)
 
+// StreamExecutor runs a command with extra environment variables, passing each
+// line of its stdout and stderr to onLine while it runs.
+type StreamExecutor func(ctx context.Context, env map[string]string, onLine func(string), name string, args ...string) error
+
 // Runtime wraps Docker or Podman CLI operations.
 type Runtime struct {
 	executable string
+	host       string   // daemon address (config runtime_host); empty for the CLI's default
+	compose    []string // compose invocation (argv prefix), see DetectComposeCommand
 	exec       CommandExecutor
+	logsExec   CommandExecutor // like exec, but returns stdout and stderr combined
+	streamExec StreamExecutor  // nil with a test executor: output is replayed after exec
+	cappedExec cappedExecutor  // nil with a test executor: exec's output is cut afterwards
 }
 
-// NewRuntime creates a new Runtime with the specified executable (docker or podman).
-func NewRuntime(executable string) *Runtime {
+// cappedExecutor is like CommandExecutor but keeps at most limit bytes of
+// stdout, reporting whether any were dropped.
+type cappedExecutor func(ctx context.Context, limit int, name string, args ...string) (string, bool, error)
+
+// NewRuntime creates a new Runtime with the specified executable (docker or
+// podman). composeCommand overrides the compose invocation; when empty it is
+// detected by running `<candidate> version` (see DetectComposeCommand). host,
+// when set, is the daemon every command targets (a remote or rootless socket,
+// see config.ValidateRuntimeHost).
+func NewRuntime(executable, composeCommand, host string) *Runtime {
 	return &Runtime{
 		executable: executable,
+		host:       host,
+		compose:    DetectComposeCommand(executable, composeCommand, probeComposeVersion),
 		exec:       defaultExecutor,
+		logsExec:   combinedExecutor,
+		streamExec: streamingExecutor,
+		cappedExec: cappedOutputExecutor,
 	}
 }
 
 // NewRuntimeWithExecutor creates a new Runtime with a custom executor for testing.
+// The compose invocation is the runtime's preferred one, without probing.
 func NewRuntimeWithExecutor(executable string, exec CommandExecutor) *Runtime {
 	return &Runtime{
 		executable: executable,
+		compose:    composeCandidates(executable)[0],
 		exec:       exec,
+		logsExec:   exec,
 	}
 }
 
@@ -57,9 +88,147 @@ func defaultExecutor(ctx context.Context, name string, args ...string) (string,
 	return stdout.String(), nil
 }
 
+// combinedExecutor runs commands using os/exec and returns stdout and stderr
+// interleaved. `logs` replays a container's stderr on its own stderr, so both
+// streams are needed.
+func combinedExecutor(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// maxCappedStderr bounds the stderr cappedOutputExecutor keeps for its error.
+const maxCappedStderr = 64 << 10
+
+// cappedOutputExecutor runs commands using os/exec like defaultExecutor, but
+// keeps only the first limit bytes of stdout (and maxCappedStderr of stderr).
+// The rest is read and discarded, so a chatty command neither blocks on a full
+// pipe nor grows the buffer without bound.
+func cappedOutputExecutor(ctx context.Context, limit int, name string, args ...string) (string, bool, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout := &cappedWriter{limit: limit}
+	stderr := &cappedWriter{limit: maxCappedStderr}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if stderr.buf.Len() > 0 {
+			return stdout.buf.String(), stdout.truncated, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.buf.String()))
+		}
+		return stdout.buf.String(), stdout.truncated, err
+	}
+	return stdout.buf.String(), stdout.truncated, nil
+}
+
+// cappedWriter keeps the first limit bytes written to it and discards the
+// rest, recording that it did.
+type cappedWriter struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	room := max(w.limit-w.buf.Len(), 0)
+	if len(p) > room {
+		w.truncated = true
+		w.buf.Write(p[:room])
+		return len(p), nil
+	}
+	return w.buf.Write(p)
+}
+
+// maxStreamLine is the longest output line streamingExecutor passes on, well
+// above bufio.Scanner's 64KB default: a build step or a progress meter that
+// redraws with \r can print far longer lines.
+const maxStreamLine = 4 << 20
+
+// streamingExecutor runs commands using os/exec, scanning stdout and stderr
+// line by line. onLine is never called concurrently. Like defaultExecutor, a
+// failure carries the command's stderr. A line longer than maxStreamLine
+// stops the scan of its stream (the rest is drained unread) and is returned
+// as an error once the command exits.
+func streamingExecutor(ctx context.Context, env map[string]string, onLine func(string), name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	var (
+		mu        sync.Mutex
+		stderrBuf strings.Builder
+		scanErr   error
+		wg        sync.WaitGroup
+	)
+	scan := func(r io.Reader, keep *strings.Builder) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64<<10), maxStreamLine)
+		for scanner.Scan() {
+			mu.Lock()
+			if keep != nil {
+				keep.WriteString(scanner.Text() + "\n")
+			}
+			if onLine != nil {
+				onLine(scanner.Text())
+			}
+			mu.Unlock()
+		}
+		if err := scanner.Err(); err != nil {
+			// Keep draining so the command does not block on a full pipe
+			_, _ = io.Copy(io.Discard, r)
+			mu.Lock()
+			if scanErr == nil {
+				scanErr = err
+			}
+			mu.Unlock()
+		}
+	}
+	wg.Add(2)
+	go scan(stdout, nil)
+	go scan(stderr, &stderrBuf)
+	// Pipes must be drained before Wait closes them.
+	wg.Wait()
+
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderrBuf.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	if scanErr != nil {
+		return fmt.Errorf("failed to read output of %s: %w", name, scanErr)
+	}
+	return nil
+}
+
 // ListContainers returns all devagent-managed containers.
 func (r *Runtime) ListContainers(ctx context.Context) ([]Container, error) {
-	output, err := r.exec(ctx, r.executable, "ps", "-a", "--no-trunc", "--filter", "label=devagent.managed=true", "--format", "json")
+	output, err := r.exec(ctx, r.executable, r.cli("ps", "-a", "--no-trunc", "--filter", "label=devagent.managed=true", "--format", "json")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.parseContainerList(output)
+}
+
+// ListAllContainers returns every container on the host, managed or not.
+func (r *Runtime) ListAllContainers(ctx context.Context) ([]Container, error) {
+	output, err := r.exec(ctx, r.executable, r.cli("ps", "-a", "--no-trunc", "--format", "json")...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,32 +238,82 @@ func (r *Runtime) ListContainers(ctx context.Context) ([]Container, error) {
 
 // InspectContainer returns the state of a container.
 func (r *Runtime) InspectContainer(ctx context.Context, id string) (ContainerState, error) {
-	output, err := r.exec(ctx, r.executable, "inspect", "--format", "{{.State.Status}}", id)
+	output, err := r.exec(ctx, r.executable, r.cli("inspect", "--format", "{{.State.Status}} {{.State.ExitCode}}", id)...)
 	if err != nil {
 		return "", err
 	}
-	status := strings.TrimSpace(output)
+	status, code, _ := strings.Cut(strings.TrimSpace(output), " ")
+	exitCode, _ := strconv.Atoi(code)
 	switch status {
-	case "running":
-		return StateRunning, nil
-	case "exited", "dead":
-		return StateStopped, nil
-	case "created":
-		return StateCreated, nil
+	case "running", "exited", "dead", "created", "paused":
+		return mapState(status, exitCode), nil
 	default:
 		return ContainerState(status), nil
 	}
 }
 
+// InspectRaw returns the runtime's inspect output for a container unchanged
+// (a JSON array with one object).
+func (r *Runtime) InspectRaw(ctx context.Context, id string) (json.RawMessage, error) {
+	output, err := r.exec(ctx, r.executable, r.cli("inspect", id)...)
+	if err != nil {
+		return nil, err
+	}
+	data := bytes.TrimSpace([]byte(output))
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("inspect %s: output is not JSON", id)
+	}
+	return json.RawMessage(data), nil
+}
+
 // Exec runs a command inside a container as root.
 func (r *Runtime) Exec(ctx context.Context, id string, cmd []string) (string, error) {
-	args := append([]string{"exec", id}, cmd...)
+	args := append(r.cli("exec", id), cmd...)
 	return r.exec(ctx, r.executable, args...)
 }
 
+// Logs returns the last tail lines of a container's output with timestamps.
+func (r *Runtime) Logs(ctx context.Context, id string, tail int) (string, error) {
+	return r.logsExec(ctx, r.executable, r.cli("logs", "--timestamps", "--tail", strconv.Itoa(tail), id)...)
+}
+
+// FollowLogs streams a container's output (`logs --follow`), starting from
+// its last tail lines, passing each line to onLine until ctx is canceled or
+// the container exits.
+func (r *Runtime) FollowLogs(ctx context.Context, id string, tail int, onLine func(string)) error {
+	args := r.cli("logs", "--timestamps", "--follow", "--tail", strconv.Itoa(tail), id)
+	if r.streamExec != nil {
+		return r.streamExec(ctx, nil, onLine, r.executable, args...)
+	}
+	output, err := r.logsExec(ctx, r.executable, args...)
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		onLine(line)
+	}
+	return err
+}
+
+// ExecCapped runs a command inside a container as user, or as the
+// container's default user when user is empty, keeping at most limit bytes of
+// its stdout; truncated reports whether any were dropped.
+func (r *Runtime) ExecCapped(ctx context.Context, id, user string, cmd []string, limit int) (out string, truncated bool, err error) {
+	args := r.cli("exec")
+	if user != "" {
+		args = append(args, "-u", user)
+	}
+	args = append(append(args, id), cmd...)
+	if r.cappedExec != nil {
+		return r.cappedExec(ctx, limit, r.executable, args...)
+	}
+	out, err = r.exec(ctx, r.executable, args...)
+	if len(out) > limit {
+		return out[:limit], true, err
+	}
+	return out, false, err
+}
+
 // ExecAs runs a command inside a container as the specified user.
 func (r *Runtime) ExecAs(ctx context.Context, id string, user string, cmd []string) (string, error) {
-	args := []string{"exec", "-u", user, id}
+	args := r.cli("exec", "-u", user, id)
 	args = append(args, cmd...)
 	return r.exec(ctx, r.executable, args...)
 }
@@ -108,7 +327,9 @@ type containerJSON struct {
 	// Docker uses string, Podman uses array
 	Names     any    `json:"Names"`
 	State     string `json:"State"`
-	Labels    any    `json:"Labels"` // Docker: string, Podman: map
+	Status    string `json:"Status"`   // e.g. "Exited (137) 2 hours ago"
+	ExitCode  int    `json:"ExitCode"` // Podman only
+	Labels    any    `json:"Labels"`   // Docker: string, Podman: map
 	CreatedAt string `json:"CreatedAt"`
 	Created   int64  `json:"Created"` // Podman uses unix timestamp
 }
@@ -134,6 +355,43 @@ func (cj *containerJSON) getName() string {
 	return ""
 }
 
+// exitedStatus matches the exit code in a ps Status such as "Exited (137) 2 hours ago".
+var exitedStatus = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+
+// getExitCode returns the exit code of an exited container: Podman's ExitCode,
+// else the one in Docker's Status text. 0 when unknown.
+func (cj *containerJSON) getExitCode() int {
+	if cj.ExitCode != 0 {
+		return cj.ExitCode
+	}
+	if m := exitedStatus.FindStringSubmatch(cj.Status); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code
+	}
+	return 0
+}
+
+// toContainer converts a ps entry to a Container.
+func (cj *containerJSON) toContainer() Container {
+	labels := cj.getLabels()
+	c := Container{
+		ID:             cj.getID(),
+		Name:           cj.getName(),
+		State:          mapState(cj.State, cj.getExitCode()),
+		ProjectPath:    labels[LabelProjectPath],
+		Template:       labels[LabelTemplate],
+		Agent:          labels[LabelAgent],
+		RemoteUser:     getRemoteUser(labels),
+		CreatedAt:      cj.getCreatedAt(),
+		Labels:         labels,
+		ComposeProject: labels[LabelComposeProject],
+	}
+	if c.State == StateExited {
+		c.ExitCode = cj.getExitCode()
+	}
+	return c
+}
+
 func (cj *containerJSON) getLabels() map[string]string {
 	labels := make(map[string]string)
 	switch v := cj.Labels.(type) {
@@ -182,19 +440,7 @@ func (r *Runtime) parseContainerList(output string) ([]Container, error) {
 		var cjs []containerJSON
 		if err := json.Unmarshal([]byte(output), &cjs); err == nil {
 			for _, cj := range cjs {
-				labels := cj.getLabels()
-				containers = append(containers, Container{
-					ID:             cj.getID(),
-					Name:           cj.getName(),
-					State:          mapState(cj.State),
-					ProjectPath:    labels[LabelProjectPath],
-					Template:       labels[LabelTemplate],
-					Agent:          labels[LabelAgent],
-					RemoteUser:     getRemoteUser(labels),
-					CreatedAt:      cj.getCreatedAt(),
-					Labels:         labels,
-					ComposeProject: labels[LabelComposeProject],
-				})
+				containers = append(containers, cj.toContainer())
 			}
 			return containers, nil
 		}
@@ -213,34 +459,28 @@ func (r *Runtime) parseContainerList(output string) ([]Container, error) {
 			continue // Skip malformed lines
 		}
 
-		labels := cj.getLabels()
-		containers = append(containers, Container{
-			ID:             cj.getID(),
-			Name:           cj.getName(),
-			State:          mapState(cj.State),
-			ProjectPath:    labels[LabelProjectPath],
-			Template:       labels[LabelTemplate],
-			Agent:          labels[LabelAgent],
-			RemoteUser:     getRemoteUser(labels),
-			CreatedAt:      cj.getCreatedAt(),
-			Labels:         labels,
-			ComposeProject: labels[LabelComposeProject],
-		})
+		containers = append(containers, cj.toContainer())
 	}
 
 	return containers, nil
 }
 
-// mapState converts Docker/Podman state strings to ContainerState.
-func mapState(state string) ContainerState {
+// mapState converts Docker/Podman state strings to ContainerState. An
+// "exited" container with a non-zero exitCode is StateExited.
+func mapState(state string, exitCode int) ContainerState {
 	switch strings.ToLower(state) {
 	case "running":
 		return StateRunning
 	case "created":
 		return StateCreated
-	case "exited", "paused", "dead", "removing":
+	case "paused":
+		return StatePaused
+	case "exited":
+		if exitCode != 0 {
+			return StateExited
+		}
 		return StateStopped
-	default:
+	default: // dead, removing, unknown
 		return StateStopped
 	}
 }
@@ -297,7 +537,7 @@ type networkJSON struct {
 
 // GetIsolationInfo returns isolation details for a container by inspecting its runtime config.
 func (r *Runtime) GetIsolationInfo(ctx context.Context, id string) (*IsolationInfo, error) {
-	output, err := r.exec(ctx, r.executable, "inspect", id)
+	output, err := r.exec(ctx, r.executable, r.cli("inspect", id)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
@@ -384,28 +624,84 @@ func formatBytes(bytes int64) string {
 	return fmt.Sprintf("%d", bytes)
 }
 
-// composeCommand returns the compose command for this runtime.
-// For docker: uses "docker compose" (v2 subcommand)
-// For podman: uses "podman-compose" standalone binary
+// ComposeCommand returns the resolved compose invocation, e.g.
+// ["docker", "compose"] or ["podman-compose"].
+func (r *Runtime) ComposeCommand() []string {
+	return r.compose
+}
+
+// composeCommand returns the compose binary and the arguments that precede
+// the compose subcommand. A fresh slice is returned so callers can append.
+// A compose plugin of the runtime (docker compose, podman compose) gets the
+// host flags; a standalone binary gets the host from composeEnv instead.
 func (r *Runtime) composeCommand() (string, []string) {
-	if r.executable == "podman" {
-		return "podman-compose", nil
+	if r.compose[0] == r.executable {
+		return r.compose[0], append(r.hostArgs(), r.compose[1:]...)
+	}
+	return r.compose[0], append([]string{}, r.compose[1:]...)
+}
+
+// composeEnv returns env plus, when a host is set and compose is a standalone
+// binary (docker-compose, podman-compose), the variable that binary reads the
+// daemon from (see config.RuntimeHostEnvVar). env itself is not modified.
+func (r *Runtime) composeEnv(env map[string]string) map[string]string {
+	if r.host == "" || r.compose[0] == r.executable {
+		return env
+	}
+	out := make(map[string]string, len(env)+1)
+	for k, v := range env {
+		out[k] = v
+	}
+	out[config.RuntimeHostEnvVar(r.compose[0])] = r.host
+	return out
+}
+
+// hostArgs returns the global flags that point the runtime CLI at r.host:
+// --url for podman, --host for docker. Nil when no host is set.
+func (r *Runtime) hostArgs() []string {
+	switch {
+	case r.host == "":
+		return nil
+	case config.IsPodmanBinary(r.executable):
+		return []string{"--url=" + r.host}
+	default:
+		return []string{"--host=" + r.host}
 	}
-	// Docker uses compose as subcommand
-	return r.executable, []string{"compose"}
+}
+
+// cli returns the arguments for a runtime CLI command: hostArgs, then args.
+func (r *Runtime) cli(args ...string) []string {
+	return append(r.hostArgs(), args...)
 }
 
 // ComposeUp runs docker-compose/podman-compose up -d in the project directory.
 // The compose file is expected at {projectDir}/.devcontainer/docker-compose.yml
 // env specifies environment variables to pass to the compose command (for dynamic port allocation).
 func (r *Runtime) ComposeUp(ctx context.Context, projectDir string, projectName string, env map[string]string) error {
+	return r.ComposeUpWithProgress(ctx, projectDir, projectName, env, nil)
+}
+
+// ComposeUpWithProgress is ComposeUp, passing each line compose prints
+// (image pulls, builds, container creation) to onLine as it runs. onLine may
+// be nil.
+func (r *Runtime) ComposeUpWithProgress(ctx context.Context, projectDir string, projectName string, env map[string]string, onLine func(string)) error {
 	composeFile := filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")
 
 	cmd, baseArgs := r.composeCommand()
 	args := append(baseArgs, "-f", composeFile, "-p", projectName, "up", "-d")
+	env = r.composeEnv(env)
+
+	if r.streamExec != nil {
+		return r.streamExec(ctx, env, onLine, cmd, args...)
+	}
 
 	// Use execWithEnv to pass port environment variables
-	_, err := r.execWithEnv(ctx, env, cmd, args...)
+	output, err := r.execWithEnv(ctx, env, cmd, args...)
+	if onLine != nil {
+		for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+			onLine(line)
+		}
+	}
 	return err
 }
 
@@ -443,18 +739,33 @@ func (r *Runtime) ComposeStart(ctx context.Context, projectDir string, projectNa
 	cmd, baseArgs := r.composeCommand()
 	args := append(baseArgs, "-f", composeFile, "-p", projectName, "start")
 
-	_, err := r.exec(ctx, cmd, args...)
+	_, err := r.execWithEnv(ctx, r.composeEnv(nil), cmd, args...)
 	return err
 }
 
-// ComposeStop runs docker-compose/podman-compose stop.
-func (r *Runtime) ComposeStop(ctx context.Context, projectDir string, projectName string) error {
+// ComposeStop runs docker-compose/podman-compose stop for the given services
+// (all services when none are given).
+func (r *Runtime) ComposeStop(ctx context.Context, projectDir string, projectName string, services ...string) error {
 	composeFile := filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")
 
 	cmd, baseArgs := r.composeCommand()
 	args := append(baseArgs, "-f", composeFile, "-p", projectName, "stop")
+	args = append(args, services...)
+
+	_, err := r.execWithEnv(ctx, r.composeEnv(nil), cmd, args...)
+	return err
+}
+
+// ComposeRestart runs docker-compose/podman-compose restart for the given
+// services (all services when none are given).
+func (r *Runtime) ComposeRestart(ctx context.Context, projectDir string, projectName string, services ...string) error {
+	composeFile := filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")
+
+	cmd, baseArgs := r.composeCommand()
+	args := append(baseArgs, "-f", composeFile, "-p", projectName, "restart")
+	args = append(args, services...)
 
-	_, err := r.exec(ctx, cmd, args...)
+	_, err := r.execWithEnv(ctx, r.composeEnv(nil), cmd, args...)
 	return err
 }
 
@@ -465,7 +776,7 @@ func (r *Runtime) ComposeDown(ctx context.Context, projectDir string, projectNam
 	cmd, baseArgs := r.composeCommand()
 	args := append(baseArgs, "-f", composeFile, "-p", projectName, "down")
 
-	_, err := r.exec(ctx, cmd, args...)
+	_, err := r.execWithEnv(ctx, r.composeEnv(nil), cmd, args...)
 	return err
 }
 
@@ -478,9 +789,22 @@ type mountJSON struct {
 	RW          bool   `json:"RW"`
 }
 
+// GetPorts returns the container's ports published on the host.
+func (r *Runtime) GetPorts(ctx context.Context, id string) ([]PortMapping, error) {
+	output, err := r.exec(ctx, r.executable, r.cli("inspect", "--format", "{{json .NetworkSettings.Ports}}", id)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect ports: %w", err)
+	}
+	ports, err := parsePortBindings(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ports: %w", err)
+	}
+	return ports, nil
+}
+
 // GetMounts returns all mounts for a container.
 func (r *Runtime) GetMounts(ctx context.Context, id string) ([]MountInfo, error) {
-	output, err := r.exec(ctx, r.executable, "inspect", "--format", "{{json .Mounts}}", id)
+	output, err := r.exec(ctx, r.executable, r.cli("inspect", "--format", "{{json .Mounts}}", id)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect mounts: %w", err)
 	}
@@ -3,8 +3,11 @@ package container
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -69,38 +72,107 @@ func TestParseContainerList_MultipleContainers(t *testing.T) {
 	}
 }
 
-func TestMapState_Running(t *testing.T) {
-	got := mapState("running")
-	if got != StateRunning {
-		t.Errorf("mapState(running): got %q, want %q", got, StateRunning)
+func TestMapState(t *testing.T) {
+	tests := []struct {
+		state    string
+		exitCode int
+		want     ContainerState
+	}{
+		{"running", 0, StateRunning},
+		{"Running", 0, StateRunning},
+		{"created", 0, StateCreated},
+		{"paused", 0, StatePaused},
+		{"exited", 0, StateStopped},
+		{"exited", 1, StateExited},
+		{"exited", 137, StateExited},
+		{"dead", 0, StateStopped},
+		{"removing", 0, StateStopped},
+		{"unknown-state", 0, StateStopped},
 	}
-}
-
-func TestMapState_Stopped(t *testing.T) {
-	got := mapState("exited")
-	if got != StateStopped {
-		t.Errorf("mapState(exited): got %q, want %q", got, StateStopped)
+	for _, tt := range tests {
+		if got := mapState(tt.state, tt.exitCode); got != tt.want {
+			t.Errorf("mapState(%q, %d) = %q, want %q", tt.state, tt.exitCode, got, tt.want)
+		}
 	}
 }
 
-func TestMapState_Created(t *testing.T) {
-	got := mapState("created")
-	if got != StateCreated {
-		t.Errorf("mapState(created): got %q, want %q", got, StateCreated)
+func TestParseContainerList_ExitCodes(t *testing.T) {
+	r := NewRuntimeWithExecutor("docker", nil)
+	tests := []struct {
+		name     string
+		output   string
+		want     ContainerState
+		wantCode int
+	}{
+		{
+			name:     "docker exited with error",
+			output:   `{"ID":"a","Names":"c","State":"exited","Status":"Exited (137) 2 hours ago","Labels":""}`,
+			want:     StateExited,
+			wantCode: 137,
+		},
+		{
+			name:   "docker exited cleanly",
+			output: `{"ID":"a","Names":"c","State":"exited","Status":"Exited (0) 5 seconds ago","Labels":""}`,
+			want:   StateStopped,
+		},
+		{
+			name:   "docker paused",
+			output: `{"ID":"a","Names":"c","State":"paused","Status":"Up 3 minutes (Paused)","Labels":""}`,
+			want:   StatePaused,
+		},
+		{
+			name:   "docker running ignores status",
+			output: `{"ID":"a","Names":"c","State":"running","Status":"Up 2 hours","Labels":""}`,
+			want:   StateRunning,
+		},
+		{
+			name:     "podman exit code field",
+			output:   `[{"Id":"a","Names":["c"],"State":"exited","ExitCode":2,"Exited":true,"Labels":{}}]`,
+			want:     StateExited,
+			wantCode: 2,
+		},
+		{
+			name:   "podman exited cleanly",
+			output: `[{"Id":"a","Names":["c"],"State":"exited","ExitCode":0,"Exited":true,"Labels":{}}]`,
+			want:   StateStopped,
+		},
 	}
-}
-
-func TestMapState_Paused(t *testing.T) {
-	got := mapState("paused")
-	if got != StateStopped {
-		t.Errorf("mapState(paused): got %q, want %q", got, StateStopped)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err := r.parseContainerList(tt.output)
+			if err != nil || len(containers) != 1 {
+				t.Fatalf("parseContainerList() = %v, %v; want one container", containers, err)
+			}
+			c := containers[0]
+			if c.State != tt.want || c.ExitCode != tt.wantCode {
+				t.Errorf("state = %q (exit %d), want %q (exit %d)", c.State, c.ExitCode, tt.want, tt.wantCode)
+			}
+		})
 	}
 }
 
-func TestMapState_Unknown(t *testing.T) {
-	got := mapState("unknown-state")
-	if got != StateStopped {
-		t.Errorf("mapState(unknown): got %q, want %q", got, StateStopped)
+func TestInspectContainer_ExitCode(t *testing.T) {
+	tests := []struct {
+		output string
+		want   ContainerState
+	}{
+		{"running 0\n", StateRunning},
+		{"exited 0\n", StateStopped},
+		{"exited 1\n", StateExited},
+		{"paused 0\n", StatePaused},
+		{"restarting 0\n", ContainerState("restarting")},
+	}
+	for _, tt := range tests {
+		r := NewRuntimeWithExecutor("docker", func(ctx context.Context, name string, args ...string) (string, error) {
+			return tt.output, nil
+		})
+		got, err := r.InspectContainer(context.Background(), "abc")
+		if err != nil {
+			t.Fatalf("InspectContainer() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("InspectContainer() with %q = %q, want %q", tt.output, got, tt.want)
+		}
 	}
 }
 
@@ -174,6 +246,38 @@ func TestListContainers_CallsCorrectCommand(t *testing.T) {
 	}
 }
 
+func TestListAllContainers_OmitsManagedFilter(t *testing.T) {
+	var capturedArgs []string
+	mockExec := func(ctx context.Context, name string, args ...string) (string, error) {
+		capturedArgs = args
+		return "", nil
+	}
+
+	r := NewRuntimeWithExecutor("docker", mockExec)
+	_, _ = r.ListAllContainers(context.Background())
+
+	want := []string{"ps", "-a", "--no-trunc", "--format", "json"}
+	if !slices.Equal(capturedArgs, want) {
+		t.Errorf("args = %v, want %v", capturedArgs, want)
+	}
+}
+
+func TestLogs_CallsCorrectCommand(t *testing.T) {
+	var capturedArgs []string
+	mockExec := func(ctx context.Context, name string, args ...string) (string, error) {
+		capturedArgs = args
+		return "", nil
+	}
+
+	r := NewRuntimeWithExecutor("docker", mockExec)
+	_, _ = r.Logs(context.Background(), "abc123", 200)
+
+	want := []string{"logs", "--timestamps", "--tail", "200", "abc123"}
+	if !slices.Equal(capturedArgs, want) {
+		t.Errorf("args = %v, want %v", capturedArgs, want)
+	}
+}
+
 func TestExec_CallsCorrectCommand(t *testing.T) {
 	var capturedArgs []string
 
@@ -197,6 +301,65 @@ func TestExec_CallsCorrectCommand(t *testing.T) {
 	}
 }
 
+func TestExecCapped_CallsCorrectCommand(t *testing.T) {
+	var capturedArgs []string
+	mockExec := func(ctx context.Context, name string, args ...string) (string, error) {
+		capturedArgs = args
+		return "0123456789", nil
+	}
+
+	r := NewRuntimeWithExecutor("docker", mockExec)
+	out, truncated, err := r.ExecCapped(context.Background(), "abc123", "vscode", []string{"id"}, 4)
+	if err != nil {
+		t.Fatalf("ExecCapped() error = %v", err)
+	}
+	if out != "0123" || !truncated {
+		t.Errorf("ExecCapped() = %q, %v; want %q, true", out, truncated, "0123")
+	}
+	if want := []string{"exec", "-u", "vscode", "abc123", "id"}; !slices.Equal(capturedArgs, want) {
+		t.Errorf("args = %v, want %v", capturedArgs, want)
+	}
+}
+
+func TestCappedOutputExecutor_DiscardsPastLimit(t *testing.T) {
+	out, truncated, err := cappedOutputExecutor(context.Background(), 1000, "sh", "-c", "head -c 5000000 /dev/zero")
+	if err != nil {
+		t.Fatalf("cappedOutputExecutor() error = %v", err)
+	}
+	if len(out) != 1000 || !truncated {
+		t.Errorf("output length = %d, truncated = %v; want 1000, true", len(out), truncated)
+	}
+
+	out, truncated, err = cappedOutputExecutor(context.Background(), 1000, "sh", "-c", "echo hi; echo oops >&2; exit 3")
+	if out != "hi\n" || truncated {
+		t.Errorf("output = %q, truncated = %v; want %q, false", out, truncated, "hi\n")
+	}
+	if err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("error = %v, want the command's stderr", err)
+	}
+}
+
+func TestStreamingExecutor_LongLines(t *testing.T) {
+	// A line past bufio.Scanner's 64KB default is passed on whole
+	var lines []string
+	err := streamingExecutor(context.Background(), nil, func(line string) { lines = append(lines, line) },
+		"sh", "-c", "head -c 100000 /dev/zero | tr '\\0' a; echo; echo done")
+	if err != nil {
+		t.Fatalf("streamingExecutor() error = %v", err)
+	}
+	if len(lines) != 2 || len(lines[0]) != 100000 || lines[1] != "done" {
+		t.Errorf("got %d lines (first %d bytes), want the 100000-byte line and done", len(lines), len(lines[0]))
+	}
+
+	// One past maxStreamLine is an error, and the command is not left blocked
+	// on its undrained output
+	err = streamingExecutor(context.Background(), nil, nil,
+		"sh", "-c", fmt.Sprintf("head -c %d /dev/zero | tr '\\0' a; echo; head -c 1000000 /dev/zero", maxStreamLine+1))
+	if err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Errorf("error = %v, want the scanner's token too long error", err)
+	}
+}
+
 func TestListContainers_ReturnsError(t *testing.T) {
 	mockExec := func(ctx context.Context, name string, args ...string) (string, error) {
 		return "", errors.New("docker not running")
@@ -210,7 +373,7 @@ func TestListContainers_ReturnsError(t *testing.T) {
 }
 
 func TestNewRuntime(t *testing.T) {
-	r := NewRuntime("podman")
+	r := NewRuntime("podman", "podman-compose", "")
 	if r.executable != "podman" {
 		t.Errorf("executable: got %q, want %q", r.executable, "podman")
 	}
@@ -788,3 +951,59 @@ func TestExecWithEnv_WithNonEmptyMap(t *testing.T) {
 		t.Error("Expected r.exec to be called when env is empty")
 	}
 }
+
+func TestRuntime_HostReachesEveryCommand(t *testing.T) {
+	type call struct {
+		name string
+		args []string
+	}
+	var calls []call
+	mockExec := func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, call{name, args})
+		return "[]", nil
+	}
+	ctx := context.Background()
+
+	docker := NewRuntimeWithExecutor("docker", mockExec)
+	docker.host = "ssh://core@build-box"
+	_, _ = docker.ListContainers(ctx)
+	_, _ = docker.Exec(ctx, "abc", []string{"ls"})
+	_, _ = docker.Logs(ctx, "abc", 10)
+	_ = docker.ComposeStart(ctx, "/test/project", "testproj")
+
+	podman := NewRuntimeWithExecutor("podman", mockExec)
+	podman.compose = []string{"podman", "compose"}
+	podman.host = "unix:///run/user/1000/podman/podman.sock"
+	_, _ = podman.InspectContainer(ctx, "abc")
+	_ = podman.ComposeDown(ctx, "/test/project", "testproj")
+
+	want := map[string]string{"docker": "--host=ssh://core@build-box", "podman": "--url=unix:///run/user/1000/podman/podman.sock"}
+	if len(calls) != 6 {
+		t.Fatalf("got %d calls, want 6: %v", len(calls), calls)
+	}
+	for _, c := range calls {
+		if len(c.args) == 0 || c.args[0] != want[c.name] {
+			t.Errorf("%s %v: want the host flag %q first", c.name, c.args, want[c.name])
+		}
+	}
+
+	// A standalone compose binary reads the host from its environment.
+	podman.compose = []string{"podman-compose"}
+	if got := podman.composeEnv(map[string]string{"PORT": "1"}); got["CONTAINER_HOST"] != podman.host || got["PORT"] != "1" {
+		t.Errorf("composeEnv() = %v, want CONTAINER_HOST and PORT", got)
+	}
+	if cmd, args := podman.composeCommand(); cmd != "podman-compose" || len(args) != 0 {
+		t.Errorf("composeCommand() = %s %v, want podman-compose without flags", cmd, args)
+	}
+	docker.host = ""
+	if got := docker.cli("ps"); !slices.Equal(got, []string{"ps"}) {
+		t.Errorf("cli() without host = %v, want [ps]", got)
+	}
+
+	// A podman given by path is still podman, as it is for the host variable.
+	podmanPath := NewRuntimeWithExecutor("/usr/bin/podman", mockExec)
+	podmanPath.host = podman.host
+	if got := podmanPath.hostArgs(); !slices.Equal(got, []string{"--url=" + podman.host}) {
+		t.Errorf("hostArgs() for /usr/bin/podman = %v, want --url", got)
+	}
+}
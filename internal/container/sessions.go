@@ -0,0 +1,83 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"devagent/internal/tmux"
+)
+
+// DuplicateSessions recreates the source container's tmux sessions, by name,
+// in each target container. Sessions a target already has are skipped, as is
+// the source if it is listed as a target. New sessions start a shell in
+// tmux's default directory. Returns the number of sessions created; failures
+// are collected and returned together after trying every target.
+func (m *Manager) DuplicateSessions(ctx context.Context, sourceID string, targetIDs []string) (int, error) {
+	source, ok := m.Get(sourceID)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrContainerNotFound, sourceID)
+	}
+	sessions, err := m.tmuxClient.ListSessions(ctx, source.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions of %s: %w", source.Name, err)
+	}
+	if len(sessions) == 0 {
+		return 0, fmt.Errorf("%s has no sessions to duplicate", source.Name)
+	}
+
+	created := 0
+	var errs []error
+	for _, id := range targetIDs {
+		if id == source.ID {
+			continue
+		}
+		n, err := m.duplicateSessionsTo(ctx, id, sessions)
+		created += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if created > 0 {
+		m.notifyChange()
+	}
+
+	m.containerLogger(source.Name).Info("sessions duplicated", "containerID", source.ID, "targets", len(targetIDs), "created", created, "errors", len(errs))
+	return created, errors.Join(errs...)
+}
+
+// duplicateSessionsTo creates the named sessions missing from one target.
+func (m *Manager) duplicateSessionsTo(ctx context.Context, targetID string, sessions []tmux.Session) (int, error) {
+	target, ok := m.Get(targetID)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrContainerNotFound, targetID)
+	}
+	if !target.IsRunning() {
+		return 0, fmt.Errorf("%w: %s", ErrNotRunning, target.Name)
+	}
+
+	existing, err := m.tmuxClient.ListSessions(ctx, target.ID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to list sessions: %w", target.Name, err)
+	}
+	have := make(map[string]bool, len(existing))
+	for _, s := range existing {
+		have[s.Name] = true
+	}
+
+	created := 0
+	var errs []error
+	for _, s := range sessions {
+		if have[s.Name] {
+			continue
+		}
+		if err := m.tmuxClient.CreateSession(ctx, target.ID, s.Name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: create session %s: %w", target.Name, s.Name, err))
+			continue
+		}
+		created++
+	}
+	return created, errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package container
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"devagent/internal/config"
+)
+
+func TestDuplicateSessions_ReplicatesNamesToTarget(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{
+			{ID: "src", Name: "alpha", State: StateRunning},
+			{ID: "dst", Name: "bravo", State: StateRunning},
+			{ID: "off", Name: "charlie", State: StateStopped},
+		},
+		execAsOutput: func(id string, cmd []string) string {
+			if !slices.Contains(cmd, "list-sessions") {
+				return ""
+			}
+			switch id {
+			case "src":
+				return "dev: 1 windows (created Mon Jan  1 00:00:00 2024)\n" +
+					"test: 1 windows (created Mon Jan  1 00:00:00 2024)\n" +
+					"logs: 1 windows (created Mon Jan  1 00:00:00 2024)\n"
+			case "dst":
+				return "test: 1 windows (created Mon Jan  1 00:00:00 2024)\n"
+			}
+			return ""
+		},
+	}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	created, err := mgr.DuplicateSessions(context.Background(), "src", []string{"src", "dst"})
+	if err != nil {
+		t.Fatalf("DuplicateSessions() error = %v", err)
+	}
+	if created != 2 {
+		t.Errorf("created = %d, want 2", created)
+	}
+
+	// dst already has "test"; only dev and logs are created, in source order
+	var newSessions []string
+	for i, cmd := range mock.execAsCalls {
+		if slices.Contains(cmd, "new-session") {
+			if mock.execAsIDs[i] != "dst" {
+				t.Errorf("new-session ran in %s, want dst", mock.execAsIDs[i])
+			}
+			newSessions = append(newSessions, cmd[len(cmd)-1])
+		}
+	}
+	if !slices.Equal(newSessions, []string{"dev", "logs"}) {
+		t.Errorf("created sessions = %v, want [dev logs]", newSessions)
+	}
+
+	// A stopped target is reported without stopping the other targets
+	mock.execAsCalls, mock.execAsIDs = nil, nil
+	_, err = mgr.DuplicateSessions(context.Background(), "src", []string{"off", "dst"})
+	if err == nil || !strings.Contains(err.Error(), "charlie") {
+		t.Errorf("DuplicateSessions() error = %v, want it to name the stopped target", err)
+	}
+}
+
+func TestCreateSession_UpdatesLastActivity(t *testing.T) {
+	mock := &mockRuntime{
+		containers: []Container{{ID: "app", Name: "alpha", State: StateRunning}},
+	}
+	mgr := NewManager(ManagerOptions{Config: &config.Config{}, Runtime: mock})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if _, ok := mgr.LastActivity("app"); ok {
+		t.Fatal("LastActivity() reported activity before any")
+	}
+
+	before := time.Now()
+	if err := mgr.CreateSession(context.Background(), "app", "dev"); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	last, ok := mgr.LastActivity("app")
+	if !ok {
+		t.Fatal("LastActivity() reported no activity after CreateSession")
+	}
+	if last.Before(before) {
+		t.Errorf("LastActivity() = %v, want at or after %v", last, before)
+	}
+
+	// Activity of removed containers is dropped on refresh
+	mock.containers = nil
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, ok := mgr.LastActivity("app"); ok {
+		t.Error("LastActivity() kept activity of a removed container")
+	}
+}
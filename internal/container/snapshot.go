@@ -0,0 +1,120 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned when a container has no creation snapshot
+// (e.g. it was created before snapshots existed or outside devagent). It is an
+// ErrNotFound.
+var ErrSnapshotNotFound error = &kindError{msg: "creation snapshot not found", kind: ErrNotFound}
+
+// snapshotFiles are the generated files captured at creation time, relative to
+// the project's .devcontainer directory. filter.py is the proxy allowlist that
+// enforces network isolation.
+var snapshotFiles = []string{
+	"devcontainer.json",
+	"docker-compose.yml",
+	filterScriptRelPath,
+}
+
+// CreationSnapshot records the configuration a container was created with, so it
+// can be inspected after the template or config changes.
+type CreationSnapshot struct {
+	ContainerID    string            `json:"container_id"`
+	Name           string            `json:"name"`
+	Template       string            `json:"template"`
+	ProjectPath    string            `json:"project_path"`
+	ComposeProject string            `json:"compose_project"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Ports          map[string]string `json:"ports,omitempty"`
+	Isolation      IsolationSnapshot `json:"isolation"`
+	Files          map[string]string `json:"files"` // .devcontainer-relative path -> contents
+}
+
+// IsolationSnapshot records the container's isolation settings at creation
+// time: runtime capabilities and limits, network, and the proxy allowlist
+// (including domains added from network.registries).
+type IsolationSnapshot struct {
+	DroppedCaps             []string `json:"dropped_caps,omitempty"`
+	AddedCaps               []string `json:"added_caps,omitempty"`
+	MemoryLimit             string   `json:"memory_limit,omitempty"`
+	CPULimit                string   `json:"cpu_limit,omitempty"`
+	PidsLimit               int      `json:"pids_limit,omitempty"`
+	NetworkIsolated         bool     `json:"network_isolated"`
+	NetworkName             string   `json:"network_name,omitempty"`
+	AllowedDomains          []string `json:"allowed_domains,omitempty"`
+	ExtraAllowedDomains     string   `json:"extra_allowed_domains,omitempty"`
+	ExtraPassthroughDomains string   `json:"extra_passthrough_domains,omitempty"`
+}
+
+// snapshotPath returns the snapshot file for a container ID.
+func snapshotPath(containerID string) string {
+	return filepath.Join(getDataDir(), "snapshots", containerID+".json")
+}
+
+// readSnapshotFiles reads the generated files from a project's .devcontainer
+// directory. Files that do not exist are skipped.
+func readSnapshotFiles(projectPath string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, rel := range snapshotFiles {
+		data, err := os.ReadFile(filepath.Join(projectPath, ".devcontainer", rel))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+	}
+	return files, nil
+}
+
+// writeSnapshot stores a snapshot atomically (temp file + rename).
+func writeSnapshot(s *CreationSnapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := snapshotPath(s.ContainerID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readSnapshot loads a container's snapshot, returning ErrSnapshotNotFound if
+// none was written.
+func readSnapshot(containerID string) (*CreationSnapshot, error) {
+	data, err := os.ReadFile(snapshotPath(containerID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s CreationSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	return &s, nil
+}
+
+// removeSnapshot deletes a container's snapshot. A missing file is not an error.
+func removeSnapshot(containerID string) error {
+	if err := os.Remove(snapshotPath(containerID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
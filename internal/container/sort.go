@@ -0,0 +1,81 @@
+// pattern: Functional Core
+
+package container
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey identifies the field used to order containers.
+type SortKey string
+
+const (
+	SortByName    SortKey = "name"
+	SortByState   SortKey = "state"
+	SortByCreated SortKey = "created"
+)
+
+// SortKeys lists the supported sort keys in cycling order.
+var SortKeys = []SortKey{SortByName, SortByState, SortByCreated}
+
+// ParseSortKey parses a sort key name. An empty string selects SortByName.
+func ParseSortKey(s string) (SortKey, error) {
+	if s == "" {
+		return SortByName, nil
+	}
+	key := SortKey(strings.ToLower(s))
+	if !slices.Contains(SortKeys, key) {
+		return "", fmt.Errorf("invalid sort key %q (want name, state, or created)", s)
+	}
+	return key, nil
+}
+
+// Next returns the sort key that follows k in SortKeys, wrapping around.
+func (k SortKey) Next() SortKey {
+	idx := slices.Index(SortKeys, k)
+	return SortKeys[(idx+1)%len(SortKeys)]
+}
+
+// stateRank orders states for SortByState: running first, then paused, then
+// created, then stopped and exited.
+func stateRank(s ContainerState) int {
+	switch s {
+	case StateRunning:
+		return 0
+	case StatePaused:
+		return 1
+	case StateCreated:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SortContainers sorts containers in place by key. The sort is stable and ties
+// are broken by name, then ID, so the order is deterministic across refreshes.
+// With desc, the primary key is reversed; tie-breaking stays ascending.
+func SortContainers(containers []*Container, key SortKey, desc bool) {
+	slices.SortStableFunc(containers, func(a, b *Container) int {
+		var c int
+		switch key {
+		case SortByState:
+			c = stateRank(a.State) - stateRank(b.State)
+		case SortByCreated:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			c = strings.Compare(a.Name, b.Name)
+		}
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		if c = strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
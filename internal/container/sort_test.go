@@ -0,0 +1,82 @@
+package container
+
+import (
+	"testing"
+	"time"
+)
+
+func sortTestContainers() []*Container {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*Container{
+		{ID: "3", Name: "charlie", State: StateStopped, CreatedAt: base.Add(1 * time.Hour)},
+		{ID: "1", Name: "alpha", State: StateRunning, CreatedAt: base.Add(3 * time.Hour)},
+		{ID: "2", Name: "bravo", State: StateRunning, CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "4", Name: "delta", State: StateCreated, CreatedAt: base},
+	}
+}
+
+func names(containers []*Container) []string {
+	var result []string
+	for _, c := range containers {
+		result = append(result, c.Name)
+	}
+	return result
+}
+
+func TestSortContainers(t *testing.T) {
+	tests := []struct {
+		key  SortKey
+		desc bool
+		want []string
+	}{
+		{SortByName, false, []string{"alpha", "bravo", "charlie", "delta"}},
+		{SortByName, true, []string{"delta", "charlie", "bravo", "alpha"}},
+		// Running first; ties broken by name
+		{SortByState, false, []string{"alpha", "bravo", "delta", "charlie"}},
+		{SortByState, true, []string{"charlie", "delta", "alpha", "bravo"}},
+		{SortByCreated, false, []string{"delta", "charlie", "bravo", "alpha"}},
+		{SortByCreated, true, []string{"alpha", "bravo", "charlie", "delta"}},
+	}
+
+	for _, tt := range tests {
+		containers := sortTestContainers()
+		SortContainers(containers, tt.key, tt.desc)
+		got := names(containers)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortContainers(%s, desc=%v) = %v, want %v", tt.key, tt.desc, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSortContainers_TiesAreDeterministic(t *testing.T) {
+	a := []*Container{{ID: "b", Name: "same"}, {ID: "a", Name: "same"}}
+	b := []*Container{{ID: "a", Name: "same"}, {ID: "b", Name: "same"}}
+
+	SortContainers(a, SortByName, false)
+	SortContainers(b, SortByName, false)
+
+	if a[0].ID != "a" || b[0].ID != "a" {
+		t.Errorf("expected ID tie-break regardless of input order, got %s and %s", a[0].ID, b[0].ID)
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	if key, err := ParseSortKey(""); err != nil || key != SortByName {
+		t.Errorf("ParseSortKey(\"\") = %q, %v; want name, nil", key, err)
+	}
+	if key, err := ParseSortKey("State"); err != nil || key != SortByState {
+		t.Errorf("ParseSortKey(\"State\") = %q, %v; want state, nil", key, err)
+	}
+	if _, err := ParseSortKey("size"); err == nil {
+		t.Error("ParseSortKey(\"size\") should fail")
+	}
+}
+
+func TestSortKey_Next(t *testing.T) {
+	if SortByName.Next() != SortByState || SortByState.Next() != SortByCreated || SortByCreated.Next() != SortByName {
+		t.Error("Next() should cycle name -> state -> created -> name")
+	}
+}
@@ -0,0 +1,102 @@
+// pattern: Imperative Shell
+
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// devcontainerJSON is the devcontainer config file, merged across template
+// layers instead of replaced.
+const devcontainerJSON = "devcontainer.json"
+
+// renderTemplateFile renders rel from a .devcontainer directory: "<rel>.tmpl"
+// is executed with data, otherwise rel is read as is. ok is false when the
+// directory has neither.
+func renderTemplateFile(dir, rel string, data TemplateData) (content string, ok bool, err error) {
+	src := filepath.Join(dir, rel)
+	if _, err := os.Stat(src + ".tmpl"); err == nil {
+		content, err := processTemplate(src+".tmpl", data)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to process template %s: %w", rel+".tmpl", err)
+		}
+		return content, true, nil
+	}
+	raw, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(raw), true, nil
+}
+
+// renderLayeredFile renders rel from template layers (outermost base first):
+// devcontainer.json is the deep merge of every layer's (see
+// mergeDevcontainerJSON), any other file comes from the last layer that has
+// it. ok is false when no layer has the file.
+func renderLayeredFile(dirs []string, rel string, data TemplateData) (string, bool, error) {
+	if rel == devcontainerJSON && len(dirs) > 1 {
+		return mergeDevcontainerJSON(dirs, data)
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		content, ok, err := renderTemplateFile(dirs[i], rel, data)
+		if err != nil || ok {
+			return content, ok, err
+		}
+	}
+	return "", false, nil
+}
+
+// mergeDevcontainerJSON renders each layer's devcontainer.json and merges
+// them in order with mergeJSONObjects, so a child template overrides values
+// such as image while maps such as features and containerEnv are combined.
+func mergeDevcontainerJSON(dirs []string, data TemplateData) (string, bool, error) {
+	var merged map[string]any
+	for _, dir := range dirs {
+		content, ok, err := renderTemplateFile(dir, devcontainerJSON, data)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			continue
+		}
+		var layer map[string]any
+		if err := json.Unmarshal([]byte(content), &layer); err != nil {
+			return "", false, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, devcontainerJSON), err)
+		}
+		merged = mergeJSONObjects(merged, layer)
+	}
+	if merged == nil {
+		return "", false, nil
+	}
+	out, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", false, err
+	}
+	return string(out) + "\n", true, nil
+}
+
+// mergeJSONObjects merges override into base: keys holding objects on both
+// sides are merged recursively, any other override value replaces base's.
+func mergeJSONObjects(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if ov, ok := v.(map[string]any); ok {
+			if bv, ok := merged[k].(map[string]any); ok {
+				merged[k] = mergeJSONObjects(bv, ov)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,108 @@
+package container
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"devagent/internal/config"
+	"devagent/internal/logging"
+)
+
+func writeLayerFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, ".devcontainer", rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestComposeGenerator_WriteToProject_ExtendedTemplate(t *testing.T) {
+	baseDir := t.TempDir()
+	childDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	writeLayerFiles(t, baseDir, map[string]string{
+		"devcontainer.json.tmpl": `{
+  "name": "{{.ProjectName}}",
+  "image": "ubuntu:22.04",
+  "features": {"ghcr.io/devcontainers/features/git:1": {}},
+  "containerEnv": {"EDITOR": "vim", "LANG": "C.UTF-8"}
+}`,
+		"docker-compose.yml.tmpl": "services:\n  app:\n    image: base\n",
+		"Dockerfile":              "FROM ubuntu\n",
+	})
+	writeLayerFiles(t, childDir, map[string]string{
+		"devcontainer.json":       `{"image": "golang:1.24", "containerEnv": {"EDITOR": "nano", "GOFLAGS": "-mod=mod"}}`,
+		"docker-compose.yml.tmpl": "services:\n  app:\n    image: child\n",
+	})
+
+	gen := NewComposeGenerator(&config.Config{}, []config.Template{
+		{Name: "go", Path: childDir, Extends: "base", BasePaths: []string{baseDir}},
+	}, logging.NopLogger())
+	if err := gen.WriteToProject(projectDir, "go", TemplateData{ProjectName: "demo"}); err != nil {
+		t.Fatalf("WriteToProject() error = %v", err)
+	}
+
+	devDir := filepath.Join(projectDir, ".devcontainer")
+	if data, err := os.ReadFile(filepath.Join(devDir, "Dockerfile")); err != nil || string(data) != "FROM ubuntu\n" {
+		t.Errorf("Dockerfile = %q, %v; want the base's", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(devDir, "docker-compose.yml")); string(data) != "services:\n  app:\n    image: child\n" {
+		t.Errorf("docker-compose.yml = %q, want the child's", data)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(devDir, "devcontainer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Name         string            `json:"name"`
+		Image        string            `json:"image"`
+		Features     map[string]any    `json:"features"`
+		ContainerEnv map[string]string `json:"containerEnv"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("merged devcontainer.json is invalid: %v\n%s", err, raw)
+	}
+	if got.Name != "demo" {
+		t.Errorf("name = %q, want rendered base value", got.Name)
+	}
+	if got.Image != "golang:1.24" {
+		t.Errorf("image = %q, want child override", got.Image)
+	}
+	if _, ok := got.Features["ghcr.io/devcontainers/features/git:1"]; !ok {
+		t.Errorf("features = %v, want inherited git feature", got.Features)
+	}
+	wantEnv := map[string]string{"EDITOR": "nano", "LANG": "C.UTF-8", "GOFLAGS": "-mod=mod"}
+	if len(got.ContainerEnv) != len(wantEnv) {
+		t.Errorf("containerEnv = %v, want %v", got.ContainerEnv, wantEnv)
+	}
+	for k, v := range wantEnv {
+		if got.ContainerEnv[k] != v {
+			t.Errorf("containerEnv[%s] = %q, want %q", k, got.ContainerEnv[k], v)
+		}
+	}
+}
+
+func TestComposeGenerator_WriteToProject_ChildWithoutDevcontainerDir(t *testing.T) {
+	baseDir := t.TempDir()
+	projectDir := t.TempDir()
+	writeLayerFiles(t, baseDir, map[string]string{"docker-compose.yml.tmpl": "services:\n  app:\n"})
+
+	gen := NewComposeGenerator(&config.Config{}, []config.Template{
+		{Name: "child", Path: t.TempDir(), Extends: "base", BasePaths: []string{baseDir}},
+	}, logging.NopLogger())
+	if err := gen.WriteToProject(projectDir, "child", TemplateData{}); err != nil {
+		t.Fatalf("WriteToProject() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")); err != nil {
+		t.Errorf("base docker-compose.yml not written: %v", err)
+	}
+}
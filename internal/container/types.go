@@ -14,7 +14,9 @@ type ContainerState string
 const (
 	StateCreated ContainerState = "created"
 	StateRunning ContainerState = "running"
-	StateStopped ContainerState = "stopped"
+	StateStopped ContainerState = "stopped" // exited cleanly (code 0), dead or being removed
+	StateExited  ContainerState = "exited"  // exited with a non-zero code (see Container.ExitCode)
+	StatePaused  ContainerState = "paused"
 )
 
 // Container represents a devagent-managed container.
@@ -26,21 +28,25 @@ type Container struct {
 	Agent          string
 	RemoteUser     string // User for exec commands (default: vscode)
 	State          ContainerState
+	ExitCode       int // Non-zero exit code when State is StateExited
 	CreatedAt      time.Time
 	Labels         map[string]string
 	ComposeProject string            // Docker Compose project name (from com.docker.compose.project label)
 	Ports          map[string]string // Allocated host ports (env var name → port string)
 	Sessions       []tmux.Session
+	SidecarWarning string // Set by Refresh when the container runs but a sidecar (e.g. its proxy) does not
+	Unmanaged      bool   // Set by ListAll for containers without the devagent.managed=true label
 }
 
 // Sidecar represents an auxiliary container that provides services to a devcontainer.
 // ParentRef contains the compose project name, which groups the app and sidecar containers.
 type Sidecar struct {
-	ID        string // Container ID
-	Name      string // Container name (auto-generated by Docker Compose, e.g., "myproject-proxy-1")
-	Type      string // Sidecar type (e.g., "proxy")
-	ParentRef string // Compose project name linking sidecar to devcontainer
-	State     ContainerState
+	ID         string // Container ID
+	Name       string // Container name (auto-generated by Docker Compose, e.g., "myproject-proxy-1")
+	Type       string // Sidecar type (e.g., "proxy")
+	ParentRef  string // Compose project name linking sidecar to devcontainer
+	ProjectDir string // Project root, derived from the compose working dir label (empty if unknown)
+	State      ContainerState
 }
 
 // BuildConfig represents the build section of a devcontainer.json.
@@ -85,6 +91,22 @@ type CreateOptions struct {
 	Name        string
 	Agent       string
 	OnProgress  ProgressCallback // Optional callback for progress updates
+	Force       bool             // Create even if the project already has a container
+	ExtraMounts []string         // Additional docker --mount strings for the app service
+	// UseExisting keeps the project's own .devcontainer/devcontainer.json
+	// instead of overwriting it with the template's; the other template files
+	// (and the devagent labels in docker-compose.yml) are still written.
+	// The kept file does not define the container: compose builds and starts
+	// it from the template's docker-compose.yml, so the file's image, build,
+	// runArgs and mounts are not applied. Templates with
+	// use_existing_devcontainer always keep it.
+	UseExisting bool
+}
+
+// DestroyOptions holds options for destroying a container.
+type DestroyOptions struct {
+	Purge bool // Also remove the project's proxy cert directory (CleanupProxyConfigs)
+	Force bool // Destroy even a container protected by a do-not-delete note (IsProtected)
 }
 
 // Label constants for devagent metadata.
@@ -103,7 +125,8 @@ const (
 
 // Docker Compose label constants
 const (
-	LabelComposeProject = "com.docker.compose.project" // Set by devcontainer CLI / docker compose
+	LabelComposeProject    = "com.docker.compose.project"             // Set by devcontainer CLI / docker compose
+	LabelComposeWorkingDir = "com.docker.compose.project.working_dir" // Directory of the compose file
 )
 
 // DefaultRemoteUser is the default user for devcontainer exec commands.
@@ -140,6 +163,11 @@ func (c *Container) IsRunning() bool {
 	return c.State == StateRunning
 }
 
+// IsStopped returns true if the container has stopped, cleanly or not.
+func (c *Container) IsStopped() bool {
+	return c.State == StateStopped || c.State == StateExited
+}
+
 // MountInfo represents a bind mount or volume mount on a container.
 type MountInfo struct {
 	Type        string `json:"type"`        // "bind" or "volume"
@@ -148,6 +176,13 @@ type MountInfo struct {
 	ReadOnly    bool   `json:"read_only"`
 }
 
+// PortMapping is a container port published on the host.
+type PortMapping struct {
+	ContainerPort int    `json:"container"`
+	HostPort      int    `json:"host"`
+	Protocol      string `json:"protocol"` // "tcp" or "udp"
+}
+
 // FindTemplateForProject finds the template name used by existing containers for a project.
 // Falls back to "basic" if no existing containers found.
 // Note: After compose root launch, worktree containers share the project root as ProjectPath,
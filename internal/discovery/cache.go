@@ -0,0 +1,141 @@
+// pattern: Imperative Shell
+
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// scanCache records what a cached scan saw, keyed by directory modification
+// time, so the next scan can skip unchanged directories.
+type scanCache struct {
+	dirs     map[string]cachedDir     // walked directory -> its subdirectories
+	projects map[string]cachedProject // resolved candidate path -> detection result
+}
+
+// cachedDir is a directory listing, valid while the directory's mtime is
+// unchanged (adding, removing or renaming an entry updates it).
+type cachedDir struct {
+	modTime time.Time
+	subdirs []string
+}
+
+// cachedProject is the detection result for a candidate directory, valid while
+// its stamp is unchanged.
+type cachedProject struct {
+	stamp     projectStamp
+	project   DiscoveredProject
+	isProject bool
+}
+
+// projectStamp holds the mtimes that detection depends on: the directory
+// itself (Makefile, .devcontainer), its compose file (managed label), and its
+// git worktree metadata. Missing files have a zero time.
+type projectStamp struct {
+	dir       time.Time
+	compose   time.Time
+	worktrees time.Time
+}
+
+func newScanCache() *scanCache {
+	return &scanCache{
+		dirs:     make(map[string]cachedDir),
+		projects: make(map[string]cachedProject),
+	}
+}
+
+// ScanAllCached is ScanAll for repeated scans of the same paths. Directories
+// whose mtime is unchanged since the previous ScanAllCached are not re-read,
+// and projects whose directory, compose file and git worktree metadata are
+// unchanged are returned from the cache without re-parsing or running git.
+// Directories that are no longer reached are dropped from the cache.
+func (s *Scanner) ScanAllCached(paths []string) []DiscoveredProject {
+	return s.scanCached(paths).projects
+}
+
+// scanCached runs a cached scan and returns its walk state.
+func (s *Scanner) scanCached(paths []string) *scanWalk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := s.newWalk()
+	w.prev = s.cache
+	if w.prev == nil {
+		w.prev = newScanCache()
+	}
+	w.next = newScanCache()
+	w.scan(paths)
+	s.cache = w.next
+	return w
+}
+
+// subdirs lists dir's subdirectories, reusing the cached listing when dir's
+// mtime is unchanged.
+func (w *scanWalk) subdirs(dir string) ([]string, bool) {
+	if w.next == nil {
+		return readSubdirs(dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, false
+	}
+	if cached, ok := w.prev.dirs[dir]; ok && cached.modTime.Equal(info.ModTime()) {
+		w.next.dirs[dir] = cached
+		return cached.subdirs, true
+	}
+	names, ok := readSubdirs(dir)
+	if !ok {
+		return nil, false
+	}
+	w.next.dirs[dir] = cachedDir{modTime: info.ModTime(), subdirs: names}
+	return names, true
+}
+
+// detect reports whether path is a devagent project, reusing the cached
+// result when the project's stamp is unchanged.
+func (w *scanWalk) detect(name, path string) (DiscoveredProject, bool) {
+	if w.next == nil {
+		return detectProject(name, path)
+	}
+	stamp := statProject(path)
+	if cached, ok := w.prev.projects[path]; ok && cached.stamp == stamp {
+		w.next.projects[path] = cached
+		cached.project.Name = name
+		return cached.project, cached.isProject
+	}
+	project, ok := detectProject(name, path)
+	w.next.projects[path] = cachedProject{stamp: stamp, project: project, isProject: ok}
+	return project, ok
+}
+
+// statProject collects the mtimes in a project's stamp. The worktrees time is
+// the newest of .git/worktrees and its entries, which git updates when a
+// worktree is added, removed or switches branch.
+func statProject(path string) projectStamp {
+	var stamp projectStamp
+	stamp.dir = modTime(path)
+	stamp.compose = modTime(filepath.Join(path, ".devcontainer", "docker-compose.yml"))
+
+	worktreesDir := filepath.Join(path, ".git", "worktrees")
+	stamp.worktrees = modTime(worktreesDir)
+	if names, ok := readSubdirs(worktreesDir); ok {
+		for _, name := range names {
+			if t := modTime(filepath.Join(worktreesDir, name)); t.After(stamp.worktrees) {
+				stamp.worktrees = t
+			}
+		}
+	}
+	return stamp
+}
+
+// modTime returns path's modification time, or the zero time if it can't be
+// stat'ed.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
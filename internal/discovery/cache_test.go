@@ -0,0 +1,115 @@
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestScanAllCached_NewProjectAppearsAfterParentMtimeChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeManagedProject(t, filepath.Join(tmpDir, "alpha"))
+
+	scanner := NewScanner()
+	if got := projectNames(scanner.ScanAllCached([]string{tmpDir})); !slices.Equal(got, []string{"alpha"}) {
+		t.Fatalf("first scan = %v, want [alpha]", got)
+	}
+
+	info, err := os.Stat(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeManagedProject(t, filepath.Join(tmpDir, "bravo"))
+
+	// With the scan root's mtime put back, the cached listing is still used
+	if err := os.Chtimes(tmpDir, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if got := projectNames(scanner.ScanAllCached([]string{tmpDir})); !slices.Equal(got, []string{"alpha"}) {
+		t.Fatalf("scan with unchanged mtime = %v, want cached [alpha]", got)
+	}
+
+	// Once the scan root's mtime moves, bravo is found
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(tmpDir, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := projectNames(scanner.ScanAllCached([]string{tmpDir})); !slices.Equal(got, []string{"alpha", "bravo"}) {
+		t.Fatalf("scan after mtime change = %v, want [alpha bravo]", got)
+	}
+
+	// A full scan always sees the current tree
+	if got := projectNames(scanner.ScanAll([]string{tmpDir})); !slices.Equal(got, []string{"alpha", "bravo"}) {
+		t.Errorf("ScanAll() = %v, want [alpha bravo]", got)
+	}
+}
+
+func TestScanAllCached_ComposeChangeIsDetected(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "alpha")
+	composePath := filepath.Join(projectDir, ".devcontainer", "docker-compose.yml")
+	if err := os.MkdirAll(filepath.Dir(composePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(composePath, []byte("services:\n  app: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := NewScanner()
+	if got := scanner.ScanAllCached([]string{tmpDir}); len(got) != 0 {
+		t.Fatalf("unmanaged compose file: got %d projects, want 0", len(got))
+	}
+
+	// Adding the managed label updates the compose file's mtime
+	writeManagedProject(t, projectDir)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(composePath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := projectNames(scanner.ScanAllCached([]string{tmpDir})); !slices.Equal(got, []string{"alpha"}) {
+		t.Errorf("after labelling = %v, want [alpha]", got)
+	}
+}
+
+// benchmarkTree creates a scan root with count devagent projects.
+func benchmarkTree(b *testing.B, count int) string {
+	b.Helper()
+	root := b.TempDir()
+	compose := []byte("services:\n  app:\n    labels:\n      devagent.managed: \"true\"\n")
+	for i := 0; i < count; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("project-%03d", i), ".devcontainer")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "docker-compose.yml"), compose, 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return root
+}
+
+func BenchmarkScanAll(b *testing.B) {
+	root := benchmarkTree(b, 200)
+	scanner := NewScanner()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if got := scanner.ScanAll([]string{root}); len(got) != 200 {
+			b.Fatalf("found %d projects, want 200", len(got))
+		}
+	}
+}
+
+func BenchmarkScanAllCached(b *testing.B) {
+	root := benchmarkTree(b, 200)
+	scanner := NewScanner()
+	scanner.ScanAllCached([]string{root}) // warm the cache
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if got := scanner.ScanAllCached([]string{root}); len(got) != 200 {
+			b.Fatalf("found %d projects, want 200", len(got))
+		}
+	}
+}
@@ -3,17 +3,38 @@
 package discovery
 
 import (
-	"bufio"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
+
+	"devagent/internal/worktree"
 )
 
-// Scanner discovers projects in configured scan paths.
-type Scanner struct{}
+// DefaultMaxDepth is how many directory levels below a scan path are searched
+// when Scanner.MaxDepth is unset: only the scan path's direct children.
+const DefaultMaxDepth = 1
+
+// skipDirs are directory names never descended into while scanning.
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Scanner discovers projects in configured scan paths. A Scanner is safe for
+// concurrent use.
+type Scanner struct {
+	// MaxDepth bounds how many levels below each scan path are searched.
+	// Zero or negative means DefaultMaxDepth.
+	MaxDepth int
+
+	mu    sync.Mutex
+	cache *scanCache // results of the last ScanAllCached
+}
 
 // NewScanner creates a new project scanner.
 func NewScanner() *Scanner {
@@ -21,49 +42,185 @@ func NewScanner() *Scanner {
 }
 
 // ScanAll scans all provided paths for discoverable projects.
-// Each path is walked one level deep looking for directories containing
-// .devcontainer/docker-compose.yml with devagent.managed: "true" label.
+// Each path is walked up to MaxDepth levels deep looking for directories
+// containing .devcontainer/docker-compose.yml with devagent.managed: "true"
+// label. A project's subdirectories are not searched, and neither are skipped
+// directories (.git, node_modules, vendor) or those matched by the scan path's
+// top-level .gitignore.
+// Overlapping roots (the same directory listed twice, via a symlink or a
+// trailing slash, or nested roots) yield each project once, keyed by its
+// symlink-resolved path.
 func (s *Scanner) ScanAll(paths []string) []DiscoveredProject {
-	var projects []DiscoveredProject
-	seen := make(map[string]bool)
+	return s.newWalk().scan(paths)
+}
+
+// newWalk returns the state for one scan.
+func (s *Scanner) newWalk() *scanWalk {
+	maxDepth := s.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	return &scanWalk{maxDepth: maxDepth, seen: make(map[string]bool)}
+}
+
+// scan walks every scan path, skipping roots already walked.
+func (w *scanWalk) scan(paths []string) []DiscoveredProject {
+	seenRoots := make(map[string]bool)
 
 	for _, scanPath := range paths {
-		entries, err := os.ReadDir(scanPath)
+		root, err := filepath.EvalSymlinks(scanPath)
 		if err != nil {
-			continue // Skip inaccessible directories
+			root = filepath.Clean(scanPath)
+		}
+		if seenRoots[root] {
+			continue
 		}
+		seenRoots[root] = true
+
+		w.root = scanPath
+		w.ignore = loadGitignore(filepath.Join(scanPath, ".gitignore"))
+		w.walk(scanPath, 1)
+	}
+
+	return w.projects
+}
 
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-			projectPath := filepath.Join(scanPath, entry.Name())
-
-			// Resolve symlinks to get canonical path
-			resolved, err := filepath.EvalSymlinks(projectPath)
-			if err != nil {
-				resolved = projectPath
-			}
-			if seen[resolved] {
-				continue
-			}
-			seen[resolved] = true
-
-			if !isDevagentProject(resolved) {
-				continue
-			}
-
-			project := DiscoveredProject{
-				Name:        entry.Name(),
-				Path:        resolved,
-				HasMakefile: hasMakefile(resolved),
-				Worktrees:   listWorktrees(resolved),
-			}
-			projects = append(projects, project)
+// scanWalk holds the state of one ScanAll or ScanAllCached call.
+type scanWalk struct {
+	maxDepth int
+	root     string
+	ignore   []ignorePattern
+	seen     map[string]bool
+	projects []DiscoveredProject
+	dirs     []string // directories whose listing was walked
+	// candidates are the directories checked for a project that is not
+	// there, including those at maxDepth whose listing is not walked.
+	candidates []string
+
+	// prev and next are set for cached scans: prev is read, and everything
+	// this walk looks at is recorded in next.
+	prev, next *scanCache
+}
+
+// walk checks each subdirectory of dir, which lies depth-1 levels below the
+// scan root, and descends into those that are not projects.
+func (w *scanWalk) walk(dir string, depth int) {
+	names, ok := w.subdirs(dir)
+	if !ok {
+		return // Skip inaccessible directories
+	}
+	w.dirs = append(w.dirs, dir)
+
+	for _, name := range names {
+		projectPath := filepath.Join(dir, name)
+		if skipDirs[name] || w.ignored(projectPath) {
+			continue
+		}
+
+		// Resolve symlinks to get canonical path
+		resolved, err := filepath.EvalSymlinks(projectPath)
+		if err != nil {
+			resolved = projectPath
+		}
+		if w.seen[resolved] {
+			continue
+		}
+		w.seen[resolved] = true
+
+		if project, ok := w.detect(name, resolved); ok {
+			w.projects = append(w.projects, project)
+			continue
+		}
+		w.candidates = append(w.candidates, projectPath)
+		if depth < w.maxDepth {
+			w.walk(projectPath, depth+1)
 		}
 	}
+}
 
-	return projects
+// readSubdirs returns the names of dir's subdirectories (symlinks excluded).
+func readSubdirs(dir string) ([]string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, false
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, true
+}
+
+// detectProject reports whether path is a devagent project and, if so,
+// returns it with the given display name.
+func detectProject(name, path string) (DiscoveredProject, bool) {
+	if !isDevagentProject(path) {
+		return DiscoveredProject{}, false
+	}
+	return DiscoveredProject{
+		Name:        name,
+		Path:        path,
+		HasMakefile: hasMakefile(path),
+		Worktrees:   listWorktrees(path),
+	}, true
+}
+
+// ignorePattern is a directory pattern from a .gitignore file.
+type ignorePattern struct {
+	glob     string
+	anchored bool // match the path relative to the scan root, not just the name
+}
+
+// ignored reports whether path matches a pattern from the scan root's
+// .gitignore.
+func (w *scanWalk) ignored(path string) bool {
+	if len(w.ignore) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	name := filepath.Base(path)
+	for _, p := range w.ignore {
+		target := name
+		if p.anchored {
+			target = rel
+		}
+		if ok, _ := filepath.Match(p.glob, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadGitignore reads directory patterns from a .gitignore file, following
+// git's rule that a pattern with a leading or inner slash is relative to the
+// file's directory while others match a name at any depth. Comments, blank
+// lines and negations are dropped. Returns nil if the file is missing.
+func loadGitignore(path string) []ignorePattern {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var patterns []ignorePattern
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		line = strings.TrimSuffix(line, "/")
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+		patterns = append(patterns, ignorePattern{glob: line, anchored: anchored})
+	}
+	return patterns
 }
 
 // isDevagentProject checks if a directory has .devcontainer/docker-compose.yml
@@ -119,56 +276,22 @@ func listWorktrees(projectPath string) []Worktree {
 	return parseWorktreeList(string(output))
 }
 
-// parseWorktreeList parses the porcelain output of `git worktree list`.
-// Format:
-//
-//	worktree /path/to/worktree
-//	HEAD abc123
-//	branch refs/heads/branch-name
-//	<blank line>
-//
-// The first entry is the main worktree; we skip it and return only additional worktrees.
+// parseWorktreeList parses the porcelain output of `git worktree list` with
+// worktree.ParsePorcelain and returns the linked worktrees, skipping the
+// main one.
 func parseWorktreeList(output string) []Worktree {
 	var worktrees []Worktree
-	var current *Worktree
-
-	scanner := bufio.NewScanner(strings.NewReader(output))
-	isFirst := true
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "worktree ") {
-			// Save previous (non-first) worktree
-			if current != nil && !isFirst {
-				worktrees = append(worktrees, *current)
-			}
-			if current != nil {
-				isFirst = false
-			}
-			path := strings.TrimPrefix(line, "worktree ")
-			current = &Worktree{
-				Path: path,
-				Name: filepath.Base(path),
-			}
-		} else if strings.HasPrefix(line, "branch ") && current != nil {
-			branch := strings.TrimPrefix(line, "branch refs/heads/")
-			current.Branch = branch
-		} else if line == "" && current != nil {
-			// End of entry
-			if !isFirst {
-				worktrees = append(worktrees, *current)
-				current = nil
-			} else {
-				isFirst = false
-				current = nil
-			}
+	for _, info := range worktree.ParsePorcelain(output) {
+		if info.IsMain {
+			continue
 		}
+		worktrees = append(worktrees, Worktree{
+			Name:     info.Name,
+			Path:     info.Path,
+			Branch:   info.Branch,
+			Locked:   info.Locked,
+			Prunable: info.Prunable,
+		})
 	}
-
-	// Handle last entry if no trailing newline
-	if current != nil && !isFirst {
-		worktrees = append(worktrees, *current)
-	}
-
 	return worktrees
 }
@@ -3,6 +3,7 @@ package discovery
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -41,6 +42,65 @@ branch refs/heads/fix/bug-123
 	}
 }
 
+func TestParseWorktreeList_SlashNames(t *testing.T) {
+	output := `worktree /home/user/project
+HEAD abc123def456
+branch refs/heads/main
+
+worktree /home/user/project/.worktrees/feature/login
+HEAD def456abc123
+branch refs/heads/feature/login
+
+worktree /elsewhere/checkout
+HEAD 789abc123def
+branch refs/heads/other
+
+`
+	worktrees := parseWorktreeList(output)
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+	if worktrees[0].Name != "feature/login" {
+		t.Errorf("name = %q, want the path under .worktrees", worktrees[0].Name)
+	}
+	if worktrees[1].Name != "checkout" {
+		t.Errorf("name = %q, want the directory name outside .worktrees", worktrees[1].Name)
+	}
+}
+
+func TestParseWorktreeList_LockedAndPrunable(t *testing.T) {
+	output := `worktree /home/user/project
+HEAD abc123def456
+branch refs/heads/main
+
+worktree /home/user/project/.worktrees/pinned
+HEAD def456abc123
+branch refs/heads/pinned
+locked on removable disk
+
+worktree /home/user/project/.worktrees/gone
+HEAD 789abc123def
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+
+worktree /home/user/project/.worktrees/both
+HEAD 123def456abc
+detached
+locked
+prunable
+`
+	worktrees := parseWorktreeList(output)
+
+	want := []Worktree{
+		{Name: "pinned", Path: "/home/user/project/.worktrees/pinned", Branch: "pinned", Locked: true},
+		{Name: "gone", Path: "/home/user/project/.worktrees/gone", Branch: "gone", Prunable: true},
+		{Name: "both", Path: "/home/user/project/.worktrees/both", Locked: true, Prunable: true},
+	}
+	if !slices.Equal(worktrees, want) {
+		t.Errorf("parseWorktreeList() = %+v, want %+v", worktrees, want)
+	}
+}
+
 func TestParseWorktreeList_MainOnly(t *testing.T) {
 	output := `worktree /home/user/project
 HEAD abc123def456
@@ -234,3 +294,117 @@ func TestScanAll_DeduplicatesSymlinks(t *testing.T) {
 		t.Fatalf("expected 1 project (deduplicated), got %d", len(projects))
 	}
 }
+
+func TestScanAll_DeduplicatesOverlappingRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	composeContent := []byte(`services:
+  app:
+    labels:
+      devagent.managed: "true"
+`)
+	// code/alpha and code/work/bravo are projects; code/work is a nested scan root
+	for _, p := range []string{"code/alpha", "code/work/bravo"} {
+		devcontainerDir := filepath.Join(tmpDir, p, ".devcontainer")
+		if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(devcontainerDir, "docker-compose.yml"), composeContent, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	codeDir := filepath.Join(tmpDir, "code")
+	linkedRoot := filepath.Join(tmpDir, "code-link")
+	if err := os.Symlink(codeDir, linkedRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := NewScanner()
+	projects := scanner.ScanAll([]string{codeDir, codeDir + "/", linkedRoot, filepath.Join(codeDir, "work")})
+
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if len(projects) != 2 || names[0] != "alpha" || names[1] != "bravo" {
+		t.Errorf("ScanAll() names = %v, want [alpha bravo]", names)
+	}
+}
+
+// writeManagedProject creates a devagent project at dir.
+func writeManagedProject(t *testing.T, dir string) {
+	t.Helper()
+	devcontainerDir := filepath.Join(dir, ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	composeContent := []byte(`services:
+  app:
+    labels:
+      devagent.managed: "true"
+`)
+	if err := os.WriteFile(filepath.Join(devcontainerDir, "docker-compose.yml"), composeContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func projectNames(projects []DiscoveredProject) []string {
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestScanAll_MaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, p := range []string{"top", "group/mid", "group/sub/deep", "top/inner"} {
+		writeManagedProject(t, filepath.Join(tmpDir, p))
+	}
+
+	tests := []struct {
+		maxDepth int
+		want     []string
+	}{
+		{maxDepth: 0, want: []string{"top"}},
+		{maxDepth: 1, want: []string{"top"}},
+		{maxDepth: 2, want: []string{"mid", "top"}},
+		// top/inner is inside a project, so it is never searched
+		{maxDepth: 3, want: []string{"deep", "mid", "top"}},
+	}
+
+	for _, tt := range tests {
+		scanner := &Scanner{MaxDepth: tt.maxDepth}
+		got := projectNames(scanner.ScanAll([]string{tmpDir}))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("MaxDepth %d: ScanAll() names = %v, want %v", tt.maxDepth, got, tt.want)
+		}
+	}
+}
+
+func TestScanAll_SkipsIgnoredDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, p := range []string{
+		"node_modules/pkg",
+		"vendor/lib",
+		"group/.git/modules",
+		"build/out",
+		"archive/old",
+		"group/archive/kept",
+		"group/keep",
+	} {
+		writeManagedProject(t, filepath.Join(tmpDir, p))
+	}
+	gitignore := "# generated\nbuild/\n/archive\n!group\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(gitignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := &Scanner{MaxDepth: 3}
+	got := projectNames(scanner.ScanAll([]string{tmpDir}))
+	// "/archive" is anchored to the scan root, so group/archive is still searched
+	want := []string{"keep", "kept"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ScanAll() names = %v, want %v", got, want)
+	}
+}
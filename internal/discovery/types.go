@@ -4,9 +4,11 @@ package discovery
 
 // Worktree represents a git worktree for a project.
 type Worktree struct {
-	Name   string // Branch name or worktree directory name
-	Path   string // Absolute path to the worktree directory
-	Branch string // Git branch name
+	Name     string // Branch name or worktree directory name
+	Path     string // Absolute path to the worktree directory
+	Branch   string // Git branch name
+	Locked   bool   // Locked via `git worktree lock`
+	Prunable bool   // Directory is gone; `git worktree prune` would remove it
 }
 
 // DiscoveredProject represents a project found during directory scanning.
@@ -0,0 +1,198 @@
+// pattern: Imperative Shell
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long a Watcher waits for filesystem events to
+// settle before reporting a change.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// DefaultWatchFallback is how often a Watcher reports a change regardless of
+// events, so a project its watches cannot see (e.g. a compose file whose
+// devagent label is added later) is still picked up eventually.
+const DefaultWatchFallback = 5 * time.Minute
+
+// Watcher watches scan paths with fsnotify and reports when a project or git
+// worktree may have been added or removed. It watches every directory the
+// scanner walks (so creating, removing or renaming a project directory is
+// seen), each directory checked for a project, including those at the
+// deepest level, and its .devcontainer directory (so a compose file added to
+// an existing directory is seen), and each project's .git and .git/worktrees
+// directories. Changes that do not add or remove entries, such as editing a
+// compose file, are only picked up by the fallback report every
+// DefaultWatchFallback.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	scanner  *Scanner
+	paths    func() []string
+	debounce time.Duration
+	fallback time.Duration
+	onChange func()
+	resync   chan struct{}
+	// watched maps each watched directory to the only entry name whose
+	// events matter there, or "" for any entry.
+	watched map[string]string
+}
+
+// NewWatcher creates a watcher for the scan paths returned by paths, walked up
+// to maxDepth levels like Scanner.MaxDepth. onChange is called from Run once
+// events have been quiet for debounce. Returns an error if fsnotify is
+// unavailable or a directory can't be watched (e.g. the inotify watch limit
+// is reached); callers should fall back to polling.
+func NewWatcher(paths func() []string, maxDepth int, debounce time.Duration, onChange func()) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	w := &Watcher{
+		fsw:      fsw,
+		scanner:  &Scanner{MaxDepth: maxDepth},
+		paths:    paths,
+		debounce: debounce,
+		fallback: DefaultWatchFallback,
+		onChange: onChange,
+		resync:   make(chan struct{}, 1),
+		watched:  make(map[string]string),
+	}
+	if err := w.sync(); err != nil {
+		_ = fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Resync re-reads the scan paths and reports a change after the debounce,
+// e.g. after a config reload changed them. It does not block.
+func (w *Watcher) Resync() {
+	select {
+	case w.resync <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers debounced changes, plus one every DefaultWatchFallback, until
+// ctx is cancelled, then closes the watcher and returns nil. It returns an error if watching stops working, in
+// which case the caller should fall back to polling.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer func() { _ = w.fsw.Close() }()
+
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	var fire <-chan time.Time
+	schedule := func() {
+		timer.Reset(w.debounce)
+		fire = timer.C
+	}
+	fallback := time.NewTicker(w.fallback)
+	defer fallback.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				// fsnotify drops the watch of a removed directory; forget it
+				// so sync re-adds it if the path comes back
+				delete(w.watched, event.Name)
+			}
+			if w.relevant(event) {
+				schedule()
+			}
+
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return errors.New("file watcher closed")
+			}
+			// Event queue overflow and similar errors may have dropped
+			// events; rescan to be safe
+			schedule()
+
+		case <-w.resync:
+			schedule()
+
+		case <-fallback.C:
+			schedule()
+
+		case <-fire:
+			fire = nil
+			if err := w.sync(); err != nil {
+				return err
+			}
+			w.onChange()
+		}
+	}
+}
+
+// relevant reports whether an event can add or remove a project or worktree:
+// an entry created, removed or renamed in a watched directory, limited to
+// the entry that matters there (see watchDirs).
+func (w *Watcher) relevant(event fsnotify.Event) bool {
+	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+		return false
+	}
+	only := w.watched[filepath.Dir(event.Name)]
+	return only == "" || filepath.Base(event.Name) == only
+}
+
+// sync updates the watch list to the directories the current scan walks.
+func (w *Watcher) sync() error {
+	want := w.watchDirs()
+	for dir, only := range want {
+		if _, ok := w.watched[dir]; !ok {
+			if err := w.fsw.Add(dir); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", dir, err)
+			}
+		}
+		w.watched[dir] = only
+	}
+	for dir := range w.watched {
+		if _, ok := want[dir]; !ok {
+			_ = w.fsw.Remove(dir)
+			delete(w.watched, dir)
+		}
+	}
+	return nil
+}
+
+// watchDirs returns the directories to watch, each with the only entry name
+// whose events matter there ("" for any): those walked by a scan (any entry);
+// each candidate that is not walked (.devcontainer) and the .devcontainer of
+// every candidate (docker-compose.yml); and each project's .git (worktrees)
+// and .git/worktrees (any entry), where they exist.
+func (w *Watcher) watchDirs() map[string]string {
+	walk := w.scanner.scanCached(w.paths())
+	dirs := make(map[string]string, len(walk.dirs)+len(walk.candidates))
+	addDir := func(dir, only string) {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs[dir] = only
+		}
+	}
+	for _, dir := range walk.candidates {
+		addDir(dir, ".devcontainer")
+		addDir(filepath.Join(dir, ".devcontainer"), "docker-compose.yml")
+	}
+	for _, dir := range walk.dirs {
+		dirs[dir] = ""
+	}
+	for _, p := range walk.projects {
+		addDir(filepath.Join(p.Path, ".git"), "worktrees")
+		addDir(filepath.Join(p.Path, ".git", "worktrees"), "")
+	}
+	return dirs
+}
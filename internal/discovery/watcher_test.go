@@ -0,0 +1,138 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatcher_NewDirectoryTriggersDebouncedCallback(t *testing.T) {
+	root := t.TempDir()
+	var calls atomic.Int32
+	changed := make(chan struct{}, 10)
+	w, err := NewWatcher(func() []string { return []string{root} }, 1, 100*time.Millisecond, func() {
+		calls.Add(1)
+		changed <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+
+	// A burst of new directories produces a single callback
+	for _, name := range []string{"alpha", "bravo", "charlie"} {
+		writeManagedProject(t, filepath.Join(root, name))
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no callback after creating directories")
+	}
+	time.Sleep(300 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("callbacks = %d, want 1 for a burst of changes", n)
+	}
+
+	// Removing a project is reported too
+	if err := os.RemoveAll(filepath.Join(root, "bravo")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no callback after removing a project")
+	}
+}
+
+func TestWatcher_MissingRootIsNotAnError(t *testing.T) {
+	w, err := NewWatcher(func() []string { return []string{"/nonexistent/path"} }, 1, DefaultWatchDebounce, func() {})
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Run(ctx); err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestWatcher_ExistingDirectoryBecomingAProjectTriggersCallback(t *testing.T) {
+	root := t.TempDir()
+	// A directory at the deepest scanned level whose .devcontainer exists
+	// before its compose file is written
+	devcontainerDir := filepath.Join(root, "alpha", ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan struct{}, 10)
+	w, err := NewWatcher(func() []string { return []string{root} }, 1, 100*time.Millisecond, func() {
+		changed <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+	runWatcher(t, w)
+
+	// Noise in the candidate directory is ignored
+	if err := os.WriteFile(filepath.Join(root, "alpha", "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+		t.Fatal("callback for an unrelated file")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	writeManagedProject(t, filepath.Join(root, "alpha"))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no callback after writing the compose file")
+	}
+}
+
+func TestWatcher_FallbackReportsWithoutEvents(t *testing.T) {
+	root := t.TempDir()
+	changed := make(chan struct{}, 10)
+	w, err := NewWatcher(func() []string { return []string{root} }, 1, 10*time.Millisecond, func() {
+		changed <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+	w.fallback = 50 * time.Millisecond
+	runWatcher(t, w)
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no fallback callback")
+	}
+}
+
+// runWatcher runs w until the test ends.
+func runWatcher(t *testing.T, w *Watcher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+}
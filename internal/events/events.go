@@ -11,3 +11,7 @@ type WebListenURLMsg struct{ URL string }
 
 // TailscaleURLMsg is sent when the tailscale FQDN becomes available.
 type TailscaleURLMsg struct{ URL string }
+
+// TailscaleStatusMsg is sent when the tsnsrv process state changes
+// ("running", "restarting", "failed" or "stopped").
+type TailscaleStatusMsg struct{ Status string }
@@ -3,27 +3,63 @@ package instance
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+
+	"devagent/internal/audit"
 )
 
+// UnixPrefix marks an address as a Unix socket path: the port file holds
+// "unix:<path>" instead of host:port when the web server listens on a socket,
+// and Discover returns it unchanged as the base URL.
+const UnixPrefix = "unix:"
+
 // Client is a thin HTTP client for communicating with a running devagent instance.
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
-// NewClient creates a Client targeting the given base URL.
+// NewClient creates a Client targeting the given base URL, either
+// "http://host:port" or "unix:<socket path>".
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+	return NewClientWithTimeout(baseURL, 10*time.Second)
+}
+
+// newHTTPClient returns an HTTP client for baseURL and the URL prefix to send
+// requests to. For a "unix:<path>" base URL every connection dials the socket
+// and requests use a placeholder host.
+func newHTTPClient(baseURL string, timeout time.Duration) (*http.Client, string) {
+	socket, ok := strings.CutPrefix(baseURL, UnixPrefix)
+	if !ok {
+		return &http.Client{Timeout: timeout, Transport: sourceTransport{http.DefaultTransport}}, baseURL
 	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}
+	return &http.Client{Timeout: timeout, Transport: sourceTransport{transport}}, "http://devagent"
+}
+
+// sourceTransport marks every request as coming from the CLI, so the
+// instance's audit log attributes the actions to it.
+type sourceTransport struct {
+	base http.RoundTripper
+}
+
+func (t sourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(audit.SourceHeader, audit.SourceCLI)
+	return t.base.RoundTrip(req)
 }
 
 // List fetches the project list from the running instance.
@@ -33,6 +69,11 @@ func (c *Client) List() ([]byte, error) {
 	return c.get("/api/projects")
 }
 
+// ListAll returns the same JSON as List, including unmanaged containers.
+func (c *Client) ListAll() ([]byte, error) {
+	return c.get("/api/projects?all=true")
+}
+
 // get performs a GET request and returns the response body.
 func (c *Client) get(path string) ([]byte, error) {
 	resp, err := c.httpClient.Get(c.baseURL + path)
@@ -150,6 +191,11 @@ func extractErrorMessage(body []byte) string {
 	return string(body)
 }
 
+// GetContainer returns a single container with its sessions.
+func (c *Client) GetContainer(id string) ([]byte, error) {
+	return c.get("/api/containers/" + id)
+}
+
 // StartContainer starts a stopped container.
 func (c *Client) StartContainer(id string) ([]byte, error) {
 	return c.post("/api/containers/" + id + "/start")
@@ -165,6 +211,21 @@ func (c *Client) DestroyContainer(id string) ([]byte, error) {
 	return c.delete("/api/containers/" + id)
 }
 
+// Prune destroys all stopped devagent-managed containers. Without confirm,
+// the instance refuses a prune of more containers than its destroy threshold.
+func (c *Client) Prune(confirm bool) ([]byte, error) {
+	if confirm {
+		return c.post("/api/prune?confirm=true")
+	}
+	return c.post("/api/prune")
+}
+
+// Restart asks the instance to restart itself (re-exec with the same
+// config dir). It returns once the restart is scheduled.
+func (c *Client) Restart() ([]byte, error) {
+	return c.post("/api/restart")
+}
+
 // CreateSession creates a tmux session in the named container.
 func (c *Client) CreateSession(containerID, sessionName string) ([]byte, error) {
 	return c.postJSON("/api/containers/"+containerID+"/sessions", map[string]string{"name": sessionName})
@@ -222,8 +283,9 @@ func (c *Client) SendToSession(containerID, session, text string) error {
 // NewClientWithTimeout creates a Client with a custom timeout.
 // Used for long-running operations like worktree creation with devcontainer builds.
 func NewClientWithTimeout(baseURL string, timeout time.Duration) *Client {
+	httpClient, base := newHTTPClient(baseURL, timeout)
 	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    base,
+		httpClient: httpClient,
 	}
 }
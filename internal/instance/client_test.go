@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"devagent/internal/audit"
 )
 
 func TestClient_List(t *testing.T) {
@@ -110,6 +112,26 @@ func TestClient_Delete_Success(t *testing.T) {
 	}
 }
 
+func TestClient_MarksRequestsAsCLI(t *testing.T) {
+	var sources []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sources = append(sources, r.Header.Get(audit.SourceHeader))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL)
+	if _, err := client.get("/api/test"); err != nil {
+		t.Fatalf("get() error: %v", err)
+	}
+	if _, err := client.delete("/api/test"); err != nil {
+		t.Fatalf("delete() error: %v", err)
+	}
+	if len(sources) != 2 || sources[0] != audit.SourceCLI || sources[1] != audit.SourceCLI {
+		t.Errorf("%s headers = %q, want %q on every request", audit.SourceHeader, sources, audit.SourceCLI)
+	}
+}
+
 func TestClient_PostJSON_SendsBody(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/api/test" && r.Method == "POST" {
@@ -15,7 +15,8 @@ import (
 const healthTimeout = 2 * time.Second
 
 // Discover checks whether a running devagent instance exists and returns
-// its base URL (e.g. "http://127.0.0.1:12345"). Returns an error if no
+// its base URL (e.g. "http://127.0.0.1:12345", or "unix:/path/web.sock" for
+// an instance listening on a Unix socket; NewClient accepts both). Returns an error if no
 // instance is running, the port file is missing, or the health check fails.
 func Discover(dataDir string) (string, error) {
 	// Try to acquire the lock — if we succeed, no instance is running.
@@ -32,6 +33,27 @@ func Discover(dataDir string) (string, error) {
 	}
 
 	// Lock is held — read the port file.
+	baseURL, err := readPortFile(dataDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Health check to verify the instance is responsive.
+	client, requestBase := newHTTPClient(baseURL, healthTimeout)
+	resp, err := client.Get(requestBase + "/api/health")
+	if err != nil {
+		return "", fmt.Errorf("devagent instance not responding; its lock may be stale (restart devagent with --force): %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("devagent health check failed (status %d)", resp.StatusCode)
+	}
+
+	return baseURL, nil
+}
+
+// readPortFile returns the base URL recorded in dataDir's port file.
+func readPortFile(dataDir string) (string, error) {
 	portPath := filepath.Join(dataDir, portFileName)
 	data, err := os.ReadFile(portPath)
 	if err != nil {
@@ -43,18 +65,26 @@ func Discover(dataDir string) (string, error) {
 		return "", fmt.Errorf("devagent port file is empty (try 'devagent cleanup')")
 	}
 
-	baseURL := fmt.Sprintf("http://%s", addr)
+	if strings.HasPrefix(addr, UnixPrefix) {
+		return addr, nil
+	}
+	return fmt.Sprintf("http://%s", addr), nil
+}
 
-	// Health check to verify the instance is responsive.
-	client := &http.Client{Timeout: healthTimeout}
-	resp, err := client.Get(baseURL + "/api/health")
+// probeRecorded reports whether an instance answers GET /healthz at the
+// address in dataDir's port file, returning that base URL. ok is false when
+// there is no usable port file (e.g. the instance runs without the web
+// server), so nothing could be probed.
+func probeRecorded(dataDir string) (baseURL string, alive, ok bool) {
+	baseURL, err := readPortFile(dataDir)
 	if err != nil {
-		return "", fmt.Errorf("devagent instance not responding (try 'devagent cleanup'): %w", err)
+		return "", false, false
 	}
-	resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("devagent health check failed (status %d)", resp.StatusCode)
+	client, requestBase := newHTTPClient(baseURL, healthTimeout)
+	resp, err := client.Get(requestBase + "/healthz")
+	if err != nil {
+		return baseURL, false, true
 	}
-
-	return baseURL, nil
+	resp.Body.Close()
+	return baseURL, resp.StatusCode == http.StatusOK, true
 }
@@ -1,8 +1,11 @@
 package instance
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -52,6 +55,60 @@ func TestDiscover_WithInstance(t *testing.T) {
 	}
 }
 
+func TestDiscover_UnixSocket(t *testing.T) {
+	dir := t.TempDir()
+	fl, err := Lock(dir)
+	if err != nil {
+		t.Fatalf("Lock() failed: %v", err)
+	}
+	defer Cleanup(dir, fl)
+
+	// Socket paths are length-limited, so keep this one short
+	sockDir, err := os.MkdirTemp("", "da")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(sockDir)
+	sock := filepath.Join(sockDir, "web.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen on socket: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/api/projects":
+			w.Write([]byte(`{"projects":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	if err := WritePort(dir, UnixPrefix+sock); err != nil {
+		t.Fatalf("WritePort() failed: %v", err)
+	}
+
+	baseURL, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover() failed: %v", err)
+	}
+	if baseURL != UnixPrefix+sock {
+		t.Fatalf("Discover() = %q, want %q", baseURL, UnixPrefix+sock)
+	}
+
+	data, err := NewClient(baseURL).List()
+	if err != nil {
+		t.Fatalf("List() over socket error = %v", err)
+	}
+	if string(data) != `{"projects":[]}` {
+		t.Errorf("List() = %s", data)
+	}
+}
+
 func TestDiscover_StalePortFile(t *testing.T) {
 	dir := t.TempDir()
 
@@ -2,6 +2,7 @@
 package instance
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,9 +15,16 @@ const (
 	portFileName = "devagent.port"
 )
 
+// ErrStaleLock is returned (wrapped) by Lock when another process holds the
+// lock but no instance answers on the recorded port, e.g. a hung prior run.
+var ErrStaleLock = errors.New("devagent lock is held but the instance is not responding")
+
 // Lock acquires an exclusive file lock for single-instance enforcement.
 // Returns the flock handle (caller must defer Cleanup) or an error if
-// another instance already holds the lock.
+// another instance already holds the lock. When the holder recorded a port
+// but does not answer GET /healthz there, the error wraps ErrStaleLock
+// (ForceLock takes over such a lock); without a port file the holder cannot
+// be probed and is assumed to be running.
 func Lock(dataDir string) (*flock.Flock, error) {
 	lockPath := filepath.Join(dataDir, lockFileName)
 	fl := flock.New(lockPath)
@@ -25,22 +33,75 @@ func Lock(dataDir string) (*flock.Flock, error) {
 		return nil, fmt.Errorf("failed to acquire lock: %w", err)
 	}
 	if !locked {
-		return nil, fmt.Errorf("another devagent instance is already running")
+		baseURL, alive, probed := probeRecorded(dataDir)
+		switch {
+		case !probed:
+			return nil, fmt.Errorf("another devagent instance is already running")
+		case !alive:
+			return nil, fmt.Errorf("%w at %s (start with --force to take over, or stop the old process)", ErrStaleLock, baseURL)
+		}
+		return nil, fmt.Errorf("another devagent instance is already running at %s", baseURL)
 	}
 	return fl, nil
 }
 
-// WritePort writes the web server's listener address to the port file.
+// ForceLock is Lock, except that a stale lock (ErrStaleLock) is taken over:
+// the lock and port files are removed and a fresh lock file is locked. The
+// unresponsive holder keeps its lock on the removed file only, so it no
+// longer excludes anyone. A responsive instance is never displaced.
+func ForceLock(dataDir string) (*flock.Flock, error) {
+	fl, err := Lock(dataDir)
+	if !errors.Is(err, ErrStaleLock) {
+		return fl, err
+	}
+	for _, path := range append(StaleFiles(dataDir), filepath.Join(dataDir, lockFileName)) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale %s: %w", filepath.Base(path), err)
+		}
+	}
+	return Lock(dataDir)
+}
+
+// WritePort writes the web server's listener address to the port file:
+// host:port, or UnixPrefix + path for a Unix socket.
 func WritePort(dataDir, addr string) error {
 	portPath := filepath.Join(dataDir, portFileName)
 	return os.WriteFile(portPath, []byte(addr), 0600)
 }
 
-// Cleanup removes the port file and releases the file lock.
-func Cleanup(dataDir string, fl *flock.Flock) {
+// StaleFiles returns the files in dataDir that Cleanup would remove.
+// Missing files are omitted, so an empty result means there is nothing to
+// remove. The lock file itself is never removed (only released): deleting a
+// file another process may be about to flock would break mutual exclusion.
+func StaleFiles(dataDir string) []string {
+	var files []string
 	portPath := filepath.Join(dataDir, portFileName)
-	_ = os.Remove(portPath)
+	if _, err := os.Stat(portPath); err == nil {
+		files = append(files, portPath)
+	}
+	return files
+}
+
+// Release releases the file lock without removing any files.
+func Release(fl *flock.Flock) {
 	if fl != nil {
 		_ = fl.Unlock()
 	}
 }
+
+// RemoveStale removes the files StaleFiles reports and returns them. Call it
+// only while holding the lock: a port file left then is from a run that
+// crashed, and CLI commands would otherwise be pointed at a dead address.
+func RemoveStale(dataDir string) []string {
+	files := StaleFiles(dataDir)
+	for _, path := range files {
+		_ = os.Remove(path)
+	}
+	return files
+}
+
+// Cleanup removes the port file and releases the file lock.
+func Cleanup(dataDir string, fl *flock.Flock) {
+	RemoveStale(dataDir)
+	Release(fl)
+}
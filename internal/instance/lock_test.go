@@ -1,9 +1,16 @@
 package instance
 
 import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/gofrs/flock"
 )
 
 func TestLockAndCleanup(t *testing.T) {
@@ -54,3 +61,104 @@ func TestLockAndCleanup(t *testing.T) {
 	}
 	Cleanup(dir, fl2)
 }
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	if files := StaleFiles(dir); len(files) != 0 {
+		t.Fatalf("StaleFiles() on empty dir = %v, want none", files)
+	}
+
+	if err := WritePort(dir, "127.0.0.1:8080"); err != nil {
+		t.Fatalf("WritePort() failed: %v", err)
+	}
+
+	files := StaleFiles(dir)
+	if len(files) != 1 || files[0] != filepath.Join(dir, portFileName) {
+		t.Fatalf("StaleFiles() = %v, want [%s]", files, filepath.Join(dir, portFileName))
+	}
+
+	// Detection must not remove anything
+	if _, err := os.Stat(files[0]); err != nil {
+		t.Errorf("StaleFiles() should not remove files: %v", err)
+	}
+}
+
+// holdLock locks dir's lock file through a separate handle, standing in for
+// another process.
+func holdLock(t *testing.T, dir string) {
+	t.Helper()
+	other := flock.New(filepath.Join(dir, lockFileName))
+	if ok, err := other.TryLock(); !ok || err != nil {
+		t.Fatalf("TryLock() = %v, %v", ok, err)
+	}
+	t.Cleanup(func() { _ = other.Unlock() })
+}
+
+func TestLock_StalePortIsTakenOverWithForce(t *testing.T) {
+	dir := t.TempDir()
+	holdLock(t, dir)
+
+	// Record a port nothing listens on.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	if err := WritePort(dir, addr); err != nil {
+		t.Fatalf("WritePort() failed: %v", err)
+	}
+
+	if _, err := Lock(dir); !errors.Is(err, ErrStaleLock) {
+		t.Fatalf("Lock() error = %v, want ErrStaleLock", err)
+	}
+
+	fl, err := ForceLock(dir)
+	if err != nil {
+		t.Fatalf("ForceLock() failed: %v", err)
+	}
+	defer Cleanup(dir, fl)
+	if _, err := os.Stat(filepath.Join(dir, portFileName)); !os.IsNotExist(err) {
+		t.Errorf("stale port file should have been removed (stat error = %v)", err)
+	}
+	if _, err := Lock(dir); err == nil {
+		t.Error("Lock() after ForceLock should fail")
+	}
+}
+
+func TestLock_LiveInstanceIsNotTakenOver(t *testing.T) {
+	dir := t.TempDir()
+	holdLock(t, dir)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+	if err := WritePort(dir, srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("WritePort() failed: %v", err)
+	}
+
+	for name, lock := range map[string]func(string) (*flock.Flock, error){"Lock": Lock, "ForceLock": ForceLock} {
+		_, err := lock(dir)
+		if err == nil || errors.Is(err, ErrStaleLock) || !strings.Contains(err.Error(), "already running") {
+			t.Errorf("%s() error = %v, want already running", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, portFileName)); err != nil {
+		t.Errorf("live instance's port file removed: %v", err)
+	}
+}
+
+func TestLock_WithoutPortFileAssumesRunning(t *testing.T) {
+	dir := t.TempDir()
+	holdLock(t, dir)
+
+	if _, err := ForceLock(dir); err == nil || errors.Is(err, ErrStaleLock) {
+		t.Errorf("ForceLock() error = %v, want an instance without a web server left alone", err)
+	}
+}
@@ -23,9 +23,16 @@ type Config struct {
 	MaxBackups     int    // Max number of old log files to keep
 	MaxAgeDays     int    // Max days to keep old log files
 	Level          string // Minimum log level (debug, info, warn, error)
+	Format         string // Log file format: "json" (default) or "text"
 	ChannelBufSize int    // Buffer size for TUI channel (default 1000)
 }
 
+// Log file formats accepted by Config.Format.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
 // LoggerProvider is an interface for obtaining scoped loggers.
 // Both Manager and TestLogManager implement this interface.
 type LoggerProvider interface {
@@ -92,7 +99,7 @@ type Manager struct {
 	fileWriter  *lumberjack.Logger
 	loggers     map[string]*ScopedLogger
 	mu          sync.RWMutex
-	level       zapcore.Level
+	level       zap.AtomicLevel
 }
 
 // NewManager creates a new log manager with the given configuration.
@@ -115,13 +122,14 @@ func NewManager(cfg Config) (*Manager, error) {
 	if cfg.MaxAgeDays == 0 {
 		cfg.MaxAgeDays = 7
 	}
-
-	// Parse level
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		level = zapcore.InfoLevel
+	fileEncoder, err := newFileEncoder(cfg.Format)
+	if err != nil {
+		return nil, err
 	}
 
+	// Parse level (atomic so it can be changed on config reload)
+	level := zap.NewAtomicLevelAt(parseZapLevel(cfg.Level))
+
 	// Ensure log directory exists
 	if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0755); err != nil {
 		return nil, err
@@ -139,15 +147,15 @@ func NewManager(cfg Config) (*Manager, error) {
 	// Create channel sink for TUI
 	channelSink := NewChannelSink(cfg.ChannelBufSize)
 
-	// Encoder configuration
+	// Channel encoder configuration (the sink parses the "logger" key as scope)
 	encoderCfg := zap.NewProductionEncoderConfig()
 	encoderCfg.TimeKey = "ts"
 	encoderCfg.EncodeTime = zapcore.EpochTimeEncoder
 	encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
 
-	// File core (JSON)
+	// File core (JSON lines or human-readable text)
 	fileCore := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderCfg),
+		fileEncoder,
 		zapcore.AddSync(fileWriter),
 		level,
 	)
@@ -174,6 +182,27 @@ func NewManager(cfg Config) (*Manager, error) {
 	}, nil
 }
 
+// newFileEncoder returns the encoder for the log file. JSON lines carry
+// {ts, level, scope, msg, ...attrs}; text is zap's tab-separated console format.
+func newFileEncoder(format string) (zapcore.Encoder, error) {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.TimeKey = "ts"
+	encoderCfg.NameKey = "scope"
+	encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+
+	switch format {
+	case "", FormatJSON:
+		encoderCfg.EncodeTime = zapcore.EpochTimeEncoder
+		return zapcore.NewJSONEncoder(encoderCfg), nil
+	case FormatText:
+		encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderCfg.EncodeDuration = zapcore.StringDurationEncoder
+		return zapcore.NewConsoleEncoder(encoderCfg), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %q or %q)", format, FormatJSON, FormatText)
+	}
+}
+
 // For returns a logger for the given scope.
 // Scopes are hierarchical (e.g., "container.abc123", "session.abc.mysession").
 // Loggers are cached and reused for the same scope.
@@ -212,11 +241,37 @@ func (m *Manager) For(scope string) *ScopedLogger {
 	return logger
 }
 
+// SetLevel changes the minimum log level for all loggers, including ones
+// already handed out by For. Unknown levels fall back to info.
+func (m *Manager) SetLevel(level string) {
+	m.level.SetLevel(parseZapLevel(level))
+}
+
+// Level returns the current minimum log level name (e.g. "info").
+func (m *Manager) Level() string {
+	return m.level.Level().String()
+}
+
+// parseZapLevel parses a level name, defaulting to info for unknown values.
+func parseZapLevel(level string) zapcore.Level {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return zapcore.InfoLevel
+	}
+	return l
+}
+
 // Entries returns the channel for consuming log entries.
 func (m *Manager) Entries() <-chan LogEntry {
 	return m.channelSink.Entries()
 }
 
+// Dropped returns how many entries the TUI channel has dropped because its
+// consumer fell behind.
+func (m *Manager) Dropped() uint64 {
+	return m.channelSink.Dropped()
+}
+
 // GetChannelSink returns the channel sink for external log sources.
 // This allows proxy log readers to send entries directly to the TUI channel.
 func (m *Manager) GetChannelSink() *ChannelSink {
@@ -251,13 +306,13 @@ func (m *Manager) Close() error {
 // zapSlogHandler adapts zap.Logger to slog.Handler interface.
 type zapSlogHandler struct {
 	zap    *zap.Logger
-	level  zapcore.Level
+	level  zapcore.LevelEnabler
 	attrs  []slog.Attr
 	groups []string
 }
 
 func (h *zapSlogHandler) Enabled(_ context.Context, level slog.Level) bool {
-	return h.slogToZapLevel(level) >= h.level
+	return h.level.Enabled(h.slogToZapLevel(level))
 }
 
 func (h *zapSlogHandler) Handle(_ context.Context, r slog.Record) error {
@@ -265,12 +320,12 @@ func (h *zapSlogHandler) Handle(_ context.Context, r slog.Record) error {
 
 	// Add handler attrs
 	for _, attr := range h.attrs {
-		fields = append(fields, zap.Any(attr.Key, attr.Value.Any()))
+		fields = appendZapField(fields, attr)
 	}
 
 	// Add record attrs
 	r.Attrs(func(attr slog.Attr) bool {
-		fields = append(fields, zap.Any(attr.Key, attr.Value.Any()))
+		fields = appendZapField(fields, attr)
 		return true
 	})
 
@@ -314,6 +369,47 @@ func (h *zapSlogHandler) WithGroup(name string) slog.Handler {
 	}
 }
 
+// appendZapField converts a slog attribute to a zap field so it keeps its type
+// in JSON output: LogValuers are resolved, groups become nested objects, and
+// empty attributes are dropped (as slog handlers do).
+func appendZapField(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		var group []zap.Field
+		for _, a := range attr.Value.Group() {
+			group = appendZapField(group, a)
+		}
+		if len(group) == 0 {
+			return fields
+		}
+		if attr.Key == "" {
+			return append(fields, group...) // inline, like slog's empty-key groups
+		}
+		return append(fields, zap.Dict(attr.Key, group...))
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, attr.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, attr.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, attr.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, attr.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, attr.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, attr.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, attr.Value.Time()))
+	default:
+		return append(fields, zap.Any(attr.Key, attr.Value.Any()))
+	}
+}
+
 func (h *zapSlogHandler) slogToZapLevel(level slog.Level) zapcore.Level {
 	switch {
 	case level >= slog.LevelError:
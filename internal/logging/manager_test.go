@@ -3,10 +3,15 @@
 package logging
 
 import (
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewManager(t *testing.T) {
@@ -113,6 +118,56 @@ func TestManager_LoggingToChannel(t *testing.T) {
 	}
 }
 
+func TestManager_SetLevel(t *testing.T) {
+	tmpDir := t.TempDir()
+	logFile := filepath.Join(tmpDir, "test.log")
+
+	cfg := Config{
+		FilePath:       logFile,
+		Level:          "info",
+		ChannelBufSize: 100,
+	}
+
+	mgr, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	defer func() { _ = mgr.Close() }()
+
+	// Logger obtained before the level change must observe it
+	logger := mgr.For("test.level")
+	logger.Debug("hidden debug")
+	_ = mgr.Sync()
+
+	select {
+	case entry := <-mgr.Entries():
+		t.Fatalf("unexpected entry at info level: %q", entry.Message)
+	default:
+	}
+
+	mgr.SetLevel("debug")
+	if mgr.Level() != "debug" {
+		t.Errorf("Level() = %q, want %q", mgr.Level(), "debug")
+	}
+
+	logger.Debug("visible debug")
+	_ = mgr.Sync()
+
+	select {
+	case entry := <-mgr.Entries():
+		if entry.Message != "visible debug" {
+			t.Errorf("Message = %q, want %q", entry.Message, "visible debug")
+		}
+	default:
+		t.Fatal("debug entry not received after SetLevel(debug)")
+	}
+
+	mgr.SetLevel("bogus")
+	if mgr.Level() != "info" {
+		t.Errorf("Level() after invalid level = %q, want %q", mgr.Level(), "info")
+	}
+}
+
 func TestManager_LoggingToFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	logFile := filepath.Join(tmpDir, "test.log")
@@ -152,6 +207,101 @@ func TestManager_LoggingToFile(t *testing.T) {
 	}
 }
 
+func TestManager_JSONFormat_AttrsRoundTrip(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "test.log")
+
+	mgr, err := NewManager(Config{FilePath: logFile, Level: "debug", Format: FormatJSON, ChannelBufSize: 10})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	logger := mgr.For("container.abc").With("containerID", "abc")
+	logger.Info("started",
+		"attempt", 3,
+		"ok", true,
+		"elapsed", 1500*time.Millisecond,
+		"err", errors.New("boom"),
+		slog.Group("ports", "web", 8080),
+	)
+	logger.Debug("second")
+	_ = mgr.Close()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), data)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %v\n%s", err, lines[0])
+	}
+	if _, ok := entry["ts"].(float64); !ok {
+		t.Errorf("ts = %v, want a number", entry["ts"])
+	}
+	want := map[string]any{
+		"level":       "info",
+		"scope":       "container.abc",
+		"msg":         "started",
+		"containerID": "abc",
+		"attempt":     float64(3),
+		"ok":          true,
+		"elapsed":     1.5,
+		"err":         "boom",
+		"ports":       map[string]any{"web": float64(8080)},
+	}
+	for key, w := range want {
+		if got := entry[key]; !reflect.DeepEqual(got, w) {
+			t.Errorf("%s = %#v, want %#v", key, got, w)
+		}
+	}
+
+	// The TUI channel still receives the entry with its scope and fields.
+	select {
+	case e := <-mgr.Entries():
+		if e.Scope != "container.abc" || e.Fields["containerID"] != "abc" {
+			t.Errorf("channel entry = %+v, want scope container.abc with containerID", e)
+		}
+	default:
+		t.Fatal("entry not received on channel")
+	}
+}
+
+func TestManager_TextFormat(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "test.log")
+
+	mgr, err := NewManager(Config{FilePath: logFile, Level: "info", Format: FormatText})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	mgr.For("app").Info("hello", "key", "value")
+	_ = mgr.Close()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	line := string(data)
+	if json.Valid(data) {
+		t.Errorf("text format wrote JSON: %s", line)
+	}
+	for _, want := range []string{"info", "app", "hello", `{"key": "value"}`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("text line %q missing %q", line, want)
+		}
+	}
+}
+
+func TestNewManager_UnknownFormat(t *testing.T) {
+	_, err := NewManager(Config{FilePath: filepath.Join(t.TempDir(), "test.log"), Format: "xml"})
+	if err == nil {
+		t.Fatal("NewManager() with unknown format should fail")
+	}
+}
+
 func TestManager_Cleanup(t *testing.T) {
 	tmpDir := t.TempDir()
 	logFile := filepath.Join(tmpDir, "test.log")
@@ -6,16 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // ChannelSink implements zapcore.WriteSyncer and routes parsed log entries
 // to a channel for TUI consumption. Writes are non-blocking; if the channel
-// is full, the oldest entry is dropped.
+// is full, the oldest entry is dropped and counted (see Dropped), so a slow
+// consumer never stalls logging.
 type ChannelSink struct {
 	entries chan LogEntry
 	mu      sync.Mutex
 	closed  bool
+	dropped atomic.Uint64
 }
 
 // NewChannelSink creates a new channel sink with the specified buffer size.
@@ -42,21 +45,7 @@ func (s *ChannelSink) Write(p []byte) (int, error) {
 		return 0, fmt.Errorf("write to closed channel sink")
 	}
 
-	// Non-blocking send with overflow handling — protected by mutex
-	select {
-	case s.entries <- entry:
-	default:
-		// Channel full - drop oldest and retry
-		select {
-		case <-s.entries:
-		default:
-		}
-		select {
-		case s.entries <- entry:
-		default:
-		}
-	}
-
+	s.send(entry)
 	return len(p), nil
 }
 
@@ -92,21 +81,37 @@ func (s *ChannelSink) Send(entry LogEntry) {
 	if s.closed {
 		return
 	}
+	s.send(entry)
+}
 
-	// Non-blocking send with overflow handling
+// send delivers entry without blocking. When the channel is full the oldest
+// entry is dropped to make room; if the retry still fails (a concurrent
+// producer refilled the slot), entry itself is dropped. Either way the drop is
+// counted. Callers must hold s.mu with the sink open.
+func (s *ChannelSink) send(entry LogEntry) {
 	select {
 	case s.entries <- entry:
+		return
 	default:
-		// Channel full - drop oldest and retry
-		select {
-		case <-s.entries:
-		default:
-		}
-		select {
-		case s.entries <- entry:
-		default:
-		}
 	}
+
+	// Channel full - drop oldest and retry
+	select {
+	case <-s.entries:
+		s.dropped.Add(1)
+	default:
+	}
+	select {
+	case s.entries <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many entries have been dropped because the channel was
+// full.
+func (s *ChannelSink) Dropped() uint64 {
+	return s.dropped.Load()
 }
 
 // parseEntry converts JSON log data from Zap into a LogEntry.
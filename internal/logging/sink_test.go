@@ -86,6 +86,36 @@ done:
 	}
 }
 
+func TestChannelSink_DroppedCounter(t *testing.T) {
+	sink := NewChannelSink(2)
+	defer func() { _ = sink.Close() }()
+
+	data, _ := json.Marshal(map[string]any{"level": "info", "msg": "flood", "logger": "app"})
+
+	// Flood with no consumer: every send must return rather than block, and
+	// every entry beyond the buffer is counted as dropped.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_, _ = sink.Write(data)
+			sink.Send(LogEntry{Message: "external", Scope: "proxy.app"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("flooding the sink blocked")
+	}
+
+	if got := sink.Dropped(); got != 198 {
+		t.Errorf("Dropped() = %d, want 198 (200 sent, 2 buffered)", got)
+	}
+	if got := len(sink.Entries()); got != 2 {
+		t.Errorf("buffered entries = %d, want 2", got)
+	}
+}
+
 func TestChannelSink_Sync(t *testing.T) {
 	sink := NewChannelSink(10)
 	defer func() { _ = sink.Close() }()
@@ -31,27 +31,63 @@ type Config struct {
 	Args       []string
 	RestartOn  RestartPolicy
 	MaxRetries int
-	RetryDelay time.Duration
+	RetryDelay time.Duration // delay before the first restart; 1s if unset
+	// MaxRetryDelay caps the restart backoff: the delay doubles after each
+	// consecutive restart up to this value. 0 keeps RetryDelay constant.
+	MaxRetryDelay time.Duration
+	// StableAfter resets the retry count and backoff once a run has lasted
+	// this long, so occasional crashes far apart never exhaust MaxRetries.
+	// 0 never resets.
+	StableAfter time.Duration
+}
+
+// Status is the supervised process's state as reported by Supervisor.Status.
+type Status int
+
+const (
+	StatusStopped    Status = iota // not started, stopped, or exited without restart
+	StatusRunning                  // process is running
+	StatusRestarting               // process exited; waiting out the backoff before restarting
+	StatusFailed                   // gave up after MaxRetries restarts
+)
+
+// String returns the lowercase name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusRunning:
+		return "running"
+	case StatusRestarting:
+		return "restarting"
+	case StatusFailed:
+		return "failed"
+	default:
+		return "stopped"
+	}
 }
 
 // Supervisor manages the lifecycle of a child process.
 type Supervisor struct {
 	cfg    Config
 	logger *logging.ScopedLogger
-
-	mu      sync.Mutex
-	cmd     *exec.Cmd
-	running bool
-	stopped bool
-	done    chan struct{}
+	after  func(time.Duration) <-chan time.Time // backoff timer; replaced in tests
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	running  bool
+	stopped  bool
+	status   Status
+	statusCh chan Status
+	done     chan struct{}
 }
 
 // NewSupervisor creates a new child process supervisor.
 func NewSupervisor(cfg Config, logger *logging.ScopedLogger) *Supervisor {
 	return &Supervisor{
-		cfg:    cfg,
-		logger: logger,
-		done:   make(chan struct{}),
+		cfg:      cfg,
+		logger:   logger,
+		after:    time.After,
+		statusCh: make(chan Status, 1),
+		done:     make(chan struct{}),
 	}
 }
 
@@ -122,11 +158,66 @@ func (s *Supervisor) Done() <-chan struct{} {
 	return s.done
 }
 
+// Status returns the supervised process's current state.
+func (s *Supervisor) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Healthy reports whether the process is currently running.
+func (s *Supervisor) Healthy() bool {
+	return s.Status() == StatusRunning
+}
+
+// StatusChanges returns a channel that receives the status after each change.
+// It holds only the newest status, so a slow reader skips intermediate ones
+// but always sees the latest. Closed after the final status when the
+// supervisor exits.
+func (s *Supervisor) StatusChanges() <-chan Status {
+	return s.statusCh
+}
+
+// setStatus records st and publishes it on statusCh, replacing an unread
+// older status.
+func (s *Supervisor) setStatus(st Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == st {
+		return
+	}
+	s.status = st
+	select {
+	case <-s.statusCh:
+	default:
+	}
+	s.statusCh <- st
+}
+
+// backoffDelay returns the delay before restart attempt n (1-based): base
+// doubled per earlier attempt, capped at maxDelay (no growth when it is 0).
+func backoffDelay(base, maxDelay time.Duration, n int) time.Duration {
+	if base == 0 {
+		base = time.Second
+	}
+	if maxDelay <= 0 {
+		return base
+	}
+	delay := base
+	for i := 1; i < n && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
 func (s *Supervisor) run(ctx context.Context) {
 	defer close(s.done)
+	final := StatusStopped
 	defer func() {
+		s.setStatus(final)
 		s.mu.Lock()
 		s.running = false
+		close(s.statusCh)
 		s.mu.Unlock()
 	}()
 
@@ -139,7 +230,11 @@ func (s *Supervisor) run(ctx context.Context) {
 		}
 		s.mu.Unlock()
 
+		started := time.Now()
 		exitCode := s.runOnce(ctx)
+		if s.cfg.StableAfter > 0 && time.Since(started) >= s.cfg.StableAfter {
+			retries = 0
+		}
 
 		s.mu.Lock()
 		if s.stopped {
@@ -165,18 +260,16 @@ func (s *Supervisor) run(ctx context.Context) {
 		retries++
 		if s.cfg.MaxRetries > 0 && retries > s.cfg.MaxRetries {
 			s.logger.Error("max retries exceeded", "retries", retries-1, "process", s.cfg.Name)
+			final = StatusFailed
 			return
 		}
 
-		delay := s.cfg.RetryDelay
-		if delay == 0 {
-			delay = time.Second
-		}
-
+		delay := backoffDelay(s.cfg.RetryDelay, s.cfg.MaxRetryDelay, retries)
 		s.logger.Info("restarting process", "process", s.cfg.Name, "attempt", retries, "delay", delay)
+		s.setStatus(StatusRestarting)
 
 		select {
-		case <-time.After(delay):
+		case <-s.after(delay):
 		case <-ctx.Done():
 			return
 		}
@@ -207,6 +300,7 @@ func (s *Supervisor) runOnce(ctx context.Context) int {
 	s.mu.Lock()
 	s.cmd = cmd
 	s.mu.Unlock()
+	s.setStatus(StatusRunning)
 
 	// Capture stdout and stderr into logger
 	var wg sync.WaitGroup
@@ -2,6 +2,10 @@ package process
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -154,3 +158,96 @@ func TestSupervisor_ContextCancellation(t *testing.T) {
 		t.Error("Done() not closed after context cancellation")
 	}
 }
+
+func TestSupervisor_BackoffAndStatus(t *testing.T) {
+	runs := filepath.Join(t.TempDir(), "runs")
+	s := NewSupervisor(Config{
+		Name:          "crasher",
+		Binary:        "sh",
+		Args:          []string{"-c", "echo run >> " + runs + "; exit 1"},
+		RestartOn:     OnFailure,
+		MaxRetries:    3,
+		RetryDelay:    10 * time.Millisecond,
+		MaxRetryDelay: 30 * time.Millisecond,
+	}, testLogger(t))
+
+	var delays []time.Duration
+	s.after = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		return time.After(time.Millisecond)
+	}
+
+	var seen []Status
+	collected := make(chan struct{})
+	go func() {
+		for st := range s.StatusChanges() {
+			seen = append(seen, st)
+		}
+		close(collected)
+	}()
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not give up after max retries")
+	}
+	<-collected
+
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	if !slices.Equal(delays, want) {
+		t.Errorf("backoff delays = %v, want %v", delays, want)
+	}
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "run"); got != 4 {
+		t.Errorf("process ran %d times, want 4 (initial + 3 restarts)", got)
+	}
+	if s.Status() != StatusFailed || s.Healthy() {
+		t.Errorf("final status = %v (healthy %v), want failed", s.Status(), s.Healthy())
+	}
+	if !slices.Contains(seen, StatusRestarting) || seen[len(seen)-1] != StatusFailed {
+		t.Errorf("status changes = %v, want restarting ... failed", seen)
+	}
+}
+
+func TestSupervisor_HealthyWhileRunning(t *testing.T) {
+	s := NewSupervisor(Config{
+		Name:   "sleeper",
+		Binary: "sleep",
+		Args:   []string{"60"},
+	}, testLogger(t))
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if st := <-s.StatusChanges(); st != StatusRunning || !s.Healthy() {
+		t.Errorf("status = %v (healthy %v), want running", st, s.Healthy())
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if s.Status() != StatusStopped {
+		t.Errorf("status after Stop() = %v, want stopped", s.Status())
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	if got := backoffDelay(0, 0, 3); got != time.Second {
+		t.Errorf("default delay = %v, want 1s", got)
+	}
+	if got := backoffDelay(time.Second, 0, 5); got != time.Second {
+		t.Errorf("uncapped delay = %v, want constant 1s", got)
+	}
+	if got := backoffDelay(time.Second, time.Minute, 4); got != 8*time.Second {
+		t.Errorf("attempt 4 delay = %v, want 8s", got)
+	}
+	if got := backoffDelay(time.Second, time.Minute, 20); got != time.Minute {
+		t.Errorf("attempt 20 delay = %v, want capped 1m", got)
+	}
+}
@@ -61,6 +61,21 @@ func (c *Client) ListSessions(ctx context.Context, containerID string) ([]Sessio
 	return sessions, nil
 }
 
+// ListWindows returns the windows of a tmux session in the container.
+func (c *Client) ListWindows(ctx context.Context, containerID, session string) ([]Window, error) {
+	c.logger.Debug("listing tmux windows", "containerID", containerID, "session", session)
+
+	output, err := c.exec(ctx, containerID, []string{"tmux", "list-windows", "-t", session, "-F", ListWindowsFormat})
+	if err != nil {
+		c.logger.Error("failed to list windows", "containerID", containerID, "session", session, "error", err)
+		return nil, err
+	}
+
+	windows := ParseListWindows(output)
+	c.logger.Debug("windows listed", "containerID", containerID, "session", session, "count", len(windows))
+	return windows, nil
+}
+
 // CreateSession creates a new detached tmux session.
 func (c *Client) CreateSession(ctx context.Context, containerID, name string) error {
 	c.logger.Info("creating tmux session", "containerID", containerID, "session", name)
@@ -75,6 +90,28 @@ func (c *Client) CreateSession(ctx context.Context, containerID, name string) er
 	return nil
 }
 
+// CreateSessionIn creates a new detached tmux session starting in dir and running
+// command. An empty dir uses tmux's default; an empty command starts a shell.
+func (c *Client) CreateSessionIn(ctx context.Context, containerID, name, dir, command string) error {
+	c.logger.Info("creating tmux session", "containerID", containerID, "session", name, "dir", dir)
+
+	cmd := []string{"tmux", "-u", "new-session", "-d", "-s", name}
+	if dir != "" {
+		cmd = append(cmd, "-c", dir)
+	}
+	if command != "" {
+		cmd = append(cmd, command)
+	}
+
+	if _, err := c.exec(ctx, containerID, cmd); err != nil {
+		c.logger.Error("failed to create session", "containerID", containerID, "session", name, "error", err)
+		return err
+	}
+
+	c.logger.Info("session created", "containerID", containerID, "session", name)
+	return nil
+}
+
 // KillSession destroys a tmux session.
 func (c *Client) KillSession(ctx context.Context, containerID, name string) error {
 	c.logger.Info("killing tmux session", "containerID", containerID, "session", name)
@@ -204,17 +241,12 @@ func (c *Client) CursorPosition(ctx context.Context, containerID, session string
 
 // SendKeys sends keys to a tmux session, followed by Enter.
 func (c *Client) SendKeys(ctx context.Context, containerID, session, keys string) error {
-	c.logger.Debug("sending keys", "containerID", containerID, "session", session)
-
-	// Send the text first
-	_, err := c.exec(ctx, containerID, []string{"tmux", "send-keys", "-t", session, keys})
-	if err != nil {
-		c.logger.Error("failed to send keys", "containerID", containerID, "session", session, "error", err)
+	if err := c.TypeKeys(ctx, containerID, session, keys); err != nil {
 		return err
 	}
 	// Send Enter separately — TUI apps (e.g. Claude Code) need this gap
 	// to distinguish "submit" from "newline within input"
-	_, err = c.exec(ctx, containerID, []string{"tmux", "send-keys", "-t", session, "Enter"})
+	_, err := c.exec(ctx, containerID, []string{"tmux", "send-keys", "-t", session, "Enter"})
 	if err != nil {
 		c.logger.Error("failed to send Enter", "containerID", containerID, "session", session, "error", err)
 		return err
@@ -222,3 +254,18 @@ func (c *Client) SendKeys(ctx context.Context, containerID, session, keys string
 
 	return nil
 }
+
+// TypeKeys types keys into a tmux session without pressing Enter. keys is a
+// single argv element, so it never passes through a shell, and it is sent
+// literally (-l, after --): text such as "C-c", "Enter" or "-X" is typed as
+// those characters, not read as a key name or a flag.
+func (c *Client) TypeKeys(ctx context.Context, containerID, session, keys string) error {
+	c.logger.Debug("sending keys", "containerID", containerID, "session", session)
+
+	_, err := c.exec(ctx, containerID, []string{"tmux", "send-keys", "-l", "-t", session, "--", keys})
+	if err != nil {
+		c.logger.Error("failed to send keys", "containerID", containerID, "session", session, "error", err)
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package tmux
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -119,6 +120,53 @@ func TestClient_ListSessions_NoServer(t *testing.T) {
 	}
 }
 
+func TestClient_ListWindows(t *testing.T) {
+	mock := newMockExec()
+	mock.outputs["container1:tmux:list-windows"] = "0\tbash\t0\tbash\n1\teditor\t1\tvim\n"
+	client := NewClient(mock.exec)
+
+	windows, err := client.ListWindows(context.Background(), "container1", "dev")
+	if err != nil {
+		t.Fatalf("ListWindows() error = %v", err)
+	}
+	if len(windows) != 2 || windows[1].Name != "editor" || !windows[1].Active || windows[1].Command != "vim" {
+		t.Errorf("ListWindows() = %+v, want bash and active editor running vim", windows)
+	}
+
+	wantCmd := []string{"tmux", "list-windows", "-t", "dev", "-F", ListWindowsFormat}
+	if len(mock.calls) != 1 || !slices.Equal(mock.calls[0].cmd, wantCmd) {
+		t.Errorf("calls = %+v, want %v", mock.calls, wantCmd)
+	}
+}
+
+func TestClient_CreateSessionIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		command string
+		want    []string
+	}{
+		{"dir and command", "/workspace", "make watch", []string{"tmux", "-u", "new-session", "-d", "-s", "dev", "-c", "/workspace", "make watch"}},
+		{"defaults", "", "", []string{"tmux", "-u", "new-session", "-d", "-s", "dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockExec()
+			client := NewClient(mock.exec)
+
+			if err := client.CreateSessionIn(context.Background(), "container1", "dev", tt.dir, tt.command); err != nil {
+				t.Fatalf("CreateSessionIn() error = %v", err)
+			}
+			if len(mock.calls) != 1 {
+				t.Fatalf("Expected 1 call, got %d", len(mock.calls))
+			}
+			if !slices.Equal(mock.calls[0].cmd, tt.want) {
+				t.Errorf("cmd = %v, want %v", mock.calls[0].cmd, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_CreateSession(t *testing.T) {
 	mock := newMockExec()
 	client := NewClient(mock.exec)
@@ -434,7 +482,7 @@ func TestClient_SendKeys(t *testing.T) {
 
 	// First call: send the text
 	call := mock.calls[0]
-	expectedCmd := []string{"tmux", "send-keys", "-t", "dev", "echo hello"}
+	expectedCmd := []string{"tmux", "send-keys", "-l", "-t", "dev", "--", "echo hello"}
 	if len(call.cmd) != len(expectedCmd) {
 		t.Fatalf("call[0] cmd = %v, want %v", call.cmd, expectedCmd)
 	}
@@ -456,3 +504,19 @@ func TestClient_SendKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestClient_TypeKeys_SendsLiterally(t *testing.T) {
+	// Text that tmux would otherwise read as a key name or a flag
+	for _, keys := range []string{"C-c", "Enter", "-X cancel"} {
+		mock := newMockExec()
+		client := NewClient(mock.exec)
+
+		if err := client.TypeKeys(context.Background(), "container1", "dev", keys); err != nil {
+			t.Fatalf("TypeKeys(%q) error = %v", keys, err)
+		}
+		want := []string{"tmux", "send-keys", "-l", "-t", "dev", "--", keys}
+		if len(mock.calls) != 1 || !slices.Equal(mock.calls[0].cmd, want) {
+			t.Errorf("TypeKeys(%q) calls = %v, want [%q]", keys, mock.calls, want)
+		}
+	}
+}
@@ -58,3 +58,39 @@ func parseSessionLine(containerID, line string) Session {
 
 	return session
 }
+
+// ListWindowsFormat is the -F format ParseListWindows expects from
+// tmux list-windows: index, name, active flag and active pane command,
+// tab-separated so window names may contain spaces.
+const ListWindowsFormat = "#{window_index}\t#{window_name}\t#{window_active}\t#{pane_current_command}"
+
+// ParseListWindows parses tmux list-windows output in ListWindowsFormat into
+// a slice of Window objects. Empty lines and malformed lines are skipped.
+func ParseListWindows(output string) []Window {
+	var windows []Window
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			continue
+		}
+		windows = append(windows, Window{
+			Index:   index,
+			Name:    parts[1],
+			Active:  parts[2] == "1",
+			Command: parts[3],
+		})
+	}
+
+	return windows
+}
@@ -297,3 +297,29 @@ test_env: 5 windows (created Tue Feb 25 11:15:00 2026) (attached)`
 		}
 	}
 }
+
+func TestParseListWindows(t *testing.T) {
+	output := "0\tbash\t0\tbash\n1\tclaude code\t1\tnode\n\n2\tbroken\n" +
+		"x\tbad index\t0\tbash\n3\tlogs\t0\ttail\r\n"
+
+	got := ParseListWindows(output)
+	want := []Window{
+		{Index: 0, Name: "bash", Active: false, Command: "bash"},
+		{Index: 1, Name: "claude code", Active: true, Command: "node"},
+		{Index: 3, Name: "logs", Active: false, Command: "tail"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d windows (%+v), want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("window[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseListWindows_Empty(t *testing.T) {
+	if got := ParseListWindows(""); len(got) != 0 {
+		t.Errorf("ParseListWindows(\"\") = %+v, want none", got)
+	}
+}
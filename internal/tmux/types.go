@@ -14,6 +14,14 @@ type Session struct {
 	Attached    bool
 }
 
+// Window represents one window of a tmux session.
+type Window struct {
+	Index   int
+	Name    string
+	Active  bool   // the session's current window
+	Command string // command running in the window's active pane
+}
+
 // AttachCommand returns the command to attach to this session.
 // The user parameter specifies which user to exec as (typically "vscode").
 // Includes -e flags for TERM/COLORTERM since docker exec inherits TERM=dumb by default,
@@ -149,5 +149,9 @@ func BuildProcessConfigWith(tc config.TailscaleConfig, upstreamAddr string, reso
 		RestartOn:  process.OnFailure,
 		MaxRetries: 5,
 		RetryDelay: 3 * time.Second,
+		// Back off 3s, 6s, 12s... up to a minute; ten minutes of uptime resets
+		// the count
+		MaxRetryDelay: time.Minute,
+		StableAfter:   10 * time.Minute,
 	}, nil
 }
@@ -3,21 +3,38 @@
 package tui
 
 import (
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
+	"devagent/internal/config"
 	"devagent/internal/container"
 )
 
 // ActionCommand represents a command the user can copy/run for a container.
 type ActionCommand struct {
-	Label   string // Short description
-	Command string // The actual command to copy
+	Label   string   // Short description
+	Command string   // The actual command to copy
+	Args    []string // argv the action menu can run directly (o); nil when copy-only
+}
+
+// httpPorts are container ports commonly serving HTTP (dev servers, web apps).
+var httpPorts = []int{80, 3000, 3001, 4000, 4200, 5000, 5173, 8000, 8080, 8081, 8888, 9000}
+
+// BrowserURL returns http://localhost:<host port> for the first published TCP
+// port whose container port looks like HTTP (see httpPorts), or "" if none does.
+func BrowserURL(ports []container.PortMapping) string {
+	for _, p := range ports {
+		if p.Protocol == "tcp" && slices.Contains(httpPorts, p.ContainerPort) {
+			return fmt.Sprintf("http://localhost:%d", p.HostPort)
+		}
+	}
+	return ""
 }
 
 // GenerateContainerActions returns all available actions for a container.
-func GenerateContainerActions(c *container.Container, runtimePath string) []ActionCommand {
+// ports are its published ports; an HTTP-looking one adds "Open in browser".
+func GenerateContainerActions(c *container.Container, runtimePath string, ports []container.PortMapping) []ActionCommand {
 	if c == nil {
 		return nil
 	}
@@ -34,6 +51,7 @@ func GenerateContainerActions(c *container.Container, runtimePath string) []Acti
 		{
 			Label:   "Open in VS Code",
 			Command: GenerateVSCodeCommand(c.ID, workspaceFolder),
+			Args:    VSCodeArgs(c.ID, workspaceFolder),
 		},
 		{
 			Label:   "Create tmux session (named)",
@@ -48,20 +66,67 @@ func GenerateContainerActions(c *container.Container, runtimePath string) []Acti
 			Command: fmt.Sprintf("%s exec -it -u %s -w %s %s /bin/bash", runtimePath, user, workspaceFolder, c.Name),
 		},
 	}
+	if url := BrowserURL(ports); url != "" {
+		actions = append(actions, ActionCommand{Label: "Open in browser", Command: url})
+	}
 
 	return actions
 }
 
+// GenerateAttachCommand returns the command to attach to a tmux session in a container,
+// rendered from attachTemplate (config attach_command_template; empty or
+// unrenderable uses config.DefaultAttachCommandTemplate).
+// Uses the container name instead of the ID since docker ps returns truncated IDs.
+func GenerateAttachCommand(c *container.Container, sessionName, runtimePath, attachTemplate string) string {
+	user := c.RemoteUser
+	if user == "" {
+		user = container.DefaultRemoteUser
+	}
+	data := config.AttachCommandData{Runtime: runtimePath, User: user, Name: c.Name, Session: sessionName}
+	cmd, err := config.RenderAttachCommand(attachTemplate, data)
+	if err != nil {
+		cmd, _ = config.RenderAttachCommand("", data)
+	}
+	return cmd
+}
+
+// AttachArgs returns the argv (argv[0] included) the session view's `a` key
+// runs to attach to a tmux session in c: the default attach command, since a
+// custom attach_command_template is only for display and copying.
+func AttachArgs(c *container.Container, sessionName, runtimePath string) []string {
+	user := c.RemoteUser
+	if user == "" {
+		user = container.DefaultRemoteUser
+	}
+	return []string{runtimePath, "exec", "-it", "-u", user, c.Name, "tmux", "attach", "-t", sessionName}
+}
+
+// GenerateProjectAttachCommands returns attach commands for every session across
+// the given containers, in container then session order.
+func GenerateProjectAttachCommands(containers []*container.Container, runtimePath, attachTemplate string) []string {
+	var commands []string
+	for _, c := range containers {
+		for _, session := range c.Sessions {
+			commands = append(commands, GenerateAttachCommand(c, session.Name, runtimePath, attachTemplate))
+		}
+	}
+	return commands
+}
+
 // GenerateVSCodeURI builds the vscode-remote URI to attach to a running container.
 // containerID is the full 64-character Docker/Podman container ID.
 // workspacePath is the path inside the container (e.g. /workspaces).
 func GenerateVSCodeURI(containerID, workspacePath string) string {
-	payload, _ := json.Marshal(map[string]string{"containerName": containerID})
-	hexPayload := hex.EncodeToString(payload)
-	return fmt.Sprintf("vscode-remote://attached-container+%s%s", hexPayload, workspacePath)
+	return container.VSCodeURI(containerID, workspacePath)
 }
 
 // GenerateVSCodeCommand returns the full CLI command to open VS Code attached to a container.
 func GenerateVSCodeCommand(containerID, workspacePath string) string {
-	return fmt.Sprintf("code --folder-uri %s", GenerateVSCodeURI(containerID, workspacePath))
+	return strings.Join(VSCodeArgs(containerID, workspacePath), " ")
+}
+
+// VSCodeArgs returns the argv (argv[0] included) that opens VS Code attached
+// to a container.
+func VSCodeArgs(containerID, workspacePath string) []string {
+	return []string{"code", "--folder-uri", GenerateVSCodeURI(containerID, workspacePath)}
 }
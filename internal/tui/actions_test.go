@@ -3,14 +3,18 @@ package tui
 import (
 	"encoding/hex"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"devagent/internal/container"
+	"devagent/internal/tmux"
 )
 
 func TestGenerateContainerActions_NilContainer(t *testing.T) {
-	actions := GenerateContainerActions(nil, "/usr/bin/docker")
+	actions := GenerateContainerActions(nil, "/usr/bin/docker", nil)
 	if actions != nil {
 		t.Errorf("expected nil for nil container, got %v", actions)
 	}
@@ -23,7 +27,7 @@ func TestGenerateContainerActions_DefaultUser(t *testing.T) {
 		RemoteUser:  "", // empty should use default
 	}
 
-	actions := GenerateContainerActions(c, "/usr/bin/docker")
+	actions := GenerateContainerActions(c, "/usr/bin/docker", nil)
 
 	if len(actions) != 4 {
 		t.Errorf("expected 4 actions, got %d", len(actions))
@@ -44,7 +48,7 @@ func TestGenerateContainerActions_CustomUser(t *testing.T) {
 		RemoteUser:  "developer",
 	}
 
-	actions := GenerateContainerActions(c, "/usr/bin/docker")
+	actions := GenerateContainerActions(c, "/usr/bin/docker", nil)
 
 	// Check that custom user is used
 	for _, action := range actions {
@@ -62,7 +66,7 @@ func TestGenerateContainerActions_ContainsExpectedActions(t *testing.T) {
 		ID:          "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
 	}
 
-	actions := GenerateContainerActions(c, "/usr/bin/docker")
+	actions := GenerateContainerActions(c, "/usr/bin/docker", nil)
 
 	expectedLabels := []string{
 		"Open in VS Code",
@@ -114,6 +118,27 @@ func TestGenerateContainerActions_ContainsExpectedActions(t *testing.T) {
 	}
 }
 
+func TestGenerateContainerActions_OpenInBrowser(t *testing.T) {
+	c := &container.Container{Name: "mycontainer", ProjectPath: "/projects/myapp"}
+	ports := []container.PortMapping{
+		{ContainerPort: 5432, HostPort: 15432, Protocol: "tcp"},
+		{ContainerPort: 3000, HostPort: 13000, Protocol: "tcp"},
+	}
+
+	actions := GenerateContainerActions(c, "/usr/bin/docker", ports)
+	last := actions[len(actions)-1]
+	if last.Label != "Open in browser" || last.Command != "http://localhost:13000" {
+		t.Errorf("last action = %+v, want Open in browser at http://localhost:13000", last)
+	}
+
+	// No HTTP-looking port: no browser entry
+	for _, a := range GenerateContainerActions(c, "/usr/bin/docker", ports[:1]) {
+		if a.Label == "Open in browser" {
+			t.Error("unexpected Open in browser action for a non-HTTP port")
+		}
+	}
+}
+
 func TestGenerateVSCodeCommand(t *testing.T) {
 	containerID := "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"
 	workspacePath := "/workspaces"
@@ -158,3 +183,91 @@ func TestGenerateVSCodeCommand(t *testing.T) {
 		t.Errorf("JSON payload should contain containerName=%q, got %v", containerID, decoded)
 	}
 }
+
+func TestGenerateContainerActions_VSCodeArgsUseWorkspaceFolder(t *testing.T) {
+	projectPath := t.TempDir()
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(devcontainerDir, "devcontainer.json"), []byte(`{"name": "app", "workspaceFolder": "/workspaces/myapp"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &container.Container{ID: "0123abcd", Name: "myapp", ProjectPath: projectPath}
+
+	actions := GenerateContainerActions(c, "docker", nil)
+	if actions[0].Label != "Open in VS Code" {
+		t.Fatalf("first action = %q, want Open in VS Code", actions[0].Label)
+	}
+	// hex of {"containerName":"0123abcd"}
+	wantURI := "vscode-remote://attached-container+7b22636f6e7461696e65724e616d65223a223031323361626364227d/workspaces/myapp"
+	if want := []string{"code", "--folder-uri", wantURI}; !slices.Equal(actions[0].Args, want) {
+		t.Errorf("Args = %q, want %q", actions[0].Args, want)
+	}
+	if actions[0].Command != "code --folder-uri "+wantURI {
+		t.Errorf("Command = %q, want the same invocation", actions[0].Command)
+	}
+	for _, a := range actions[1:] {
+		if a.Args != nil {
+			t.Errorf("action %q should be copy-only", a.Label)
+		}
+	}
+}
+
+func TestGenerateAttachCommand(t *testing.T) {
+	c := &container.Container{Name: "proj-app-1", RemoteUser: "dev"}
+	got := GenerateAttachCommand(c, "agent", "/usr/bin/docker", "")
+	want := "/usr/bin/docker exec -it -u dev proj-app-1 tmux attach -t agent"
+	if got != want {
+		t.Errorf("GenerateAttachCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateAttachCommand_CustomTemplate(t *testing.T) {
+	c := &container.Container{Name: "proj-app-1"}
+	tmpl := "{{.Runtime}} exec -it -u {{.User}} -e TERM=xterm-256color {{.Name}} tmux -u attach -t {{.Session}}"
+	got := GenerateAttachCommand(c, "agent", "podman", tmpl)
+	want := "podman exec -it -u vscode -e TERM=xterm-256color proj-app-1 tmux -u attach -t agent"
+	if got != want {
+		t.Errorf("GenerateAttachCommand() = %q, want %q", got, want)
+	}
+
+	// An unrenderable template falls back to the default form
+	got = GenerateAttachCommand(c, "agent", "podman", "{{.Shell}}")
+	want = "podman exec -it -u vscode proj-app-1 tmux attach -t agent"
+	if got != want {
+		t.Errorf("GenerateAttachCommand() with bad template = %q, want %q", got, want)
+	}
+}
+
+func TestAttachArgs(t *testing.T) {
+	c := &container.Container{Name: "proj-app-1"}
+	got := AttachArgs(c, "agent", "/usr/bin/podman")
+	want := []string{"/usr/bin/podman", "exec", "-it", "-u", "vscode", "proj-app-1", "tmux", "attach", "-t", "agent"}
+	if !slices.Equal(got, want) {
+		t.Errorf("AttachArgs() = %v, want %v", got, want)
+	}
+
+	c.RemoteUser = "dev"
+	if got := AttachArgs(c, "agent", "docker"); got[4] != "dev" {
+		t.Errorf("AttachArgs() user = %q, want dev", got[4])
+	}
+}
+
+func TestGenerateProjectAttachCommands(t *testing.T) {
+	containers := []*container.Container{
+		{Name: "proj-app-1", Sessions: []tmux.Session{{Name: "dev"}, {Name: "agent"}}},
+		{Name: "proj-feature-app-1", RemoteUser: "node", Sessions: []tmux.Session{{Name: "dev"}}},
+		{Name: "proj-stopped-app-1"},
+	}
+
+	got := GenerateProjectAttachCommands(containers, "docker", "")
+	want := []string{
+		"docker exec -it -u vscode proj-app-1 tmux attach -t dev",
+		"docker exec -it -u vscode proj-app-1 tmux attach -t agent",
+		"docker exec -it -u node proj-feature-app-1 tmux attach -t dev",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("GenerateProjectAttachCommands() =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+// pattern: Imperative Shell
+
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devagent/internal/container"
+)
+
+// maxContainerLogLines bounds the followed container output kept in memory;
+// the oldest lines are dropped first.
+const maxContainerLogLines = 500
+
+// containerLogTail is the container output followed into the log panel. At
+// most one container is followed at a time; id is empty when none is.
+type containerLogTail struct {
+	id     string
+	name   string
+	lines  []string
+	ended  bool               // the stream closed (e.g. the container stopped)
+	cancel context.CancelFunc // stops the stream; nil once ended
+}
+
+// containerLogLinesMsg delivers a batch of followed output for container id.
+// closed is set when the stream has ended.
+type containerLogLinesMsg struct {
+	id     string
+	lines  []string
+	closed bool
+	ch     <-chan string
+}
+
+// startContainerLogs follows c's output in the log panel, replacing any
+// container followed before, and opens the panel.
+func (m *Model) startContainerLogs(c *container.Container) tea.Cmd {
+	m.stopContainerLogs()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := m.manager.StreamLogs(ctx, c.ID)
+	if err != nil {
+		cancel()
+		m.setError("Failed to follow logs", err)
+		return nil
+	}
+	m.logger.Debug("following container logs", "container", c.Name)
+	m.containerTail = containerLogTail{id: c.ID, name: c.Name, cancel: cancel}
+	m.setLogPanelOpen(true)
+	return waitForContainerLogs(c.ID, ch)
+}
+
+// stopContainerLogs cancels the followed stream, if any, and returns the log
+// panel to devagent's own logs.
+func (m *Model) stopContainerLogs() {
+	if m.containerTail.cancel != nil {
+		m.containerTail.cancel()
+	}
+	m.containerTail = containerLogTail{}
+}
+
+// stopContainerLogsUnlessSelected stops following once the followed container
+// is no longer the selected one.
+func (m *Model) stopContainerLogsUnlessSelected() {
+	if m.containerTail.id == "" {
+		return
+	}
+	if m.selectedContainer == nil || m.selectedContainer.ID != m.containerTail.id {
+		m.stopContainerLogs()
+	}
+}
+
+// addContainerLogLines appends followed output, dropping the oldest lines
+// beyond maxContainerLogLines.
+func (m *Model) addContainerLogLines(lines []string) {
+	m.containerTail.lines = append(m.containerTail.lines, lines...)
+	if len(m.containerTail.lines) > maxContainerLogLines {
+		m.containerTail.lines = m.containerTail.lines[len(m.containerTail.lines)-maxContainerLogLines:]
+	}
+}
+
+// handleContainerLogLines records a batch of followed output and waits for
+// the next one. Batches from a stream that was stopped are discarded.
+func (m *Model) handleContainerLogLines(msg containerLogLinesMsg) tea.Cmd {
+	if msg.id != m.containerTail.id {
+		return nil
+	}
+	m.addContainerLogLines(msg.lines)
+	if msg.closed {
+		m.containerTail.ended = true
+		m.containerTail.cancel = nil
+		return nil
+	}
+	return waitForContainerLogs(msg.id, msg.ch)
+}
+
+// waitForContainerLogs returns a command that waits for followed output,
+// batching up to 50 lines that are already available.
+func waitForContainerLogs(id string, ch <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return containerLogLinesMsg{id: id, closed: true}
+		}
+		lines := []string{line}
+		for len(lines) < 50 {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					return containerLogLinesMsg{id: id, lines: lines, closed: true}
+				}
+				lines = append(lines, line)
+			default:
+				return containerLogLinesMsg{id: id, lines: lines, ch: ch}
+			}
+		}
+		return containerLogLinesMsg{id: id, lines: lines, ch: ch}
+	}
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestContainerLogLines_BoundedAndScopedToFollowedContainer(t *testing.T) {
+	m := newTestModel(t)
+	m.containerTail = containerLogTail{id: "abc", name: "app", cancel: func() {}}
+
+	lines := make([]string, maxContainerLogLines+10)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	ch := make(chan string)
+	if cmd := m.handleContainerLogLines(containerLogLinesMsg{id: "abc", lines: lines, ch: ch}); cmd == nil {
+		t.Error("expected a command waiting for more output")
+	}
+	if got := len(m.containerTail.lines); got != maxContainerLogLines {
+		t.Fatalf("kept %d lines, want %d", got, maxContainerLogLines)
+	}
+	if first := m.containerTail.lines[0]; first != "line 10" {
+		t.Errorf("oldest kept line = %q, want %q", first, "line 10")
+	}
+
+	// A batch from a stream that is no longer followed is dropped
+	m.handleContainerLogLines(containerLogLinesMsg{id: "other", lines: []string{"stale"}})
+	if last := m.containerTail.lines[len(m.containerTail.lines)-1]; last == "stale" {
+		t.Error("stale stream output should be discarded")
+	}
+
+	m.handleContainerLogLines(containerLogLinesMsg{id: "abc", closed: true})
+	if !m.containerTail.ended {
+		t.Error("closed stream should mark the tail ended")
+	}
+}
+
+func TestContainerLogs_SelectionChangeStopsStream(t *testing.T) {
+	m := newTestModel(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.containerTail = containerLogTail{id: "abc", name: "app", lines: []string{"hello"}, cancel: cancel}
+
+	// Selecting anything other than the followed container stops the stream
+	m.selectedIdx = -1
+	m.syncSelectionFromTree()
+
+	if ctx.Err() == nil {
+		t.Error("changing the selection should cancel the log stream")
+	}
+	if m.containerTail.id != "" || m.containerTail.lines != nil {
+		t.Errorf("containerTail = %+v, want cleared", m.containerTail)
+	}
+}
@@ -96,7 +96,7 @@ func (d containerDelegate) Render(w io.Writer, m list.Model, index int, item lis
 	switch ci.container.State {
 	case container.StateRunning:
 		stateColor = lipgloss.Color(d.styles.flavor.Green().Hex)
-	case container.StateStopped:
+	case container.StateStopped, container.StateExited:
 		stateColor = lipgloss.Color(d.styles.flavor.Red().Hex)
 	default:
 		stateColor = lipgloss.Color(d.styles.flavor.Yellow().Hex)
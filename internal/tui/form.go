@@ -3,12 +3,22 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"devagent/internal/config"
+	"devagent/internal/container"
 	"devagent/internal/discovery"
 )
 
@@ -19,9 +29,15 @@ const (
 	FieldTemplate FormField = iota
 	FieldProjectPath
 	FieldContainerName
+	FieldMounts
 	fieldCount // Used for wrap-around
 )
 
+// formTemplateListHeight is how many filtered templates the create form lists
+// at once while the template field is focused; the list scrolls to keep the
+// selection visible.
+const formTemplateListHeight = 5
+
 // Form state accessors for testing and view rendering.
 
 // IsFormOpen returns true if the container creation form is open.
@@ -39,6 +55,16 @@ func (m Model) FormContainerName() string {
 	return m.formContainerName
 }
 
+// FormMounts returns the current extra mounts input.
+func (m Model) FormMounts() string {
+	return m.formMounts
+}
+
+// FormTemplateQuery returns the current template filter.
+func (m Model) FormTemplateQuery() string {
+	return m.formTemplateQuery
+}
+
 // FormTemplateIndex returns the currently selected template index.
 func (m Model) FormTemplateIndex() int {
 	return m.formTemplateIdx
@@ -54,12 +80,22 @@ func (m Model) FormError() string {
 	return m.formError
 }
 
-// resetForm clears the form state.
+// resetForm clears the form state, canceling an in-flight create. Its
+// messages are stale from here on (see formCreationDoneMsg).
 func (m *Model) resetForm() {
+	if m.formCancel != nil {
+		m.formCancel()
+		m.formCancel = nil
+	}
+	m.formProgressChan = nil
 	m.formOpen = false
 	m.formTemplateIdx = 0
+	m.formTemplateQuery = ""
 	m.formProjectPath = ""
 	m.formContainerName = ""
+	m.formNameEdited = false
+	m.formMounts = ""
+	m.formClone = false
 	m.formFocusedField = FieldTemplate
 	m.formError = ""
 
@@ -70,32 +106,268 @@ func (m *Model) resetForm() {
 	m.formCurrentStep = ""
 	m.formCompleted = false
 	m.formCompletedError = false
+	m.formExists = false
+	m.formForce = false
+	m.formKeepDevcontainer = false
+	m.formPreviewOpen = false
 }
 
 // openForm opens the creation form.
 func (m *Model) openForm() {
 	m.formOpen = true
 	m.formTemplateIdx = 0
+	m.formTemplateQuery = ""
 	m.formProjectPath = ""
 	m.formContainerName = ""
+	m.formNameEdited = false
+	m.formMounts = ""
+	m.formKeepDevcontainer = false
 	m.formFocusedField = FieldTemplate
 	m.formError = ""
 
 	// Check if templates are available
 	if len(m.templates) == 0 {
 		m.formError = "No templates available"
+		return
+	}
+	m.applyTemplateDefaults()
+}
+
+// openCloneForm opens the creation form in its clone variant: the project
+// path field takes a git URL, which is cloned under the clone root before the
+// container is created (see container.Manager.CloneAndCreate).
+func (m *Model) openCloneForm() {
+	m.openForm()
+	m.formClone = true
+	m.formProjectPath = ""
+	m.formContainerName = ""
+	m.formFocusedField = FieldProjectPath
+}
+
+// IsCloneForm returns true if the open form clones a git URL.
+func (m Model) IsCloneForm() bool {
+	return m.formClone
+}
+
+// applyTemplateDefaults pre-fills the form from the selected template: an
+// empty project path gets the template's DefaultScanRoot, and the container
+// name is regenerated from its NameTemplate.
+func (m *Model) applyTemplateDefaults() {
+	if m.formTemplateIdx >= len(m.templates) {
+		return
+	}
+	tmpl := m.templates[m.formTemplateIdx]
+	if m.formProjectPath == "" && tmpl.DefaultScanRoot != "" && !m.formClone {
+		m.formProjectPath = m.cfg.ResolveTokenPath(tmpl.DefaultScanRoot)
+	}
+	m.updateGeneratedName()
+}
+
+// updateGeneratedName renders the selected template's NameTemplate against the
+// project path (in the clone variant, the clone destination) into the name
+// field. Does nothing once the user has typed a name; clears a previously
+// generated name when the template has none. An invalid rendered name is left
+// out and reported as a form error.
+func (m *Model) updateGeneratedName() {
+	if m.formNameEdited || m.formTemplateIdx >= len(m.templates) {
+		return
+	}
+	tmpl := m.templates[m.formTemplateIdx]
+	projectPath := strings.TrimSpace(m.formProjectPath)
+	if m.formClone && projectPath != "" {
+		// an unfinished URL has no destination yet; leave the name empty
+		projectPath, _ = m.manager.CloneDestination(projectPath)
+	}
+	if tmpl.NameTemplate == "" || projectPath == "" {
+		m.formContainerName = ""
+		return
+	}
+	name, err := config.RenderContainerName(tmpl.NameTemplate, projectPath, tmpl.Name)
+	if err != nil {
+		m.formContainerName = ""
+		m.formError = "Name template: " + err.Error()
+		return
+	}
+	m.formContainerName = name
+}
+
+// filteredTemplates returns the indexes into m.templates whose names contain
+// the template filter (case-insensitive), in template order.
+func (m Model) filteredTemplates() []int {
+	query := strings.ToLower(m.formTemplateQuery)
+	var idxs []int
+	for i, tmpl := range m.templates {
+		if strings.Contains(strings.ToLower(tmpl.Name), query) {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// moveTemplateSelection moves the template selection by delta within the
+// filtered templates, stopping at either end.
+func (m *Model) moveTemplateSelection(delta int) {
+	idxs := m.filteredTemplates()
+	pos := slices.Index(idxs, m.formTemplateIdx)
+	if pos < 0 {
+		return
+	}
+	next := pos + delta
+	if next < 0 || next >= len(idxs) {
+		return
+	}
+	m.formTemplateIdx = idxs[next]
+	m.formError = ""
+	m.applyTemplateDefaults()
+}
+
+// setTemplateQuery changes the template filter. When the selected template no
+// longer matches, the first match is selected; when nothing matches, the
+// selection is kept so it stays valid.
+func (m *Model) setTemplateQuery(query string) {
+	m.formTemplateQuery = query
+	idxs := m.filteredTemplates()
+	if len(idxs) == 0 || slices.Contains(idxs, m.formTemplateIdx) {
+		return
+	}
+	m.formTemplateIdx = idxs[0]
+	m.applyTemplateDefaults()
+}
+
+// editFormField applies an edit to the focused text field. Editing the
+// template field filters the templates; editing the project path regenerates
+// the name; editing the name stops generation until the field is cleared
+// again.
+func (m *Model) editFormField(edit func(string) string) {
+	switch m.formFocusedField {
+	case FieldTemplate:
+		m.setTemplateQuery(edit(m.formTemplateQuery))
+	case FieldProjectPath:
+		m.formProjectPath = edit(m.formProjectPath)
+		m.updateGeneratedName()
+	case FieldContainerName:
+		m.formContainerName = edit(m.formContainerName)
+		m.formNameEdited = m.formContainerName != ""
+	case FieldMounts:
+		m.formMounts = edit(m.formMounts)
+	}
+}
+
+// FormOverwriteDevcontainer returns whether the create replaces the project's
+// own devcontainer.json (the default; ctrl+o keeps it).
+func (m Model) FormOverwriteDevcontainer() bool {
+	return !m.formKeepDevcontainer
+}
+
+// hasOwnDevcontainerJSON reports whether the form's project has its own
+// .devcontainer/devcontainer.json that a create would replace: one without a
+// docker-compose.yml (with one, no template files are written at all).
+func (m Model) hasOwnDevcontainerJSON() bool {
+	projectPath := strings.TrimSpace(m.formProjectPath)
+	if m.formClone || projectPath == "" {
+		return false
+	}
+	devDir := filepath.Join(projectPath, ".devcontainer")
+	if _, err := os.Stat(filepath.Join(devDir, "devcontainer.json")); err != nil {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(devDir, "docker-compose.yml"))
+	return os.IsNotExist(err)
+}
+
+// formPreviewMsg carries the result of previewing the form's create.
+type formPreviewMsg struct {
+	result *container.GenerateResult
+	err    error
+}
+
+// previewCreate returns a command that previews the form's create with
+// container.Manager.PreviewCreate, which writes and starts nothing.
+func (m Model) previewCreate() tea.Cmd {
+	opts := container.CreateOptions{
+		ProjectPath: strings.TrimSpace(m.formProjectPath),
+		Name:        strings.TrimSpace(m.formContainerName),
+		ExtraMounts: container.SplitMounts(m.formMounts),
+		UseExisting: m.formKeepDevcontainer,
+	}
+	if m.formTemplateIdx < len(m.templates) {
+		opts.Template = m.templates[m.formTemplateIdx].Name
+	}
+	if opts.Name == "" {
+		opts.Name = container.SanitizeComposeName(filepath.Base(opts.ProjectPath))
+	}
+	manager := m.manager
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		result, err := manager.PreviewCreate(ctx, opts)
+		return formPreviewMsg{result: result, err: err}
 	}
 }
 
+// openFormPreview shows a create preview in the form's scrollable viewport.
+func (m *Model) openFormPreview(result *container.GenerateResult) {
+	m.formPreview = viewport.New(max(m.width-4, 40), max(m.height-8, 5))
+	m.formPreview.SetContent(formPreviewContent(result))
+	m.formPreviewOpen = true
+}
+
+// formPreviewContent renders a create preview: the docker run flags the app
+// container's isolation and mounts amount to, then the devcontainer.json.
+func formPreviewContent(result *container.GenerateResult) string {
+	var b strings.Builder
+	b.WriteString("Run args:\n")
+	if len(result.RunArgs) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, arg := range result.RunArgs {
+		b.WriteString("  " + arg + "\n")
+	}
+	if len(result.IgnoredRunArgs) > 0 {
+		b.WriteString("\ndevcontainer.json runArgs, ignored (compose starts the container):\n")
+		for _, arg := range result.IgnoredRunArgs {
+			b.WriteString("  " + arg + "\n")
+		}
+	}
+	if result.Plan != nil && result.Plan.Proxy != nil {
+		fmt.Fprintf(&b, "\nProxy: %s (%d allowed domains)\n", result.Plan.Proxy.Image, len(result.Plan.Proxy.AllowedDomains))
+	}
+	b.WriteString("\ndevcontainer.json:\n")
+	if result.DevcontainerJSON == "" {
+		b.WriteString("  (none)\n")
+	}
+	b.WriteString(result.DevcontainerJSON)
+	return b.String()
+}
+
 // validateForm validates form inputs before submission.
 // Returns an error message string, or empty string if valid.
 func (m Model) validateForm() string {
-	if len(m.formProjectPath) == 0 {
+	if m.formClone {
+		url := strings.TrimSpace(m.formProjectPath)
+		if url == "" {
+			return "Git URL is required"
+		}
+		if _, err := m.manager.CloneDestination(url); err != nil {
+			return err.Error()
+		}
+	} else if len(m.formProjectPath) == 0 {
 		return "Project path is required"
 	}
 	if len(m.templates) == 0 {
 		return "No templates available"
 	}
+	if len(m.filteredTemplates()) == 0 {
+		return "No templates match " + strconv.Quote(m.formTemplateQuery)
+	}
+	if name := strings.TrimSpace(m.formContainerName); name != "" {
+		if err := config.ValidateContainerName(name); err != nil {
+			return "Invalid container name: use lowercase letters, digits, '-' and '_'"
+		}
+	}
+	if _, err := container.ParseMounts(container.SplitMounts(m.formMounts)); err != nil {
+		return "Invalid " + err.Error()
+	}
 	return ""
 }
 
@@ -110,6 +382,7 @@ func (m *Model) startFormSubmission() tea.Cmd {
 	m.formCurrentStep = ""
 	m.formCompleted = false
 	m.formCompletedError = false
+	m.formExists = false
 
 	// Initialize status spinner (for current step)
 	m.formStatusSpinner = spinner.New()
@@ -165,14 +438,19 @@ func (m Model) IsFormCompleted() bool {
 func (m *Model) openWorktreeForm(project *discovery.DiscoveredProject) {
 	m.worktreeFormOpen = true
 	m.worktreeFormName = ""
+	m.worktreeFormBase = ""
+	m.worktreeFormBaseFocused = false
 	m.worktreeFormProject = project
 	m.worktreeFormError = ""
+	m.worktreeFormVerifying = false
 }
 
 // resetWorktreeForm clears the worktree form state.
 func (m *Model) resetWorktreeForm() {
 	m.worktreeFormOpen = false
 	m.worktreeFormName = ""
+	m.worktreeFormBase = ""
+	m.worktreeFormBaseFocused = false
 	m.worktreeFormProject = nil
 	m.worktreeFormError = ""
 }
@@ -181,3 +459,75 @@ func (m *Model) resetWorktreeForm() {
 func (m Model) IsWorktreeFormOpen() bool {
 	return m.worktreeFormOpen
 }
+
+// openAllowlistEditor opens the allowlist editor for a container with its
+// current allowed domains.
+func (m *Model) openAllowlistEditor(c *container.Container, domains []string) {
+	m.allowlistEditorOpen = true
+	m.allowlistContainer = c
+	m.allowlistDomains = slices.Clone(domains)
+	m.allowlistSelected = 0
+	m.allowlistInput = ""
+	m.allowlistError = ""
+}
+
+// resetAllowlistEditor clears the allowlist editor state.
+func (m *Model) resetAllowlistEditor() {
+	m.allowlistEditorOpen = false
+	m.allowlistContainer = nil
+	m.allowlistDomains = nil
+	m.allowlistSelected = 0
+	m.allowlistInput = ""
+	m.allowlistError = ""
+}
+
+// openNoteEditor opens the note editor for a container with its current note.
+func (m *Model) openNoteEditor(c *container.Container, note string) {
+	m.noteEditorOpen = true
+	m.noteContainer = c
+	m.noteInput = note
+	m.noteError = ""
+}
+
+// resetNoteEditor clears the note editor state.
+func (m *Model) resetNoteEditor() {
+	m.noteEditorOpen = false
+	m.noteContainer = nil
+	m.noteInput = ""
+	m.noteError = ""
+}
+
+// addAllowlistDomain validates the typed domain and adds it to the editor's
+// list, selecting it. Invalid or duplicate domains set allowlistError instead.
+func (m *Model) addAllowlistDomain() {
+	domain := strings.ToLower(strings.TrimSpace(m.allowlistInput))
+	if err := container.ValidateAllowlistDomain(domain); err != nil {
+		m.allowlistError = err.Error()
+		return
+	}
+	if slices.Contains(m.allowlistDomains, domain) {
+		m.allowlistError = domain + " is already allowed"
+		return
+	}
+	m.allowlistDomains = append(m.allowlistDomains, domain)
+	m.allowlistSelected = len(m.allowlistDomains) - 1
+	m.allowlistInput = ""
+	m.allowlistError = ""
+}
+
+// removeAllowlistDomain removes the selected domain from the editor's list.
+func (m *Model) removeAllowlistDomain() {
+	if m.allowlistSelected < 0 || m.allowlistSelected >= len(m.allowlistDomains) {
+		return
+	}
+	m.allowlistDomains = slices.Delete(m.allowlistDomains, m.allowlistSelected, m.allowlistSelected+1)
+	if m.allowlistSelected >= len(m.allowlistDomains) && m.allowlistSelected > 0 {
+		m.allowlistSelected--
+	}
+	m.allowlistError = ""
+}
+
+// IsAllowlistEditorOpen returns true if the allowlist editor is open.
+func (m Model) IsAllowlistEditorOpen() bool {
+	return m.allowlistEditorOpen
+}
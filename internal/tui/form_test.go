@@ -1,18 +1,30 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"devagent/internal/config"
+	"devagent/internal/container"
+	"devagent/internal/discovery"
 	"devagent/internal/logging"
 )
 
 func newTestModel(t *testing.T) Model {
-	cfg := &config.Config{
+	return newTestModelWithConfig(t, &config.Config{
 		Theme: "mocha",
-	}
+	})
+}
+
+// newTestModelWithConfig is newTestModel with a caller-supplied config.
+func newTestModelWithConfig(t *testing.T, cfg *config.Config) Model {
 	templates := []config.Template{
 		{Name: "go-project"},
 		{Name: "python-project"},
@@ -135,6 +147,125 @@ func TestForm_TemplateSelection_ArrowKeys(t *testing.T) {
 	}
 }
 
+func TestForm_TemplateFilter_SelectsWithinMatches(t *testing.T) {
+	m := newTestModel(t)
+	m.templates = []config.Template{
+		{Name: "basic"},
+		{Name: "go-project"},
+		{Name: "python-project"},
+		{Name: "go-web"},
+		{Name: "rust"},
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+
+	// Filtering drops "basic", so the first match is selected.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("GO")})
+	m = updated.(Model)
+	if m.FormTemplateQuery() != "GO" || m.FormTemplateIndex() != 1 {
+		t.Fatalf("after filter: query %q, index %d, want GO and 1 (go-project)", m.FormTemplateQuery(), m.FormTemplateIndex())
+	}
+
+	// Down skips python-project, which doesn't match, and stops at the end.
+	for range 2 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	if got := m.templates[m.FormTemplateIndex()].Name; got != "go-web" {
+		t.Errorf("selected %q after down, want go-web", got)
+	}
+	view := m.View()
+	if !strings.Contains(view, "go-project") || strings.Contains(view, "python-project") || strings.Contains(view, "rust") {
+		t.Errorf("template list should show only matches:\n%s", view)
+	}
+
+	// A filter matching nothing keeps the selection valid but blocks submit.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	if got := m.templates[m.FormTemplateIndex()].Name; got != "go-web" {
+		t.Errorf("selected %q with no matches, want go-web kept", got)
+	}
+	m.formProjectPath = t.TempDir()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || !strings.Contains(m.FormError(), "No templates match") {
+		t.Errorf("submit with no matches: error %q, want No templates match", m.FormError())
+	}
+
+	// Narrowing to "GO-w" keeps go-web, the only match, so up stays there.
+	for range 2 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-w")})
+	m = updated.(Model)
+	if got := m.templates[m.FormTemplateIndex()].Name; got != "go-web" {
+		t.Errorf("selected %q for filter %q, want go-web", got, m.FormTemplateQuery())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(Model)
+	if got := m.templates[m.FormTemplateIndex()].Name; got != "go-web" {
+		t.Errorf("up moved to %q, want go-web (only match)", got)
+	}
+}
+
+func TestForm_NameTemplate_AutoFillsName(t *testing.T) {
+	m := newTestModel(t)
+	m.templates[1].NameTemplate = "{{.ProjectBase}}-{{.Template}}"
+	m.templates[1].DefaultScanRoot = "/srv/code/api"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	if m.FormContainerName() != "" || m.FormProjectPath() != "" {
+		t.Fatalf("first template has no defaults, got (%q, %q)", m.FormProjectPath(), m.FormContainerName())
+	}
+
+	// Selecting the template pre-fills the path and renders the name
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.FormProjectPath() != "/srv/code/api" || m.FormContainerName() != "api-python-project" {
+		t.Fatalf("got (%q, %q), want default path and rendered name", m.FormProjectPath(), m.FormContainerName())
+	}
+
+	// Editing the path re-renders the name
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(Model)
+	if m.FormContainerName() != "api2-python-project" {
+		t.Errorf("name = %q, want it regenerated from the new path", m.FormContainerName())
+	}
+
+	// A typed name overrides generation
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	m.formFocusedField = FieldProjectPath
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	m = updated.(Model)
+	if m.FormContainerName() != "api2-python-projectx" {
+		t.Errorf("name = %q, user override should be kept", m.FormContainerName())
+	}
+}
+
+func TestForm_NameTemplate_InvalidNameShowsError(t *testing.T) {
+	m := newTestModel(t)
+	m.templates[0].NameTemplate = "{{.ProjectBase}}"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/src/MyApp")})
+	m = updated.(Model)
+
+	if m.FormContainerName() != "" || !strings.Contains(m.FormError(), "invalid container name") {
+		t.Errorf("got name %q, error %q; want no name and an invalid-name error", m.FormContainerName(), m.FormError())
+	}
+}
+
 func TestForm_TemplateSelection_BoundsCheck(t *testing.T) {
 	m := newTestModel(t)
 
@@ -188,6 +319,13 @@ func TestForm_TabCycles_ThroughFields(t *testing.T) {
 		t.Errorf("Expected focused field 2, got %d", m.FormFocusedField())
 	}
 
+	// Tab to mounts
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.FormFocusedField() != 3 {
+		t.Errorf("Expected focused field 3, got %d", m.FormFocusedField())
+	}
+
 	// Tab wraps back to template
 	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
 	m = updated.(Model)
@@ -196,6 +334,116 @@ func TestForm_TabCycles_ThroughFields(t *testing.T) {
 	}
 }
 
+func TestForm_InvalidMount_ShowsError(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	m.formProjectPath = t.TempDir()
+	m.formFocusedField = FieldMounts
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("type=bind,source=/a,target=/b, type=bind,target=/c")})
+	m = updated.(Model)
+	if m.FormMounts() != "type=bind,source=/a,target=/b, type=bind,target=/c" {
+		t.Fatalf("FormMounts() = %q", m.FormMounts())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.IsFormSubmitting() {
+		t.Fatal("form submitted with an invalid mount")
+	}
+	if !strings.Contains(m.FormError(), "source is required") {
+		t.Errorf("FormError() = %q, want the missing source reported", m.FormError())
+	}
+}
+
+func TestForm_ExistingDevcontainerJSON_WarnsAndToggles(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	projectPath := t.TempDir()
+	m.formProjectPath = projectPath
+	if strings.Contains(m.View(), "Existing devcontainer.json") {
+		t.Error("warning shown for a project without devcontainer.json")
+	}
+
+	devDir := filepath.Join(projectPath, ".devcontainer")
+	if err := os.MkdirAll(devDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(devDir, "devcontainer.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Overwriting stays the default, as before the form knew about the file
+	if !m.FormOverwriteDevcontainer() {
+		t.Error("FormOverwriteDevcontainer() = false, want overwrite by default")
+	}
+	if view := m.View(); !strings.Contains(view, "devcontainer.json: overwrite") || !strings.Contains(view, "ctrl+o: keep it") {
+		t.Errorf("view lacks the overwrite warning:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = updated.(Model)
+	if m.FormOverwriteDevcontainer() {
+		t.Fatal("ctrl+o did not switch to keeping the file")
+	}
+	if view := m.View(); !strings.Contains(view, "docker-compose.yml still defines the container") || !strings.Contains(view, "ctrl+o: overwrite") {
+		t.Errorf("view lacks the keep warning:\n%s", view)
+	}
+
+	// With a docker-compose.yml no template files are written, so no warning.
+	if err := os.WriteFile(filepath.Join(devDir, "docker-compose.yml"), []byte("services: {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(m.View(), "Existing devcontainer.json") {
+		t.Error("warning shown for a project with its own docker-compose.yml")
+	}
+}
+
+func TestForm_CtrlP_ShowsPreviewModal(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+
+	// Without a project path there is nothing to preview
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	if cmd != nil || m.FormError() != "Project path is required" {
+		t.Fatalf("ctrl+p on an empty form: cmd = %v, FormError() = %q", cmd, m.FormError())
+	}
+
+	m.width, m.height = 100, 40
+	m.formProjectPath = t.TempDir()
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("ctrl+p returned no preview command")
+	}
+
+	updated, _ = m.Update(formPreviewMsg{result: &container.GenerateResult{
+		DevcontainerJSON: `{"name": "demo"}`,
+		RunArgs:          []string{"--cap-drop=NET_RAW", "--memory=4g"},
+	}})
+	m = updated.(Model)
+	view := m.View()
+	for _, want := range []string{"Create Preview", "--cap-drop=NET_RAW", `"name": "demo"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview view lacks %q:\n%s", want, view)
+		}
+	}
+	if m.IsFormSubmitting() {
+		t.Error("preview started the create")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	if !m.IsFormOpen() || strings.Contains(m.View(), "Create Preview") {
+		t.Error("Esc should close the preview and keep the form open")
+	}
+}
+
 func TestForm_Backspace_DeletesCharacter(t *testing.T) {
 	m := newTestModel(t)
 
@@ -282,6 +530,41 @@ func TestForm_Submit_EmptyProjectPath_ShowsError(t *testing.T) {
 	}
 }
 
+func TestCloneForm_ValidatesURLAndSubmits(t *testing.T) {
+	root := t.TempDir()
+	m := newTestModelWithConfig(t, &config.Config{Theme: "mocha", CloneRoot: root})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = updated.(Model)
+	if !m.IsFormOpen() || !m.IsCloneForm() {
+		t.Fatal("'i' should open the clone form")
+	}
+	if m.FormFocusedField() != int(FieldProjectPath) {
+		t.Errorf("focused field = %d, want the git URL field", m.FormFocusedField())
+	}
+	if view := m.renderCreateForm(); !strings.Contains(view, "Clone Repository") || !strings.Contains(view, "Git URL:") {
+		t.Errorf("clone form view should name its variant, got:\n%s", view)
+	}
+
+	// A URL with no safe directory name is rejected before anything runs
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("https://github.com/org/..")})
+	m = updated.(Model)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.IsFormSubmitting() || m.FormError() == "" {
+		t.Fatalf("invalid URL should show an error, got error %q, submitting %v", m.FormError(), m.IsFormSubmitting())
+	}
+
+	m.formProjectPath = "git@github.com:org/repo.git"
+	if errMsg := m.validateForm(); errMsg != "" {
+		t.Errorf("valid URL rejected: %s", errMsg)
+	}
+	m.resetForm()
+	if m.IsCloneForm() {
+		t.Error("resetForm should clear the clone variant")
+	}
+}
+
 func TestForm_NoTemplates_ShowsError(t *testing.T) {
 	cfg := &config.Config{Theme: "mocha"}
 	tmpDir := t.TempDir()
@@ -558,3 +841,278 @@ func containsStringHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWorktreeForm_TabSwitchesToBaseField(t *testing.T) {
+	m := newTestModel(t)
+	m.openWorktreeForm(&discovery.DiscoveredProject{Name: "proj", Path: t.TempDir()})
+
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("feat")},
+		{Type: tea.KeyTab},
+		{Type: tea.KeyRunes, Runes: []rune("mainx")},
+		{Type: tea.KeyBackspace},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	if m.worktreeFormName != "feat" {
+		t.Errorf("worktreeFormName = %q, want %q", m.worktreeFormName, "feat")
+	}
+	if m.worktreeFormBase != "main" {
+		t.Errorf("worktreeFormBase = %q, want %q", m.worktreeFormBase, "main")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updated.(Model)
+	if m.worktreeFormBaseFocused {
+		t.Error("shift+tab should return focus to the name field")
+	}
+}
+
+func TestWorktreeForm_UnknownBaseRef_ShowsError(t *testing.T) {
+	m := newTestModel(t)
+	// A directory that is not a git repository cannot resolve any ref
+	m.openWorktreeForm(&discovery.DiscoveredProject{Name: "proj", Path: t.TempDir()})
+	m.worktreeFormName = "feat"
+	m.worktreeFormBase = "no-such-ref"
+
+	// Enter resolves the ref in a command instead of blocking Update
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || !m.worktreeFormVerifying {
+		t.Fatal("Enter with a base ref should start verifying it")
+	}
+	if !strings.Contains(m.View(), "Checking base ref") {
+		t.Error("view should show the ref being checked")
+	}
+	if _, again := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); again != nil {
+		t.Error("Enter while verifying should do nothing")
+	}
+
+	updated, cmd = m.Update(cmd())
+	result := updated.(Model)
+	if cmd != nil {
+		t.Error("no create command expected for an unknown base ref")
+	}
+	if !result.worktreeFormOpen {
+		t.Error("form should stay open")
+	}
+	if result.worktreeFormError != "unknown base ref" {
+		t.Errorf("worktreeFormError = %q, want %q", result.worktreeFormError, "unknown base ref")
+	}
+}
+
+func TestWorktreeForm_BaseRefVerifiedAfterEditIsIgnored(t *testing.T) {
+	m := newTestModel(t)
+	m.openWorktreeForm(&discovery.DiscoveredProject{Name: "proj", Path: t.TempDir()})
+	m.worktreeFormName = "feat"
+	m.worktreeFormBase = "main"
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	msg := cmd().(worktreeBaseVerifiedMsg)
+
+	// The base is edited before the check finishes: its result must not
+	// create (or reject) a worktree for the old value
+	m.worktreeFormBase = "develop"
+	msg.err = nil
+	updated, cmd = m.Update(msg)
+	m = updated.(Model)
+	if cmd != nil || !m.worktreeFormOpen || m.worktreeFormVerifying {
+		t.Errorf("stale result: cmd = %v, open = %v, verifying = %v; want the form open and idle", cmd != nil, m.worktreeFormOpen, m.worktreeFormVerifying)
+	}
+}
+
+func TestAllowlistEditor_AddRemove(t *testing.T) {
+	m := newTestModel(t)
+	c := &container.Container{ID: "c1", Name: "alpha", State: container.StateRunning}
+	m.openAllowlistEditor(c, []string{"github.com", "api.anthropic.com"})
+
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		return cmd
+	}
+	typeText := func(s string) {
+		t.Helper()
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	// Invalid and duplicate domains are rejected
+	typeText("https://bad")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.allowlistError == "" || len(m.allowlistDomains) != 2 {
+		t.Fatalf("invalid domain: error %q, domains %v", m.allowlistError, m.allowlistDomains)
+	}
+	m.allowlistInput = ""
+	typeText("github.com")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.allowlistError, "already allowed") {
+		t.Errorf("duplicate domain: error %q", m.allowlistError)
+	}
+
+	// Adding a valid domain selects it and clears the input
+	m.allowlistInput = ""
+	typeText("*.Example.com")
+	if m.allowlistError != "" {
+		t.Errorf("typing should clear the error, got %q", m.allowlistError)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	want := []string{"github.com", "api.anthropic.com", "*.example.com"}
+	if !slices.Equal(m.allowlistDomains, want) || m.allowlistSelected != 2 || m.allowlistInput != "" {
+		t.Fatalf("after add: domains %v, selected %d, input %q", m.allowlistDomains, m.allowlistSelected, m.allowlistInput)
+	}
+
+	// Removing the last entry moves the selection up
+	press(tea.KeyMsg{Type: tea.KeyDelete})
+	if !slices.Equal(m.allowlistDomains, want[:2]) || m.allowlistSelected != 1 {
+		t.Fatalf("after remove: domains %v, selected %d", m.allowlistDomains, m.allowlistSelected)
+	}
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	press(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if !slices.Equal(m.allowlistDomains, []string{"api.anthropic.com"}) || m.allowlistSelected != 0 {
+		t.Fatalf("after second remove: domains %v, selected %d", m.allowlistDomains, m.allowlistSelected)
+	}
+
+	// Enter with an empty input applies the list and closes the editor
+	cmd := press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.allowlistEditorOpen || cmd == nil || m.statusLevel != StatusLoading {
+		t.Errorf("apply: open %v, cmd %v, status %v", m.allowlistEditorOpen, cmd != nil, m.statusLevel)
+	}
+}
+
+func TestAllowlistEditor_OpensFromIsolatedDetailView(t *testing.T) {
+	projectPath := t.TempDir()
+	scriptDir := filepath.Join(projectPath, ".devcontainer", "containers", "proxy", "opt", "devagent-proxy")
+	if err := os.MkdirAll(scriptDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n"
+	if err := os.WriteFile(filepath.Join(scriptDir, "filter.py"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(t)
+	m.selectedContainer = &container.Container{ID: "c1", Name: "alpha", ProjectPath: projectPath, State: container.StateRunning}
+	m.detailPanelOpen = true
+
+	// Not isolated: "a" does nothing
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(Model)
+	if m.allowlistEditorOpen {
+		t.Fatal("editor should not open without network isolation")
+	}
+
+	m.cachedIsolationInfo = &container.IsolationInfo{NetworkIsolated: true}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = updated.(Model)
+	if !m.allowlistEditorOpen || !slices.Equal(m.allowlistDomains, []string{"github.com"}) {
+		t.Fatalf("editor open %v with domains %v, want [github.com]", m.allowlistEditorOpen, m.allowlistDomains)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	if m.allowlistEditorOpen {
+		t.Error("esc should close the editor")
+	}
+}
+
+func TestForm_EscapeDuringSubmission_CancelsCreate(t *testing.T) {
+	m := newTestModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.startFormSubmission()
+	m.formCancel = cancel
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+
+	if ctx.Err() == nil {
+		t.Error("Escape during submission should cancel the create's context")
+	}
+	if m.IsFormOpen() || m.formCancel != nil {
+		t.Error("form should be closed and its cancel func cleared")
+	}
+
+	// The canceled create finishing later must not reopen the form as completed
+	updated, _ = m.Update(formCreationDoneMsg{id: "demo", err: context.Canceled})
+	m = updated.(Model)
+	if m.IsFormCompleted() {
+		t.Error("a canceled create should not mark the form completed")
+	}
+}
+
+func TestForm_CanceledCreateDoesNotTouchNextCreate(t *testing.T) {
+	m := newTestModel(t)
+	open := func() {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+		m = updated.(Model)
+		m.formProjectPath = t.TempDir()
+		m.formContainerName = "demo"
+	}
+
+	// Create A is submitted, then canceled with Escape
+	open()
+	m.submitForm()
+	chA := m.formProgressChan
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	if m.formProgressChan != nil {
+		t.Fatal("resetForm should drop the canceled create's channel")
+	}
+
+	// Create B is submitted while A is still cleaning up
+	open()
+	m.submitForm()
+	chB := m.formProgressChan
+	defer m.resetForm()
+
+	// A's late progress and completion must not reach B
+	updated, _ = m.Update(formProgressMsg{step: container.ProgressStep{Status: "failed", Message: "A failed"}, ch: chA})
+	m = updated.(Model)
+	updated, _ = m.Update(formCreationDoneMsg{id: "demo", err: context.Canceled, ch: chA})
+	m = updated.(Model)
+	if !m.IsFormSubmitting() || m.formProgressChan != chB || m.formCancel == nil || len(m.formStatusSteps) != 0 {
+		t.Fatalf("B disturbed by A: submitting = %v, same channel = %v, cancel kept = %v, steps = %v",
+			m.IsFormSubmitting(), m.formProgressChan == chB, m.formCancel != nil, m.formStatusSteps)
+	}
+
+	// B's channel is still open for its create to send on
+	chB <- formProgressMsg{step: container.ProgressStep{Step: "done", Status: "completed"}}
+	updated, _ = m.Update(waitForProgress(chB, "demo")())
+	m = updated.(Model)
+	if !m.IsFormCompleted() || m.formCompletedError {
+		t.Errorf("B should complete successfully: completed = %v, error = %v", m.IsFormCompleted(), m.formCompletedError)
+	}
+}
+
+func TestForm_ExistingContainer_OffersForce(t *testing.T) {
+	m := newTestModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	m.formProjectPath = t.TempDir()
+	m.formContainerName = "demo"
+	m.startFormSubmission()
+
+	err := fmt.Errorf("%w: demo (%s)", container.ErrContainerExists, m.formProjectPath)
+	updated, _ = m.Update(formCreationDoneMsg{id: "demo", err: err})
+	m = updated.(Model)
+	if !m.formExists || !m.IsFormCompleted() {
+		t.Fatalf("formExists = %v, completed = %v; want both true", m.formExists, m.IsFormCompleted())
+	}
+	view := m.renderCreateForm()
+	if !strings.Contains(view, "already has a container") || !strings.Contains(view, "f: create anyway") {
+		t.Errorf("view should explain the conflict and offer f, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(Model)
+	if !m.formForce || !m.IsFormSubmitting() {
+		t.Errorf("f should resubmit with force: force = %v, submitting = %v", m.formForce, m.IsFormSubmitting())
+	}
+	m.resetForm()
+}
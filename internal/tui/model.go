@@ -4,9 +4,11 @@ package tui
 
 import (
 	"context"
-	"fmt"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -14,12 +16,15 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
+	"devagent/internal/audit"
 	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/logging"
 	"devagent/internal/tmux"
+	"devagent/internal/worktree"
 )
 
 // FormStatusStep represents a completed step during form submission.
@@ -37,6 +42,7 @@ const (
 	TreeItemWorktree
 	TreeItemContainer
 	TreeItemSession
+	TreeItemWindow
 )
 
 // TreeItem represents a selectable item in the tree view.
@@ -44,10 +50,13 @@ type TreeItem struct {
 	Type         TreeItemType
 	ContainerID  string
 	SessionName  string // empty for containers
-	Expanded     bool   // meaningful for containers and projects
+	WindowIndex  int    // tmux window index, set for window items
+	Expanded     bool   // meaningful for containers, sessions and projects
 	ProjectPath  string // set for project and worktree items
 	ProjectName  string // display name for project items
 	WorktreeName string // set for worktree items
+	Locked       bool   // worktree is locked (git worktree lock)
+	Prunable     bool   // worktree directory is gone; removed by git worktree prune
 }
 
 // IsAllProjects returns true if this is the "All Projects" item.
@@ -65,6 +74,9 @@ func (t TreeItem) IsContainer() bool { return t.Type == TreeItemContainer }
 // IsSession returns true if this is a session item.
 func (t TreeItem) IsSession() bool { return t.Type == TreeItemSession }
 
+// IsWindow returns true if this is a tmux window item.
+func (t TreeItem) IsWindow() bool { return t.Type == TreeItemWindow }
+
 // StatusLevel represents the current status type for the status bar.
 type StatusLevel int
 
@@ -110,32 +122,68 @@ type Model struct {
 	cfg                *config.Config
 	templates          []config.Template
 	discoveredProjects []discovery.DiscoveredProject
+	scanner            *discovery.Scanner // shared across rescans so its cache persists
+	projectWatch       bool               // a discovery.Watcher pushes project changes; skip tick rescans
+	refreshPaused      bool               // z: tickMsg schedules no refresh and no next tick
+	tickGen            int                // bumped on resume; tickMsgs from older chains are dropped
 	manager            *container.Manager
+	audit              *audit.Log // lifecycle actions started from the TUI; nil records nothing
 	containerList      list.Model
 	containerDelegate  containerDelegate
 
 	// Form state for container creation
 	formOpen          bool
 	formTemplateIdx   int
+	formTemplateQuery string // template field filter: name substring typed while it is focused
 	formProjectPath   string
 	formContainerName string
+	formNameEdited    bool   // user typed a name; stop generating it from the template
+	formMounts        string // extra --mount strings, comma or newline separated
+	formClone         bool   // clone variant: formProjectPath holds a git URL to clone
 	formFocusedField  FormField
 	formError         string
 
 	// Form submission progress state
-	formSubmitting     bool
-	formTitlePulse     int // cycles 0-3 for pulsing effect
-	formStatusSpinner  spinner.Model
-	formStatusSteps    []FormStatusStep
-	formCurrentStep    string
-	formCompleted      bool // true when submission finished (success or error)
-	formCompletedError bool // true if submission ended with error
+	formSubmitting       bool
+	formTitlePulse       int // cycles 0-3 for pulsing effect
+	formStatusSpinner    spinner.Model
+	formStatusSteps      []FormStatusStep
+	formCurrentStep      string
+	formCompleted        bool               // true when submission finished (success or error)
+	formCompletedError   bool               // true if submission ended with error
+	formCancel           context.CancelFunc // cancels the in-flight create; nil when none
+	formExists           bool               // create refused because the project already has a container
+	formForce            bool               // create even if the project already has a container
+	formKeepDevcontainer bool               // keep the project's own devcontainer.json instead of replacing it
+	formPreviewOpen      bool               // ctrl+p: showing what the create would generate
+	formPreview          viewport.Model     // scrollable preview content
 
 	// Worktree creation form state
-	worktreeFormOpen    bool
-	worktreeFormName    string
-	worktreeFormProject *discovery.DiscoveredProject
-	worktreeFormError   string
+	worktreeFormOpen        bool
+	worktreeFormName        string
+	worktreeFormBase        string // optional base ref; empty branches from HEAD
+	worktreeFormBaseFocused bool   // tab toggles focus between name and base
+	worktreeFormProject     *discovery.DiscoveredProject
+	worktreeFormError       string
+	worktreeFormVerifying   bool // the base ref is being resolved (worktreeBaseVerifiedMsg)
+
+	// Allowlist editor state ("a" in an isolated container's detail view)
+	allowlistEditorOpen bool
+	allowlistContainer  *container.Container
+	allowlistDomains    []string
+	allowlistSelected   int    // index into allowlistDomains
+	allowlistInput      string // domain being typed
+	allowlistError      string
+
+	// Note editor state ("N" on a container)
+	noteEditorOpen bool
+	noteContainer  *container.Container
+	noteInput      string
+	noteError      string
+
+	// Session layout being duplicated ("D" marks the source, then "D" on a
+	// target or "A" for all running containers)
+	layoutSource *container.Container
 
 	// Session view state
 	sessionViewOpen    bool
@@ -148,6 +196,9 @@ type Model struct {
 
 	// Action menu state - shows commands for the selected container
 	actionMenuOpen bool
+	actionMenuIdx  int // highlighted action; y copies its command
+
+	vscodeAvailable bool // the code CLI is on PATH; gates launching VS Code
 
 	// Session created confirmation state
 	sessionCreatedOpen bool
@@ -158,6 +209,8 @@ type Model struct {
 	confirmAction  string // "destroy_container", "kill_session"
 	confirmTarget  string // container ID or session name
 	confirmMessage string // message to display
+	confirmName    string // when set, confirming requires typing this name
+	confirmInput   string // name typed so far
 
 	// Log panel
 	logPanelOpen bool
@@ -166,10 +219,26 @@ type Model struct {
 	treeItems          []TreeItem
 	selectedIdx        int
 	expandedContainers map[string]bool
-	expandedProjects   map[string]bool // projectPath -> expanded
+	expandedProjects   map[string]bool          // projectPath -> expanded
+	expandedSessions   map[string]bool          // sessionKey -> expanded
+	sessionWindows     map[string][]tmux.Window // sessionKey -> windows, loaded on expand
 	detailPanelOpen    bool
 	panelFocus         PanelFocus
 
+	// Tree filter state ("/" opens the input; matches container name or project path)
+	treeFilterOpen bool   // filter input is capturing keystrokes
+	treeFilter     string // case-insensitive substring; empty = no filter
+
+	// Tree sort key ("o" cycles name/state/created)
+	treeSort container.SortKey
+
+	// Persisted state awaiting the first container refresh (see RestoreUIState)
+	pendingRestore *UIState
+
+	// startup_view: detail selects the first running container on the first
+	// container refresh
+	startupDetailPending bool
+
 	// Detail panel viewport for scrolling
 	detailViewport viewport.Model
 	detailReady    bool   // viewport initialized
@@ -177,6 +246,7 @@ type Model struct {
 
 	// Cached isolation info for selected container (avoids blocking View())
 	cachedIsolationInfo *container.IsolationInfo
+	cachedPorts         []container.PortMapping // published ports, fetched with the isolation info
 
 	// Progress channel for container creation (owned by Model, not package-level)
 	formProgressChan chan formProgressMsg
@@ -186,9 +256,6 @@ type Model struct {
 	statusLevel   StatusLevel
 	statusSpinner spinner.Model
 
-	// Pending operations (containerID -> operation type)
-	pendingOperations map[string]string
-
 	// Pending worktree operations (worktree path -> operation type)
 	pendingWorktrees map[string]string
 
@@ -200,18 +267,28 @@ type Model struct {
 	logFilterLabel string
 	logLevelFilter map[string]bool // Enabled log levels: "DEBUG", "INFO", "WARN", "ERROR"
 	logAutoScroll  bool
-	logReady       bool // viewport initialized
+	logSearchOpen  bool   // search input is capturing keystrokes
+	logSearch      string // case-insensitive substring of message or scope; empty = no search
+	logReady       bool   // viewport initialized
+	logsDropped    uint64 // entries the log channel dropped because consumption fell behind
 	logManager     *logging.Manager
 	logger         *logging.ScopedLogger
 
+	// Followed container output shown in the log panel (see containerlogs.go)
+	containerTail containerLogTail
+
 	// Log details panel
 	logDetailsOpen     bool // whether the log details panel is shown
 	selectedLogIndex   int  // index into filteredLogEntries()
 	logDetailsViewport viewport.Model
 	logDetailsReady    bool // viewport initialized
+	logDetailsWrap     bool // w: soft-wrap long lines to the viewport width instead of scrolling horizontally
 
 	// listenURLs holds the URLs the service is listening on, for display in the header.
 	listenURLs []string
+	// tailscaleStatus is the tsnsrv supervisor state from TailscaleStatusMsg;
+	// the header flags it unless it is empty or "running".
+	tailscaleStatus string
 
 	// Quit tracking
 	lastCtrlCTime time.Time // for double ctrl+c detection
@@ -232,6 +309,20 @@ func (m *Model) SetDiscoveredProjects(projects []discovery.DiscoveredProject) {
 	m.discoveredProjects = projects
 }
 
+// SetProjectWatch records that a discovery.Watcher delivers project changes
+// (see ProjectsRefreshed), so the periodic tick stops rescanning scan paths.
+// Called before the Bubbletea program starts.
+func (m *Model) SetProjectWatch(watching bool) {
+	m.projectWatch = watching
+}
+
+// SetAuditLog sets the audit log that lifecycle actions started from the TUI
+// are recorded in (as audit.SourceTUI). Called before the Bubbletea program
+// starts.
+func (m *Model) SetAuditLog(l *audit.Log) {
+	m.audit = l
+}
+
 // NewModelWithTemplates creates a new TUI model with explicit templates (for testing).
 func NewModelWithTemplates(cfg *config.Config, templates []config.Template, logManager *logging.Manager) Model {
 	// Create container manager with logger
@@ -260,7 +351,14 @@ func NewModelWithTemplates(cfg *config.Config, templates []config.Template, logM
 	logger := logManager.For("tui")
 	logger.Debug("TUI model initialized")
 
+	scanner := discovery.NewScanner()
+	scanner.MaxDepth = cfg.ScanMaxDepth
+
+	_, err := exec.LookPath("code")
+	vscodeAvailable := err == nil
+
 	m := Model{
+		scanner:           scanner,
 		themeName:         cfg.Theme,
 		styles:            styles,
 		cfg:               cfg,
@@ -269,14 +367,16 @@ func NewModelWithTemplates(cfg *config.Config, templates []config.Template, logM
 		containerList:     containerList,
 		containerDelegate: delegate,
 		statusSpinner:     s,
-		pendingOperations: make(map[string]string),
 		expandedProjects:  make(map[string]bool),
+		treeSort:          container.SortByName,
 		logEntries:        make([]logging.LogEntry, 0, maxLogEntries),
 		logLevelFilter:    map[string]bool{"DEBUG": true, "INFO": true, "WARN": true, "ERROR": true},
 		logAutoScroll:     true,
 		logManager:        logManager,
 		logger:            logger,
+		vscodeAvailable:   vscodeAvailable,
 	}
+	m.applyStartupView(cfg.StartupView)
 	return m
 }
 
@@ -290,7 +390,18 @@ func (m Model) Init() tea.Cmd {
 }
 
 // refreshContainers returns a command to refresh the container list.
+// Failures are reported as errors.
 func (m Model) refreshContainers() tea.Cmd {
+	return m.refreshContainersWith(false)
+}
+
+// refreshContainersInBackground is the periodic variant of refreshContainers:
+// on failure the cached container list is kept and only a subtle status is shown.
+func (m Model) refreshContainersInBackground() tea.Cmd {
+	return m.refreshContainersWith(true)
+}
+
+func (m Model) refreshContainersWith(background bool) tea.Cmd {
 	return func() tea.Msg {
 		m.logger.Debug("refreshing containers")
 
@@ -299,7 +410,7 @@ func (m Model) refreshContainers() tea.Cmd {
 
 		if err := m.manager.Refresh(ctx); err != nil {
 			m.logger.Error("container refresh failed", "error", err)
-			return containerErrorMsg{err: err}
+			return containerErrorMsg{err: err, background: background}
 		}
 
 		containers := m.manager.List()
@@ -308,13 +419,60 @@ func (m Model) refreshContainers() tea.Cmd {
 	}
 }
 
-// tick returns a command for periodic refresh.
+// tick returns a command for periodic refresh, every cfg.RefreshInterval.
 func (m Model) tick() tea.Cmd {
-	return tea.Tick(10*time.Second, func(t time.Time) tea.Msg {
-		return tickMsg{time: t}
+	interval := m.cfg.RefreshInterval
+	if interval <= 0 {
+		interval = config.DefaultRefreshInterval
+	}
+	gen := m.tickGen
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return tickMsg{time: t, gen: gen}
 	})
 }
 
+// toggleRefreshPaused pauses or resumes periodic refresh. Pausing lets the
+// pending tick lapse; resuming refreshes immediately and starts a new tick
+// chain, so a tick still pending from before the pause is dropped as stale.
+func (m *Model) toggleRefreshPaused() tea.Cmd {
+	m.refreshPaused = !m.refreshPaused
+	if m.refreshPaused {
+		m.logger.Info("periodic refresh paused")
+		m.setSuccess("Periodic refresh paused (z to resume)")
+		return nil
+	}
+	m.logger.Info("periodic refresh resumed")
+	m.setSuccess("Periodic refresh resumed")
+	m.tickGen++
+	return tea.Batch(
+		m.refreshContainersInBackground(),
+		m.refreshAllSessions(),
+		m.tick(),
+	)
+}
+
+// applyTheme switches the TUI to the named Catppuccin flavor, rebuilding the
+// styles and everything that captured colors from them.
+func (m *Model) applyTheme(name string) {
+	m.themeName = name
+	m.styles = NewStyles(name)
+	m.containerDelegate = newContainerDelegate(m.styles)
+	m.containerList.SetDelegate(m.containerDelegate)
+	m.statusSpinner.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(m.styles.flavor.Teal().Hex))
+}
+
+// cycleTheme switches to the theme after the current one in config.Themes.
+// The choice is persisted in the UI state (see UIState), not the config file.
+func (m *Model) cycleTheme() {
+	next := config.Themes[0]
+	if i := slices.Index(config.Themes, m.themeName); i >= 0 {
+		next = config.Themes[(i+1)%len(config.Themes)]
+	}
+	m.applyTheme(next)
+	m.logger.Info("theme changed", "theme", next)
+	m.setSuccess("Theme: " + next)
+}
+
 // sessionsRefreshedMsg is sent when session list is updated.
 type sessionsRefreshedMsg struct {
 	containerID string
@@ -350,6 +508,37 @@ type allSessionsRefreshedMsg struct {
 	sessionsByContainer map[string][]tmux.Session
 }
 
+// windowsLoadedMsg carries the windows of one tmux session.
+type windowsLoadedMsg struct {
+	containerID string
+	sessionName string
+	windows     []tmux.Window
+	err         error
+}
+
+// fetchWindows returns a command to list the windows of a tmux session.
+func (m Model) fetchWindows(containerID, sessionName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		windows, err := m.manager.ListWindows(ctx, containerID, sessionName)
+		return windowsLoadedMsg{containerID: containerID, sessionName: sessionName, windows: windows, err: err}
+	}
+}
+
+// refreshExpandedWindows returns a command reloading the windows of every
+// expanded session, so the tree follows windows opened or closed in tmux.
+func (m Model) refreshExpandedWindows() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, item := range m.treeItems {
+		if item.Type == TreeItemSession && item.Expanded {
+			cmds = append(cmds, m.fetchWindows(item.ContainerID, item.SessionName))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
 // refreshAllSessions returns a command to refresh sessions for all running containers.
 func (m Model) refreshAllSessions() tea.Cmd {
 	var runningIDs []string
@@ -366,21 +555,56 @@ func (m Model) refreshAllSessions() tea.Cmd {
 	}
 
 	return func() tea.Msg {
-		result := make(map[string][]tmux.Session)
-		for _, id := range runningIDs {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			sessions, err := m.manager.ListSessions(ctx, id)
-			cancel()
-			if err != nil {
+		result := fetchSessions(runningIDs, m.manager.ListSessions, sessionFetchWorkers, sessionFetchTimeout,
+			func(id string, err error) {
 				m.logger.Error("session refresh failed", "containerID", id, "error", err)
-				continue
-			}
-			result[id] = sessions
-		}
+			})
 		return allSessionsRefreshedMsg{sessionsByContainer: result}
 	}
 }
 
+// Session refresh fans out one ListSessions (a tmux exec round-trip) per
+// running container, at most sessionFetchWorkers at a time.
+const (
+	sessionFetchWorkers = 8
+	sessionFetchTimeout = 5 * time.Second
+)
+
+// fetchSessions lists sessions for each container ID using a bounded pool of
+// workers, each call with its own timeout. Failed containers are reported via
+// onErr and omitted from the result.
+func fetchSessions(ids []string, list func(context.Context, string) ([]tmux.Session, error), workers int, timeout time.Duration, onErr func(id string, err error)) map[string][]tmux.Session {
+	result := make(map[string][]tmux.Session, len(ids))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := make(chan string)
+	for range min(workers, len(ids)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				sessions, err := list(ctx, id)
+				cancel()
+				mu.Lock()
+				if err != nil {
+					onErr(id, err)
+				} else {
+					result[id] = sessions
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+	return result
+}
+
 // ContainerCount returns the number of containers in the list.
 // This is an accessor for E2E testing.
 func (m Model) ContainerCount() int {
@@ -443,14 +667,43 @@ func (m Model) AttachCommand() string {
 		return ""
 	}
 	// Use manager's runtime path to bypass shell aliases (e.g., alias docker=podman)
-	runtimePath := m.manager.RuntimePath()
-	// Get the remote user for exec (defaults to vscode)
-	user := m.selectedContainer.RemoteUser
-	if user == "" {
-		user = container.DefaultRemoteUser
+	return GenerateAttachCommand(m.selectedContainer, session.Name, m.manager.RuntimePath(), m.attachCommandTemplate())
+}
+
+// attachCommandTemplate returns the configured attach_command_template.
+func (m Model) attachCommandTemplate() string {
+	if m.cfg == nil {
+		return ""
 	}
-	// Use container name instead of ID since docker ps returns truncated IDs
-	return fmt.Sprintf("%s exec -it -u %s %s tmux attach -t %s", runtimePath, user, m.selectedContainer.Name, session.Name)
+	return m.cfg.AttachCommandTemplate
+}
+
+// projectItemContainers returns the containers grouped under a project tree item:
+// the project's main and worktree containers, or the unmatched containers for
+// the "Other" group (empty ProjectPath). Ordered by the tree sort key.
+func (m *Model) projectItemContainers(item TreeItem) []*container.Container {
+	if item.ProjectPath != "" {
+		for _, project := range m.discoveredProjects {
+			if project.Path == item.ProjectPath {
+				return m.findContainersForProject(project)
+			}
+		}
+		return nil
+	}
+
+	matched := make(map[string]bool)
+	for _, project := range m.discoveredProjects {
+		for _, c := range m.findContainersForProject(project) {
+			matched[c.ID] = true
+		}
+	}
+	var result []*container.Container
+	for _, c := range m.sortedContainers() {
+		if !matched[c.ID] {
+			result = append(result, c)
+		}
+	}
+	return result
 }
 
 // closeSessionView closes the session view.
@@ -472,6 +725,41 @@ func (m Model) IsActionMenuOpen() bool {
 // closeActionMenu closes the action menu.
 func (m *Model) closeActionMenu() {
 	m.actionMenuOpen = false
+	m.actionMenuIdx = 0
+}
+
+// containerActions returns the action menu entries for the selected container.
+// Without the code CLI on PATH, VS Code entries stay copy-only.
+func (m Model) containerActions() []ActionCommand {
+	actions := GenerateContainerActions(m.selectedContainer, m.manager.RuntimePath(), m.cachedPorts)
+	if !m.vscodeAvailable {
+		for i := range actions {
+			if len(actions[i].Args) > 0 && actions[i].Args[0] == "code" {
+				actions[i].Args = nil
+			}
+		}
+	}
+	return actions
+}
+
+// selectedActionCommand returns the command of the highlighted action menu
+// entry, or "" when there is none.
+func (m Model) selectedActionCommand() string {
+	actions := m.containerActions()
+	if m.actionMenuIdx < 0 || m.actionMenuIdx >= len(actions) {
+		return ""
+	}
+	return actions[m.actionMenuIdx].Command
+}
+
+// selectedActionArgs returns the argv of the highlighted action menu entry,
+// or nil when it cannot be run directly.
+func (m Model) selectedActionArgs() []string {
+	actions := m.containerActions()
+	if m.actionMenuIdx < 0 || m.actionMenuIdx >= len(actions) {
+		return nil
+	}
+	return actions[m.actionMenuIdx].Args
 }
 
 // IsSessionFormOpen returns whether the session creation form is open.
@@ -524,28 +812,26 @@ func (m *Model) clearStatus() {
 	m.err = nil
 }
 
-// setPending marks a container as having a pending operation.
+// setPending marks a container as having a pending operation. Pending
+// operations live in the manager's tracker, so operations started from the
+// web UI show as pending here too.
 func (m *Model) setPending(containerID, operation string) {
-	if m.pendingOperations == nil {
-		m.pendingOperations = make(map[string]string)
-	}
-	m.pendingOperations[containerID] = operation
+	m.manager.Operations().Begin(containerID, operation)
 }
 
 // clearPending removes a container from pending operations.
 func (m *Model) clearPending(containerID string) {
-	delete(m.pendingOperations, containerID)
+	m.manager.Operations().End(containerID)
 }
 
 // isPending returns true if the container has a pending operation.
 func (m Model) isPending(containerID string) bool {
-	_, ok := m.pendingOperations[containerID]
-	return ok
+	return m.getPendingOperation(containerID) != ""
 }
 
 // getPendingOperation returns the pending operation type for a container.
 func (m Model) getPendingOperation(containerID string) string {
-	return m.pendingOperations[containerID]
+	return m.manager.Operations().Action(containerID)
 }
 
 // setPendingWorktree marks a worktree as having a pending operation.
@@ -579,12 +865,13 @@ func (m *Model) addLogEntry(entry logging.LogEntry) {
 	}
 }
 
-// filteredLogEntries returns entries matching the current scope and level filters.
+// filteredLogEntries returns entries matching the current scope and level filters
+// and the log search, if any.
 // When a container is selected, matches both container.<name> and proxy.<name> scopes.
 // When a project or worktree is selected, matches logs for all containers in that scope.
 func (m Model) filteredLogEntries() []logging.LogEntry {
 	hasLevelFilter := m.logLevelFilter != nil
-	if m.logFilter == "" && !hasLevelFilter {
+	if m.logFilter == "" && !hasLevelFilter && m.logSearch == "" {
 		return m.logEntries
 	}
 
@@ -607,11 +894,57 @@ func (m Model) filteredLogEntries() []logging.LogEntry {
 		if hasLevelFilter && entry.Level != "" && !m.logLevelFilter[entry.Level] {
 			continue
 		}
+		if !m.logEntryMatchesSearch(entry) {
+			continue
+		}
 		filtered = append(filtered, entry)
 	}
 	return filtered
 }
 
+// logEntryMatchesSearch reports whether the entry's message or scope contains
+// the log search (case-insensitive). Every entry matches when no search is set.
+func (m Model) logEntryMatchesSearch(entry logging.LogEntry) bool {
+	if m.logSearch == "" {
+		return true
+	}
+	needle := strings.ToLower(m.logSearch)
+	return strings.Contains(strings.ToLower(entry.Message), needle) ||
+		strings.Contains(strings.ToLower(entry.Scope), needle)
+}
+
+// setLogSearch applies a new log search, selecting the newest match. Clearing
+// the search resumes auto-scroll; an active search suspends it.
+func (m *Model) setLogSearch(search string) {
+	m.logSearch = search
+	m.logAutoScroll = search == ""
+	entries := m.filteredLogEntries()
+	if len(entries) > 0 {
+		m.selectedLogIndex = len(entries) - 1
+	} else {
+		m.selectedLogIndex = 0
+	}
+	m.updateLogViewportContent()
+}
+
+// cycleLogMatch moves the log selection to the next (delta 1) or previous
+// (delta -1) search match, wrapping around. With a search active every
+// filtered entry is a match.
+func (m *Model) cycleLogMatch(delta int) {
+	entries := m.filteredLogEntries()
+	if len(entries) == 0 {
+		return
+	}
+	m.selectedLogIndex = (m.selectedLogIndex + delta + len(entries)) % len(entries)
+	m.logAutoScroll = false
+}
+
+// logFollowing reports whether the log panel should follow new entries:
+// auto-scroll is on and no search is active.
+func (m Model) logFollowing() bool {
+	return m.logAutoScroll && m.logSearch == ""
+}
+
 // toggleLogLevel flips the enabled state for the given log level and resets the selected index.
 func (m *Model) toggleLogLevel(level string) {
 	if m.logLevelFilter == nil {
@@ -655,13 +988,11 @@ func (m *Model) setLogFilterFromContext() {
 			if len(names) == 0 {
 				// No registered container yet (e.g. build in-progress or failed).
 				// Predict the compose name so build/error logs are still visible.
-				var baseName string
-				if item.WorktreeName == "main" {
-					baseName = filepath.Base(item.ProjectPath)
-				} else {
-					baseName = filepath.Base(filepath.Dir(filepath.Dir(item.ProjectPath))) + "-" + item.WorktreeName
+				composeName := container.SanitizeComposeName(filepath.Base(item.ProjectPath))
+				if item.WorktreeName != "main" {
+					composeName = worktree.ComposeName(worktree.ProjectPathFromDir(item.ProjectPath, item.WorktreeName), item.WorktreeName)
 				}
-				names = map[string]bool{container.SanitizeComposeName(baseName): true}
+				names = map[string]bool{composeName: true}
 			}
 			m.logFilter = "scope"
 			m.logFilterNames = names
@@ -726,9 +1057,11 @@ func (m *Model) containerNamesForProject(projectPath string) map[string]bool {
 }
 
 // consumeLogEntries reads entries from the log manager channel.
-// Call this to start/continue log consumption.
+// Call this to start/continue log consumption. Each batch also reports the
+// channel's dropped-entry count.
 func (m Model) consumeLogEntries(logMgr interface {
 	Entries() <-chan logging.LogEntry
+	Dropped() uint64
 }) tea.Cmd {
 	return func() tea.Msg {
 		ch := logMgr.Entries()
@@ -747,15 +1080,15 @@ func (m Model) consumeLogEntries(logMgr interface {
 			case entry, ok := <-ch:
 				if !ok {
 					// Channel closed
-					return logEntriesMsg{entries: entries}
+					return logEntriesMsg{entries: entries, dropped: logMgr.Dropped()}
 				}
 				entries = append(entries, entry)
 			default:
 				// No more entries ready
-				return logEntriesMsg{entries: entries}
+				return logEntriesMsg{entries: entries, dropped: logMgr.Dropped()}
 			}
 		}
-		return logEntriesMsg{entries: entries}
+		return logEntriesMsg{entries: entries, dropped: logMgr.Dropped()}
 	}
 }
 
@@ -772,11 +1105,29 @@ func (m *Model) updateLogViewportContent() {
 	content := strings.Join(lines, "\n")
 	m.logViewport.SetContent(content)
 
-	if m.logAutoScroll {
+	if m.logFollowing() {
 		m.logViewport.GotoBottom()
 	}
 }
 
+// setLogPanelOpen shows or hides the log panel, initializing its viewport on
+// first open and resizing the container list for the split layout.
+func (m *Model) setLogPanelOpen(open bool) {
+	m.logPanelOpen = open
+	if !m.logPanelOpen && m.panelFocus == FocusLogs {
+		m.panelFocus = FocusTree
+	}
+	layout := ComputeLayout(m.width, m.height, m.logPanelOpen, m.detailPanelOpen)
+	if m.logPanelOpen {
+		if !m.logReady {
+			m.logViewport = viewport.New(layout.Logs.Width, layout.Logs.Height-1)
+			m.logReady = true
+		}
+		m.updateLogViewportContent()
+	}
+	m.containerList.SetSize(m.width-4, layout.ContentListHeight())
+}
+
 // updateDetailViewportContent updates the detail viewport with the current detail content.
 // Skips the viewport SetContent call if the rendered content is unchanged,
 // avoiding visual jitter from unnecessary re-renders during periodic refreshes.
@@ -816,6 +1167,11 @@ func (m *Model) renderDetailContent() string {
 			return m.styles.InfoStyle().Render("Select an item to view details")
 		}
 		return m.renderSessionDetailContent()
+	case TreeItemWindow:
+		if m.selectedContainer == nil {
+			return m.styles.InfoStyle().Render("Select an item to view details")
+		}
+		return m.renderWindowDetailContent(item)
 	}
 	return ""
 }
@@ -829,27 +1185,11 @@ func (m *Model) rebuildTreeItems() {
 
 	// If no discovered projects, fall back to flat container list
 	if len(m.discoveredProjects) == 0 {
-		for _, item := range m.containerList.Items() {
-			ci, ok := item.(containerItem)
-			if !ok {
+		for _, c := range m.sortedContainers() {
+			if !m.containerMatchesFilter(c) {
 				continue
 			}
-			c := ci.container
-			expanded := m.expandedContainers[c.ID]
-			m.treeItems = append(m.treeItems, TreeItem{
-				Type:        TreeItemContainer,
-				ContainerID: c.ID,
-				Expanded:    expanded,
-			})
-			if expanded {
-				for _, session := range c.Sessions {
-					m.treeItems = append(m.treeItems, TreeItem{
-						Type:        TreeItemSession,
-						ContainerID: c.ID,
-						SessionName: session.Name,
-					})
-				}
-			}
+			m.addContainerTreeItems(c)
 		}
 		return
 	}
@@ -859,6 +1199,18 @@ func (m *Model) rebuildTreeItems() {
 
 	// Build project groups
 	for _, project := range m.discoveredProjects {
+		// Always mark containers as matched regardless of expansion state
+		projBase := filepath.Base(project.Path)
+		projectContainers := m.findContainersForProject(project)
+		for _, c := range projectContainers {
+			matchedContainers[c.ID] = true
+		}
+
+		// Projects with no containers matching the filter collapse out
+		if m.treeFilter != "" && len(m.filterContainers(projectContainers)) == 0 {
+			continue
+		}
+
 		expanded := m.expandedProjects[project.Path]
 		m.treeItems = append(m.treeItems, TreeItem{
 			Type:        TreeItemProject,
@@ -867,13 +1219,6 @@ func (m *Model) rebuildTreeItems() {
 			Expanded:    expanded,
 		})
 
-		// Always mark containers as matched regardless of expansion state
-		projBase := filepath.Base(project.Path)
-		projectContainers := m.findContainersForProject(project)
-		for _, c := range projectContainers {
-			matchedContainers[c.ID] = true
-		}
-
 		if !expanded {
 			continue
 		}
@@ -882,25 +1227,21 @@ func (m *Model) rebuildTreeItems() {
 		// First, add "main" (the project root itself)
 		mainCompose := container.SanitizeComposeName(projBase)
 		mainContainers := m.findContainersByCompose(mainCompose)
-		m.addWorktreeTreeItems("main", project.Path, mainContainers)
+		m.addWorktreeTreeItems(discovery.Worktree{Branch: "main", Path: project.Path}, mainContainers)
 
 		// Then add discovered worktrees
 		for _, wt := range project.Worktrees {
-			wtCompose := container.SanitizeComposeName(projBase + "-" + wt.Name)
+			wtCompose := worktree.ComposeName(project.Path, wt.Name)
 			wtContainers := m.findContainersByCompose(wtCompose)
-			m.addWorktreeTreeItems(wt.Branch, wt.Path, wtContainers)
+			m.addWorktreeTreeItems(wt, wtContainers)
 		}
 	}
 
 	// "Other" group for unmatched containers
 	var unmatched []*container.Container
-	for _, item := range m.containerList.Items() {
-		ci, ok := item.(containerItem)
-		if !ok {
-			continue
-		}
-		if !matchedContainers[ci.container.ID] {
-			unmatched = append(unmatched, ci.container)
+	for _, c := range m.sortedContainers() {
+		if !matchedContainers[c.ID] && m.containerMatchesFilter(c) {
+			unmatched = append(unmatched, c)
 		}
 	}
 
@@ -913,62 +1254,172 @@ func (m *Model) rebuildTreeItems() {
 
 		if m.expandedProjects["__other__"] {
 			for _, c := range unmatched {
-				expanded := m.expandedContainers[c.ID]
-				m.treeItems = append(m.treeItems, TreeItem{
-					Type:        TreeItemContainer,
-					ContainerID: c.ID,
-					Expanded:    expanded,
-				})
-				if expanded {
-					for _, session := range c.Sessions {
-						m.treeItems = append(m.treeItems, TreeItem{
-							Type:        TreeItemSession,
-							ContainerID: c.ID,
-							SessionName: session.Name,
-						})
-					}
-				}
+				m.addContainerTreeItems(c)
 			}
 		}
 	}
 }
 
-// addWorktreeTreeItems adds a worktree node and its containers/sessions to the tree.
-func (m *Model) addWorktreeTreeItems(name, path string, containers []*container.Container) {
+// addWorktreeTreeItems adds a worktree node (labelled by its branch) and its
+// containers/sessions to the tree.
+func (m *Model) addWorktreeTreeItems(wt discovery.Worktree, containers []*container.Container) {
+	// While filtering, only worktrees with matching containers are shown
+	if m.treeFilter != "" {
+		containers = m.filterContainers(containers)
+		if len(containers) == 0 {
+			return
+		}
+	}
+
 	m.treeItems = append(m.treeItems, TreeItem{
 		Type:         TreeItemWorktree,
-		ProjectPath:  path,
-		WorktreeName: name,
+		ProjectPath:  wt.Path,
+		WorktreeName: wt.Branch,
+		Locked:       wt.Locked,
+		Prunable:     wt.Prunable,
 	})
 
 	for _, c := range containers {
-		expanded := m.expandedContainers[c.ID]
+		m.addContainerTreeItems(c)
+	}
+}
+
+// addContainerTreeItems adds a container node to the tree, its sessions when
+// the container is expanded, and the loaded windows of each expanded session.
+func (m *Model) addContainerTreeItems(c *container.Container) {
+	expanded := m.expandedContainers[c.ID]
+	m.treeItems = append(m.treeItems, TreeItem{
+		Type:        TreeItemContainer,
+		ContainerID: c.ID,
+		Expanded:    expanded,
+	})
+	if !expanded {
+		return
+	}
+	for _, session := range c.Sessions {
+		key := sessionKey(c.ID, session.Name)
+		sessExpanded := m.expandedSessions[key]
 		m.treeItems = append(m.treeItems, TreeItem{
-			Type:        TreeItemContainer,
+			Type:        TreeItemSession,
 			ContainerID: c.ID,
-			Expanded:    expanded,
+			SessionName: session.Name,
+			Expanded:    sessExpanded,
 		})
-		if expanded {
-			for _, session := range c.Sessions {
-				m.treeItems = append(m.treeItems, TreeItem{
-					Type:        TreeItemSession,
-					ContainerID: c.ID,
-					SessionName: session.Name,
-				})
+		if !sessExpanded {
+			continue
+		}
+		for _, w := range m.sessionWindows[key] {
+			m.treeItems = append(m.treeItems, TreeItem{
+				Type:        TreeItemWindow,
+				ContainerID: c.ID,
+				SessionName: session.Name,
+				WindowIndex: w.Index,
+			})
+		}
+	}
+}
+
+// sessionKey identifies a session across containers in expandedSessions and
+// sessionWindows.
+func sessionKey(containerID, sessionName string) string {
+	return containerID + "/" + sessionName
+}
+
+// windowForItem returns the loaded tmux window a window item refers to, or
+// nil if it is no longer known.
+func (m *Model) windowForItem(item TreeItem) *tmux.Window {
+	windows := m.sessionWindows[sessionKey(item.ContainerID, item.SessionName)]
+	for i := range windows {
+		if windows[i].Index == item.WindowIndex {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// containerMatchesFilter reports whether a container's name or project path
+// contains the tree filter (case-insensitive). Always true with no filter.
+func (m *Model) containerMatchesFilter(c *container.Container) bool {
+	if m.treeFilter == "" {
+		return true
+	}
+	needle := strings.ToLower(m.treeFilter)
+	return strings.Contains(strings.ToLower(c.Name), needle) ||
+		strings.Contains(strings.ToLower(c.ProjectPath), needle)
+}
+
+// filterContainers returns the containers matching the tree filter.
+func (m *Model) filterContainers(containers []*container.Container) []*container.Container {
+	if m.treeFilter == "" {
+		return containers
+	}
+	var result []*container.Container
+	for _, c := range containers {
+		if m.containerMatchesFilter(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// filteredContainerCount returns the number of containers matching the tree filter.
+func (m *Model) filteredContainerCount() int {
+	count := 0
+	for _, item := range m.containerList.Items() {
+		if ci, ok := item.(containerItem); ok && m.containerMatchesFilter(ci.container) {
+			count++
+		}
+	}
+	return count
+}
+
+// setTreeFilter applies a new tree filter and rebuilds the tree, preserving selection.
+func (m *Model) setTreeFilter(filter string) {
+	m.treeFilter = filter
+	m.rebuildTreePreservingSelection()
+}
+
+// cycleTreeSort advances the tree sort key and rebuilds the tree, preserving selection.
+func (m *Model) cycleTreeSort() {
+	m.treeSort = m.treeSort.Next()
+	m.rebuildTreePreservingSelection()
+}
+
+// rebuildTreePreservingSelection rebuilds the tree and keeps the previously
+// selected item selected if it is still visible. Otherwise the selection snaps
+// to the nearest visible item.
+func (m *Model) rebuildTreePreservingSelection() {
+	var prev TreeItem
+	hadPrev := m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems)
+	if hadPrev {
+		prev = m.treeItems[m.selectedIdx]
+	}
+
+	m.rebuildTreeItems()
+
+	if hadPrev {
+		for i, item := range m.treeItems {
+			if item.Type == prev.Type && item.ContainerID == prev.ContainerID &&
+				item.SessionName == prev.SessionName && item.WindowIndex == prev.WindowIndex &&
+				item.ProjectPath == prev.ProjectPath &&
+				item.ProjectName == prev.ProjectName && item.WorktreeName == prev.WorktreeName {
+				m.selectedIdx = i
+				break
 			}
 		}
 	}
+	m.syncSelectionFromTree()
 }
 
 // findContainersForProject returns all containers that belong to a project
-// by matching compose project names (e.g., "myproject" for main, "myproject-feature" for worktrees).
+// by matching compose project names (e.g., "myproject" for main, "myproject-feature-<hash>" for worktrees, see worktree.ComposeName).
 func (m *Model) findContainersForProject(project discovery.DiscoveredProject) []*container.Container {
 	projBase := filepath.Base(project.Path)
 	var result []*container.Container
 	mainCompose := container.SanitizeComposeName(projBase)
 	result = append(result, m.findContainersByCompose(mainCompose)...)
 	for _, wt := range project.Worktrees {
-		wtCompose := container.SanitizeComposeName(projBase + "-" + wt.Name)
+		wtCompose := worktree.ComposeName(project.Path, wt.Name)
 		result = append(result, m.findContainersByCompose(wtCompose)...)
 	}
 	return result
@@ -990,26 +1441,50 @@ func (m *Model) findContainersForPath(path string) []*container.Container {
 	return result
 }
 
+// containerByID returns the listed container with the given ID, or nil.
+func (m *Model) containerByID(id string) *container.Container {
+	if id == "" {
+		return nil
+	}
+	for _, item := range m.containerList.Items() {
+		if ci, ok := item.(containerItem); ok && ci.container.ID == id {
+			return ci.container
+		}
+	}
+	return nil
+}
+
 // findContainersByCompose returns containers whose ComposeProject matches the given name.
 func (m *Model) findContainersByCompose(composeName string) []*container.Container {
 	var result []*container.Container
-	for _, item := range m.containerList.Items() {
-		ci, ok := item.(containerItem)
-		if !ok {
-			continue
+	for _, c := range m.sortedContainers() {
+		if c.ComposeProject == composeName {
+			result = append(result, c)
 		}
-		if ci.container.ComposeProject == composeName {
+	}
+	return result
+}
+
+// sortedContainers returns the listed containers ordered by the tree sort key.
+// The sort is stable with name/ID tie-breaks so the tree does not shuffle on refresh.
+func (m *Model) sortedContainers() []*container.Container {
+	items := m.containerList.Items()
+	result := make([]*container.Container, 0, len(items))
+	for _, item := range items {
+		if ci, ok := item.(containerItem); ok {
 			result = append(result, ci.container)
 		}
 	}
+	container.SortContainers(result, m.treeSort, false)
 	return result
 }
 
-// toggleTreeExpand toggles expansion of a container in the tree view.
-// If the selected item is a session, this does nothing.
-func (m *Model) toggleTreeExpand() {
+// toggleTreeExpand toggles expansion of a project, container or session in
+// the tree view. Expanding a session returns a command loading its windows;
+// otherwise the command is nil.
+func (m *Model) toggleTreeExpand() tea.Cmd {
 	if m.selectedIdx < 0 || m.selectedIdx >= len(m.treeItems) {
-		return
+		return nil
 	}
 	item := m.treeItems[m.selectedIdx]
 
@@ -1030,13 +1505,27 @@ func (m *Model) toggleTreeExpand() {
 		}
 		m.expandedContainers[item.ContainerID] = !m.expandedContainers[item.ContainerID]
 		m.rebuildTreeItems()
+	case TreeItemSession:
+		if m.expandedSessions == nil {
+			m.expandedSessions = make(map[string]bool)
+		}
+		key := sessionKey(item.ContainerID, item.SessionName)
+		m.expandedSessions[key] = !m.expandedSessions[key]
+		m.rebuildTreeItems()
+		if m.expandedSessions[key] {
+			return m.fetchWindows(item.ContainerID, item.SessionName)
+		}
 	}
+	return nil
 }
 
 // syncSelectionFromTree updates selectedContainer and selectedSessionIdx
 // based on the current tree selection (selectedIdx), and keeps the log
 // filter in sync so it always matches the active display scope.
 func (m *Model) syncSelectionFromTree() {
+	// Only the selected container's output is followed
+	defer m.stopContainerLogsUnlessSelected()
+
 	// Remember previous container ID to detect actual container changes
 	prevContainerID := ""
 	if m.selectedContainer != nil {
@@ -1046,12 +1535,18 @@ func (m *Model) syncSelectionFromTree() {
 	// Track previous session index to detect session changes
 	prevSessionIdx := m.selectedSessionIdx
 
+	// Snap to the nearest visible item when the tree shrank (e.g. filtering)
+	if m.selectedIdx >= len(m.treeItems) && len(m.treeItems) > 0 {
+		m.selectedIdx = len(m.treeItems) - 1
+	}
+
 	if m.selectedIdx < 0 || m.selectedIdx >= len(m.treeItems) {
 		m.selectedContainer = nil
 		m.selectedSessionIdx = 0
 		// Clear cache only if container changed
 		if prevContainerID != "" {
 			m.cachedIsolationInfo = nil
+			m.cachedPorts = nil
 		}
 		m.setLogFilterFromContext()
 		m.refreshDetailViewport()
@@ -1069,6 +1564,7 @@ func (m *Model) syncSelectionFromTree() {
 		// Clear cache only if container changed
 		if prevContainerID != "" {
 			m.cachedIsolationInfo = nil
+			m.cachedPorts = nil
 		}
 		m.setLogFilterFromContext()
 		m.refreshDetailViewport()
@@ -1088,10 +1584,11 @@ func (m *Model) syncSelectionFromTree() {
 				// Clear cache only if container changed
 				if containerChanged {
 					m.cachedIsolationInfo = nil
+					m.cachedPorts = nil
 				}
 
-				// If it's a session, find the session index
-				if item.Type == TreeItemSession {
+				// If it's a session or one of its windows, find the session index
+				if item.Type == TreeItemSession || item.Type == TreeItemWindow {
 					for i, sess := range ci.container.Sessions {
 						if sess.Name == item.SessionName {
 							m.selectedSessionIdx = i
@@ -1181,10 +1678,35 @@ func (m *Model) updateLogDetailsContent() {
 
 	entry := entries[m.selectedLogIndex]
 	content := m.renderLogEntryDetails(entry)
+	if m.logDetailsWrap {
+		content = ansi.Wrap(content, m.logDetailsViewport.Width, "")
+	}
 	m.logDetailsViewport.SetContent(content)
 	m.logDetailsViewport.GotoTop()
 }
 
+// logDetailsHorizontalStep is how many columns left/right scroll the
+// unwrapped log details.
+const logDetailsHorizontalStep = 8
+
+// toggleLogDetailsWrap switches the log details between soft-wrapped lines
+// and horizontally scrollable unwrapped lines.
+func (m *Model) toggleLogDetailsWrap() {
+	m.logDetailsWrap = !m.logDetailsWrap
+	m.logDetailsViewport.SetXOffset(0)
+	m.updateLogDetailsContent()
+}
+
+// selectedLogDetailsText returns the selected log entry as rendered in the
+// details panel, without styling, or "" when no entry is selected.
+func (m Model) selectedLogDetailsText() string {
+	entries := m.filteredLogEntries()
+	if m.selectedLogIndex < 0 || m.selectedLogIndex >= len(entries) {
+		return ""
+	}
+	return ansi.Strip(m.renderLogEntryDetails(entries[m.selectedLogIndex]))
+}
+
 // closeLogDetailsPanel closes the log details panel and returns focus to log list.
 func (m *Model) closeLogDetailsPanel() {
 	m.logDetailsOpen = false
@@ -1233,6 +1755,84 @@ func (m *Model) moveTreeSelectionDown() {
 	}
 }
 
+// jumpToContainer moves the selection to the next (or, with !forward, the
+// previous) visible container whose running state matches running, skipping
+// projects, worktrees, and sessions. With wrap, the search continues from the
+// other end of the tree. Returns false, leaving the selection unchanged, if no
+// other container matches.
+func (m *Model) jumpToContainer(running, forward, wrap bool) bool {
+	n := len(m.treeItems)
+	step := 1
+	if !forward {
+		step = -1
+	}
+	for i, idx := 1, m.selectedIdx+step; i < n; i, idx = i+1, idx+step {
+		if idx < 0 || idx >= n {
+			if !wrap {
+				return false
+			}
+			idx = (idx + n) % n
+		}
+		item := m.treeItems[idx]
+		if item.Type != TreeItemContainer {
+			continue
+		}
+		if c := m.containerByID(item.ContainerID); c != nil && c.IsRunning() == running {
+			m.selectedIdx = idx
+			m.syncSelectionFromTree()
+			return true
+		}
+	}
+	return false
+}
+
+// applyStartupView opens the panels configured by startup_view. The detail
+// view's container is selected on the first container refresh.
+func (m *Model) applyStartupView(view string) {
+	switch view {
+	case config.StartupViewLogs:
+		m.logPanelOpen = true
+	case config.StartupViewDetail:
+		m.detailPanelOpen = true
+		m.startupDetailPending = true
+	}
+}
+
+// selectFirstRunningContainer expands the project (or "Other" group) holding
+// the first running container and selects that container in the tree.
+// Returns false if no container is running.
+func (m *Model) selectFirstRunningContainer() bool {
+	var target *container.Container
+	for _, c := range m.sortedContainers() {
+		if c.IsRunning() {
+			target = c
+			break
+		}
+	}
+	if target == nil {
+		return false
+	}
+
+	group := "__other__"
+	for _, project := range m.discoveredProjects {
+		for _, c := range m.findContainersForProject(project) {
+			if c.ID == target.ID {
+				group = project.Path
+			}
+		}
+	}
+	m.expandedProjects[group] = true
+	m.rebuildTreeItems()
+
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemContainer && item.ContainerID == target.ID {
+			m.selectedIdx = i
+			return true
+		}
+	}
+	return false
+}
+
 // nextFocus returns the next panel focus, skipping panels that aren't open.
 func (m *Model) nextFocus() PanelFocus {
 	switch m.panelFocus {
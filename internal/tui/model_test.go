@@ -2,10 +2,16 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
 
+	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/logging"
 	"devagent/internal/tmux"
@@ -56,14 +62,14 @@ func TestModel_PendingOperations(t *testing.T) {
 	m := newTestModel(t)
 
 	// Initially empty
-	if len(m.pendingOperations) != 0 {
-		t.Error("pendingOperations should be empty initially")
+	if ops := m.manager.Operations().List(); len(ops) != 0 {
+		t.Errorf("pending operations = %v, want none initially", ops)
 	}
 
 	// Can add pending operation
 	m.setPending("abc123", "start")
-	if op, ok := m.pendingOperations["abc123"]; !ok || op != "start" {
-		t.Errorf("pendingOperations[abc123] = %q, want 'start'", op)
+	if op := m.getPendingOperation("abc123"); op != "start" {
+		t.Errorf("getPendingOperation(abc123) = %q, want 'start'", op)
 	}
 
 	// Can check if pending
@@ -604,3 +610,163 @@ func TestModel_FilteredLogEntries_LevelAndScopeFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestModel_LogSearch(t *testing.T) {
+	m := newTestModel(t)
+	m.logPanelOpen = true
+	m.logReady = true
+	m.panelFocus = FocusLogs
+
+	m.addLogEntry(logging.LogEntry{Level: "INFO", Message: "starting proxy", Scope: "app"})
+	m.addLogEntry(logging.LogEntry{Level: "INFO", Message: "unrelated", Scope: "app"})
+	m.addLogEntry(logging.LogEntry{Level: "DEBUG", Message: "ready", Scope: "proxy.myapp"})
+	m.addLogEntry(logging.LogEntry{Level: "INFO", Message: "Proxy restarted", Scope: "app"})
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !m.logSearchOpen {
+		t.Fatal("/ should open the log search input")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("proxy")})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+
+	entries := m.filteredLogEntries()
+	if len(entries) != 3 {
+		t.Fatalf("search %q matched %d entries, want 3 (message or scope, case-insensitive)", m.logSearch, len(entries))
+	}
+	if m.selectedLogIndex != 2 {
+		t.Errorf("selectedLogIndex = %d, want newest match (2)", m.selectedLogIndex)
+	}
+	if m.logFollowing() {
+		t.Error("auto-scroll should be suspended while a search is active")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m.selectedLogIndex != 0 {
+		t.Errorf("n from last match: selectedLogIndex = %d, want 0 (wrap)", m.selectedLogIndex)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	if m.selectedLogIndex != 2 {
+		t.Errorf("N from first match: selectedLogIndex = %d, want 2 (wrap)", m.selectedLogIndex)
+	}
+
+	// New entries do not move the selection while searching
+	updated, _ := m.Update(logEntriesMsg{entries: []logging.LogEntry{{Level: "INFO", Message: "proxy again", Scope: "app"}}})
+	m = updated.(Model)
+	if m.selectedLogIndex != 2 {
+		t.Errorf("selectedLogIndex moved to %d on new entry during search, want 2", m.selectedLogIndex)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.logSearch != "" {
+		t.Errorf("esc should clear the search, got %q", m.logSearch)
+	}
+	if m.panelFocus != FocusLogs {
+		t.Error("esc clearing the search should keep log focus")
+	}
+	if got := len(m.filteredLogEntries()); got != 5 {
+		t.Errorf("after clearing search got %d entries, want 5", got)
+	}
+	if !m.logFollowing() {
+		t.Error("clearing the search should resume auto-scroll")
+	}
+}
+
+func TestModel_LogsDropped(t *testing.T) {
+	m := newTestModel(t)
+	m.logPanelOpen = true
+	layout := ComputeLayout(120, 40, true, false)
+
+	if strings.Contains(m.renderLogPanel(layout), "logs dropped") {
+		t.Error("log header should not mention dropped logs before any are dropped")
+	}
+
+	updated, _ := m.Update(logEntriesMsg{entries: []logging.LogEntry{{Level: "INFO", Message: "after flood", Scope: "app"}}, dropped: 42})
+	m = updated.(Model)
+	if !strings.Contains(m.renderLogPanel(layout), "42 logs dropped") {
+		t.Errorf("log header = %q, want it to report 42 logs dropped", m.renderLogPanel(layout))
+	}
+
+	// A later batch never lowers the reported count
+	updated, _ = m.Update(logEntriesMsg{entries: nil, dropped: 0})
+	m = updated.(Model)
+	if m.logsDropped != 42 {
+		t.Errorf("logsDropped = %d, want 42", m.logsDropped)
+	}
+}
+
+func TestModel_RenderLogEntry_HighlightsSearch(t *testing.T) {
+	// Force colors so the highlight is visible in the rendered string
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+
+	m := newTestModel(t)
+	entry := logging.LogEntry{Level: "INFO", Message: "starting Proxy now", Scope: "app"}
+
+	plain := m.renderLogEntry(entry)
+	m.logSearch = "proxy"
+	highlighted := m.renderLogEntry(entry)
+
+	match := m.styles.LogMatchStyle().Render("Proxy")
+	if highlighted == plain || !strings.Contains(highlighted, match) {
+		t.Errorf("rendered entry %q should contain highlighted match %q", highlighted, match)
+	}
+	if ansi.Strip(highlighted) != ansi.Strip(plain) {
+		t.Errorf("highlighting changed the text: %q vs %q", ansi.Strip(highlighted), ansi.Strip(plain))
+	}
+}
+
+func TestStartupView_OpensConfiguredPanels(t *testing.T) {
+	tests := []struct {
+		view       string
+		wantLogs   bool
+		wantDetail bool
+	}{
+		{view: "", wantLogs: false, wantDetail: false},
+		{view: config.StartupViewTree, wantLogs: false, wantDetail: false},
+		{view: config.StartupViewLogs, wantLogs: true, wantDetail: false},
+		{view: config.StartupViewDetail, wantLogs: false, wantDetail: true},
+	}
+	for _, tt := range tests {
+		m := newTestModelWithConfig(t, &config.Config{Theme: "mocha", StartupView: tt.view})
+		if m.logPanelOpen != tt.wantLogs || m.detailPanelOpen != tt.wantDetail {
+			t.Errorf("startup_view %q: logPanelOpen=%v detailPanelOpen=%v, want %v %v",
+				tt.view, m.logPanelOpen, m.detailPanelOpen, tt.wantLogs, tt.wantDetail)
+		}
+	}
+}
+
+func TestStartupView_DetailSelectsFirstRunningContainer(t *testing.T) {
+	m := newTestModelWithConfig(t, &config.Config{Theme: "mocha", StartupView: config.StartupViewDetail})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	containers := []*container.Container{
+		{ID: "aaa", Name: "alpha", State: container.StateStopped},
+		{ID: "bbb", Name: "bravo", State: container.StateRunning},
+	}
+	updated, _ = m.Update(containersRefreshedMsg{containers: containers})
+	m = updated.(Model)
+
+	if m.selectedContainer == nil || m.selectedContainer.ID != "bbb" {
+		t.Fatalf("selectedContainer = %v, want first running container bbb", m.selectedContainer)
+	}
+	if !m.detailPanelOpen || !m.detailReady {
+		t.Error("detail panel should be open with its viewport initialized")
+	}
+
+	// Later refreshes keep the user's selection
+	m.selectedIdx = 0
+	m.syncSelectionFromTree()
+	updated, _ = m.Update(containersRefreshedMsg{containers: containers})
+	m = updated.(Model)
+	if m.selectedContainer != nil && m.selectedContainer.ID == "bbb" {
+		t.Error("startup selection should only apply on the first refresh")
+	}
+}
@@ -1,8 +1,13 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -204,6 +209,86 @@ func TestSessionView_AttachCommand(t *testing.T) {
 	}
 }
 
+func TestSessionView_PressY_CopiesAttachCommand(t *testing.T) {
+	m := newTestModelWithContainers(t)
+
+	containers := []*container.Container{
+		{
+			ID:       "abc123def456",
+			Name:     "test-container",
+			State:    container.StateRunning,
+			Sessions: []tmux.Session{{Name: "dev", ContainerID: "abc123def456"}},
+		},
+		{ID: "fed654cba321", Name: "empty-container", State: container.StateRunning},
+	}
+	updated, _ := m.Update(containersRefreshedMsg{containers: containers})
+	m = updated.(Model)
+
+	m.selectedContainer = containers[0]
+	m.sessionViewOpen = true
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Error("y in the session view should copy the attach command")
+	}
+
+	m.selectedContainer = containers[1]
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd != nil {
+		t.Error("y without a session should do nothing")
+	}
+}
+
+func TestSessionView_PressA_AttachesInline(t *testing.T) {
+	m := newTestModelWithContainers(t)
+
+	containers := []*container.Container{
+		{
+			ID:       "abc123def456",
+			Name:     "test-container",
+			State:    container.StateRunning,
+			Sessions: []tmux.Session{{Name: "dev", ContainerID: "abc123def456"}},
+		},
+		{
+			ID:       "fed654cba321",
+			Name:     "stopped-container",
+			State:    container.StateStopped,
+			Sessions: []tmux.Session{{Name: "dev", ContainerID: "fed654cba321"}},
+		},
+	}
+	updated, _ := m.Update(containersRefreshedMsg{containers: containers})
+	m = updated.(Model)
+	m.sessionViewOpen = true
+
+	m.selectedContainer = containers[0]
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}); cmd == nil {
+		t.Error("a in the session view should attach to the selected session")
+	}
+
+	m.selectedContainer = containers[1]
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if cmd != nil {
+		t.Error("a on a stopped container should not attach")
+	}
+	if got := updated.(Model); got.statusLevel != StatusError {
+		t.Errorf("statusLevel = %v, want StatusError", got.statusLevel)
+	}
+}
+
+func TestSessionAttachDone_ShowsError(t *testing.T) {
+	m := newTestModelWithContainers(t)
+	m.sessionViewOpen = true
+
+	updated, cmd := m.Update(sessionAttachDoneMsg{session: "dev", err: errors.New("exit status 1")})
+	got := updated.(Model)
+	if got.statusLevel != StatusError || !strings.Contains(got.statusMessage, "dev") {
+		t.Errorf("status = %v %q, want an error naming the session", got.statusLevel, got.statusMessage)
+	}
+	if !got.sessionViewOpen {
+		t.Error("session view should stay open after a failed attach")
+	}
+	if cmd == nil {
+		t.Error("returning from an attach should refresh containers")
+	}
+}
+
 func TestSessionView_PressT_OpensCreateSessionForm(t *testing.T) {
 	t.Skip("Session form 't' handler in Sessions tab is Phase 3, Task 4")
 }
@@ -219,3 +304,85 @@ func TestSessionForm_PressEscape_ClosesForm(t *testing.T) {
 func TestSessionView_PressK_ReturnsKillCommand(t *testing.T) {
 	t.Skip("Session kill 'k' handler in Sessions tab is Phase 3, Task 4")
 }
+
+// TestFetchSessions_Concurrent verifies that session listing for 50 containers
+// fans out across the worker pool (wall-clock well under the sequential cost),
+// never exceeds the worker bound, and keys each result by its own container.
+func TestFetchSessions_Concurrent(t *testing.T) {
+	const (
+		containers = 50
+		workers    = 8
+		delay      = 20 * time.Millisecond
+	)
+	ids := make([]string, containers)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("container-%02d", i)
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	list := func(ctx context.Context, id string) ([]tmux.Session, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		if id == "container-07" {
+			return nil, errors.New("exec failed")
+		}
+		return []tmux.Session{{ContainerID: id, Name: "dev-" + id}}, nil
+	}
+
+	var failed []string
+	start := time.Now()
+	result := fetchSessions(ids, list, workers, time.Second, func(id string, err error) {
+		failed = append(failed, id)
+	})
+	elapsed := time.Since(start)
+
+	sequential := containers * delay
+	if elapsed >= sequential/2 {
+		t.Errorf("fetchSessions took %v, want well under sequential %v", elapsed, sequential)
+	}
+	t.Logf("fetched %d containers in %v (sequential: %v)", containers, elapsed, sequential)
+
+	if got := maxInFlight.Load(); got > workers {
+		t.Errorf("max concurrent calls = %d, want <= %d", got, workers)
+	}
+	if len(failed) != 1 || failed[0] != "container-07" {
+		t.Errorf("failed = %v, want [container-07]", failed)
+	}
+	if len(result) != containers-1 {
+		t.Fatalf("len(result) = %d, want %d", len(result), containers-1)
+	}
+	for id, sessions := range result {
+		if len(sessions) != 1 || sessions[0].Name != "dev-"+id {
+			t.Errorf("result[%s] = %+v, want session dev-%s", id, sessions, id)
+		}
+	}
+}
+
+func TestFetchSessions_PerCallTimeout(t *testing.T) {
+	list := func(ctx context.Context, id string) ([]tmux.Session, error) {
+		if id == "hung" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return []tmux.Session{{Name: "main"}}, nil
+	}
+
+	var errs []error
+	result := fetchSessions([]string{"hung", "ok"}, list, 2, 10*time.Millisecond, func(id string, err error) {
+		errs = append(errs, err)
+	})
+
+	if len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("errors = %v, want one DeadlineExceeded", errs)
+	}
+	if _, ok := result["ok"]; !ok || len(result) != 1 {
+		t.Errorf("result = %v, want only ok", result)
+	}
+}
@@ -0,0 +1,190 @@
+// pattern: Imperative Shell
+
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"devagent/internal/config"
+)
+
+// stateFileName is the TUI state file, kept in the data dir next to the
+// instance lock and port files.
+const stateFileName = "tui-state.json"
+
+// StatePath returns the path of the TUI state file within dataDir.
+func StatePath(dataDir string) string {
+	return filepath.Join(dataDir, stateFileName)
+}
+
+// UIState is the tree expansion and selection state persisted across restarts.
+// Containers are keyed by project path rather than ID so the state survives
+// container rebuilds.
+type UIState struct {
+	ExpandedProjects   []string        `json:"expanded_projects,omitempty"`   // project paths ("__other__" for the Other group)
+	ExpandedContainers []string        `json:"expanded_containers,omitempty"` // container project paths
+	Selection          *SelectionState `json:"selection,omitempty"`
+	Theme              string          `json:"theme,omitempty"` // theme chosen with T; empty when it matches the config
+}
+
+// SelectionState identifies the selected tree item independently of container IDs.
+type SelectionState struct {
+	Type          TreeItemType `json:"type"`
+	ProjectPath   string       `json:"project_path,omitempty"`
+	ProjectName   string       `json:"project_name,omitempty"`
+	WorktreeName  string       `json:"worktree_name,omitempty"`
+	ContainerPath string       `json:"container_path,omitempty"` // project path of the selected container or session's container
+	SessionName   string       `json:"session_name,omitempty"`
+}
+
+// LoadState reads the TUI state file. A missing or corrupt file yields an
+// empty state so the TUI starts fresh.
+func LoadState(path string) UIState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return UIState{}
+	}
+	var s UIState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return UIState{}
+	}
+	return s
+}
+
+// SaveState writes the TUI state file atomically (temp file + rename).
+func SaveState(path string, s UIState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// UIState captures the current tree expansion and selection for persistence.
+// Expanded containers that are no longer listed are dropped.
+func (m Model) UIState() UIState {
+	var s UIState
+	for key, expanded := range m.expandedProjects {
+		if expanded {
+			s.ExpandedProjects = append(s.ExpandedProjects, key)
+		}
+	}
+	seen := make(map[string]bool)
+	for id, expanded := range m.expandedContainers {
+		if !expanded {
+			continue
+		}
+		if c := m.containerByID(id); c != nil && c.ProjectPath != "" && !seen[c.ProjectPath] {
+			seen[c.ProjectPath] = true
+			s.ExpandedContainers = append(s.ExpandedContainers, c.ProjectPath)
+		}
+	}
+	sort.Strings(s.ExpandedProjects)
+	sort.Strings(s.ExpandedContainers)
+
+	if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) {
+		item := m.treeItems[m.selectedIdx]
+		sel := &SelectionState{
+			Type:         item.Type,
+			ProjectPath:  item.ProjectPath,
+			ProjectName:  item.ProjectName,
+			WorktreeName: item.WorktreeName,
+			SessionName:  item.SessionName,
+		}
+		if c := m.containerByID(item.ContainerID); c != nil {
+			sel.ContainerPath = c.ProjectPath
+		}
+		s.Selection = sel
+	}
+	if m.themeName != m.cfg.Theme {
+		s.Theme = m.themeName
+	}
+	return s
+}
+
+// RestoreUIState applies a persisted state. Project expansion and the theme
+// apply immediately (an unknown theme is ignored); container expansion and
+// selection are resolved against the first container refresh, since container
+// IDs are not known until then.
+func (m *Model) RestoreUIState(s UIState) {
+	if config.IsTheme(s.Theme) {
+		m.applyTheme(s.Theme)
+	}
+	if m.expandedProjects == nil {
+		m.expandedProjects = make(map[string]bool)
+	}
+	for _, key := range s.ExpandedProjects {
+		m.expandedProjects[key] = true
+	}
+	m.pendingRestore = &s
+}
+
+// applyPendingRestore resolves the restored container expansion to the current
+// container IDs. Call after the container list is populated, before rebuilding
+// the tree. No-op unless RestoreUIState is awaiting the first refresh.
+func (m *Model) applyPendingRestore() {
+	if m.pendingRestore == nil || len(m.pendingRestore.ExpandedContainers) == 0 {
+		return
+	}
+	paths := make(map[string]bool)
+	for _, p := range m.pendingRestore.ExpandedContainers {
+		paths[p] = true
+	}
+	if m.expandedContainers == nil {
+		m.expandedContainers = make(map[string]bool)
+	}
+	for _, c := range m.sortedContainers() {
+		if paths[c.ProjectPath] {
+			m.expandedContainers[c.ID] = true
+		}
+	}
+}
+
+// restoreSelection selects the tree item matching the restored selection and
+// clears the pending restore. A session that no longer exists falls back to its
+// container. Call after rebuilding the tree.
+func (m *Model) restoreSelection() {
+	if m.pendingRestore == nil {
+		return
+	}
+	sel := m.pendingRestore.Selection
+	m.pendingRestore = nil
+	if sel == nil {
+		return
+	}
+
+	fallback := -1
+	for i, item := range m.treeItems {
+		if item.Type != sel.Type && !(sel.Type == TreeItemSession && item.Type == TreeItemContainer) {
+			continue
+		}
+		containerPath := ""
+		if c := m.containerByID(item.ContainerID); c != nil {
+			containerPath = c.ProjectPath
+		}
+		if containerPath != sel.ContainerPath || item.ProjectPath != sel.ProjectPath ||
+			item.ProjectName != sel.ProjectName || item.WorktreeName != sel.WorktreeName {
+			continue
+		}
+		if item.Type == sel.Type && item.SessionName == sel.SessionName {
+			m.selectedIdx = i
+			return
+		}
+		if item.Type == TreeItemContainer && fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		m.selectedIdx = fallback
+	}
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	catppuccin "github.com/catppuccin/go"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"devagent/internal/container"
+	"devagent/internal/tmux"
+)
+
+func stateTestContainers(prefix string) []*container.Container {
+	return []*container.Container{
+		{ID: prefix + "-a", Name: "alpha", ProjectPath: "/src/alpha", Sessions: []tmux.Session{{Name: "dev"}}},
+		{ID: prefix + "-b", Name: "bravo", ProjectPath: "/src/bravo", Sessions: []tmux.Session{{Name: "dev"}, {Name: "agent"}}},
+	}
+}
+
+func TestLoadState_MissingOrCorrupt(t *testing.T) {
+	dir := t.TempDir()
+
+	if s := LoadState(StatePath(dir)); s.Selection != nil || len(s.ExpandedProjects) != 0 || len(s.ExpandedContainers) != 0 {
+		t.Errorf("LoadState(missing) = %+v, want empty", s)
+	}
+
+	if err := os.WriteFile(StatePath(dir), []byte("{not json"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if s := LoadState(StatePath(dir)); s.Selection != nil || len(s.ExpandedProjects) != 0 || len(s.ExpandedContainers) != 0 {
+		t.Errorf("LoadState(corrupt) = %+v, want empty", s)
+	}
+}
+
+func TestSaveState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", stateFileName)
+	want := UIState{
+		ExpandedProjects:   []string{"/src/alpha", "__other__"},
+		ExpandedContainers: []string{"/src/bravo"},
+		Selection:          &SelectionState{Type: TreeItemSession, ContainerPath: "/src/bravo", SessionName: "agent"},
+	}
+
+	if err := SaveState(path, want); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	got := LoadState(path)
+
+	if !slices.Equal(got.ExpandedProjects, want.ExpandedProjects) || !slices.Equal(got.ExpandedContainers, want.ExpandedContainers) {
+		t.Errorf("LoadState() = %+v, want %+v", got, want)
+	}
+	if got.Selection == nil || *got.Selection != *want.Selection {
+		t.Errorf("Selection = %+v, want %+v", got.Selection, want.Selection)
+	}
+}
+
+// TestUIState_SurvivesContainerRebuild verifies that expansion and selection are
+// keyed by container project path, so a restart with new container IDs restores them.
+func TestUIState_SurvivesContainerRebuild(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.expandedContainers = map[string]bool{"old-b": true}
+	updated, _ := m.Update(containersRefreshedMsg{containers: stateTestContainers("old")})
+	m = updated.(Model)
+
+	// Select bravo's "agent" session: All, alpha, bravo, dev, agent
+	m.selectedIdx = 4
+	if item := m.treeItems[m.selectedIdx]; item.SessionName != "agent" {
+		t.Fatalf("setup: selected %+v, want agent session", item)
+	}
+
+	saved := m.UIState()
+	if !slices.Equal(saved.ExpandedContainers, []string{"/src/bravo"}) {
+		t.Errorf("ExpandedContainers = %v, want [/src/bravo]", saved.ExpandedContainers)
+	}
+
+	restored := newTreeTestModel(t)
+	restored.RestoreUIState(saved)
+	updated, _ = restored.Update(containersRefreshedMsg{containers: stateTestContainers("new")})
+	restored = updated.(Model)
+
+	if !restored.expandedContainers["new-b"] {
+		t.Errorf("expandedContainers = %v, want new-b expanded", restored.expandedContainers)
+	}
+	item := restored.treeItems[restored.selectedIdx]
+	if item.ContainerID != "new-b" || item.SessionName != "agent" {
+		t.Errorf("selected %+v, want session agent in new-b", item)
+	}
+	if restored.pendingRestore != nil {
+		t.Error("pendingRestore should be cleared after the first refresh")
+	}
+}
+
+func TestRestoreUIState_MissingSessionFallsBackToContainer(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.RestoreUIState(UIState{
+		ExpandedContainers: []string{"/src/alpha"},
+		Selection:          &SelectionState{Type: TreeItemSession, ContainerPath: "/src/alpha", SessionName: "gone"},
+	})
+	updated, _ := m.Update(containersRefreshedMsg{containers: stateTestContainers("c")})
+	m = updated.(Model)
+
+	item := m.treeItems[m.selectedIdx]
+	if item.Type != TreeItemContainer || item.ContainerID != "c-a" {
+		t.Errorf("selected %+v, want container c-a", item)
+	}
+}
+
+func TestThemeKey_CyclesRestylesAndPersists(t *testing.T) {
+	m := newTestModel(t)
+	m.cfg.Theme = "mocha"
+	m.applyTheme("mocha")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m = updated.(Model)
+
+	if m.themeName != "latte" {
+		t.Fatalf("theme after T = %q, want latte (wraps after mocha)", m.themeName)
+	}
+	if got, want := m.styles.ErrorStyle().GetForeground(), lipgloss.Color(catppuccin.Latte.Red().Hex); got != want {
+		t.Errorf("ErrorStyle foreground = %v, want latte red %v", got, want)
+	}
+	if m.cfg.Theme != "mocha" {
+		t.Errorf("config theme = %q, want it left at mocha", m.cfg.Theme)
+	}
+
+	s := m.UIState()
+	if s.Theme != "latte" {
+		t.Fatalf("UIState().Theme = %q, want latte", s.Theme)
+	}
+	restored := newTestModel(t)
+	restored.RestoreUIState(s)
+	if restored.themeName != "latte" {
+		t.Errorf("restored theme = %q, want latte", restored.themeName)
+	}
+
+	restored.RestoreUIState(UIState{Theme: "solarized"})
+	if restored.themeName != "latte" {
+		t.Errorf("unknown persisted theme changed the theme to %q", restored.themeName)
+	}
+}
@@ -148,6 +148,13 @@ func (s *Styles) LogScopeStyle() lipgloss.Style {
 		Foreground(lipgloss.Color(s.flavor.Teal().Hex))
 }
 
+// LogMatchStyle returns the style for log search matches.
+func (s *Styles) LogMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color(s.flavor.Base().Hex)).
+		Background(lipgloss.Color(s.flavor.Yellow().Hex))
+}
+
 // TreeItemSelectedStyle returns the style for the selected tree item.
 func (s *Styles) TreeItemSelectedStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 	"devagent/internal/discovery"
 	"devagent/internal/logging"
 	"devagent/internal/tmux"
+	"devagent/internal/worktree"
 )
 
 func TestTreeItemType_Container(t *testing.T) {
@@ -144,6 +146,79 @@ func TestRebuildTreeItems_ExpandedContainer(t *testing.T) {
 	}
 }
 
+func TestRebuildTreeItems_ExpandedSessionShowsWindows(t *testing.T) {
+	m := newTreeTestModel(t)
+
+	c1 := &container.Container{
+		ID:   "c1",
+		Name: "container-1",
+		Sessions: []tmux.Session{
+			{Name: "dev", ContainerID: "c1"},
+			{Name: "test", ContainerID: "c1"},
+		},
+	}
+	m.containerList.SetItems([]list.Item{containerItem{container: c1}})
+	m.expandedContainers = map[string]bool{"c1": true}
+	m.rebuildTreeItems()
+
+	// Enter on "dev" expands it and asks for its windows
+	m.selectedIdx = 2
+	if cmd := m.toggleTreeExpand(); cmd == nil {
+		t.Fatal("expanding a session should return a command loading its windows")
+	}
+	m.sessionWindows = map[string][]tmux.Window{
+		sessionKey("c1", "dev"): {
+			{Index: 0, Name: "bash", Command: "bash"},
+			{Index: 1, Name: "editor", Active: true, Command: "vim"},
+		},
+	}
+	m.rebuildTreeItems()
+
+	// All, container, dev, 2 windows, test
+	var got []string
+	for _, item := range m.treeItems {
+		switch item.Type {
+		case TreeItemSession:
+			got = append(got, item.SessionName)
+		case TreeItemWindow:
+			got = append(got, fmt.Sprintf("%s:%d", item.SessionName, item.WindowIndex))
+		}
+	}
+	if want := []string{"dev", "dev:0", "dev:1", "test"}; !slices.Equal(got, want) {
+		t.Fatalf("sessions and windows = %v, want %v", got, want)
+	}
+	if !m.treeItems[2].Expanded {
+		t.Error("dev session should be marked expanded")
+	}
+
+	// A window selects its session's container and shows window details
+	m.selectedIdx = 4
+	m.syncSelectionFromTree()
+	if m.selectedContainer == nil || m.selectedContainer.ID != "c1" || m.selectedSessionIdx != 0 {
+		t.Errorf("window selection = %v/%d, want c1 session 0", m.selectedContainer, m.selectedSessionIdx)
+	}
+	detail := m.renderDetailContent()
+	for _, want := range []string{"Index:     1", "Name:      editor", "Command:   vim"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("window detail missing %q:\n%s", want, detail)
+		}
+	}
+
+	layout := ComputeLayout(80, 24, false, false)
+	if tree := m.renderTree(layout); !strings.Contains(tree, "1:editor*") {
+		t.Errorf("tree should show the active window, got: %s", tree)
+	}
+
+	// Collapsing hides the windows again
+	m.selectedIdx = 2
+	if cmd := m.toggleTreeExpand(); cmd != nil {
+		t.Error("collapsing a session should not load windows")
+	}
+	if len(m.treeItems) != 4 {
+		t.Errorf("expected 4 items after collapsing, got %d", len(m.treeItems))
+	}
+}
+
 func TestRebuildTreeItems_MixedExpansion(t *testing.T) {
 	m := newTreeTestModel(t)
 
@@ -569,6 +644,75 @@ func TestRenderDetailPanel_Container(t *testing.T) {
 	}
 }
 
+func TestRenderDetailPanel_ContainerSidecarWarning(t *testing.T) {
+	m := newTreeTestModel(t)
+	c := &container.Container{
+		ID:             "c1",
+		Name:           "my-container",
+		State:          container.StateRunning,
+		SidecarWarning: "proxy sidecar is stopped",
+	}
+	m.containerList.SetItems([]list.Item{containerItem{container: c}})
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.detailPanelOpen = true
+	m.syncSelectionFromTree()
+
+	result := m.renderContainerDetailContent()
+
+	if !strings.Contains(result, "Degraded: proxy sidecar is stopped") {
+		t.Errorf("should show sidecar warning, got: %s", result)
+	}
+}
+
+func TestRender_ContainerNote(t *testing.T) {
+	m := newTreeTestModel(t)
+	c := &container.Container{
+		ID:          "c1",
+		Name:        "my-container",
+		State:       container.StateRunning,
+		ProjectPath: "/projects/my",
+	}
+	m.containerList.SetItems([]list.Item{containerItem{container: c}})
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.detailPanelOpen = true
+	m.syncSelectionFromTree()
+	if err := m.manager.SetNote("/projects/my", "staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	if row := m.renderTreeItem(1, m.treeItems[1], false); !strings.Contains(row, "staging") {
+		t.Errorf("tree row should show the note, got: %s", row)
+	}
+	if detail := m.renderContainerDetailContent(); !strings.Contains(detail, "Note:     staging") {
+		t.Errorf("detail panel should show the note, got: %s", detail)
+	}
+}
+
+func TestRender_ExitedContainerShowsExitCode(t *testing.T) {
+	m := newTreeTestModel(t)
+	c := &container.Container{
+		ID:       "c1",
+		Name:     "crashed",
+		State:    container.StateExited,
+		ExitCode: 137,
+	}
+	m.containerList.SetItems([]list.Item{containerItem{container: c}})
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.detailPanelOpen = true
+	m.syncSelectionFromTree()
+
+	if row := m.renderTreeItem(1, m.treeItems[1], false); !strings.Contains(row, "[exited 137]") {
+		t.Errorf("tree row should show the exit code, got: %s", row)
+	}
+	detail := m.renderContainerDetailContent()
+	if !strings.Contains(detail, "State:    exited 137") || !strings.Contains(detail, "Exited with code 137") {
+		t.Errorf("detail panel should show the exit code, got: %s", detail)
+	}
+}
+
 func TestRenderDetailPanel_Session(t *testing.T) {
 	m := newTreeTestModel(t)
 	c := &container.Container{
@@ -849,7 +993,7 @@ func TestRebuildTreeItems_WithProjectsCreatesProjectGroups(t *testing.T) {
 
 	// Set up containers matching projects (matched by compose project name)
 	c1 := &container.Container{ID: "c1", Name: "container-1", ProjectPath: "/projects/proj1", ComposeProject: container.SanitizeComposeName("proj1")}
-	c2 := &container.Container{ID: "c2", Name: "container-2", ProjectPath: "/projects/proj1", ComposeProject: container.SanitizeComposeName("proj1-feature-x")}
+	c2 := &container.Container{ID: "c2", Name: "container-2", ProjectPath: "/projects/proj1", ComposeProject: worktree.ComposeName("/projects/proj1", "feature-x")}
 	items := []list.Item{
 		containerItem{container: c1},
 		containerItem{container: c2},
@@ -1078,7 +1222,7 @@ func TestRebuildTreeItems_ContainerExpansionUnderWorktree(t *testing.T) {
 		ID:             "c1",
 		Name:           "container-1",
 		ProjectPath:    "/projects/proj1/feature-x",
-		ComposeProject: container.SanitizeComposeName("proj1-feature-x"),
+		ComposeProject: worktree.ComposeName("/projects/proj1", "feature-x"),
 		Sessions: []tmux.Session{
 			{Name: "dev", ContainerID: "c1"},
 			{Name: "test", ContainerID: "c1"},
@@ -1261,8 +1405,8 @@ func TestFindContainersForProject_IncludesMainAndWorktrees(t *testing.T) {
 
 	// Containers in main and worktrees
 	c1 := &container.Container{ID: "c1", Name: "container-1", ProjectPath: "/projects/proj1", ComposeProject: container.SanitizeComposeName("proj1")}
-	c2 := &container.Container{ID: "c2", Name: "container-2", ProjectPath: "/projects/proj1/feature", ComposeProject: container.SanitizeComposeName("proj1-feature")}
-	c3 := &container.Container{ID: "c3", Name: "container-3", ProjectPath: "/projects/proj1/bugfix", ComposeProject: container.SanitizeComposeName("proj1-bugfix")}
+	c2 := &container.Container{ID: "c2", Name: "container-2", ProjectPath: "/projects/proj1/feature", ComposeProject: worktree.ComposeName("/projects/proj1", "feature")}
+	c3 := &container.Container{ID: "c3", Name: "container-3", ProjectPath: "/projects/proj1/bugfix", ComposeProject: worktree.ComposeName("/projects/proj1", "bugfix")}
 	items := []list.Item{
 		containerItem{container: c1},
 		containerItem{container: c2},
@@ -1347,7 +1491,7 @@ func TestSyncSelection_WorktreeNodeClearsSelectedContainer(t *testing.T) {
 	m.expandedProjects["/projects/proj1"] = true
 
 	// Container under worktree
-	c1 := &container.Container{ID: "c1", Name: "container-1", ProjectPath: "/projects/proj1/feature", ComposeProject: container.SanitizeComposeName("proj1-feature")}
+	c1 := &container.Container{ID: "c1", Name: "container-1", ProjectPath: "/projects/proj1/feature", ComposeProject: worktree.ComposeName("/projects/proj1", "feature")}
 	items := []list.Item{containerItem{container: c1}}
 	m.containerList.SetItems(items)
 
@@ -1393,3 +1537,326 @@ func TestSyncSelection_WorktreeNodeClearsSelectedContainer(t *testing.T) {
 		t.Errorf("selectedSessionIdx should be 0 when worktree node is selected, got %d", m.selectedSessionIdx)
 	}
 }
+
+// Tree Filter Tests
+
+func typeTreeFilter(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	if !m.treeFilterOpen {
+		t.Fatal("expected '/' to open the tree filter input")
+	}
+	for _, r := range text {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestTreeFilter_MatchesContainerNameCaseInsensitive(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+
+	m = typeTreeFilter(t, m, "CONTAINER-2")
+
+	// All + container-2 only
+	if len(m.treeItems) != 2 {
+		t.Fatalf("expected 2 tree items, got %d", len(m.treeItems))
+	}
+	if m.treeItems[1].ContainerID != "c2" {
+		t.Errorf("expected c2 to remain, got %q", m.treeItems[1].ContainerID)
+	}
+	if got := m.filteredContainerCount(); got != 1 {
+		t.Errorf("filteredContainerCount() = %d, want 1", got)
+	}
+	if !strings.Contains(m.renderAllProjectsTreeItem("", false), "All Containers (1)") {
+		t.Errorf("All Containers row should show filtered count, got %q", m.renderAllProjectsTreeItem("", false))
+	}
+}
+
+func TestTreeFilter_MatchesProjectPath(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.containerList.SetItems([]list.Item{
+		containerItem{container: &container.Container{ID: "c1", Name: "alpha", ProjectPath: "/code/webapp"}},
+		containerItem{container: &container.Container{ID: "c2", Name: "beta", ProjectPath: "/code/cli"}},
+	})
+	m.rebuildTreeItems()
+
+	m = typeTreeFilter(t, m, "webapp")
+
+	if len(m.treeItems) != 2 || m.treeItems[1].ContainerID != "c1" {
+		t.Errorf("expected only c1 to match project path, got %+v", m.treeItems)
+	}
+}
+
+func TestTreeFilter_ProjectsWithoutMatchesCollapseOut(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{
+		{Name: "webapp", Path: "/code/webapp"},
+		{Name: "cli", Path: "/code/cli"},
+	}
+	m.containerList.SetItems([]list.Item{
+		containerItem{container: &container.Container{ID: "c1", Name: "webapp-dev", ComposeProject: "webapp"}},
+		containerItem{container: &container.Container{ID: "c2", Name: "cli-dev", ComposeProject: "cli"}},
+	})
+	m.rebuildTreeItems()
+
+	m = typeTreeFilter(t, m, "webapp")
+
+	var projects []string
+	for _, item := range m.treeItems {
+		if item.Type == TreeItemProject {
+			projects = append(projects, item.ProjectName)
+		}
+	}
+	if len(projects) != 1 || projects[0] != "webapp" {
+		t.Errorf("expected only webapp project to remain, got %v", projects)
+	}
+}
+
+func TestTreeFilter_EscapeClearsFilter(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+	m = typeTreeFilter(t, m, "container-1")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+
+	if m.treeFilterOpen || m.treeFilter != "" {
+		t.Errorf("expected filter cleared and closed, got open=%v filter=%q", m.treeFilterOpen, m.treeFilter)
+	}
+	if len(m.treeItems) != 4 {
+		t.Errorf("expected all 4 tree items after clearing, got %d", len(m.treeItems))
+	}
+}
+
+func TestTreeFilter_EnterKeepsFilterAndEscapeClearsIt(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+	m = typeTreeFilter(t, m, "container-3")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.treeFilterOpen {
+		t.Error("enter should close the filter input")
+	}
+	if m.treeFilter != "container-3" {
+		t.Errorf("treeFilter = %q, want container-3", m.treeFilter)
+	}
+	if !strings.Contains(m.renderTreeFilterStatus(), "container-3") {
+		t.Errorf("status bar should show the active filter, got %q", m.renderTreeFilterStatus())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	if m.treeFilter != "" {
+		t.Errorf("escape should clear the active filter, got %q", m.treeFilter)
+	}
+}
+
+func TestTreeFilter_SelectionSnapsWhenItemFilteredAway(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+	// Select the last container (All + 3 containers)
+	m.selectedIdx = 3
+	m.syncSelectionFromTree()
+
+	m = typeTreeFilter(t, m, "container-1")
+
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.treeItems) {
+		t.Fatalf("selectedIdx %d out of range for %d items", m.selectedIdx, len(m.treeItems))
+	}
+	if m.selectedContainer == nil || m.selectedContainer.ID != "c1" {
+		t.Errorf("expected selection to snap to c1, got %+v", m.selectedContainer)
+	}
+}
+
+func TestTreeFilter_SelectionFollowsVisibleItem(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+	// Select container-3 and filter to it; it moves from index 3 to 1
+	m.selectedIdx = 3
+	m.syncSelectionFromTree()
+
+	m = typeTreeFilter(t, m, "container-3")
+
+	if m.selectedIdx != 1 {
+		t.Errorf("selectedIdx = %d, want 1", m.selectedIdx)
+	}
+	if m.selectedContainer == nil || m.selectedContainer.ID != "c3" {
+		t.Errorf("expected c3 to stay selected, got %+v", m.selectedContainer)
+	}
+}
+
+// Tree Sort Tests
+
+func TestTreeSort_FlatFallbackIsSortedByName(t *testing.T) {
+	m := newTreeTestModel(t)
+	// Items deliberately out of order (as if from an unordered source)
+	m.containerList.SetItems([]list.Item{
+		containerItem{container: &container.Container{ID: "c2", Name: "bravo"}},
+		containerItem{container: &container.Container{ID: "c1", Name: "alpha"}},
+	})
+	m.rebuildTreeItems()
+
+	if m.treeItems[1].ContainerID != "c1" || m.treeItems[2].ContainerID != "c2" {
+		t.Errorf("expected alpha before bravo, got %s, %s", m.treeItems[1].ContainerID, m.treeItems[2].ContainerID)
+	}
+}
+
+func TestTreeSort_OKeyCyclesSortKey(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.containerList.SetItems([]list.Item{
+		containerItem{container: &container.Container{ID: "c1", Name: "alpha", State: container.StateStopped}},
+		containerItem{container: &container.Container{ID: "c2", Name: "bravo", State: container.StateRunning}},
+	})
+	m.rebuildTreeItems()
+	// Select alpha
+	m.selectedIdx = 1
+	m.syncSelectionFromTree()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(Model)
+
+	if m.treeSort != container.SortByState {
+		t.Fatalf("treeSort = %q, want state", m.treeSort)
+	}
+	// Running (bravo) sorts first under state ordering
+	if m.treeItems[1].ContainerID != "c2" {
+		t.Errorf("expected running container first, got %s", m.treeItems[1].ContainerID)
+	}
+	// Selection follows alpha to its new position
+	if m.selectedContainer == nil || m.selectedContainer.ID != "c1" {
+		t.Errorf("expected alpha to stay selected, got %+v", m.selectedContainer)
+	}
+	if !strings.Contains(m.statusMessage, "state") {
+		t.Errorf("statusMessage = %q, should mention sort key", m.statusMessage)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(Model)
+	if m.treeSort != container.SortByName {
+		t.Errorf("treeSort = %q after full cycle, want name", m.treeSort)
+	}
+}
+
+func TestProjectItemContainers_ProjectAndOther(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{{
+		Name:      "proj1",
+		Path:      "/projects/proj1",
+		Worktrees: []discovery.Worktree{{Name: "feature-x", Path: "/projects/proj1/.worktrees/feature-x", Branch: "feature-x"}},
+	}}
+	m.containerList.SetItems([]list.Item{
+		containerItem{container: &container.Container{ID: "c1", Name: "proj1-app-1", ComposeProject: "proj1",
+			Sessions: []tmux.Session{{Name: "dev"}, {Name: "agent"}}}},
+		containerItem{container: &container.Container{ID: "c2", Name: "proj1-feature-x-app-1", ComposeProject: worktree.ComposeName("/projects/proj1", "feature-x"),
+			Sessions: []tmux.Session{{Name: "dev"}}}},
+		containerItem{container: &container.Container{ID: "c3", Name: "stray-app-1", ComposeProject: "stray",
+			Sessions: []tmux.Session{{Name: "misc"}}}},
+	})
+
+	got := GenerateProjectAttachCommands(m.projectItemContainers(TreeItem{Type: TreeItemProject, ProjectPath: "/projects/proj1"}), "docker", "")
+	want := []string{
+		"docker exec -it -u vscode proj1-app-1 tmux attach -t dev",
+		"docker exec -it -u vscode proj1-app-1 tmux attach -t agent",
+		"docker exec -it -u vscode proj1-feature-x-app-1 tmux attach -t dev",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("project commands =\n%v\nwant\n%v", got, want)
+	}
+
+	other := m.projectItemContainers(TreeItem{Type: TreeItemProject, ProjectName: "Other"})
+	if len(other) != 1 || other[0].ID != "c3" {
+		t.Errorf("Other group containers = %v, want [c3]", other)
+	}
+}
+
+func TestYKey_OnProjectWithoutSessions(t *testing.T) {
+	m := newTreeTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{{Name: "proj1", Path: "/projects/proj1"}}
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	result := updated.(Model)
+
+	if cmd != nil {
+		t.Error("no clipboard command expected when the project has no sessions")
+	}
+	if result.statusMessage != "No sessions to copy" {
+		t.Errorf("statusMessage = %q, want %q", result.statusMessage, "No sessions to copy")
+	}
+}
+
+// TestJumpToContainer_ByState verifies ]/[ and }/{ jump between running and
+// stopped containers, skipping sessions and wrapping around the tree.
+func TestJumpToContainer_ByState(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 5)
+	for i, item := range m.containerList.Items() {
+		c := item.(containerItem).container
+		if i == 0 || i == 3 {
+			c.State = container.StateRunning
+		} else {
+			c.State = container.StateStopped
+		}
+	}
+	m.expandedContainers["c1"] = true // c1's sessions sit between c1 and c2
+	m.rebuildTreeItems()
+
+	indexOf := func(id string) int {
+		for i, item := range m.treeItems {
+			if item.Type == TreeItemContainer && item.ContainerID == id {
+				return i
+			}
+		}
+		t.Fatalf("container %s not in tree", id)
+		return -1
+	}
+	press := func(key string) string {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		return m.treeItems[m.selectedIdx].ContainerID
+	}
+
+	m.selectedIdx = indexOf("c1")
+	if got := press("]"); got != "c4" {
+		t.Errorf("] from c1 selected %s, want next running c4", got)
+	}
+	if m.selectedContainer == nil || m.selectedContainer.ID != "c4" {
+		t.Error("selectedContainer should follow the jump")
+	}
+	if got := press("]"); got != "c1" {
+		t.Errorf("] from c4 selected %s, want wrap to c1", got)
+	}
+	if got := press("}"); got != "c2" {
+		t.Errorf("} from c1 selected %s, want next stopped c2 (skipping sessions)", got)
+	}
+	if got := press("["); got != "c1" {
+		t.Errorf("[ from c2 selected %s, want previous running c1", got)
+	}
+	if got := press("{"); got != "c5" {
+		t.Errorf("{ from c1 selected %s, want wrap to c5", got)
+	}
+
+	m.selectedIdx = indexOf("c4")
+	if m.jumpToContainer(true, true, false) {
+		t.Error("jumpToContainer without wrap should find no running container after c4")
+	}
+	if m.selectedIdx != indexOf("c4") {
+		t.Error("selection should not move when no container matches")
+	}
+}
+
+func TestJumpToContainer_NoMatchShowsStatus(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 2)
+	m.selectedIdx = 1
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	m = updated.(Model)
+
+	if m.selectedIdx != 1 {
+		t.Errorf("selectedIdx = %d, want unchanged 1", m.selectedIdx)
+	}
+	if m.statusMessage != "No other running containers" {
+		t.Errorf("statusMessage = %q, want no-match message", m.statusMessage)
+	}
+}
@@ -3,21 +3,28 @@
 package tui
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"devagent/internal/audit"
+	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/events"
 	"devagent/internal/logging"
+	"devagent/internal/tmux"
 	"devagent/internal/worktree"
 )
 
@@ -30,9 +37,14 @@ type containersRefreshedMsg struct {
 }
 
 type containerErrorMsg struct {
-	err error
+	err        error
+	background bool // from the periodic refresh; the cached list stays on screen
 }
 
+// staleRefreshStatus is shown when a periodic refresh fails. It is cleared by
+// the next successful refresh.
+const staleRefreshStatus = "Refresh failed, showing cached containers"
+
 type containerActionMsg struct {
 	action string
 	id     string
@@ -43,13 +55,66 @@ type vscodeLaunchMsg struct {
 	err error
 }
 
+// pruneResultMsg reports the containers removed by a prune.
+type pruneResultMsg struct {
+	removed []string
+	err     error
+}
+
+// proxyCertsRegeneratedMsg reports the result of regenerating a project's
+// proxy certificates.
+type proxyCertsRegeneratedMsg struct {
+	name string
+	err  error
+}
+
+// allowlistUpdatedMsg reports the result of applying an edited proxy
+// allowlist to a container.
+type allowlistUpdatedMsg struct {
+	containerID string
+	name        string
+	err         error
+}
+
+// sessionsDuplicatedMsg reports the result of duplicating a container's
+// session layout.
+type sessionsDuplicatedMsg struct {
+	source  string
+	created int
+	err     error
+}
+
+// containerLogsExportedMsg reports the result of saving a container's recent
+// logs to a file in the data dir.
+type containerLogsExportedMsg struct {
+	name string
+	path string
+	err  error
+}
+
+// clipboardCopiedMsg reports the result of Model.copyToClipboard. success is
+// the status shown on success; empty means "Copied".
+type clipboardCopiedMsg struct {
+	err     error
+	success string
+}
+
+// sessionAttachDoneMsg is sent when an inline attach (session view `a`)
+// returns control to the TUI, on detach or when the attach command fails.
+type sessionAttachDoneMsg struct {
+	session string
+	err     error
+}
+
 type tickMsg struct {
 	time time.Time
+	gen  int // Model.tickGen when scheduled; stale ticks are dropped
 }
 
 // logEntriesMsg delivers log entries from the logging channel.
 type logEntriesMsg struct {
 	entries []logging.LogEntry
+	dropped uint64 // total entries dropped by the channel so far
 }
 
 // clearStatusMsg is sent after a timed delay to clear the status bar.
@@ -59,19 +124,47 @@ type clearStatusMsg struct{}
 type formAutoCloseMsg struct{}
 
 // isolationInfoMsg is sent when isolation info is fetched for the selected container.
+// It also carries the container's published ports.
 type isolationInfoMsg struct {
 	info        *container.IsolationInfo
+	ports       []container.PortMapping
 	containerID string
 }
 
 // worktreeActionMsg is sent when a worktree operation completes.
 type worktreeActionMsg struct {
-	action      string // "create" or "destroy"
+	action      string // "create", "destroy" or "prune"
 	name        string
 	projectPath string
 	err         error
 }
 
+// worktreeBaseVerifiedMsg reports whether the worktree form's base ref
+// resolves (worktree.VerifyRef), checked off the Update loop before the
+// worktree is created.
+type worktreeBaseVerifiedMsg struct {
+	projectPath string
+	name        string
+	base        string
+	err         error
+}
+
+// worktreeChangesMsg carries a worktree's uncommitted changes, checked before
+// its removal is confirmed.
+type worktreeChangesMsg struct {
+	name         string
+	changedFiles []string
+	err          error
+}
+
+// confirmForceDeleteWorktree is the confirm action for removing a worktree
+// with uncommitted changes. It is not a config.Confirm* action, so it always
+// asks.
+const confirmForceDeleteWorktree = "force_delete_worktree"
+
+// maxConfirmChangedFiles caps the changed files listed in the confirm dialog.
+const maxConfirmChangedFiles = 10
+
 // worktreeContainerMsg is sent when a worktree container start completes.
 type worktreeContainerMsg struct {
 	name string
@@ -79,11 +172,49 @@ type worktreeContainerMsg struct {
 	err  error
 }
 
+// worktreeProgressMsg delivers a progress step while a worktree container
+// starts. updates carries the remaining messages for this start, ending with
+// its worktreeContainerMsg.
+type worktreeProgressMsg struct {
+	name    string
+	step    container.ProgressStep
+	updates <-chan tea.Msg
+}
+
 // projectsRefreshedMsg is sent when projects are rescanned.
 type projectsRefreshedMsg struct {
 	projects []discovery.DiscoveredProject
 }
 
+// configReloadedMsg carries a freshly loaded config and template set.
+// Only the fields that are safe to change live are applied.
+type configReloadedMsg struct {
+	cfg       config.Config
+	templates []config.Template
+}
+
+// ConfigReloaded returns a message that applies a reloaded config to a running
+// TUI. Callers are expected to have validated cfg (e.g. ValidateRuntime) first.
+func ConfigReloaded(cfg config.Config, templates []config.Template) tea.Msg {
+	return configReloadedMsg{cfg: cfg, templates: templates}
+}
+
+// ProjectsRefreshed returns a message that replaces the discovered projects of
+// a running TUI, e.g. after a discovery.Watcher reported a change.
+func ProjectsRefreshed(projects []discovery.DiscoveredProject) tea.Msg {
+	return projectsRefreshedMsg{projects: projects}
+}
+
+// projectWatchStoppedMsg reports that project watching failed, so the TUI
+// resumes rescanning on every tick.
+type projectWatchStoppedMsg struct{}
+
+// ProjectWatchStopped returns a message that switches a running TUI back to
+// polling scan paths after its discovery.Watcher stopped.
+func ProjectWatchStopped() tea.Msg {
+	return projectWatchStoppedMsg{}
+}
+
 // Update handles messages and updates the model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -143,7 +274,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 
 			// Update list delegate with new spinner frame
-			m.containerDelegate = m.containerDelegate.WithSpinnerState(m.statusSpinner.View(), m.pendingOperations)
+			m.containerDelegate = m.containerDelegate.WithSpinnerState(m.statusSpinner.View(), m.manager.Operations().Actions())
 			m.containerList.SetDelegate(m.containerDelegate)
 		}
 
@@ -195,6 +326,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleActionMenuKey(msg)
 		}
 
+		// Handle note editor input when the editor is open
+		if m.noteEditorOpen {
+			return m.handleNoteEditorKey(msg)
+		}
+
+		// Handle allowlist editor input when the editor is open
+		if m.allowlistEditorOpen {
+			return m.handleAllowlistEditorKey(msg)
+		}
+
 		// Handle worktree form input when worktree form is open
 		if m.worktreeFormOpen {
 			return m.handleWorktreeFormKey(msg)
@@ -215,6 +356,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleSessionViewKey(msg)
 		}
 
+		// Handle tree filter input when the filter is being edited
+		if m.treeFilterOpen {
+			return m.handleTreeFilterKey(msg)
+		}
+
+		// Handle log search input when the search is being edited
+		if m.logSearchOpen {
+			return m.handleLogSearchKey(msg)
+		}
+
 		// Handle tree navigation when tree items exist and tree is focused
 		if len(m.treeItems) > 0 && m.panelFocus == FocusTree {
 			switch msg.Type {
@@ -225,12 +376,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.moveTreeSelectionDown()
 				return m, m.fetchIsolationInfoIfNeeded()
 			case tea.KeyEnter:
-				// Toggle expand/collapse for projects and containers
+				// Toggle expand/collapse for projects, containers and sessions
 				if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) {
 					item := m.treeItems[m.selectedIdx]
-					if item.Type == TreeItemProject || item.Type == TreeItemContainer {
-						m.toggleTreeExpand()
-						return m, nil
+					if item.Type == TreeItemProject || item.Type == TreeItemContainer || item.Type == TreeItemSession {
+						return m, m.toggleTreeExpand()
 					}
 				}
 			case tea.KeyRight:
@@ -245,6 +395,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			case tea.KeyEscape:
+				// Clear an active tree filter first
+				if m.treeFilter != "" {
+					m.setTreeFilter("")
+					m.quitHintCount = 0
+					return m, nil
+				}
 				// Close detail panel (if open)
 				if m.detailPanelOpen {
 					m.detailPanelOpen = false
@@ -281,6 +437,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "4":
 				m.toggleLogLevel("ERROR")
 				return m, nil
+			case "/":
+				// Open the log search input (keeps any existing search text)
+				m.logSearchOpen = true
+				m.logAutoScroll = false
+				return m, nil
+			case "n":
+				if m.logSearch != "" {
+					m.cycleLogMatch(1)
+					return m, nil
+				}
+			case "N":
+				if m.logSearch != "" {
+					m.cycleLogMatch(-1)
+					return m, nil
+				}
+			}
+
+			// Details panel: w toggles wrapping, y copies the entry, and
+			// left/right scroll unwrapped lines before left closes the panel
+			if m.logDetailsOpen && m.logDetailsReady {
+				switch msg.String() {
+				case "w":
+					m.toggleLogDetailsWrap()
+					return m, nil
+				case "y":
+					if text := m.selectedLogDetailsText(); text != "" {
+						return m, m.copyToClipboard(text)
+					}
+					return m, nil
+				}
+				if !m.logDetailsWrap {
+					switch {
+					case msg.Type == tea.KeyRight:
+						m.logDetailsViewport.ScrollRight(logDetailsHorizontalStep)
+						return m, nil
+					case msg.Type == tea.KeyLeft && m.logDetailsViewport.HorizontalScrollPercent() > 0:
+						m.logDetailsViewport.ScrollLeft(logDetailsHorizontalStep)
+						return m, nil
+					}
+				}
 			}
 
 			// Right/Left arrow for opening/closing details panel
@@ -327,7 +523,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			// Escape to return to tree
+			// Escape clears an active search first, then returns to tree
+			if msg.Type == tea.KeyEscape && m.logSearch != "" {
+				m.setLogSearch("")
+				m.quitHintCount = 0
+				return m, nil
+			}
 			if msg.Type == tea.KeyEscape {
 				m.panelFocus = FocusTree
 				m.quitHintCount = 0
@@ -405,11 +606,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.panelFocus = m.nextFocus()
 			return m, nil
 
+		case "/":
+			// Open the tree filter input (keeps any existing filter text)
+			m.treeFilterOpen = true
+			m.panelFocus = FocusTree
+			return m, nil
+
+		case "o":
+			// Cycle tree sort order (name → state → created)
+			m.cycleTreeSort()
+			m.statusLevel = StatusInfo
+			m.statusMessage = "Sorted by " + string(m.treeSort)
+			return m, nil
+
+		case "]", "[", "}", "{":
+			// Jump to the next/previous running (]/[) or stopped (}/{) container
+			key := msg.String()
+			running := key == "]" || key == "["
+			if !m.jumpToContainer(running, key == "]" || key == "}", true) {
+				state := "stopped"
+				if running {
+					state = "running"
+				}
+				m.statusLevel = StatusInfo
+				m.statusMessage = "No other " + state + " containers"
+			}
+			return m, nil
+
+		case "P":
+			// Prune all stopped managed containers (after confirmation).
+			// Past the destroy threshold, confirming needs "destroy" typed,
+			// whatever the bulk policy says.
+			if n := m.pruneCandidateCount(); m.cfg != nil && n > m.cfg.Confirm.DestroyConfirmThreshold() {
+				m.openConfirm(config.ConfirmPrune, "",
+					fmt.Sprintf("This destroys %d stopped containers and orphaned sidecars. Type %q to confirm.", n, confirmDestroyWord))
+				m.confirmName = confirmDestroyWord
+				return m, nil
+			}
+			return m.confirmOrRun(config.ConfirmPrune, "", "Destroy all stopped containers and orphaned sidecars?")
+
 		case "r":
 			// Refresh containers
 			m.logger.Debug("refresh containers requested")
 			return m, m.refreshContainers()
 
+		case "z":
+			// Pause/resume periodic refresh
+			return m, m.toggleRefreshPaused()
+
+		case "T":
+			m.cycleTheme()
+			return m, nil
+
 		case "c":
 			// Open container creation form
 			m.logger.Debug("opening container creation form")
@@ -456,23 +704,133 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 			c := m.selectedContainer
-			m.confirmOpen = true
-			m.confirmAction = "destroy_container"
-			m.confirmTarget = c.ID
-			m.confirmMessage = fmt.Sprintf("Destroy container '%s'?", c.Name)
+			if m.manager.IsProtected(c) {
+				// do-not-delete containers always confirm, by typing the name
+				m.openConfirm(config.ConfirmDestroyContainer, c.ID,
+					fmt.Sprintf("Container '%s' is marked %s. Type its name to destroy it.", c.Name, container.ProtectedNote))
+				m.confirmName = c.Name
+				return m, nil
+			}
+			return m.confirmOrRun(config.ConfirmDestroyContainer, c.ID, fmt.Sprintf("Destroy container '%s'?", c.Name))
+
+		case "i":
+			// Open the creation form in its clone-a-git-URL variant
+			m.logger.Debug("opening clone form")
+			m.openCloneForm()
 			return m, nil
 
+		case "N":
+			// Edit the selected container's note
+			if m.selectedContainer != nil {
+				c := m.selectedContainer
+				m.logger.Debug("opening note editor", "container", c.Name)
+				m.openNoteEditor(c, m.manager.Note(c.ProjectPath))
+				return m, nil
+			}
+
+		case "C":
+			// Regenerate proxy certificates for the selected running container
+			if m.selectedContainer != nil && m.selectedContainer.IsRunning() {
+				c := m.selectedContainer
+				m.logger.Info("regenerating proxy certificates", "containerID", c.ID, "name", c.Name)
+				cmd := m.setLoading("Regenerating proxy certificates for " + c.Name + "...")
+				return m, tea.Batch(cmd, m.regenerateProxyCerts(c.Name, c.ProjectPath))
+			}
+
+		case "a":
+			// Edit the proxy allowlist of the isolated container in the detail panel
+			info := m.cachedIsolationInfo
+			if m.detailPanelOpen && m.selectedContainer != nil && m.selectedContainer.IsRunning() && info != nil && info.NetworkIsolated {
+				c := m.selectedContainer
+				domains, err := container.ReadAllowlistFromFilterScript(c.ProjectPath)
+				if err != nil {
+					m.setError("Failed to read allowlist for "+c.Name, err)
+					return m, nil
+				}
+				m.logger.Debug("opening allowlist editor", "container", c.Name)
+				m.openAllowlistEditor(c, domains)
+				return m, nil
+			}
+
+		case "D":
+			// Mark the selected container's session layout, or duplicate the
+			// marked layout into the selected container
+			if m.selectedContainer == nil || !m.selectedContainer.IsRunning() {
+				break
+			}
+			c := m.selectedContainer
+			src := m.layoutSource
+			switch {
+			case src == nil:
+				m.layoutSource = c
+				m.statusLevel = StatusInfo
+				m.statusMessage = "Copied session layout of " + c.Name + " (D: paste into selected • A: paste into all running • D on source: cancel)"
+				return m, nil
+			case src.ID == c.ID:
+				m.layoutSource = nil
+				m.clearStatus()
+				return m, nil
+			}
+			m.layoutSource = nil
+			cmd := m.setLoading("Duplicating sessions from " + src.Name + " to " + c.Name + "...")
+			return m, tea.Batch(cmd, m.duplicateSessions(src.ID, src.Name, []string{c.ID}))
+
+		case "A":
+			// Duplicate the marked session layout into every running container
+			if src := m.layoutSource; src != nil {
+				var targets []string
+				for _, c := range m.sortedContainers() {
+					if c.IsRunning() && c.ID != src.ID {
+						targets = append(targets, c.ID)
+					}
+				}
+				m.layoutSource = nil
+				if len(targets) == 0 {
+					m.statusLevel = StatusInfo
+					m.statusMessage = "No other running containers"
+					return m, nil
+				}
+				cmd := m.setLoading(fmt.Sprintf("Duplicating sessions from %s to %d containers...", src.Name, len(targets)))
+				return m, tea.Batch(cmd, m.duplicateSessions(src.ID, src.Name, targets))
+			}
+
+		case "e":
+			// Export the selected container's recent logs to a file
+			if m.selectedContainer != nil {
+				c := m.selectedContainer
+				cmd := m.setLoading("Saving logs for " + c.Name + "...")
+				return m, tea.Batch(cmd, m.exportContainerLogs(c.ID, c.Name))
+			}
+
 		case "t":
 			// Open action menu for selected container
 			if m.selectedContainer != nil && m.selectedContainer.State == container.StateRunning {
 				m.logger.Debug("opening action menu")
 				m.actionMenuOpen = true
+				m.actionMenuIdx = 0
 				return m, nil
 			}
 
+		case "y":
+			// Copy attach commands for every session in the selected project
+			if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) && m.treeItems[m.selectedIdx].IsProject() {
+				containers := m.projectItemContainers(m.treeItems[m.selectedIdx])
+				commands := GenerateProjectAttachCommands(containers, m.manager.RuntimePath(), m.attachCommandTemplate())
+				if len(commands) == 0 {
+					m.statusLevel = StatusInfo
+					m.statusMessage = "No sessions to copy"
+					return m, nil
+				}
+				return m, m.copyToClipboardAs(strings.Join(commands, "\n"), fmt.Sprintf("Copied %d attach commands", len(commands)))
+			}
+
 		case "v":
 			// Launch VS Code attached to selected container
 			if m.selectedContainer != nil && m.selectedContainer.State == container.StateRunning {
+				if !m.vscodeAvailable {
+					m.setError("VS Code CLI (code) not found on PATH", nil)
+					return m, nil
+				}
 				m.logger.Debug("launching VS Code", "container", m.selectedContainer.Name)
 				workspaceFolder := container.ReadWorkspaceFolder(m.selectedContainer.ProjectPath)
 				return m, m.launchVSCode(m.selectedContainer.ID, workspaceFolder)
@@ -508,34 +866,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) {
 				item := m.treeItems[m.selectedIdx]
 				if item.Type == TreeItemWorktree && item.WorktreeName != "main" {
-					m.confirmOpen = true
-					m.confirmAction = "destroy_worktree"
-					m.confirmTarget = item.WorktreeName
-					m.confirmMessage = fmt.Sprintf("Remove worktree '%s'?", item.WorktreeName)
-					return m, nil
+					if projectPath := m.worktreeProjectPath(item.WorktreeName); projectPath != "" {
+						// Check for uncommitted changes first; the confirm dialog lists them
+						return m, m.checkWorktreeChanges(projectPath, item.WorktreeName)
+					}
+				}
+			}
+
+		case "p":
+			// Prune worktrees whose directories are gone from the selected project
+			if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) {
+				item := m.treeItems[m.selectedIdx]
+				if item.Type == TreeItemProject && item.ProjectPath != "" {
+					m.logger.Info("pruning worktrees", "project", item.ProjectName)
+					cmd := m.setLoading("Pruning worktrees of " + item.ProjectName + "...")
+					return m, tea.Batch(cmd, m.pruneWorktrees(item.ProjectPath, item.ProjectName))
 				}
 			}
 
 		case "l", "L":
 			// Toggle log panel
 			m.logger.Debug("toggling log panel", "visible", !m.logPanelOpen)
-			m.logPanelOpen = !m.logPanelOpen
-			if !m.logPanelOpen && m.panelFocus == FocusLogs {
-				m.panelFocus = FocusTree
-			}
-			if m.logPanelOpen {
-				// Recalculate layout and initialize viewport if needed
-				layout := ComputeLayout(m.width, m.height, m.logPanelOpen, m.detailPanelOpen)
-				if !m.logReady {
-					m.logViewport = viewport.New(layout.Logs.Width, layout.Logs.Height-1)
-					m.logReady = true
+			m.setLogPanelOpen(!m.logPanelOpen)
+			return m, nil
+
+		case "f":
+			// Follow the selected container's output in the log panel; again to stop
+			if m.selectedContainer != nil {
+				if m.containerTail.id == m.selectedContainer.ID {
+					m.stopContainerLogs()
+					return m, nil
+				}
+				if m.selectedContainer.IsRunning() {
+					return m, m.startContainerLogs(m.selectedContainer)
 				}
-				m.updateLogViewportContent()
 			}
-			// Recalculate list size for split layout
-			layout := ComputeLayout(m.width, m.height, m.logPanelOpen, m.detailPanelOpen)
-			m.containerList.SetSize(m.width-4, layout.ContentListHeight())
-			return m, nil
 
 		case "j":
 			// Scroll logs down when panel is open
@@ -551,12 +916,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) && m.treeItems[m.selectedIdx].Type == TreeItemSession {
 				session := m.SelectedSession()
 				if session != nil && m.selectedContainer != nil {
-					// Show confirmation dialog
-					m.confirmOpen = true
-					m.confirmAction = "kill_session"
-					m.confirmTarget = session.Name
-					m.confirmMessage = fmt.Sprintf("Kill session '%s'?", session.Name)
-					return m, nil
+					return m.confirmOrRun(config.ConfirmKillSession, session.Name, fmt.Sprintf("Kill session '%s'?", session.Name))
 				}
 			}
 			// Scroll logs up when panel is open
@@ -593,18 +953,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case containersRefreshedMsg:
 		m.err = nil
+		if m.statusMessage == staleRefreshStatus {
+			m.clearStatus()
+		}
 		items := toListItems(msg.containers)
 		m.containerList.SetItems(items)
 		// Rebuild tree items after container refresh
+		m.applyPendingRestore()
 		m.rebuildTreeItems()
+		m.restoreSelection()
 		// Sync selection after rebuild, but preserve selectedContainer if session view is open
 		// to prevent the modal from "rotating" through containers during periodic refresh
 		if !m.sessionViewOpen {
 			m.syncSelectionFromTree()
 		}
+		if m.startupDetailPending {
+			m.startupDetailPending = false
+			if m.selectFirstRunningContainer() {
+				m.syncSelectionFromTree()
+				return m, m.fetchIsolationInfoIfNeeded()
+			}
+		}
 		return m, nil
 
 	case containerErrorMsg:
+		if msg.background {
+			m.logger.Warn("periodic refresh failed, keeping cached containers", "error", msg.err)
+			if m.statusLevel != StatusError {
+				m.statusLevel = StatusInfo
+				m.statusMessage = staleRefreshStatus
+			}
+			return m, nil
+		}
 		m.logger.Error("container operation error", "error", msg.err)
 		m.err = msg.err
 		return m, nil
@@ -613,6 +993,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Clear pending state regardless of success/error
 		m.clearPending(msg.id)
 
+		actionNames := map[string]string{
+			"create":  "created",
+			"start":   "started",
+			"stop":    "stopped",
+			"destroy": "destroyed",
+		}
+		if errors.Is(msg.err, container.ErrAlreadyRunning) || errors.Is(msg.err, container.ErrNotRunning) {
+			// Started or stopped elsewhere (e.g. the web UI) since the last
+			// refresh: the container is already where the action would put it
+			m.logger.Info("container already in requested state", "action", msg.action, "containerID", msg.id)
+			m.setSuccess(fmt.Sprintf("Container already %s", actionNames[msg.action]))
+			return m, m.refreshContainers()
+		}
 		if msg.err != nil {
 			m.logger.Error("container action failed", "action", msg.action, "containerID", msg.id, "error", msg.err)
 			m.setError(fmt.Sprintf("Failed to %s container", msg.action), msg.err)
@@ -620,15 +1013,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.logger.Info("container action completed", "action", msg.action, "containerID", msg.id)
-		actionNames := map[string]string{
-			"create":  "created",
-			"start":   "started",
-			"stop":    "stopped",
-			"destroy": "destroyed",
-		}
 		m.setSuccess(fmt.Sprintf("Container %s", actionNames[msg.action]))
 		return m, m.refreshContainers()
 
+	case pruneResultMsg:
+		if msg.err != nil {
+			m.logger.Error("prune failed", "removed", len(msg.removed), "error", msg.err)
+			m.setError(fmt.Sprintf("Prune failed (%d removed)", len(msg.removed)), msg.err)
+			return m, m.refreshContainers()
+		}
+		m.logger.Info("prune completed", "removed", len(msg.removed))
+		m.setSuccess(fmt.Sprintf("Pruned %d stopped containers", len(msg.removed)))
+		return m, m.refreshContainers()
+
+	case proxyCertsRegeneratedMsg:
+		if msg.err != nil {
+			m.logger.Error("proxy certificate regeneration failed", "name", msg.name, "error", msg.err)
+			m.setError("Failed to regenerate proxy certificates", msg.err)
+			return m, m.refreshContainers()
+		}
+		m.logger.Info("proxy certificates regenerated", "name", msg.name)
+		m.setSuccess("Regenerated proxy certificates for " + msg.name)
+		return m, m.refreshContainers()
+
+	case allowlistUpdatedMsg:
+		if msg.err != nil {
+			m.logger.Error("allowlist update failed", "name", msg.name, "error", msg.err)
+			m.setError("Failed to update allowlist for "+msg.name, msg.err)
+			return m, nil
+		}
+		m.logger.Info("allowlist updated", "name", msg.name)
+		m.setSuccess("Updated allowlist for " + msg.name)
+		return m, m.fetchIsolationInfo(msg.containerID)
+
+	case sessionsDuplicatedMsg:
+		if msg.err != nil {
+			m.logger.Error("session layout duplication failed", "source", msg.source, "created", msg.created, "error", msg.err)
+			m.setError(fmt.Sprintf("Duplicated %d sessions from %s with errors", msg.created, msg.source), msg.err)
+			return m, m.refreshAllSessions()
+		}
+		m.setSuccess(fmt.Sprintf("Duplicated %d sessions from %s", msg.created, msg.source))
+		return m, m.refreshAllSessions()
+
+	case containerLogsExportedMsg:
+		if msg.err != nil {
+			m.logger.Error("container log export failed", "name", msg.name, "error", msg.err)
+			m.setError("Failed to save logs for "+msg.name, msg.err)
+			return m, nil
+		}
+		m.setSuccess("Saved logs for " + msg.name + " to " + msg.path)
+		return m, nil
+
+	case clipboardCopiedMsg:
+		if msg.err != nil {
+			// Headless systems have no clipboard; not worth an error state
+			m.logger.Warn("clipboard copy failed", "error", msg.err)
+			m.statusLevel = StatusInfo
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.setSuccess(cmp.Or(msg.success, "Copied"))
+		}
+		return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return clearStatusMsg{}
+		})
+
+	case sessionAttachDoneMsg:
+		if msg.err != nil {
+			m.logger.Error("session attach failed", "session", msg.session, "error", msg.err)
+			m.setError(fmt.Sprintf("Attach to %s failed", msg.session), msg.err)
+		}
+		// The session may have ended or changed while attached
+		return m, m.refreshContainers()
+
 	case vscodeLaunchMsg:
 		if msg.err != nil {
 			m.logger.Error("VS Code launch failed", "error", msg.err)
@@ -639,14 +1095,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tickMsg:
-		// Periodic refresh
+		// Periodic refresh; a paused or superseded tick chain ends here
+		if m.refreshPaused || msg.gen != m.tickGen {
+			return m, nil
+		}
 		m.logger.Debug("periodic refresh triggered")
 		cmds := []tea.Cmd{
-			m.refreshContainers(),
-			m.rescanProjects(),
+			m.refreshContainersInBackground(),
 			m.tick(),
 			m.refreshAllSessions(),
 		}
+		if !m.projectWatch {
+			cmds = append(cmds, m.rescanProjects())
+		}
 		return m, tea.Batch(cmds...)
 
 	case sessionActionMsg:
@@ -663,6 +1124,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Refresh sessions after action
 		return m, m.refreshSessions()
 
+	case worktreeBaseVerifiedMsg:
+		// Ignore a result for a form that was closed meanwhile; one that was
+		// edited waits for Enter again
+		if !m.worktreeFormVerifying || m.worktreeFormProject == nil || m.worktreeFormProject.Path != msg.projectPath {
+			return m, nil
+		}
+		m.worktreeFormVerifying = false
+		if worktree.NormalizeName(m.worktreeFormName) != msg.name || strings.TrimSpace(m.worktreeFormBase) != msg.base {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.worktreeFormError = "unknown base ref"
+			return m, nil
+		}
+		m.resetWorktreeForm()
+		cmd := m.setLoading("Creating worktree " + msg.name + "...")
+		return m, tea.Batch(cmd, m.createWorktree(msg.projectPath, msg.name, msg.base))
+
 	case worktreeActionMsg:
 		if msg.err != nil {
 			m.logger.Error("worktree action failed", "action", msg.action, "name", msg.name, "error", msg.err)
@@ -677,10 +1156,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.startWorktreeContainer(msg.projectPath, msg.name),
 			)
 		}
+		if msg.action == "prune" {
+			m.setSuccess(fmt.Sprintf("Pruned stale worktrees of %s", msg.name))
+			return m, m.rescanProjects()
+		}
 		// destroy
 		m.setSuccess(fmt.Sprintf("Worktree removed: %s", msg.name))
 		return m, m.rescanProjects()
 
+	case worktreeChangesMsg:
+		if msg.err != nil {
+			// Let git report the problem if the removal goes ahead
+			m.logger.Warn("worktree status check failed", "name", msg.name, "error", msg.err)
+		}
+		return m.confirmWorktreeRemoval(msg.name, msg.changedFiles)
+
+	case worktreeProgressMsg:
+		if msg.step.Status == "started" {
+			cmd := m.setLoading(fmt.Sprintf("Starting container for %s: %s...", msg.name, msg.step.Message))
+			return m, tea.Batch(cmd, waitForWorktreeProgress(msg.updates))
+		}
+		return m, waitForWorktreeProgress(msg.updates)
+
 	case worktreeContainerMsg:
 		m.clearPendingWorktree(msg.path)
 		if msg.err != nil {
@@ -693,12 +1190,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.setSuccess(fmt.Sprintf("Worktree container started: %s", msg.name))
 		return m, m.refreshContainers()
 
+	case configReloadedMsg:
+		return m, m.applyConfigReload(msg)
+
 	case projectsRefreshedMsg:
 		m.discoveredProjects = msg.projects
 		m.rebuildTreeItems()
 		m.syncSelectionFromTree()
 		return m, m.refreshContainers()
 
+	case projectWatchStoppedMsg:
+		m.projectWatch = false
+		return m, m.rescanProjects()
+
 	case events.WebListenURLMsg:
 		m.listenURLs = append(m.listenURLs, msg.URL)
 		return m, nil
@@ -707,6 +1211,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.listenURLs = append(m.listenURLs, msg.URL)
 		return m, nil
 
+	case events.TailscaleStatusMsg:
+		m.tailscaleStatus = msg.Status
+		return m, nil
+
 	case events.WebSessionActionMsg:
 		return m, m.refreshAllSessions()
 
@@ -735,17 +1243,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.sessionViewOpen {
 			m.syncSelectionFromTree()
 		}
+		return m, m.refreshExpandedWindows()
+
+	case windowsLoadedMsg:
+		if msg.err != nil {
+			m.logger.Error("window listing failed", "containerID", msg.containerID, "session", msg.sessionName, "error", msg.err)
+			return m, nil
+		}
+		if m.sessionWindows == nil {
+			m.sessionWindows = make(map[string][]tmux.Window)
+		}
+		m.sessionWindows[sessionKey(msg.containerID, msg.sessionName)] = msg.windows
+		m.rebuildTreePreservingSelection()
 		return m, nil
 
+	case containerLogLinesMsg:
+		return m, m.handleContainerLogLines(msg)
+
 	case logEntriesMsg:
 		for _, entry := range msg.entries {
 			m.addLogEntry(entry)
 		}
+		if msg.dropped > m.logsDropped {
+			m.logsDropped = msg.dropped
+		}
 		if m.logPanelOpen && m.logReady {
 			m.updateLogViewportContent()
 		}
 		// Update selectedLogIndex if auto-scrolling
-		if m.logAutoScroll {
+		if m.logFollowing() {
 			entries := m.filteredLogEntries()
 			if len(entries) > 0 {
 				m.selectedLogIndex = len(entries) - 1
@@ -772,6 +1298,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update cached isolation info if it's still for the selected container
 		if m.selectedContainer != nil && m.selectedContainer.ID == msg.containerID {
 			m.cachedIsolationInfo = msg.info
+			m.cachedPorts = msg.ports
 			// Refresh detail viewport to show the new info
 			if m.detailReady && m.detailPanelOpen {
 				m.updateDetailViewportContent()
@@ -779,7 +1306,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case formPreviewMsg:
+		if !m.formOpen || m.formSubmitting {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.formError = "Preview failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.openFormPreview(msg.result)
+		return m, nil
+
 	case formProgressMsg:
+		// A create the form no longer shows (canceled, then another
+		// submitted): keep draining it so its completion still refreshes
+		if msg.ch != m.formProgressChan {
+			return m, waitForProgress(msg.ch, msg.name)
+		}
 		// Handle individual progress update
 		switch msg.step.Status {
 		case "started":
@@ -792,19 +1335,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.formCurrentStep = ""
 		}
 		// Continue waiting for more progress
-		return m, waitForProgress(m.formProgressChan, "")
+		return m, waitForProgress(msg.ch, msg.name)
 
 	case formCreationDoneMsg:
-		// Clear pending operation
+		// The form was closed mid-create (and maybe another create
+		// submitted since): nothing to show, but the list may have changed.
+		// The channel is never closed here: its create may still send.
+		if msg.ch != m.formProgressChan || !m.formSubmitting {
+			if !m.formSubmitting || msg.id != strings.TrimSpace(m.formContainerName) {
+				m.clearPending(msg.id)
+			}
+			m.logger.Info("container creation canceled", "name", msg.id, "error", msg.err)
+			return m, m.refreshContainers()
+		}
 		m.clearPending(msg.id)
-
-		// Close the progress channel
-		if m.formProgressChan != nil {
-			close(m.formProgressChan)
-			m.formProgressChan = nil
+		m.formProgressChan = nil
+		if m.formCancel != nil {
+			m.formCancel()
+			m.formCancel = nil
 		}
 
 		// Handle completion
+		if errors.Is(msg.err, container.ErrContainerExists) {
+			m.logger.Warn("container creation refused", "error", msg.err)
+			m.formExists = true
+			m.addFormStatusStep(false, "Skipped: "+msg.err.Error())
+			return m, m.finishFormSubmission(false)
+		}
 		if msg.err != nil {
 			m.logger.Error("container creation failed", "error", msg.err)
 			m.addFormStatusStep(false, "Creation failed: "+msg.err.Error())
@@ -844,6 +1401,7 @@ func (m Model) startContainer(id string) tea.Cmd {
 		defer cancel()
 
 		err := m.manager.StartWithCompose(ctx, id)
+		m.audit.Record("container.start", id, audit.SourceTUI, err)
 		return containerActionMsg{action: "start", id: id, err: err}
 	}
 }
@@ -855,37 +1413,152 @@ func (m Model) stopContainer(id string) tea.Cmd {
 		defer cancel()
 
 		err := m.manager.StopWithCompose(ctx, id)
+		m.audit.Record("container.stop", id, audit.SourceTUI, err)
 		return containerActionMsg{action: "stop", id: id, err: err}
 	}
 }
 
-// destroyContainer returns a command to destroy a container.
+// destroyContainer returns a command to destroy a container. A protected one
+// only gets here after its name was typed in the confirm dialog, so the
+// manager's do-not-delete refusal is overridden.
 func (m Model) destroyContainer(id string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := m.manager.DestroyWithCompose(ctx, id)
+		err := m.manager.DestroyWithOptions(ctx, id, container.DestroyOptions{Purge: true, Force: true})
+		m.audit.Record("container.destroy", id, audit.SourceTUI, err)
 		return containerActionMsg{action: "destroy", id: id, err: err}
 	}
 }
 
-// launchVSCode returns a command that launches VS Code attached to a container.
-func (m Model) launchVSCode(containerID, workspacePath string) tea.Cmd {
+// confirmDestroyWord is typed to confirm destroying more containers than
+// confirm.destroy_threshold at once.
+const confirmDestroyWord = "destroy"
+
+// pruneCandidateCount returns how many containers a prune would destroy,
+// counted by the manager so the gate matches what Prune does.
+func (m Model) pruneCandidateCount() int {
+	return len(m.manager.PruneCandidates())
+}
+
+// pruneContainers returns a command that destroys all stopped managed containers.
+func (m Model) pruneContainers() tea.Cmd {
 	return func() tea.Msg {
-		uri := GenerateVSCodeURI(containerID, workspacePath)
-		cmd := exec.Command("code", "--folder-uri", uri)
-		err := cmd.Start()
-		return vscodeLaunchMsg{err: err}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		removed, err := m.manager.Prune(ctx)
+		m.audit.Record("container.prune", strings.Join(removed, ","), audit.SourceTUI, err)
+		return pruneResultMsg{removed: removed, err: err}
 	}
 }
 
-// handleFormKey processes key events when the form is open.
-func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// exportContainerLogs returns a command that saves the container's last
+// container.DefaultLogTail log lines to a file in the data dir.
+func (m Model) exportContainerLogs(id, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		path, err := m.manager.ExportLogs(ctx, id, container.DefaultLogTail)
+		return containerLogsExportedMsg{name: name, path: path, err: err}
+	}
+}
+
+// regenerateProxyCerts returns a command that regenerates the proxy CA for the
+// containers of a project and re-installs it.
+func (m Model) regenerateProxyCerts(name, projectPath string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		err := m.manager.RegenerateProxyCerts(ctx, projectPath)
+		return proxyCertsRegeneratedMsg{name: name, err: err}
+	}
+}
+
+// duplicateSessions returns a command that recreates the source container's
+// sessions in the target containers.
+func (m Model) duplicateSessions(sourceID, sourceName string, targetIDs []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		created, err := m.manager.DuplicateSessions(ctx, sourceID, targetIDs)
+		return sessionsDuplicatedMsg{source: sourceName, created: created, err: err}
+	}
+}
+
+// updateAllowlist returns a command that applies an edited proxy allowlist to
+// a container and restarts its proxy.
+func (m Model) updateAllowlist(containerID, name string, domains []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := m.manager.UpdateAllowlist(ctx, containerID, domains)
+		return allowlistUpdatedMsg{containerID: containerID, name: name, err: err}
+	}
+}
+
+// launchVSCode returns a command that launches VS Code attached to a container.
+func (m Model) launchVSCode(containerID, workspacePath string) tea.Cmd {
+	return runVSCode(VSCodeArgs(containerID, workspacePath))
+}
+
+// runVSCode runs a code CLI invocation with tea.ExecProcess (the CLI hands off
+// to the editor and exits, so the TUI resumes right away), reporting the
+// result as a vscodeLaunchMsg.
+func runVSCode(args []string) tea.Cmd {
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return vscodeLaunchMsg{err: err}
+	})
+}
+
+// copyToClipboard returns a command that copies s to the system clipboard,
+// reported by a clipboardCopiedMsg.
+func (m Model) copyToClipboard(s string) tea.Cmd {
+	return m.copyToClipboardAs(s, "")
+}
+
+// copyToClipboardAs is copyToClipboard with success as the status shown once
+// copied (e.g. how many attach commands).
+func (m Model) copyToClipboardAs(s, success string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardCopiedMsg{err: clipboard.WriteAll(s), success: success}
+	}
+}
+
+// attachSession suspends the TUI and attaches to the selected session with
+// tea.ExecProcess, resuming on detach. Returns nil when no session is
+// selected or the container is not running.
+func (m *Model) attachSession() tea.Cmd {
+	session := m.SelectedSession()
+	if session == nil || m.selectedContainer == nil {
+		return nil
+	}
+	if m.selectedContainer.State != container.StateRunning {
+		m.setError("Cannot attach: container is not running", nil)
+		return nil
+	}
+	args := AttachArgs(m.selectedContainer, session.Name, m.manager.RuntimePath())
+	name := session.Name
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), m.manager.RuntimeEnv()...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return sessionAttachDoneMsg{session: name, err: err}
+	})
+}
+
+// handleFormKey processes key events when the form is open.
+func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// If form is submitting, only allow Escape to cancel
 	if m.formSubmitting {
 		if msg.Type == tea.KeyEscape {
-			// Cancel the submission - the container creation is async so we just close
+			// Cancel the submission: resetForm cancels the create's context,
+			// which kills compose up and tears down what it started
+			m.logger.Info("canceling container creation", "name", m.formContainerName)
 			m.resetForm()
 			return m, nil
 		}
@@ -893,34 +1566,68 @@ func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// If form is completed (showing result), Enter or Escape closes it
+	// If form is completed (showing result), Enter or Escape closes it.
+	// After a refused create, f retries it with Force.
 	if m.formCompleted {
 		if msg.Type == tea.KeyEnter || msg.Type == tea.KeyEscape {
 			m.resetForm()
 		}
+		if m.formExists && msg.String() == "f" {
+			m.logger.Info("creating container despite existing one", "name", m.formContainerName)
+			m.formForce = true
+			return m, m.submitForm()
+		}
 		return m, nil
 	}
 
+	// While the preview is shown, keys scroll it; Esc or ctrl+p goes back
+	if m.formPreviewOpen {
+		if msg.Type == tea.KeyEscape || msg.Type == tea.KeyCtrlP {
+			m.formPreviewOpen = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.formPreview, cmd = m.formPreview.Update(msg)
+		return m, cmd
+	}
+
 	// Handle special keys by type first
 	switch msg.Type {
 	case tea.KeyEscape:
 		m.resetForm()
 		return m, nil
 
+	case tea.KeyCtrlP:
+		// Preview what the create would generate, without creating anything
+		if m.formClone {
+			m.formError = "Preview is not available when cloning"
+			return m, nil
+		}
+		if errMsg := m.validateForm(); errMsg != "" {
+			m.formError = errMsg
+			return m, nil
+		}
+		m.formError = ""
+		return m, m.previewCreate()
+
 	case tea.KeyEnter:
 		if errMsg := m.validateForm(); errMsg != "" {
 			m.formError = errMsg
 			return m, nil
 		}
-		// Submit the form - keep it open with progress
-		containerName := m.formContainerName
-		m.logger.Info("creating container", "name", containerName)
-		m.setPending(containerName, "create")
-		spinnerCmd := m.startFormSubmission()
-		// Create the progress channel and store it in the model
-		m.formProgressChan = make(chan formProgressMsg, 20)
-		createCmd := m.createContainerWithProgress()
-		return m, tea.Batch(spinnerCmd, createCmd)
+		if m.formClone {
+			m.logger.Info("cloning and creating container", "url", m.formProjectPath, "name", m.formContainerName)
+		} else {
+			m.logger.Info("creating container", "name", m.formContainerName)
+		}
+		return m, m.submitForm()
+
+	case tea.KeyCtrlO:
+		// Toggle between replacing and keeping the project's devcontainer.json
+		if m.hasOwnDevcontainerJSON() {
+			m.formKeepDevcontainer = !m.formKeepDevcontainer
+		}
+		return m, nil
 
 	case tea.KeyTab:
 		// Cycle through fields
@@ -928,75 +1635,83 @@ func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyUp:
-		// Template selection
-		if m.formFocusedField == FieldTemplate && m.formTemplateIdx > 0 {
-			m.formTemplateIdx--
+		// Template selection within the filtered templates
+		if m.formFocusedField == FieldTemplate {
+			m.moveTemplateSelection(-1)
 		}
 		return m, nil
 
 	case tea.KeyDown:
-		// Template selection
-		if m.formFocusedField == FieldTemplate && m.formTemplateIdx < len(m.templates)-1 {
-			m.formTemplateIdx++
+		// Template selection within the filtered templates
+		if m.formFocusedField == FieldTemplate {
+			m.moveTemplateSelection(1)
 		}
 		return m, nil
 
 	case tea.KeyBackspace:
 		// Delete character from focused text field
-		switch m.formFocusedField {
-		case FieldProjectPath:
-			if len(m.formProjectPath) > 0 {
-				m.formProjectPath = m.formProjectPath[:len(m.formProjectPath)-1]
-			}
-		case FieldContainerName:
-			if len(m.formContainerName) > 0 {
-				m.formContainerName = m.formContainerName[:len(m.formContainerName)-1]
+		m.formError = ""
+		m.editFormField(func(s string) string {
+			if len(s) > 0 {
+				return s[:len(s)-1]
 			}
-		}
+			return s
+		})
 		return m, nil
 
 	case tea.KeyRunes:
 		// Clear any previous error when typing
 		m.formError = ""
 		// Text input for focused field
-		switch m.formFocusedField {
-		case FieldProjectPath:
-			m.formProjectPath += string(msg.Runes)
-		case FieldContainerName:
-			m.formContainerName += string(msg.Runes)
-		}
+		m.editFormField(func(s string) string { return s + string(msg.Runes) })
 		return m, nil
 	}
 
 	// Handle any other keys that have runes (fallback for text input)
 	if len(msg.Runes) > 0 {
 		m.formError = ""
-		switch m.formFocusedField {
-		case FieldProjectPath:
-			m.formProjectPath += string(msg.Runes)
-		case FieldContainerName:
-			m.formContainerName += string(msg.Runes)
-		}
+		m.editFormField(func(s string) string { return s + string(msg.Runes) })
 		return m, nil
 	}
 
 	return m, nil
 }
 
+// submitForm starts creating the container described by the form, keeping the
+// form open to show progress.
+func (m *Model) submitForm() tea.Cmd {
+	m.setPending(m.formContainerName, "create")
+	spinnerCmd := m.startFormSubmission()
+	// Create the progress channel and store it in the model
+	m.formProgressChan = make(chan formProgressMsg, 20)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	m.formCancel = cancel
+	createCmd := m.createContainerWithProgress(ctx)
+	return tea.Batch(spinnerCmd, createCmd)
+}
+
 // formProgressMsg delivers a single progress update during container creation.
+// err carries the create's error with the "error" step so its type survives.
+// ch and name identify the create that sent it (set by waitForProgress).
 type formProgressMsg struct {
 	step container.ProgressStep
+	err  error
+	ch   chan formProgressMsg
+	name string
 }
 
-// formCreationDoneMsg is sent when container creation completes.
+// formCreationDoneMsg is sent when container creation completes. ch is the
+// create's progress channel; it is stale unless it is m.formProgressChan.
 type formCreationDoneMsg struct {
 	err error
 	id  string
+	ch  chan formProgressMsg
 }
 
 // createContainerWithProgress returns a command to create a container with progress reporting.
-// The caller must set m.formProgressChan before calling this function.
-func (m Model) createContainerWithProgress() tea.Cmd {
+// The caller must set m.formProgressChan before calling this function; canceling
+// ctx aborts the create (see resetForm).
+func (m Model) createContainerWithProgress(ctx context.Context) tea.Cmd {
 	templateName := ""
 	if len(m.templates) > m.formTemplateIdx {
 		templateName = m.templates[m.formTemplateIdx].Name
@@ -1004,19 +1719,23 @@ func (m Model) createContainerWithProgress() tea.Cmd {
 	// Trim whitespace from form inputs to avoid invalid container names
 	projectPath := strings.TrimSpace(m.formProjectPath)
 	containerName := strings.TrimSpace(m.formContainerName)
+	mounts := container.SplitMounts(m.formMounts)
 
 	// Capture the channel for use in goroutine
 	progressChan := m.formProgressChan
+	force := m.formForce
+	useExisting := m.formKeepDevcontainer
 
-	// Start container creation in background
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-		defer cancel()
-
-		_, err := m.manager.CreateWithCompose(ctx, container.CreateOptions{
+	// Container creation runs in the background once the command runs
+	clone := m.formClone
+	create := func() {
+		opts := container.CreateOptions{
 			ProjectPath: projectPath,
 			Template:    templateName,
 			Name:        containerName,
+			Force:       force,
+			ExtraMounts: mounts,
+			UseExisting: useExisting,
 			OnProgress: func(step container.ProgressStep) {
 				// Send progress to channel (non-blocking)
 				select {
@@ -1024,7 +1743,16 @@ func (m Model) createContainerWithProgress() tea.Cmd {
 				default:
 				}
 			},
-		})
+		}
+		var err error
+		if clone {
+			// projectPath holds the git URL in the clone variant
+			_, err = m.manager.CloneAndCreate(ctx, projectPath, opts)
+			m.audit.Record("container.clone", projectPath, audit.SourceTUI, err)
+		} else {
+			_, err = m.manager.CreateWithCompose(ctx, opts)
+			m.audit.Record("container.create", cmp.Or(containerName, projectPath), audit.SourceTUI, err)
+		}
 
 		// Send completion or error message (mutually exclusive)
 		if err != nil {
@@ -1033,7 +1761,7 @@ func (m Model) createContainerWithProgress() tea.Cmd {
 				Step:    "error",
 				Status:  "failed",
 				Message: err.Error(),
-			}}:
+			}, err: err}:
 			default:
 			}
 		} else {
@@ -1045,13 +1773,18 @@ func (m Model) createContainerWithProgress() tea.Cmd {
 			default:
 			}
 		}
-	}()
+	}
 
-	// Return command to wait for first progress message
-	return waitForProgress(progressChan, containerName)
+	// Start the create, then wait for its first progress message
+	wait := waitForProgress(progressChan, containerName)
+	return func() tea.Msg {
+		go create()
+		return wait()
+	}
 }
 
-// waitForProgress returns a command that waits for the next progress message.
+// waitForProgress returns a command that waits for the next progress message
+// of the create whose channel is progressChan, tagging it with that channel.
 func waitForProgress(progressChan chan formProgressMsg, containerName string) tea.Cmd {
 	return func() tea.Msg {
 		if progressChan == nil {
@@ -1061,17 +1794,21 @@ func waitForProgress(progressChan chan formProgressMsg, containerName string) te
 		msg, ok := <-progressChan
 		if !ok {
 			// Channel closed
-			return formCreationDoneMsg{id: containerName, err: nil}
+			return formCreationDoneMsg{id: containerName, err: nil, ch: progressChan}
 		}
 
 		// Check for completion signals
 		if msg.step.Step == "done" {
-			return formCreationDoneMsg{id: containerName, err: nil}
+			return formCreationDoneMsg{id: containerName, err: nil, ch: progressChan}
 		}
 		if msg.step.Step == "error" {
-			return formCreationDoneMsg{id: containerName, err: fmt.Errorf("%s", msg.step.Message)}
+			if msg.err != nil {
+				return formCreationDoneMsg{id: containerName, err: msg.err, ch: progressChan}
+			}
+			return formCreationDoneMsg{id: containerName, err: fmt.Errorf("%s", msg.step.Message), ch: progressChan}
 		}
 
+		msg.ch, msg.name = progressChan, containerName
 		return msg
 	}
 }
@@ -1104,49 +1841,72 @@ func (m Model) fetchIsolationInfo(containerID string) tea.Cmd {
 			return isolationInfoMsg{info: nil, containerID: containerID}
 		}
 
-		return isolationInfoMsg{info: info, containerID: containerID}
+		// Ports are best effort: a failed inspect just shows none
+		ports, _ := m.manager.GetPorts(ctx, containerID)
+		return isolationInfoMsg{info: info, ports: ports, containerID: containerID}
 	}
 }
 
 // Note: TUI calls worktree functions directly (unlike web which uses an interface).
 // This is intentional — Bubbletea's architecture makes interface injection less practical here.
 
-// createWorktree returns a command to create a worktree.
-func (m Model) createWorktree(projectPath, name string) tea.Cmd {
+// verifyWorktreeBase returns a command that checks the worktree form's base
+// ref resolves in projectPath.
+func verifyWorktreeBase(projectPath, name, base string) tea.Cmd {
 	return func() tea.Msg {
-		_, err := worktree.Create(projectPath, name)
+		err := worktree.VerifyRef(projectPath, base)
+		return worktreeBaseVerifiedMsg{projectPath: projectPath, name: name, base: base, err: err}
+	}
+}
+
+// createWorktree returns a command to create a worktree branching from base (HEAD if empty).
+func (m Model) createWorktree(projectPath, name, base string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := worktree.Create(projectPath, name, base)
+		m.audit.Record("worktree.create", projectPath+"/"+name, audit.SourceTUI, err)
 		return worktreeActionMsg{action: "create", name: name, projectPath: projectPath, err: err}
 	}
 }
 
 // destroyWorktree returns a command to destroy a worktree and its container (if any).
-func (m Model) destroyWorktree(projectPath, name string) tea.Cmd {
+func (m Model) destroyWorktree(projectPath, name string, force bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		defer cancel()
-		err := worktree.DestroyWorktreeWithContainer(ctx, m.manager, projectPath, name, nil)
+		err := worktree.DestroyWorktreeWithContainer(ctx, m.manager, projectPath, name, nil, force)
+		m.audit.Record("worktree.delete", projectPath+"/"+name, audit.SourceTUI, err)
 		return worktreeActionMsg{action: "destroy", name: name, projectPath: projectPath, err: err}
 	}
 }
 
-// startWorktreeContainer returns a command to start a container for a worktree.
-func (m Model) startWorktreeContainer(projectPath, name string) tea.Cmd {
+// checkWorktreeChanges returns a command that lists a worktree's uncommitted
+// changes ahead of the removal confirm dialog.
+func (m Model) checkWorktreeChanges(projectPath, name string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-		defer cancel()
+		files, err := worktree.ChangedFiles(projectPath, name)
+		return worktreeChangesMsg{name: name, changedFiles: files, err: err}
+	}
+}
 
-		// Determine template — use the project's existing template
-		templateName := container.FindTemplateForProject(m.manager.List(), projectPath)
+// pruneWorktrees returns a command that runs git worktree prune for a project.
+func (m Model) pruneWorktrees(projectPath, name string) tea.Cmd {
+	return func() tea.Msg {
+		err := worktree.Prune(projectPath)
+		return worktreeActionMsg{action: "prune", name: name, projectPath: projectPath, err: err}
+	}
+}
 
-		opts := container.CreateOptions{
-			ProjectPath: projectPath, // project root, NOT worktree path
-			Template:    templateName,
-			Name:        container.SanitizeComposeName(filepath.Base(projectPath) + "-" + name),
-		}
-		_, err := m.manager.CreateWithCompose(ctx, opts)
-		wtPath := worktree.WorktreeDir(projectPath, name)
-		return worktreeContainerMsg{name: name, path: wtPath, err: err}
+// startWorktreeContainer returns a command to start a container for a worktree.
+func (m Model) startWorktreeContainer(projectPath, name string) tea.Cmd {
+	// Determine template — use the project's existing template
+	templateName := container.FindTemplateForProject(m.manager.List(), projectPath)
+
+	opts := container.CreateOptions{
+		ProjectPath: projectPath, // project root, NOT worktree path
+		Template:    templateName,
+		Name:        worktree.ComposeName(projectPath, name),
 	}
+	return m.createWorktreeContainer(opts, name, worktree.WorktreeDir(projectPath, name), 10*time.Minute)
 }
 
 // startMissingWorktreeContainer returns a command to start a container for a
@@ -1154,44 +1914,127 @@ func (m Model) startWorktreeContainer(projectPath, name string) tea.Cmd {
 // (used during worktree creation where project root + name are available),
 // this takes the pre-built worktree path from the tree item.
 func (m Model) startMissingWorktreeContainer(wtPath, name string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
-		defer cancel()
+	// Extract project root from worktree path.
+	// For the "main" worktree, wtPath IS the project root.
+	// For other worktrees, wtPath is <projectPath>/.worktrees/<name>, where a
+	// slash-style name spans several directories.
+	projectPath := wtPath
+	if name != "main" {
+		projectPath = worktree.ProjectPathFromDir(wtPath, name)
+	}
 
-		// Extract project root from worktree path.
-		// For the "main" worktree, wtPath IS the project root.
-		// For other worktrees, wtPath is <projectPath>/.worktrees/<name>.
-		projectPath := wtPath
-		if name != "main" {
-			projectPath = filepath.Dir(filepath.Dir(wtPath))
-		}
+	templateName := container.FindTemplateForProject(m.manager.List(), projectPath)
 
-		templateName := container.FindTemplateForProject(m.manager.List(), projectPath)
+	// Main worktree uses bare project name; other worktrees get the suffix.
+	composeName := container.SanitizeComposeName(filepath.Base(projectPath))
+	if name != "main" {
+		composeName = worktree.ComposeName(projectPath, name)
+	}
+	opts := container.CreateOptions{
+		ProjectPath: projectPath,
+		Template:    templateName,
+		Name:        composeName,
+	}
+	return m.createWorktreeContainer(opts, name, wtPath, 300*time.Second)
+}
 
-		// Main worktree uses bare project name; other worktrees get the suffix.
-		composeName := container.SanitizeComposeName(filepath.Base(projectPath))
-		if name != "main" {
-			composeName = container.SanitizeComposeName(filepath.Base(projectPath) + "-" + name)
+// createWorktreeContainer runs CreateWithCompose in the background and streams
+// its OnProgress steps as worktreeProgressMsg, followed by a final
+// worktreeContainerMsg.
+func (m Model) createWorktreeContainer(opts container.CreateOptions, name, wtPath string, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		updates := make(chan tea.Msg, 16)
+		opts.OnProgress = func(step container.ProgressStep) {
+			// Progress is best-effort (non-blocking); the final result is not.
+			select {
+			case updates <- worktreeProgressMsg{name: name, step: step, updates: updates}:
+			default:
+			}
 		}
-		opts := container.CreateOptions{
-			ProjectPath: projectPath,
-			Template:    templateName,
-			Name:        composeName,
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			_, err := m.manager.CreateWithCompose(ctx, opts)
+			m.audit.Record("container.create", opts.Name, audit.SourceTUI, err)
+			updates <- worktreeContainerMsg{name: name, path: wtPath, err: err}
+			close(updates)
+		}()
+
+		return waitForWorktreeProgress(updates)()
+	}
+}
+
+// waitForWorktreeProgress returns a command that waits for the next message
+// from a worktree container start.
+func waitForWorktreeProgress(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
 		}
-		_, err := m.manager.CreateWithCompose(ctx, opts)
-		return worktreeContainerMsg{name: name, path: wtPath, err: err}
+		return msg
 	}
 }
 
+// applyConfigReload applies the live-reloadable fields of a reloaded config:
+// templates, scan paths, theme, log level, refresh interval (from the next
+// tick), and the confirm policy. Returns a command that re-runs
+// project discovery so the tree reflects the new scan paths.
+func (m *Model) applyConfigReload(msg configReloadedMsg) tea.Cmd {
+	m.cfg.ScanPaths = msg.cfg.ScanPaths
+	m.cfg.LogLevel = msg.cfg.LogLevel
+	m.cfg.Confirm = msg.cfg.Confirm
+	m.cfg.AttachCommandTemplate = msg.cfg.AttachCommandTemplate
+	m.cfg.RefreshInterval = msg.cfg.RefreshInterval
+	if m.logManager != nil {
+		m.logManager.SetLevel(msg.cfg.LogLevel)
+	}
+
+	m.templates = msg.templates
+	if m.formTemplateIdx >= len(m.templates) {
+		m.formTemplateIdx = 0
+	}
+	m.manager.SetTemplates(msg.templates)
+
+	// Only a changed config theme replaces the active one, so a theme chosen
+	// with T survives reloads that leave theme alone
+	if msg.cfg.Theme != m.cfg.Theme {
+		m.cfg.Theme = msg.cfg.Theme
+		m.applyTheme(msg.cfg.Theme)
+	}
+	if msg.cfg.UnknownTheme != "" {
+		m.logger.Warn("unknown theme, using default", "theme", msg.cfg.UnknownTheme, "default", config.DefaultTheme)
+	}
+
+	m.logger.Info("config reloaded",
+		"templates", len(msg.templates),
+		"scan_paths", msg.cfg.ScanPaths,
+		"theme", msg.cfg.Theme,
+		"log_level", msg.cfg.LogLevel)
+	m.setSuccess("Config reloaded")
+
+	// rescanProjects is a no-op without scan paths, so clear stale projects here
+	if len(m.cfg.ResolveScanPaths()) == 0 {
+		m.discoveredProjects = nil
+		m.rebuildTreeItems()
+		m.syncSelectionFromTree()
+		return m.refreshContainers()
+	}
+	return m.rescanProjects()
+}
+
 // rescanProjects rescans all configured scan paths to update discovered projects and worktree lists.
+// It runs on every tick, so it uses the cached scan: only directories whose
+// mtime changed since the last rescan are re-read.
 func (m Model) rescanProjects() tea.Cmd {
 	return func() tea.Msg {
-		scanner := discovery.NewScanner()
 		paths := m.cfg.ResolveScanPaths()
 		if len(paths) == 0 {
 			return nil
 		}
-		projects := scanner.ScanAll(paths)
+		projects := m.scanner.ScanAllCached(paths)
 		return projectsRefreshedMsg{projects: projects}
 	}
 }
@@ -1232,15 +2075,22 @@ func (m Model) handleSessionViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.openSessionForm()
 		return m, nil
 
+	case "y":
+		// Copy the selected session's attach command
+		if cmd := m.AttachCommand(); cmd != "" {
+			return m, m.copyToClipboard(cmd)
+		}
+		return m, nil
+
+	case "a":
+		// Attach in place; the TUI resumes when the session is detached
+		return m, m.attachSession()
+
 	case "k":
-		// Kill selected session - show confirmation dialog
+		// Kill selected session (after confirmation, unless disabled)
 		session := m.SelectedSession()
 		if session != nil && m.selectedContainer != nil {
-			m.confirmOpen = true
-			m.confirmAction = "kill_session"
-			m.confirmTarget = session.Name
-			m.confirmMessage = fmt.Sprintf("Kill session '%s'?", session.Name)
-			return m, nil
+			return m.confirmOrRun(config.ConfirmKillSession, session.Name, fmt.Sprintf("Kill session '%s'?", session.Name))
 		}
 		return m, nil
 	}
@@ -1305,6 +2155,72 @@ func (m Model) handleSessionFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleTreeFilterKey processes key events while the tree filter input is open.
+// The tree is re-filtered on every keystroke; Enter keeps the filter and
+// returns to normal navigation, Escape clears it.
+func (m Model) handleTreeFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.treeFilterOpen = false
+		m.setTreeFilter("")
+		return m, nil
+
+	case tea.KeyEnter:
+		m.treeFilterOpen = false
+		return m, m.fetchIsolationInfoIfNeeded()
+
+	case tea.KeyUp:
+		m.moveTreeSelectionUp()
+		return m, m.fetchIsolationInfoIfNeeded()
+
+	case tea.KeyDown:
+		m.moveTreeSelectionDown()
+		return m, m.fetchIsolationInfoIfNeeded()
+
+	case tea.KeyBackspace:
+		if len(m.treeFilter) > 0 {
+			runes := []rune(m.treeFilter)
+			m.setTreeFilter(string(runes[:len(runes)-1]))
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.setTreeFilter(m.treeFilter + string(msg.Runes))
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleLogSearchKey processes key events while the log search input is open.
+// The log list is re-filtered on every keystroke; Enter keeps the search and
+// returns to log navigation, Escape clears it.
+func (m Model) handleLogSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.logSearchOpen = false
+		m.setLogSearch("")
+		return m, nil
+
+	case tea.KeyEnter:
+		m.logSearchOpen = false
+		return m, nil
+
+	case tea.KeyBackspace:
+		if len(m.logSearch) > 0 {
+			runes := []rune(m.logSearch)
+			m.setLogSearch(string(runes[:len(runes)-1]))
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.setLogSearch(m.logSearch + string(msg.Runes))
+		return m, nil
+	}
+
+	return m, nil
+}
+
 // sessionActionMsg is sent when a session action completes.
 type sessionActionMsg struct {
 	action      string
@@ -1320,6 +2236,7 @@ func (m Model) createSession(containerID, sessionName string) tea.Cmd {
 		defer cancel()
 
 		err := m.manager.CreateSession(ctx, containerID, sessionName)
+		m.audit.Record("session.create", containerID+"/"+sessionName, audit.SourceTUI, err)
 		return sessionActionMsg{
 			action:      "create",
 			containerID: containerID,
@@ -1336,6 +2253,7 @@ func (m Model) killSession(containerID, sessionName string) tea.Cmd {
 		defer cancel()
 
 		err := m.manager.KillSession(ctx, containerID, sessionName)
+		m.audit.Record("session.kill", containerID+"/"+sessionName, audit.SourceTUI, err)
 		return sessionActionMsg{
 			action:      "kill",
 			containerID: containerID,
@@ -1346,73 +2264,35 @@ func (m Model) killSession(containerID, sessionName string) tea.Cmd {
 }
 
 // handleConfirmKey processes key events when the confirmation dialog is open.
+// When a name must be typed (confirmName), Enter only confirms on an exact
+// match and y/n are ordinary input.
 func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEscape:
 		// Cancel the action
-		m.confirmOpen = false
-		m.confirmAction = ""
-		m.confirmTarget = ""
-		m.confirmMessage = ""
+		m.closeConfirm()
 		return m, nil
 
 	case tea.KeyEnter:
+		if m.confirmName != "" && m.confirmInput != m.confirmName {
+			return m, nil
+		}
 		// Confirm and execute the action
 		action := m.confirmAction
 		target := m.confirmTarget
+		m.closeConfirm()
+		return m.runConfirmedAction(action, target)
+	}
 
-		// Close dialog
-		m.confirmOpen = false
-		m.confirmAction = ""
-		m.confirmTarget = ""
-		m.confirmMessage = ""
-
-		// Execute the confirmed action
-		switch action {
-		case "destroy_container":
-			// Find the container to get its name for the loading message
-			var containerName string
-			for _, item := range m.containerList.Items() {
-				if ci, ok := item.(containerItem); ok && ci.container.ID == target {
-					containerName = ci.container.Name
-					break
-				}
-			}
-			m.logger.Info("destroying container", "containerID", target, "name", containerName)
-			m.setPending(target, "destroy")
-			cmd := m.setLoading("Destroying " + containerName + "...")
-			return m, tea.Batch(cmd, m.destroyContainer(target))
-
-		case "kill_session":
-			if m.selectedContainer != nil {
-				m.logger.Info("killing session", "containerID", m.selectedContainer.ID, "session", target)
-				return m, m.killSession(m.selectedContainer.ID, target)
-			}
-
-		case "destroy_worktree":
-			// Find the project path for this worktree
-			for _, item := range m.treeItems {
-				if item.Type == TreeItemWorktree && item.WorktreeName == target {
-					// Find parent project
-					projectPath := ""
-					for _, p := range m.discoveredProjects {
-						for _, wt := range p.Worktrees {
-							if wt.Branch == target {
-								projectPath = p.Path
-								break
-							}
-						}
-						if projectPath != "" {
-							break
-						}
-					}
-					if projectPath != "" {
-						cmd := m.setLoading("Removing worktree " + target + "...")
-						return m, tea.Batch(cmd, m.destroyWorktree(projectPath, target))
-					}
-					break
-				}
+	if m.confirmName != "" {
+		switch msg.Type {
+		case tea.KeyBackspace:
+			if len(m.confirmInput) > 0 {
+				runes := []rune(m.confirmInput)
+				m.confirmInput = string(runes[:len(runes)-1])
 			}
+		case tea.KeyRunes:
+			m.confirmInput += string(msg.Runes)
 		}
 		return m, nil
 	}
@@ -1431,12 +2311,144 @@ func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// confirmOrRun opens the confirmation dialog for a destructive action, or runs
+// it immediately when the confirm policy (cfg.Confirm) has it disabled.
+func (m Model) confirmOrRun(action, target, message string) (tea.Model, tea.Cmd) {
+	if m.cfg != nil && !m.cfg.Confirm.Requires(action) {
+		return m.runConfirmedAction(action, target)
+	}
+	m.openConfirm(action, target, message)
+	return m, nil
+}
+
+// openConfirm opens the confirmation dialog for action on target.
+func (m *Model) openConfirm(action, target, message string) {
+	m.confirmOpen = true
+	m.confirmAction = action
+	m.confirmTarget = target
+	m.confirmMessage = message
+	m.confirmName = ""
+	m.confirmInput = ""
+}
+
+// closeConfirm closes the confirmation dialog and clears its state.
+func (m *Model) closeConfirm() {
+	m.confirmOpen = false
+	m.confirmAction = ""
+	m.confirmTarget = ""
+	m.confirmMessage = ""
+	m.confirmName = ""
+	m.confirmInput = ""
+}
+
+// runConfirmedAction executes a destructive action once it has been confirmed
+// (or needs no confirmation).
+func (m Model) runConfirmedAction(action, target string) (tea.Model, tea.Cmd) {
+	switch action {
+	case config.ConfirmDestroyContainer:
+		// Find the container to get its name for the loading message
+		var containerName string
+		for _, item := range m.containerList.Items() {
+			if ci, ok := item.(containerItem); ok && ci.container.ID == target {
+				containerName = ci.container.Name
+				break
+			}
+		}
+		m.logger.Info("destroying container", "containerID", target, "name", containerName)
+		m.setPending(target, "destroy")
+		cmd := m.setLoading("Destroying " + containerName + "...")
+		return m, tea.Batch(cmd, m.destroyContainer(target))
+
+	case config.ConfirmPrune:
+		m.logger.Info("pruning stopped containers")
+		cmd := m.setLoading("Pruning stopped containers...")
+		return m, tea.Batch(cmd, m.pruneContainers())
+
+	case config.ConfirmKillSession:
+		if m.selectedContainer != nil {
+			m.logger.Info("killing session", "containerID", m.selectedContainer.ID, "session", target)
+			return m, m.killSession(m.selectedContainer.ID, target)
+		}
+
+	case config.ConfirmDeleteWorktree, confirmForceDeleteWorktree:
+		if projectPath := m.worktreeProjectPath(target); projectPath != "" {
+			force := action == confirmForceDeleteWorktree
+			m.logger.Info("removing worktree", "worktree", target, "force", force)
+			cmd := m.setLoading("Removing worktree " + target + "...")
+			return m, tea.Batch(cmd, m.destroyWorktree(projectPath, target, force))
+		}
+	}
+	return m, nil
+}
+
+// worktreeProjectPath returns the path of the discovered project owning the
+// worktree with the given branch, or "" if none does.
+func (m Model) worktreeProjectPath(branch string) string {
+	for _, p := range m.discoveredProjects {
+		for _, wt := range p.Worktrees {
+			if wt.Branch == branch {
+				return p.Path
+			}
+		}
+	}
+	return ""
+}
+
+// confirmWorktreeRemoval opens the confirm dialog for removing a worktree. A
+// dirty worktree always asks, listing its changed files, and is then removed
+// with --force.
+func (m Model) confirmWorktreeRemoval(name string, changedFiles []string) (tea.Model, tea.Cmd) {
+	if len(changedFiles) == 0 {
+		return m.confirmOrRun(config.ConfirmDeleteWorktree, name, fmt.Sprintf("Remove worktree '%s'?", name))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Worktree '%s' has %d uncommitted change(s):\n", name, len(changedFiles))
+	for i, f := range changedFiles {
+		if i == maxConfirmChangedFiles {
+			fmt.Fprintf(&b, "  … and %d more\n", len(changedFiles)-i)
+			break
+		}
+		fmt.Fprintf(&b, "  %s\n", f)
+	}
+	b.WriteString("Discard them and remove the worktree?")
+	return m.confirmOrRun(confirmForceDeleteWorktree, name, b.String())
+}
+
 // handleActionMenuKey processes key events when the action menu is open.
 func (m Model) handleActionMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEscape:
 		m.closeActionMenu()
 		return m, nil
+
+	case tea.KeyUp:
+		if m.actionMenuIdx > 0 {
+			m.actionMenuIdx--
+		}
+		return m, nil
+
+	case tea.KeyDown:
+		if m.actionMenuIdx < len(m.containerActions())-1 {
+			m.actionMenuIdx++
+		}
+		return m, nil
+	}
+
+	if msg.Type == tea.KeyEnter || msg.String() == "y" {
+		// Copy the highlighted command and close the menu so the status bar shows the result
+		if cmd := m.selectedActionCommand(); cmd != "" {
+			m.closeActionMenu()
+			return m, m.copyToClipboard(cmd)
+		}
+	}
+	if msg.String() == "o" {
+		// Run the highlighted action (e.g. open VS Code) instead of copying it
+		if args := m.selectedActionArgs(); args != nil {
+			m.closeActionMenu()
+			m.logger.Debug("running action", "command", args[0])
+			return m, runVSCode(args)
+		}
 	}
 	return m, nil
 }
@@ -1449,7 +2461,10 @@ func (m Model) handleWorktreeFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyEnter:
-		name := strings.TrimSpace(m.worktreeFormName)
+		if m.worktreeFormVerifying {
+			return m, nil
+		}
+		name := worktree.NormalizeName(m.worktreeFormName)
 		if name == "" {
 			m.worktreeFormError = "Worktree name is required"
 			return m, nil
@@ -1460,19 +2475,131 @@ func (m Model) handleWorktreeFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		project := m.worktreeFormProject
+		base := strings.TrimSpace(m.worktreeFormBase)
+		if base != "" {
+			// git may be slow on a large repository; resolve the ref in a
+			// command and create once it is known
+			m.worktreeFormVerifying = true
+			m.worktreeFormError = ""
+			return m, verifyWorktreeBase(project.Path, name, base)
+		}
 		m.resetWorktreeForm()
 		cmd := m.setLoading("Creating worktree " + name + "...")
-		return m, tea.Batch(cmd, m.createWorktree(project.Path, name))
+		return m, tea.Batch(cmd, m.createWorktree(project.Path, name, base))
+
+	case tea.KeyTab, tea.KeyShiftTab:
+		m.worktreeFormBaseFocused = !m.worktreeFormBaseFocused
+		return m, nil
 
 	case tea.KeyBackspace:
-		if len(m.worktreeFormName) > 0 {
-			m.worktreeFormName = m.worktreeFormName[:len(m.worktreeFormName)-1]
+		field := &m.worktreeFormName
+		if m.worktreeFormBaseFocused {
+			field = &m.worktreeFormBase
+		}
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
 		}
 		return m, nil
 
 	case tea.KeyRunes:
 		m.worktreeFormError = ""
-		m.worktreeFormName += string(msg.Runes)
+		if m.worktreeFormBaseFocused {
+			m.worktreeFormBase += string(msg.Runes)
+		} else {
+			m.worktreeFormName += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleAllowlistEditorKey processes key events when the allowlist editor is
+// open. Typing edits the new domain; Enter adds it, or applies the list when
+// nothing is typed.
+func (m Model) handleAllowlistEditorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.resetAllowlistEditor()
+		return m, nil
+
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.allowlistInput) != "" {
+			m.addAllowlistDomain()
+			return m, nil
+		}
+		c := m.allowlistContainer
+		domains := m.allowlistDomains
+		m.resetAllowlistEditor()
+		m.logger.Info("applying allowlist", "containerID", c.ID, "name", c.Name, "domains", len(domains))
+		cmd := m.setLoading("Updating allowlist for " + c.Name + "...")
+		return m, tea.Batch(cmd, m.updateAllowlist(c.ID, c.Name, domains))
+
+	case tea.KeyUp:
+		if m.allowlistSelected > 0 {
+			m.allowlistSelected--
+		}
+		return m, nil
+
+	case tea.KeyDown:
+		if m.allowlistSelected < len(m.allowlistDomains)-1 {
+			m.allowlistSelected++
+		}
+		return m, nil
+
+	case tea.KeyDelete, tea.KeyCtrlX:
+		m.removeAllowlistDomain()
+		return m, nil
+
+	case tea.KeyBackspace:
+		if len(m.allowlistInput) > 0 {
+			m.allowlistInput = m.allowlistInput[:len(m.allowlistInput)-1]
+		}
+		return m, nil
+
+	case tea.KeyRunes:
+		m.allowlistError = ""
+		m.allowlistInput += string(msg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleNoteEditorKey processes key events when the note editor is open.
+// Enter saves the note (an empty note clears it); Esc cancels.
+func (m Model) handleNoteEditorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.resetNoteEditor()
+		return m, nil
+
+	case tea.KeyEnter:
+		c := m.noteContainer
+		note := strings.TrimSpace(m.noteInput)
+		if err := m.manager.SetNote(c.ProjectPath, note); err != nil {
+			m.noteError = err.Error()
+			return m, nil
+		}
+		m.resetNoteEditor()
+		m.logger.Info("container note updated", "containerID", c.ID, "name", c.Name, "note", note)
+		if note == "" {
+			m.setSuccess("Cleared note for " + c.Name)
+		} else {
+			m.setSuccess("Saved note for " + c.Name)
+		}
+		return m, nil
+
+	case tea.KeyBackspace:
+		if len(m.noteInput) > 0 {
+			runes := []rune(m.noteInput)
+			m.noteInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.noteError = ""
+		m.noteInput += string(msg.Runes)
 		return m, nil
 	}
 
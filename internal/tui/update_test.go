@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/logging"
@@ -115,6 +117,60 @@ func TestContainerActionMsg_Error(t *testing.T) {
 	}
 }
 
+func TestContainerActionMsg_AlreadyInStateIsNotAnError(t *testing.T) {
+	m := newTestModel(t)
+	m.statusLevel = StatusLoading
+
+	msg := containerActionMsg{action: "stop", id: "abc123", err: container.ErrNotRunning}
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+
+	if m.statusLevel != StatusSuccess || m.err != nil {
+		t.Errorf("status = %v (err %v), want success", m.statusLevel, m.err)
+	}
+	if m.statusMessage != "Container already stopped" {
+		t.Errorf("statusMessage = %q, want %q", m.statusMessage, "Container already stopped")
+	}
+}
+
+func TestContainerErrorMsg_BackgroundKeepsCachedContainers(t *testing.T) {
+	m := newTreeTestModel(t)
+	updated, _ := m.Update(containersRefreshedMsg{containers: stateTestContainers("c")})
+	m = updated.(Model)
+	itemCount := len(m.treeItems)
+
+	updated, _ = m.Update(containerErrorMsg{err: fmt.Errorf("docker ps failed"), background: true})
+	m = updated.(Model)
+
+	if m.err != nil {
+		t.Errorf("err = %v, want nil for a background refresh failure", m.err)
+	}
+	if m.statusLevel != StatusInfo || m.statusMessage != staleRefreshStatus {
+		t.Errorf("status = (%v, %q), want (StatusInfo, %q)", m.statusLevel, m.statusMessage, staleRefreshStatus)
+	}
+	if len(m.treeItems) != itemCount {
+		t.Errorf("tree items = %d, want %d (cached)", len(m.treeItems), itemCount)
+	}
+
+	// The next successful refresh clears the stale indicator
+	updated, _ = m.Update(containersRefreshedMsg{containers: stateTestContainers("c")})
+	m = updated.(Model)
+	if m.statusMessage != "" {
+		t.Errorf("statusMessage = %q, want cleared after successful refresh", m.statusMessage)
+	}
+}
+
+func TestContainerErrorMsg_ManualRefreshShowsError(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(containerErrorMsg{err: fmt.Errorf("docker ps failed")})
+	m = updated.(Model)
+
+	if m.err == nil {
+		t.Error("err should be set for a manual refresh failure")
+	}
+}
+
 func TestEscape_ClearsError(t *testing.T) {
 	m := newTestModel(t)
 	m.statusLevel = StatusError
@@ -985,6 +1041,113 @@ func TestIsActionMenuOpen(t *testing.T) {
 	}
 }
 
+func TestActionMenu_OpenRunsVSCodeOnlyWithCodeCLI(t *testing.T) {
+	m := newTestModel(t)
+	containers := []*container.Container{
+		{ID: "aaa111222333", Name: "running-container", State: container.StateRunning},
+	}
+	m.containerList.SetItems(toListItems(containers))
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.syncSelectionFromTree()
+	m.actionMenuOpen = true
+
+	// Without code on PATH the VS Code entry is copy-only
+	m.vscodeAvailable = false
+	if m.selectedActionArgs() != nil {
+		t.Error("VS Code action should not be runnable without the code CLI")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd != nil || !updated.(Model).actionMenuOpen {
+		t.Error("o should do nothing without the code CLI")
+	}
+
+	m.vscodeAvailable = true
+	if args := m.selectedActionArgs(); len(args) == 0 || args[0] != "code" {
+		t.Fatalf("selectedActionArgs() = %q, want a code invocation", args)
+	}
+	if !strings.Contains(m.renderActionMenu(), "o: open") {
+		t.Error("action menu should advertise o: open for a runnable entry")
+	}
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd == nil || updated.(Model).actionMenuOpen {
+		t.Error("o should close the menu and run VS Code")
+	}
+
+	// Entries other than VS Code stay copy-only
+	m.actionMenuIdx = 1
+	if m.selectedActionArgs() != nil {
+		t.Error("shell actions should not be runnable")
+	}
+}
+
+func TestActionMenu_SelectedActionCommandFollowsHighlight(t *testing.T) {
+	m := newTestModel(t)
+	containers := []*container.Container{
+		{ID: "aaa111222333", Name: "running-container", State: container.StateRunning},
+	}
+	m.containerList.SetItems(toListItems(containers))
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.syncSelectionFromTree()
+	m.actionMenuOpen = true
+
+	actions := m.containerActions()
+	if len(actions) < 2 {
+		t.Fatalf("expected several actions, got %d", len(actions))
+	}
+	if got := m.selectedActionCommand(); got != actions[0].Command {
+		t.Errorf("initial selection = %q, want %q", got, actions[0].Command)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if got := m.selectedActionCommand(); got != actions[1].Command {
+		t.Errorf("after down = %q, want %q", got, actions[1].Command)
+	}
+
+	// Up past the top stays on the first action
+	for range 3 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+		m = updated.(Model)
+	}
+	if m.actionMenuIdx != 0 {
+		t.Errorf("actionMenuIdx = %d, want 0", m.actionMenuIdx)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Error("y should return a clipboard copy command")
+	}
+	if m.actionMenuOpen {
+		t.Error("y should close the action menu")
+	}
+}
+
+func TestClipboardCopiedMsg_UnavailableIsInfo(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(clipboardCopiedMsg{err: fmt.Errorf("no clipboard utilities available")})
+	m = updated.(Model)
+	if m.statusLevel != StatusInfo || m.statusMessage != "Clipboard unavailable" {
+		t.Errorf("status = (%v, %q), want info \"Clipboard unavailable\"", m.statusLevel, m.statusMessage)
+	}
+
+	updated, _ = m.Update(clipboardCopiedMsg{})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || m.statusMessage != "Copied" {
+		t.Errorf("status = (%v, %q), want success \"Copied\"", m.statusLevel, m.statusMessage)
+	}
+
+	// Project attach commands (y on a project) report how many were copied
+	updated, _ = m.Update(clipboardCopiedMsg{success: "Copied 3 attach commands"})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || m.statusMessage != "Copied 3 attach commands" {
+		t.Errorf("status = (%v, %q), want success with the count", m.statusLevel, m.statusMessage)
+	}
+}
+
 // AC1.1 - Worktree container lifecycle tests
 
 func TestSKeyHandler_ContainerlessWorktree(t *testing.T) {
@@ -1118,6 +1281,38 @@ func TestWorktreeContainerMsg_Error(t *testing.T) {
 	}
 }
 
+func TestStartWorktreeContainer_EmitsProgress(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := newTestModel(t)
+	wtPath := t.TempDir()
+
+	// No template matches the project, so creation fails after its first step;
+	// progress must still stream ahead of the final result.
+	var steps []container.ProgressStep
+	msg := m.startMissingWorktreeContainer(wtPath, "main")()
+	for {
+		progress, ok := msg.(worktreeProgressMsg)
+		if !ok {
+			break
+		}
+		steps = append(steps, progress.step)
+		updated, _ := m.Update(progress)
+		m = updated.(Model)
+		msg = waitForWorktreeProgress(progress.updates)()
+	}
+
+	if len(steps) == 0 || steps[0].Step != "compose" || steps[0].Status != "started" {
+		t.Fatalf("steps = %+v, want compose started first", steps)
+	}
+	if m.statusLevel != StatusLoading || !strings.Contains(m.statusMessage, "Generating compose configuration") {
+		t.Errorf("status = (%v, %q), want loading with step message", m.statusLevel, m.statusMessage)
+	}
+	done, ok := msg.(worktreeContainerMsg)
+	if !ok || done.path != wtPath {
+		t.Fatalf("final msg = %#v, want worktreeContainerMsg for %s", msg, wtPath)
+	}
+}
+
 // AC1.6 - No-op when no selection
 
 func TestSKeyHandler_NoSelection(t *testing.T) {
@@ -1158,6 +1353,7 @@ func TestSKeyHandler_NoSelection(t *testing.T) {
 
 func TestVKey_RunningContainer_DispatchesCommand(t *testing.T) {
 	m := newTestModel(t)
+	m.vscodeAvailable = true
 
 	// Add a container and select it
 	ctr := &container.Container{
@@ -1181,6 +1377,26 @@ func TestVKey_RunningContainer_DispatchesCommand(t *testing.T) {
 	}
 }
 
+func TestVKey_WithoutCodeCLI_ShowsError(t *testing.T) {
+	m := newTestModel(t)
+	m.vscodeAvailable = false
+	ctr := &container.Container{ID: "abc123def456", Name: "test-container", State: container.StateRunning}
+	m.containerList.SetItems(toListItems([]*container.Container{ctr}))
+	m.rebuildTreeItems()
+	m.selectedIdx = 1
+	m.syncSelectionFromTree()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(Model)
+
+	if cmd != nil {
+		t.Error("should not launch VS Code without the code CLI")
+	}
+	if m.statusLevel != StatusError || !strings.Contains(m.statusMessage, "code") {
+		t.Errorf("status = %v %q, want an error naming the code CLI", m.statusLevel, m.statusMessage)
+	}
+}
+
 // AC3.3 - No-op when stopped
 
 func TestVKey_StoppedContainer_NoOp(t *testing.T) {
@@ -1262,3 +1478,532 @@ func TestVSCodeLaunchMsg_Error(t *testing.T) {
 		t.Error("err should be set")
 	}
 }
+
+func TestConfigReloadedMsg_AppliesLiveFields(t *testing.T) {
+	m := newTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{{Name: "stale", Path: "/stale"}}
+	m.formTemplateIdx = 1
+
+	newCfg := config.Config{Theme: "latte", LogLevel: "warn"}
+	templates := []config.Template{{Name: "only-template"}}
+
+	updated, cmd := m.Update(ConfigReloaded(newCfg, templates))
+	m = updated.(Model)
+
+	if m.themeName != "latte" || m.cfg.Theme != "latte" {
+		t.Errorf("theme = %q/%q, want latte", m.themeName, m.cfg.Theme)
+	}
+	if len(m.templates) != 1 || m.templates[0].Name != "only-template" {
+		t.Errorf("templates = %v, want [only-template]", m.templates)
+	}
+	if m.formTemplateIdx != 0 {
+		t.Errorf("formTemplateIdx = %d, want 0 after templates shrank", m.formTemplateIdx)
+	}
+	if m.logManager.Level() != "warn" {
+		t.Errorf("log level = %q, want warn", m.logManager.Level())
+	}
+	// No scan paths: stale discovered projects are cleared immediately
+	if len(m.discoveredProjects) != 0 {
+		t.Errorf("discoveredProjects = %v, want empty", m.discoveredProjects)
+	}
+	if m.statusLevel != StatusSuccess {
+		t.Errorf("statusLevel = %v, want %v", m.statusLevel, StatusSuccess)
+	}
+	if cmd == nil {
+		t.Error("expected a refresh command after reload")
+	}
+}
+
+func TestConfigReloadedMsg_RescansNewScanPaths(t *testing.T) {
+	m := newTestModel(t)
+	scanDir := t.TempDir()
+
+	updated, cmd := m.Update(ConfigReloaded(config.Config{Theme: "mocha", ScanPaths: []string{scanDir}}, nil))
+	m = updated.(Model)
+
+	if len(m.cfg.ScanPaths) != 1 || m.cfg.ScanPaths[0] != scanDir {
+		t.Errorf("ScanPaths = %v, want [%s]", m.cfg.ScanPaths, scanDir)
+	}
+	if cmd == nil {
+		t.Fatal("expected rescan command")
+	}
+	if _, ok := cmd().(projectsRefreshedMsg); !ok {
+		t.Error("expected rescan command to produce projectsRefreshedMsg")
+	}
+}
+
+func TestPruneKey_ConfirmsBeforePruning(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("P should not prune before confirmation")
+	}
+	if !m.confirmOpen || m.confirmAction != "prune" {
+		t.Fatalf("confirm = (%v, %q), want open with prune action", m.confirmOpen, m.confirmAction)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.confirmOpen {
+		t.Error("confirm dialog should close on Enter")
+	}
+	if cmd == nil || m.statusLevel != StatusLoading {
+		t.Errorf("Enter should start pruning with a loading status, got level %v", m.statusLevel)
+	}
+}
+
+func TestPruneResultMsg(t *testing.T) {
+	m := newTestModel(t)
+
+	updated, _ := m.Update(pruneResultMsg{removed: []string{"a", "b"}})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || m.statusMessage != "Pruned 2 stopped containers" {
+		t.Errorf("status = (%v, %q), want success with count", m.statusLevel, m.statusMessage)
+	}
+
+	updated, _ = m.Update(pruneResultMsg{removed: []string{"a"}, err: fmt.Errorf("compose down failed")})
+	m = updated.(Model)
+	if m.statusLevel != StatusError || m.err == nil {
+		t.Errorf("status = %v, err = %v; want error", m.statusLevel, m.err)
+	}
+}
+
+func TestDestroyKey_ConfirmPolicy(t *testing.T) {
+	for _, confirm := range []bool{true, false} {
+		t.Run(fmt.Sprintf("confirm=%v", confirm), func(t *testing.T) {
+			m := newTreeTestModelWithContainers(t, 1)
+			m.cfg.Confirm.DestroyContainer = &confirm
+			for i, item := range m.treeItems {
+				if item.Type == TreeItemContainer && item.ContainerID == "c1" {
+					m.selectedIdx = i
+				}
+			}
+			m.syncSelectionFromTree()
+
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+			m = updated.(Model)
+
+			if confirm {
+				if !m.confirmOpen || m.confirmAction != config.ConfirmDestroyContainer || cmd != nil {
+					t.Fatalf("confirm = (%v, %q), want dialog open before destroying", m.confirmOpen, m.confirmAction)
+				}
+				return
+			}
+			if m.confirmOpen {
+				t.Fatal("confirm dialog should not open when destroy confirmation is disabled")
+			}
+			if cmd == nil || m.statusLevel != StatusLoading || m.getPendingOperation("c1") != "destroy" {
+				t.Errorf("d should start destroying c1 directly, got status %v pending %q", m.statusLevel, m.getPendingOperation("c1"))
+			}
+		})
+	}
+}
+
+func TestPruneKey_ThresholdRequiresTypedWord(t *testing.T) {
+	stoppedModel := func(count int) Model {
+		m := newTreeTestModel(t)
+		disabled := false
+		m.cfg.Confirm.Bulk = &disabled // the threshold overrides the policy
+		var stopped []container.Container
+		for i := range count {
+			stopped = append(stopped, container.Container{
+				ID: fmt.Sprintf("c%d", i), Name: fmt.Sprintf("container-%d", i), State: container.StateStopped,
+				Labels: map[string]string{container.LabelManagedBy: "true"},
+			})
+		}
+		m.manager = container.NewManager(container.ManagerOptions{Runtime: &listOnlyRuntime{containers: stopped}})
+		if err := m.manager.Refresh(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		// The gate counts what the manager would prune, not what is listed
+		m.containerList.SetItems([]list.Item{containerItem{container: &stopped[0]}})
+		m.rebuildTreeItems()
+		return m
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// At the threshold (default 3), bulk: false prunes at once
+	m := stoppedModel(3)
+	updated, cmd := m.Update(runes("P"))
+	m = updated.(Model)
+	if m.confirmOpen || cmd == nil {
+		t.Fatalf("3 containers: confirm open = %v, cmd = %v; want an immediate prune", m.confirmOpen, cmd != nil)
+	}
+
+	// Past it, "destroy" must be typed
+	m = stoppedModel(4)
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		return cmd
+	}
+	if cmd := press(runes("P")); cmd != nil || !m.confirmOpen || m.confirmName != confirmDestroyWord {
+		t.Fatalf("4 containers: confirm = (%v, name %q), want dialog requiring %q", m.confirmOpen, m.confirmName, confirmDestroyWord)
+	}
+	if !strings.Contains(m.confirmMessage, "4") {
+		t.Errorf("confirm message %q should give the count", m.confirmMessage)
+	}
+	press(runes("y"))
+	if !m.confirmOpen || m.confirmInput != "y" {
+		t.Fatalf("confirmOpen = %v, input = %q; want y typed, not confirming", m.confirmOpen, m.confirmInput)
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(runes("destro"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !m.confirmOpen {
+		t.Fatal("Enter with a partial word should not prune")
+	}
+	press(runes("y"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || m.confirmOpen || m.statusLevel != StatusLoading {
+		t.Errorf("Enter with %q should start the prune, got open %v status %v", confirmDestroyWord, m.confirmOpen, m.statusLevel)
+	}
+}
+
+func TestDestroyKey_ProtectedRequiresName(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 1)
+	disabled := false
+	m.cfg.Confirm.DestroyContainer = &disabled // protection overrides the policy
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemContainer && item.ContainerID == "c1" {
+			m.selectedIdx = i
+		}
+	}
+	m.syncSelectionFromTree()
+	m.selectedContainer.ProjectPath = "/projects/c1"
+	if err := m.manager.SetNote("/projects/c1", "staging, DO-NOT-DELETE"); err != nil {
+		t.Fatal(err)
+	}
+
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		return cmd
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	if cmd := press(runes("d")); cmd != nil || !m.confirmOpen || m.confirmName != "container-1" {
+		t.Fatalf("confirm = (%v, name %q), want dialog requiring the container name", m.confirmOpen, m.confirmName)
+	}
+
+	// y is typed, not a shortcut; Enter without the exact name does nothing
+	press(runes("y"))
+	if !m.confirmOpen || m.confirmInput != "y" {
+		t.Fatalf("confirmOpen = %v, input = %q; want y typed into the dialog", m.confirmOpen, m.confirmInput)
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(runes("container-"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !m.confirmOpen {
+		t.Fatal("Enter with a partial name should not destroy")
+	}
+
+	press(runes("1"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || m.confirmOpen || m.getPendingOperation("c1") != "destroy" {
+		t.Errorf("Enter with the exact name should destroy c1, got open %v pending %q", m.confirmOpen, m.getPendingOperation("c1"))
+	}
+	if m.confirmName != "" || m.confirmInput != "" {
+		t.Errorf("confirm state not cleared: name %q input %q", m.confirmName, m.confirmInput)
+	}
+}
+
+func TestNoteEditor(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 1)
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemContainer && item.ContainerID == "c1" {
+			m.selectedIdx = i
+		}
+	}
+	m.syncSelectionFromTree()
+	m.selectedContainer.ProjectPath = "/projects/c1"
+
+	press := func(msg tea.KeyMsg) {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if !m.noteEditorOpen {
+		t.Fatal("N should open the note editor")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("staging")})
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("box")})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.noteEditorOpen {
+		t.Error("Enter should close the note editor")
+	}
+	if got := m.manager.Note("/projects/c1"); got != "staging box" {
+		t.Errorf("note = %q, want %q", got, "staging box")
+	}
+	if m.statusLevel != StatusSuccess {
+		t.Errorf("status = %v, want success", m.statusLevel)
+	}
+
+	// Esc discards edits
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	press(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.noteEditorOpen || m.manager.Note("/projects/c1") != "staging box" {
+		t.Errorf("Esc should cancel, note = %q", m.manager.Note("/projects/c1"))
+	}
+}
+
+func TestRegenerateCertsKey(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 1)
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemContainer && item.ContainerID == "c1" {
+			m.selectedIdx = i
+		}
+	}
+	m.syncSelectionFromTree()
+	m.selectedContainer.State = container.StateRunning
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	m = updated.(Model)
+	if cmd == nil || m.statusLevel != StatusLoading || !strings.Contains(m.statusMessage, "proxy certificates") {
+		t.Errorf("C should start regenerating with a loading status, got (%v, %q)", m.statusLevel, m.statusMessage)
+	}
+
+	updated, _ = m.Update(proxyCertsRegeneratedMsg{name: "c1"})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess {
+		t.Errorf("statusLevel = %v, want StatusSuccess", m.statusLevel)
+	}
+}
+
+func TestExportLogsKey(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 1)
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemContainer && item.ContainerID == "c1" {
+			m.selectedIdx = i
+		}
+	}
+	m.syncSelectionFromTree()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(Model)
+	if cmd == nil || m.statusLevel != StatusLoading || !strings.Contains(m.statusMessage, "Saving logs") {
+		t.Errorf("e should start saving logs with a loading status, got (%v, %q)", m.statusLevel, m.statusMessage)
+	}
+
+	updated, _ = m.Update(containerLogsExportedMsg{name: "c1", path: "/data/container-logs/c1.log"})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || !strings.Contains(m.statusMessage, "/data/container-logs/c1.log") {
+		t.Errorf("status = (%v, %q), want success showing the path", m.statusLevel, m.statusMessage)
+	}
+}
+
+func TestDuplicateSessionsKeys(t *testing.T) {
+	m := newTreeTestModelWithContainers(t, 3)
+	selectContainer := func(id string) {
+		t.Helper()
+		for i, item := range m.treeItems {
+			if item.Type == TreeItemContainer && item.ContainerID == id {
+				m.selectedIdx = i
+			}
+		}
+		m.syncSelectionFromTree()
+	}
+	for _, c := range m.sortedContainers() {
+		c.State = container.StateRunning
+	}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+		return cmd
+	}
+
+	// D marks the source; D on it again cancels
+	selectContainer("c1")
+	press("D")
+	if m.layoutSource == nil || m.layoutSource.ID != "c1" {
+		t.Fatalf("D should mark c1 as the layout source, got %v", m.layoutSource)
+	}
+	press("D")
+	if m.layoutSource != nil {
+		t.Fatal("D on the source should cancel")
+	}
+
+	// D on another container duplicates into it
+	press("D")
+	selectContainer("c2")
+	if cmd := press("D"); cmd == nil || m.layoutSource != nil || m.statusLevel != StatusLoading {
+		t.Errorf("D on a target should start duplicating, got (source %v, status %v)", m.layoutSource, m.statusLevel)
+	}
+
+	// A duplicates into all running containers
+	selectContainer("c1")
+	press("D")
+	if cmd := press("A"); cmd == nil || !strings.Contains(m.statusMessage, "2 containers") {
+		t.Errorf("A should duplicate into the 2 other containers, got %q", m.statusMessage)
+	}
+
+	updated, _ := m.Update(sessionsDuplicatedMsg{source: "container-1", created: 4})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || !strings.Contains(m.statusMessage, "Duplicated 4 sessions") {
+		t.Errorf("status = (%v, %q), want success", m.statusLevel, m.statusMessage)
+	}
+}
+
+func TestProjectWatchMessages(t *testing.T) {
+	m := newTestModel(t)
+	m.SetProjectWatch(true)
+
+	projects := []discovery.DiscoveredProject{{Name: "alpha", Path: "/src/alpha"}}
+	updated, _ := m.Update(ProjectsRefreshed(projects))
+	m = updated.(Model)
+	if len(m.discoveredProjects) != 1 || m.discoveredProjects[0].Name != "alpha" {
+		t.Errorf("discoveredProjects = %v, want [alpha]", m.discoveredProjects)
+	}
+
+	updated, cmd := m.Update(ProjectWatchStopped())
+	m = updated.(Model)
+	if m.projectWatch || cmd == nil {
+		t.Errorf("ProjectWatchStopped: projectWatch = %v, cmd = %v; want polling resumed with a rescan", m.projectWatch, cmd != nil)
+	}
+}
+
+func TestPKeyHandler_PrunesProjectWorktrees(t *testing.T) {
+	m := newTestModel(t)
+
+	projectPath := "/path/to/project"
+	m.discoveredProjects = []discovery.DiscoveredProject{{
+		Name: "test-project",
+		Path: projectPath,
+		Worktrees: []discovery.Worktree{
+			{Name: "gone", Path: projectPath + "/.worktrees/gone", Branch: "gone", Prunable: true},
+		},
+	}}
+	m.expandedProjects = map[string]bool{projectPath: true}
+	m.rebuildTreeItems()
+
+	projIdx, wtIdx := -1, -1
+	for i, item := range m.treeItems {
+		switch {
+		case item.Type == TreeItemProject && item.ProjectPath == projectPath:
+			projIdx = i
+		case item.Type == TreeItemWorktree && item.WorktreeName == "gone":
+			wtIdx = i
+		}
+	}
+	if projIdx < 0 || wtIdx < 0 {
+		t.Fatal("could not find project and worktree items in tree")
+	}
+	if !m.treeItems[wtIdx].Prunable {
+		t.Error("worktree tree item should carry the Prunable flag")
+	}
+
+	m.selectedIdx = projIdx
+	m.syncSelectionFromTree()
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a prune command")
+	}
+	if !strings.Contains(m.statusMessage, "Pruning worktrees of test-project") {
+		t.Errorf("statusMessage = %q, want pruning status", m.statusMessage)
+	}
+
+	updated, _ = m.Update(worktreeActionMsg{action: "prune", name: "test-project", projectPath: projectPath})
+	m = updated.(Model)
+	if m.statusLevel != StatusSuccess || !strings.Contains(m.statusMessage, "Pruned stale worktrees") {
+		t.Errorf("status = %v %q, want prune success", m.statusLevel, m.statusMessage)
+	}
+}
+
+func TestWKeyHandler_DirtyWorktreeListsChangedFiles(t *testing.T) {
+	m := newTestModel(t)
+	noConfirm := false
+	m.cfg.Confirm.DeleteWorktree = &noConfirm
+
+	projectPath := "/path/to/project"
+	m.discoveredProjects = []discovery.DiscoveredProject{{
+		Name:      "test-project",
+		Path:      projectPath,
+		Worktrees: []discovery.Worktree{{Name: "feature", Path: projectPath + "/.worktrees/feature", Branch: "feature"}},
+	}}
+	m.expandedProjects = map[string]bool{projectPath: true}
+	m.rebuildTreeItems()
+	for i, item := range m.treeItems {
+		if item.Type == TreeItemWorktree && item.WorktreeName == "feature" {
+			m.selectedIdx = i
+		}
+	}
+	m.syncSelectionFromTree()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a worktree status check command")
+	}
+	if m.confirmOpen {
+		t.Fatal("confirm dialog should wait for the status check")
+	}
+
+	updated, _ = m.Update(worktreeChangesMsg{name: "feature", changedFiles: []string{"main.go", "notes.txt"}})
+	m = updated.(Model)
+	if !m.confirmOpen || m.confirmAction != confirmForceDeleteWorktree {
+		t.Fatalf("confirmOpen=%v action=%q, want force delete confirm even with confirmation disabled", m.confirmOpen, m.confirmAction)
+	}
+	if !strings.Contains(m.confirmMessage, "main.go") || !strings.Contains(m.confirmMessage, "notes.txt") {
+		t.Errorf("confirmMessage = %q, want changed files listed", m.confirmMessage)
+	}
+	m.confirmOpen = false
+
+	// A clean worktree follows the confirm policy (disabled here: removal starts)
+	updated, cmd = m.Update(worktreeChangesMsg{name: "feature"})
+	m = updated.(Model)
+	if m.confirmOpen {
+		t.Error("clean worktree removal should not ask when delete_worktree confirmation is disabled")
+	}
+	if cmd == nil || !strings.Contains(m.statusMessage, "Removing worktree feature") {
+		t.Errorf("statusMessage = %q, want removal started", m.statusMessage)
+	}
+}
+
+func TestRefreshPause_StopsSchedulingTicks(t *testing.T) {
+	m := newTestModelWithConfig(t, &config.Config{Theme: "mocha", RefreshInterval: time.Second})
+
+	updated, cmd := m.Update(tickMsg{time: time.Now(), gen: m.tickGen})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("tick while running should schedule a refresh and the next tick")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	m = updated.(Model)
+	if !m.refreshPaused {
+		t.Fatal("z should pause periodic refresh")
+	}
+	if !strings.Contains(m.renderStatusBar(120), "refresh paused") {
+		t.Error("status bar should show the paused indicator")
+	}
+
+	updated, cmd = m.Update(tickMsg{time: time.Now(), gen: m.tickGen})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("tick while paused should not schedule anything")
+	}
+
+	staleGen := m.tickGen
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	m = updated.(Model)
+	if m.refreshPaused || cmd == nil {
+		t.Fatal("second z should resume and refresh immediately")
+	}
+	if _, cmd = m.Update(tickMsg{time: time.Now(), gen: staleGen}); cmd != nil {
+		t.Error("a tick from before the pause should be dropped after resume")
+	}
+}
+
+// listOnlyRuntime is a container runtime that only lists containers.
+type listOnlyRuntime struct {
+	container.RuntimeInterface
+	containers []container.Container
+}
+
+func (r *listOnlyRuntime) ListContainers(context.Context) ([]container.Container, error) {
+	return r.containers, nil
+}
@@ -4,11 +4,14 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 
+	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/logging"
 	"devagent/internal/tmux"
@@ -45,13 +48,20 @@ func (m Model) View() string {
 	if len(m.listenURLs) > 0 {
 		title += " (" + strings.Join(m.listenURLs, ", ") + ")"
 	}
+	if m.tailscaleStatus != "" && m.tailscaleStatus != "running" {
+		title += " [tailscale " + m.tailscaleStatus + "]"
+	}
 	header := m.styles.TitleStyle().Render(truncateString(title, layout.Header.Width))
 
 	// Build content: tree view + optional detail panel
 	var content string
-	// Worktree creation form replaces content area
+	// Worktree creation form and allowlist editor replace content area
 	if m.worktreeFormOpen {
 		content = m.renderWorktreeForm()
+	} else if m.allowlistEditorOpen {
+		content = m.renderAllowlistEditor()
+	} else if m.noteEditorOpen {
+		content = m.renderNoteEditor()
 	} else if m.formOpen {
 		// Container creation form replaces content area
 		content = m.renderCreateForm()
@@ -98,6 +108,61 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
+// renderAllowlistEditor renders the proxy allowlist editor: the allowed
+// domains with the selected one marked, and an input for a new domain.
+func (m Model) renderAllowlistEditor() string {
+	name := ""
+	if m.allowlistContainer != nil {
+		name = m.allowlistContainer.Name
+	}
+
+	header := m.styles.TitleStyle().Render("Edit Allowlist") + "  " +
+		m.styles.SubtitleStyle().Render(fmt.Sprintf("for %s", name))
+
+	parts := []string{header, ""}
+	if len(m.allowlistDomains) == 0 {
+		parts = append(parts, m.styles.HelpStyle().Render("  (no domains allowed)"))
+	}
+	for i, domain := range m.allowlistDomains {
+		if i == m.allowlistSelected {
+			parts = append(parts, m.styles.AccentStyle().Render("▸ "+domain))
+		} else {
+			parts = append(parts, "  "+domain)
+		}
+	}
+
+	parts = append(parts, "", "  Add domain: "+m.allowlistInput+"_")
+	if m.allowlistError != "" {
+		parts = append(parts, m.styles.ErrorStyle().Render("Error: "+m.allowlistError))
+	}
+
+	help := m.styles.HelpStyle().Render("↑/↓: select • del/ctrl+x: remove • enter: add (empty: apply and restart proxy) • esc: cancel")
+	parts = append(parts, "", help)
+
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// renderNoteEditor renders the container note editor.
+func (m Model) renderNoteEditor() string {
+	name := ""
+	if m.noteContainer != nil {
+		name = m.noteContainer.Name
+	}
+
+	header := m.styles.TitleStyle().Render("Edit Note") + "  " +
+		m.styles.SubtitleStyle().Render(fmt.Sprintf("for %s", name))
+
+	parts := []string{header, "", "  Note: " + m.noteInput + "_"}
+	if m.noteError != "" {
+		parts = append(parts, m.styles.ErrorStyle().Render("Error: "+m.noteError))
+	}
+
+	help := m.styles.HelpStyle().Render(fmt.Sprintf("enter: save (empty: clear) • esc: cancel • %q protects from destroy and prune", container.ProtectedNote))
+	parts = append(parts, "", help)
+
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
 // renderWorktreeForm renders the worktree creation form as a left-justified input area.
 func (m Model) renderWorktreeForm() string {
 	projectName := ""
@@ -108,20 +173,31 @@ func (m Model) renderWorktreeForm() string {
 	header := m.styles.TitleStyle().Render("Create Worktree") + "  " +
 		m.styles.SubtitleStyle().Render(fmt.Sprintf("in %s", projectName))
 
-	label := m.styles.AccentStyle().Render("Branch Name: ")
-	value := m.worktreeFormName + "_"
+	nameLine := "  Branch Name: " + m.worktreeFormName
+	baseLine := "  Base Ref:    " + m.worktreeFormBase
+	if m.worktreeFormBaseFocused {
+		baseLine = m.styles.AccentStyle().Render("▸ Base Ref:    ") + m.worktreeFormBase + "_"
+	} else {
+		nameLine = m.styles.AccentStyle().Render("▸ Branch Name: ") + m.worktreeFormName + "_"
+	}
+	if m.worktreeFormBase == "" && !m.worktreeFormBaseFocused {
+		baseLine += m.styles.HelpStyle().Render("(HEAD)")
+	}
 
 	var errorLine string
 	if m.worktreeFormError != "" {
 		errorLine = m.styles.ErrorStyle().Render("Error: " + m.worktreeFormError)
+	} else if m.worktreeFormVerifying {
+		errorLine = m.styles.HelpStyle().Render("Checking base ref...")
 	}
 
-	help := m.styles.HelpStyle().Render("Enter: create • Esc: cancel")
+	help := m.styles.HelpStyle().Render("Tab: switch field • Enter: create • Esc: cancel")
 
 	parts := []string{
 		header,
 		"",
-		label + value,
+		nameLine,
+		baseLine,
 	}
 	if errorLine != "" {
 		parts = append(parts, errorLine)
@@ -137,9 +213,18 @@ func (m Model) renderCreateForm() string {
 	if m.formSubmitting || m.formCompleted {
 		return m.renderFormSubmitting()
 	}
+	if m.formPreviewOpen {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			m.styles.TitleStyle().Render("Create Preview")+"  "+m.styles.SubtitleStyle().Render("nothing is written or started"),
+			"",
+			m.formPreview.View(),
+			"",
+			m.styles.HelpStyle().Render("↑/↓ PgUp/PgDn: scroll • Esc: back to form"),
+		)
+	}
 
 	// Normal form rendering
-	title := m.styles.TitleStyle().Render("Create Container")
+	title := m.styles.TitleStyle().Render(m.formTitle())
 
 	// Template selection - compact horizontal display
 	templateLabel := "Template: "
@@ -148,11 +233,13 @@ func (m Model) renderCreateForm() string {
 	}
 
 	var templateValue string
+	var templateList []string
 	if len(m.templates) > 0 && m.formTemplateIdx < len(m.templates) {
 		tmpl := m.templates[m.formTemplateIdx]
 		templateValue = m.styles.AccentStyle().Render(tmpl.Name)
 		if m.formFocusedField == FieldTemplate {
-			templateValue += m.styles.HelpStyle().Render(fmt.Sprintf(" (↑↓ to change, %d/%d)", m.formTemplateIdx+1, len(m.templates)))
+			templateValue += m.styles.HelpStyle().Render(fmt.Sprintf(" (type to filter, ↑↓ to change, %d/%d)", m.formTemplateIdx+1, len(m.templates)))
+			templateList = m.renderTemplateList()
 		}
 	} else {
 		templateValue = m.styles.ErrorStyle().Render("No templates available")
@@ -160,9 +247,9 @@ func (m Model) renderCreateForm() string {
 	templateLine := templateLabel + templateValue
 
 	// Project path input - single line
-	projectPathLabel := "Project Path: "
+	projectPathLabel := m.formPathLabel() + " "
 	if m.formFocusedField == FieldProjectPath {
-		projectPathLabel = m.styles.AccentStyle().Render("▸ Project Path: ")
+		projectPathLabel = m.styles.AccentStyle().Render("▸ " + projectPathLabel)
 	}
 	projectPathValue := m.formProjectPath
 	if projectPathValue == "" && m.formFocusedField != FieldProjectPath {
@@ -187,6 +274,38 @@ func (m Model) renderCreateForm() string {
 	}
 	nameLine := nameLabel + nameValue
 
+	// Extra mounts input - single line, mounts separated by commas
+	mountsLabel := "Mounts: "
+	if m.formFocusedField == FieldMounts {
+		mountsLabel = m.styles.AccentStyle().Render("▸ Mounts: ")
+	}
+	mountsValue := m.formMounts
+	if mountsValue == "" && m.formFocusedField != FieldMounts {
+		mountsValue = m.styles.SubtitleStyle().Render("(optional, type=bind,source=...,target=...)")
+	}
+	if m.formFocusedField == FieldMounts {
+		mountsValue += "_" // cursor
+	}
+	mountsLine := mountsLabel + mountsValue
+
+	// Warn before a create would replace the project's own devcontainer.json,
+	// and that keeping it does not change the container: the template's
+	// docker-compose.yml defines that either way
+	var devcontainerLine string
+	if m.hasOwnDevcontainerJSON() {
+		const composeNote = "; the template's docker-compose.yml still defines the container"
+		switch {
+		case m.formTemplateIdx < len(m.templates) && m.templates[m.formTemplateIdx].UseExistingDevcontainer:
+			devcontainerLine = m.styles.LogWarnStyle().Render("Existing devcontainer.json: kept (template uses it)" + composeNote)
+		case m.formKeepDevcontainer:
+			devcontainerLine = m.styles.LogWarnStyle().Render("Existing devcontainer.json: kept"+composeNote) +
+				m.styles.HelpStyle().Render(" (ctrl+o: overwrite)")
+		default:
+			devcontainerLine = m.styles.LogWarnStyle().Render("Existing devcontainer.json: overwrite") +
+				m.styles.HelpStyle().Render(" (ctrl+o: keep it)")
+		}
+	}
+
 	// Error display
 	var errorLine string
 	if m.formError != "" {
@@ -194,14 +313,21 @@ func (m Model) renderCreateForm() string {
 	}
 
 	// Help text
-	help := m.styles.HelpStyle().Render("Tab: next field • Enter: create • Esc: cancel")
+	help := m.styles.HelpStyle().Render("Tab: next field • Enter: create • ctrl+p: preview • Esc: cancel")
 
 	parts := []string{
 		title,
 		"",
 		templateLine,
+	}
+	parts = append(parts, templateList...)
+	parts = append(parts,
 		projectPathLine,
 		nameLine,
+		mountsLine,
+	)
+	if devcontainerLine != "" {
+		parts = append(parts, devcontainerLine)
 	}
 
 	if errorLine != "" {
@@ -213,12 +339,61 @@ func (m Model) renderCreateForm() string {
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
+// renderTemplateList renders the focused template field's filter and the
+// filtered templates, formTemplateListHeight at a time, scrolled so the
+// selected one is visible.
+func (m Model) renderTemplateList() []string {
+	lines := []string{"  Filter: " + m.formTemplateQuery + "_"}
+	idxs := m.filteredTemplates()
+	if len(idxs) == 0 {
+		return append(lines, m.styles.SubtitleStyle().Render("    (no templates match)"))
+	}
+
+	start := 0
+	if pos := slices.Index(idxs, m.formTemplateIdx); pos >= formTemplateListHeight {
+		start = pos - formTemplateListHeight + 1
+	}
+	end := min(start+formTemplateListHeight, len(idxs))
+	if start > 0 {
+		lines = append(lines, m.styles.HelpStyle().Render(fmt.Sprintf("    ↑ %d more", start)))
+	}
+	for _, i := range idxs[start:end] {
+		if i == m.formTemplateIdx {
+			lines = append(lines, m.styles.AccentStyle().Render("  ▸ "+m.templates[i].Name))
+		} else {
+			lines = append(lines, "    "+m.templates[i].Name)
+		}
+	}
+	if end < len(idxs) {
+		lines = append(lines, m.styles.HelpStyle().Render(fmt.Sprintf("    ↓ %d more", len(idxs)-end)))
+	}
+	return lines
+}
+
+// formTitle returns the creation form's title, which names its variant.
+func (m Model) formTitle() string {
+	if m.formClone {
+		return "Clone Repository"
+	}
+	return "Create Container"
+}
+
+// formPathLabel labels the form's project path field, a git URL when cloning.
+func (m Model) formPathLabel() string {
+	if m.formClone {
+		return "Git URL:"
+	}
+	return "Project Path:"
+}
+
 // renderFormSubmitting renders the form in submitting state with progress.
 func (m Model) renderFormSubmitting() string {
 	// Title - pulsing while submitting, static when completed
 	var title string
 	if m.formCompleted {
-		title = m.styles.TitleStyle().Render("Create Container")
+		title = m.styles.TitleStyle().Render(m.formTitle())
+	} else if m.formClone {
+		title = m.renderPulsingTitle("Cloning Repository")
 	} else {
 		title = m.renderPulsingTitle("Creating Container")
 	}
@@ -228,7 +403,7 @@ func (m Model) renderFormSubmitting() string {
 	templateValue := m.styles.DisabledStyle().Render(m.templates[m.formTemplateIdx].Name)
 	templateLine := templateLabel + templateValue
 
-	projectPathLabel := m.styles.DisabledStyle().Render("Project Path: ")
+	projectPathLabel := m.styles.DisabledStyle().Render(fmt.Sprintf("%-14s", m.formPathLabel()))
 	projectPathValue := m.styles.DisabledStyle().Render(m.formProjectPath)
 	projectPathLine := projectPathLabel + projectPathValue
 
@@ -246,8 +421,12 @@ func (m Model) renderFormSubmitting() string {
 		templateLine,
 		projectPathLine,
 		nameLine,
-		"",
 	}
+	if m.formMounts != "" {
+		mountsLabel := m.styles.DisabledStyle().Render("Mounts:       ")
+		parts = append(parts, mountsLabel+m.styles.DisabledStyle().Render(m.formMounts))
+	}
+	parts = append(parts, "")
 
 	// Completed steps with checkmarks
 	for _, step := range m.formStatusSteps {
@@ -268,12 +447,16 @@ func (m Model) renderFormSubmitting() string {
 
 	// Final error status line when completed with error
 	// (Success is already shown as a step from the manager)
-	if m.formCompleted && m.formCompletedError {
+	if m.formCompleted && m.formExists {
+		parts = append(parts, m.styles.LogWarnStyle().Render("⚠ Project already has a container"))
+	} else if m.formCompleted && m.formCompletedError {
 		parts = append(parts, m.styles.ErrorStyle().Render("✗ Creation failed"))
 	}
 
 	// Help text
-	if m.formCompleted {
+	if m.formCompleted && m.formExists {
+		parts = append(parts, "", m.styles.HelpStyle().Render("f: create anyway • Enter/Esc: continue"))
+	} else if m.formCompleted {
 		parts = append(parts, "", m.styles.HelpStyle().Render("Enter/Esc: continue"))
 	} else {
 		parts = append(parts, "", m.styles.HelpStyle().Render("Esc: cancel"))
@@ -360,7 +543,7 @@ func (m Model) renderSessionView() string {
 	var helpText string
 	hasSessions := m.selectedContainer != nil && len(m.selectedContainer.Sessions) > 0
 	if hasSessions {
-		helpText = "t: create session • k: kill session • ↑↓: navigate • esc: back"
+		helpText = "t: create session • a: attach • k: kill session • y: copy attach • ↑↓: navigate • esc: back"
 	} else {
 		helpText = "t: create session • esc: back"
 	}
@@ -379,6 +562,9 @@ func (m Model) renderSessionView() string {
 
 	if errorDisplay != "" {
 		parts = append(parts, "", errorDisplay)
+	} else if m.statusMessage != "" && m.statusLevel != StatusLoading {
+		// The modal hides the status bar; show results such as "Copied" here
+		parts = append(parts, "", m.styles.InfoStatusStyle().Render(m.statusMessage))
 	}
 
 	parts = append(parts, "", help)
@@ -409,15 +595,9 @@ func (m Model) renderSessionCreated() string {
 	title := m.styles.TitleStyle().Render("Session Created")
 	sessionInfo := m.styles.AccentStyle().Render(m.sessionCreatedName)
 
-	// Build attach command with terminal environment for proper TUI rendering
 	var attachCmd string
 	if m.selectedContainer != nil {
-		runtimePath := m.manager.RuntimePath()
-		user := m.selectedContainer.RemoteUser
-		if user == "" {
-			user = container.DefaultRemoteUser
-		}
-		attachCmd = fmt.Sprintf("%s exec -it -u %s %s tmux attach -t %s", runtimePath, user, m.selectedContainer.Name, m.sessionCreatedName)
+		attachCmd = GenerateAttachCommand(m.selectedContainer, m.sessionCreatedName, m.manager.RuntimePath(), m.attachCommandTemplate())
 	}
 	attachLine := m.styles.InfoStyle().Render(fmt.Sprintf("Attach: %s", attachCmd))
 
@@ -490,18 +670,30 @@ func (m Model) renderActionMenu() string {
 	subtitle := m.styles.SubtitleStyle().Render(fmt.Sprintf("%s (%s)", containerName, containerState))
 
 	// Generate actions for this container
-	actions := GenerateContainerActions(m.selectedContainer, m.manager.RuntimePath())
+	actions := m.containerActions()
 
 	var lines []string
-	for _, action := range actions {
-		label := m.styles.AccentStyle().Render(action.Label)
-		cmd := m.styles.InfoStyle().Render("  " + action.Command)
+	for i, action := range actions {
+		indicator := "  "
+		if i == m.actionMenuIdx {
+			indicator = "▸ "
+		}
+		labelText := indicator + action.Label
+		if action.Args != nil {
+			labelText += " (o: open)"
+		}
+		label := m.styles.AccentStyle().Render(labelText)
+		cmd := m.styles.InfoStyle().Render("    " + action.Command)
 		lines = append(lines, label, cmd, "")
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
-	help := m.styles.HelpStyle().Render("Esc: close")
+	helpText := "↑↓: select • enter/y: copy command • Esc: close"
+	if m.selectedActionArgs() != nil {
+		helpText = "↑↓: select • enter/y: copy command • o: open • Esc: close"
+	}
+	help := m.styles.HelpStyle().Render(helpText)
 
 	parts := []string{
 		title,
@@ -538,9 +730,17 @@ func (m Model) renderConfirmDialog() string {
 		title,
 		"",
 		message,
-		"",
-		help,
 	}
+	if m.confirmName != "" {
+		label := "Name: "
+		help = m.styles.HelpStyle().Render("Enter: confirm (name must match) • Esc: cancel")
+		if m.confirmName == confirmDestroyWord && m.confirmAction == config.ConfirmPrune {
+			label = "Type: "
+			help = m.styles.HelpStyle().Render("Enter: confirm (must match) • Esc: cancel")
+		}
+		parts = append(parts, "", label+m.confirmInput+"_")
+	}
+	parts = append(parts, "", help)
 
 	view := lipgloss.JoinVertical(lipgloss.Left, parts...)
 	boxed := m.styles.BoxStyle().Render(view)
@@ -591,6 +791,28 @@ func (m Model) renderStatusBar(width int) string {
 		statusText += m.styles.HelpStyle().Render(" (esc to clear)")
 	}
 
+	// Show the tree filter or log search input, or the active one, ahead of the status message
+	filterText := m.renderTreeFilterStatus()
+	if filterText == "" {
+		filterText = m.renderLogSearchStatus()
+	}
+	if filterText != "" {
+		if statusText != "" {
+			statusText = filterText + "  " + statusText
+		} else {
+			statusText = filterText
+		}
+	}
+
+	if m.refreshPaused {
+		paused := m.styles.AccentStyle().Render("⏸ refresh paused")
+		if statusText != "" {
+			statusText = paused + "  " + statusText
+		} else {
+			statusText = paused
+		}
+	}
+
 	// Build help text
 	help := m.renderContextualHelp()
 
@@ -612,22 +834,63 @@ func (m Model) renderStatusBar(width int) string {
 	)
 }
 
+// renderTreeFilterStatus returns the status-bar text for the tree filter:
+// the input line while editing, a hint while a filter is active, or "".
+func (m Model) renderTreeFilterStatus() string {
+	if m.treeFilterOpen {
+		return m.styles.AccentStyle().Render("/"+m.treeFilter) + m.styles.HelpStyle().Render("▏")
+	}
+	if m.treeFilter != "" {
+		return m.styles.AccentStyle().Render(fmt.Sprintf("filter: %q", m.treeFilter)) +
+			m.styles.HelpStyle().Render(" (esc to clear)")
+	}
+	return ""
+}
+
+// renderLogSearchStatus returns the status-bar text for the log search:
+// the input line while editing, a hint while a search is active, or "".
+func (m Model) renderLogSearchStatus() string {
+	if m.logSearchOpen {
+		return m.styles.AccentStyle().Render("/"+m.logSearch) + m.styles.HelpStyle().Render("▏")
+	}
+	if m.logSearch != "" {
+		return m.styles.AccentStyle().Render(fmt.Sprintf("search: %q", m.logSearch)) +
+			m.styles.HelpStyle().Render(fmt.Sprintf(" (%d matches, esc to clear)", len(m.filteredLogEntries())))
+	}
+	return ""
+}
+
 // renderContextualHelp returns help text based on current state and panel focus.
 func (m Model) renderContextualHelp() string {
+	if m.treeFilterOpen {
+		return m.styles.HelpStyle().Render("type to filter • ↑/↓: navigate • enter: apply • esc: clear")
+	}
+	if m.logSearchOpen {
+		return m.styles.HelpStyle().Render("type to search logs • enter: apply • esc: clear")
+	}
+
 	var help string
 	switch m.panelFocus {
 	case FocusDetail:
 		help = "tab: next panel • esc: tree • l: logs"
 	case FocusLogs:
-		help = "↑/↓: scroll • 1-4: filter levels • g/G: top/bottom • tab: next panel • esc: tree"
+		help = "↑/↓: scroll • 1-4: filter levels • /: search • g/G: top/bottom • tab: next panel • esc: tree"
+		if m.logSearch != "" {
+			help = "↑/↓: scroll • n/N: next/prev match • /: edit search • esc: clear search • tab: next panel"
+		} else if m.logDetailsOpen {
+			help = "↑/↓: scroll • ←/→: scroll sideways • w: wrap • y: copy entry • ←: close details • esc: tree"
+			if m.logDetailsWrap {
+				help = "↑/↓: scroll • w: unwrap • y: copy entry • ←: close details • esc: tree"
+			}
+		}
 	default: // FocusTree
 		if m.selectedIdx >= 0 && m.selectedIdx < len(m.treeItems) {
 			item := m.treeItems[m.selectedIdx]
 			switch item.Type {
 			case TreeItemAllProjects:
-				help = "↑/↓: navigate • →: details • c: create • w: new worktree • l: logs"
+				help = "↑/↓: navigate • →: details • /: filter • o: sort • c: create • i: clone • w: new worktree • P: prune stopped • l: logs"
 			case TreeItemProject:
-				help = "↑/↓: navigate • enter: expand • w: new worktree • c: create • l: logs"
+				help = "↑/↓: navigate • enter: expand • w: new worktree • p: prune worktrees • c: create • y: copy attach commands • l: logs"
 			case TreeItemWorktree:
 				containers := m.findContainersForPath(item.ProjectPath)
 				if len(containers) == 0 {
@@ -636,16 +899,21 @@ func (m Model) renderContextualHelp() string {
 					help = "↑/↓: navigate • c: create container • W: delete worktree • l: logs"
 				}
 			case TreeItemSession:
-				help = "↑/↓: navigate • →: details • k: kill session • v: VS Code • tab: next panel • l: logs"
+				help = "↑/↓: navigate • enter: windows • →: details • k: kill session • " + m.vscodeHint() + "tab: next panel • l: logs"
+			case TreeItemWindow:
+				help = "↑/↓: navigate • →: details • tab: next panel • l: logs"
 			case TreeItemContainer:
 				if m.detailPanelOpen {
 					help = "←/esc: close detail • ↑/↓: navigate • tab: next panel • l: logs"
+					if info := m.cachedIsolationInfo; info != nil && info.NetworkIsolated {
+						help = "←/esc: close detail • ↑/↓: navigate • a: edit allowlist • tab: next panel • l: logs"
+					}
 				} else {
-					help = "↑/↓: navigate • enter: expand • →: details • c: create • s/x/d: start/stop/destroy • t: actions • v: VS Code • tab: next panel • l: logs"
+					help = "↑/↓: navigate • enter: expand • →: details • c: create • s/x/d: start/stop/destroy • N: note • t: actions • D: duplicate sessions • e: save logs • C: regen certs • " + m.vscodeHint() + "tab: next panel • l: logs"
 				}
 			}
 		} else {
-			help = "c: create container • l: logs"
+			help = "c: create container • i: clone repo • l: logs"
 		}
 	}
 	return m.styles.HelpStyle().Render(help)
@@ -671,15 +939,42 @@ func (m Model) renderLogEntry(entry logging.LogEntry) string {
 		level = m.styles.LogInfoStyle().Render(entry.Level)
 	}
 
-	// Scope
-	scope := m.styles.LogScopeStyle().Render("[" + entry.Scope + "]")
-
-	// Message
-	message := entry.Message
+	// Scope and message, with log search matches highlighted
+	scopeStyle := m.styles.LogScopeStyle()
+	scope := scopeStyle.Render("[") + m.highlightLogSearch(entry.Scope, scopeStyle) + scopeStyle.Render("]")
+	message := m.highlightLogSearch(entry.Message, lipgloss.NewStyle())
 
 	return fmt.Sprintf("%s %s %s %s", ts, level, scope, message)
 }
 
+// highlightLogSearch renders s in base, with each case-insensitive occurrence
+// of the log search rendered in LogMatchStyle.
+func (m Model) highlightLogSearch(s string, base lipgloss.Style) string {
+	lower := strings.ToLower(s)
+	needle := strings.ToLower(m.logSearch)
+	// Offsets into lower only map onto s when lowercasing kept byte lengths.
+	if needle == "" || len(lower) != len(s) {
+		return base.Render(s)
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			break
+		}
+		if i > 0 {
+			sb.WriteString(base.Render(s[:i]))
+		}
+		sb.WriteString(m.styles.LogMatchStyle().Render(s[i : i+len(needle)]))
+		s, lower = s[i+len(needle):], lower[i+len(needle):]
+	}
+	if s != "" {
+		sb.WriteString(base.Render(s))
+	}
+	return sb.String()
+}
+
 // renderLogLevelCheckboxes returns the inline level filter checkboxes for the log panel header.
 func (m Model) renderLogLevelCheckboxes() string {
 	style := lipgloss.NewStyle().Foreground(lipgloss.Color(m.styles.flavor.Overlay0().Hex))
@@ -700,6 +995,10 @@ func (m Model) renderLogLevelCheckboxes() string {
 
 // renderLogPanel renders the log panel content.
 func (m Model) renderLogPanel(layout Layout) string {
+	if m.containerTail.id != "" {
+		return m.renderContainerLogPanel(layout)
+	}
+
 	// Calculate widths based on whether details panel is open
 	logListWidth := layout.Logs.Width
 	if m.logDetailsOpen && m.logDetailsReady {
@@ -723,7 +1022,11 @@ func (m Model) renderLogPanel(layout Layout) string {
 	levelCheckboxes := m.renderLogLevelCheckboxes()
 
 	// Log list panel (enforce height so status bar stays pinned to bottom)
-	header := headerStyle.Width(logListWidth).Render(fmt.Sprintf(" Logs (%s)  %s", filterInfo, levelCheckboxes))
+	headerText := fmt.Sprintf(" Logs (%s)  %s", filterInfo, levelCheckboxes)
+	if m.logsDropped > 0 {
+		headerText += fmt.Sprintf("  %d logs dropped", m.logsDropped)
+	}
+	header := headerStyle.Width(logListWidth).Render(headerText)
 	logListBody := lipgloss.NewStyle().
 		Width(logListWidth).
 		Height(layout.Logs.Height - 1).
@@ -753,7 +1056,11 @@ func (m Model) renderLogPanel(layout Layout) string {
 	divider := strings.Join(dividerLines, "\n")
 
 	// Details panel
-	detailsHeader := m.styles.PanelHeaderUnfocusedStyle().Width(detailsWidth).Render(" Details")
+	detailsTitle := " Details"
+	if m.logDetailsWrap {
+		detailsTitle += " [wrap]"
+	}
+	detailsHeader := m.styles.PanelHeaderUnfocusedStyle().Width(detailsWidth).Render(detailsTitle)
 	detailsContent := m.logDetailsViewport.View()
 	detailsPanel := lipgloss.JoinVertical(lipgloss.Left, detailsHeader, detailsContent)
 
@@ -761,6 +1068,36 @@ func (m Model) renderLogPanel(layout Layout) string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, logListPanel, divider, detailsPanel)
 }
 
+// renderContainerLogPanel renders the followed container output in place of
+// devagent's logs: the newest lines that fit, each cut to the panel width.
+func (m Model) renderContainerLogPanel(layout Layout) string {
+	headerStyle := m.styles.PanelHeaderUnfocusedStyle()
+	if m.panelFocus == FocusLogs {
+		headerStyle = m.styles.PanelHeaderFocusedStyle()
+	}
+	state := "following, f to stop"
+	if m.containerTail.ended {
+		state = "stream ended, f to close"
+	}
+	header := headerStyle.Width(layout.Logs.Width).Render(fmt.Sprintf(" Container logs (%s)  %s", m.containerTail.name, state))
+
+	bodyHeight := max(layout.Logs.Height-1, 0)
+	lines := m.containerTail.lines
+	if len(lines) > bodyHeight {
+		lines = lines[len(lines)-bodyHeight:]
+	}
+	content := m.styles.InfoStyle().Render("Waiting for output...")
+	if len(lines) > 0 {
+		cut := make([]string, len(lines))
+		for i, line := range lines {
+			cut[i] = truncateString(line, layout.Logs.Width)
+		}
+		content = strings.Join(cut, "\n")
+	}
+	body := lipgloss.NewStyle().Width(layout.Logs.Width).Height(bodyHeight).Render(content)
+	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+}
+
 // renderLogListContent renders the log entries with selection indicator.
 func (m Model) renderLogListContent(width, height int) string {
 	entries := m.filteredLogEntries()
@@ -853,6 +1190,8 @@ func (m Model) renderTreeItem(idx int, item TreeItem, selected bool) string {
 		line = m.renderWorktreeTreeItem(item, cursor, selected)
 	case TreeItemContainer:
 		line = m.renderContainerTreeItem(item, cursor, selected)
+	case TreeItemWindow:
+		line = m.renderWindowTreeItem(idx, item, cursor)
 	default:
 		line = m.renderSessionTreeItem(idx, item, cursor)
 	}
@@ -871,9 +1210,18 @@ func (m Model) renderAllProjectsTreeItem(cursor string, selected bool) string {
 	}
 
 	projectCount := len(m.discoveredProjects)
-	containerCount := len(m.containerList.Items())
+	containerCount := m.filteredContainerCount()
+	if m.treeFilter != "" {
+		// Only projects that survived the filter are shown in the tree
+		projectCount = 0
+		for _, item := range m.treeItems {
+			if item.Type == TreeItemProject && item.ProjectPath != "" {
+				projectCount++
+			}
+		}
+	}
 
-	if projectCount > 0 {
+	if len(m.discoveredProjects) > 0 {
 		return fmt.Sprintf("%s%s All Projects (%d) — %d containers",
 			cursor, icon, projectCount, containerCount)
 	}
@@ -951,6 +1299,24 @@ func (m Model) renderWorktreeTreeItem(item TreeItem, cursor string, selected boo
 	}
 
 	name := item.WorktreeName
+	var badges []string
+	if item.Locked {
+		badges = append(badges, "[locked]")
+	}
+	if item.Prunable {
+		badges = append(badges, "[prunable]")
+	}
+	if len(badges) > 0 {
+		badge := strings.Join(badges, " ")
+		if !selected {
+			if item.Prunable {
+				badge = m.styles.ErrorStyle().Render(badge)
+			} else {
+				badge = m.styles.InfoStyle().Render(badge)
+			}
+		}
+		name += " " + badge
+	}
 	return fmt.Sprintf("%s   %s %s", cursor, stateIcon, name)
 }
 
@@ -984,7 +1350,7 @@ func (m Model) renderContainerTreeItem(item TreeItem, cursor string, selected bo
 		switch c.State {
 		case container.StateRunning:
 			stateIcon = "●"
-		case container.StateStopped:
+		case container.StateStopped, container.StateExited:
 			stateIcon = "○"
 		default:
 			stateIcon = "◌"
@@ -995,20 +1361,30 @@ func (m Model) renderContainerTreeItem(item TreeItem, cursor string, selected bo
 			stateIcon = m.styles.SuccessStyle().Render("●")
 		case container.StateStopped:
 			stateIcon = m.styles.InfoStyle().Render("○")
+		case container.StateExited:
+			stateIcon = m.styles.ErrorStyle().Render("○")
 		default:
 			stateIcon = m.styles.InfoStyle().Render("◌")
 		}
 	}
 
 	name := c.Name
-	state := string(c.State)
+	state := containerStateLabel(c)
 
 	// Indent containers under worktrees when projects are discovered
 	indent := ""
 	if len(m.discoveredProjects) > 0 {
 		indent = "     "
 	}
-	return fmt.Sprintf("%s%s%s %s %s [%s]", cursor, indent, indicator, stateIcon, name, state)
+	row := fmt.Sprintf("%s%s%s %s %s [%s]", cursor, indent, indicator, stateIcon, name, state)
+	if note := m.manager.Note(c.ProjectPath); note != "" {
+		if selected {
+			row += "  " + note
+		} else {
+			row += "  " + m.styles.HelpStyle().Render(note)
+		}
+	}
+	return row
 }
 
 // renderSessionTreeItem renders a session in the tree (indented under container).
@@ -1033,18 +1409,8 @@ func (m Model) renderSessionTreeItem(idx int, item TreeItem, cursor string) stri
 		return cursor + "    └─ (unknown session)"
 	}
 
-	// Determine if this is the last session under its container:
-	// Check if the next tree item is NOT a session for the same container
-	isLast := true
-	if idx+1 < len(m.treeItems) {
-		next := m.treeItems[idx+1]
-		if next.Type == TreeItemSession && next.ContainerID == item.ContainerID {
-			isLast = false
-		}
-	}
-
 	connector := "├─"
-	if isLast {
+	if m.isLastSessionItem(idx) {
 		connector = "└─"
 	}
 
@@ -1062,6 +1428,57 @@ func (m Model) renderSessionTreeItem(idx int, item TreeItem, cursor string) stri
 	return fmt.Sprintf("%s%s%s %s%s", cursor, indent, connector, sess.Name, attachedIndicator)
 }
 
+// isLastSessionItem reports whether the session at idx is the last one under
+// its container, looking past the session's own windows.
+func (m Model) isLastSessionItem(idx int) bool {
+	item := m.treeItems[idx]
+	for _, next := range m.treeItems[idx+1:] {
+		if next.Type == TreeItemWindow && next.ContainerID == item.ContainerID && next.SessionName == item.SessionName {
+			continue
+		}
+		return next.Type != TreeItemSession || next.ContainerID != item.ContainerID
+	}
+	return true
+}
+
+// renderWindowTreeItem renders a tmux window in the tree (indented under its
+// session), marking the session's active window with "*".
+func (m Model) renderWindowTreeItem(idx int, item TreeItem, cursor string) string {
+	w := m.windowForItem(item)
+	if w == nil {
+		return cursor + "        └─ (unknown window)"
+	}
+
+	// Find the window's session to continue its branch line
+	sessIdx := idx
+	for sessIdx > 0 && m.treeItems[sessIdx].Type == TreeItemWindow {
+		sessIdx--
+	}
+	branch := "│  "
+	if m.isLastSessionItem(sessIdx) {
+		branch = "   "
+	}
+
+	connector := "└─"
+	if idx+1 < len(m.treeItems) {
+		next := m.treeItems[idx+1]
+		if next.Type == TreeItemWindow && next.ContainerID == item.ContainerID && next.SessionName == item.SessionName {
+			connector = "├─"
+		}
+	}
+
+	active := ""
+	if w.Active {
+		active = "*"
+	}
+
+	indent := "    "
+	if len(m.discoveredProjects) > 0 {
+		indent = "        "
+	}
+	return fmt.Sprintf("%s%s%s%s %d:%s%s", cursor, indent, branch, connector, w.Index, w.Name, active)
+}
+
 // renderDetailPanel renders the detail panel for the selected item.
 func (m Model) renderDetailPanel(layout Layout) string {
 	if layout.Detail.Width == 0 {
@@ -1099,17 +1516,22 @@ func (m Model) renderDetailPanel(layout Layout) string {
 
 // renderAllProjectsDetailContent renders the summary detail for "All Projects".
 func (m Model) renderAllProjectsDetailContent() string {
-	var running, stopped, created, totalSessions int
+	var running, stopped, exited, paused, created, totalSessions int
 	for _, item := range m.containerList.Items() {
 		ci, ok := item.(containerItem)
 		if !ok {
 			continue
 		}
-		switch ci.container.State {
-		case container.StateRunning:
+		switch {
+		case ci.container.IsRunning():
 			running++
-		case container.StateStopped:
+		case ci.container.IsStopped():
 			stopped++
+			if ci.container.State == container.StateExited {
+				exited++
+			}
+		case ci.container.State == container.StatePaused:
+			paused++
 		default:
 			created++
 		}
@@ -1122,6 +1544,12 @@ func (m Model) renderAllProjectsDetailContent() string {
 		fmt.Sprintf("Running:    %d", running),
 		fmt.Sprintf("Stopped:    %d", stopped),
 	}
+	if exited > 0 {
+		lines = append(lines, fmt.Sprintf("  Failed:   %d", exited))
+	}
+	if paused > 0 {
+		lines = append(lines, fmt.Sprintf("Paused:     %d", paused))
+	}
 	if created > 0 {
 		lines = append(lines, fmt.Sprintf("Created:    %d", created))
 	}
@@ -1131,20 +1559,23 @@ func (m Model) renderAllProjectsDetailContent() string {
 	return strings.Join(lines, "\n")
 }
 
-// renderProjectDetailContent renders detail content for a project.
+// renderProjectDetailContent renders detail content for a project: path,
+// Makefile, worktree count and its containers counted by state.
 func (m Model) renderProjectDetailContent(item TreeItem) string {
 	lines := []string{
-		fmt.Sprintf("Project:  %s", item.ProjectName),
-		fmt.Sprintf("Path:     %s", item.ProjectPath),
+		fmt.Sprintf("Project:    %s", item.ProjectName),
+		fmt.Sprintf("Path:       %s", item.ProjectPath),
 	}
 
 	// Find the project
 	for _, p := range m.discoveredProjects {
 		if p.Path == item.ProjectPath {
-			lines = append(lines, fmt.Sprintf("Makefile: %v", p.HasMakefile))
 			containers := m.findContainersForProject(p)
-			lines = append(lines, fmt.Sprintf("Containers: %d", len(containers)))
-			lines = append(lines, fmt.Sprintf("Worktrees: %d", len(p.Worktrees)))
+			lines = append(lines,
+				fmt.Sprintf("Makefile:   %s", yesNo(p.HasMakefile)),
+				fmt.Sprintf("Worktrees:  %d", len(p.Worktrees)),
+				fmt.Sprintf("Containers: %d%s", len(containers), containerStateCounts(containers)),
+			)
 
 			if len(p.Worktrees) > 0 {
 				lines = append(lines, "", "Worktrees:")
@@ -1159,17 +1590,36 @@ func (m Model) renderProjectDetailContent(item TreeItem) string {
 	return strings.Join(lines, "\n")
 }
 
-// renderWorktreeDetailContent renders detail content for a worktree.
+// renderWorktreeDetailContent renders detail content for a worktree: branch,
+// path, whether it is the project's main worktree, and its container's state.
 func (m Model) renderWorktreeDetailContent(item TreeItem) string {
+	isMain := false
+	for _, p := range m.discoveredProjects {
+		if p.Path == item.ProjectPath {
+			isMain = true
+			break
+		}
+	}
+
 	lines := []string{
-		fmt.Sprintf("Worktree: %s", item.WorktreeName),
-		fmt.Sprintf("Path:     %s", item.ProjectPath),
+		fmt.Sprintf("Branch:    %s", item.WorktreeName),
+		fmt.Sprintf("Path:      %s", item.ProjectPath),
+		fmt.Sprintf("Main:      %s", yesNo(isMain)),
+	}
+	if item.Locked {
+		lines = append(lines, "Locked:    yes")
+	}
+	if item.Prunable {
+		lines = append(lines, "Prunable:  yes (directory is gone)")
 	}
 
 	containers := m.findContainersForPath(item.ProjectPath)
-	lines = append(lines, fmt.Sprintf("Containers: %d", len(containers)))
-
-	if len(containers) > 0 {
+	switch len(containers) {
+	case 0:
+		lines = append(lines, "Container: none")
+	case 1:
+		lines = append(lines, fmt.Sprintf("Container: %s [%s]", containers[0].Name, containers[0].State))
+	default:
 		lines = append(lines, "", "Containers:")
 		for _, c := range containers {
 			lines = append(lines, fmt.Sprintf("  • %s [%s]", c.Name, c.State))
@@ -1179,6 +1629,53 @@ func (m Model) renderWorktreeDetailContent(item TreeItem) string {
 	return strings.Join(lines, "\n")
 }
 
+// vscodeHint is the help entry for v, empty when the code CLI is missing.
+func (m Model) vscodeHint() string {
+	if !m.vscodeAvailable {
+		return ""
+	}
+	return "v: VS Code • "
+}
+
+// containerStateLabel describes a container's state, with the exit code when
+// it exited non-zero (e.g. "exited 137").
+func containerStateLabel(c *container.Container) string {
+	if c.State == container.StateExited {
+		return fmt.Sprintf("%s %d", c.State, c.ExitCode)
+	}
+	return string(c.State)
+}
+
+// containerStateCounts formats containers counted by state, e.g.
+// " (2 running, 1 stopped)", or "" when there are none.
+func containerStateCounts(containers []*container.Container) string {
+	counts := make(map[container.ContainerState]int)
+	var order []container.ContainerState
+	for _, c := range containers {
+		if counts[c.State] == 0 {
+			order = append(order, c.State)
+		}
+		counts[c.State]++
+	}
+	if len(order) == 0 {
+		return ""
+	}
+	slices.Sort(order)
+	parts := make([]string, len(order))
+	for i, state := range order {
+		parts[i] = fmt.Sprintf("%d %s", counts[state], state)
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// yesNo renders a bool as "yes" or "no".
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
 // renderContainerDetailContent renders detail content for a container.
 func (m Model) renderContainerDetailContent() string {
 	if m.selectedContainer == nil {
@@ -1191,11 +1688,29 @@ func (m Model) renderContainerDetailContent() string {
 	lines := []string{
 		fmt.Sprintf("Name:     %s", c.Name),
 		fmt.Sprintf("ID:       %s", c.ID),
-		fmt.Sprintf("State:    %s", string(c.State)),
+		"State:    " + containerStateLabel(c),
 		fmt.Sprintf("Template: %s", c.Template),
 		fmt.Sprintf("Project:  %s", c.ProjectPath),
 		fmt.Sprintf("Sessions: %d", len(c.Sessions)),
 	}
+	if m.manager != nil {
+		if note := m.manager.Note(c.ProjectPath); note != "" {
+			lines = append(lines, fmt.Sprintf("Note:     %s", note))
+		}
+		if last, ok := m.manager.LastActivity(c.ID); ok {
+			lines = append(lines, fmt.Sprintf("Activity: %s", formatActivityAge(time.Since(last))))
+		}
+	}
+
+	// Crashed container: point at the logs for the cause
+	if c.State == container.StateExited {
+		lines = append(lines, "", m.styles.ErrorStyle().Render(fmt.Sprintf("✗ Exited with code %d (e: save its logs)", c.ExitCode)))
+	}
+
+	// Degraded sidecar (e.g. proxy died while the container runs)
+	if c.SidecarWarning != "" {
+		lines = append(lines, "", m.styles.ErrorStyle().Render("⚠ Degraded: "+c.SidecarWarning))
+	}
 
 	// List sessions if any
 	if len(c.Sessions) > 0 {
@@ -1209,12 +1724,33 @@ func (m Model) renderContainerDetailContent() string {
 		}
 	}
 
+	if c.State == container.StateRunning && len(m.cachedPorts) > 0 {
+		lines = append(lines, "", "Ports:")
+		for _, p := range m.cachedPorts {
+			lines = append(lines, fmt.Sprintf("  %d/%s → localhost:%d", p.ContainerPort, p.Protocol, p.HostPort))
+		}
+	}
+
 	// Always show isolation section (actual values, loading, or unknown placeholders)
 	lines = append(lines, m.renderIsolationSection(c.State, m.cachedIsolationInfo)...)
 
 	return strings.Join(lines, "\n")
 }
 
+// formatActivityAge renders how long ago a container was last active.
+func formatActivityAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
 // renderIsolationInfo formats isolation details for display.
 func (m Model) renderIsolationInfo(info *container.IsolationInfo) []string {
 	var lines []string
@@ -1365,6 +1901,30 @@ func (m Model) renderSessionDetailContent() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderWindowDetailContent renders detail content for a tmux window.
+func (m Model) renderWindowDetailContent(item TreeItem) string {
+	w := m.windowForItem(item)
+	if m.selectedContainer == nil || w == nil {
+		return "No window selected"
+	}
+
+	activeStr := "No"
+	if w.Active {
+		activeStr = "Yes"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Index:     %d", w.Index),
+		fmt.Sprintf("Name:      %s", w.Name),
+		fmt.Sprintf("Command:   %s", w.Command),
+		fmt.Sprintf("Active:    %s", activeStr),
+		fmt.Sprintf("Session:   %s", item.SessionName),
+		fmt.Sprintf("Container: %s", m.selectedContainer.Name),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // renderLogEntryDetails renders the full details of a log entry.
 // For proxy requests, shows full request/response details.
 // For regular logs, shows the Fields map as key-value pairs.
@@ -12,19 +12,22 @@ func TestContextualHelp_VSCodeHint(t *testing.T) {
 	tests := []struct {
 		name     string
 		item     TreeItem
+		noCode   bool // the code CLI is not on PATH
 		wantHint bool
 	}{
-		{"container shows v", TreeItem{Type: TreeItemContainer, ContainerID: "abc"}, true},
-		{"session shows v", TreeItem{Type: TreeItemSession, ContainerID: "abc", SessionName: "dev"}, true},
-		{"project omits v", TreeItem{Type: TreeItemProject, ProjectPath: "/p"}, false},
-		{"worktree omits v", TreeItem{Type: TreeItemWorktree, ProjectPath: "/p", WorktreeName: "main"}, false},
-		{"all-projects omits v", TreeItem{Type: TreeItemAllProjects}, false},
+		{"container shows v", TreeItem{Type: TreeItemContainer, ContainerID: "abc"}, false, true},
+		{"session shows v", TreeItem{Type: TreeItemSession, ContainerID: "abc", SessionName: "dev"}, false, true},
+		{"container without code omits v", TreeItem{Type: TreeItemContainer, ContainerID: "abc"}, true, false},
+		{"project omits v", TreeItem{Type: TreeItemProject, ProjectPath: "/p"}, false, false},
+		{"worktree omits v", TreeItem{Type: TreeItemWorktree, ProjectPath: "/p", WorktreeName: "main"}, false, false},
+		{"all-projects omits v", TreeItem{Type: TreeItemAllProjects}, false, false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			m := newTreeTestModel(t)
 			m.panelFocus = FocusTree
+			m.vscodeAvailable = !tt.noCode
 			m.treeItems = []TreeItem{tt.item}
 			m.selectedIdx = 0
 
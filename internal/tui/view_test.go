@@ -6,8 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
 	"devagent/internal/container"
+	"devagent/internal/discovery"
+	"devagent/internal/events"
 	"devagent/internal/logging"
+	"devagent/internal/worktree"
 )
 
 // Sessions tab content tests removed - sessions now shown in tree view
@@ -337,6 +344,26 @@ func TestRenderWorktreeTreeItem_ContainerlessWorktree(t *testing.T) {
 	}
 }
 
+func TestRenderWorktreeTreeItem_LockedAndPrunableBadges(t *testing.T) {
+	m := newTestModel(t)
+
+	item := TreeItem{
+		Type:         TreeItemWorktree,
+		ProjectPath:  "/path/to/worktree",
+		WorktreeName: "feature-branch",
+	}
+	if result := m.renderWorktreeTreeItem(item, ">", true); strings.Contains(result, "[") {
+		t.Errorf("plain worktree should have no badge, got %q", result)
+	}
+
+	item.Locked = true
+	item.Prunable = true
+	result := m.renderWorktreeTreeItem(item, ">", true)
+	if !strings.Contains(result, "feature-branch [locked] [prunable]") {
+		t.Errorf("result = %q, want locked and prunable badges", result)
+	}
+}
+
 func TestContextualHelp_ContainerlessWorktree(t *testing.T) {
 	m := newTestModel(t)
 
@@ -398,3 +425,169 @@ func TestContextualHelp_WorktreeWithContainer(t *testing.T) {
 		t.Error("help text should include 'c: create container'")
 	}
 }
+
+func TestFormatActivityAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{5*time.Minute + 30*time.Second, "5m ago"},
+		{3*time.Hour + 10*time.Minute, "3h ago"},
+		{50 * time.Hour, "2d ago"},
+	}
+	for _, tt := range tests {
+		if got := formatActivityAge(tt.age); got != tt.want {
+			t.Errorf("formatActivityAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestRenderProjectDetailContent(t *testing.T) {
+	m := newTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{{
+		Name:        "myproj",
+		Path:        "/src/myproj",
+		HasMakefile: true,
+		Worktrees:   []discovery.Worktree{{Name: "feature", Branch: "feature", Path: "/src/myproj-feature"}},
+	}}
+	updated, _ := m.Update(containersRefreshedMsg{containers: []*container.Container{
+		{ID: "a", Name: "myproj", State: container.StateRunning, ComposeProject: "myproj"},
+		{ID: "b", Name: "myproj-feature", State: container.StateStopped, ComposeProject: worktree.ComposeName("/src/myproj", "feature")},
+	}})
+	m = updated.(Model)
+
+	content := m.renderProjectDetailContent(TreeItem{Type: TreeItemProject, ProjectName: "myproj", ProjectPath: "/src/myproj"})
+
+	for _, want := range []string{
+		"/src/myproj",
+		"Makefile:   yes",
+		"Worktrees:  1",
+		"Containers: 2 (1 running, 1 stopped)",
+		"feature",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("project detail missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestRenderWorktreeDetailContent(t *testing.T) {
+	m := newTestModel(t)
+	m.discoveredProjects = []discovery.DiscoveredProject{{Name: "myproj", Path: "/src/myproj"}}
+	updated, _ := m.Update(containersRefreshedMsg{containers: []*container.Container{
+		{ID: "a", Name: "myproj-feature", State: container.StateRunning, ProjectPath: "/src/myproj-feature"},
+	}})
+	m = updated.(Model)
+
+	tests := []struct {
+		name string
+		item TreeItem
+		want []string
+	}{
+		{
+			name: "main worktree without container",
+			item: TreeItem{Type: TreeItemWorktree, ProjectPath: "/src/myproj", WorktreeName: "main"},
+			want: []string{"Branch:    main", "Path:      /src/myproj", "Main:      yes", "Container: none"},
+		},
+		{
+			name: "linked worktree with container",
+			item: TreeItem{Type: TreeItemWorktree, ProjectPath: "/src/myproj-feature", WorktreeName: "feature", Locked: true},
+			want: []string{"Branch:    feature", "Main:      no", "Locked:    yes", "Container: myproj-feature [running]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := m.renderWorktreeDetailContent(tt.item)
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("worktree detail missing %q:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestView_HeaderShowsTailscaleTrouble(t *testing.T) {
+	m := newTestModel(t)
+	m.width, m.height = 160, 40
+
+	updated, _ := m.Update(events.TailscaleStatusMsg{Status: "restarting"})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "[tailscale restarting]") {
+		t.Error("header should flag a restarting tailscale proxy")
+	}
+
+	updated, _ = m.Update(events.TailscaleStatusMsg{Status: "running"})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "[tailscale") {
+		t.Error("header should not flag a running tailscale proxy")
+	}
+}
+
+func TestLogDetails_WrapVersusHorizontalScroll(t *testing.T) {
+	m := newTestModel(t)
+	m.addLogEntry(logging.LogEntry{
+		Level:   "INFO",
+		Scope:   "app",
+		Message: "compose up finished for project alpha with warnings about the proxy sidecar END",
+	})
+	m.panelFocus = FocusLogs
+	m.logPanelOpen = true
+	m.logReady = true
+	m.logDetailsOpen = true
+	m.logDetailsViewport = viewport.New(30, 20)
+	m.logDetailsReady = true
+	m.updateLogDetailsContent()
+
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		updated, _ := m.Update(key)
+		m = updated.(Model)
+	}
+	maxWidth := func(view string) int {
+		widest := 0
+		for _, line := range strings.Split(view, "\n") {
+			widest = max(widest, lipgloss.Width(line))
+		}
+		return widest
+	}
+
+	unwrapped := m.logDetailsViewport.View()
+	unwrappedLines := m.logDetailsViewport.TotalLineCount()
+	if strings.Contains(unwrapped, "END") {
+		t.Errorf("unwrapped view should cut the message at the width, got:\n%s", unwrapped)
+	}
+	if w := maxWidth(unwrapped); w > 30 {
+		t.Errorf("unwrapped view is %d columns wide, want at most 30", w)
+	}
+
+	// Right scrolls unwrapped lines sideways; Left scrolls back before closing
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	if m.logDetailsViewport.View() == unwrapped {
+		t.Error("right should scroll the unwrapped details")
+	}
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if !m.logDetailsOpen || m.logDetailsViewport.View() != unwrapped {
+		t.Error("left should scroll back to the start without closing the details")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if !m.logDetailsWrap {
+		t.Fatal("w should turn wrapping on")
+	}
+	wrapped := m.logDetailsViewport.View()
+	if !strings.Contains(wrapped, "compose up") || !strings.Contains(wrapped, "sidecar END") {
+		t.Errorf("wrapped view should show the whole message, got:\n%s", wrapped)
+	}
+	if w := maxWidth(wrapped); w > 30 {
+		t.Errorf("wrapped view is %d columns wide, want at most 30", w)
+	}
+	if m.logDetailsViewport.TotalLineCount() <= unwrappedLines {
+		t.Error("wrapping should spread the message over more lines")
+	}
+
+	if got := m.selectedLogDetailsText(); !strings.Contains(got, "Message: compose up finished") || strings.Contains(got, "\x1b[") {
+		t.Errorf("copied text = %q, want the unstyled entry", got)
+	}
+}
@@ -3,9 +3,11 @@
 package web
 
 import (
+	"cmp"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"devagent/internal/audit"
 	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/events"
@@ -26,6 +29,7 @@ type ContainerResponse struct {
 	ID             string            `json:"id"`
 	Name           string            `json:"name"`
 	State          string            `json:"state"`
+	ExitCode       int               `json:"exit_code,omitempty"` // set when state is "exited" (non-zero exit)
 	Template       string            `json:"template"`
 	ProjectPath    string            `json:"project_path"`
 	RemoteUser     string            `json:"remote_user"`
@@ -33,6 +37,20 @@ type ContainerResponse struct {
 	Ports          map[string]string `json:"ports"`
 	CreatedAt      time.Time         `json:"created_at"`
 	Sessions       []SessionResponse `json:"sessions"`
+	Unmanaged      bool              `json:"unmanaged,omitempty"` // only in GET /api/projects?all=true
+	// LastActivity is the last exec, session create, send or terminal attach
+	// seen by this instance; omitted if there was none since startup.
+	LastActivity *time.Time `json:"last_activity,omitempty"`
+	// Mounts is only set by GET /api/containers/{id} for a running container;
+	// list endpoints skip it to avoid one inspect per container.
+	Mounts []container.MountInfo `json:"mounts,omitempty"`
+	// PublishedPorts, like Mounts, is only set by GET /api/containers/{id}
+	// for a running container. (Ports holds the host ports allocated at
+	// create time, keyed by compose env var.)
+	PublishedPorts []container.PortMapping `json:"published_ports,omitempty"`
+	// Note is the user's devagent.note label (see POST .../labels); omitted
+	// when unset.
+	Note string `json:"note,omitempty"`
 }
 
 // SessionResponse is the JSON representation of a tmux session.
@@ -59,6 +77,52 @@ type WorktreeResponse struct {
 	Container *ContainerResponse `json:"container"`
 }
 
+// WorktreeInfoResponse is the JSON representation of a worktree reported
+// directly by git (GET /api/projects/{encodedPath}/worktrees).
+type WorktreeInfoResponse struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch"`
+	IsMain   bool   `json:"is_main"`
+	Locked   bool   `json:"locked"`
+	Prunable bool   `json:"prunable"`
+}
+
+// OperationResponse is the JSON representation of an in-flight container
+// operation (GET /api/operations).
+type OperationResponse struct {
+	ID        string    `json:"id"`     // container ID, or the container name for a create
+	Action    string    `json:"action"` // create, start, stop or destroy
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ContainersPageResponse is one page of GET /api/containers, returned when
+// ?limit or ?offset is given.
+type ContainersPageResponse struct {
+	Containers []ContainerResponse `json:"containers"`
+	Total      int                 `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// maxContainersPageLimit bounds ?limit on GET /api/containers; it is also the
+// limit when only ?offset is given.
+const maxContainersPageLimit = 500
+
+// PruneResponse is the JSON representation of a prune result (POST /api/prune).
+type PruneResponse struct {
+	Removed []string `json:"removed"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ConfirmRequiredResponse is the 412 body of a request that would destroy
+// more containers than the destroy threshold without ?confirm=true.
+type ConfirmRequiredResponse struct {
+	Error     string `json:"error"`
+	Count     int    `json:"count"`     // containers the request would destroy
+	Threshold int    `json:"threshold"` // most allowed without confirm=true
+}
+
 // ProjectsListResponse wraps the projects list with unmatched containers.
 // Unmatched containers are those not belonging to any discovered project.
 type ProjectsListResponse struct {
@@ -73,12 +137,14 @@ func (s *Server) buildContainerResponse(ctx context.Context, c *container.Contai
 		ID:             c.ID,
 		Name:           c.Name,
 		State:          string(c.State),
+		ExitCode:       c.ExitCode,
 		Template:       c.Template,
 		ProjectPath:    c.ProjectPath,
 		RemoteUser:     c.RemoteUser,
 		ComposeProject: c.ComposeProject,
 		CreatedAt:      c.CreatedAt,
 		Sessions:       []SessionResponse{},
+		Unmanaged:      c.Unmanaged,
 	}
 
 	resp.Ports = c.Ports
@@ -86,6 +152,11 @@ func (s *Server) buildContainerResponse(ctx context.Context, c *container.Contai
 		resp.Ports = make(map[string]string) // ensure JSON serializes as {} not null
 	}
 
+	if t, ok := s.manager.LastActivity(c.ID); ok {
+		resp.LastActivity = &t
+	}
+	resp.Note = s.manager.Note(c.ProjectPath)
+
 	if c.IsRunning() {
 		sessions, err := s.manager.ListSessions(ctx, c.ID)
 		if err == nil {
@@ -104,37 +175,142 @@ func (s *Server) buildContainerResponse(ctx context.Context, c *container.Contai
 
 // handleListContainers handles GET /api/containers.
 // Returns JSON array of all managed containers. Populates sessions for running containers.
+// Optional ?sort=name|state|created and ?order=asc|desc control ordering (default name asc).
+// With ?limit=N (1..500) and/or ?offset=N the sorted list is paged and wrapped
+// in a ContainersPageResponse; sorting is stable, so pages do not overlap.
+// Returns 400 for unknown sort keys or orders and out-of-range limits or offsets.
 func (s *Server) handleListContainers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key, err := container.ParseSortKey(query.Get("sort"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	order := query.Get("order")
+	if order != "" && order != "asc" && order != "desc" {
+		writeError(w, http.StatusBadRequest, "invalid order (want asc or desc)")
+		return
+	}
+
+	paged := query.Has("limit") || query.Has("offset")
+	limit, offset := maxContainersPageLimit, 0
+	if query.Has("limit") {
+		n, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || n < 1 || n > maxContainersPageLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit parameter (want 1..%d)", maxContainersPageLimit))
+			return
+		}
+		limit = n
+	}
+	if query.Has("offset") {
+		n, err := strconv.Atoi(query.Get("offset"))
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid offset parameter")
+			return
+		}
+		offset = n
+	}
+
 	containers := s.manager.List()
-	result := make([]ContainerResponse, 0, len(containers))
+	container.SortContainers(containers, key, order == "desc")
+	total := len(containers)
+	if paged {
+		start := min(offset, total)
+		containers = containers[start : start+min(limit, total-start)]
+	}
 
+	result := make([]ContainerResponse, 0, len(containers))
 	for _, c := range containers {
 		result = append(result, s.buildContainerResponse(r.Context(), c))
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	if !paged {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+	writeJSON(w, http.StatusOK, ContainersPageResponse{
+		Containers: result,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	})
 }
 
 // handleGetContainer handles GET /api/containers/{id}.
-// Returns single container JSON including sessions. Returns 404 for unknown IDs.
+// Returns single container JSON including sessions and, for a running
+// container, its mounts and published ports (each omitted if its inspect
+// fails). Returns 404 for unknown IDs.
 func (s *Server) handleGetContainer(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainer(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, s.buildContainerResponse(r.Context(), c))
+	resp := s.buildContainerResponse(r.Context(), c)
+	if c.IsRunning() {
+		mounts, err := s.manager.GetMounts(r.Context(), c.ID)
+		if err == nil {
+			resp.Mounts = mounts
+		}
+		if ports, err := s.manager.GetPorts(r.Context(), c.ID); err == nil {
+			resp.PublishedPorts = ports
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleGetSnapshot handles GET /api/containers/{id}/snapshot.
+// Returns the configuration recorded when the container was created.
+// Returns 404 for unknown containers or containers without a snapshot.
+func (s *Server) handleGetSnapshot(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	snapshot, err := s.manager.GetCreationSnapshot(c.ID)
+	if errors.Is(err, container.ErrSnapshotNotFound) {
+		writeError(w, http.StatusNotFound, "no creation snapshot for container")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to read creation snapshot")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+// handleInspectContainer handles GET /api/containers/{id}/inspect.
+// Returns the runtime's inspect JSON verbatim (container.Manager.InspectRaw,
+// cached for a few seconds). Returns 404 for unknown containers, 500 if the
+// runtime inspect fails.
+func (s *Server) handleInspectContainer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	data, err := s.manager.InspectRaw(r.Context(), c.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to inspect container")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
 }
 
 // handleListSessions handles GET /api/containers/{id}/sessions.
 // Returns sessions for a container. Returns 404 for unknown container IDs.
 func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainer(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
 		return
 	}
 
@@ -164,9 +340,34 @@ type CreateSessionRequest struct {
 // CreateWorktreeRequest is the JSON body for creating a git worktree.
 type CreateWorktreeRequest struct {
 	Name    string `json:"name"`
+	Base    string `json:"base"` // Optional ref to branch from; empty branches from HEAD
 	NoStart bool   `json:"no_start"`
 }
 
+// CloneProjectRequest is the JSON body for POST /api/projects/clone.
+type CloneProjectRequest struct {
+	URL      string   `json:"url"`
+	Template string   `json:"template"` // default "basic"
+	Name     string   `json:"name"`     // container name; default derived like a normal create
+	Mounts   []string `json:"mounts"`   // extra docker --mount strings for the app service
+}
+
+// CreateContainerRequest is the JSON body for POST /api/containers.
+type CreateContainerRequest struct {
+	ProjectPath string   `json:"project_path"`
+	Template    string   `json:"template"`     // default: the project's template, else "basic"
+	Name        string   `json:"name"`         // default: the sanitized project directory name
+	Mounts      []string `json:"mounts"`       // extra docker --mount strings for the app service
+	Force       bool     `json:"force"`        // create even if the project already has a container
+	UseExisting bool     `json:"use_existing"` // keep the project's own .devcontainer/devcontainer.json
+}
+
+// JobAcceptedResponse is the 202 body of POST /api/containers.
+type JobAcceptedResponse struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
 // decodeProjectPath decodes a base64-URL-encoded project path from the URL.
 func decodeProjectPath(encoded string) (string, error) {
 	decoded, err := base64.URLEncoding.DecodeString(encoded)
@@ -193,31 +394,15 @@ func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainer(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
-		return
-	}
-
-	if !c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is not running")
 		return
 	}
 
-	sessions, err := s.manager.ListSessions(r.Context(), c.ID)
+	err := s.manager.CreateSession(r.Context(), c.ID, req.Name)
+	s.record(r, "session.create", c.ID+"/"+req.Name, err)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list sessions")
-		return
-	}
-	for _, sess := range sessions {
-		if sess.Name == req.Name {
-			writeError(w, http.StatusConflict, "session already exists")
-			return
-		}
-	}
-
-	if err := s.manager.CreateSession(r.Context(), c.ID, req.Name); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to create session")
+		writeManagerError(w, err, "failed to create session")
 		return
 	}
 
@@ -234,19 +419,15 @@ func (s *Server) handleDestroySession(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainerExact(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
-		return
-	}
-
-	if !c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is not running")
 		return
 	}
 
-	if err := s.manager.KillSession(r.Context(), c.ID, name); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to destroy session")
+	err := s.manager.KillSession(r.Context(), c.ID, name)
+	s.record(r, "session.kill", c.ID+"/"+name, err)
+	if err != nil {
+		writeManagerError(w, err, "failed to destroy session")
 		return
 	}
 
@@ -262,19 +443,15 @@ func (s *Server) handleDestroySession(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleStartContainer(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainer(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
-		return
-	}
-
-	if c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is already running")
 		return
 	}
 
-	if err := s.manager.StartWithCompose(r.Context(), c.ID); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to start container")
+	err := s.manager.StartWithCompose(r.Context(), c.ID)
+	s.record(r, "container.start", c.ID, err)
+	if err != nil {
+		writeManagerError(w, err, "failed to start container")
 		return
 	}
 
@@ -290,19 +467,15 @@ func (s *Server) handleStartContainer(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleStopContainer(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainerExact(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
-		return
-	}
-
-	if !c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is not running")
 		return
 	}
 
-	if err := s.manager.StopWithCompose(r.Context(), c.ID); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to stop container")
+	err := s.manager.StopWithCompose(r.Context(), c.ID)
+	s.record(r, "container.stop", c.ID, err)
+	if err != nil {
+		writeManagerError(w, err, "failed to stop container")
 		return
 	}
 
@@ -313,19 +486,26 @@ func (s *Server) handleStopContainer(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleDestroyContainer handles DELETE /api/containers/{id}.
-// Destroys a container via docker-compose down. Returns 404 if container not found,
-// 500 on internal error.
+// Destroys a container via docker-compose down. Only the container goes: the
+// project directory and any git worktree it lives in are never touched (use
+// handleDeleteWorktree to remove a worktree with its container). The project's
+// proxy certificates are kept for a later container unless ?purge=true. A
+// container protected by a do-not-delete note is refused with 409 unless
+// ?force=true. Returns 404 if container not found, 500 on internal error.
 func (s *Server) handleDestroyContainer(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
+	purge := r.URL.Query().Get("purge") == "true"
+	force := r.URL.Query().Get("force") == "true"
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainerExact(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
 		return
 	}
 
-	if err := s.manager.DestroyWithCompose(r.Context(), c.ID); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to destroy container")
+	err := s.manager.DestroyWithOptions(r.Context(), c.ID, container.DestroyOptions{Purge: purge, Force: force})
+	s.record(r, "container.destroy", c.ID, err)
+	if err != nil {
+		writeManagerError(w, err, "failed to destroy container")
 		return
 	}
 
@@ -335,9 +515,103 @@ func (s *Server) handleDestroyContainer(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, map[string]string{"status": "destroyed"})
 }
 
+// handleListOperations handles GET /api/operations.
+// Returns the in-flight container operations (from the TUI, the web API or any
+// other Manager caller), oldest first.
+func (s *Server) handleListOperations(w http.ResponseWriter, r *http.Request) {
+	ops := s.manager.Operations().List()
+	result := make([]OperationResponse, 0, len(ops))
+	for _, op := range ops {
+		result = append(result, OperationResponse{ID: op.ID, Action: op.Action, StartedAt: op.StartedAt})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handlePrune handles POST /api/prune.
+// Destroys all stopped devagent-managed containers and orphaned sidecars.
+// Returns the removed container IDs; on partial failure returns 500 with the
+// IDs that were removed and the error. A prune of more containers than the
+// destroy threshold needs ?confirm=true, else 412 with the count.
+func (s *Server) handlePrune(w http.ResponseWriter, r *http.Request) {
+	if !s.destroyConfirmed(w, r, len(s.manager.PruneCandidates())) {
+		return
+	}
+
+	removed, err := s.manager.Prune(r.Context())
+	s.record(r, "container.prune", strings.Join(removed, ","), err)
+	if removed == nil {
+		removed = []string{}
+	}
+
+	if s.notifyTUI != nil {
+		for _, id := range removed {
+			s.notifyTUI(events.WebSessionActionMsg{ContainerID: id})
+		}
+	}
+
+	resp := PruneResponse{Removed: removed}
+	if err != nil {
+		resp.Error = err.Error()
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// destroyConfirmed reports whether a request destroying count containers may
+// go ahead: count is within the destroy threshold, or the request carries
+// ?confirm=true. Otherwise it writes 412 with the count and returns false.
+func (s *Server) destroyConfirmed(w http.ResponseWriter, r *http.Request, count int) bool {
+	if count <= s.destroyThreshold || r.URL.Query().Get("confirm") == "true" {
+		return true
+	}
+	writeJSON(w, http.StatusPreconditionFailed, ConfirmRequiredResponse{
+		Error:     fmt.Sprintf("this would destroy %d containers (more than %d); repeat with confirm=true", count, s.destroyThreshold),
+		Count:     count,
+		Threshold: s.destroyThreshold,
+	})
+	return false
+}
+
+// handleListWorktrees handles GET /api/projects/{encodedPath}/worktrees.
+// Lists worktrees by running git directly, so it works for projects outside the
+// configured scan paths. Returns 400 for bad encoding, 404 if the path is not a
+// git repository, 500 on git failure.
+func (s *Server) handleListWorktrees(w http.ResponseWriter, r *http.Request) {
+	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project path encoding")
+		return
+	}
+
+	worktrees, err := s.worktreeOps.List(projectPath)
+	if err != nil {
+		if errors.Is(err, worktree.ErrNotGitRepo) {
+			writeError(w, http.StatusNotFound, "not a git repository")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to list worktrees: "+err.Error())
+		return
+	}
+
+	result := make([]WorktreeInfoResponse, 0, len(worktrees))
+	for _, wt := range worktrees {
+		result = append(result, WorktreeInfoResponse{
+			Name:     wt.Name,
+			Path:     wt.Path,
+			Branch:   wt.Branch,
+			IsMain:   wt.IsMain,
+			Locked:   wt.Locked,
+			Prunable: wt.Prunable,
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 // handleCreateWorktree handles POST /api/projects/{encodedPath}/worktrees.
-// Creates a git worktree and auto-starts a container for it.
-// Returns 400 for invalid name, 409 for duplicate branch, 500 on internal error.
+// Creates a git worktree (branching from the optional base ref) and auto-starts a container for it.
+// Returns 400 for invalid name or unknown base ref, 409 for duplicate branch or
+// when a container already exists for the worktree, 500 on internal error.
 func (s *Server) handleCreateWorktree(w http.ResponseWriter, r *http.Request) {
 	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
 	if err != nil {
@@ -346,18 +620,28 @@ func (s *Server) handleCreateWorktree(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CreateWorktreeRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	req.Name = worktree.NormalizeName(req.Name)
+	if req.Name == "" {
 		writeError(w, http.StatusBadRequest, "name is required")
 		return
 	}
 
-	if err := s.worktreeOps.ValidateName(req.Name); err != nil {
+	if err := worktree.ValidateName(req.Name); err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	wtPath, err := s.worktreeOps.Create(projectPath, req.Name)
+	wtPath, err := s.worktreeOps.Create(projectPath, req.Name, req.Base)
+	s.record(r, "worktree.create", projectPath+"/"+req.Name, err)
 	if err != nil {
+		if errors.Is(err, worktree.ErrUnknownBaseRef) {
+			writeError(w, http.StatusBadRequest, "unknown base ref")
+			return
+		}
 		// Check if the error indicates the worktree already exists.
 		// The worktree package embeds git output in errors; "already exists"
 		// is the reliable substring from git's error message.
@@ -374,9 +658,14 @@ func (s *Server) handleCreateWorktree(w http.ResponseWriter, r *http.Request) {
 		opts := container.CreateOptions{
 			ProjectPath: projectPath, // project root from URL param
 			Template:    container.FindTemplateForProject(s.manager.List(), projectPath),
-			Name:        container.SanitizeComposeName(filepath.Base(projectPath) + "-" + req.Name),
+			Name:        worktree.ComposeName(projectPath, req.Name),
 		}
 		c, err := s.manager.CreateWithCompose(r.Context(), opts)
+		s.record(r, "container.create", opts.Name, err)
+		if errors.Is(err, container.ErrContainerExists) {
+			writeError(w, http.StatusConflict, "worktree created but not started: "+err.Error())
+			return
+		}
 		if err != nil {
 			writeError(w, http.StatusInternalServerError, "worktree created but failed to start container: "+err.Error())
 			return
@@ -398,9 +687,41 @@ func (s *Server) handleCreateWorktree(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handlePruneWorktrees handles POST /api/projects/{encodedPath}/worktrees/prune.
+// Runs `git worktree prune` to drop worktrees whose directories are gone.
+// Returns 400 for bad encoding, 404 if the path is not a git repository,
+// 500 on git failure.
+func (s *Server) handlePruneWorktrees(w http.ResponseWriter, r *http.Request) {
+	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project path encoding")
+		return
+	}
+
+	if err := s.worktreeOps.Prune(projectPath); err != nil {
+		if errors.Is(err, worktree.ErrNotGitRepo) {
+			writeError(w, http.StatusNotFound, "not a git repository")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to prune worktrees: "+err.Error())
+		return
+	}
+
+	if s.notifyTUI != nil {
+		s.notifyTUI(events.WebSessionActionMsg{ContainerID: ""})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "pruned"})
+}
+
 // handleDeleteWorktree handles DELETE /api/projects/{encodedPath}/worktrees/{name}.
-// Performs compound operation: stop container (if running) -> destroy container -> git worktree remove.
-// Returns error if git refuses (dirty worktree, unmerged branch).
+// Performs compound operation: stop container (if running) -> destroy container
+// (purging its proxy configs) -> git worktree remove.
+// A worktree with uncommitted changes is left untouched and answered with 409
+// and the changed files, unless ?force=true (which passes --force to git).
+// A worktree whose container is protected by a do-not-delete note is refused
+// with 409 before anything is stopped. Returns 500 if git refuses otherwise
+// (e.g. unmerged branch).
 func (s *Server) handleDeleteWorktree(w http.ResponseWriter, r *http.Request) {
 	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
 	if err != nil {
@@ -409,9 +730,28 @@ func (s *Server) handleDeleteWorktree(w http.ResponseWriter, r *http.Request) {
 	}
 
 	name := r.PathValue("name")
+	if err := worktree.ValidateName(name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
 
 	// Use shared function for compound destroy operation
-	if err := worktree.DestroyWorktreeWithContainer(r.Context(), s.manager, projectPath, name, s.worktreeOps); err != nil {
+	err = worktree.DestroyWorktreeWithContainer(r.Context(), s.manager, projectPath, name, s.worktreeOps, force)
+	s.record(r, "worktree.delete", projectPath+"/"+name, err)
+	if err != nil {
+		var dirty *worktree.DirtyError
+		if errors.As(err, &dirty) {
+			writeJSON(w, http.StatusConflict, map[string]any{
+				"error":         err.Error(),
+				"changed_files": dirty.ChangedFiles,
+			})
+			return
+		}
+		if errors.Is(err, container.ErrProtected) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -425,6 +765,9 @@ func (s *Server) handleDeleteWorktree(w http.ResponseWriter, r *http.Request) {
 
 // handleStartWorktreeContainer starts a container for a worktree that has no container yet.
 // POST /api/projects/{encodedPath}/worktrees/{name}/start
+// With ?dry_run=true nothing is created; the response is the creation plan
+// (see handleGetCreatePlan) with 200. Returns 409 if the worktree already has
+// a container, unless ?force=true.
 func (s *Server) handleStartWorktreeContainer(w http.ResponseWriter, r *http.Request) {
 	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
 	if err != nil {
@@ -433,6 +776,10 @@ func (s *Server) handleStartWorktreeContainer(w http.ResponseWriter, r *http.Req
 	}
 
 	name := r.PathValue("name")
+	if err := worktree.ValidateName(name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Resolve worktree path. For linked worktrees this is
 	// <projectPath>/.worktrees/<name>. For the main worktree the path
@@ -448,8 +795,9 @@ func (s *Server) handleStartWorktreeContainer(w http.ResponseWriter, r *http.Req
 	}
 
 	// Check if a container already exists for this worktree by compose project name
-	composeName := container.SanitizeComposeName(filepath.Base(projectPath) + "-" + name)
-	if existing := s.manager.GetByComposeProject(composeName); existing != nil {
+	composeName := worktree.ComposeName(projectPath, name)
+	force := r.URL.Query().Get("force") == "true"
+	if existing := s.manager.GetByComposeProject(composeName); existing != nil && !force {
 		writeError(w, http.StatusConflict, "worktree already has a container")
 		return
 	}
@@ -459,8 +807,18 @@ func (s *Server) handleStartWorktreeContainer(w http.ResponseWriter, r *http.Req
 		ProjectPath: projectPath, // project root, NOT wtPath
 		Template:    container.FindTemplateForProject(s.manager.List(), projectPath),
 		Name:        composeName,
+		Force:       force,
+	}
+	if r.URL.Query().Get("dry_run") == "true" {
+		s.writeCreatePlan(w, r, opts)
+		return
 	}
 	c, err := s.manager.CreateWithCompose(r.Context(), opts)
+	s.record(r, "container.create", opts.Name, err)
+	if errors.Is(err, container.ErrContainerExists) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to start worktree container: "+err.Error())
 		return
@@ -474,88 +832,337 @@ func (s *Server) handleStartWorktreeContainer(w http.ResponseWriter, r *http.Req
 	writeJSON(w, http.StatusCreated, s.buildContainerResponse(r.Context(), c))
 }
 
-// handleCapturePane handles GET /api/containers/{id}/sessions/{name}/capture.
-// Captures pane content from a tmux session with optional query parameters.
-// Returns 200 with JSON containing content, cursor_y, and lines_requested.
-// Returns 400 if container is not running, 404 if container not found, 500 on error.
-func (s *Server) handleCapturePane(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	name := r.PathValue("name")
-
-	c, ok := s.manager.GetByNameOrID(id)
-	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
+// handleCloneProject handles POST /api/projects/clone.
+// Clones the git URL into the configured clone root and creates a container
+// for it (container.Manager.CloneAndCreate). Returns 201 with the container,
+// 400 for an invalid URL or when no clone root is configured, 409 if the
+// destination exists or already has a container, 500 if the clone or create
+// fails. Invalid mounts are rejected with 400 before anything is cloned.
+func (s *Server) handleCloneProject(w http.ResponseWriter, r *http.Request) {
+	var req CloneProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-
-	if !c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is not running")
+	req.URL = strings.TrimSpace(req.URL)
+	if _, err := s.manager.CloneDestination(req.URL); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	// Parse query parameters
-	var opts tmux.CaptureOpts
-	opts.FromCursor = -1 // disabled by default
-
-	if v := r.URL.Query().Get("lines"); v != "" {
-		n, err := strconv.Atoi(v)
-		if err != nil || n < 0 {
-			writeError(w, http.StatusBadRequest, "invalid lines parameter")
-			return
-		}
-		opts.Lines = n
+	if err := validateAPIMounts(req.Mounts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-
-	if v := r.URL.Query().Get("from_cursor"); v != "" {
-		n, err := strconv.Atoi(v)
-		if err != nil || n < 0 {
-			writeError(w, http.StatusBadRequest, "invalid from_cursor parameter")
-			return
-		}
-		opts.FromCursor = n
+	if req.Template == "" {
+		req.Template = "basic"
 	}
 
-	// Use c.ID (the canonical container ID) rather than the raw path param,
-	// because GetByNameOrID may have resolved a container name to its ID.
-	content, err := s.manager.CaptureSession(r.Context(), c.ID, name, opts)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to capture pane")
+	c, err := s.manager.CloneAndCreate(r.Context(), req.URL, container.CreateOptions{
+		Template:    req.Template,
+		Name:        req.Name,
+		ExtraMounts: req.Mounts,
+	})
+	s.record(r, "container.clone", req.URL, err)
+	if errors.Is(err, container.ErrCloneExists) || errors.Is(err, container.ErrContainerExists) {
+		writeError(w, http.StatusConflict, err.Error())
 		return
 	}
-
-	cursorY, err := s.manager.CursorPosition(r.Context(), c.ID, name)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to get cursor position")
+		writeError(w, http.StatusInternalServerError, "failed to clone project: "+err.Error())
 		return
 	}
 
-	linesRequested := opts.Lines
-	if linesRequested == 0 {
-		if opts.FromCursor >= 0 {
-			linesRequested = opts.FromCursor // report from_cursor as lines_requested
-		} else {
-			linesRequested = -1 // indicate "all visible" in response
-		}
+	if s.notifyTUI != nil {
+		s.notifyTUI(events.WebSessionActionMsg{ContainerID: c.ID})
 	}
-
-	writeJSON(w, http.StatusOK, map[string]any{
-		"content":         content,
-		"cursor_y":        cursorY,
-		"lines_requested": linesRequested,
-	})
+	writeJSON(w, http.StatusCreated, s.buildContainerResponse(r.Context(), c))
 }
 
-// handleCaptureLines handles GET /api/containers/{id}/sessions/{name}/capture-lines.
-// Captures the last N lines from a tmux session's scrollback history.
-// Returns 200 with JSON containing content. Default lines is 20.
+// handleCreateContainer handles POST /api/containers.
+// Queues a container build for a project directory and answers at once with
+// 202, the job ID and a Location of /api/jobs/{id} to poll. The build waits
+// for a free build slot, then runs CreateWithCompose. Returns 400 for a
+// missing or relative project path or an invalid mount, 404 if the project
+// path does not exist or is not a discovered project, 409 if the project already has a container (unless
+// force), 429 when too many jobs are pending.
+func (s *Server) handleCreateContainer(w http.ResponseWriter, r *http.Request) {
+	opts, ok := s.decodeCreateRequest(w, r)
+	if !ok {
+		return
+	}
+	if existing := s.manager.ExistingContainer(opts); existing != nil && !opts.Force {
+		writeError(w, http.StatusConflict, "project already has a container")
+		return
+	}
+
+	// The job outlives the request, so take what the audit entry needs now.
+	source := auditSource(r)
+	id, ok := s.jobs.enqueue(opts.Name, func(ctx context.Context, progress func(string)) (*ContainerResponse, error) {
+		opts.OnProgress = func(step container.ProgressStep) {
+			progress(cmp.Or(step.Message, step.Step))
+		}
+		c, err := s.manager.CreateWithCompose(ctx, opts)
+		s.audit.Record("container.create", opts.Name, source, err)
+		if err != nil {
+			return nil, err
+		}
+		if s.notifyTUI != nil {
+			s.notifyTUI(events.WebSessionActionMsg{ContainerID: c.ID})
+		}
+		resp := s.buildContainerResponse(ctx, c)
+		return &resp, nil
+	})
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(buildRetryAfter))
+		writeError(w, http.StatusTooManyRequests,
+			fmt.Sprintf("too many container builds pending (max %d); retry later", maxPendingJobs))
+		return
+	}
+
+	w.Header().Set("Location", "/api/jobs/"+id)
+	writeJSON(w, http.StatusAccepted, JobAcceptedResponse{JobID: id, Status: JobQueued})
+}
+
+// handlePreviewContainer handles POST /api/containers/preview.
+// Takes the POST /api/containers body and answers with what the create would
+// generate (container.GenerateResult: the devcontainer.json, the app
+// container's isolation and mounts as docker run flags, and the full plan)
+// without writing files or creating anything. Returns 400 and 404 as the
+// create does, also 400 for an unknown template or invalid template data
+// (container.ErrInvalid), 500 if the preview cannot be built otherwise.
+func (s *Server) handlePreviewContainer(w http.ResponseWriter, r *http.Request) {
+	opts, ok := s.decodeCreateRequest(w, r)
+	if !ok {
+		return
+	}
+	result, err := s.manager.PreviewCreate(r.Context(), opts)
+	if err != nil {
+		writeManagerError(w, err, "failed to preview container")
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// decodeCreateRequest decodes and validates a CreateContainerRequest into
+// create options, defaulting the template to the project's (else basic) and
+// the name to the sanitized directory name. The project path must be a
+// discovered project (or one of its worktrees), so a request cannot have an
+// arbitrary host directory mounted into a container. On a bad request it
+// answers 400 (404 for a missing or undiscovered project) and returns false.
+func (s *Server) decodeCreateRequest(w http.ResponseWriter, r *http.Request) (container.CreateOptions, bool) {
+	var req CreateContainerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return container.CreateOptions{}, false
+	}
+	if req.ProjectPath == "" || !filepath.IsAbs(req.ProjectPath) {
+		writeError(w, http.StatusBadRequest, "project_path must be an absolute path")
+		return container.CreateOptions{}, false
+	}
+	req.ProjectPath = filepath.Clean(req.ProjectPath)
+	if !s.isDiscoveredPath(r.Context(), req.ProjectPath) {
+		writeError(w, http.StatusNotFound, "project not found under the scan paths")
+		return container.CreateOptions{}, false
+	}
+	if info, err := os.Stat(req.ProjectPath); err != nil || !info.IsDir() {
+		writeError(w, http.StatusNotFound, "project not found")
+		return container.CreateOptions{}, false
+	}
+	if err := validateAPIMounts(req.Mounts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return container.CreateOptions{}, false
+	}
+
+	opts := container.CreateOptions{
+		ProjectPath: req.ProjectPath,
+		Template:    req.Template,
+		Name:        req.Name,
+		Force:       req.Force,
+		ExtraMounts: req.Mounts,
+		UseExisting: req.UseExisting,
+	}
+	if opts.Template == "" {
+		opts.Template = container.FindTemplateForProject(s.manager.List(), opts.ProjectPath)
+	}
+	if opts.Name == "" {
+		opts.Name = container.SanitizeComposeName(filepath.Base(opts.ProjectPath))
+	}
+	return opts, true
+}
+
+// validateAPIMounts parses extra mounts from an API request and refuses bind
+// mounts: the API has no authentication, so it may only add named volumes.
+// Bind mounts of host directories are left to the TUI create form.
+func validateAPIMounts(specs []string) error {
+	mounts, err := container.ParseMounts(specs)
+	if err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if m.Type == "bind" {
+			return fmt.Errorf("mount %s: bind mounts are not accepted through the API; use a volume or the TUI create form", m.Source)
+		}
+	}
+	return nil
+}
+
+// isDiscoveredPath reports whether path is a discovered project or one of its
+// worktrees. Without a scanner nothing is discovered.
+func (s *Server) isDiscoveredPath(ctx context.Context, path string) bool {
+	if s.scanner == nil {
+		return false
+	}
+	for _, proj := range s.scanner(ctx) {
+		if proj.Path == path {
+			return true
+		}
+		for _, wt := range proj.Worktrees {
+			if wt.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleGetJob handles GET /api/jobs/{id}.
+// Returns the job's status, latest progress message, and the container once
+// completed or the error once failed. Returns 404 for an unknown (or
+// long-finished) job.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleGetCreatePlan handles GET /api/projects/{encodedPath}/plan.
+// Returns the creation plan for the project without creating anything:
+// generated files, app isolation and mounts, and the proxy sidecar (omitted
+// when the template has none). Query parameters: template (default: the
+// template the project already uses, else "basic"), name (default: the
+// sanitized project directory name) and use_existing=true (keep the project's
+// devcontainer.json).
+// Returns 400 for bad encoding, 404 if the project path does not exist,
+// 500 if the plan cannot be built.
+func (s *Server) handleGetCreatePlan(w http.ResponseWriter, r *http.Request) {
+	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project path encoding")
+		return
+	}
+	if _, err := os.Stat(projectPath); os.IsNotExist(err) {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+
+	opts := container.CreateOptions{
+		ProjectPath: projectPath,
+		Template:    r.URL.Query().Get("template"),
+		Name:        r.URL.Query().Get("name"),
+		UseExisting: r.URL.Query().Get("use_existing") == "true",
+	}
+	if opts.Template == "" {
+		opts.Template = container.FindTemplateForProject(s.manager.List(), projectPath)
+	}
+	if opts.Name == "" {
+		opts.Name = container.SanitizeComposeName(filepath.Base(projectPath))
+	}
+	s.writeCreatePlan(w, r, opts)
+}
+
+// writeCreatePlan answers with the creation plan for opts.
+func (s *Server) writeCreatePlan(w http.ResponseWriter, r *http.Request, opts container.CreateOptions) {
+	plan, err := s.manager.PlanCreate(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to plan container: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// handleCapturePane handles GET /api/containers/{id}/sessions/{name}/capture.
+// Captures pane content from a tmux session with optional query parameters.
+// Returns 200 with JSON containing content, cursor_y, and lines_requested.
+// Returns 400 if container is not running, 404 if container not found, 500 on error.
+func (s *Server) handleCapturePane(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	name := r.PathValue("name")
+
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	if !c.IsRunning() {
+		writeError(w, http.StatusBadRequest, "container is not running")
+		return
+	}
+
+	// Parse query parameters
+	var opts tmux.CaptureOpts
+	opts.FromCursor = -1 // disabled by default
+
+	if v := r.URL.Query().Get("lines"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid lines parameter")
+			return
+		}
+		opts.Lines = n
+	}
+
+	if v := r.URL.Query().Get("from_cursor"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid from_cursor parameter")
+			return
+		}
+		opts.FromCursor = n
+	}
+
+	// Use c.ID (the canonical container ID) rather than the raw path param,
+	// because lookupContainer may have resolved a name or prefix to its ID.
+	content, err := s.manager.CaptureSession(r.Context(), c.ID, name, opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to capture pane")
+		return
+	}
+
+	cursorY, err := s.manager.CursorPosition(r.Context(), c.ID, name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to get cursor position")
+		return
+	}
+
+	linesRequested := opts.Lines
+	if linesRequested == 0 {
+		if opts.FromCursor >= 0 {
+			linesRequested = opts.FromCursor // report from_cursor as lines_requested
+		} else {
+			linesRequested = -1 // indicate "all visible" in response
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"content":         content,
+		"cursor_y":        cursorY,
+		"lines_requested": linesRequested,
+	})
+}
+
+// handleCaptureLines handles GET /api/containers/{id}/sessions/{name}/capture-lines.
+// Captures the last N lines from a tmux session's scrollback history.
+// Returns 200 with JSON containing content. Default lines is 20.
 // Returns 400 if container is not running or lines param is invalid, 404 if container not found, 500 on error.
 func (s *Server) handleCaptureLines(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainer(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
 		return
 	}
 
@@ -598,14 +1205,8 @@ func (s *Server) handleSendKeys(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	name := r.PathValue("name")
 
-	c, ok := s.manager.GetByNameOrID(id)
+	c, ok := s.lookupContainerExact(w, id)
 	if !ok {
-		writeError(w, http.StatusNotFound, "container not found")
-		return
-	}
-
-	if !c.IsRunning() {
-		writeError(w, http.StatusBadRequest, "container is not running")
 		return
 	}
 
@@ -621,13 +1222,345 @@ func (s *Server) handleSendKeys(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := s.manager.SendToSession(r.Context(), c.ID, name, req.Text); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to send keys")
+		writeManagerError(w, err, "failed to send keys")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SessionKeysRequest is the JSON body for POST /api/containers/{id}/sessions/{name}/keys.
+// Enter defaults to true.
+type SessionKeysRequest struct {
+	Keys  string `json:"keys"`
+	Enter *bool  `json:"enter"`
+}
+
+// handleSessionKeys handles POST /api/containers/{id}/sessions/{name}/keys.
+// Sends keys to an existing tmux session (container.Manager.SendKeys), then
+// Enter unless enter is false. Returns 204 on success. Returns 400 if the
+// container is not running or keys is empty, 404 if the container or session
+// is not found, 500 on internal error.
+func (s *Server) handleSessionKeys(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	name := r.PathValue("name")
+
+	var req SessionKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Keys == "" {
+		writeError(w, http.StatusBadRequest, "keys is required")
+		return
+	}
+
+	c, ok := s.lookupContainerExact(w, id)
+	if !ok {
+		return
+	}
+
+	if !c.IsRunning() {
+		writeError(w, http.StatusBadRequest, "container is not running")
+		return
+	}
+
+	sessions, err := s.manager.ListSessions(r.Context(), c.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list sessions")
+		return
+	}
+	found := false
+	for _, sess := range sessions {
+		if sess.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	enter := req.Enter == nil || *req.Enter
+	if err := s.manager.SendKeys(r.Context(), c.ID, name, req.Keys, enter); err != nil {
+		writeManagerError(w, err, "failed to send keys")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRegenerateProxyCerts handles POST /api/containers/{id}/regenerate-certs.
+// Regenerates the proxy CA for the container's project and re-installs it in
+// each running container of the project. Returns 204 on success, 400 if the
+// container is not running, 404 if container not found, 500 on failure.
+func (s *Server) handleRegenerateProxyCerts(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	if !c.IsRunning() {
+		writeError(w, http.StatusBadRequest, "container is not running")
+		return
+	}
+
+	if err := s.manager.RegenerateProxyCerts(r.Context(), c.ProjectPath); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to regenerate proxy certificates: "+err.Error())
+		return
+	}
+
+	if s.notifyTUI != nil {
+		s.notifyTUI(events.WebSessionActionMsg{ContainerID: c.ID})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleReloadAllowlist handles POST /api/containers/{id}/allowlist/reload.
+// Re-reads network.allowlist and network.allowlist_file into the project's
+// proxy filter script and restarts the proxy of its running containers.
+// Returns 204 on success, 404 for an unknown container, 500 on failure.
+func (s *Server) handleReloadAllowlist(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	if err := s.manager.ReloadAllowlist(r.Context(), c.ProjectPath); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to reload allowlist: "+err.Error())
+		return
+	}
+
+	if s.notifyTUI != nil {
+		s.notifyTUI(events.WebSessionActionMsg{ContainerID: c.ID})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LabelsRequest is the JSON body for POST /api/containers/{id}/labels. Only
+// the user label devagent.note is settable; an empty value clears it.
+type LabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// LabelsResponse holds a container's user labels after an update.
+type LabelsResponse struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// handleSetLabels handles POST /api/containers/{id}/labels.
+// Container labels are fixed at create, so the note is stored by the manager
+// keyed by project path. Returns 400 for labels other than devagent.note or an
+// invalid note, 404 if the container doesn't exist, 500 if the note store
+// cannot be written.
+func (s *Server) handleSetLabels(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req LabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Labels) == 0 {
+		writeError(w, http.StatusBadRequest, "labels are required")
+		return
+	}
+	note, ok := req.Labels[container.LabelNote]
+	if !ok || len(req.Labels) != 1 {
+		writeError(w, http.StatusBadRequest, "only the "+container.LabelNote+" label can be set")
+		return
+	}
+
+	c, found := s.lookupContainer(w, id)
+	if !found {
+		return
+	}
+
+	if err := s.manager.SetNote(c.ProjectPath, note); err != nil {
+		writeManagerError(w, err, "failed to save note")
+		return
+	}
+
+	if s.notifyTUI != nil {
+		s.notifyTUI(events.WebSessionActionMsg{ContainerID: c.ID})
+	}
+	labels := map[string]string{}
+	if n := s.manager.Note(c.ProjectPath); n != "" {
+		labels[container.LabelNote] = n
+	}
+	writeJSON(w, http.StatusOK, LabelsResponse{Labels: labels})
+}
+
+// execTimeout bounds how long a one-shot exec may run.
+const execTimeout = 30 * time.Second
+
+// ExecRequest is the JSON body for running a one-shot command in a container.
+type ExecRequest struct {
+	Command []string `json:"command"`
+	User    string   `json:"user,omitempty"` // empty runs as the container's default user
+}
+
+// ExecResponse is the JSON result of a one-shot command.
+type ExecResponse struct {
+	Stdout    string `json:"stdout"`
+	ExitCode  int    `json:"exit_code"`
+	Truncated bool   `json:"truncated,omitempty"` // stdout exceeded container.MaxExecOutput
+}
+
+// handleExec handles POST /api/containers/{id}/exec.
+// Runs a non-interactive command with a server-side timeout and returns its
+// stdout and exit code (a non-zero exit is still 200). Returns 400 if the
+// container is not running or the command is empty, 404 if container not found,
+// 504 if the command times out, 500 on internal error.
+func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	c, ok := s.lookupContainerExact(w, id)
+	if !ok {
+		return
+	}
+
+	var req ExecRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(req.Command) == 0 || req.Command[0] == "" {
+		writeError(w, http.StatusBadRequest, "command is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), execTimeout)
+	defer cancel()
+
+	result, err := s.manager.Exec(ctx, c.ID, req.User, req.Command)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			writeError(w, http.StatusGatewayTimeout, "command timed out")
+			return
+		}
+		writeManagerError(w, err, "failed to execute command: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ExecResponse{Stdout: result.Stdout, ExitCode: result.ExitCode, Truncated: result.Truncated})
+}
+
+// handleContainerLogs handles GET /api/containers/{id}/logs.
+// Returns the last ?tail=N lines of container output as text/plain (default
+// container.DefaultLogTail, capped at container.MaxLogTail). With
+// ?download=true the response is an attachment named <name>-<timestamp>.log.
+func (s *Server) handleContainerLogs(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	c, ok := s.lookupContainer(w, id)
+	if !ok {
+		return
+	}
+
+	tail := 0
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "tail must be a positive integer")
+			return
+		}
+		tail = n
+	}
+
+	logs, err := s.manager.Logs(r.Context(), c.ID, tail)
+	if err != nil {
+		writeManagerError(w, err, "failed to read logs: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if r.URL.Query().Get("download") == "true" {
+		filename := container.LogExportFilename(c.Name, time.Now())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(logs))
+}
+
+// handleGetAudit handles GET /api/audit.
+// Returns the newest audit log entries first; ?limit= (default
+// audit.DefaultLimit) caps how many. Returns 400 for an invalid limit, 500 if
+// the audit log cannot be read.
+func (s *Server) handleGetAudit(w http.ResponseWriter, r *http.Request) {
+	limit := audit.DefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	entries, err := s.audit.Recent(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to read audit log: "+err.Error())
+		return
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// record writes an audit entry for a mutation requested by r (see
+// auditSource).
+func (s *Server) record(r *http.Request, action, target string, err error) {
+	s.audit.Record(action, target, auditSource(r), err)
+}
+
+// auditSource attributes r to the CLI when it set audit.SourceHeader and to
+// the web API otherwise.
+func auditSource(r *http.Request) string {
+	if r.Header.Get(audit.SourceHeader) == audit.SourceCLI {
+		return audit.SourceCLI
+	}
+	return audit.SourceWeb
+}
+
+// lookupContainer resolves a container path segment (ID, name, or a unique
+// prefix of either; see container.Manager.Resolve). When nothing matches it
+// answers 404, when a prefix is ambiguous 409, and returns false.
+func (s *Server) lookupContainer(w http.ResponseWriter, ref string) (*container.Container, bool) {
+	c, err := s.manager.Resolve(ref)
+	switch {
+	case errors.Is(err, container.ErrAmbiguousRef):
+		writeError(w, http.StatusConflict, err.Error())
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusNotFound, "container not found")
+		return nil, false
+	}
+	return c, true
+}
+
+// lookupContainerExact is lookupContainer for routes that stop, destroy or
+// run something in a container (see container.Manager.ResolveExact): a
+// reference that only abbreviates a container's name or ID answers 400.
+func (s *Server) lookupContainerExact(w http.ResponseWriter, ref string) (*container.Container, bool) {
+	c, err := s.manager.ResolveExact(ref)
+	switch {
+	case errors.Is(err, container.ErrInexactRef):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	case errors.Is(err, container.ErrAmbiguousRef):
+		writeError(w, http.StatusConflict, err.Error())
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusNotFound, "container not found")
+		return nil, false
+	}
+	return c, true
+}
+
 // writeJSON writes v as JSON with the given HTTP status code.
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -642,80 +1575,80 @@ func writeError(w http.ResponseWriter, status int, message string) {
 
 // handleGetProjects handles GET /api/projects.
 // Returns ProjectsListResponse with projects (matched to worktrees) and unmatched containers.
+// With ?all=true, containers without the devagent.managed label are queried from the
+// runtime and included with "unmanaged": true.
 func (s *Server) handleGetProjects(w http.ResponseWriter, r *http.Request) {
 	var projects []discovery.DiscoveredProject
 	if s.scanner != nil {
 		projects = s.scanner(r.Context())
 	}
 
-	containers := s.manager.List()
+	containers, err := s.projectContainers(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list containers: "+err.Error())
+		return
+	}
 
 	result := s.buildProjectResponses(r.Context(), projects, containers)
 	writeJSON(w, http.StatusOK, result)
 }
 
+// handleGetProject handles GET /api/projects/{encodedPath}: one discovered
+// project with its worktrees and their containers, shaped like an entry of
+// GET /api/projects. Supports the same ?all=true. Returns 404 if the path is
+// not a discovered project.
+func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	projectPath, err := decodeProjectPath(r.PathValue("encodedPath"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid project path encoding")
+		return
+	}
+
+	var project *discovery.DiscoveredProject
+	if s.scanner != nil {
+		projects := s.scanner(r.Context())
+		for i := range projects {
+			if projects[i].Path == projectPath {
+				project = &projects[i]
+				break
+			}
+		}
+	}
+	if project == nil {
+		writeError(w, http.StatusNotFound, "project not found")
+		return
+	}
+
+	containers, err := s.projectContainers(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list containers: "+err.Error())
+		return
+	}
+
+	byCompose := containersByComposeProject(containers)
+	writeJSON(w, http.StatusOK, s.buildProjectResponse(r.Context(), *project, byCompose, make(map[string]bool)))
+}
+
+// projectContainers returns the containers to match against projects: the
+// managed ones, or with ?all=true every host container (Manager.ListAll).
+func (s *Server) projectContainers(r *http.Request) ([]*container.Container, error) {
+	if r.URL.Query().Get("all") == "true" {
+		return s.manager.ListAll(r.Context())
+	}
+	return s.manager.List(), nil
+}
+
 // buildProjectResponses assembles ProjectsListResponse by matching containers to worktrees.
 // Matching uses compose project names, which encode the project+worktree relationship
-// (e.g., "myproject" for main, "myproject-feature" for a worktree named "feature").
+// (e.g., "myproject" for main, "myproject-feature-<hash>" for a worktree named "feature", see worktree.ComposeName).
 // Containers not matched to any project worktree appear in the Unmatched list.
 func (s *Server) buildProjectResponses(ctx context.Context, projects []discovery.DiscoveredProject, containers []*container.Container) ProjectsListResponse {
-	// Index containers by ComposeProject name for O(1) lookup.
-	// When multiple containers share the same compose project, prefer running ones.
-	containersByCompose := make(map[string]*container.Container, len(containers))
+	containersByCompose := containersByComposeProject(containers)
 	matched := make(map[string]bool, len(containers))
-	for _, c := range containers {
-		if c.ComposeProject == "" {
-			continue
-		}
-		existing, exists := containersByCompose[c.ComposeProject]
-		if !exists || (c.IsRunning() && !existing.IsRunning()) {
-			containersByCompose[c.ComposeProject] = c
-		}
-	}
 
 	result := make([]ProjectResponse, 0, len(projects))
 	for _, proj := range projects {
-		pr := ProjectResponse{
-			Name:        proj.Name,
-			Path:        proj.Path,
-			EncodedPath: base64.URLEncoding.EncodeToString([]byte(proj.Path)),
-			HasMakefile: proj.HasMakefile,
-			Worktrees:   make([]WorktreeResponse, 0, len(proj.Worktrees)+1),
-		}
-
-		projBase := filepath.Base(proj.Path)
-
-		// Main worktree (the project root itself)
-		mainCompose := container.SanitizeComposeName(projBase)
-		mainWR := WorktreeResponse{
-			Name:   "main",
-			Path:   proj.Path,
-			IsMain: true,
-		}
-		if c, ok := containersByCompose[mainCompose]; ok {
-			resp := s.buildContainerResponse(ctx, c)
-			mainWR.Container = &resp
-			matched[c.ID] = true
-		}
-		pr.Worktrees = append(pr.Worktrees, mainWR)
-
-		// Linked worktrees
-		for _, wt := range proj.Worktrees {
-			wtCompose := container.SanitizeComposeName(projBase + "-" + wt.Name)
-			wr := WorktreeResponse{
-				Name:   wt.Name,
-				Path:   wt.Path,
-				IsMain: false,
-			}
-			if c, ok := containersByCompose[wtCompose]; ok {
-				resp := s.buildContainerResponse(ctx, c)
-				wr.Container = &resp
-				matched[c.ID] = true
-			}
-			pr.Worktrees = append(pr.Worktrees, wr)
-		}
-
-		result = append(result, pr)
+		result = append(result, s.buildProjectResponse(ctx, proj, containersByCompose, matched))
 	}
 
 	// Collect unmatched containers
@@ -734,3 +1667,65 @@ func (s *Server) buildProjectResponses(ctx context.Context, projects []discovery
 		Unmatched: unmatched,
 	}
 }
+
+// containersByComposeProject indexes containers by ComposeProject name for
+// O(1) lookup. When multiple containers share the same compose project,
+// running ones are preferred.
+func containersByComposeProject(containers []*container.Container) map[string]*container.Container {
+	byCompose := make(map[string]*container.Container, len(containers))
+	for _, c := range containers {
+		if c.ComposeProject == "" {
+			continue
+		}
+		existing, exists := byCompose[c.ComposeProject]
+		if !exists || (c.IsRunning() && !existing.IsRunning()) {
+			byCompose[c.ComposeProject] = c
+		}
+	}
+	return byCompose
+}
+
+// buildProjectResponse assembles one project's ProjectResponse: the main
+// worktree (the project root) then its linked worktrees, each with the
+// container of its compose project from byCompose, if any. The IDs of
+// containers placed are recorded in matched.
+func (s *Server) buildProjectResponse(ctx context.Context, proj discovery.DiscoveredProject, byCompose map[string]*container.Container, matched map[string]bool) ProjectResponse {
+	pr := ProjectResponse{
+		Name:        proj.Name,
+		Path:        proj.Path,
+		EncodedPath: base64.URLEncoding.EncodeToString([]byte(proj.Path)),
+		HasMakefile: proj.HasMakefile,
+		Worktrees:   make([]WorktreeResponse, 0, len(proj.Worktrees)+1),
+	}
+
+	// Main worktree (the project root itself)
+	mainCompose := container.SanitizeComposeName(filepath.Base(proj.Path))
+	mainWR := WorktreeResponse{
+		Name:   "main",
+		Path:   proj.Path,
+		IsMain: true,
+	}
+	if c, ok := byCompose[mainCompose]; ok {
+		resp := s.buildContainerResponse(ctx, c)
+		mainWR.Container = &resp
+		matched[c.ID] = true
+	}
+	pr.Worktrees = append(pr.Worktrees, mainWR)
+
+	// Linked worktrees
+	for _, wt := range proj.Worktrees {
+		wtCompose := worktree.ComposeName(proj.Path, wt.Name)
+		wr := WorktreeResponse{
+			Name:   wt.Name,
+			Path:   wt.Path,
+			IsMain: false,
+		}
+		if c, ok := byCompose[wtCompose]; ok {
+			resp := s.buildContainerResponse(ctx, c)
+			wr.Container = &resp
+			matched[c.ID] = true
+		}
+		pr.Worktrees = append(pr.Worktrees, wr)
+	}
+	return pr
+}
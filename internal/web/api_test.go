@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -20,18 +22,27 @@ import (
 	"devagent/internal/events"
 	"devagent/internal/logging"
 	"devagent/internal/web"
+	"devagent/internal/worktree"
 )
 
 // apiMockRuntime is a mock runtime for API handler tests.
 type apiMockRuntime struct {
 	containers []container.Container
+	unmanaged  []container.Container // returned only by ListAllContainers
 	execOutput string
+	mounts     []container.MountInfo
+	mountCalls int // GetMounts invocations
+	ports      []container.PortMapping
 }
 
 func (m *apiMockRuntime) ListContainers(_ context.Context) ([]container.Container, error) {
 	return m.containers, nil
 }
 
+func (m *apiMockRuntime) ListAllContainers(_ context.Context) ([]container.Container, error) {
+	return slices.Concat(m.containers, m.unmanaged), nil
+}
+
 func (m *apiMockRuntime) Exec(_ context.Context, _ string, _ []string) (string, error) {
 	return m.execOutput, nil
 }
@@ -40,10 +51,27 @@ func (m *apiMockRuntime) ExecAs(_ context.Context, _ string, _ string, _ []strin
 	return m.execOutput, nil
 }
 
+func (m *apiMockRuntime) Logs(_ context.Context, id string, tail int) (string, error) {
+	return fmt.Sprintf("logs for %s (tail %d)\n", id, tail), nil
+}
+
 func (m *apiMockRuntime) InspectContainer(_ context.Context, _ string) (container.ContainerState, error) {
 	return container.StateRunning, nil
 }
 
+func (m *apiMockRuntime) InspectRaw(_ context.Context, id string) (json.RawMessage, error) {
+	return json.RawMessage(`[{"Id":"` + id + `","State":{"Status":"running"}}]`), nil
+}
+
+func (m *apiMockRuntime) GetMounts(_ context.Context, _ string) ([]container.MountInfo, error) {
+	m.mountCalls++
+	return m.mounts, nil
+}
+
+func (m *apiMockRuntime) GetPorts(_ context.Context, _ string) ([]container.PortMapping, error) {
+	return m.ports, nil
+}
+
 func (m *apiMockRuntime) GetIsolationInfo(_ context.Context, _ string) (*container.IsolationInfo, error) {
 	return &container.IsolationInfo{}, nil
 }
@@ -52,8 +80,13 @@ func (m *apiMockRuntime) ComposeUp(_ context.Context, _ string, _ string, _ map[
 	return nil
 }
 func (m *apiMockRuntime) ComposeStart(_ context.Context, _ string, _ string) error { return nil }
-func (m *apiMockRuntime) ComposeStop(_ context.Context, _ string, _ string) error  { return nil }
-func (m *apiMockRuntime) ComposeDown(_ context.Context, _ string, _ string) error  { return nil }
+func (m *apiMockRuntime) ComposeStop(_ context.Context, _ string, _ string, _ ...string) error {
+	return nil
+}
+func (m *apiMockRuntime) ComposeDown(_ context.Context, _ string, _ string) error { return nil }
+func (m *apiMockRuntime) ComposeRestart(_ context.Context, _ string, _ string, _ ...string) error {
+	return nil
+}
 
 // mutationMockRuntime is a mock runtime for session mutation tests.
 // It maps tmux subcommands to canned outputs, allowing different responses for
@@ -68,6 +101,10 @@ func (m *mutationMockRuntime) ListContainers(_ context.Context) ([]container.Con
 	return m.containers, nil
 }
 
+func (m *mutationMockRuntime) ListAllContainers(_ context.Context) ([]container.Container, error) {
+	return m.containers, nil
+}
+
 func (m *mutationMockRuntime) Exec(_ context.Context, _ string, _ []string) (string, error) {
 	return "", nil
 }
@@ -83,10 +120,26 @@ func (m *mutationMockRuntime) ExecAs(_ context.Context, _ string, _ string, cmd
 	return "", nil
 }
 
+func (m *mutationMockRuntime) Logs(_ context.Context, _ string, _ int) (string, error) {
+	return "", nil
+}
+
 func (m *mutationMockRuntime) InspectContainer(_ context.Context, _ string) (container.ContainerState, error) {
 	return container.StateRunning, nil
 }
 
+func (m *mutationMockRuntime) InspectRaw(_ context.Context, _ string) (json.RawMessage, error) {
+	return json.RawMessage(`[{}]`), nil
+}
+
+func (m *mutationMockRuntime) GetMounts(_ context.Context, _ string) ([]container.MountInfo, error) {
+	return nil, nil
+}
+
+func (m *mutationMockRuntime) GetPorts(_ context.Context, _ string) ([]container.PortMapping, error) {
+	return nil, nil
+}
+
 func (m *mutationMockRuntime) GetIsolationInfo(_ context.Context, _ string) (*container.IsolationInfo, error) {
 	return &container.IsolationInfo{}, nil
 }
@@ -95,27 +148,57 @@ func (m *mutationMockRuntime) ComposeUp(_ context.Context, _ string, _ string, _
 	return nil
 }
 func (m *mutationMockRuntime) ComposeStart(_ context.Context, _ string, _ string) error { return nil }
-func (m *mutationMockRuntime) ComposeStop(_ context.Context, _ string, _ string) error  { return nil }
-func (m *mutationMockRuntime) ComposeDown(_ context.Context, _ string, _ string) error  { return nil }
+func (m *mutationMockRuntime) ComposeStop(_ context.Context, _ string, _ string, _ ...string) error {
+	return nil
+}
+func (m *mutationMockRuntime) ComposeDown(_ context.Context, _ string, _ string) error { return nil }
+func (m *mutationMockRuntime) ComposeRestart(_ context.Context, _ string, _ string, _ ...string) error {
+	return nil
+}
+
+// blockingStopRuntime is a mutationMockRuntime whose ComposeStop blocks until
+// release is closed, keeping a stop operation in flight.
+type blockingStopRuntime struct {
+	mutationMockRuntime
+	release chan struct{}
+}
+
+func (b *blockingStopRuntime) ComposeStop(_ context.Context, _ string, _ string, _ ...string) error {
+	<-b.release
+	return nil
+}
 
 // mockWorktreeOps is a mock implementation of worktreeOps for testing.
 type mockWorktreeOps struct {
-	validateErr error
-	createPath  string
-	createErr   error
-	destroyErr  error
-	wtDir       string
+	createPath   string
+	createErr    error
+	createBase   string // base ref passed to Create
+	createName   string // name passed to Create; empty if not called
+	destroyErr   error
+	destroyForce bool   // force passed to Destroy
+	destroyName  string // name passed to Destroy; empty if not called
+	changedFiles []string
+	wtDir        string
+	listResult   []worktree.Info
+	listErr      error
+	listPath     string // project path passed to List
+	pruneErr     error
+	prunePath    string // project path passed to Prune
 }
 
-func (m *mockWorktreeOps) ValidateName(name string) error {
-	return m.validateErr
+func (m *mockWorktreeOps) Create(projectPath, name, base string) (string, error) {
+	m.createBase = base
+	m.createName = name
+	return m.createPath, m.createErr
 }
 
-func (m *mockWorktreeOps) Create(projectPath, name string) (string, error) {
-	return m.createPath, m.createErr
+func (m *mockWorktreeOps) ChangedFiles(projectPath, name string) ([]string, error) {
+	return m.changedFiles, nil
 }
 
-func (m *mockWorktreeOps) Destroy(projectPath, name string) error {
+func (m *mockWorktreeOps) Destroy(projectPath, name string, force bool) error {
+	m.destroyForce = force
+	m.destroyName = name
 	return m.destroyErr
 }
 
@@ -123,6 +206,16 @@ func (m *mockWorktreeOps) WorktreeDir(projectPath, name string) string {
 	return m.wtDir
 }
 
+func (m *mockWorktreeOps) List(projectPath string) ([]worktree.Info, error) {
+	m.listPath = projectPath
+	return m.listResult, m.listErr
+}
+
+func (m *mockWorktreeOps) Prune(projectPath string) error {
+	m.prunePath = projectPath
+	return m.pruneErr
+}
+
 // createTestTemplateDir creates a temporary template directory with minimal .devcontainer structure
 // for ComposeGenerator tests. Returns a config.Config and slice of config.Template ready for use
 // in container.NewManager.
@@ -206,6 +299,12 @@ func startMutationTestServer(t *testing.T, containers []container.Container, out
 		containers:   containers,
 		outputsByCmd: outputsByCmd,
 	}
+	return startRuntimeTestServer(t, runtime, notifyTUI)
+}
+
+// startRuntimeTestServer creates a test server around the given runtime and an optional notifyTUI callback.
+func startRuntimeTestServer(t *testing.T, runtime container.RuntimeInterface, notifyTUI func(any)) string {
+	t.Helper()
 
 	mgr := container.NewManager(container.ManagerOptions{Runtime: runtime})
 	if err := mgr.Refresh(context.Background()); err != nil {
@@ -303,6 +402,12 @@ func startProjectsTestServer(t *testing.T, containers []container.Container, ses
 		containers: containers,
 		execOutput: sessionOutput,
 	}
+	return startProjectsTestServerWithRuntime(t, runtime, projects)
+}
+
+// startProjectsTestServerWithRuntime is startProjectsTestServer for a caller-built runtime.
+func startProjectsTestServerWithRuntime(t *testing.T, runtime *apiMockRuntime, projects []discovery.DiscoveredProject) string {
+	t.Helper()
 
 	mgr := container.NewManager(container.ManagerOptions{Runtime: runtime})
 	if err := mgr.Refresh(context.Background()); err != nil {
@@ -412,6 +517,130 @@ func TestHandleListContainers_GH17AC11(t *testing.T) {
 	})
 }
 
+// TestHandleListContainers_Sort verifies ?sort and ?order control ordering and reject unknown values.
+func TestHandleListContainers_Sort(t *testing.T) {
+	base := time.Date(2025, 1, 27, 10, 0, 0, 0, time.UTC)
+	containers := []container.Container{
+		{ID: "c1", Name: "bravo", State: container.StateStopped, CreatedAt: base, Labels: map[string]string{}},
+		{ID: "c2", Name: "alpha", State: container.StateCreated, CreatedAt: base.Add(2 * time.Hour), Labels: map[string]string{}},
+		{ID: "c3", Name: "charlie", State: container.StateRunning, CreatedAt: base.Add(time.Hour), Labels: map[string]string{}},
+	}
+	srv := startAPITestServer(t, containers, "")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"alpha", "bravo", "charlie"}},
+		{"?sort=name&order=desc", []string{"charlie", "bravo", "alpha"}},
+		{"?sort=state", []string{"charlie", "alpha", "bravo"}},
+		{"?sort=created&order=desc", []string{"alpha", "charlie", "bravo"}},
+	}
+	for _, tt := range tests {
+		t.Run("query "+tt.query, func(t *testing.T) {
+			resp, err := http.Get(srv + "/api/containers" + tt.query)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			var result []map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			var names []string
+			for _, c := range result {
+				names = append(names, c["name"].(string))
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("names = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	for _, query := range []string{"?sort=size", "?order=sideways"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			resp, err := http.Get(srv + "/api/containers" + query)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+// TestHandleListContainers_Pagination verifies ?limit/?offset page the sorted
+// list into a ContainersPageResponse and reject out-of-range values.
+func TestHandleListContainers_Pagination(t *testing.T) {
+	var containers []container.Container
+	for _, name := range []string{"echo", "alpha", "delta", "bravo", "charlie"} {
+		containers = append(containers, container.Container{ID: "id-" + name, Name: name, State: container.StateStopped, Labels: map[string]string{}})
+	}
+	srv := startAPITestServer(t, containers, "")
+
+	tests := []struct {
+		query      string
+		wantNames  []string
+		wantLimit  int
+		wantOffset int
+	}{
+		{"?limit=2", []string{"alpha", "bravo"}, 2, 0},
+		{"?limit=2&offset=2", []string{"charlie", "delta"}, 2, 2},
+		{"?limit=2&offset=4", []string{"echo"}, 2, 4},
+		{"?limit=2&offset=10", []string{}, 2, 10},
+		{"?offset=3", []string{"delta", "echo"}, 500, 3},
+		{"?limit=2&offset=1&order=desc", []string{"delta", "charlie"}, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run("query "+tt.query, func(t *testing.T) {
+			resp, err := http.Get(srv + "/api/containers" + tt.query)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			var page web.ContainersPageResponse
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			names := []string{}
+			for _, c := range page.Containers {
+				names = append(names, c.Name)
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+			if page.Total != 5 || page.Limit != tt.wantLimit || page.Offset != tt.wantOffset {
+				t.Errorf("total/limit/offset = %d/%d/%d, want 5/%d/%d", page.Total, page.Limit, page.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+
+	for _, query := range []string{"?limit=0", "?limit=501", "?limit=abc", "?offset=-1"} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			resp, err := http.Get(srv + "/api/containers" + query)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
 // TestHandleGetContainer_GH17AC12 verifies GET /api/containers/{id} returns single container with sessions.
 func TestHandleGetContainer_GH17AC12(t *testing.T) {
 	createdAt := time.Date(2025, 1, 27, 10, 0, 0, 0, time.UTC)
@@ -812,6 +1041,98 @@ func TestHandleGetProjects_AC11(t *testing.T) {
 	checkStringField(t, containerData, "name", "devcontainer")
 }
 
+// TestHandleGetProject_SingleProject verifies GET /api/projects/{encodedPath}
+// returns one project shaped like a GET /api/projects entry, with containers
+// nested in its worktrees, and 404 for a path that is not a discovered project.
+func TestHandleGetProject_SingleProject(t *testing.T) {
+	projectPath := "/home/user/project1"
+	containers := []container.Container{
+		{
+			ID:             "container1",
+			Name:           "devcontainer",
+			State:          container.StateRunning,
+			Template:       "template1",
+			ProjectPath:    projectPath,
+			ComposeProject: container.SanitizeComposeName("project1"),
+			RemoteUser:     "user",
+			CreatedAt:      time.Now(),
+		},
+		{
+			ID:             "container2",
+			Name:           "feature-container",
+			State:          container.StateRunning,
+			ProjectPath:    projectPath + "/.worktrees/feature",
+			ComposeProject: worktree.ComposeName(projectPath, "feature"),
+			CreatedAt:      time.Now(),
+		},
+		{
+			ID:             "other",
+			Name:           "other-container",
+			State:          container.StateRunning,
+			ProjectPath:    "/home/user/project2",
+			ComposeProject: container.SanitizeComposeName("project2"),
+			CreatedAt:      time.Now(),
+		},
+	}
+	projects := []discovery.DiscoveredProject{
+		{
+			Name:        "project1",
+			Path:        projectPath,
+			HasMakefile: true,
+			Worktrees:   []discovery.Worktree{{Name: "feature", Path: projectPath + "/.worktrees/feature"}},
+		},
+		{Name: "project2", Path: "/home/user/project2"},
+	}
+
+	base := startProjectsTestServer(t, containers, "", projects)
+
+	encoded := base64.URLEncoding.EncodeToString([]byte(projectPath))
+	resp, err := http.Get(base + "/api/projects/" + encoded)
+	if err != nil {
+		t.Fatalf("GET /api/projects/{path} error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var proj map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&proj); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	checkStringField(t, proj, "name", "project1")
+	checkStringField(t, proj, "path", projectPath)
+	checkStringField(t, proj, "encoded_path", encoded)
+
+	worktreesArr, ok := proj["worktrees"].([]any)
+	if !ok || len(worktreesArr) != 2 {
+		t.Fatalf("worktrees = %v, want main and feature", proj["worktrees"])
+	}
+	for i, want := range []struct{ name, containerID string }{{"main", "container1"}, {"feature", "container2"}} {
+		wt := worktreesArr[i].(map[string]any)
+		checkStringField(t, wt, "name", want.name)
+		containerData, ok := wt["container"].(map[string]any)
+		if !ok {
+			t.Fatalf("container field missing in worktree %s", want.name)
+		}
+		checkStringField(t, containerData, "id", want.containerID)
+	}
+
+	for path, want := range map[string]int{
+		base64.URLEncoding.EncodeToString([]byte("/home/user/unknown")): http.StatusNotFound,
+		"not-base64!": http.StatusBadRequest,
+	} {
+		resp, err := http.Get(base + "/api/projects/" + path)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET /api/projects/%s status = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
 // TestHandleGetProjects_AC12 verifies main worktree has is_main: true; linked worktrees have is_main: false.
 // web-lifecycle-ops.AC1.2 Success: Main worktree (project root) has is_main: true; linked worktrees have is_main: false
 func TestHandleGetProjects_AC12(t *testing.T) {
@@ -826,7 +1147,7 @@ func TestHandleGetProjects_AC12(t *testing.T) {
 			State:          container.StateRunning,
 			Template:       "template1",
 			ProjectPath:    projectPath,
-			ComposeProject: container.SanitizeComposeName("project2-feature"),
+			ComposeProject: worktree.ComposeName(projectPath, "feature"),
 			RemoteUser:     "user",
 			CreatedAt:      time.Now(),
 		},
@@ -980,6 +1301,49 @@ func TestHandleGetProjects_AC13(t *testing.T) {
 	}
 }
 
+// TestHandleGetProjects_All verifies ?all=true adds unmanaged containers,
+// marked "unmanaged", while the default listing stays managed-only.
+func TestHandleGetProjects_All(t *testing.T) {
+	runtime := &apiMockRuntime{
+		containers: []container.Container{stoppedContainer("managed1")},
+		unmanaged: []container.Container{
+			{ID: "other1", Name: "postgres", State: container.StateRunning, Labels: map[string]string{}},
+		},
+	}
+	base := startProjectsTestServerWithRuntime(t, runtime, nil)
+
+	get := func(path string) web.ProjectsListResponse {
+		t.Helper()
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s status = %d, want %d", path, resp.StatusCode, http.StatusOK)
+		}
+		var body web.ProjectsListResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		return body
+	}
+
+	if got := get("/api/projects").Unmatched; len(got) != 1 || got[0].ID != "managed1" || got[0].Unmanaged {
+		t.Errorf("default unmatched = %+v, want only managed1", got)
+	}
+
+	got := get("/api/projects?all=true").Unmatched
+	if len(got) != 2 {
+		t.Fatalf("all unmatched = %+v, want 2 containers", got)
+	}
+	for _, c := range got {
+		if want := c.ID == "other1"; c.Unmanaged != want {
+			t.Errorf("container %s unmanaged = %v, want %v", c.ID, c.Unmanaged, want)
+		}
+	}
+}
+
 // TestHandleStartContainer_AC21 verifies POST /api/containers/{id}/start on a stopped container returns 200 and sends TUI notification.
 // web-lifecycle-ops.AC2.1 Success: POST /api/containers/{id}/start starts a stopped container
 func TestHandleStartContainer_AC21(t *testing.T) {
@@ -1124,6 +1488,37 @@ func TestHandleDestroyContainer_AC23(t *testing.T) {
 	}
 }
 
+// TestHandleDestroyContainer_RefusesProtected verifies DELETE /api/containers/{id}
+// answers 409 for a container whose note says do-not-delete, and destroys it
+// with ?force=true.
+func TestHandleDestroyContainer_RefusesProtected(t *testing.T) {
+	containers := []container.Container{
+		{ID: "abc123", Name: "abc123-app-1", State: container.StateRunning, ProjectPath: "/home/user/myproject", Labels: map[string]string{}},
+	}
+	base := startMutationTestServer(t, containers, map[string]string{}, nil)
+
+	resp := postJSON(t, base+"/api/containers/abc123/labels", map[string]any{
+		"labels": map[string]string{"devagent.note": "staging, do-not-delete"},
+	})
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set note status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	resp = deleteRequest(t, base+"/api/containers/abc123")
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict || !strings.Contains(string(body), "do-not-delete") {
+		t.Errorf("status = %d, body = %s; want 409 naming the note", resp.StatusCode, body)
+	}
+
+	resp = deleteRequest(t, base+"/api/containers/abc123?force=true")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("forced status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
 // TestHandleStartContainer_AC24_Nonexistent verifies POST /api/containers/{id}/start on nonexistent container returns 404.
 // web-lifecycle-ops.AC2.4 Failure: Start on nonexistent container returns 404
 func TestHandleStartContainer_AC24_Nonexistent(t *testing.T) {
@@ -1187,6 +1582,59 @@ func TestHandleDestroyContainer_AC24_Nonexistent(t *testing.T) {
 	}
 }
 
+// TestHandleDestroyContainer_LeavesWorktree verifies that DELETE
+// /api/containers/{id} on a worktree's container removes only the container:
+// the worktree directory and git are untouched, and the project's proxy certs
+// are kept unless ?purge=true.
+func TestHandleDestroyContainer_LeavesWorktree(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	projectPath := "/home/user/myproject"
+	wtPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(wtPath, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("WriteFile error = %v", err)
+	}
+	certDir, err := container.GetProxyCertDir(wtPath)
+	if err != nil {
+		t.Fatalf("GetProxyCertDir error = %v", err)
+	}
+	wtContainer := func(id string) container.Container {
+		return container.Container{
+			ID:          id,
+			Name:        id + "-app-1",
+			State:       container.StateStopped,
+			ProjectPath: wtPath,
+			Labels:      map[string]string{container.LabelComposeProject: worktree.ComposeName(projectPath, "feature")},
+		}
+	}
+
+	for _, tc := range []struct {
+		query       string
+		wantCertDir bool
+	}{
+		{query: "", wantCertDir: true},
+		{query: "?purge=true", wantCertDir: false},
+	} {
+		wt := &mockWorktreeOps{}
+		base := startWorktreeTestServer(t, []container.Container{wtContainer("wt123")}, wt, nil)
+
+		resp := deleteRequest(t, base+"/api/containers/wt123"+tc.query)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("DELETE%s status = %d, want 200", tc.query, resp.StatusCode)
+		}
+		if wt.destroyName != "" {
+			t.Errorf("DELETE%s removed worktree %q, want git untouched", tc.query, wt.destroyName)
+		}
+		if _, err := os.Stat(filepath.Join(wtPath, "main.go")); err != nil {
+			t.Errorf("DELETE%s: worktree file stat error = %v", tc.query, err)
+		}
+		_, statErr := os.Stat(certDir)
+		if kept := statErr == nil; kept != tc.wantCertDir {
+			t.Errorf("DELETE%s: proxy cert dir kept = %v, want %v", tc.query, kept, tc.wantCertDir)
+		}
+	}
+}
+
 // TestHandleStartContainer_AC25_AlreadyRunning verifies POST /api/containers/{id}/start on already-running container returns 400.
 // web-lifecycle-ops.AC2.5 Failure: Start on already-running container returns 400
 func TestHandleStartContainer_AC25_AlreadyRunning(t *testing.T) {
@@ -1305,15 +1753,141 @@ func TestHandleCreateWorktree_AC31(t *testing.T) {
 	}
 }
 
+// TestHandleListWorktrees verifies GET /api/projects/{encodedPath}/worktrees
+// lists worktrees for an explicit project path and maps errors to status codes.
+func TestHandleListWorktrees(t *testing.T) {
+	projectPath := "/outside/scan/paths/project"
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+
+	t.Run("returns worktrees", func(t *testing.T) {
+		wt := &mockWorktreeOps{listResult: []worktree.Info{
+			{Name: "project", Path: projectPath, Branch: "main", IsMain: true},
+			{Name: "bisect", Path: projectPath + "/.worktrees/bisect", Locked: true},
+			{Name: "gone", Path: projectPath + "/.worktrees/gone", Prunable: true},
+		}}
+		base := startWorktreeTestServer(t, nil, wt, nil)
+
+		resp, err := http.Get(base + "/api/projects/" + encodedPath + "/worktrees")
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if wt.listPath != projectPath {
+			t.Errorf("List() called with %q, want %q", wt.listPath, projectPath)
+		}
+
+		var result []map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		if len(result) != 3 {
+			t.Fatalf("len(result) = %d, want 3", len(result))
+		}
+		checkStringField(t, result[0], "name", "project")
+		checkStringField(t, result[0], "branch", "main")
+		if result[0]["is_main"] != true || result[0]["locked"] != false {
+			t.Errorf("main worktree flags = %v", result[0])
+		}
+		checkStringField(t, result[1], "branch", "")
+		if result[1]["is_main"] != false || result[1]["locked"] != true || result[1]["prunable"] != false {
+			t.Errorf("locked worktree flags = %v", result[1])
+		}
+		if result[2]["locked"] != false || result[2]["prunable"] != true {
+			t.Errorf("prunable worktree flags = %v", result[2])
+		}
+	})
+
+	tests := []struct {
+		name    string
+		encoded string
+		listErr error
+		want    int
+	}{
+		{"not a git repo", encodedPath, worktree.ErrNotGitRepo, http.StatusNotFound},
+		{"git failure", encodedPath, errors.New("boom"), http.StatusInternalServerError},
+		{"bad encoding", "!!!", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := startWorktreeTestServer(t, nil, &mockWorktreeOps{listErr: tt.listErr}, nil)
+
+			resp, err := http.Get(base + "/api/projects/" + tt.encoded + "/worktrees")
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlePruneWorktrees(t *testing.T) {
+	projectPath := "/home/user/project"
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+
+	t.Run("prunes and notifies", func(t *testing.T) {
+		wt := &mockWorktreeOps{}
+		var notified []any
+		base := startWorktreeTestServer(t, nil, wt, func(msg any) { notified = append(notified, msg) })
+
+		resp, err := http.Post(base+"/api/projects/"+encodedPath+"/worktrees/prune", "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST error = %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if wt.prunePath != projectPath {
+			t.Errorf("Prune() called with %q, want %q", wt.prunePath, projectPath)
+		}
+		if len(notified) != 1 {
+			t.Errorf("notifyTUI called %d times, want 1", len(notified))
+		}
+	})
+
+	tests := []struct {
+		name     string
+		encoded  string
+		pruneErr error
+		want     int
+	}{
+		{"not a git repo", encodedPath, worktree.ErrNotGitRepo, http.StatusNotFound},
+		{"git failure", encodedPath, errors.New("boom"), http.StatusInternalServerError},
+		{"bad encoding", "!!!", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := startWorktreeTestServer(t, nil, &mockWorktreeOps{pruneErr: tt.pruneErr}, nil)
+
+			resp, err := http.Post(base+"/api/projects/"+tt.encoded+"/worktrees/prune", "application/json", nil)
+			if err != nil {
+				t.Fatalf("POST error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
 // TestHandleCreateWorktree_AC32 verifies POST with invalid name returns 400.
 // web-lifecycle-ops.AC3.2: Invalid name returns 400
 func TestHandleCreateWorktree_AC32(t *testing.T) {
 	projectPath := "/home/user/myproject"
 	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
 
-	wt := &mockWorktreeOps{
-		validateErr: fmt.Errorf("invalid worktree name: contains invalid characters"),
-	}
+	wt := &mockWorktreeOps{}
 
 	base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
 
@@ -1333,6 +1907,43 @@ func TestHandleCreateWorktree_AC32(t *testing.T) {
 	}
 }
 
+// TestHandleCreateWorktree_ValidatesNameBeforeGit verifies the real
+// worktree.ValidateName rules apply before the mock's Create runs, and that
+// the name is trimmed first.
+func TestHandleCreateWorktree_ValidatesNameBeforeGit(t *testing.T) {
+	projectPath := "/home/user/myproject"
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+
+	tests := []struct {
+		name     string
+		want     int
+		wantName string
+	}{
+		{name: "HEAD", want: http.StatusBadRequest},
+		{name: "-rf", want: http.StatusBadRequest},
+		{name: "feature/.hidden", want: http.StatusBadRequest},
+		{name: "fëature", want: http.StatusBadRequest},
+		{name: "   ", want: http.StatusBadRequest},
+		{name: "  feature/login ", want: http.StatusCreated, wantName: "feature/login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt := &mockWorktreeOps{createPath: projectPath + "/.worktrees/feature/login"}
+			base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
+
+			resp := postJSON(t, base+"/api/projects/"+encodedPath+"/worktrees", map[string]any{"name": tt.name, "no_start": true})
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if wt.createName != tt.wantName {
+				t.Errorf("Create called with %q, want %q", wt.createName, tt.wantName)
+			}
+		})
+	}
+}
+
 // TestHandleCreateWorktree_AC33 verifies POST with duplicate branch name returns 409.
 // web-lifecycle-ops.AC3.3: Duplicate branch name returns 409
 func TestHandleCreateWorktree_AC33(t *testing.T) {
@@ -1361,6 +1972,46 @@ func TestHandleCreateWorktree_AC33(t *testing.T) {
 	}
 }
 
+// TestHandleCreateWorktree_Base verifies the optional base ref is passed to
+// worktree creation and an unknown base ref returns 400.
+func TestHandleCreateWorktree_Base(t *testing.T) {
+	encodedPath := base64.URLEncoding.EncodeToString([]byte("/home/user/myproject"))
+
+	t.Run("passes base through", func(t *testing.T) {
+		wt := &mockWorktreeOps{createPath: "/home/user/myproject/.worktrees/feature-x"}
+		base := startWorktreeTestServer(t, nil, wt, nil)
+
+		resp := postJSON(t, base+"/api/projects/"+encodedPath+"/worktrees", map[string]any{"name": "feature-x", "base": "origin/main", "no_start": true})
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+		}
+		if wt.createBase != "origin/main" {
+			t.Errorf("Create() base = %q, want %q", wt.createBase, "origin/main")
+		}
+	})
+
+	t.Run("unknown base ref returns 400", func(t *testing.T) {
+		wt := &mockWorktreeOps{createErr: fmt.Errorf("%w %q", worktree.ErrUnknownBaseRef, "nope")}
+		base := startWorktreeTestServer(t, nil, wt, nil)
+
+		resp := postJSON(t, base+"/api/projects/"+encodedPath+"/worktrees", map[string]any{"name": "feature-x", "base": "nope"})
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		if body["error"] != "unknown base ref" {
+			t.Errorf("error = %q, want %q", body["error"], "unknown base ref")
+		}
+	})
+}
+
 // TestHandleCreateWorktree_NoStart verifies POST /api/projects/{path}/worktrees with no_start=true
 // creates worktree WITHOUT starting a container and returns 201 without container_id.
 // web-lifecycle-ops.AC2.2: Create worktree with --no-start flag
@@ -1464,6 +2115,26 @@ func TestHandleDeleteWorktree_AC34(t *testing.T) {
 	checkStringField(t, body, "status", "deleted")
 }
 
+// TestHandleDeleteWorktree_SlashName verifies a slash-style name sent as an
+// escaped path segment reaches Destroy intact.
+func TestHandleDeleteWorktree_SlashName(t *testing.T) {
+	projectPath := "/home/user/myproject"
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+
+	wt := &mockWorktreeOps{wtDir: "/home/user/myproject/.worktrees/feature/login"}
+	base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
+
+	resp := deleteRequest(t, base+"/api/projects/"+encodedPath+"/worktrees/feature%2Flogin")
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if wt.destroyName != "feature/login" {
+		t.Errorf("Destroy name = %q, want %q", wt.destroyName, "feature/login")
+	}
+}
+
 // TestHandleDeleteWorktree_AC35 verifies DELETE with dirty worktree returns error with descriptive message.
 // web-lifecycle-ops.AC3.5: Dirty worktree returns error
 func TestHandleDeleteWorktree_AC35(t *testing.T) {
@@ -1494,21 +2165,85 @@ func TestHandleDeleteWorktree_AC35(t *testing.T) {
 	}
 }
 
-// startWorktreeContainerMockRuntime is a mock runtime that can return different containers
-// on sequential ListContainers calls, used to simulate container creation during tests.
-type startWorktreeContainerMockRuntime struct {
-	// initialContainers returned on first ListContainers call
-	initialContainers []container.Container
-	// afterUpContainers returned on subsequent ListContainers calls (after CreateWithCompose)
-	afterUpContainers []container.Container
-	// listCallCount tracks how many times ListContainers has been called
-	listCallCount int
-	// outputsByCmd maps tmux subcommand to output
-	outputsByCmd map[string]string
-}
+// TestHandleDeleteWorktree_DirtyCheck verifies the uncommitted-changes pre-check:
+// clean worktrees are deleted, dirty ones return 409 with the changed files
+// unless ?force=true.
+func TestHandleDeleteWorktree_DirtyCheck(t *testing.T) {
+	projectPath := "/home/user/myproject"
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+	url := "/api/projects/" + encodedPath + "/worktrees/feature-x"
 
-func (m *startWorktreeContainerMockRuntime) ListContainers(_ context.Context) ([]container.Container, error) {
-	m.listCallCount++
+	t.Run("clean", func(t *testing.T) {
+		wt := &mockWorktreeOps{}
+		base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
+
+		resp := deleteRequest(t, base+url)
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if wt.destroyForce {
+			t.Error("Destroy() should not be forced without ?force=true")
+		}
+	})
+
+	t.Run("dirty without force", func(t *testing.T) {
+		wt := &mockWorktreeOps{changedFiles: []string{"main.go", "notes.txt"}}
+		base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
+
+		resp := deleteRequest(t, base+url)
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusConflict)
+		}
+		var body struct {
+			Error        string   `json:"error"`
+			ChangedFiles []string `json:"changed_files"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		if body.Error == "" {
+			t.Error("error should be set")
+		}
+		if len(body.ChangedFiles) != 2 || body.ChangedFiles[0] != "main.go" || body.ChangedFiles[1] != "notes.txt" {
+			t.Errorf("changed_files = %v, want [main.go notes.txt]", body.ChangedFiles)
+		}
+	})
+
+	t.Run("dirty with force", func(t *testing.T) {
+		wt := &mockWorktreeOps{changedFiles: []string{"main.go"}}
+		base := startWorktreeTestServer(t, []container.Container{}, wt, nil)
+
+		resp := deleteRequest(t, base+url+"?force=true")
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if !wt.destroyForce {
+			t.Error("Destroy() should be forced with ?force=true")
+		}
+	})
+}
+
+// startWorktreeContainerMockRuntime is a mock runtime that can return different containers
+// on sequential ListContainers calls, used to simulate container creation during tests.
+type startWorktreeContainerMockRuntime struct {
+	// initialContainers returned on first ListContainers call
+	initialContainers []container.Container
+	// afterUpContainers returned on subsequent ListContainers calls (after CreateWithCompose)
+	afterUpContainers []container.Container
+	// listCallCount tracks how many times ListContainers has been called
+	listCallCount int
+	// outputsByCmd maps tmux subcommand to output
+	outputsByCmd map[string]string
+}
+
+func (m *startWorktreeContainerMockRuntime) ListContainers(_ context.Context) ([]container.Container, error) {
+	m.listCallCount++
 	// Return afterUpContainers on subsequent calls (after CreateWithCompose has been called)
 	if m.listCallCount > 1 {
 		return m.afterUpContainers, nil
@@ -1516,6 +2251,10 @@ func (m *startWorktreeContainerMockRuntime) ListContainers(_ context.Context) ([
 	return m.initialContainers, nil
 }
 
+func (m *startWorktreeContainerMockRuntime) ListAllContainers(ctx context.Context) ([]container.Container, error) {
+	return m.ListContainers(ctx)
+}
+
 func (m *startWorktreeContainerMockRuntime) Exec(_ context.Context, _ string, _ []string) (string, error) {
 	return "", nil
 }
@@ -1529,10 +2268,22 @@ func (m *startWorktreeContainerMockRuntime) ExecAs(_ context.Context, _ string,
 	return "", nil
 }
 
+func (m *startWorktreeContainerMockRuntime) Logs(_ context.Context, _ string, _ int) (string, error) {
+	return "", nil
+}
+
 func (m *startWorktreeContainerMockRuntime) InspectContainer(_ context.Context, _ string) (container.ContainerState, error) {
 	return container.StateRunning, nil
 }
 
+func (m *startWorktreeContainerMockRuntime) InspectRaw(_ context.Context, _ string) (json.RawMessage, error) {
+	return json.RawMessage(`[{}]`), nil
+}
+
+func (m *startWorktreeContainerMockRuntime) GetPorts(_ context.Context, _ string) ([]container.PortMapping, error) {
+	return nil, nil
+}
+
 func (m *startWorktreeContainerMockRuntime) GetIsolationInfo(_ context.Context, _ string) (*container.IsolationInfo, error) {
 	return &container.IsolationInfo{}, nil
 }
@@ -1543,13 +2294,21 @@ func (m *startWorktreeContainerMockRuntime) ComposeUp(_ context.Context, _ strin
 func (m *startWorktreeContainerMockRuntime) ComposeStart(_ context.Context, _ string, _ string) error {
 	return nil
 }
-func (m *startWorktreeContainerMockRuntime) ComposeStop(_ context.Context, _ string, _ string) error {
+func (m *startWorktreeContainerMockRuntime) ComposeStop(_ context.Context, _ string, _ string, _ ...string) error {
 	return nil
 }
 func (m *startWorktreeContainerMockRuntime) ComposeDown(_ context.Context, _ string, _ string) error {
 	return nil
 }
 
+func (m *startWorktreeContainerMockRuntime) GetMounts(_ context.Context, _ string) ([]container.MountInfo, error) {
+	return nil, nil
+}
+
+func (m *startWorktreeContainerMockRuntime) ComposeRestart(_ context.Context, _ string, _ string, _ ...string) error {
+	return nil
+}
+
 // setupProjectDirectory creates a project directory with a .devcontainer/docker-compose.yml file
 // and returns the project path. This is needed for CreateWithCompose to succeed.
 func setupProjectDirectory(t *testing.T) string {
@@ -1586,6 +2345,32 @@ func startWorktreeContainerTestServer(
 	afterUpContainers []container.Container,
 	wt *mockWorktreeOps,
 	notifyTUI func(any),
+) string {
+	t.Helper()
+	return startWorktreeContainerTestServerWithScanner(t, initialContainers, afterUpContainers, wt, notifyTUI, nil)
+}
+
+// startCreateTestServer is startWorktreeContainerTestServer with the given
+// project paths discovered, as POST /api/containers requires.
+func startCreateTestServer(t *testing.T, initialContainers, afterUpContainers []container.Container, projectPaths ...string) string {
+	t.Helper()
+	scanner := func(context.Context) []discovery.DiscoveredProject {
+		projects := make([]discovery.DiscoveredProject, 0, len(projectPaths))
+		for _, p := range projectPaths {
+			projects = append(projects, discovery.DiscoveredProject{Name: filepath.Base(p), Path: p})
+		}
+		return projects
+	}
+	return startWorktreeContainerTestServerWithScanner(t, initialContainers, afterUpContainers, &mockWorktreeOps{}, nil, scanner)
+}
+
+func startWorktreeContainerTestServerWithScanner(
+	t *testing.T,
+	initialContainers []container.Container,
+	afterUpContainers []container.Container,
+	wt *mockWorktreeOps,
+	notifyTUI func(any),
+	scanner func(context.Context) []discovery.DiscoveredProject,
 ) string {
 	t.Helper()
 	runtime := &startWorktreeContainerMockRuntime{
@@ -1607,7 +2392,7 @@ func startWorktreeContainerTestServer(
 	lm := logging.NewTestLogManager(10)
 	t.Cleanup(func() { _ = lm.Close() })
 
-	s := web.New(web.Config{Bind: "127.0.0.1", Port: 0}, mgr, notifyTUI, lm, nil)
+	s := web.New(web.Config{Bind: "127.0.0.1", Port: 0}, mgr, notifyTUI, lm, scanner)
 	s.SetWorktreeOpsForTest(wt)
 
 	ln, err := s.Listen()
@@ -1701,6 +2486,137 @@ func TestHandleStartWorktreeContainer_AC21(t *testing.T) {
 	}
 }
 
+// TestHandleStartWorktreeContainer_DryRun verifies ?dry_run=true returns the
+// creation plan without creating a container or notifying the TUI.
+func TestHandleStartWorktreeContainer_DryRun(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	encodedPath := base64.URLEncoding.EncodeToString([]byte(projectPath))
+
+	// An existing container makes FindTemplateForProject pick "default".
+	existing := []container.Container{{
+		ID:          "existing-container",
+		Name:        "project-app-1",
+		State:       container.StateRunning,
+		Template:    "default",
+		ProjectPath: projectPath,
+		CreatedAt:   time.Now().UTC(),
+		Labels:      map[string]string{},
+	}}
+	notified := false
+	base := startWorktreeContainerTestServer(t, existing, nil, &mockWorktreeOps{wtDir: projectPath}, func(any) { notified = true })
+
+	resp, err := http.Post(base+"/api/projects/"+encodedPath+"/worktrees/feature-x/start?dry_run=true", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d, want %d (body: %s)", resp.StatusCode, http.StatusOK, body)
+	}
+
+	var plan container.CreatePlan
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	wantName := worktree.ComposeName(projectPath, "feature-x")
+	if plan.Name != wantName {
+		t.Errorf("plan name = %q, want %q", plan.Name, wantName)
+	}
+	if !plan.ExistingConfig {
+		t.Error("existing_config = false, want true for a project with docker-compose.yml")
+	}
+	if plan.Proxy != nil {
+		t.Errorf("proxy = %+v, want none for a compose file without a proxy service", plan.Proxy)
+	}
+	if notified {
+		t.Error("dry run should not notify the TUI")
+	}
+}
+
+// TestHandleGetCreatePlan verifies GET /api/projects/{path}/plan returns the
+// plan for the project and 404 for a missing project.
+func TestHandleGetCreatePlan(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	base := startWorktreeContainerTestServer(t, nil, nil, &mockWorktreeOps{}, nil)
+
+	resp, err := http.Get(base + "/api/projects/" + base64.URLEncoding.EncodeToString([]byte(projectPath)) + "/plan?template=default")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d, want %d (body: %s)", resp.StatusCode, http.StatusOK, body)
+	}
+	var plan container.CreatePlan
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if plan.Template != "default" || plan.Name != container.SanitizeComposeName(filepath.Base(projectPath)) {
+		t.Errorf("plan template/name = %q/%q", plan.Template, plan.Name)
+	}
+	if _, ok := plan.Files["docker-compose.yml"]; !ok {
+		t.Errorf("plan files = %v, want docker-compose.yml", plan.Files)
+	}
+
+	missing, err := http.Get(base + "/api/projects/" + base64.URLEncoding.EncodeToString([]byte("/does/not/exist")) + "/plan")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	_ = missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("missing project status = %d, want %d", missing.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestHandlePreviewContainer(t *testing.T) {
+	projectPath := t.TempDir()
+	base := startCreateTestServer(t, nil, nil, projectPath)
+
+	resp := postJSON(t, base+"/api/containers/preview", map[string]any{"project_path": projectPath, "template": "default"})
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d, want %d (body: %s)", resp.StatusCode, http.StatusOK, body)
+	}
+	var result container.GenerateResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if !strings.Contains(result.DevcontainerJSON, `"name": "test"`) || result.RunArgs == nil || result.Plan == nil {
+		t.Errorf("result = %+v, want the devcontainer.json, run args and plan", result)
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".devcontainer")); !os.IsNotExist(err) {
+		t.Error("preview wrote files into the project")
+	}
+
+	list, err := http.Get(base + "/api/containers")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	var containers []web.ContainerResponse
+	err = json.NewDecoder(list.Body).Decode(&containers)
+	_ = list.Body.Close()
+	if err != nil || len(containers) != 0 {
+		t.Errorf("containers after preview = %v (decode error %v), want none", containers, err)
+	}
+
+	missing := postJSON(t, base+"/api/containers/preview", map[string]any{"project_path": "/does/not/exist"})
+	_ = missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("missing project status = %d, want %d", missing.StatusCode, http.StatusNotFound)
+	}
+
+	unknown := postJSON(t, base+"/api/containers/preview", map[string]any{"project_path": projectPath, "template": "nope"})
+	body, _ := io.ReadAll(unknown.Body)
+	_ = unknown.Body.Close()
+	if unknown.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "template not found") {
+		t.Errorf("unknown template = %d %s, want 400 naming the template", unknown.StatusCode, body)
+	}
+}
+
 // TestHandleStartWorktreeContainer_AC23 verifies TUI notification is sent.
 // start-missing-container.AC2.3: TUI notification sent
 func TestHandleStartWorktreeContainer_AC23(t *testing.T) {
@@ -1842,9 +2758,9 @@ func TestHandleStartWorktreeContainer_AC25(t *testing.T) {
 		wtDir: wtPath,
 	}
 
-	// The compose project name is SanitizeComposeName(base(projectPath) + "-" + "feature-x")
-	// For /tmp/xyz, this would be something like "xyz-feature-x"
-	composeName := container.SanitizeComposeName(filepath.Base(projectPath) + "-" + "feature-x")
+	// The compose project name is worktree.ComposeName: for /tmp/xyz,
+	// something like "xyz-feature-x-<hash>"
+	composeName := worktree.ComposeName(projectPath, "feature-x")
 
 	// Container already exists for this worktree with matching compose project name
 	existingContainers := []container.Container{
@@ -1924,6 +2840,125 @@ func TestAPI_GetContainer_ByName(t *testing.T) {
 	checkStringField(t, result, "name", "my-project-app-1")
 }
 
+// TestAPI_GetContainer_ByPrefix verifies that a unique prefix of an ID or
+// name resolves, except on destructive routes (400), and that an ambiguous
+// prefix is rejected with 409.
+func TestAPI_GetContainer_ByPrefix(t *testing.T) {
+	containers := []container.Container{
+		{ID: "abc123", Name: "my-project-app-1", State: container.StateStopped, Labels: map[string]string{}},
+		{ID: "def456", Name: "my-project-worker-1", State: container.StateStopped, Labels: map[string]string{}},
+	}
+	base := startAPITestServer(t, containers, "")
+
+	for path, wantID := range map[string]string{"abc": "abc123", "my-project-w": "def456"} {
+		resp, err := http.Get(base + "/api/containers/" + path)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		var result web.ContainerResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil || result.ID != wantID {
+			t.Errorf("GET /api/containers/%s = %d %q (decode error %v), want %s", path, resp.StatusCode, result.ID, err, wantID)
+		}
+	}
+
+	// Stopping, destroying or running something takes the full name or ID
+	for _, req := range []struct{ method, path string }{
+		{http.MethodPost, "/api/containers/abc/stop"},
+		{http.MethodDelete, "/api/containers/my-project-w"},
+		{http.MethodPost, "/api/containers/a/exec"},
+	} {
+		r, err := http.NewRequest(req.method, base+req.path, strings.NewReader(`{"command":["id"]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", req.method, req.path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s %s status = %d, want 400 for an abbreviated reference", req.method, req.path, resp.StatusCode)
+		}
+	}
+
+	resp := postJSON(t, base+"/api/containers/my-project/stop", nil)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("ambiguous prefix status = %d, want 409", resp.StatusCode)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if !strings.Contains(body["error"], "my-project-app-1, my-project-worker-1") {
+		t.Errorf("error = %q, want both matching names", body["error"])
+	}
+}
+
+// TestAPI_GetContainer_Mounts verifies GET /api/containers/{id} includes the
+// mounts and published ports of a running container, while the list endpoint
+// and stopped containers skip the inspect.
+func TestAPI_GetContainer_Mounts(t *testing.T) {
+	mounts := []container.MountInfo{
+		{Type: "bind", Source: "/home/user/myproject", Destination: "/workspaces/myproject"},
+		{Type: "volume", Source: "proxy-certs", Destination: "/tmp/mitmproxy-certs", ReadOnly: true},
+	}
+	ports := []container.PortMapping{{ContainerPort: 3000, HostPort: 13000, Protocol: "tcp"}}
+	runtime := &apiMockRuntime{
+		containers: []container.Container{runningContainer("abc123"), stoppedContainer("def456")},
+		mounts:     mounts,
+		ports:      ports,
+	}
+	base := startProjectsTestServerWithRuntime(t, runtime, nil)
+
+	getContainer := func(path string) web.ContainerResponse {
+		t.Helper()
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var result web.ContainerResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		return result
+	}
+
+	running := getContainer("/api/containers/abc123")
+	if !slices.Equal(running.Mounts, mounts) {
+		t.Errorf("mounts = %+v, want %+v", running.Mounts, mounts)
+	}
+	if !slices.Equal(running.PublishedPorts, ports) {
+		t.Errorf("published ports = %+v, want %+v", running.PublishedPorts, ports)
+	}
+	stopped := getContainer("/api/containers/def456")
+	if stopped.Mounts != nil || stopped.PublishedPorts != nil {
+		t.Errorf("stopped container mounts/ports = %+v/%+v, want none", stopped.Mounts, stopped.PublishedPorts)
+	}
+
+	resp, err := http.Get(base + "/api/containers")
+	if err != nil {
+		t.Fatalf("GET /api/containers error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var list []map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	for _, c := range list {
+		if _, ok := c["mounts"]; ok {
+			t.Errorf("list entry %v has mounts, want the list endpoint to skip them", c["id"])
+		}
+	}
+	if runtime.mountCalls != 1 {
+		t.Errorf("GetMounts called %d times, want 1 (running detail request only)", runtime.mountCalls)
+	}
+}
+
 // TestAPI_StartContainer_ByName verifies that POST /api/containers/{name}/start uses name resolution.
 func TestAPI_StartContainer_ByName(t *testing.T) {
 	containers := []container.Container{
@@ -2245,6 +3280,53 @@ func TestAPI_SendKeys_ContainerStopped(t *testing.T) {
 	}
 }
 
+// TestAPI_SessionKeys verifies POST /api/containers/{id}/sessions/{name}/keys
+// sends keys to an existing session and rejects missing containers and
+// sessions, stopped containers, and empty keys.
+func TestAPI_SessionKeys(t *testing.T) {
+	outputsByCmd := map[string]string{
+		"list-sessions": "dev: 1 windows (created Mon Jan 27 10:00:00 2025)",
+	}
+	running := startMutationTestServer(t, []container.Container{runningContainer("abc123")}, outputsByCmd, nil)
+	stopped := startMutationTestServer(t, []container.Container{stoppedContainer("abc123")}, outputsByCmd, nil)
+
+	tests := []struct {
+		name       string
+		base       string
+		path       string
+		body       web.SessionKeysRequest
+		wantStatus int
+		wantError  string
+	}{
+		{name: "sends keys", base: running, path: "abc123/sessions/dev", body: web.SessionKeysRequest{Keys: "make test"}, wantStatus: http.StatusNoContent},
+		{name: "without enter", base: running, path: "abc123/sessions/dev", body: web.SessionKeysRequest{Keys: "C-c", Enter: new(bool)}, wantStatus: http.StatusNoContent},
+		{name: "empty keys", base: running, path: "abc123/sessions/dev", body: web.SessionKeysRequest{}, wantStatus: http.StatusBadRequest, wantError: "keys is required"},
+		{name: "unknown session", base: running, path: "abc123/sessions/other", body: web.SessionKeysRequest{Keys: "ls"}, wantStatus: http.StatusNotFound, wantError: "session not found"},
+		{name: "unknown container", base: running, path: "nonexistent/sessions/dev", body: web.SessionKeysRequest{Keys: "ls"}, wantStatus: http.StatusNotFound, wantError: "container not found"},
+		{name: "stopped container", base: stopped, path: "abc123/sessions/dev", body: web.SessionKeysRequest{Keys: "ls"}, wantStatus: http.StatusBadRequest, wantError: "container is not running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postJSON(t, tt.base+"/api/containers/"+tt.path+"/keys", tt.body)
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantError == "" {
+				return
+			}
+			var result map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if result["error"] != tt.wantError {
+				t.Errorf("error = %q, want %q", result["error"], tt.wantError)
+			}
+		})
+	}
+}
+
 // TestAPI_CaptureLines_Default verifies GET /api/containers/{id}/sessions/{name}/capture-lines
 // returns scrollback content with default 20 lines.
 func TestAPI_CaptureLines_Default(t *testing.T) {
@@ -2348,3 +3430,756 @@ func TestAPI_CaptureLines_ContainerStopped(t *testing.T) {
 		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
 	}
 }
+
+// TestHandleGetSnapshot verifies that GET /api/containers/{id}/snapshot returns the
+// stored creation snapshot (resolving the container by name), and 404 when the
+// container or its snapshot does not exist.
+func TestHandleGetSnapshot(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataDir)
+
+	containers := []container.Container{
+		{ID: "abc123", Name: "myproject-app-1", State: container.StateRunning, ProjectPath: "/home/user/myproject", Labels: map[string]string{}},
+		{ID: "def456", Name: "legacy-app-1", State: container.StateRunning, ProjectPath: "/home/user/legacy", Labels: map[string]string{}},
+	}
+	base := startAPITestServer(t, containers, "")
+
+	snapshotDir := filepath.Join(dataDir, "devagent", "snapshots")
+	if err := os.MkdirAll(snapshotDir, 0755); err != nil {
+		t.Fatalf("MkdirAll error = %v", err)
+	}
+	stored := `{"container_id":"abc123","template":"go","files":{"devcontainer.json":"{\"name\":\"go\"}"}}`
+	if err := os.WriteFile(filepath.Join(snapshotDir, "abc123.json"), []byte(stored), 0644); err != nil {
+		t.Fatalf("WriteFile error = %v", err)
+	}
+
+	t.Run("returns snapshot", func(t *testing.T) {
+		resp, err := http.Get(base + "/api/containers/myproject-app-1/snapshot")
+		if err != nil {
+			t.Fatalf("GET snapshot error = %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var snap container.CreationSnapshot
+		if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+			t.Fatalf("decode error = %v", err)
+		}
+		if snap.Template != "go" || snap.Files["devcontainer.json"] != `{"name":"go"}` {
+			t.Errorf("snapshot = %+v, want template go with devcontainer.json", snap)
+		}
+	})
+
+	for _, tt := range []struct{ name, id string }{
+		{"no snapshot", "def456"},
+		{"unknown container", "unknown"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(base + "/api/containers/" + tt.id + "/snapshot")
+			if err != nil {
+				t.Fatalf("GET snapshot error = %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+			}
+		})
+	}
+}
+
+// TestHandleInspectContainer verifies that GET /api/containers/{id}/inspect
+// passes the runtime's inspect JSON through verbatim, resolving the container
+// by name, and returns 404 for unknown containers.
+func TestHandleInspectContainer(t *testing.T) {
+	containers := []container.Container{
+		{ID: "abc123", Name: "myproject-app-1", State: container.StateRunning, ProjectPath: "/home/user/myproject", Labels: map[string]string{}},
+	}
+	base := startAPITestServer(t, containers, "")
+
+	resp, err := http.Get(base + "/api/containers/myproject-app-1/inspect")
+	if err != nil {
+		t.Fatalf("GET inspect error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if want := `[{"Id":"abc123","State":{"Status":"running"}}]`; string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+
+	notFound, err := http.Get(base + "/api/containers/unknown/inspect")
+	if err != nil {
+		t.Fatalf("GET inspect error = %v", err)
+	}
+	defer func() { _ = notFound.Body.Close() }()
+	if notFound.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", notFound.StatusCode, http.StatusNotFound)
+	}
+}
+
+// TestHandlePrune verifies POST /api/prune destroys only stopped managed containers.
+func TestHandlePrune(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	managed := func(project string) map[string]string {
+		return map[string]string{"devagent.managed": "true", "com.docker.compose.project": project}
+	}
+	containers := []container.Container{
+		{ID: "stopped1", Name: "old", State: container.StateStopped, ProjectPath: "/src/old", Labels: managed("old")},
+		{ID: "running1", Name: "live", State: container.StateRunning, ProjectPath: "/src/live", Labels: managed("live")},
+		{ID: "foreign1", Name: "foreign", State: container.StateStopped, ProjectPath: "/src/foreign", Labels: map[string]string{}},
+	}
+	var notified []string
+	base := startMutationTestServer(t, containers, nil, func(msg any) {
+		if m, ok := msg.(events.WebSessionActionMsg); ok {
+			notified = append(notified, m.ContainerID)
+		}
+	})
+
+	resp, err := http.Post(base+"/api/prune", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /api/prune error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var result web.PruneResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != "stopped1" {
+		t.Errorf("removed = %v, want [stopped1]", result.Removed)
+	}
+	if len(notified) != 1 || notified[0] != "stopped1" {
+		t.Errorf("notified = %v, want [stopped1]", notified)
+	}
+}
+
+// TestHandlePrune_ConfirmThreshold verifies a prune of more containers than the
+// destroy threshold is refused with 412 until it carries confirm=true.
+func TestHandlePrune_ConfirmThreshold(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var containers []container.Container
+	for i := range 4 {
+		id := fmt.Sprintf("stopped%d", i)
+		containers = append(containers, container.Container{
+			ID: id, Name: id, State: container.StateStopped, ProjectPath: "/src/" + id,
+			Labels: map[string]string{"devagent.managed": "true", "com.docker.compose.project": id},
+		})
+	}
+	base := startMutationTestServer(t, containers, nil, nil)
+
+	resp, err := http.Post(base+"/api/prune", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /api/prune error = %v", err)
+	}
+	var refused web.ConfirmRequiredResponse
+	err = json.NewDecoder(resp.Body).Decode(&refused)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusPreconditionFailed)
+	}
+	if err != nil || refused.Count != 4 || refused.Threshold != 3 {
+		t.Errorf("412 body = %+v (decode error %v), want count 4, threshold 3", refused, err)
+	}
+
+	resp, err = http.Post(base+"/api/prune?confirm=true", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /api/prune?confirm=true error = %v", err)
+	}
+	var result web.PruneResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || err != nil {
+		t.Fatalf("confirmed status = %d (decode error %v), want %d", resp.StatusCode, err, http.StatusOK)
+	}
+	if len(result.Removed) != 4 {
+		t.Errorf("removed = %v, want all 4 stopped containers", result.Removed)
+	}
+}
+
+// getOperations fetches GET /api/operations.
+func getOperations(t *testing.T, base string) []web.OperationResponse {
+	t.Helper()
+	resp, err := http.Get(base + "/api/operations")
+	if err != nil {
+		t.Fatalf("GET /api/operations error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var ops []web.OperationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ops); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	return ops
+}
+
+// TestHandleListOperations verifies GET /api/operations reports a stop while it
+// is in flight and drops it once the stop completes.
+func TestHandleListOperations(t *testing.T) {
+	runtime := &blockingStopRuntime{
+		mutationMockRuntime: mutationMockRuntime{containers: []container.Container{
+			{ID: "abc123", Name: "abc123-app-1", State: container.StateRunning, ProjectPath: "/home/user/myproject", Labels: map[string]string{}},
+		}},
+		release: make(chan struct{}),
+	}
+	base := startRuntimeTestServer(t, runtime, nil)
+
+	if ops := getOperations(t, base); len(ops) != 0 {
+		t.Fatalf("operations = %+v, want none", ops)
+	}
+
+	stopped := make(chan int, 1)
+	go func() {
+		resp, err := http.Post(base+"/api/containers/abc123/stop", "application/json", nil)
+		if err != nil {
+			stopped <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		stopped <- resp.StatusCode
+	}()
+
+	var ops []web.OperationResponse
+	deadline := time.Now().Add(3 * time.Second)
+	for len(ops) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		ops = getOperations(t, base)
+	}
+	if len(ops) != 1 || ops[0].ID != "abc123" || ops[0].Action != "stop" || ops[0].StartedAt.IsZero() {
+		t.Fatalf("operations = %+v, want one in-flight stop of abc123", ops)
+	}
+
+	close(runtime.release)
+	if status := <-stopped; status != http.StatusOK {
+		t.Fatalf("stop status = %d, want %d", status, http.StatusOK)
+	}
+	if ops := getOperations(t, base); len(ops) != 0 {
+		t.Errorf("operations after stop = %+v, want none", ops)
+	}
+}
+
+// TestAPI_Exec verifies POST /api/containers/{id}/exec returns stdout and exit code.
+func TestAPI_Exec(t *testing.T) {
+	containers := []container.Container{runningContainer("abc123")}
+	base := startAPITestServer(t, containers, "On branch main\n")
+
+	body := map[string]any{"command": []string{"git", "status"}, "user": "vscode"}
+	resp := postJSON(t, base+"/api/containers/abc123/exec", body)
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var result web.ExecResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if result.Stdout != "On branch main\n" || result.ExitCode != 0 || result.Truncated {
+		t.Errorf("result = %+v, want stdout with exit code 0", result)
+	}
+}
+
+// TestAPI_Exec_TruncatesOutput verifies stdout is capped at 1 MiB.
+func TestAPI_Exec_TruncatesOutput(t *testing.T) {
+	containers := []container.Container{runningContainer("abc123")}
+	base := startAPITestServer(t, containers, strings.Repeat("x", 1<<20+100))
+
+	resp := postJSON(t, base+"/api/containers/abc123/exec", map[string]any{"command": []string{"cat", "big"}})
+	defer func() { _ = resp.Body.Close() }()
+
+	var result web.ExecResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(result.Stdout) != 1<<20 || !result.Truncated {
+		t.Errorf("stdout length = %d, truncated = %v; want %d, true", len(result.Stdout), result.Truncated, 1<<20)
+	}
+}
+
+// TestAPI_Exec_Errors verifies 404 for unknown containers and 400 for stopped
+// containers or an empty command.
+func TestAPI_Exec_Errors(t *testing.T) {
+	containers := []container.Container{runningContainer("abc123"), stoppedContainer("def456")}
+	base := startAPITestServer(t, containers, "")
+
+	tests := []struct {
+		name       string
+		id         string
+		body       map[string]any
+		wantStatus int
+		wantError  string
+	}{
+		{"unknown container", "nonexistent", map[string]any{"command": []string{"ls"}}, http.StatusNotFound, "container not found"},
+		{"stopped container", "def456", map[string]any{"command": []string{"ls"}}, http.StatusBadRequest, "container is not running"},
+		{"empty command", "abc123", map[string]any{"command": []string{}}, http.StatusBadRequest, "command is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postJSON(t, base+"/api/containers/"+tt.id+"/exec", tt.body)
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			var result map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if result["error"] != tt.wantError {
+				t.Errorf("error = %q, want %q", result["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+// TestAPI_RegenerateProxyCerts verifies POST /api/containers/{id}/regenerate-certs
+// regenerates via the container's proxy sidecar and notifies the TUI.
+func TestAPI_RegenerateProxyCerts(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	app := runningContainer("abc123")
+	app.ProjectPath = "/src/abc"
+	proxy := container.Container{
+		ID:    "proxy1",
+		Name:  "abc123-proxy-1",
+		State: container.StateRunning,
+		Labels: map[string]string{
+			container.LabelSidecarType:    "proxy",
+			container.LabelComposeProject: app.Name,
+		},
+	}
+	var notified []any
+	base := startMutationTestServer(t, []container.Container{app, proxy, stoppedContainer("def456")}, nil, func(msg any) {
+		notified = append(notified, msg)
+	})
+
+	resp := postJSON(t, base+"/api/containers/abc123/regenerate-certs", nil)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if len(notified) != 1 {
+		t.Errorf("notifyTUI called %d times, want 1", len(notified))
+	}
+
+	for id, want := range map[string]int{"def456": http.StatusBadRequest, "nonexistent": http.StatusNotFound} {
+		resp := postJSON(t, base+"/api/containers/"+id+"/regenerate-certs", nil)
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: status = %d, want %d", id, resp.StatusCode, want)
+		}
+	}
+}
+
+// TestAPI_ReloadAllowlist verifies POST /api/containers/{id}/allowlist/reload
+// rewrites the project's filter script and notifies the TUI; a project without
+// a filter script fails and an unknown container is 404.
+func TestAPI_ReloadAllowlist(t *testing.T) {
+	app := runningContainer("abc123")
+	app.ProjectPath = t.TempDir()
+	scriptPath := filepath.Join(app.ProjectPath, ".devcontainer", "containers", "proxy", "opt", "devagent-proxy", "filter.py")
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(scriptPath, []byte("ALLOWED_DOMAINS = [\n    \"github.com\",\n]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	noScript := stoppedContainer("def456")
+	noScript.ProjectPath = t.TempDir()
+
+	var notified []any
+	base := startMutationTestServer(t, []container.Container{app, noScript}, nil, func(msg any) {
+		notified = append(notified, msg)
+	})
+
+	resp := postJSON(t, base+"/api/containers/abc123/allowlist/reload", nil)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if len(notified) != 1 {
+		t.Errorf("notifyTUI called %d times, want 1", len(notified))
+	}
+
+	for id, want := range map[string]int{"def456": http.StatusInternalServerError, "nonexistent": http.StatusNotFound} {
+		resp := postJSON(t, base+"/api/containers/"+id+"/allowlist/reload", nil)
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: status = %d, want %d", id, resp.StatusCode, want)
+		}
+	}
+}
+
+// TestAPI_SetLabels verifies POST /api/containers/{id}/labels stores the
+// devagent.note label, reports it on the container and rejects other labels.
+func TestAPI_SetLabels(t *testing.T) {
+	var notified []any
+	base := startMutationTestServer(t, []container.Container{runningContainer("abc123")}, nil, func(msg any) {
+		notified = append(notified, msg)
+	})
+
+	resp := postJSON(t, base+"/api/containers/abc123/labels", map[string]any{
+		"labels": map[string]string{"devagent.note": " do-not-delete "},
+	})
+	var got web.LabelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || got.Labels["devagent.note"] != "do-not-delete" {
+		t.Errorf("status = %d, labels = %v; want 200 with the trimmed note", resp.StatusCode, got.Labels)
+	}
+	if len(notified) != 1 {
+		t.Errorf("notifyTUI called %d times, want 1", len(notified))
+	}
+
+	resp, err := http.Get(base + "/api/containers/abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c web.ContainerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if c.Note != "do-not-delete" {
+		t.Errorf("container note = %q, want do-not-delete", c.Note)
+	}
+
+	for name, tc := range map[string]struct {
+		id     string
+		labels map[string]string
+		want   int
+	}{
+		"other label":       {"abc123", map[string]string{"devagent.template": "x"}, http.StatusBadRequest},
+		"multi-line note":   {"abc123", map[string]string{"devagent.note": "a\nb"}, http.StatusBadRequest},
+		"unknown container": {"nonexistent", map[string]string{"devagent.note": "x"}, http.StatusNotFound},
+	} {
+		resp := postJSON(t, base+"/api/containers/"+tc.id+"/labels", map[string]any{"labels": tc.labels})
+		_ = resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("%s: status = %d, want %d", name, resp.StatusCode, tc.want)
+		}
+	}
+}
+
+// TestAPI_ContainerLogs_Download verifies GET /api/containers/{id}/logs?download=true
+// returns the log tail as a text attachment.
+func TestAPI_ContainerLogs_Download(t *testing.T) {
+	base := startAPITestServer(t, []container.Container{stoppedContainer("abc123")}, "")
+
+	resp, err := http.Get(base + "/api/containers/abc123/logs?tail=50&download=true")
+	if err != nil {
+		t.Fatalf("GET logs error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	cd := resp.Header.Get("Content-Disposition")
+	if !strings.HasPrefix(cd, `attachment; filename="abc123-app-1-`) || !strings.HasSuffix(cd, `.log"`) {
+		t.Errorf("Content-Disposition = %q, want attachment named abc123-app-1-<timestamp>.log", cd)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "logs for abc123 (tail 50)\n" {
+		t.Errorf("body = %q, want the runtime's log tail", body)
+	}
+}
+
+// TestAPI_ContainerLogs verifies the default tail, inline response, and errors.
+func TestAPI_ContainerLogs(t *testing.T) {
+	base := startAPITestServer(t, []container.Container{runningContainer("abc123")}, "")
+
+	resp, err := http.Get(base + "/api/containers/abc123/logs")
+	if err != nil {
+		t.Fatalf("GET logs error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.Header.Get("Content-Disposition") != "" {
+		t.Error("Content-Disposition should only be set with download=true")
+	}
+	if want := fmt.Sprintf("logs for abc123 (tail %d)\n", container.DefaultLogTail); string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+
+	for path, wantStatus := range map[string]int{
+		"/api/containers/nonexistent/logs":   http.StatusNotFound,
+		"/api/containers/abc123/logs?tail=x": http.StatusBadRequest,
+		"/api/containers/abc123/logs?tail=0": http.StatusBadRequest,
+	} {
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Errorf("GET %s status = %d, want %d", path, resp.StatusCode, wantStatus)
+		}
+	}
+}
+
+func TestHealthzAndReadyz(t *testing.T) {
+	runtime := &apiMockRuntime{containers: []container.Container{runningContainer("c1"), stoppedContainer("c2")}}
+	mgr := container.NewManager(container.ManagerOptions{Runtime: runtime, RuntimeName: "podman"})
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	base := serveTestServer(t, web.New(web.Config{Bind: "127.0.0.1", Port: 0}, mgr, nil, lm, nil))
+
+	getHealth := func(t *testing.T) web.HealthResponse {
+		t.Helper()
+		resp, err := http.Get(base + "/healthz")
+		if err != nil {
+			t.Fatalf("GET /healthz error = %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("/healthz status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var health web.HealthResponse
+		if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+			t.Fatalf("decode /healthz: %v", err)
+		}
+		return health
+	}
+	readyStatus := func(t *testing.T) int {
+		t.Helper()
+		resp, err := http.Get(base + "/readyz")
+		if err != nil {
+			t.Fatalf("GET /readyz error = %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("before first refresh", func(t *testing.T) {
+		health := getHealth(t)
+		if health.Status != "ok" || health.Runtime != "podman" || health.Containers != 0 {
+			t.Errorf("/healthz = %+v, want status ok, runtime podman, 0 containers", health)
+		}
+		if health.UptimeSeconds < 0 {
+			t.Errorf("uptime_seconds = %d, want >= 0", health.UptimeSeconds)
+		}
+		if got := readyStatus(t); got != http.StatusServiceUnavailable {
+			t.Errorf("/readyz status = %d, want %d", got, http.StatusServiceUnavailable)
+		}
+	})
+
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("manager.Refresh() error = %v", err)
+	}
+
+	t.Run("after refresh", func(t *testing.T) {
+		if health := getHealth(t); health.Containers != 2 {
+			t.Errorf("containers = %d, want 2", health.Containers)
+		}
+		if got := readyStatus(t); got != http.StatusOK {
+			t.Errorf("/readyz status = %d, want %d", got, http.StatusOK)
+		}
+	})
+}
+
+// startCloneTestServer creates a test server whose manager clones into
+// cloneRoot with a fake git (recording each URL in gitURLs) and, after
+// compose up, lists afterUpContainers.
+func startCloneTestServer(t *testing.T, cloneRoot string, afterUpContainers []container.Container, gitURLs *[]string, notifyTUI func(any)) string {
+	t.Helper()
+	gitExec := func(_ context.Context, _ map[string]string, _ func(string), _ string, args ...string) error {
+		*gitURLs = append(*gitURLs, args[len(args)-2])
+		return os.MkdirAll(args[len(args)-1], 0755)
+	}
+	return startCloneTestServerWith(t, web.Config{Bind: "127.0.0.1", Port: 0}, cloneRoot, afterUpContainers, gitExec, notifyTUI)
+}
+
+// startCloneTestServerWith is startCloneTestServer with a caller-supplied
+// server config and git executor.
+func startCloneTestServerWith(t *testing.T, webCfg web.Config, cloneRoot string, afterUpContainers []container.Container, gitExec container.StreamExecutor, notifyTUI func(any)) string {
+	t.Helper()
+	runtime := &startWorktreeContainerMockRuntime{
+		afterUpContainers: afterUpContainers,
+		outputsByCmd:      make(map[string]string),
+	}
+
+	cfg, templates := createTestTemplateDir(t)
+	cfg.CloneRoot = cloneRoot
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	mgr := container.NewManager(container.ManagerOptions{
+		Config:      cfg,
+		Templates:   templates,
+		Runtime:     runtime,
+		GitExecutor: gitExec,
+	})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("manager.Refresh() error = %v", err)
+	}
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+
+	s := web.New(webCfg, mgr, notifyTUI, lm, nil)
+	ln, err := s.Listen()
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+		<-done
+	})
+	return "http://" + s.Addr()
+}
+
+// TestHandleCloneProject verifies POST /api/projects/clone clones into the
+// clone root, creates the container there, and rejects bad URLs and existing
+// destinations without running git.
+func TestHandleCloneProject(t *testing.T) {
+	root := t.TempDir()
+	dest := filepath.Join(root, "repo")
+	afterUp := []container.Container{{
+		ID:          "cloned-abc",
+		Name:        "repo",
+		State:       container.StateRunning,
+		Template:    "default",
+		ProjectPath: dest,
+		Labels:      map[string]string{},
+	}}
+
+	var gitURLs []string
+	var notified []any
+	base := startCloneTestServer(t, root, afterUp, &gitURLs, func(msg any) { notified = append(notified, msg) })
+
+	resp := postJSON(t, base+"/api/projects/clone", web.CloneProjectRequest{URL: "https://github.com/org/repo.git", Template: "default", Name: "repo"})
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body %s", resp.StatusCode, http.StatusCreated, body)
+	}
+	var got web.ContainerResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if got.ID != "cloned-abc" || got.ProjectPath != dest {
+		t.Errorf("container = %s at %s, want cloned-abc at %s", got.ID, got.ProjectPath, dest)
+	}
+	if !slices.Equal(gitURLs, []string{"https://github.com/org/repo.git"}) {
+		t.Errorf("git cloned %v", gitURLs)
+	}
+	if len(notified) != 1 {
+		t.Errorf("TUI notified %d times, want 1", len(notified))
+	}
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"url": "--upload-pack=evil"}`, http.StatusBadRequest},
+		{`{"url": "https://github.com/org/.."}`, http.StatusBadRequest},
+		{`{"url": "/etc"}`, http.StatusBadRequest},
+		{`"not an object"`, http.StatusBadRequest},
+		{`{"url": "https://github.com/org/new.git", "mounts": ["type=bind,target=/cache"]}`, http.StatusBadRequest},
+		{`{"url": "https://github.com/org/new.git", "mounts": ["type=bind,source=/home,target=/cache"]}`, http.StatusBadRequest},
+		{`{"url": "git@github.com:other/repo.git"}`, http.StatusConflict}, // dest exists now
+	} {
+		resp := postJSON(t, base+"/api/projects/clone", json.RawMessage(tt.body))
+		_ = resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("POST %s: status = %d, want %d", tt.body, resp.StatusCode, tt.want)
+		}
+	}
+	if len(gitURLs) != 1 {
+		t.Errorf("git ran %d times, want 1", len(gitURLs))
+	}
+}
+
+// TestBuildLimit_RejectsBeyondMaxConcurrentBuilds verifies that with
+// MaxConcurrentBuilds builds in flight, a further build request gets 429 with
+// Retry-After, and a slot frees up once a build finishes.
+func TestBuildLimit_RejectsBeyondMaxConcurrentBuilds(t *testing.T) {
+	const maxBuilds = 2
+	started := make(chan string, maxBuilds+1)
+	release := make(chan struct{})
+	gitExec := func(ctx context.Context, _ map[string]string, _ func(string), _ string, args ...string) error {
+		started <- args[len(args)-2]
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return errors.New("clone failed")
+	}
+	base := startCloneTestServerWith(t, web.Config{Bind: "127.0.0.1", Port: 0, MaxConcurrentBuilds: maxBuilds},
+		t.TempDir(), nil, gitExec, func(any) {})
+
+	clone := func(repo string) *http.Response {
+		return postJSON(t, base+"/api/projects/clone", web.CloneProjectRequest{URL: "https://github.com/org/" + repo + ".git"})
+	}
+
+	statuses := make(chan int, maxBuilds)
+	for i := range maxBuilds {
+		go func() {
+			resp := clone(fmt.Sprintf("repo%d", i))
+			_ = resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	for range maxBuilds {
+		select {
+		case <-started:
+		case <-time.After(3 * time.Second):
+			t.Fatal("the first builds did not start")
+		}
+	}
+
+	// Every slot is taken: clone and worktree start are both rejected
+	resp := clone("one-too-many")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("extra clone status = %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+	if got := resp.Header.Get("Retry-After"); got == "" {
+		t.Error("429 should carry Retry-After")
+	}
+	encoded := base64.URLEncoding.EncodeToString([]byte(t.TempDir()))
+	resp = postJSON(t, base+"/api/projects/"+encoded+"/worktrees/feature/start", nil)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("worktree start status = %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+
+	close(release)
+	for range maxBuilds {
+		if status := <-statuses; status == http.StatusTooManyRequests {
+			t.Errorf("an admitted build got %d", status)
+		}
+	}
+	select {
+	case repo := <-started:
+		t.Errorf("rejected request for %s still ran git", repo)
+	default:
+	}
+
+	// Slots are released once the builds finish
+	resp = clone("after")
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.Error("a build after the others finished should be admitted")
+	}
+}
@@ -0,0 +1,82 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"devagent/internal/audit"
+	"devagent/internal/container"
+	"devagent/internal/logging"
+	"devagent/internal/web"
+)
+
+// TestAudit_DestroyRecordsSource verifies that destroying a container through
+// the API writes an audit entry with source "web" (or "cli" when the request
+// carries audit.SourceHeader), readable newest first via GET /api/audit.
+func TestAudit_DestroyRecordsSource(t *testing.T) {
+	runtime := &mutationMockRuntime{containers: []container.Container{
+		{ID: "abc123", Name: "abc-app-1", State: container.StateRunning, ProjectPath: "/home/user/abc", Labels: map[string]string{}},
+		{ID: "def456", Name: "def-app-1", State: container.StateRunning, ProjectPath: "/home/user/def", Labels: map[string]string{}},
+	}}
+	mgr := container.NewManager(container.ManagerOptions{Runtime: runtime})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("manager.Refresh() error = %v", err)
+	}
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	auditLog := audit.Open(filepath.Join(t.TempDir(), audit.FileName))
+	base := serveTestServer(t, web.New(web.Config{Bind: "127.0.0.1", Port: 0, Audit: auditLog}, mgr, nil, lm, nil))
+
+	resp := deleteRequest(t, base+"/api/containers/abc123")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("DELETE status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, base+"/api/containers/def456", nil)
+	req.Header.Set(audit.SourceHeader, audit.SourceCLI)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	entries, err := auditLog.Recent(10)
+	if err != nil {
+		t.Fatalf("Recent() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("audit entries = %+v, want 2", entries)
+	}
+	if e := entries[1]; e.Action != "container.destroy" || e.Target != "abc123" || e.Source != audit.SourceWeb || e.Result != audit.ResultOK {
+		t.Errorf("web destroy entry = %+v", e)
+	}
+	if e := entries[0]; e.Target != "def456" || e.Source != audit.SourceCLI {
+		t.Errorf("cli destroy entry = %+v", e)
+	}
+
+	resp, err = http.Get(base + "/api/audit?limit=1")
+	if err != nil {
+		t.Fatalf("GET /api/audit error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var got []audit.Entry
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if len(got) != 1 || got[0].Target != "def456" {
+		t.Errorf("GET /api/audit?limit=1 = %+v, want the newest entry", got)
+	}
+
+	bad, err := http.Get(base + "/api/audit?limit=zero")
+	if err != nil {
+		t.Fatalf("GET /api/audit error = %v", err)
+	}
+	_ = bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid limit status = %d, want %d", bad.StatusCode, http.StatusBadRequest)
+	}
+}
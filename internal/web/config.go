@@ -0,0 +1,97 @@
+// pattern: Imperative Shell
+package web
+
+import (
+	"net/http"
+	"sync"
+
+	"devagent/internal/config"
+)
+
+// ConfigResponse is the read-only view of the configuration served by
+// GET /api/config. It is built field by field from config.Config so that
+// secrets (token paths, the tailscale auth key path) and anything added to
+// config.Config later are never serialized by accident.
+type ConfigResponse struct {
+	Runtime      string             `json:"runtime"`
+	ScanPaths    []string           `json:"scan_paths"`
+	Templates    []TemplateResponse `json:"templates"`
+	WebURL       string             `json:"web_url"`
+	TailscaleURL string             `json:"tailscale_url,omitempty"`
+	ReadOnly     bool               `json:"read_only"` // this caller's mutations are rejected (web.read_only)
+}
+
+// TemplateResponse describes one template in ConfigResponse. Only session
+// names are exposed: session commands may carry credentials.
+type TemplateResponse struct {
+	Name            string   `json:"name"`
+	Extends         string   `json:"extends,omitempty"`
+	DefaultScanRoot string   `json:"default_scan_root,omitempty"`
+	Sessions        []string `json:"sessions"`
+}
+
+// configState holds what GET /api/config reports; main updates it at startup,
+// on SIGHUP reload and once the tailscale URL resolves.
+type configState struct {
+	mu           sync.RWMutex
+	set          bool
+	scanPaths    []string
+	templates    []TemplateResponse
+	tailscaleURL string
+}
+
+// SetConfig records the configuration and templates GET /api/config reports.
+// Safe to call while serving (e.g. after a config reload). Until it is
+// called, the endpoint returns 503.
+func (s *Server) SetConfig(cfg config.Config, templates []config.Template) {
+	tmpls := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		sessions := make([]string, 0, len(t.InitialSessions))
+		for _, spec := range t.InitialSessions {
+			sessions = append(sessions, spec.Name)
+		}
+		tmpls = append(tmpls, TemplateResponse{
+			Name:            t.Name,
+			Extends:         t.Extends,
+			DefaultScanRoot: t.DefaultScanRoot,
+			Sessions:        sessions,
+		})
+	}
+	scanPaths := cfg.ResolveScanPaths()
+	if scanPaths == nil {
+		scanPaths = []string{}
+	}
+
+	s.config.mu.Lock()
+	defer s.config.mu.Unlock()
+	s.config.set = true
+	s.config.scanPaths = scanPaths
+	s.config.templates = tmpls
+}
+
+// SetTailscaleURL records the instance's tailscale URL for GET /api/config.
+func (s *Server) SetTailscaleURL(url string) {
+	s.config.mu.Lock()
+	defer s.config.mu.Unlock()
+	s.config.tailscaleURL = url
+}
+
+// handleGetConfig handles GET /api/config.
+// Returns 503 until SetConfig has been called.
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	s.config.mu.RLock()
+	defer s.config.mu.RUnlock()
+	if !s.config.set {
+		writeError(w, http.StatusServiceUnavailable, "configuration is not available")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ConfigResponse{
+		Runtime:      s.manager.RuntimeName(),
+		ScanPaths:    s.config.scanPaths,
+		Templates:    s.config.templates,
+		WebURL:       s.URL(),
+		TailscaleURL: s.config.tailscaleURL,
+		ReadOnly:     isReadOnly(r.Context()),
+	})
+}
@@ -0,0 +1,85 @@
+package web_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"devagent/internal/config"
+	"devagent/internal/container"
+	"devagent/internal/logging"
+	"devagent/internal/web"
+)
+
+func TestHandleGetConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ScanPaths = []string{"/home/user/projects"}
+	cfg.ClaudeTokenPath = "/secret/claude-token"
+	cfg.GitHubTokenPath = "/secret/github-token"
+	cfg.Tailscale.AuthKeyPath = "/secret/tailscale-authkey"
+	templates := []config.Template{{
+		Name:            "go",
+		Extends:         "base",
+		DefaultScanRoot: "~/go-projects",
+		InitialSessions: []config.SessionSpec{{Name: "agent", Command: "run --token=/secret/inline"}},
+	}}
+
+	mgr := container.NewManager(container.ManagerOptions{Config: &cfg, RuntimeName: "podman"})
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	s := web.New(web.Config{Bind: "127.0.0.1", Port: 0}, mgr, nil, lm, nil)
+
+	baseURL := serveTestServer(t, s)
+
+	resp, err := http.Get(baseURL + "/api/config")
+	if err != nil {
+		t.Fatalf("GET /api/config error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status before SetConfig = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	s.SetConfig(cfg, templates)
+	s.SetTailscaleURL("https://devagent.example.ts.net")
+
+	resp, err = http.Get(baseURL + "/api/config")
+	if err != nil {
+		t.Fatalf("GET /api/config error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got web.ConfigResponse
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if got.Runtime != "podman" {
+		t.Errorf("runtime = %q, want podman", got.Runtime)
+	}
+	if len(got.ScanPaths) != 1 || got.ScanPaths[0] != "/home/user/projects" {
+		t.Errorf("scan_paths = %v, want [/home/user/projects]", got.ScanPaths)
+	}
+	if len(got.Templates) != 1 || got.Templates[0].Name != "go" || got.Templates[0].Extends != "base" ||
+		len(got.Templates[0].Sessions) != 1 || got.Templates[0].Sessions[0] != "agent" {
+		t.Errorf("templates = %+v, want go extending base with session agent", got.Templates)
+	}
+	if got.WebURL != baseURL {
+		t.Errorf("web_url = %q, want %q", got.WebURL, baseURL)
+	}
+	if got.TailscaleURL != "https://devagent.example.ts.net" {
+		t.Errorf("tailscale_url = %q", got.TailscaleURL)
+	}
+
+	if strings.Contains(string(raw), "/secret/") || strings.Contains(string(raw), "token_path") {
+		t.Errorf("response leaks credentials: %s", raw)
+	}
+}
@@ -0,0 +1,56 @@
+// pattern: Imperative Shell
+
+package web
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORS response values for /api/ routes.
+const (
+	corsAllowMethods = "GET, POST, DELETE, OPTIONS"
+	corsAllowHeaders = "Content-Type"
+)
+
+// corsMiddleware answers cross-origin requests to /api/ routes from the
+// allowed origins (exact match, or "*" for any origin) and handles OPTIONS
+// preflights itself. Requests without an Origin header, and requests outside
+// /api/, are passed through untouched. A disallowed origin gets no CORS
+// headers, so the browser blocks it; its preflight is answered 403.
+func corsMiddleware(allowed []string, next http.Handler) http.Handler {
+	anyOrigin := slices.Contains(allowed, "*")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		switch {
+		case anyOrigin:
+			h.Set("Access-Control-Allow-Origin", "*")
+		case slices.Contains(allowed, origin):
+			h.Set("Access-Control-Allow-Origin", origin)
+		case preflight:
+			w.WriteHeader(http.StatusForbidden)
+			return
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if preflight {
+			h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
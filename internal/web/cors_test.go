@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"devagent/internal/logging"
+	"devagent/internal/web"
+)
+
+func startCORSTestServer(t *testing.T, allowed []string) string {
+	t.Helper()
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	return serveTestServer(t, web.New(web.Config{Bind: "127.0.0.1", Port: 0, AllowedOrigins: allowed}, nil, nil, lm, nil))
+}
+
+func corsRequest(t *testing.T, method, url, origin string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	base := startCORSTestServer(t, []string{"https://dash.example.ts.net"})
+
+	t.Run("allowed origin", func(t *testing.T) {
+		resp := corsRequest(t, http.MethodOptions, base+"/api/prune", "https://dash.example.ts.net")
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://dash.example.ts.net" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Methods"); got != "GET, POST, DELETE, OPTIONS" {
+			t.Errorf("Access-Control-Allow-Methods = %q", got)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Headers"); got != "Content-Type" {
+			t.Errorf("Access-Control-Allow-Headers = %q, want Content-Type", got)
+		}
+	})
+
+	t.Run("other origin", func(t *testing.T) {
+		resp := corsRequest(t, http.MethodOptions, base+"/api/prune", "https://evil.example.com")
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+		}
+	})
+}
+
+func TestCORS_OriginMatching(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    string
+	}{
+		{name: "exact match", allowed: []string{"https://a.example", "https://b.example"}, origin: "https://b.example", want: "https://b.example"},
+		{name: "no match", allowed: []string{"https://a.example"}, origin: "https://a.example:8443"},
+		{name: "wildcard", allowed: []string{"*"}, origin: "https://anything.example", want: "*"},
+		{name: "not configured", origin: "https://a.example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := startCORSTestServer(t, tt.allowed)
+			resp := corsRequest(t, http.MethodGet, base+"/api/health", tt.origin)
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package web_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRejectCrossSite(t *testing.T) {
+	base := startCORSTestServer(t, []string{"https://dash.example.ts.net"})
+	host := strings.TrimPrefix(base, "http://")
+
+	// POST /api/restart with no restart func answers 503 once it gets past
+	// the cross-site guard.
+	tests := []struct {
+		name    string
+		headers map[string]string
+		body    string
+		want    int
+	}{
+		{name: "no browser headers", want: http.StatusServiceUnavailable},
+		{name: "same origin", headers: map[string]string{"Sec-Fetch-Site": "same-origin", "Origin": base}, want: http.StatusServiceUnavailable},
+		{name: "origin matches host", headers: map[string]string{"Origin": "http://" + host}, want: http.StatusServiceUnavailable},
+		{name: "allowed origin", headers: map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://dash.example.ts.net"}, want: http.StatusServiceUnavailable},
+		{name: "cross-site", headers: map[string]string{"Sec-Fetch-Site": "cross-site", "Origin": "https://evil.example.com"}, want: http.StatusForbidden},
+		{name: "same-site other port", headers: map[string]string{"Sec-Fetch-Site": "same-site", "Origin": "http://127.0.0.1:1"}, want: http.StatusForbidden},
+		{name: "foreign origin without Sec-Fetch-Site", headers: map[string]string{"Origin": "https://evil.example.com"}, want: http.StatusForbidden},
+		{name: "json body", headers: map[string]string{"Content-Type": "application/json; charset=utf-8"}, body: "{}", want: http.StatusServiceUnavailable},
+		{name: "text/plain body", headers: map[string]string{"Content-Type": "text/plain"}, body: `{"command":["id"]}`, want: http.StatusUnsupportedMediaType},
+		{name: "body without content type", body: "{}", want: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, base+"/api/restart", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST /api/restart error = %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	t.Run("reads are not guarded", func(t *testing.T) {
+		resp := corsRequest(t, http.MethodGet, base+"/api/health", "https://evil.example.com")
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+	})
+}
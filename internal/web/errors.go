@@ -0,0 +1,39 @@
+// pattern: Imperative Shell
+
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"devagent/internal/container"
+)
+
+// managerErrorStatus maps an error from container.Manager to the HTTP status
+// its kind calls for: 404 for container.ErrNotFound, 400 for
+// container.ErrNotRunning, container.ErrAlreadyRunning and
+// container.ErrInvalid, 409 for container.ErrAlreadyExists and
+// container.ErrProtected, and 500 for anything else.
+func managerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, container.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, container.ErrNotRunning), errors.Is(err, container.ErrAlreadyRunning), errors.Is(err, container.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, container.ErrAlreadyExists), errors.Is(err, container.ErrProtected):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// writeManagerError answers a failed container.Manager call. A typed error
+// gets its status (managerErrorStatus) with its own message as the body;
+// anything else is a 500 with fallback, so internal details stay out of it.
+func writeManagerError(w http.ResponseWriter, err error, fallback string) {
+	status := managerErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		writeError(w, status, fallback)
+		return
+	}
+	writeError(w, status, err.Error())
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"devagent/internal/container"
+)
+
+func TestManagerErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{container.ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w: abc", container.ErrContainerNotFound), http.StatusNotFound},
+		{container.ErrSnapshotNotFound, http.StatusNotFound},
+		{container.ErrNotRunning, http.StatusBadRequest},
+		{container.ErrAlreadyRunning, http.StatusBadRequest},
+		{container.ErrAlreadyExists, http.StatusConflict},
+		{fmt.Errorf("%w: dev", container.ErrSessionExists), http.StatusConflict},
+		{container.ErrContainerExists, http.StatusConflict},
+		{container.ErrCloneExists, http.StatusConflict},
+		{fmt.Errorf("failed to generate compose config: %w", fmt.Errorf("%w: nope", container.ErrTemplateNotFound)), http.StatusBadRequest},
+		{container.ErrInvalidTemplateData, http.StatusBadRequest},
+		{container.ValidateNote("a\nb"), http.StatusBadRequest},
+		{fmt.Errorf("%w: app", container.ErrContainerProtected), http.StatusConflict},
+		{&os.PathError{Op: "open", Path: "/data/container-notes.json.tmp", Err: os.ErrPermission}, http.StatusInternalServerError},
+		{errors.New("compose exploded"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := managerErrorStatus(tt.err); got != tt.want {
+			t.Errorf("managerErrorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWriteManagerError_KeepsTypedMessages(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeManagerError(rec, fmt.Errorf("%w: dev", container.ErrSessionExists), "failed to create session")
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if rec.Code != http.StatusConflict || body["error"] != "session already exists: dev" {
+		t.Errorf("got %d %q, want 409 with the error's message", rec.Code, body["error"])
+	}
+
+	rec = httptest.NewRecorder()
+	writeManagerError(rec, errors.New("exec: docker: permission denied"), "failed to create session")
+	body = nil
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if rec.Code != http.StatusInternalServerError || body["error"] != "failed to create session" {
+		t.Errorf("got %d %q, want 500 with the fallback", rec.Code, body["error"])
+	}
+}
@@ -2,6 +2,7 @@ export type Container = {
   id: string
   name: string
   state: string
+  exit_code?: number
   template: string
   project_path: string
   remote_user: string
@@ -9,6 +10,23 @@ export type Container = {
   ports: Record<string, string>
   created_at: string
   sessions: Array<Session>
+  mounts?: Array<Mount> // only from GET /api/containers/{id} for a running container
+  published_ports?: Array<PortMapping> // only from GET /api/containers/{id} for a running container
+  last_activity?: string // last exec/session/attach activity since the instance started
+  note?: string // user devagent.note label
+}
+
+export type Mount = {
+  type: string // "bind" or "volume"
+  source: string
+  destination: string
+  read_only: boolean
+}
+
+export type PortMapping = {
+  container: number
+  host: number
+  protocol: string // "tcp" or "udp"
 }
 
 export type Session = {
@@ -37,6 +55,22 @@ export type ProjectsListResponse = {
   unmatched: Array<Container>
 }
 
+export type TemplateSummary = {
+  name: string
+  extends?: string // base template, if any
+  default_scan_root?: string
+  sessions: Array<string> // initial session names
+}
+
+export type ConfigResponse = {
+  runtime: string
+  scan_paths: Array<string>
+  templates: Array<TemplateSummary>
+  web_url: string
+  tailscale_url?: string
+  read_only: boolean // this client's mutations are rejected with 403
+}
+
 const API_BASE = '/api'
 
 export async function fetchContainers(): Promise<Array<Container>> {
@@ -45,6 +79,19 @@ export async function fetchContainers(): Promise<Array<Container>> {
   return res.json() as Promise<Array<Container>>
 }
 
+export type ContainersPage = {
+  containers: Array<Container>
+  total: number
+  limit: number
+  offset: number
+}
+
+export async function fetchContainersPage(limit: number, offset = 0): Promise<ContainersPage> {
+  const res = await fetch(`${API_BASE}/containers?limit=${limit}&offset=${offset}`)
+  if (!res.ok) throw new Error(`failed to fetch containers: ${res.status}`)
+  return res.json() as Promise<ContainersPage>
+}
+
 export async function fetchContainer(id: string): Promise<Container> {
   const res = await fetch(`${API_BASE}/containers/${id}`)
   if (!res.ok) throw new Error(`failed to fetch container: ${res.status}`)
@@ -63,6 +110,18 @@ export async function fetchProjects(): Promise<ProjectsListResponse> {
   return res.json() as Promise<ProjectsListResponse>
 }
 
+export async function fetchProject(encodedPath: string): Promise<ProjectResponse> {
+  const res = await fetch(`${API_BASE}/projects/${encodedPath}`)
+  if (!res.ok) throw new Error(`failed to fetch project: ${res.status}`)
+  return res.json() as Promise<ProjectResponse>
+}
+
+export async function fetchConfig(): Promise<ConfigResponse> {
+  const res = await fetch(`${API_BASE}/config`)
+  if (!res.ok) throw new Error(`failed to fetch config: ${res.status}`)
+  return res.json() as Promise<ConfigResponse>
+}
+
 export async function startContainer(id: string): Promise<void> {
   const res = await fetch(`${API_BASE}/containers/${id}/start`, {
     method: 'POST',
@@ -93,6 +152,31 @@ export async function destroyContainer(id: string): Promise<void> {
   }
 }
 
+export async function setContainerNote(id: string, note: string): Promise<void> {
+  const res = await fetch(`${API_BASE}/containers/${id}/labels`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ labels: { 'devagent.note': note } }),
+  })
+  if (!res.ok) {
+    const body = await res.json().catch(() => ({})) as { error?: string }
+    throw new Error(body.error ?? `failed to set container note: ${res.status}`)
+  }
+}
+
+export async function cloneProject(url: string, template = '', name = ''): Promise<Container> {
+  const res = await fetch(`${API_BASE}/projects/clone`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ url, template, name }),
+  })
+  if (!res.ok) {
+    const body = await res.json().catch(() => ({})) as { error?: string }
+    throw new Error(body.error ?? `failed to clone project: ${res.status}`)
+  }
+  return res.json() as Promise<Container>
+}
+
 export async function createSession(containerId: string, name: string): Promise<void> {
   const res = await fetch(`${API_BASE}/containers/${containerId}/sessions`, {
     method: 'POST',
@@ -156,7 +240,7 @@ export async function createWorktree(encodedPath: string, name: string): Promise
 }
 
 export async function deleteWorktree(encodedPath: string, name: string): Promise<void> {
-  const res = await fetch(`${API_BASE}/projects/${encodedPath}/worktrees/${name}`, {
+  const res = await fetch(`${API_BASE}/projects/${encodedPath}/worktrees/${encodeURIComponent(name)}`, {
     method: 'DELETE',
   })
   if (!res.ok) {
@@ -166,7 +250,7 @@ export async function deleteWorktree(encodedPath: string, name: string): Promise
 }
 
 export async function startWorktreeContainer(encodedPath: string, name: string): Promise<void> {
-  const res = await fetch(`${API_BASE}/projects/${encodedPath}/worktrees/${name}/start`, {
+  const res = await fetch(`${API_BASE}/projects/${encodedPath}/worktrees/${encodeURIComponent(name)}/start`, {
     method: 'POST',
   })
   if (!res.ok) {
@@ -17,6 +17,8 @@ function stateColorClass(state: string): string {
       return 'text-green'
     case 'stopped':
       return 'text-yellow'
+    case 'exited':
+      return 'text-red'
     default:
       return 'text-overlay-0'
   }
@@ -104,7 +106,7 @@ export function ContainerCard({ container, onRefresh, onAttach, expanded, onTogg
         <div className="flex items-center gap-3 min-w-0">
           <span className="text-text font-semibold truncate">{container.name}</span>
           <span className={`text-xs font-mono shrink-0 ${stateColorClass(container.state)}`}>
-            {container.state}
+            {container.state}{container.exit_code ? ` (${container.exit_code})` : ''}
           </span>
         </div>
         <span className="text-overlay-0 text-xs ml-2 shrink-0">
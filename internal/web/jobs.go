@@ -0,0 +1,179 @@
+// pattern: Imperative Shell
+
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Job statuses, in lifecycle order.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// maxPendingJobs caps the queued and running jobs; further enqueues are
+// refused so a client cannot pile up builds without bound.
+const maxPendingJobs = 32
+
+// maxFinishedJobs is how many finished jobs are kept for polling; older ones
+// are forgotten (GET /api/jobs/{id} then returns 404).
+const maxFinishedJobs = 100
+
+// JobResponse is the JSON body of GET /api/jobs/{id}.
+type JobResponse struct {
+	ID         string             `json:"id"`
+	Status     string             `json:"status"`              // JobQueued, JobRunning, JobCompleted or JobFailed
+	Name       string             `json:"name"`                // container being created
+	Progress   string             `json:"progress,omitempty"`  // latest progress message
+	Container  *ContainerResponse `json:"container,omitempty"` // set once completed
+	Error      string             `json:"error,omitempty"`     // set once failed
+	CreatedAt  time.Time          `json:"created_at"`
+	StartedAt  *time.Time         `json:"started_at,omitempty"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+}
+
+// jobWork runs a job's build, reporting progress messages, and returns the
+// created container.
+type jobWork func(ctx context.Context, progress func(string)) (*ContainerResponse, error)
+
+// jobQueue runs container builds in the background so a create request can
+// answer at once. Jobs wait for a slot of the shared buildLimiter, so queued
+// builds and synchronous ones (worktree create/start, clone) together stay
+// within the cap. Jobs live in memory only.
+type jobQueue struct {
+	builds *buildLimiter
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	jobs     map[string]*JobResponse
+	pending  int
+	finished []string // IDs of finished jobs, oldest first
+}
+
+// newJobQueue returns an empty queue drawing build slots from builds.
+func newJobQueue(builds *buildLimiter) *jobQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobQueue{
+		builds: builds,
+		ctx:    ctx,
+		cancel: cancel,
+		jobs:   make(map[string]*JobResponse),
+	}
+}
+
+// enqueue registers a job for the container name and runs work once a build
+// slot is free. Returns the job ID, or false when maxPendingJobs are already
+// queued or running.
+func (q *jobQueue) enqueue(name string, work jobWork) (string, bool) {
+	q.mu.Lock()
+	if q.pending >= maxPendingJobs {
+		q.mu.Unlock()
+		return "", false
+	}
+	id := newJobID()
+	q.jobs[id] = &JobResponse{ID: id, Status: JobQueued, Name: name, CreatedAt: time.Now().UTC()}
+	q.pending++
+	q.wg.Add(1)
+	q.mu.Unlock()
+
+	go q.run(id, work)
+	return id, true
+}
+
+// run waits for a build slot, runs work and records its outcome.
+func (q *jobQueue) run(id string, work jobWork) {
+	defer q.wg.Done()
+
+	if err := q.builds.acquire(q.ctx); err != nil {
+		q.finish(id, nil, err)
+		return
+	}
+	defer q.builds.release()
+
+	q.update(id, func(j *JobResponse) {
+		now := time.Now().UTC()
+		j.Status = JobRunning
+		j.StartedAt = &now
+	})
+	c, err := work(q.ctx, func(msg string) {
+		q.update(id, func(j *JobResponse) { j.Progress = msg })
+	})
+	q.finish(id, c, err)
+}
+
+// update applies fn to job id under the lock.
+func (q *jobQueue) update(id string, fn func(*JobResponse)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if j, ok := q.jobs[id]; ok {
+		fn(j)
+	}
+}
+
+// finish marks job id completed with c, or failed with err, and forgets the
+// oldest finished jobs beyond maxFinishedJobs.
+func (q *jobQueue) finish(id string, c *ContainerResponse, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	j.FinishedAt = &now
+	if err != nil {
+		j.Status = JobFailed
+		j.Error = err.Error()
+	} else {
+		j.Status = JobCompleted
+		j.Container = c
+	}
+	q.pending--
+	q.finished = append(q.finished, id)
+	for len(q.finished) > maxFinishedJobs {
+		delete(q.jobs, q.finished[0])
+		q.finished = q.finished[1:]
+	}
+}
+
+// get returns a copy of job id.
+func (q *jobQueue) get(id string) (JobResponse, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return JobResponse{}, false
+	}
+	return *j, true
+}
+
+// shutdown cancels every queued and running job, then waits for them to wind
+// down (a canceled create removes what it started) until ctx is done.
+func (q *jobQueue) shutdown(ctx context.Context) {
+	q.cancel()
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// newJobID returns a random job ID.
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,161 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"devagent/internal/container"
+	"devagent/internal/web"
+)
+
+// pollJob polls GET /api/jobs/{id} until the job finishes.
+func pollJob(t *testing.T, base, id string) web.JobResponse {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get(base + "/api/jobs/" + id)
+		if err != nil {
+			t.Fatalf("GET job error = %v", err)
+		}
+		var job web.JobResponse
+		err = json.NewDecoder(resp.Body).Decode(&job)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || err != nil {
+			t.Fatalf("GET job status = %d, decode error = %v", resp.StatusCode, err)
+		}
+		if job.Status == web.JobCompleted || job.Status == web.JobFailed {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job still %s after 5s", job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// enqueueCreate posts a create request and returns the accepted job ID.
+func enqueueCreate(t *testing.T, base string, body map[string]any) string {
+	t.Helper()
+	resp := postJSON(t, base+"/api/containers", body)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /api/containers status = %d, want 202", resp.StatusCode)
+	}
+	var accepted web.JobAcceptedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil {
+		t.Fatalf("decode error = %v", err)
+	}
+	if accepted.JobID == "" || accepted.Status != web.JobQueued {
+		t.Fatalf("accepted = %+v, want a queued job", accepted)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/api/jobs/"+accepted.JobID {
+		t.Errorf("Location = %q, want /api/jobs/%s", loc, accepted.JobID)
+	}
+	return accepted.JobID
+}
+
+func TestCreateContainerJob_Completes(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	created := []container.Container{{
+		ID:          "mock-container-abc123",
+		Name:        "myapp-app-1",
+		State:       container.StateRunning,
+		Template:    "default",
+		ProjectPath: projectPath,
+		RemoteUser:  "vscode",
+		CreatedAt:   time.Now().UTC(),
+		Labels:      map[string]string{},
+	}}
+	base := startCreateTestServer(t, nil, created, projectPath)
+
+	id := enqueueCreate(t, base, map[string]any{"project_path": projectPath, "template": "default", "name": "myapp"})
+	job := pollJob(t, base, id)
+
+	if job.Status != web.JobCompleted {
+		t.Fatalf("job = %+v, want completed", job)
+	}
+	if job.Name != "myapp" || job.Container == nil || job.Container.ID != "mock-container-abc123" {
+		t.Errorf("job = %+v, want container mock-container-abc123 for myapp", job)
+	}
+	if job.StartedAt == nil || job.FinishedAt == nil {
+		t.Errorf("job times = %v / %v, want both set", job.StartedAt, job.FinishedAt)
+	}
+}
+
+func TestCreateContainerJob_FailureSurfacesError(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	base := startCreateTestServer(t, nil, nil, projectPath)
+
+	id := enqueueCreate(t, base, map[string]any{"project_path": projectPath, "template": "no-such-template", "name": "myapp"})
+	job := pollJob(t, base, id)
+
+	if job.Status != web.JobFailed || !strings.Contains(job.Error, "no-such-template") {
+		t.Errorf("job = %+v, want failed naming the template", job)
+	}
+	if job.Container != nil {
+		t.Errorf("failed job has container %+v", job.Container)
+	}
+}
+
+func TestCreateContainerJob_Validation(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	base := startCreateTestServer(t, nil, nil, projectPath)
+
+	tests := []struct {
+		name string
+		body map[string]any
+		want int
+	}{
+		{name: "relative path", body: map[string]any{"project_path": "project"}, want: http.StatusBadRequest},
+		{name: "missing project", body: map[string]any{"project_path": projectPath + "/missing"}, want: http.StatusNotFound},
+		{name: "undiscovered directory", body: map[string]any{"project_path": t.TempDir()}, want: http.StatusNotFound},
+		{name: "host root", body: map[string]any{"project_path": "/"}, want: http.StatusNotFound},
+		{name: "invalid mount", body: map[string]any{"project_path": projectPath, "mounts": []string{"type=bind,target=/x"}}, want: http.StatusBadRequest},
+		{name: "bind mount", body: map[string]any{"project_path": projectPath, "mounts": []string{"type=bind,source=/etc,target=/x"}}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := postJSON(t, base+"/api/containers", tt.body)
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+
+	resp, err := http.Get(base + "/api/jobs/unknown")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestCreateContainerJob_ExistingContainerConflicts(t *testing.T) {
+	projectPath := setupProjectDirectory(t)
+	existing := []container.Container{{
+		ID:          "existing123",
+		Name:        "myapp-app-1",
+		State:       container.StateStopped,
+		ProjectPath: projectPath,
+		Labels:      map[string]string{container.LabelComposeProject: container.SanitizeComposeName(filepath.Base(projectPath))},
+	}}
+	base := startCreateTestServer(t, existing, nil, projectPath)
+
+	// No name: the compose project is derived from the directory, as the
+	// manager's create does, so the conflict is caught before queueing
+	resp := postJSON(t, base+"/api/containers", map[string]any{"project_path": projectPath, "template": "default"})
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("status = %d, want 409 for a project that already has a container", resp.StatusCode)
+	}
+
+	// Another compose project for the same directory (e.g. a worktree name) does not collide
+	enqueueCreate(t, base, map[string]any{"project_path": projectPath, "template": "default", "name": "other"})
+}
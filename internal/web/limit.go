@@ -0,0 +1,59 @@
+// pattern: Imperative Shell
+
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// buildRetryAfter is the Retry-After (seconds) sent with a 429 from a
+// saturated build limiter; container builds take tens of seconds at least.
+const buildRetryAfter = 10
+
+// buildLimiter caps how many container builds (create, clone, worktree
+// start) the API runs at once, so a misbehaving client cannot start enough
+// compose builds to push the host into swap.
+type buildLimiter struct {
+	slots chan struct{}
+}
+
+// newBuildLimiter returns a limiter allowing max concurrent builds.
+func newBuildLimiter(max int) *buildLimiter {
+	return &buildLimiter{slots: make(chan struct{}, max)}
+}
+
+// wrap runs next while holding a build slot. When every slot is taken the
+// request is rejected at once with 429 and a Retry-After header instead of
+// queueing, so clients see back-pressure rather than hung requests.
+func (l *buildLimiter) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case l.slots <- struct{}{}:
+			defer func() { <-l.slots }()
+			next(w, r)
+		default:
+			w.Header().Set("Retry-After", strconv.Itoa(buildRetryAfter))
+			writeError(w, http.StatusTooManyRequests,
+				fmt.Sprintf("too many container builds in progress (max %d); retry later", cap(l.slots)))
+		}
+	}
+}
+
+// acquire blocks until a build slot is free or ctx is done. Queued jobs use
+// it to wait their turn instead of being rejected.
+func (l *buildLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *buildLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,58 @@
+// pattern: Imperative Shell
+
+package web
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// readOnlyKey is the request context key marking a read-only caller.
+type readOnlyKey struct{}
+
+// isReadOnly reports whether the request context is marked read-only.
+func isReadOnly(ctx context.Context) bool {
+	ro, _ := ctx.Value(readOnlyKey{}).(bool)
+	return ro
+}
+
+// markReadOnly decides each caller's access for web.read_only. The API has
+// no authentication, so, as for restarts, only the local host keeps full
+// access (isLocalRequest: a loopback peer without X-Forwarded-For, or any
+// peer on the Unix socket). Every other request, e.g. one proxied from the
+// tailnet, is marked read-only.
+func markReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLocalRequest(r) {
+			r = r.WithContext(context.WithValue(r.Context(), readOnlyKey{}, true))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// enforceReadOnly rejects, with 403, every /api/ request from a read-only
+// caller that could change state: any method other than GET, HEAD or
+// OPTIONS, and terminal attaches, which are GETs that upgrade to an
+// interactive WebSocket. Listings, SSE, health checks, the SPA and create
+// previews (a POST without side effects) pass.
+func enforceReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadOnly(r.Context()) && strings.HasPrefix(r.URL.Path, "/api/") && mutates(r) {
+			writeError(w, http.StatusForbidden, "the web API is read-only (web.read_only)")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mutates reports whether r could change state.
+func mutates(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return strings.HasSuffix(r.URL.Path, "/terminal") || strings.HasSuffix(r.URL.Path, "/attach")
+	case http.MethodPost:
+		return r.URL.Path != "/api/containers/preview"
+	}
+	return true
+}
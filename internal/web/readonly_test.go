@@ -0,0 +1,141 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"devagent/internal/config"
+	"devagent/internal/container"
+	"devagent/internal/logging"
+	"devagent/internal/web"
+)
+
+// startReadOnlyTestServer serves a read-only API over one running container.
+func startReadOnlyTestServer(t *testing.T) string {
+	t.Helper()
+	runtime := &mutationMockRuntime{containers: []container.Container{
+		{ID: "abc123", Name: "abc-app-1", State: container.StateRunning, ProjectPath: "/home/user/abc", Labels: map[string]string{}},
+	}}
+	mgr := container.NewManager(container.ManagerOptions{Runtime: runtime})
+	if err := mgr.Refresh(context.Background()); err != nil {
+		t.Fatalf("manager.Refresh() error = %v", err)
+	}
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	s := web.New(web.Config{Bind: "127.0.0.1", Port: 0, ReadOnly: true}, mgr, nil, lm, nil)
+	s.SetConfig(config.Config{}, nil)
+	return serveTestServer(t, s)
+}
+
+// remoteRequest sends a request that looks proxied from the tailnet.
+func remoteRequest(t *testing.T, ctx context.Context, method, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest error = %v", err)
+	}
+	req.Header.Set("X-Forwarded-For", "100.64.0.7")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	return resp
+}
+
+func TestReadOnly_RejectsMutations(t *testing.T) {
+	base := startReadOnlyTestServer(t)
+	encoded := "L2hvbWUvdXNlci9hYmM=" // /home/user/abc
+
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/api/containers"},
+		{http.MethodPost, "/api/containers/abc123/sessions"},
+		{http.MethodDelete, "/api/containers/abc123/sessions/main"},
+		{http.MethodPost, "/api/containers/abc123/sessions/main/send"},
+		{http.MethodPost, "/api/containers/abc123/sessions/main/keys"},
+		{http.MethodGet, "/api/containers/abc123/sessions/main/terminal"},
+		{http.MethodGet, "/api/containers/abc123/sessions/main/attach"},
+		{http.MethodPost, "/api/containers/abc123/start"},
+		{http.MethodPost, "/api/containers/abc123/stop"},
+		{http.MethodPost, "/api/containers/abc123/exec"},
+		{http.MethodPost, "/api/containers/abc123/regenerate-certs"},
+		{http.MethodPost, "/api/containers/abc123/allowlist/reload"},
+		{http.MethodPost, "/api/containers/abc123/labels"},
+		{http.MethodDelete, "/api/containers/abc123"},
+		{http.MethodPost, "/api/prune"},
+		{http.MethodPost, "/api/restart"},
+		{http.MethodPost, "/api/projects/clone"},
+		{http.MethodPost, "/api/projects/" + encoded + "/worktrees"},
+		{http.MethodPost, "/api/projects/" + encoded + "/worktrees/prune"},
+		{http.MethodPost, "/api/projects/" + encoded + "/worktrees/feature/start"},
+		{http.MethodDelete, "/api/projects/" + encoded + "/worktrees/feature"},
+		{http.MethodPost, "/api/host/sessions"},
+		{http.MethodDelete, "/api/host/sessions/main"},
+		{http.MethodGet, "/api/host/sessions/main/terminal"},
+	}
+	for _, rt := range routes {
+		resp := remoteRequest(t, context.Background(), rt.method, base+rt.path)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s %s status = %d, want 403", rt.method, rt.path, resp.StatusCode)
+		}
+	}
+
+	// The container must have survived every attempt.
+	resp := remoteRequest(t, context.Background(), http.MethodGet, base+"/api/containers/abc123")
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET container after mutations status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestReadOnly_AllowsReads(t *testing.T) {
+	base := startReadOnlyTestServer(t)
+
+	for _, path := range []string{"/api/containers", "/api/containers/abc123", "/api/operations", "/api/audit", "/api/health", "/healthz", "/readyz"} {
+		resp := remoteRequest(t, context.Background(), http.MethodGet, base+path)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s status = %d, want 200", path, resp.StatusCode)
+		}
+	}
+
+	// A create preview is a POST without side effects.
+	resp := remoteRequest(t, context.Background(), http.MethodPost, base+"/api/containers/preview")
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusForbidden {
+		t.Error("POST /api/containers/preview status = 403, want it allowed")
+	}
+
+	resp = remoteRequest(t, context.Background(), http.MethodGet, base+"/api/config")
+	var cfg web.ConfigResponse
+	err := json.NewDecoder(resp.Body).Decode(&cfg)
+	_ = resp.Body.Close()
+	if err != nil || !cfg.ReadOnly {
+		t.Errorf("GET /api/config = %+v (decode error %v), want read_only", cfg, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	resp = remoteRequest(t, ctx, http.MethodGet, base+"/api/events")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("GET /api/events status = %d, content type %q, want an event stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	cancel()
+	_ = resp.Body.Close()
+}
+
+func TestReadOnly_LocalHostKeepsFullAccess(t *testing.T) {
+	base := startReadOnlyTestServer(t)
+
+	resp, err := http.Post(base+"/api/prune", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("local POST /api/prune status = %d, want 200", resp.StatusCode)
+	}
+}
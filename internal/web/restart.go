@@ -0,0 +1,52 @@
+// pattern: Imperative Shell
+package web
+
+import (
+	"net"
+	"net/http"
+)
+
+// SetRestartFunc sets the function POST /api/restart calls to restart the
+// instance. It is called in its own goroutine after the response is written,
+// so it may shut the server down. Without one, the endpoint returns 503.
+func (s *Server) SetRestartFunc(fn func()) {
+	s.restart = fn
+}
+
+// handleRestart handles POST /api/restart.
+// The API has no authentication, so restarts are only accepted from the
+// local host (see isLocalRequest). Returns 202 once the restart
+// is scheduled, 403 for a non-local request, 503 if restart is unavailable.
+func (s *Server) handleRestart(w http.ResponseWriter, r *http.Request) {
+	if !isLocalRequest(r) {
+		writeError(w, http.StatusForbidden, "restart is only allowed from the local host")
+		return
+	}
+	if s.restart == nil {
+		writeError(w, http.StatusServiceUnavailable, "restart is not available")
+		return
+	}
+
+	s.logger.Info("restart requested via API", "remote", r.RemoteAddr)
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "restarting"})
+	go s.restart()
+}
+
+// isLocalRequest reports whether r comes from the local host: any peer on the
+// Unix socket (web.socket; mode 0600 limits it to the owner, and its
+// RemoteAddr is not an address), or a loopback peer with no X-Forwarded-For
+// header (requests proxied from the tailnet by tsnsrv carry one).
+func isLocalRequest(r *http.Request) bool {
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok && addr.Network() == "unix" {
+		return true
+	}
+	if r.Header.Get("X-Forwarded-For") != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -4,14 +4,19 @@ package web
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"devagent/internal/audit"
+	"devagent/internal/config"
 	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/logging"
@@ -19,32 +24,44 @@ import (
 )
 
 // worktreeOps abstracts worktree package functions for testability.
+// Name validation is not part of it: handlers call worktree.ValidateName
+// directly so the real rules apply even with a mock.
 type worktreeOps interface {
-	ValidateName(name string) error
-	Create(projectPath, name string) (string, error)
-	Destroy(projectPath, name string) error
+	Create(projectPath, name, base string) (string, error)
+	ChangedFiles(projectPath, name string) ([]string, error)
+	Destroy(projectPath, name string, force bool) error
 	WorktreeDir(projectPath, name string) string
+	List(projectPath string) ([]worktree.Info, error)
+	Prune(projectPath string) error
 }
 
 // realWorktreeOps delegates to the worktree package functions.
 type realWorktreeOps struct{}
 
-func (realWorktreeOps) ValidateName(name string) error {
-	return worktree.ValidateName(name)
+func (realWorktreeOps) Create(projectPath, name, base string) (string, error) {
+	return worktree.Create(projectPath, name, base)
 }
 
-func (realWorktreeOps) Create(projectPath, name string) (string, error) {
-	return worktree.Create(projectPath, name)
+func (realWorktreeOps) ChangedFiles(projectPath, name string) ([]string, error) {
+	return worktree.ChangedFiles(projectPath, name)
 }
 
-func (realWorktreeOps) Destroy(projectPath, name string) error {
-	return worktree.Destroy(projectPath, name)
+func (realWorktreeOps) Destroy(projectPath, name string, force bool) error {
+	return worktree.Destroy(projectPath, name, force)
 }
 
 func (realWorktreeOps) WorktreeDir(projectPath, name string) string {
 	return worktree.WorktreeDir(projectPath, name)
 }
 
+func (realWorktreeOps) List(projectPath string) ([]worktree.Info, error) {
+	return worktree.List(projectPath)
+}
+
+func (realWorktreeOps) Prune(projectPath string) error {
+	return worktree.Prune(projectPath)
+}
+
 // Server is the web server that serves the API and SPA.
 type Server struct {
 	httpServer  *http.Server
@@ -52,18 +69,56 @@ type Server struct {
 	notifyTUI   func(any)
 	logger      *logging.ScopedLogger
 	addr        string
+	bind        string
+	fallback    bool   // listen on an ephemeral port when addr is in use
+	socket      string // Unix socket path; empty listens on TCP addr
 	listener    net.Listener
 	events      *eventBroker
 	scanner     func(context.Context) []discovery.DiscoveredProject
 	worktreeOps worktreeOps
+	restart     func() // set by SetRestartFunc; nil disables POST /api/restart
+	config      configState
+	startedAt   time.Time
+	audit       *audit.Log
+	jobs        *jobQueue
+	// destroyThreshold is how many containers one request may destroy
+	// without ?confirm=true.
+	destroyThreshold int
 }
 
 // Config holds web server configuration.
 type Config struct {
-	Bind string
-	Port int
+	Bind         string
+	Port         int
+	Compression  bool // gzip API responses for clients that accept it
+	FallbackPort bool // if Port is in use, listen on an ephemeral port instead of failing
+	// AllowedOrigins lists the origins allowed to call /api/ cross-origin
+	// ("*" for any); empty means same-origin only.
+	AllowedOrigins []string
+	// Socket is a Unix socket path to listen on instead of Bind:Port.
+	Socket string
+	// MaxConcurrentBuilds caps concurrent container builds (worktree create,
+	// worktree start, clone); further requests get 429, while queued creates
+	// (POST /api/containers) wait for a slot. Zero or less uses
+	// config.DefaultMaxConcurrentBuilds.
+	MaxConcurrentBuilds int
+	// Audit records lifecycle actions made through the API; nil records
+	// nothing.
+	Audit *audit.Log
+	// ReadOnly rejects every state-changing API request (and terminal
+	// attaches) from anyone but the local host with 403; reads, SSE and
+	// health checks still work.
+	ReadOnly bool
+	// DestroyConfirmThreshold is how many containers one request (e.g. a
+	// prune) may destroy before it must carry ?confirm=true; larger ones get
+	// 412. Zero or less uses config.DefaultDestroyConfirmThreshold.
+	DestroyConfirmThreshold int
 }
 
+// ErrPortInUse is returned (wrapped) by Listen when the configured port is
+// already bound by another process.
+var ErrPortInUse = errors.New("port already in use")
+
 // New creates a web server.
 // notifyTUI is called after mutations to keep the TUI in sync via p.Send().
 // logProvider must implement logging.LoggerProvider (both *logging.Manager and
@@ -81,38 +136,93 @@ func New(cfg Config, manager *container.Manager, notifyTUI func(any), logProvide
 		manager.SetOnChange(events.Notify)
 	}
 
+	maxBuilds := cfg.MaxConcurrentBuilds
+	if maxBuilds <= 0 {
+		maxBuilds = config.DefaultMaxConcurrentBuilds
+	}
+	builds := newBuildLimiter(maxBuilds)
+
+	destroyThreshold := cfg.DestroyConfirmThreshold
+	if destroyThreshold <= 0 {
+		destroyThreshold = config.DefaultDestroyConfirmThreshold
+	}
+
+	var handler http.Handler = mux
+	if cfg.Compression {
+		handler = gzipMiddleware(mux)
+	}
+	if cfg.ReadOnly {
+		handler = markReadOnly(enforceReadOnly(handler))
+	}
+	handler = rejectCrossSite(cfg.AllowedOrigins, handler)
+	if len(cfg.AllowedOrigins) > 0 {
+		handler = corsMiddleware(cfg.AllowedOrigins, handler)
+	}
+
 	s := &Server{
 		httpServer: &http.Server{
 			Addr:              addr,
-			Handler:           mux,
+			Handler:           handler,
 			ReadHeaderTimeout: 10 * time.Second,
 		},
 		manager:     manager,
 		notifyTUI:   notifyTUI,
 		logger:      logger,
 		addr:        addr,
+		bind:        cfg.Bind,
+		fallback:    cfg.FallbackPort,
+		socket:      cfg.Socket,
 		events:      events,
 		scanner:     scanner,
 		worktreeOps: realWorktreeOps{},
+		startedAt:   time.Now(),
+		audit:       cfg.Audit,
+		jobs:        newJobQueue(builds),
+
+		destroyThreshold: destroyThreshold,
 	}
 
 	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
 	mux.HandleFunc("GET /api/events", s.handleEvents)
+	mux.HandleFunc("GET /api/config", s.handleGetConfig)
 	mux.HandleFunc("GET /api/projects", s.handleGetProjects)
 	mux.HandleFunc("GET /api/containers", s.handleListContainers)
+	mux.HandleFunc("POST /api/containers", s.handleCreateContainer)
+	mux.HandleFunc("POST /api/containers/preview", s.handlePreviewContainer)
+	mux.HandleFunc("GET /api/jobs/{id}", s.handleGetJob)
 	mux.HandleFunc("GET /api/containers/{id}", s.handleGetContainer)
+	mux.HandleFunc("GET /api/containers/{id}/snapshot", s.handleGetSnapshot)
+	mux.HandleFunc("GET /api/containers/{id}/inspect", s.handleInspectContainer)
+	mux.HandleFunc("GET /api/audit", s.handleGetAudit)
+	mux.HandleFunc("GET /api/containers/{id}/logs", s.handleContainerLogs)
 	mux.HandleFunc("GET /api/containers/{id}/sessions", s.handleListSessions)
 	mux.HandleFunc("POST /api/containers/{id}/sessions", s.handleCreateSession)
 	mux.HandleFunc("DELETE /api/containers/{id}/sessions/{name}", s.handleDestroySession)
 	mux.HandleFunc("GET /api/containers/{id}/sessions/{name}/capture", s.handleCapturePane)
 	mux.HandleFunc("GET /api/containers/{id}/sessions/{name}/capture-lines", s.handleCaptureLines)
 	mux.HandleFunc("POST /api/containers/{id}/sessions/{name}/send", s.handleSendKeys)
+	mux.HandleFunc("POST /api/containers/{id}/sessions/{name}/keys", s.handleSessionKeys)
 	mux.HandleFunc("GET /api/containers/{id}/sessions/{name}/terminal", s.HandleTerminal)
+	mux.HandleFunc("GET /api/containers/{id}/sessions/{name}/attach", s.HandleTerminal) // alias of /terminal
 	mux.HandleFunc("POST /api/containers/{id}/start", s.handleStartContainer)
 	mux.HandleFunc("POST /api/containers/{id}/stop", s.handleStopContainer)
+	mux.HandleFunc("POST /api/containers/{id}/exec", s.handleExec)
+	mux.HandleFunc("POST /api/containers/{id}/regenerate-certs", s.handleRegenerateProxyCerts)
+	mux.HandleFunc("POST /api/containers/{id}/allowlist/reload", s.handleReloadAllowlist)
+	mux.HandleFunc("POST /api/containers/{id}/labels", s.handleSetLabels)
 	mux.HandleFunc("DELETE /api/containers/{id}", s.handleDestroyContainer)
-	mux.HandleFunc("POST /api/projects/{encodedPath}/worktrees", s.handleCreateWorktree)
-	mux.HandleFunc("POST /api/projects/{encodedPath}/worktrees/{name}/start", s.handleStartWorktreeContainer)
+	mux.HandleFunc("POST /api/prune", s.handlePrune)
+	mux.HandleFunc("POST /api/restart", s.handleRestart)
+	mux.HandleFunc("GET /api/operations", s.handleListOperations)
+	mux.HandleFunc("POST /api/projects/clone", builds.wrap(s.handleCloneProject))
+	mux.HandleFunc("GET /api/projects/{encodedPath}", s.handleGetProject)
+	mux.HandleFunc("GET /api/projects/{encodedPath}/plan", s.handleGetCreatePlan)
+	mux.HandleFunc("GET /api/projects/{encodedPath}/worktrees", s.handleListWorktrees)
+	mux.HandleFunc("POST /api/projects/{encodedPath}/worktrees", builds.wrap(s.handleCreateWorktree))
+	mux.HandleFunc("POST /api/projects/{encodedPath}/worktrees/prune", s.handlePruneWorktrees)
+	mux.HandleFunc("POST /api/projects/{encodedPath}/worktrees/{name}/start", builds.wrap(s.handleStartWorktreeContainer))
 	mux.HandleFunc("DELETE /api/projects/{encodedPath}/worktrees/{name}", s.handleDeleteWorktree)
 	mux.HandleFunc("GET /api/host/sessions", s.handleListHostSessions)
 	mux.HandleFunc("POST /api/host/sessions", s.handleCreateHostSession)
@@ -147,8 +257,24 @@ func (s *Server) spaHandler() http.Handler {
 // Call Serve() after Listen() to start accepting connections.
 // This two-step approach allows callers to obtain the actual bound address
 // (useful for ephemeral port 0 in tests) before the server blocks on Serve().
+// If the port is already in use, the error wraps ErrPortInUse and explains
+// how to resolve it; with Config.FallbackPort, Listen instead logs a warning
+// and binds an ephemeral port. With Config.Socket it listens on that Unix
+// socket instead (see listenUnix).
 func (s *Server) Listen() (net.Listener, error) {
+	if s.socket != "" {
+		return s.listenUnix()
+	}
 	ln, err := net.Listen("tcp", s.addr)
+	if errors.Is(err, syscall.EADDRINUSE) {
+		if !s.fallback {
+			return nil, fmt.Errorf("web server listen: %w: %s is taken, possibly by another devagent instance "+
+				"(run `devagent cleanup` if one crashed) or set web.port to a free port, "+
+				"or web.fallback_port: true to use a random port: %w", ErrPortInUse, s.addr, err)
+		}
+		s.logger.Warn("web port in use, falling back to an ephemeral port", "addr", s.addr)
+		ln, err = net.Listen("tcp", net.JoinHostPort(s.bind, "0"))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("web server listen: %w", err)
 	}
@@ -156,6 +282,65 @@ func (s *Server) Listen() (net.Listener, error) {
 	return ln, nil
 }
 
+// listenUnix listens on the configured socket path with mode 0600, so only
+// the owning user can reach the API. The socket is bound inside a private
+// (0700) directory next to the path, chmodded, then renamed into place, so it
+// is never reachable by others, not even before the chmod, without touching
+// the process umask. A leftover socket from a crashed instance is removed
+// first (the instance lock keeps a live one from being taken over); any other
+// file at the path is an error.
+func (s *Server) listenUnix() (net.Listener, error) {
+	if fi, err := os.Lstat(s.socket); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("web server listen: %s exists and is not a socket", s.socket)
+		}
+		if err := os.Remove(s.socket); err != nil {
+			return nil, fmt.Errorf("web server listen: remove stale socket: %w", err)
+		}
+	}
+	private, err := os.MkdirTemp(filepath.Dir(s.socket), ".sock")
+	if err != nil {
+		return nil, fmt.Errorf("web server listen: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(private) }()
+
+	bound := filepath.Join(private, "s")
+	ln, err := net.Listen("unix", bound)
+	if err != nil {
+		return nil, fmt.Errorf("web server listen: %w", err)
+	}
+	// The socket moves away from bound; unlink it at its final path instead
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(bound, 0600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("web server listen: %w", err)
+	}
+	if err := os.Rename(bound, s.socket); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("web server listen: %w", err)
+	}
+	s.listener = &socketListener{Listener: ln, path: s.socket}
+	return s.listener, nil
+}
+
+// socketListener is a Unix socket listener that was bound at another path
+// and renamed to path: it reports path as its address and removes it on
+// Close.
+type socketListener struct {
+	net.Listener
+	path string
+}
+
+func (l *socketListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
+
+func (l *socketListener) Close() error {
+	err := l.Listener.Close()
+	_ = os.Remove(l.path)
+	return err
+}
+
 // Serve accepts connections on the listener. Blocks until the server stops.
 // Must call Listen() first.
 func (s *Server) Serve(ln net.Listener) error {
@@ -172,18 +357,33 @@ func (s *Server) Start() error {
 	return s.Serve(ln)
 }
 
-// Addr returns the address the server is listening on.
+// Addr returns the address the server is listening on: host:port, or the
+// socket path with Config.Socket.
 // Only valid after Listen() or Start() has been called.
 func (s *Server) Addr() string {
 	if s.listener != nil {
 		return s.listener.Addr().String()
 	}
+	if s.socket != "" {
+		return s.socket
+	}
 	return s.addr
 }
 
-// Shutdown gracefully stops the server.
+// URL returns the server's base URL: "http://host:port", or "unix:<path>"
+// when listening on a socket (the form instance.Discover understands).
+func (s *Server) URL() string {
+	if s.socket != "" {
+		return "unix:" + s.Addr()
+	}
+	return "http://" + s.Addr()
+}
+
+// Shutdown gracefully stops the server. Queued and running create jobs are
+// canceled first, waiting for them to clean up until ctx is done.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("web server shutting down")
+	s.jobs.shutdown(ctx)
 	return s.httpServer.Shutdown(ctx)
 }
 
@@ -193,6 +393,39 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(`{"status":"ok"}`))
 }
 
+// HealthResponse is the JSON body of GET /healthz.
+type HealthResponse struct {
+	Status        string `json:"status"`
+	Runtime       string `json:"runtime"`
+	Containers    int    `json:"containers"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+}
+
+// handleHealthz handles GET /healthz: a liveness check for monitoring and
+// Tailscale service checks. Always 200, even before the first refresh (the
+// container count is then 0).
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{
+		Status:        "ok",
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+	}
+	if s.manager != nil {
+		resp.Runtime = s.manager.RuntimeName()
+		resp.Containers = len(s.manager.List())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleReadyz handles GET /readyz: 200 once the manager has completed a
+// successful container refresh, 503 until then.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.manager == nil || !s.manager.Refreshed() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "not ready"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
 // SetWorktreeOpsForTest replaces the worktreeOps implementation. Test-only.
 func (s *Server) SetWorktreeOpsForTest(ops worktreeOps) {
 	s.worktreeOps = ops
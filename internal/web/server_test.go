@@ -1,11 +1,15 @@
 package web_test
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -274,3 +278,343 @@ func TestServer_BindFailure(t *testing.T) {
 		t.Errorf("Start() error = %q; expected address-in-use or bind error", errStr)
 	}
 }
+
+// occupyPort binds an ephemeral loopback port for the duration of the test
+// and returns the port number.
+func occupyPort(t *testing.T) int {
+	t.Helper()
+	occupier, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not open occupier listener: %v", err)
+	}
+	t.Cleanup(func() { _ = occupier.Close() })
+	return occupier.Addr().(*net.TCPAddr).Port
+}
+
+func TestServer_PortInUse(t *testing.T) {
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+
+	t.Run("explains the conflict", func(t *testing.T) {
+		s := web.New(web.Config{Bind: "127.0.0.1", Port: occupyPort(t)}, nil, nil, lm, nil)
+
+		_, err := s.Listen()
+		if !errors.Is(err, web.ErrPortInUse) {
+			t.Fatalf("Listen() error = %v, want ErrPortInUse", err)
+		}
+		for _, hint := range []string{"devagent cleanup", "web.port", "web.fallback_port"} {
+			if !strings.Contains(err.Error(), hint) {
+				t.Errorf("Listen() error = %q, want it to mention %q", err, hint)
+			}
+		}
+	})
+
+	t.Run("falls back to an ephemeral port", func(t *testing.T) {
+		port := occupyPort(t)
+		s := web.New(web.Config{Bind: "127.0.0.1", Port: port, FallbackPort: true}, nil, nil, lm, nil)
+
+		ln, err := s.Listen()
+		if err != nil {
+			t.Fatalf("Listen() error = %v", err)
+		}
+		defer func() { _ = ln.Close() }()
+
+		if got := ln.Addr().(*net.TCPAddr).Port; got == port || got == 0 {
+			t.Errorf("listening on port %d, want a different ephemeral port than %d", got, port)
+		}
+		if s.Addr() != ln.Addr().String() {
+			t.Errorf("Addr() = %q, want %q", s.Addr(), ln.Addr().String())
+		}
+	})
+}
+
+// shortTempDir returns a temp directory with a path short enough for a Unix
+// socket (t.TempDir paths can exceed the 104/108-byte sun_path limit).
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "da")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+func TestServer_UnixSocket(t *testing.T) {
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	sock := filepath.Join(shortTempDir(t), "web.sock")
+	// A leftover socket file from a crashed instance is replaced
+	stale, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	s := web.New(web.Config{Socket: sock}, nil, nil, lm, nil)
+	ln, err := s.Listen()
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+		<-done
+	})
+
+	if s.Addr() != sock || s.URL() != "unix:"+sock {
+		t.Errorf("Addr() = %q, URL() = %q, want the socket path", s.Addr(), s.URL())
+	}
+	fi, err := os.Stat(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+	// The private directory the socket was bound in is gone
+	if entries, _ := os.ReadDir(filepath.Dir(sock)); len(entries) != 1 {
+		t.Errorf("socket dir has %d entries, want only the socket", len(entries))
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err := client.Get("http://devagent/api/health")
+	if err != nil {
+		t.Fatalf("GET over socket error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestServer_UnixSocketRefusesRegularFile(t *testing.T) {
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	path := filepath.Join(shortTempDir(t), "not-a-socket")
+	if err := os.WriteFile(path, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := web.New(web.Config{Socket: path}, nil, nil, lm, nil).Listen(); err == nil {
+		t.Fatal("Listen() should refuse to replace a regular file")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep me" {
+		t.Error("regular file should be left untouched")
+	}
+}
+
+func startCompressionTestServer(t *testing.T) string {
+	t.Helper()
+	lm := logging.NewTestLogManager(10)
+	t.Cleanup(func() { _ = lm.Close() })
+	s := web.New(web.Config{Bind: "127.0.0.1", Port: 0, Compression: true}, nil, nil, lm, nil)
+
+	ln, err := s.Listen()
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+		<-done
+	})
+	return "http://" + s.Addr()
+}
+
+func TestCompression_GzipsJSONWhenAccepted(t *testing.T) {
+	baseURL := startCompressionTestServer(t)
+	// Disable the transport's transparent decompression to see the raw encoding.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	req, _ := http.NewRequest(http.MethodGet, baseURL+"/api/health", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/health error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if !strings.Contains(string(body), `"status"`) {
+		t.Errorf("decompressed body = %q, want health JSON", body)
+	}
+
+	t.Run("identity when gzip not accepted", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, baseURL+"/api/health", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET /api/health error = %v", err)
+		}
+		defer resp.Body.Close()
+		if got := resp.Header.Get("Content-Encoding"); got != "" {
+			t.Errorf("Content-Encoding = %q, want none", got)
+		}
+	})
+}
+
+func TestCompression_SkipsEventStream(t *testing.T) {
+	baseURL := startCompressionTestServer(t)
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/events error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none for SSE", got)
+	}
+}
+
+// serveTestServer starts s on its listener and returns the base URL.
+func serveTestServer(t *testing.T, s *web.Server) string {
+	t.Helper()
+	ln, err := s.Listen()
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ln)
+	}()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = s.Shutdown(ctx)
+		<-done
+	})
+	return "http://" + s.Addr()
+}
+
+func TestHandleRestart(t *testing.T) {
+	t.Run("schedules restart for local request", func(t *testing.T) {
+		s := newTestServer(t)
+		restarted := make(chan struct{}, 1)
+		s.SetRestartFunc(func() { restarted <- struct{}{} })
+		baseURL := serveTestServer(t, s)
+
+		resp, err := http.Post(baseURL+"/api/restart", "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST /api/restart error = %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+		}
+		select {
+		case <-restarted:
+		case <-time.After(2 * time.Second):
+			t.Fatal("restart func was not called")
+		}
+	})
+
+	t.Run("rejects proxied request", func(t *testing.T) {
+		s := newTestServer(t)
+		called := false
+		s.SetRestartFunc(func() { called = true })
+		baseURL := serveTestServer(t, s)
+
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/api/restart", nil)
+		req.Header.Set("X-Forwarded-For", "100.64.0.7")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /api/restart error = %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+		if called {
+			t.Error("restart func called for proxied request")
+		}
+	})
+
+	t.Run("schedules restart over the unix socket", func(t *testing.T) {
+		lm := logging.NewTestLogManager(10)
+		t.Cleanup(func() { _ = lm.Close() })
+		sock := filepath.Join(shortTempDir(t), "web.sock")
+		s := web.New(web.Config{Socket: sock}, nil, nil, lm, nil)
+		restarted := make(chan struct{}, 1)
+		s.SetRestartFunc(func() { restarted <- struct{}{} })
+		ln, err := s.Listen()
+		if err != nil {
+			t.Fatalf("Listen() error = %v", err)
+		}
+		done := make(chan error, 1)
+		go func() { done <- s.Serve(ln) }()
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			_ = s.Shutdown(ctx)
+			<-done
+		})
+
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+			},
+		}}
+		resp, err := client.Post("http://devagent/api/restart", "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST /api/restart over socket error = %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusAccepted {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+		}
+		select {
+		case <-restarted:
+		case <-time.After(2 * time.Second):
+			t.Fatal("restart func was not called")
+		}
+	})
+
+	t.Run("unavailable without restart func", func(t *testing.T) {
+		baseURL := serveTestServer(t, newTestServer(t))
+
+		resp, err := http.Post(baseURL+"/api/restart", "application/json", nil)
+		if err != nil {
+			t.Fatalf("POST /api/restart error = %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+		}
+	})
+}
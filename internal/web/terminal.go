@@ -5,6 +5,7 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"os/exec"
@@ -70,13 +71,22 @@ func bridgePTYWebSocket(conn *websocket.Conn, ptmx *os.File) {
 }
 
 // HandleTerminal upgrades to websocket and bridges PTY I/O for a tmux session.
+// Served at both .../terminal and .../attach.
 func (s *Server) HandleTerminal(w http.ResponseWriter, r *http.Request) {
 	containerID := r.PathValue("id")
 	sessionName := r.PathValue("name")
 
 	// Validate container exists and is running
-	c, ok := s.manager.GetByNameOrID(containerID)
-	if !ok {
+	c, err := s.manager.ResolveExact(containerID)
+	if errors.Is(err, container.ErrInexactRef) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, container.ErrAmbiguousRef) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
 		http.Error(w, "container not found", http.StatusNotFound)
 		return
 	}
@@ -129,6 +139,7 @@ func (s *Server) HandleTerminal(w http.ResponseWriter, r *http.Request) {
 		c.ID,
 		"tmux", "-u", "attach-session", "-t", sessionName,
 	)
+	cmd.Env = append(os.Environ(), s.manager.RuntimeEnv()...)
 
 	// Start command with PTY
 	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: 24, Cols: 80})
@@ -147,6 +158,10 @@ func (s *Server) HandleTerminal(w http.ResponseWriter, r *http.Request) {
 		_ = cmd.Wait()
 	}()
 
+	// Attaching and detaching both count as container activity
+	s.manager.TouchActivity(c.ID)
+	defer s.manager.TouchActivity(c.ID)
+
 	s.logger.Info("terminal connected",
 		"container", containerID,
 		"session", sessionName,
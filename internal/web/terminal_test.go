@@ -158,3 +158,20 @@ func TestResizeMessage_GH17AC34_LargeTerminal(t *testing.T) {
 		t.Errorf("Rows = %d, want 80", msg.Rows)
 	}
 }
+
+// TestHandleTerminal_AttachAlias verifies /attach is served by the terminal
+// handler (same pre-upgrade validation).
+func TestHandleTerminal_AttachAlias(t *testing.T) {
+	containers := []container.Container{stoppedContainer("abc123")}
+	base := startTerminalTestServer(t, containers, map[string]string{})
+
+	resp, err := http.Get(base + "/api/containers/abc123/sessions/dev/attach")
+	if err != nil {
+		t.Fatalf("GET attach error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
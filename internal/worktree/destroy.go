@@ -5,7 +5,6 @@ package worktree
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 
 	"devagent/internal/container"
 )
@@ -15,19 +14,46 @@ type ContainerOps interface {
 	GetByComposeProject(composeName string) *container.Container
 	StopWithCompose(ctx context.Context, containerID string) error
 	DestroyWithCompose(ctx context.Context, containerID string) error
+	IsProtected(c *container.Container) bool
 }
 
 // WorktreeOps abstracts worktree operations for testability.
 type WorktreeOps interface {
-	Destroy(projectPath, name string) error
+	ChangedFiles(projectPath, name string) ([]string, error)
+	Destroy(projectPath, name string, force bool) error
+}
+
+// DirtyError is returned by DestroyWorktreeWithContainer when the worktree has
+// uncommitted changes and force is not set. Nothing has been removed.
+type DirtyError struct {
+	Name         string
+	ChangedFiles []string
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf("worktree %q has %d uncommitted change(s)", e.Name, len(e.ChangedFiles))
+}
+
+// realWorktreeOps delegates to the package functions.
+type realWorktreeOps struct{}
+
+func (realWorktreeOps) ChangedFiles(projectPath, name string) ([]string, error) {
+	return ChangedFiles(projectPath, name)
+}
+
+func (realWorktreeOps) Destroy(projectPath, name string, force bool) error {
+	return Destroy(projectPath, name, force)
 }
 
 // DestroyWorktreeWithContainer performs a compound operation:
-// 1. Find container for the worktree (by project path + worktree name)
-// 2. If container exists and is running: stop it
-// 3. If container exists: destroy it (compose down)
-// 4. Remove git worktree
+// 1. Unless force: check for uncommitted changes (*DirtyError if any)
+// 2. Find container for the worktree (by project path + worktree name)
+// 3. If container exists and is running: stop it
+// 4. If container exists: destroy it (compose down)
+// 5. Remove git worktree (--force when force is set)
 //
+// A container protected by a do-not-delete note is refused with
+// container.ErrContainerProtected before anything is stopped, force or not.
 // This ensures both TUI and Web use identical semantics for worktree deletion.
 // If wtOps is nil, uses the real worktree package functions.
 func DestroyWorktreeWithContainer(
@@ -36,10 +62,29 @@ func DestroyWorktreeWithContainer(
 	projectPath string,
 	name string,
 	wtOps WorktreeOps,
+	force bool,
 ) error {
+	if wtOps == nil {
+		wtOps = realWorktreeOps{}
+	}
+
+	// Check for uncommitted changes before touching the container
+	if !force {
+		files, err := wtOps.ChangedFiles(projectPath, name)
+		if err != nil {
+			return fmt.Errorf("failed to check worktree status: %w", err)
+		}
+		if len(files) > 0 {
+			return &DirtyError{Name: name, ChangedFiles: files}
+		}
+	}
+
 	// Find container by compose project name
-	composeName := container.SanitizeComposeName(filepath.Base(projectPath) + "-" + name)
+	composeName := ComposeName(projectPath, name)
 	c := containerOps.GetByComposeProject(composeName)
+	if containerOps.IsProtected(c) {
+		return fmt.Errorf("%w: %s", container.ErrContainerProtected, c.Name)
+	}
 	if c != nil {
 		// Stop if running
 		if c.IsRunning() {
@@ -54,8 +99,5 @@ func DestroyWorktreeWithContainer(
 	}
 
 	// Remove git worktree and branch
-	if wtOps != nil {
-		return wtOps.Destroy(projectPath, name)
-	}
-	return Destroy(projectPath, name)
+	return wtOps.Destroy(projectPath, name, force)
 }
@@ -18,6 +18,7 @@ type mockContainerOps struct {
 	destroyCalled         bool
 	destroyContainerID    string
 	getByComposeCalled    string // captured compose name argument
+	protected             bool   // return value for IsProtected
 }
 
 func (m *mockContainerOps) GetByComposeProject(composeName string) *container.Container {
@@ -37,14 +38,25 @@ func (m *mockContainerOps) DestroyWithCompose(ctx context.Context, containerID s
 	return m.destroyWithComposeErr
 }
 
+func (m *mockContainerOps) IsProtected(c *container.Container) bool {
+	return c != nil && m.protected
+}
+
 // mockWorktreeOps is a mock implementation of WorktreeOps for testing.
 type mockWorktreeOps struct {
+	changedFiles  []string
 	destroyCalled bool
+	destroyForce  bool
 	destroyErr    error
 }
 
-func (m *mockWorktreeOps) Destroy(projectPath, name string) error {
+func (m *mockWorktreeOps) ChangedFiles(projectPath, name string) ([]string, error) {
+	return m.changedFiles, nil
+}
+
+func (m *mockWorktreeOps) Destroy(projectPath, name string, force bool) error {
 	m.destroyCalled = true
+	m.destroyForce = force
 	return m.destroyErr
 }
 
@@ -56,7 +68,7 @@ func TestDestroyWorktreeWithContainer_NoContainer(t *testing.T) {
 
 	// Mock the Destroy function to avoid actual git operations
 	// We'll test this by ensuring no stop/destroy is called on containers
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-x", nil)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-x", nil, false)
 
 	// Should fail because Destroy will actually try to run git commands
 	// In real testing, this would be mocked at a lower level
@@ -65,7 +77,7 @@ func TestDestroyWorktreeWithContainer_NoContainer(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-x")
+	expectedComposeName := ComposeName("/home/user/project", "feature-x")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -79,6 +91,23 @@ func TestDestroyWorktreeWithContainer_NoContainer(t *testing.T) {
 	}
 }
 
+func TestDestroyWorktreeWithContainer_Protected(t *testing.T) {
+	containerOps := &mockContainerOps{
+		getByComposeProject: &container.Container{ID: "kept", Name: "kept", State: container.StateRunning},
+		protected:           true,
+	}
+	wtOps := &mockWorktreeOps{}
+
+	err := DestroyWorktreeWithContainer(context.Background(), containerOps, "/home/user/project", "feature-x", wtOps, true)
+	if !errors.Is(err, container.ErrContainerProtected) {
+		t.Fatalf("error = %v, want ErrContainerProtected", err)
+	}
+	if containerOps.stopCalled || containerOps.destroyCalled || wtOps.destroyCalled {
+		t.Errorf("nothing should run: stop %v, destroy %v, worktree destroy %v",
+			containerOps.stopCalled, containerOps.destroyCalled, wtOps.destroyCalled)
+	}
+}
+
 func TestDestroyWorktreeWithContainer_WithRunningContainer(t *testing.T) {
 	ctx := context.Background()
 
@@ -96,7 +125,7 @@ func TestDestroyWorktreeWithContainer_WithRunningContainer(t *testing.T) {
 	// Since DestroyWorktreeWithContainer calls the real Destroy function,
 	// it will fail on git operations. However, we can verify that the
 	// container operations were called in the correct order.
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-x", nil)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-x", nil, false)
 
 	// Expect failure from git operations
 	if err == nil {
@@ -104,7 +133,7 @@ func TestDestroyWorktreeWithContainer_WithRunningContainer(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-x")
+	expectedComposeName := ComposeName("/home/user/project", "feature-x")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -140,7 +169,7 @@ func TestDestroyWorktreeWithContainer_WithStoppedContainer(t *testing.T) {
 		getByComposeProject: stoppedContainer,
 	}
 
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-y", nil)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-y", nil, false)
 
 	// Expect failure from git operations
 	if err == nil {
@@ -148,7 +177,7 @@ func TestDestroyWorktreeWithContainer_WithStoppedContainer(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-y")
+	expectedComposeName := ComposeName("/home/user/project", "feature-y")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -181,7 +210,7 @@ func TestDestroyWorktreeWithContainer_StopError(t *testing.T) {
 		stopWithComposeErr:  errors.New("compose stop failed"),
 	}
 
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-z", nil)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-z", nil, false)
 
 	// Should fail with stop error
 	if err == nil {
@@ -192,7 +221,7 @@ func TestDestroyWorktreeWithContainer_StopError(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-z")
+	expectedComposeName := ComposeName("/home/user/project", "feature-z")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -217,7 +246,7 @@ func TestDestroyWorktreeWithContainer_DestroyError(t *testing.T) {
 		destroyWithComposeErr: errors.New("compose down failed"),
 	}
 
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-w", nil)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-w", nil, false)
 
 	// Should fail with destroy error
 	if err == nil {
@@ -228,7 +257,7 @@ func TestDestroyWorktreeWithContainer_DestroyError(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-w")
+	expectedComposeName := ComposeName("/home/user/project", "feature-w")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -251,7 +280,7 @@ func TestDestroyWorktreeWithContainer_FullSuccess(t *testing.T) {
 	wtOps := &mockWorktreeOps{}
 
 	// Call with mock WorktreeOps
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-full", wtOps)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-full", wtOps, false)
 
 	// Should succeed (no errors from container or worktree ops)
 	if err != nil {
@@ -259,7 +288,7 @@ func TestDestroyWorktreeWithContainer_FullSuccess(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-full")
+	expectedComposeName := ComposeName("/home/user/project", "feature-full")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -297,7 +326,7 @@ func TestDestroyWorktreeWithContainer_WorktreeDestroyError(t *testing.T) {
 	}
 
 	// Call with mock WorktreeOps that returns an error
-	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-err", wtOps)
+	err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-err", wtOps, false)
 
 	// Should fail with the worktree destroy error
 	if err == nil {
@@ -308,7 +337,7 @@ func TestDestroyWorktreeWithContainer_WorktreeDestroyError(t *testing.T) {
 	}
 
 	// Verify GetByComposeProject was called with correct compose name
-	expectedComposeName := container.SanitizeComposeName("project-feature-err")
+	expectedComposeName := ComposeName("/home/user/project", "feature-err")
 	if containerOps.getByComposeCalled != expectedComposeName {
 		t.Errorf("expected GetByComposeProject called with %q, got %q", expectedComposeName, containerOps.getByComposeCalled)
 	}
@@ -326,3 +355,45 @@ func TestDestroyWorktreeWithContainer_WorktreeDestroyError(t *testing.T) {
 		t.Errorf("expected Destroy to be called")
 	}
 }
+
+func TestDestroyWorktreeWithContainer_Dirty(t *testing.T) {
+	ctx := context.Background()
+	runningContainer := &container.Container{
+		ID:             "test-container-dirty",
+		ComposeProject: "project-feature-dirty",
+		State:          container.StateRunning,
+	}
+
+	t.Run("without force", func(t *testing.T) {
+		containerOps := &mockContainerOps{getByComposeProject: runningContainer}
+		wtOps := &mockWorktreeOps{changedFiles: []string{"main.go", "notes.txt"}}
+
+		err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-dirty", wtOps, false)
+
+		var dirty *DirtyError
+		if !errors.As(err, &dirty) {
+			t.Fatalf("expected *DirtyError, got: %v", err)
+		}
+		if len(dirty.ChangedFiles) != 2 || dirty.ChangedFiles[0] != "main.go" {
+			t.Errorf("ChangedFiles = %v, want [main.go notes.txt]", dirty.ChangedFiles)
+		}
+		if containerOps.stopCalled || containerOps.destroyCalled || wtOps.destroyCalled {
+			t.Error("nothing should be stopped or removed for a dirty worktree")
+		}
+	})
+
+	t.Run("with force", func(t *testing.T) {
+		containerOps := &mockContainerOps{getByComposeProject: runningContainer}
+		wtOps := &mockWorktreeOps{changedFiles: []string{"main.go"}}
+
+		if err := DestroyWorktreeWithContainer(ctx, containerOps, "/home/user/project", "feature-dirty", wtOps, true); err != nil {
+			t.Fatalf("expected success, got error: %v", err)
+		}
+		if !containerOps.destroyCalled {
+			t.Error("expected destroyWithCompose to be called")
+		}
+		if !wtOps.destroyCalled || !wtOps.destroyForce {
+			t.Errorf("expected Destroy with force, got called=%v force=%v", wtOps.destroyCalled, wtOps.destroyForce)
+		}
+	})
+}
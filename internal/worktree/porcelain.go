@@ -0,0 +1,97 @@
+// pattern: Functional Core
+
+package worktree
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+)
+
+// Info describes one worktree of a repository as reported by git.
+type Info struct {
+	Name     string // Path relative to the main worktree's .worktrees/ dir, else the directory name
+	Path     string // Absolute worktree path
+	Branch   string // Short branch name; empty for a detached HEAD
+	IsMain   bool   // First entry reported by git
+	Locked   bool   // Locked via `git worktree lock`
+	Prunable bool   // Directory is gone; `git worktree prune` would remove the entry
+}
+
+// ParsePorcelain parses the output of `git worktree list --porcelain`.
+// Entries are separated by blank lines:
+//
+//	worktree /path/to/worktree
+//	HEAD abc123
+//	branch refs/heads/branch-name   (or "detached")
+//	locked [reason]                 (optional)
+//	prunable [reason]               (optional)
+//
+// The first entry is the main worktree. All entries are returned, in order.
+// This is the one parser of that output: discovery uses it too.
+func ParsePorcelain(output string) []Info {
+	var worktrees []Info
+	var current *Info
+
+	flush := func() {
+		if current != nil {
+			worktrees = append(worktrees, *current)
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			flush()
+			current = &Info{
+				Path:   strings.TrimPrefix(line, "worktree "),
+				IsMain: len(worktrees) == 0,
+			}
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "branch "):
+			current.Branch = strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+		case line == "locked" || strings.HasPrefix(line, "locked "):
+			current.Locked = true
+		case line == "prunable" || strings.HasPrefix(line, "prunable "):
+			current.Prunable = true
+		case line == "":
+			flush()
+		}
+	}
+	flush()
+
+	if len(worktrees) > 0 {
+		root := filepath.Join(worktrees[0].Path, ".worktrees")
+		for i := range worktrees {
+			worktrees[i].Name = worktreeName(root, worktrees[i].Path)
+		}
+	}
+	return worktrees
+}
+
+// worktreeName names a worktree by its path relative to root (the main
+// worktree's .worktrees/ dir), matching the name passed to Create. Worktrees
+// outside root are named by their directory.
+func worktreeName(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return filepath.Base(path)
+}
+
+// parseStatusPorcelain returns the paths listed by `git status --porcelain`.
+// Each line is a two-letter status, a space, and the path ("old -> new" for
+// renames, kept as is for display).
+func parseStatusPorcelain(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	return files
+}
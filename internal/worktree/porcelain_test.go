@@ -0,0 +1,83 @@
+package worktree
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	output := `worktree /home/user/project
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /home/user/project/.worktrees/feature/new-model
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature/new-model
+locked
+
+worktree /home/user/project/.worktrees/bisect
+HEAD 3333333333333333333333333333333333333333
+detached
+locked moved to external disk
+
+worktree /tmp/elsewhere
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/hotfix
+
+worktree /home/user/project/.worktrees/gone
+HEAD 5555555555555555555555555555555555555555
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+`
+
+	want := []Info{
+		{Name: "project", Path: "/home/user/project", Branch: "main", IsMain: true},
+		{Name: "feature/new-model", Path: "/home/user/project/.worktrees/feature/new-model", Branch: "feature/new-model", Locked: true},
+		{Name: "bisect", Path: "/home/user/project/.worktrees/bisect", Branch: "", Locked: true},
+		{Name: "elsewhere", Path: "/tmp/elsewhere", Branch: "hotfix"},
+		{Name: "gone", Path: "/home/user/project/.worktrees/gone", Branch: "gone", Prunable: true},
+	}
+
+	got := ParsePorcelain(output)
+	if len(got) != len(want) {
+		t.Fatalf("ParsePorcelain() returned %d worktrees, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("worktree %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParsePorcelain_MainOnly(t *testing.T) {
+	got := ParsePorcelain("worktree /repo\nHEAD abc\nbranch refs/heads/main\n")
+	if len(got) != 1 || !got[0].IsMain || got[0].Branch != "main" {
+		t.Errorf("ParsePorcelain() = %+v, want single main worktree on main", got)
+	}
+}
+
+func TestParsePorcelain_Empty(t *testing.T) {
+	if got := ParsePorcelain(""); len(got) != 0 {
+		t.Errorf("ParsePorcelain(\"\") = %+v, want none", got)
+	}
+}
+
+func TestList_MissingPath(t *testing.T) {
+	_, err := List(filepath.Join(t.TempDir(), "missing"))
+	if !errors.Is(err, ErrNotGitRepo) {
+		t.Errorf("List() error = %v, want ErrNotGitRepo", err)
+	}
+}
+
+func TestParseStatusPorcelain(t *testing.T) {
+	output := " M main.go\n?? notes.txt\nR  old.go -> new.go\n"
+	want := []string{"main.go", "notes.txt", "old.go -> new.go"}
+	if got := parseStatusPorcelain(output); !slices.Equal(got, want) {
+		t.Errorf("parseStatusPorcelain() = %q, want %q", got, want)
+	}
+	if got := parseStatusPorcelain(""); len(got) != 0 {
+		t.Errorf("parseStatusPorcelain(\"\") = %q, want none", got)
+	}
+}
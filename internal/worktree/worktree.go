@@ -3,26 +3,59 @@
 package worktree
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"devagent/internal/container"
 )
 
 // validNameRe matches valid worktree names: alphanumeric, hyphens, underscores, slashes.
 var validNameRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]*$`)
 
-// ValidateName checks if a worktree name is valid.
-// Names must start with an alphanumeric character and contain only
-// alphanumeric, hyphens, underscores, dots, and slashes.
+// maxNameLen bounds worktree names (and so branch names and directories).
+const maxNameLen = 100
+
+// reservedRefNames are names git uses for its own refs; a branch with one of
+// these names makes `git rev-parse <name>` ambiguous.
+var reservedRefNames = map[string]bool{
+	"HEAD":             true,
+	"FETCH_HEAD":       true,
+	"ORIG_HEAD":        true,
+	"MERGE_HEAD":       true,
+	"CHERRY_PICK_HEAD": true,
+	"REVERT_HEAD":      true,
+	"BISECT_HEAD":      true,
+	"AUTO_MERGE":       true,
+}
+
+// NormalizeName returns a worktree name as typed in a form or request, with
+// surrounding whitespace removed. Callers normalize before ValidateName; the
+// result is also the branch name Create uses.
+func NormalizeName(name string) string {
+	return strings.TrimSpace(name)
+}
+
+// ValidateName checks if a worktree name is valid. The name doubles as the
+// branch name and the directory under .worktrees/, so it must satisfy both:
+// at most 100 characters, starting with an ASCII letter or digit and
+// containing only ASCII letters, digits, '.', '_', '/' and '-' (so no leading
+// dash and no unicode); no '..' (path traversal), empty or dot-leading path
+// components, or components ending in ".lock"; no trailing '/' or '.'; and
+// not a ref git reserves (HEAD, FETCH_HEAD, ...). The TUI worktree form and
+// the web API both call it before running git.
 func ValidateName(name string) error {
 	if name == "" {
 		return fmt.Errorf("worktree name cannot be empty")
 	}
-	if len(name) > 100 {
-		return fmt.Errorf("worktree name too long (max 100 characters)")
+	if len(name) > maxNameLen {
+		return fmt.Errorf("worktree name too long (max %d characters)", maxNameLen)
 	}
 	if !validNameRe.MatchString(name) {
 		return fmt.Errorf("invalid worktree name %q: must start with alphanumeric, may contain a-z A-Z 0-9 . _ / -", name)
@@ -31,26 +64,96 @@ func ValidateName(name string) error {
 	if strings.Contains(name, "..") {
 		return fmt.Errorf("worktree name cannot contain '..'")
 	}
+	if strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid worktree name %q: cannot end with '/' or '.'", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		switch {
+		case part == "":
+			return fmt.Errorf("invalid worktree name %q: cannot contain '//'", name)
+		case strings.HasPrefix(part, "."):
+			return fmt.Errorf("invalid worktree name %q: path components cannot start with '.'", name)
+		case strings.HasSuffix(part, ".lock"):
+			return fmt.Errorf("invalid worktree name %q: path components cannot end with '.lock'", name)
+		}
+	}
+	if reservedRefNames[name] {
+		return fmt.Errorf("invalid worktree name %q: reserved by git", name)
+	}
 	return nil
 }
 
+// ErrNotGitRepo is returned by List and Prune when the project path is not a git repository.
+var ErrNotGitRepo = errors.New("not a git repository")
+
+// ErrUnknownBaseRef is returned by Create and VerifyRef when the base ref does not resolve to a commit.
+var ErrUnknownBaseRef = errors.New("unknown base ref")
+
 // WorktreeDir returns the path where a worktree would be created.
-// Worktrees are stored in <project>/.worktrees/<name>/
+// Worktrees are stored in <project>/.worktrees/<name>/; a slash-style name
+// such as "feature/login" (also the branch name) becomes nested directories,
+// mirroring git's ref namespace so two valid branch names never share a
+// directory.
 func WorktreeDir(projectPath, name string) string {
 	return filepath.Join(projectPath, ".worktrees", name)
 }
 
+// ProjectPathFromDir is the inverse of WorktreeDir: the project root of the
+// worktree directory wtDir created for name.
+func ProjectPathFromDir(wtDir, name string) string {
+	path := filepath.Clean(wtDir)
+	// One level per name component, plus .worktrees itself
+	for range strings.Count(name, "/") + 2 {
+		path = filepath.Dir(path)
+	}
+	return path
+}
+
+// composeHashLen is the number of hex characters of the worktree directory
+// hash in ComposeName.
+const composeHashLen = 8
+
+// ComposeName returns the compose project name of the container for the
+// worktree name of the project at projectPath: the sanitized
+// "<project base>-<name>-<hash>", so "feature/login" yields
+// "<project>-feature-login-<hash>". The hash is of the worktree directory
+// (WorktreeDir), since sanitizing alone maps "feature/login" and
+// "feature-login", or same-named worktrees of two projects with the same
+// base name, to one compose project.
+func ComposeName(projectPath, name string) string {
+	sum := sha256.Sum256([]byte(WorktreeDir(projectPath, name)))
+	return container.SanitizeComposeName(filepath.Base(projectPath) + "-" + name + "-" + hex.EncodeToString(sum[:])[:composeHashLen])
+}
+
+// VerifyRef checks that ref resolves to a commit in the repository at projectPath
+// using `git rev-parse --verify`. Returns ErrUnknownBaseRef otherwise.
+func VerifyRef(projectPath, ref string) error {
+	// "--end-of-options" keeps a ref starting with "-" from being read as a flag
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	cmd.Dir = projectPath
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w %q", ErrUnknownBaseRef, ref)
+	}
+	return nil
+}
+
 // Create creates a new git worktree with a feature branch.
 // Steps:
-// 1. Validate name
-// 2. git worktree add .worktrees/<name> -b <name>
+// 1. Validate name (and base, if given)
+// 2. git worktree add .worktrees/<name> -b <name> [<base>]
 // 3. Run make worktree-prep if Makefile exists
 //
+// The branch starts at base, or at the current HEAD when base is empty.
 // Returns the path to the created worktree directory.
-func Create(projectPath, name string) (string, error) {
+func Create(projectPath, name, base string) (string, error) {
 	if err := ValidateName(name); err != nil {
 		return "", err
 	}
+	if base != "" {
+		if err := VerifyRef(projectPath, base); err != nil {
+			return "", err
+		}
+	}
 
 	wtDir := WorktreeDir(projectPath, name)
 
@@ -65,7 +168,11 @@ func Create(projectPath, name string) (string, error) {
 	}
 
 	// Create git worktree with a new branch
-	cmd := exec.Command("git", "worktree", "add", wtDir, "-b", name)
+	args := []string{"worktree", "add", wtDir, "-b", name}
+	if base != "" {
+		args = append(args, base)
+	}
+	cmd := exec.Command("git", args...)
 	cmd.Dir = projectPath
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("git worktree add: %s: %w", strings.TrimSpace(string(output)), err)
@@ -87,16 +194,21 @@ func Create(projectPath, name string) (string, error) {
 // Destroy removes a worktree and its branch.
 // Steps:
 // 1. docker compose down (caller's responsibility — we just do git cleanup)
-// 2. git worktree remove (without --force, refuses if dirty)
+// 2. git worktree remove (without --force unless force, refuses if dirty)
 // 3. git branch -d (without -D, refuses if unmerged)
-func Destroy(projectPath, name string) error {
+//
+// force discards uncommitted changes in the worktree; it does not delete an
+// unmerged branch.
+func Destroy(projectPath, name string, force bool) error {
 	wtDir := WorktreeDir(projectPath, name)
 
 	// Remove the git worktree (non-force: refuses if dirty)
-	if err := removeWorktree(projectPath, wtDir); err != nil {
+	if err := removeWorktree(projectPath, wtDir, force); err != nil {
 		return err
 	}
 
+	removeEmptyParents(projectPath, wtDir)
+
 	// Delete the branch (non-force: refuses if unmerged)
 	cmd := exec.Command("git", "branch", "-d", name)
 	cmd.Dir = projectPath
@@ -107,12 +219,92 @@ func Destroy(projectPath, name string) error {
 	return nil
 }
 
+// removeEmptyParents removes the directories a slash-style name left between
+// wtDir and <project>/.worktrees once they are empty. Best effort: os.Remove
+// refuses non-empty directories, which ends the walk.
+func removeEmptyParents(projectPath, wtDir string) {
+	root := filepath.Join(projectPath, ".worktrees")
+	for dir := filepath.Dir(wtDir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+	}
+}
+
 // removeWorktree calls git worktree remove for cleanup.
-func removeWorktree(projectPath, wtDir string) error {
-	cmd := exec.Command("git", "worktree", "remove", wtDir)
+func removeWorktree(projectPath, wtDir string, force bool) error {
+	args := []string{"worktree", "remove", wtDir}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := exec.Command("git", args...)
 	cmd.Dir = projectPath
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("git worktree remove: %s: %w", strings.TrimSpace(string(output)), err)
 	}
 	return nil
 }
+
+// ChangedFiles lists the uncommitted changes (modified, staged and untracked
+// files) in a worktree, by running `git status --porcelain`. A worktree whose
+// directory does not exist has no changes.
+func ChangedFiles(projectPath, name string) ([]string, error) {
+	wtDir := WorktreeDir(projectPath, name)
+	if info, err := os.Stat(wtDir); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = wtDir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return parseStatusPorcelain(string(output)), nil
+}
+
+// Prune removes the administrative entries of worktrees whose directories no
+// longer exist, by running `git worktree prune`. Locked worktrees are kept.
+// Returns ErrNotGitRepo if projectPath does not exist or is not inside a git
+// repository.
+func Prune(projectPath string) error {
+	if info, err := os.Stat(projectPath); err != nil || !info.IsDir() {
+		return ErrNotGitRepo
+	}
+
+	cmd := exec.Command("git", "worktree", "prune")
+	cmd.Dir = projectPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(output), "not a git repository") {
+			return ErrNotGitRepo
+		}
+		return fmt.Errorf("git worktree prune: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// List returns all worktrees of the repository at projectPath, main worktree
+// first, by running `git worktree list --porcelain`. Works for any repository,
+// not only those found by discovery. Returns ErrNotGitRepo if projectPath does
+// not exist or is not inside a git repository.
+func List(projectPath string) ([]Info, error) {
+	if info, err := os.Stat(projectPath); err != nil || !info.IsDir() {
+		return nil, ErrNotGitRepo
+	}
+
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = projectPath
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			return nil, ErrNotGitRepo
+		}
+		return nil, fmt.Errorf("git worktree list: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	return ParsePorcelain(string(output)), nil
+}
@@ -1,6 +1,10 @@
 package worktree
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -21,6 +25,25 @@ func TestValidateName(t *testing.T) {
 		{"has spaces", true},             // spaces
 		{"has..dots", true},              // path traversal
 		{"../escape", true},              // path traversal
+		{strings.Repeat("a", 100), false},
+		{"feature/login", false},     // slash-style branch
+		{"user/feature/deep", false}, // nested slashes
+		{"feature/", true},           // trailing slash
+		{"feature//login", true},     // empty component
+		{"feature/.hidden", true},    // dot-leading component
+		{"feature.", true},           // trailing dot
+		{"feature.lock", true},       // git lock file suffix
+		{"feature/x.lock/y", true},   // .lock component
+		{"HEAD", true},               // reserved ref
+		{"FETCH_HEAD", true},         // reserved ref
+		{"head", false},              // refs are case-sensitive
+		{"feature@{1}", true},        // reflog syntax
+		{"fix~1", true},              // revision syntax
+		{"fix^", true},               // revision syntax
+		{"topic:x", true},            // refspec separator
+		{"fëature", true},            // unicode
+		{"功能", true},                 // unicode
+		{"feature\\login", true},     // backslash
 	}
 
 	for _, tt := range tests {
@@ -40,3 +63,203 @@ func TestWorktreeDir(t *testing.T) {
 		t.Errorf("WorktreeDir = %q, want %q", dir, expected)
 	}
 }
+
+// initTestRepo creates a git repository with two commits and a "base" tag on
+// the first. Returns the repo path and the first commit's hash.
+func initTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %s: %v", args, out, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-q")
+	run("commit", "-q", "--allow-empty", "-m", "first")
+	first := run("rev-parse", "HEAD")
+	run("tag", "base")
+	run("commit", "-q", "--allow-empty", "-m", "second")
+	return dir, first
+}
+
+func TestCreate_FromBaseRef(t *testing.T) {
+	repo, first := initTestRepo(t)
+
+	wtDir, err := Create(repo, "feature-x", "base")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", wtDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("rev-parse in worktree: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != first {
+		t.Errorf("worktree HEAD = %s, want base commit %s", got, first)
+	}
+}
+
+func TestCreate_UnknownBaseRef(t *testing.T) {
+	repo, _ := initTestRepo(t)
+
+	_, err := Create(repo, "feature-x", "no-such-ref")
+	if !errors.Is(err, ErrUnknownBaseRef) {
+		t.Fatalf("Create() error = %v, want ErrUnknownBaseRef", err)
+	}
+	if _, statErr := os.Stat(WorktreeDir(repo, "feature-x")); !os.IsNotExist(statErr) {
+		t.Error("worktree directory should not be created for an unknown base ref")
+	}
+}
+
+func TestVerifyRef(t *testing.T) {
+	repo, first := initTestRepo(t)
+
+	for _, ref := range []string{"HEAD", "base", first[:8]} {
+		if err := VerifyRef(repo, ref); err != nil {
+			t.Errorf("VerifyRef(%q) error = %v", ref, err)
+		}
+	}
+	for _, ref := range []string{"missing", "-h", "--all"} {
+		if err := VerifyRef(repo, ref); !errors.Is(err, ErrUnknownBaseRef) {
+			t.Errorf("VerifyRef(%q) error = %v, want ErrUnknownBaseRef", ref, err)
+		}
+	}
+}
+
+func TestPrune_RemovesMissingWorktrees(t *testing.T) {
+	repo, _ := initTestRepo(t)
+
+	wtDir, err := Create(repo, "gone", "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := os.RemoveAll(wtDir); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := List(repo)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(infos) != 2 || !infos[1].Prunable {
+		t.Fatalf("List() before prune = %+v, want a prunable worktree", infos)
+	}
+
+	if err := Prune(repo); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	infos, err = List(repo)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(infos) != 1 {
+		t.Errorf("List() after prune = %+v, want only the main worktree", infos)
+	}
+}
+
+func TestPrune_NotGitRepo(t *testing.T) {
+	if err := Prune(t.TempDir()); !errors.Is(err, ErrNotGitRepo) {
+		t.Errorf("Prune() error = %v, want ErrNotGitRepo", err)
+	}
+}
+
+func TestChangedFilesAndForceDestroy(t *testing.T) {
+	repo, _ := initTestRepo(t)
+
+	wtDir, err := Create(repo, "dirty", "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	files, err := ChangedFiles(repo, "dirty")
+	if err != nil || len(files) != 0 {
+		t.Fatalf("ChangedFiles() on a clean worktree = %v, %v; want none", files, err)
+	}
+
+	if err := os.WriteFile(wtDir+"/scratch.txt", []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, err = ChangedFiles(repo, "dirty")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "scratch.txt" {
+		t.Errorf("ChangedFiles() = %v, want [scratch.txt]", files)
+	}
+
+	if err := Destroy(repo, "dirty", false); err == nil {
+		t.Fatal("Destroy() without force should refuse a dirty worktree")
+	}
+	if err := Destroy(repo, "dirty", true); err != nil {
+		t.Fatalf("Destroy() with force error = %v", err)
+	}
+	if _, err := os.Stat(wtDir); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	if got := NormalizeName("  feature/login\n"); got != "feature/login" {
+		t.Errorf("NormalizeName() = %q, want %q", got, "feature/login")
+	}
+}
+
+func TestSlashNamePaths(t *testing.T) {
+	project := "/home/user/My Project"
+	for _, name := range []string{"feature-x", "feature/login", "team/alice/fix"} {
+		if got := ProjectPathFromDir(WorktreeDir(project, name), name); got != project {
+			t.Errorf("ProjectPathFromDir(WorktreeDir(%q)) = %q, want %q", name, got, project)
+		}
+	}
+	if got := ComposeName(project, "feature/login"); !strings.HasPrefix(got, "my-project-feature-login-") {
+		t.Errorf("ComposeName() = %q, want prefix %q", got, "my-project-feature-login-")
+	}
+	if got := ComposeName(project, "feature/login"); got != ComposeName(project, "feature/login") {
+		t.Errorf("ComposeName() = %q, want it stable", got)
+	}
+	// Names that sanitize alike, or same-named projects elsewhere, differ
+	names := map[string]bool{}
+	for _, pair := range [][2]string{
+		{project, "feature/login"},
+		{project, "feature-login"},
+		{"/srv/My Project", "feature/login"},
+	} {
+		names[ComposeName(pair[0], pair[1])] = true
+	}
+	if len(names) != 3 {
+		t.Errorf("ComposeName() collided: %v", names)
+	}
+}
+
+func TestCreateAndDestroy_SlashName(t *testing.T) {
+	repo, _ := initTestRepo(t)
+
+	wtDir, err := Create(repo, "feature/login", "")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if want := filepath.Join(repo, ".worktrees", "feature", "login"); wtDir != want {
+		t.Errorf("Create() dir = %q, want %q", wtDir, want)
+	}
+	if err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "refs/heads/feature/login").Run(); err != nil {
+		t.Errorf("branch feature/login should exist: %v", err)
+	}
+
+	if err := Destroy(repo, "feature/login", false); err != nil {
+		t.Fatalf("Destroy() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".worktrees", "feature")); !os.IsNotExist(err) {
+		t.Error("empty .worktrees/feature parent should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".worktrees")); err != nil {
+		t.Errorf(".worktrees itself should be kept: %v", err)
+	}
+}
@@ -7,13 +7,17 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	flag "github.com/spf13/pflag"
 
+	"devagent/internal/audit"
 	"devagent/internal/cli"
 	"devagent/internal/config"
+	"devagent/internal/container"
 	"devagent/internal/discovery"
 	"devagent/internal/events"
 	"devagent/internal/instance"
@@ -24,7 +28,12 @@ import (
 	"devagent/internal/web"
 )
 
-var version = "dev"
+// Build metadata, injected via ldflags (see Makefile).
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
 
 func main() {
 	// Stop parsing flags after the first non-flag arg (the subcommand),
@@ -32,18 +41,29 @@ func main() {
 	flag.CommandLine.SetInterspersed(false)
 
 	configDir := flag.StringP("config-dir", "c", "", "config directory (default: ~/.config/devagent)")
+	configFile := flag.String("config", "", "config file, read instead of <config-dir>/config.yaml (config dir defaults to the file's directory)")
 	agentHelp := flag.Bool("agent-help", false, "print agent orchestration guide")
+	showVersion := flag.BoolP("version", "v", false, "print version and build metadata (same as the version command)")
+	force := flag.Bool("force", false, "take over the lock of a previous instance that no longer responds")
+
+	buildInfo := cli.NewBuildInfo(version, commit, date)
 
 	// Override flag.Usage before Parse so --help uses the CLI app's help
 	flag.Usage = func() {
-		app := cli.BuildApp(version, *configDir)
+		app := cli.BuildApp(buildInfo, *configDir, *configFile)
 		app.PrintHelp(os.Stderr)
 		flag.PrintDefaults()
 	}
 
 	flag.Parse()
 
-	app := cli.BuildApp(version, *configDir)
+	*configDir = resolveConfigDir(*configDir, *configFile)
+	app := cli.BuildApp(buildInfo, *configDir, *configFile)
+
+	if *showVersion {
+		app.Execute([]string{"version"})
+		return
+	}
 
 	if *agentHelp {
 		app.PrintAgentHelp(os.Stdout)
@@ -51,16 +71,95 @@ func main() {
 	}
 
 	if app.Execute(flag.Args()) {
-		runTUI(*configDir)
+		if runTUI(*configDir, *configFile, *force) {
+			reexec(*configDir, *configFile)
+		}
 	}
 }
 
-// loadConfig loads the configuration from the specified directory or default location.
-func loadConfig(configDir string) (config.Config, error) {
+// restartArgv returns the argument vector a restarted instance is exec'd
+// with: the original program name plus the --config-dir and --config it was
+// started with, so the new process resolves the same config and data dir.
+func restartArgv(argv0, configDir, configFile string) []string {
+	argv := []string{argv0}
 	if configDir != "" {
-		return config.LoadFromDir(configDir)
+		argv = append(argv, "--config-dir", configDir)
+	}
+	if configFile != "" {
+		argv = append(argv, "--config", configFile)
+	}
+	return argv
+}
+
+// reexec replaces the process with a fresh instance of the current
+// executable. runTUI has already returned, so the lock is released and the
+// port file removed; the new process reacquires both.
+func reexec(configDir, configFile string) {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: restart failed: %v\n", err)
+		os.Exit(1)
+	}
+	if err := syscall.Exec(exe, restartArgv(os.Args[0], configDir, configFile), os.Environ()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: restart failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// resolveConfigDir returns the config directory (data dir for the lock and
+// port files, templates) for the given --config-dir and --config flags: an
+// explicit --config-dir always, otherwise the --config file's directory, or
+// "" for the default profile.
+func resolveConfigDir(configDir, configFile string) string {
+	if configDir != "" || configFile == "" {
+		return configDir
+	}
+	if abs, err := filepath.Abs(configFile); err == nil {
+		configFile = abs
+	}
+	return filepath.Dir(configFile)
+}
+
+// logManagerConfig builds the log manager settings from the user's logging
+// config; the log file defaults to orchestrator.log in dataDir.
+func logManagerConfig(cfg config.Config, dataDir string) logging.Config {
+	return logging.Config{
+		FilePath:       cfg.ResolveLogPath(dataDir),
+		MaxSizeMB:      cfg.Logging.MaxSizeMB,
+		MaxBackups:     cfg.Logging.MaxBackups,
+		MaxAgeDays:     cfg.Logging.MaxAgeDays,
+		ChannelBufSize: 1000,
+		Level:          cfg.LogLevel,
+		Format:         cfg.LogFormat,
+	}
+}
+
+// warnScanPathOverlaps logs scan paths that contain devagent's own config/data
+// directories or overlap each other. Logged rather than printed because the
+// TUI's alt screen hides stderr.
+func warnScanPathOverlaps(cfg config.Config, configDir string, logger *logging.ScopedLogger) {
+	dirs := []string{cli.ResolveDataDir(configDir)}
+	if configDir == "" {
+		dirs = append(dirs, config.DefaultConfigDir())
+	}
+	for _, w := range config.ScanPathWarnings(cfg.ResolveScanPaths(), dirs) {
+		logger.Warn("scan path overlap", "warning", w)
+	}
+}
+
+// warnInvalidTemplates logs every problem found in the templates directory.
+// A bad template is skipped or fails at create time; it never blocks startup.
+func warnInvalidTemplates(logger *logging.ScopedLogger) {
+	for _, w := range config.TemplateWarnings() {
+		logger.Warn("invalid template", "warning", w)
+	}
+}
+
+// warnUnknownTheme logs a theme that config loading replaced with the default.
+func warnUnknownTheme(cfg config.Config, logger *logging.ScopedLogger) {
+	if cfg.UnknownTheme != "" {
+		logger.Warn("unknown theme, using default", "theme", cfg.UnknownTheme, "default", config.DefaultTheme, "themes", config.Themes)
 	}
-	return config.Load()
 }
 
 // provisionDefaultProfile seeds config.yaml and materializes the embedded
@@ -95,16 +194,19 @@ func provisionDefaultProfile() {
 	}
 }
 
-// runTUI launches the interactive TUI.
-func runTUI(configDir string) {
+// runTUI launches the interactive TUI. It reports whether the instance was
+// asked to restart (POST /api/restart) rather than quit. configFile, when
+// set, is read instead of configDir's config.yaml. With force, a lock held
+// by an unresponsive previous instance is taken over (instance.ForceLock).
+func runTUI(configDir, configFile string, force bool) bool {
 	// Materialize embedded defaults into the user profile. Only the default
-	// profile is provisioned; an explicit --config-dir (e.g. `make dev`) is the
-	// user's own and is left untouched.
+	// profile is provisioned; an explicit --config-dir or --config (e.g.
+	// `make dev`) is the user's own and is left untouched.
 	if configDir == "" {
 		provisionDefaultProfile()
 	}
 
-	cfg, err := loadConfig(configDir)
+	cfg, err := cli.LoadConfig(configDir, configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
 	}
@@ -117,23 +219,20 @@ func runTUI(configDir string) {
 	dataDir := cli.ResolveDataDir(configDir)
 
 	// Acquire single-instance lock
-	fl, err := instance.Lock(dataDir)
+	lock := instance.Lock
+	if force {
+		lock = instance.ForceLock
+	}
+	fl, err := lock(dataDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 	defer instance.Cleanup(dataDir, fl)
+	// Holding the lock, any port file left is from a run that crashed.
+	staleFiles := instance.RemoveStale(dataDir)
 
-	logPath := filepath.Join(dataDir, "orchestrator.log")
-
-	logManager, err := logging.NewManager(logging.Config{
-		FilePath:       logPath,
-		MaxSizeMB:      10,
-		MaxBackups:     3,
-		MaxAgeDays:     7,
-		ChannelBufSize: 1000,
-		Level:          cfg.LogLevel,
-	})
+	logManager, err := logging.NewManager(logManagerConfig(cfg, dataDir))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logging: %v\n", err)
 		os.Exit(1)
@@ -142,32 +241,108 @@ func runTUI(configDir string) {
 
 	appLogger := logManager.For("app")
 	appLogger.Info("application starting")
+	if len(staleFiles) > 0 {
+		appLogger.Warn("removed stale files from a previous run", "files", staleFiles)
+	}
+	warnScanPathOverlaps(cfg, configDir, appLogger)
+	warnInvalidTemplates(appLogger)
+	warnUnknownTheme(cfg, appLogger)
 
 	model := tui.NewModel(&cfg, logManager)
-
-	// Start project discovery if scan paths configured and create scanner function for web server
-	var scannerFn func(context.Context) []discovery.DiscoveredProject
-	if len(cfg.ScanPaths) > 0 {
-		scanner := discovery.NewScanner()
-		resolvedPaths := cfg.ResolveScanPaths()
+	model.Manager().LoadNotes(filepath.Join(dataDir, container.NotesFileName))
+	auditLog := audit.Open(filepath.Join(dataDir, audit.FileName))
+	model.SetAuditLog(auditLog)
+
+	// Restore tree expansion/selection from the previous run
+	statePath := tui.StatePath(dataDir)
+	model.RestoreUIState(tui.LoadState(statePath))
+
+	// Start project discovery if scan paths configured. The web server's scanner
+	// reads the resolved paths through an atomic pointer so a config reload
+	// (SIGHUP) can change them.
+	scanner := discovery.NewScanner()
+	scanner.MaxDepth = cfg.ScanMaxDepth
+	resolvedPaths := cfg.ResolveScanPaths()
+	var scanPaths atomic.Pointer[[]string]
+	scanPaths.Store(&resolvedPaths)
+	if len(resolvedPaths) > 0 {
 		projects := scanner.ScanAll(resolvedPaths)
 		appLogger.Info("discovered projects", "count", len(projects), "scan_paths", resolvedPaths)
 		model.SetDiscoveredProjects(projects)
-		scannerFn = func(_ context.Context) []discovery.DiscoveredProject {
-			return scanner.ScanAll(resolvedPaths)
+	}
+	scannerFn := func(_ context.Context) []discovery.DiscoveredProject {
+		paths := *scanPaths.Load()
+		if len(paths) == 0 {
+			return nil
 		}
+		return scanner.ScanAll(paths)
 	}
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	// Push project changes from a filesystem watcher (with its slow fallback
+	// rescan) instead of rescanning on every tick; without a watcher the TUI
+	// keeps polling.
+	var p *tea.Program
+	projectWatcher, err := discovery.NewWatcher(func() []string { return *scanPaths.Load() },
+		cfg.ScanMaxDepth, discovery.DefaultWatchDebounce, func() {
+			p.Send(tui.ProjectsRefreshed(scanner.ScanAllCached(*scanPaths.Load())))
+		})
+	if err != nil {
+		appLogger.Warn("project watcher unavailable, polling scan paths instead", "error", err)
+	} else {
+		model.SetProjectWatch(true)
+	}
+
+	p = tea.NewProgram(model, tea.WithAltScreen())
+
+	if projectWatcher != nil {
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		defer stopWatch()
+		go func() {
+			if err := projectWatcher.Run(watchCtx); err != nil {
+				appLogger.Warn("project watcher stopped, polling scan paths instead", "error", err)
+				p.Send(tui.ProjectWatchStopped())
+			}
+		}()
+	}
+
+	// Re-apply proxy allowlists when an allowlist file (network.allowlist_file
+	// or a template's allowlist_file) is edited
+	allowlistCtx, stopAllowlistWatch := context.WithCancel(context.Background())
+	defer stopAllowlistWatch()
+	go func() {
+		if err := model.Manager().WatchAllowlistFiles(allowlistCtx, container.DefaultAllowlistWatchDebounce); err != nil {
+			appLogger.Warn("allowlist watcher stopped", "error", err)
+		}
+	}()
 
 	// Web server always starts (ephemeral port if not configured)
 	webServer := web.New(
-		web.Config{Bind: cfg.Web.Bind, Port: cfg.Web.Port},
+		web.Config{Bind: cfg.Web.Bind, Port: cfg.Web.Port, Compression: cfg.Web.Compression,
+			FallbackPort: cfg.Web.FallbackPort, AllowedOrigins: cfg.Web.AllowedOrigins,
+			Socket: webSocketPath(cfg), MaxConcurrentBuilds: cfg.Web.MaxConcurrentBuilds,
+			ReadOnly: cfg.Web.ReadOnly, Audit: auditLog,
+			DestroyConfirmThreshold: cfg.Confirm.DestroyConfirmThreshold()},
 		model.Manager(),
 		func(msg any) { p.Send(msg) },
 		logManager,
 		scannerFn,
 	)
+	// GET /api/config reports templates, scan paths and URLs (never secrets)
+	templates, _ := config.LoadTemplates()
+	webServer.SetConfig(cfg, templates)
+
+	// Reload templates, scan paths, theme, and log level on SIGHUP
+	stopReload := watchConfigReload(configDir, configFile, cfg, &scanPaths, projectWatcher, p, webServer, appLogger)
+	defer stopReload()
+
+	// POST /api/restart quits the TUI; main re-execs once the deferred
+	// cleanup (web shutdown, lock release) has run
+	var restartRequested atomic.Bool
+	webServer.SetRestartFunc(func() {
+		appLogger.Info("restarting instance")
+		restartRequested.Store(true)
+		p.Quit()
+	})
 	ln, err := webServer.Listen()
 	if err != nil {
 		appLogger.Error("web server listen error", "error", err)
@@ -175,12 +350,16 @@ func runTUI(configDir string) {
 		os.Exit(1)
 	}
 
-	// Write port file for CLI discovery
-	if err := instance.WritePort(dataDir, webServer.Addr()); err != nil {
+	// Write port file for CLI discovery (host:port, or unix:<path> for a socket)
+	portAddr := webServer.Addr()
+	if cfg.Web.Socket != "" {
+		portAddr = instance.UnixPrefix + portAddr
+	}
+	if err := instance.WritePort(dataDir, portAddr); err != nil {
 		appLogger.Error("failed to write port file", "error", err)
 	}
 
-	webURL := fmt.Sprintf("http://%s", webServer.Addr())
+	webURL := webServer.URL()
 	go func() {
 		p.Send(events.WebListenURLMsg{URL: webURL})
 	}()
@@ -198,14 +377,24 @@ func runTUI(configDir string) {
 		}
 	}()
 
-	// Tailscale only when web port is explicitly configured
-	if cfg.Web.Port > 0 && cfg.Tailscale.Enabled {
+	// Tailscale only when web port is explicitly configured; tsnsrv proxies
+	// to a TCP upstream, so a socket-only web server can't be exposed
+	if cfg.Web.Socket != "" && cfg.Tailscale.Enabled {
+		appLogger.Warn("tailscale is not supported with web.socket (continuing without tailscale)")
+	} else if cfg.Web.Port > 0 && cfg.Tailscale.Enabled {
 		supervisor, err := startTsnsrv(&cfg, webServer.Addr(), logManager)
 		if err != nil {
 			appLogger.Warn("tsnsrv failed to start (continuing without tailscale)", "error", err)
 		} else {
 			defer supervisor.Stop()
 
+			// Show tsnsrv restarts and failures in the TUI header
+			go func() {
+				for st := range supervisor.StatusChanges() {
+					p.Send(events.TailscaleStatusMsg{Status: st.String()})
+				}
+			}()
+
 			// Poll for tailscale FQDN in background
 			stateDir := cfg.ResolveTokenPath(cfg.Tailscale.StateDir)
 			tc := cfg.Tailscale
@@ -214,6 +403,7 @@ func runTUI(configDir string) {
 					url, ok := tsnsrv.ReadServiceURL(stateDir, tc)
 					if ok {
 						appLogger.Info("tailscale URL resolved", "url", url)
+						webServer.SetTailscaleURL(url)
 						p.Send(events.TailscaleURLMsg{URL: url})
 						return
 					}
@@ -222,18 +412,48 @@ func runTUI(configDir string) {
 				// Timed out, send fallback
 				fallback, _ := tsnsrv.ReadServiceURL(stateDir, tc)
 				appLogger.Warn("tailscale URL resolution timed out, using fallback", "url", fallback)
+				webServer.SetTailscaleURL(fallback)
 				p.Send(events.TailscaleURLMsg{URL: fallback})
 			}()
 		}
 	}
 
-	if _, err := p.Run(); err != nil {
+	finalModel, err := p.Run()
+	if err != nil {
 		appLogger.Error("application exited with error", "error", err)
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
 
+	if fm, ok := finalModel.(tui.Model); ok {
+		if err := tui.SaveState(statePath, fm.UIState()); err != nil {
+			appLogger.Warn("failed to save TUI state", "error", err)
+		}
+	}
+
+	// Stop the sidecars this run created (stop_sidecars_on_exit), unless the
+	// instance is restarting: the re-exec'd instance takes over the running
+	// containers, whose proxies must keep running.
+	if restartRequested.Load() {
+		appLogger.Info("restarting, leaving sidecars running")
+	} else {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := model.Manager().Shutdown(shutdownCtx); err != nil {
+			appLogger.Warn("failed to stop sidecars on exit", "error", err)
+		}
+		cancelShutdown()
+	}
+
 	appLogger.Info("application stopped")
+	return restartRequested.Load()
+}
+
+// webSocketPath returns web.socket with ~/ expanded, or "" to listen on TCP.
+func webSocketPath(cfg config.Config) string {
+	if cfg.Web.Socket == "" {
+		return ""
+	}
+	return cfg.ResolveTokenPath(cfg.Web.Socket)
 }
 
 // startTsnsrv validates config, builds the process config, and starts the tsnsrv supervisor.
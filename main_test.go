@@ -3,8 +3,11 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
+	"devagent/internal/config"
 	"devagent/internal/logging"
 )
 
@@ -52,3 +55,206 @@ func TestLogManagerInitialization(t *testing.T) {
 		t.Error("no log entry received on channel")
 	}
 }
+
+func TestLogManagerConfig_UsesLoggingSettings(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "logs", "devagent.log")
+	yaml := "log_level: debug\nlog_format: text\nlogging:\n  path: " + logPath + "\n  max_size_mb: 50\n  max_backups: 9\n  max_age_days: 30\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFrom(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+
+	got := logManagerConfig(cfg, filepath.Join(dir, "data"))
+	want := logging.Config{
+		FilePath:       logPath,
+		MaxSizeMB:      50,
+		MaxBackups:     9,
+		MaxAgeDays:     30,
+		Level:          "debug",
+		Format:         "text",
+		ChannelBufSize: 1000,
+	}
+	if got != want {
+		t.Fatalf("logManagerConfig() = %+v, want %+v", got, want)
+	}
+
+	lm, err := logging.NewManager(got)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	lm.For("app").Info("hello")
+	_ = lm.Close()
+	if _, err := os.Stat(logPath); err != nil {
+		t.Errorf("log file not written at configured path: %v", err)
+	}
+}
+
+func TestLogManagerConfig_DefaultsToDataDir(t *testing.T) {
+	got := logManagerConfig(config.DefaultConfig(), "/data")
+	if got.FilePath != "/data/orchestrator.log" {
+		t.Errorf("FilePath = %q, want /data/orchestrator.log", got.FilePath)
+	}
+	if got.MaxSizeMB != 10 || got.MaxBackups != 3 || got.MaxAgeDays != 7 {
+		t.Errorf("rotation = %d/%d/%d, want 10/3/7", got.MaxSizeMB, got.MaxBackups, got.MaxAgeDays)
+	}
+}
+
+func TestReloadConfig_AppliesLiveFields(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "theme: latte\nlog_level: debug\nscan_paths:\n  - /tmp/projects\nweb:\n  port: 9999\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	current := config.Config{Theme: "mocha", LogLevel: "info", Web: config.WebConfig{Port: 8080}}
+	next, _, err := reloadConfig(dir, "", current, logging.NopLogger())
+	if err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+
+	if next.Theme != "latte" {
+		t.Errorf("Theme = %q, want %q", next.Theme, "latte")
+	}
+	if next.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want %q", next.LogLevel, "debug")
+	}
+	if len(next.ScanPaths) != 1 || next.ScanPaths[0] != "/tmp/projects" {
+		t.Errorf("ScanPaths = %v, want [/tmp/projects]", next.ScanPaths)
+	}
+	// Web settings require a restart and must not change
+	if next.Web.Port != 8080 {
+		t.Errorf("Web.Port = %d, want 8080 (unchanged)", next.Web.Port)
+	}
+}
+
+// TestReloadConfig_AppliesTUISettings verifies that the settings the TUI
+// re-reads on a reload (see tui applyConfigReload) come from the new file.
+func TestReloadConfig_AppliesTUISettings(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "confirm:\n  kill_session: false\n  destroy_threshold: 7\n" +
+		"attach_command_template: \"ssh box {{.Name}}\"\n" +
+		"refresh_interval: 3s\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	next, _, err := reloadConfig(dir, "", config.DefaultConfig(), logging.NopLogger())
+	if err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+
+	if next.Confirm.Requires(config.ConfirmKillSession) || next.Confirm.DestroyThreshold != 7 {
+		t.Errorf("Confirm = %+v, want kill_session off and destroy_threshold 7", next.Confirm)
+	}
+	if next.AttachCommandTemplate != "ssh box {{.Name}}" {
+		t.Errorf("AttachCommandTemplate = %q, want the reloaded template", next.AttachCommandTemplate)
+	}
+	if next.RefreshInterval != 3*time.Second {
+		t.Errorf("RefreshInterval = %v, want 3s", next.RefreshInterval)
+	}
+}
+
+// TestReloadConfig_ReportsUnknownTheme verifies that a reload reports the new
+// file's unknown theme, and stops reporting it once the theme is fixed.
+func TestReloadConfig_ReportsUnknownTheme(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("theme: no-such-theme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next, _, err := reloadConfig(dir, "", config.DefaultConfig(), logging.NopLogger())
+	if err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+	if next.UnknownTheme != "no-such-theme" || next.Theme != config.DefaultTheme {
+		t.Errorf("Theme = %q, UnknownTheme = %q; want %q and no-such-theme", next.Theme, next.UnknownTheme, config.DefaultTheme)
+	}
+
+	if err := os.WriteFile(configPath, []byte("theme: latte\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next, _, err = reloadConfig(dir, "", next, logging.NopLogger())
+	if err != nil {
+		t.Fatalf("reloadConfig() error = %v", err)
+	}
+	if next.UnknownTheme != "" || next.Theme != "latte" {
+		t.Errorf("Theme = %q, UnknownTheme = %q; want latte and none", next.Theme, next.UnknownTheme)
+	}
+}
+
+func TestReloadConfig_KeepsOldConfigOnInvalidRuntime(t *testing.T) {
+	dir := t.TempDir()
+	yaml := "theme: latte\nruntime: bogus\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	current := config.Config{Theme: "mocha"}
+	next, templates, err := reloadConfig(dir, "", current, logging.NopLogger())
+	if err == nil {
+		t.Fatal("reloadConfig() should fail for invalid runtime")
+	}
+	if next.Theme != "mocha" {
+		t.Errorf("Theme = %q, want previous %q", next.Theme, "mocha")
+	}
+	if templates != nil {
+		t.Errorf("templates = %v, want nil on failure", templates)
+	}
+}
+
+func TestResolveConfigDir(t *testing.T) {
+	tests := []struct {
+		name       string
+		configDir  string
+		configFile string
+		want       string
+	}{
+		{name: "default profile", want: ""},
+		{name: "config dir only", configDir: "/tmp/dev-config", want: "/tmp/dev-config"},
+		{name: "config file only", configFile: "/tmp/profiles/staging.yaml", want: "/tmp/profiles"},
+		{name: "config dir wins for the data dir", configDir: "/tmp/dev-config", configFile: "/tmp/profiles/staging.yaml", want: "/tmp/dev-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveConfigDir(tt.configDir, tt.configFile); got != tt.want {
+				t.Errorf("resolveConfigDir(%q, %q) = %q, want %q", tt.configDir, tt.configFile, got, tt.want)
+			}
+		})
+	}
+
+	// A relative file resolves against the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveConfigDir("", "staging.yaml"); got != wd {
+		t.Errorf("resolveConfigDir(\"\", staging.yaml) = %q, want %q", got, wd)
+	}
+}
+
+func TestRestartArgv(t *testing.T) {
+	tests := []struct {
+		name       string
+		argv0      string
+		configDir  string
+		configFile string
+		want       []string
+	}{
+		{name: "default profile", argv0: "devagent", want: []string{"devagent"}},
+		{name: "explicit config dir", argv0: "/usr/local/bin/devagent", configDir: "/tmp/dev-config",
+			want: []string{"/usr/local/bin/devagent", "--config-dir", "/tmp/dev-config"}},
+		{name: "explicit config file", argv0: "devagent", configDir: "/tmp/profiles", configFile: "/tmp/profiles/staging.yaml",
+			want: []string{"devagent", "--config-dir", "/tmp/profiles", "--config", "/tmp/profiles/staging.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restartArgv(tt.argv0, tt.configDir, tt.configFile); !slices.Equal(got, tt.want) {
+				t.Errorf("restartArgv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// pattern: Imperative Shell
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"sync/atomic"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"devagent/internal/cli"
+	"devagent/internal/config"
+	"devagent/internal/discovery"
+	"devagent/internal/logging"
+	"devagent/internal/tui"
+	"devagent/internal/web"
+)
+
+// reloadConfig re-reads the config (configFile, else configDir's config.yaml;
+// see cli.LoadConfig) and the templates directory.
+// The returned config is current with only the live-reloadable fields
+// (templates, scan paths, theme, log level, confirm policy, attach command
+// template, refresh interval) replaced. Changes to settings that require a restart (web
+// bind/port, runtime) are logged and ignored.
+// Returns an error, leaving current untouched, if the new config fails to
+// load or its runtime is invalid.
+func reloadConfig(configDir, configFile string, current config.Config, logger *logging.ScopedLogger) (config.Config, []config.Template, error) {
+	loaded, err := cli.LoadConfig(configDir, configFile)
+	if err != nil {
+		return current, nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := loaded.ValidateRuntime(); err != nil {
+		return current, nil, fmt.Errorf("validate runtime: %w", err)
+	}
+
+	templates, err := config.LoadTemplates()
+	if err != nil {
+		return current, nil, fmt.Errorf("load templates: %w", err)
+	}
+
+	if loaded.Web.Bind != current.Web.Bind || loaded.Web.Port != current.Web.Port {
+		logger.Warn("web bind/port changed; restart devagent to apply",
+			"bind", loaded.Web.Bind, "port", loaded.Web.Port)
+	}
+	if loaded.LogFormat != current.LogFormat || loaded.Logging != current.Logging {
+		logger.Warn("log file settings changed; restart devagent to apply",
+			"log_format", loaded.LogFormat, "path", loaded.Logging.Path)
+	}
+	if loaded.ScanMaxDepth != current.ScanMaxDepth {
+		logger.Warn("scan_max_depth changed; restart devagent to apply", "scan_max_depth", loaded.ScanMaxDepth)
+	}
+	if loaded.Runtime != current.Runtime {
+		logger.Warn("runtime changed; restart devagent to apply", "runtime", loaded.Runtime)
+	}
+
+	warnScanPathOverlaps(loaded, configDir, logger)
+	warnInvalidTemplates(logger)
+
+	next := current
+	next.Theme = loaded.Theme
+	next.UnknownTheme = loaded.UnknownTheme
+	next.LogLevel = loaded.LogLevel
+	next.ScanPaths = slices.Clone(loaded.ScanPaths)
+	next.Confirm = loaded.Confirm
+	next.AttachCommandTemplate = loaded.AttachCommandTemplate
+	next.RefreshInterval = loaded.RefreshInterval
+	return next, templates, nil
+}
+
+// watchConfigReload reloads the config on SIGHUP and pushes the result into
+// the TUI and GET /api/config. The resolved scan paths are published through
+// scanPaths so the web server's discovery and the project watcher (if not
+// nil) follow the reload.
+// Returns a function that stops watching.
+func watchConfigReload(configDir, configFile string, cfg config.Config, scanPaths *atomic.Pointer[[]string], projectWatcher *discovery.Watcher, p *tea.Program, webServer *web.Server, logger *logging.ScopedLogger) func() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		current := cfg
+		for range sigs {
+			logger.Info("SIGHUP received, reloading config")
+			next, templates, err := reloadConfig(configDir, configFile, current, logger)
+			if err != nil {
+				logger.Error("config reload failed, keeping previous config", "error", err)
+				continue
+			}
+			current = next
+
+			resolved := current.ResolveScanPaths()
+			scanPaths.Store(&resolved)
+			if projectWatcher != nil {
+				projectWatcher.Resync()
+			}
+			webServer.SetConfig(current, templates)
+			p.Send(tui.ConfigReloaded(current, templates))
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigs)
+		close(sigs)
+	}
+}